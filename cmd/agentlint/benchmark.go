@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/benchmark"
+	"github.com/CiaranMcAleer/AgentLint/internal/config"
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang"
+)
+
+// runBenchmark implements the benchmark subcommand and returns the process exit code. It
+// analyzes the bundled corpus, or the one given with -corpus, prints the precision and recall
+// of each rule the corpus names, and fails when a rule drops below -min-precision or -min-recall.
+func runBenchmark(args []string) int {
+	fset := flag.NewFlagSet("benchmark", flag.ContinueOnError)
+	corpusDir := fset.String("corpus", "", "Directory holding a corpus and its "+benchmark.ExpectationsFile+" (default: the bundled corpus)")
+	configPath := fset.String("config", "", "Configuration file applied over the defaults (default: none)")
+	minPrecision := fset.Float64("min-precision", 1, "Precision below which a rule fails the benchmark (0.0 to 1.0)")
+	minRecall := fset.Float64("min-recall", 1, "Recall below which a rule fails the benchmark (0.0 to 1.0)")
+	verbose := fset.Bool("verbose", false, "List the missed and unexpected findings of every rule, not only of failing ones")
+	if err := fset.Parse(args); err != nil {
+		return 2
+	}
+	if fset.NArg() > 0 {
+		slog.Error("unexpected arguments", "args", fset.Args())
+		return 2
+	}
+
+	cfg := config.DefaultConfig()
+	if *configPath != "" {
+		loaded := loadConfig(*configPath, "")
+		if loaded == nil || !reportConfigDiagnostics(loaded.Diagnostics) {
+			return 2
+		}
+		cfg = loaded.Config
+	}
+
+	corpus := benchmark.Bundled()
+	if *corpusDir != "" {
+		corpus = os.DirFS(*corpusDir)
+	}
+	scores, err := scoreCorpus(context.Background(), corpus, cfg)
+	if err != nil {
+		slog.Error("benchmark failed", "error", err)
+		return 2
+	}
+
+	if !printScores(os.Stdout, scores, *minPrecision, *minRecall, *verbose) {
+		return 1
+	}
+	return 0
+}
+
+// scoreCorpus analyzes the files of corpus with the per-file analyzers and scores the results
+// against its expectations
+func scoreCorpus(ctx context.Context, corpus fs.FS, cfg core.Config) ([]benchmark.RuleScore, error) {
+	expected, err := benchmark.ReadExpectations(corpus)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "agentlint-benchmark-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	if err := benchmark.Extract(corpus, dir); err != nil {
+		return nil, fmt.Errorf("extracting corpus: %w", err)
+	}
+
+	registry := setupAnalyzer(cfg, golang.NewASTCache(0))
+	files, err := languages.NewMultiScanner(registry).Scan(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	results, fileErrors := analyzeFiles(ctx, files, registry, cfg, core.NewEngine(0))
+	for _, fileErr := range fileErrors {
+		slog.Warn("analyzing corpus file failed", "file", fileErr.FilePath, "error", fileErr.Message)
+	}
+	return benchmark.Score(expected, results, dir), nil
+}
+
+// printScores prints a row per rule followed by the findings behind the failing scores, and
+// reports whether every rule met the thresholds
+func printScores(w io.Writer, scores []benchmark.RuleScore, minPrecision, minRecall float64, verbose bool) bool {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "RULE\tFOUND\tMISSED\tUNEXPECTED\tPRECISION\tRECALL\t")
+	var failing []benchmark.RuleScore
+	for _, score := range scores {
+		status := ""
+		if score.Precision() < minPrecision || score.Recall() < minRecall {
+			status = "FAIL"
+			failing = append(failing, score)
+		}
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\t%.2f\t%.2f\t%s\n", score.RuleID, score.Found, len(score.Missed),
+			len(score.Unexpected), score.Precision(), score.Recall(), status)
+	}
+	table.Flush()
+
+	listed := failing
+	if verbose {
+		listed = scores
+	}
+	for _, score := range listed {
+		for _, missed := range score.Missed {
+			fmt.Fprintf(w, "missed: %s\n", missed)
+		}
+		for _, result := range score.Unexpected {
+			fmt.Fprintf(w, "unexpected: %s:%d %s: %s\n", result.FilePath, result.Line, result.RuleID, result.Message)
+		}
+	}
+
+	fmt.Fprintln(w)
+	if len(failing) > 0 {
+		fmt.Fprintf(w, "%d of %d rules below precision %.2f or recall %.2f\n", len(failing), len(scores), minPrecision, minRecall)
+		return false
+	}
+	fmt.Fprintf(w, "All %d rules met precision %.2f and recall %.2f\n", len(scores), minPrecision, minRecall)
+	return true
+}
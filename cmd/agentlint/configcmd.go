@@ -0,0 +1,178 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/config"
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/dependencies"
+	"github.com/CiaranMcAleer/AgentLint/internal/headers"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang"
+)
+
+// projectRuleIDs are the rules reported by project-wide analyses rather than by a Rule
+var projectRuleIDs = []string{
+	"cross-file-unused-function",
+	"cross-file-unused-method",
+	"cross-file-unused-type",
+	golang.SimilarityRuleID,
+	golang.DuplicateErrorRuleID,
+	golang.UntestedFunctionRuleID,
+	golang.GenericPackageRuleID,
+	golang.GenericFileRuleID,
+	golang.StubFileRuleID,
+	golang.PackageNameRuleID,
+	headers.RuleID,
+	languages.SyntaxErrorRuleID,
+	languages.MinifiedFileRuleID,
+	core.SystemicRuleID,
+	dependencies.CycleRuleID,
+	dependencies.DepthRuleID,
+}
+
+// knownRuleIDs returns the ID of every rule agentlint can report
+func knownRuleIDs() []string {
+	ids := append([]string(nil), projectRuleIDs...)
+	for id := range collectRules() {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// validateRuleIDs returns an error naming the first entry of ids that is not a known rule
+func validateRuleIDs(ids []string) error {
+	known := knownRuleIDs()
+	for _, id := range ids {
+		if i := sort.SearchStrings(known, id); i == len(known) || known[i] != id {
+			return fmt.Errorf("unknown rule %q; run agentlint explain to list the rules", id)
+		}
+	}
+	return nil
+}
+
+// loadConfig loads the configuration hierarchy, with the project file at path when it is
+// given and the named profile instead of the one the files set when it is not "". A missing
+// explicit file or an unknown profile is logged and reported as nil.
+func loadConfig(path, profile string) *config.LoadedConfig {
+	loader := config.NewConfigLoader()
+	loader.SetProfile(profile)
+	loaded, err := loader.LoadHierarchy(path, knownRuleIDs())
+	if err != nil {
+		slog.Error("loading configuration failed", "error", err)
+		return nil
+	}
+	return loaded
+}
+
+// runConfig implements the config subcommand and returns the process exit code. validate
+// reports problems in the configuration files; show lists the files applied or, with
+// --effective, prints the merged configuration.
+func runConfig(args []string) int {
+	if len(args) == 0 || (args[0] != "validate" && args[0] != "show") {
+		slog.Error("config takes a subcommand: validate or show")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("config "+args[0], flag.ContinueOnError)
+	path := fs.String("config", "", "Path to configuration file")
+	profile := fs.String("profile", "", "Rule profile applied under the configuration files")
+	effective := false
+	if args[0] == "show" {
+		fs.BoolVar(&effective, "effective", false, "Print the merged configuration")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		slog.Error("unexpected arguments", "args", fs.Args())
+		return 2
+	}
+
+	loaded := loadConfig(*path, *profile)
+	if loaded == nil {
+		return 2
+	}
+
+	if args[0] == "validate" {
+		printSources(os.Stdout, loaded)
+		printDiagnostics(os.Stdout, loaded.Diagnostics)
+		if len(loaded.Diagnostics) == 0 {
+			fmt.Fprintln(os.Stdout, "No problems found")
+		}
+	} else {
+		printDiagnostics(os.Stderr, loaded.Diagnostics)
+		if effective {
+			if err := loaded.WriteEffective(os.Stdout); err != nil {
+				slog.Error("writing configuration failed", "error", err)
+				return 1
+			}
+		} else {
+			printSources(os.Stdout, loaded)
+		}
+	}
+	if loaded.HasErrors() {
+		return 1
+	}
+	return 0
+}
+
+// printSources lists the profile and the configuration files applied over the defaults
+func printSources(w io.Writer, loaded *config.LoadedConfig) {
+	if profile := loaded.Config.Profile; profile != "" && profile != config.DefaultProfile {
+		fmt.Fprintf(w, "Profile %s, applied over the defaults before any configuration file\n", profile)
+	}
+	if len(loaded.Sources) == 0 {
+		fmt.Fprintln(w, "No configuration files found, the defaults apply")
+	} else {
+		fmt.Fprintln(w, "Configuration files, applied in order over the defaults:")
+		for _, source := range loaded.Sources {
+			fmt.Fprintf(w, "  %s\n", source)
+		}
+	}
+
+	var variables []string
+	for _, setting := range loaded.Settings {
+		if strings.HasPrefix(setting.Path, "$") {
+			variables = append(variables, strings.TrimPrefix(setting.Path, "$"))
+		}
+	}
+	if len(variables) > 0 {
+		sort.Strings(variables)
+		fmt.Fprintln(w, "Environment variables, applied over the files:")
+		for _, name := range variables {
+			fmt.Fprintf(w, "  %s\n", name)
+		}
+	}
+}
+
+// printDiagnostics prints each diagnostic followed by the count of each severity
+func printDiagnostics(w io.Writer, diags []config.Diagnostic) {
+	counts := make(map[core.Severity]int)
+	for _, diag := range diags {
+		fmt.Fprintln(w, diag)
+		counts[diag.Severity]++
+	}
+	if len(diags) == 0 {
+		return
+	}
+
+	var parts []string
+	for _, severity := range []core.Severity{core.SeverityError, core.SeverityWarning, core.SeverityInfo} {
+		switch {
+		case counts[severity] == 0:
+		case counts[severity] == 1 || severity == core.SeverityInfo:
+			parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
+		default:
+			parts = append(parts, fmt.Sprintf("%d %ss", counts[severity], severity))
+		}
+	}
+	fmt.Fprintln(w, strings.Join(parts, ", "))
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/coverage"
+)
+
+// annotateCoverage marks the results in code the tests never ran, according to the coverage
+// report at coverageFile
+func annotateCoverage(results []core.Result, coverageFile string) {
+	if coverageFile == "" || len(results) == 0 {
+		return
+	}
+
+	report, err := coverage.Load(coverageFile)
+	if err != nil {
+		slog.Warn("skipping test coverage", "error", err)
+		return
+	}
+	for i := range results {
+		results[i].Uncovered = report.Uncovered(results[i].FilePath, results[i].Line)
+	}
+}
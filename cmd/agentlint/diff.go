@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang"
+	"github.com/CiaranMcAleer/AgentLint/internal/output"
+	"github.com/CiaranMcAleer/AgentLint/internal/profiling"
+)
+
+// runDiff implements the diff subcommand and returns the process exit code. It analyzes two
+// snapshots of a tree, such as a checkout before and after an agent's change, with the same
+// configuration and reports the findings introduced, fixed and left unchanged by the change.
+// It fails when an introduced finding meets -fail-on.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	configPath := fs.String("config", "", "Path to configuration file (default: agentlint.yaml in the current directory)")
+	profile := fs.String("profile", "", "Rule profile applied under the configuration files")
+	format := fs.String("format", "", "Output format: console, json (default: output.format when it is one of them, else console)")
+	failOn := fs.String("fail-on", "", "Minimum severity of an introduced finding that causes a non-zero exit (default: output.failOn)")
+	verbose := fs.Bool("verbose", false, "List the unchanged findings and the suggestions too")
+	noColor := fs.Bool("no-color", false, "Disable colored console output")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		slog.Error("diff takes two directories: the snapshot before the change and the one after it", "args", fs.Args())
+		return 2
+	}
+	before, after := fs.Arg(0), fs.Arg(1)
+	for _, dir := range []string{before, after} {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			slog.Error("not a directory", "path", dir)
+			return 2
+		}
+	}
+
+	loaded := loadConfig(*configPath, *profile)
+	if loaded == nil || !reportConfigDiagnostics(loaded.Diagnostics) {
+		return 2
+	}
+	cfg := loaded.Config
+	if *failOn != "" {
+		cfg.Output.FailOn = *failOn
+	}
+	if !isValidFailOn(cfg.Output.FailOn) {
+		slog.Error("invalid -fail-on value (expected error, warning, info or none)", "value", cfg.Output.FailOn)
+		return 2
+	}
+	if *format == "" && cfg.Output.Format == "json" {
+		*format = "json"
+	}
+	if *format != "" && *format != "console" && *format != "json" {
+		slog.Error("invalid -format value (expected console or json)", "value", *format)
+		return 2
+	}
+
+	ctx := context.Background()
+	beforeResults, err := analyzeSnapshot(ctx, cfg, before)
+	if err != nil {
+		slog.Error("analysis failed", "path", before, "error", err)
+		return 1
+	}
+	afterResults, err := analyzeSnapshot(ctx, cfg, after)
+	if err != nil {
+		slog.Error("analysis failed", "path", after, "error", err)
+		return 1
+	}
+	comparison := core.Compare(beforeResults, afterResults)
+	failed := markBlocking(comparison.Introduced, cfg.Output)
+
+	if *format == "json" {
+		err = output.NewJSONFormatter(*verbose).FormatComparison(before, after, comparison)
+	} else {
+		console := output.NewConsoleFormatter(*verbose)
+		console.SetColor(!*noColor && !cfg.Output.NoColor && output.ColorEnabled(os.Stdout))
+		err = console.FormatComparison(before, after, comparison)
+	}
+	if err != nil {
+		slog.Error("writing the comparison failed", "error", err)
+		return 1
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// analyzeSnapshot analyzes the tree under dir as a project of its own and returns its
+// filtered results, fingerprinted relative to dir so that the same finding in two snapshots
+// matches, with paths under dir as given
+func analyzeSnapshot(ctx context.Context, cfg core.Config, dir string) ([]core.Result, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	astCache := golang.NewASTCache(0)
+	registry := setupAnalyzer(cfg, astCache)
+	scanner := languages.NewMultiScanner(registry)
+	if err := scanner.SetExtensionMap(cfg.Language.Extensions); err != nil {
+		return nil, err
+	}
+	if err := scanner.SetPathFilter(cfg.Files.Include, cfg.Files.Exclude); err != nil {
+		return nil, err
+	}
+	if cfg.Files.External {
+		scanner.IncludeVendored()
+	}
+
+	results, fileErrors, _, err := analyzeProject(ctx, &parsedFlags{}, scanner, registry, rootConfig(cfg, dir), astCache, profiling.NewTimingStats(), dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(fileErrors) > 0 {
+		slog.Warn("some files could not be analyzed", "path", dir, "files", len(fileErrors))
+	}
+
+	fingerprintRelativeTo(results, absDir)
+	results = filterResults(results, cfg.Output)
+	applySuggestions(results, cfg.Output.Suggestions)
+	for i := range results {
+		if rel, err := filepath.Rel(absDir, results[i].FilePath); err == nil && filepath.IsAbs(results[i].FilePath) {
+			results[i].FilePath = filepath.Join(dir, rel)
+		}
+		if related := results[i].Related; related != nil {
+			if rel, err := filepath.Rel(absDir, related.FilePath); err == nil && filepath.IsAbs(related.FilePath) {
+				related.FilePath = filepath.Join(dir, rel)
+			}
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	_ "github.com/CiaranMcAleer/AgentLint/internal/dependencies" // registers its rule documentation
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/python"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/reactnative"
+)
+
+// explainWidth is the column at which explain wraps prose
+const explainWidth = 80
+
+// languageTitles are the names analyzers are listed under in rule documentation; "" is a
+// project-wide rule that applies to every language
+var languageTitles = map[string]string{
+	"":            "All",
+	"go":          "Go",
+	"python":      "Python",
+	"reactnative": "JavaScript/TypeScript",
+}
+
+// languageRule is a rule as implemented by the analyzer of one language. Rules of different
+// languages share IDs, such as large-function, and are documented together.
+type languageRule struct {
+	language string
+	rule     core.Rule
+}
+
+// runExplain implements the explain subcommand and returns the process exit code. Without
+// a rule ID it lists every rule.
+func runExplain(args []string) int {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 {
+		slog.Error("explain takes a single rule ID", "args", fs.Args())
+		return 2
+	}
+
+	rules := collectRules()
+	if fs.NArg() == 0 {
+		printRuleIndex(os.Stdout, rules)
+		return 0
+	}
+
+	id := fs.Arg(0)
+	if len(rules[id]) == 0 {
+		slog.Error("unknown rule; run agentlint explain to list the rules", "rule", id)
+		return 2
+	}
+	printRuleDoc(os.Stdout, id, rules[id])
+	return 0
+}
+
+// docRule is a rule of a project-wide analysis, documented by core.RegisterRuleDoc
+type docRule struct {
+	doc core.RuleDoc
+}
+
+func (r docRule) ID() string                                                   { return r.doc.ID }
+func (r docRule) Name() string                                                 { return r.doc.Name }
+func (r docRule) Description() string                                          { return r.doc.Description }
+func (r docRule) Rationale() string                                            { return r.doc.Rationale }
+func (r docRule) Examples() []core.RuleExample                                 { return r.doc.Examples }
+func (r docRule) Options() []core.RuleOption                                   { return r.doc.Options }
+func (r docRule) Category() core.RuleCategory                                  { return r.doc.Category }
+func (r docRule) Severity() core.Severity                                      { return r.doc.Severity }
+func (r docRule) Check(context.Context, interface{}, core.Config) *core.Result { return nil }
+
+// collectRules returns the rules of every analyzer by ID, each in analyzer registration order,
+// followed by the rules of the project-wide analyses
+func collectRules() map[string][]languageRule {
+	analyzers := []interface {
+		Name() string
+		Rules() []core.Rule
+	}{
+		golang.NewAnalyzer(core.Config{}),
+		python.NewAnalyzer(core.Config{}),
+		reactnative.NewAnalyzer(core.Config{}),
+	}
+
+	rules := make(map[string][]languageRule)
+	for _, analyzer := range analyzers {
+		for _, rule := range analyzer.Rules() {
+			rules[rule.ID()] = append(rules[rule.ID()], languageRule{language: analyzer.Name(), rule: rule})
+		}
+	}
+	for _, doc := range core.RuleDocs() {
+		languageNames := doc.Languages
+		if len(languageNames) == 0 {
+			languageNames = []string{""}
+		}
+		for _, language := range languageNames {
+			rules[doc.ID] = append(rules[doc.ID], languageRule{language: language, rule: docRule{doc}})
+		}
+	}
+	return rules
+}
+
+// printRuleIndex lists every rule ID with its description
+func printRuleIndex(w io.Writer, rules map[string][]languageRule) {
+	ids := make([]string, 0, len(rules))
+	width := 0
+	for id := range rules {
+		ids = append(ids, id)
+		if len(id) > width {
+			width = len(id)
+		}
+	}
+	sort.Strings(ids)
+
+	fmt.Fprintln(w, "Rules (run agentlint explain <rule-id> for details):")
+	fmt.Fprintln(w)
+	for _, id := range ids {
+		fmt.Fprintf(w, "  %-*s  %s\n", width, id, rules[id][0].rule.Description())
+	}
+}
+
+// printRuleDoc prints the documentation of one rule across the languages implementing it.
+// Text that differs between languages is printed once per language.
+func printRuleDoc(w io.Writer, id string, entries []languageRule) {
+	first := entries[0].rule
+	fmt.Fprintf(w, "%s: %s\n", id, first.Name())
+	categories := distinct(entries, func(r core.Rule) string { return string(r.Category()) })
+	if len(categories) == 1 && categories[0] == "" {
+		categories[0] = "that of the findings it reports on"
+	}
+	fmt.Fprintf(w, "Category:  %s\n", strings.Join(categories, ", "))
+	fmt.Fprintf(w, "Severity:  %s\n", strings.Join(distinct(entries, func(r core.Rule) string { return string(r.Severity()) }), ", "))
+	fmt.Fprintf(w, "Languages: %s\n", strings.Join(ruleLanguages(entries), ", "))
+
+	fmt.Fprintln(w)
+	printWrapped(w, "", first.Description())
+	printVarying(w, "Why", entries, core.Rule.Rationale)
+
+	shown := make(map[core.RuleExample]bool)
+	for _, entry := range entries {
+		for _, example := range entry.rule.Examples() {
+			if shown[example] {
+				continue // a project-wide rule shares its examples between languages
+			}
+			shown[example] = true
+			if _, projectWide := entry.rule.(docRule); projectWide {
+				fmt.Fprintln(w, "\nExample:")
+			} else {
+				fmt.Fprintf(w, "\nExample (%s):\n", languageTitles[entry.language])
+			}
+			fmt.Fprintln(w, "  Reported:")
+			printCode(w, example.Bad)
+			fmt.Fprintln(w, "  Accepted:")
+			printCode(w, example.Good)
+		}
+	}
+
+	fmt.Fprintln(w, "\nOptions:")
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		for _, option := range entry.rule.Options() {
+			if seen[option.Key] {
+				continue
+			}
+			seen[option.Key] = true
+			fmt.Fprintf(w, "  %s (%s, default %s)\n", option.Key, option.Flag, option.Default)
+			printWrapped(w, "      ", option.Description)
+		}
+	}
+	if len(seen) == 0 {
+		fmt.Fprintln(w, "  None; the rule is always applied.")
+	}
+	fmt.Fprintln(w)
+	printWrapped(w, "", "Like every rule, it can be skipped in test files with testFiles.disabledRules "+
+		"(-test-disable-rules), and made blocking or advisory with output.blocking "+
+		"(-blocking-rules, -advisory-rules).")
+}
+
+// printVarying prints a text of the rules under title, once if every language has the same
+// text and otherwise once per language
+func printVarying(w io.Writer, title string, entries []languageRule, text func(core.Rule) string) {
+	texts := distinct(entries, text)
+	if len(texts) == 1 {
+		fmt.Fprintf(w, "\n%s:\n", title)
+		printWrapped(w, "  ", texts[0])
+		return
+	}
+	for _, entry := range entries {
+		fmt.Fprintf(w, "\n%s (%s):\n", title, languageTitles[entry.language])
+		printWrapped(w, "  ", text(entry.rule))
+	}
+}
+
+// printCode prints an example indented under its label
+func printCode(w io.Writer, code string) {
+	for _, line := range strings.Split(code, "\n") {
+		fmt.Fprintln(w, strings.TrimRight("    "+line, " "))
+	}
+}
+
+// printWrapped prints text word-wrapped at explainWidth, each line starting with indent
+func printWrapped(w io.Writer, indent, text string) {
+	line := indent
+	for _, word := range strings.Fields(text) {
+		if line != indent && len(line)+1+len(word) > explainWidth {
+			fmt.Fprintln(w, line)
+			line = indent
+		}
+		if line != indent {
+			line += " "
+		}
+		line += word
+	}
+	fmt.Fprintln(w, line)
+}
+
+// ruleLanguages returns the display names of the languages implementing a rule
+func ruleLanguages(entries []languageRule) []string {
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = languageTitles[entry.language]
+	}
+	return names
+}
+
+// distinct returns the different values of a property of the rules, in order
+func distinct(entries []languageRule, property func(core.Rule) string) []string {
+	var values []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if value := property(entry.rule); !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	return values
+}
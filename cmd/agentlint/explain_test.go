@@ -0,0 +1,64 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/dependencies"
+	"github.com/CiaranMcAleer/AgentLint/internal/headers"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang"
+)
+
+func TestCollectRules_ProjectRules(t *testing.T) {
+	rules := collectRules()
+	ids := []string{
+		dependencies.CycleRuleID,
+		dependencies.DepthRuleID,
+		golang.SimilarityRuleID,
+		"cross-file-unused-function",
+		"cross-file-unused-method",
+		"cross-file-unused-type",
+		languages.SyntaxErrorRuleID,
+		core.SystemicRuleID,
+		golang.DuplicateErrorRuleID,
+		golang.GenericPackageRuleID,
+		golang.GenericFileRuleID,
+		golang.StubFileRuleID,
+		golang.PackageNameRuleID,
+		headers.RuleID,
+		golang.UntestedFunctionRuleID,
+		languages.MinifiedFileRuleID,
+	}
+	for _, id := range ids {
+		entries := rules[id]
+		if len(entries) == 0 {
+			t.Errorf("Expected %s to be documented", id)
+			continue
+		}
+		if rule := entries[0].rule; rule.Description() == "" || rule.Rationale() == "" || len(rule.Examples()) == 0 {
+			t.Errorf("Expected %s to have a description, rationale and example", id)
+		}
+	}
+}
+
+func TestCollectRules_EmittedRules(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\nfunc main() {\n}\n\nfunc (s *server) stop() {}\n\ntype server struct{}\n\nfunc helper() int {\n\tx := 1\n\ty := 2\n\treturn x + y\n}\n")
+	writeFile(t, filepath.Join(dir, "models.py"), "from views import render\n\n\nclass User:\n    pass\n")
+	writeFile(t, filepath.Join(dir, "views.py"), "from models import User\n\n\ndef render(user):\n    return str(user)\n")
+	writeFile(t, filepath.Join(dir, "broken.py"), "def load(path)\n    return open(path).read()\n")
+	report, _ := runJSON(t, dir, "-fail-on", "none", "-tolerant", "-func-max-lines", "3", "-include-exported", ".")
+	if len(report.Results) == 0 {
+		t.Fatal("Expected findings in the project")
+	}
+
+	rules := collectRules()
+	for _, result := range report.Results {
+		if len(rules[result.RuleID]) == 0 {
+			t.Errorf("Expected %s, reported at %s:%d, to be documented", result.RuleID, result.FilePath, result.Line)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/tracker"
+)
+
+// runExport implements the export subcommand and returns the process exit code. export issues
+// files the findings of a JSON report as tickets in an issue tracker, one per rule or per
+// file, updating the tickets of earlier exports instead of filing duplicates. Jira is the
+// only tracker; its address can be given by JIRA_URL, and its credentials are read from
+// JIRA_EMAIL and JIRA_API_TOKEN, or from JIRA_TOKEN for a personal access token, and are only
+// sent over https.
+func runExport(args []string) int {
+	if len(args) == 0 || args[0] != "issues" {
+		slog.Error("export takes a subcommand: issues")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("export issues", flag.ContinueOnError)
+	trackerName := fs.String("tracker", "jira", "Issue tracker to file tickets in: jira")
+	project := fs.String("project", "", "Key of the project to file tickets in")
+	baseURL := fs.String("url", os.Getenv("JIRA_URL"), "Address of the tracker (default: $JIRA_URL)")
+	groupBy := fs.String("group-by", tracker.GroupByRule, "File a ticket per rule or per file: rule, file")
+	issueType := fs.String("issue-type", "Task", "Type of the tickets created")
+	dryRun := fs.Bool("dry-run", false, "Report the tickets that would be created, updated or marked as fixed without changing any")
+	timeout := fs.Duration("timeout", 30*time.Second, "Time allowed for each request to the tracker")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		slog.Error("export issues takes the JSON report to export, written by -format json", "args", fs.Args())
+		return 2
+	}
+	if *trackerName != "jira" {
+		slog.Error("invalid -tracker value (expected jira)", "value", *trackerName)
+		return 2
+	}
+	if *groupBy != tracker.GroupByRule && *groupBy != tracker.GroupByFile {
+		slog.Error("invalid -group-by value (expected rule or file)", "value", *groupBy)
+		return 2
+	}
+	if *project == "" || *baseURL == "" {
+		slog.Error("export issues needs the -project key and the -url of the tracker")
+		return 2
+	}
+	authorize := jiraAuthorization()
+	if u, err := url.Parse(*baseURL); authorize != nil && (err != nil || u.Scheme != "https") {
+		slog.Error("credentials are only sent to an https -url", "url", *baseURL)
+		return 2
+	}
+
+	results, err := loadBaseline(fs.Arg(0))
+	if err != nil {
+		slog.Error("reading the report failed", "error", err)
+		return 2
+	}
+	groups := tracker.GroupResults(results, *groupBy, fingerprintBase("."))
+	if skipped := len(results) - countResults(groups); skipped > 0 {
+		slog.Warn("findings without a fingerprint are not exported", "count", skipped)
+	}
+
+	jira := &tracker.Jira{BaseURL: *baseURL, Project: *project, IssueType: *issueType, Authorize: authorize, Client: &http.Client{Timeout: *timeout}}
+	outcome, err := tracker.Export(context.Background(), jira, *groupBy, groups, *dryRun)
+	printExportOutcome(outcome, *dryRun)
+	if err != nil {
+		slog.Error("exporting the findings failed", "error", err)
+		return 1
+	}
+	if len(groups) == 0 {
+		fmt.Println("No findings to export")
+	}
+	return 0
+}
+
+// jiraAuthorization returns the credentials in the environment as a request authorizer, or
+// nil when there are none
+func jiraAuthorization() func(req *http.Request) {
+	if token := os.Getenv("JIRA_TOKEN"); token != "" {
+		return func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token) }
+	}
+	email, token := os.Getenv("JIRA_EMAIL"), os.Getenv("JIRA_API_TOKEN")
+	if email != "" && token != "" {
+		return func(req *http.Request) { req.SetBasicAuth(email, token) }
+	}
+	return nil
+}
+
+// countResults returns the number of findings in groups
+func countResults(groups []tracker.Group) int {
+	n := 0
+	for _, group := range groups {
+		n += len(group.Results)
+	}
+	return n
+}
+
+// printExportOutcome lists the tickets an export created, updated, marked as fixed and left
+// alone, which are those handled before the error of a failed export
+func printExportOutcome(outcome tracker.Outcome, dryRun bool) {
+	created, updated, fixed := "Created", "Updated", "Marked"
+	if dryRun {
+		created, updated, fixed = "Would create", "Would update", "Would mark"
+	}
+	for _, line := range []struct {
+		verb, suffix string
+		tickets      []string
+	}{{created, "", outcome.Created}, {updated, "", outcome.Updated}, {fixed, " as fixed", outcome.Fixed}, {"Unchanged", "", outcome.Unchanged}} {
+		if len(line.tickets) > 0 {
+			fmt.Printf("%s %d tickets%s: %s\n", line.verb, len(line.tickets), line.suffix, strings.Join(line.tickets, ", "))
+		}
+	}
+}
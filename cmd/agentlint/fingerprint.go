@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+)
+
+// fingerprintResults sets the fingerprint of each result from its rule, its path relative to
+// fingerprintBase(dir) and the text of the line it points at
+func fingerprintResults(results []core.Result, dir string) {
+	fingerprintRelativeTo(results, fingerprintBase(dir))
+}
+
+// fingerprintBase returns the directory fingerprints of the findings under dir are relative
+// to: the top of the git working tree dir is in, so that a finding keeps its fingerprint
+// whichever directory agentlint runs in, or dir itself outside a repository. The top is found
+// as a path from dir, which is how the paths of the findings are written.
+func fingerprintBase(dir string) string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	cdup, err := gitOutput(absDir, "rev-parse", "--show-cdup")
+	if err != nil {
+		return absDir
+	}
+	return filepath.Join(absDir, filepath.FromSlash(strings.TrimSpace(cdup)))
+}
+
+// fingerprintRelativeTo fingerprints results like fingerprintResults, with paths relative to
+// base. Each file is read once; a result whose line cannot be read is fingerprinted with an
+// empty snippet.
+func fingerprintRelativeTo(results []core.Result, base string) {
+	byFile := make(map[string][]int)
+	for i := range results {
+		byFile[results[i].FilePath] = append(byFile[results[i].FilePath], i)
+	}
+
+	for file, indices := range byFile {
+		var lines []string
+		if data, err := os.ReadFile(file); err == nil {
+			lines = strings.Split(string(languages.DecodeSource(data)), "\n")
+		}
+		relPath := file
+		if rel, err := filepath.Rel(base, file); err == nil && base != "" && !strings.HasPrefix(rel, "..") {
+			relPath = rel
+		}
+
+		// Identical snippets are numbered in line order, so inserting lines above them keeps
+		// each finding's number
+		sort.SliceStable(indices, func(a, b int) bool { return results[indices[a]].Line < results[indices[b]].Line })
+		seen := make(map[string]int)
+		for _, i := range indices {
+			snippet := ""
+			if line := results[i].Line; line > 0 && line <= len(lines) {
+				snippet = lines[line-1]
+			}
+			key := results[i].RuleID + "\x00" + strings.Join(strings.Fields(snippet), " ")
+			results[i].Fingerprint = core.Fingerprint(results[i].RuleID, relPath, snippet, seen[key])
+			seen[key]++
+		}
+	}
+}
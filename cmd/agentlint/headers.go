@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/headers"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+)
+
+// analyzeHeaders reports the analyzed files whose header drifted from the project's header or
+// the configured template. Headers are compared across every file under root. With
+// -fix-headers, drifted headers are replaced with the template and no longer reported.
+func analyzeHeaders(ctx context.Context, flags *parsedFlags, scanner *languages.MultiScanner, root string, filesByLanguage map[string][]string, cfg core.Config) []core.Result {
+	if !cfg.Rules.Headers.Enabled {
+		return nil
+	}
+
+	var template []string
+	if cfg.Rules.Headers.Template != "" {
+		var err error
+		if template, err = headers.LoadTemplate(cfg.Rules.Headers.Template); err != nil {
+			slog.Warn("skipping header analysis", "error", err)
+			return nil
+		}
+	}
+	projectFiles, err := allProjectFiles(ctx, flags, scanner, root, filesByLanguage)
+	if err != nil {
+		slog.Warn("skipping header analysis", "error", err)
+		return nil
+	}
+	var files []string
+	for _, languageFiles := range projectFiles {
+		files = append(files, languageFiles...)
+	}
+
+	results := headers.Analyze(files, analyzedFiles(filesByLanguage), cfg.Rules.Headers, template)
+	if !flags.fixHeaders {
+		return results
+	}
+	var unfixed []core.Result
+	for _, result := range results {
+		if err := headers.Fix(result.FilePath, template); err != nil {
+			slog.Warn("failed to fix header", "file", result.FilePath, "error", err)
+			unfixed = append(unfixed, result)
+			continue
+		}
+		slog.Info("replaced header with template", "file", result.FilePath)
+	}
+	return unfixed
+}
@@ -0,0 +1,313 @@
+package main
+
+import "fmt"
+
+// showHelp prints the usage of agentlint and of its subcommands, and the flags of an analysis
+// run by group
+func showHelp() {
+	fmt.Println("AgentLint - A linter for detecting LLM code bad smells")
+	fmt.Println()
+	fmt.Println("Usage:")
+	fmt.Println("  agentlint [flags] [paths... | files...]")
+	fmt.Println("  agentlint install-hook [-fail-on severity] [-force]")
+	fmt.Println("  agentlint explain [rule-id]")
+	fmt.Println("  agentlint config validate|show [--effective] [-config file] [-profile name]")
+	fmt.Println("  agentlint benchmark [-corpus dir] [-config file] [-min-precision n] [-min-recall n]")
+	fmt.Println("  agentlint worker [-listen addr] [-root dir] [-workers n]")
+	fmt.Println("  agentlint schema")
+	fmt.Println("  agentlint diff [-format console|json] [-fail-on severity] [-verbose] [-config file] [-profile name] before-dir after-dir")
+	fmt.Println("  agentlint export issues -project key [-tracker jira] [-url url] [-group-by rule|file] [-issue-type type] [-dry-run] report.json")
+	fmt.Println()
+	printOutputOptions()
+	printNotifyOptions()
+	printLLMOptions()
+	printFunctionSizeOptions()
+	printFileSizeOptions()
+	printLanguageOverrideOptions()
+	printFileRoutingOptions()
+	printTestFileOptions()
+	printAIFileOptions()
+	printTypeSizeOptions()
+	printReturnOptions()
+	printCommentOptions()
+	printDocstringOptions()
+	printTypeHintOptions()
+	printDependencyOptions()
+	printBranchOptions()
+	printConcurrencyOptions()
+	printDuplicateErrorOptions()
+	printSimilarityOptions()
+	printLayoutOptions()
+	printEndpointOptions()
+	printTestQualityOptions()
+	printComponentOptions()
+	printHeaderOptions()
+	printTypeSafetyOptions()
+	printSystemicOptions()
+	printOrphanedOptions()
+	printGoOptions()
+	printGitOptions()
+	printPerformanceOptions()
+	printProfilingOptions()
+	printRemoteOptions()
+	printGeneralOptions()
+	printExamples()
+}
+
+func printFunctionSizeOptions() {
+	fmt.Println("Function Size Rules:")
+	fmt.Println("  -enable-func-size    Enable large function detection (default true)")
+	fmt.Println("  -func-max-lines      Maximum number of lines for a function (default 50)")
+	fmt.Println("  -func-metric         How function size is measured: lines, statements (default lines)")
+	fmt.Println()
+}
+
+func printFileSizeOptions() {
+	fmt.Println("File Size Rules:")
+	fmt.Println("  -enable-file-size    Enable large file detection (default true)")
+	fmt.Println("  -file-max-lines      Maximum number of lines for a file (default 500)")
+	fmt.Println("  -file-count-mode     Which lines count towards file size: total, code (default total)")
+	fmt.Println()
+}
+
+func printTypeSizeOptions() {
+	fmt.Println("Type Size Rules:")
+	fmt.Println("  -enable-type-size    Enable god struct, god class and too many methods detection (default true)")
+	fmt.Println("  -struct-max-fields   Maximum number of fields for a struct or attributes for a Python class (default 15)")
+	fmt.Println("  -type-max-methods    Maximum number of methods for a type (default 20)")
+	fmt.Println("  -class-max-lines     Maximum number of lines for a Python class (default 500)")
+	fmt.Println()
+}
+
+func printReturnOptions() {
+	fmt.Println("Return Value Rules:")
+	fmt.Println("  -enable-returns          Enable return value detection (default true)")
+	fmt.Println("  -max-return-values       Maximum number of values a function may return (default 3)")
+	fmt.Println("  -naked-return-max-lines  Maximum function length in which naked returns are allowed (default 5)")
+	fmt.Println()
+}
+
+func printCommentOptions() {
+	fmt.Println("Comment Rules:")
+	fmt.Println("  -enable-comments      Enable overcommenting detection (default true)")
+	fmt.Println("  -comment-max-ratio    Maximum comment-to-code ratio (default 0.3)")
+	fmt.Println("  -check-redundant      Check for redundant comments (default true)")
+	fmt.Println("  -redundant-threshold  Comment/code word overlap at which a comment is redundant (default 0.6)")
+	fmt.Println("  -check-docs           Check for missing documentation (default true)")
+	fmt.Println("  -enable-ai-comments   Enable LLM comment fingerprint detection (default true)")
+	fmt.Println("  -ai-comment-phrases   Comma-separated extra phrases to flag in comments")
+	fmt.Println()
+}
+
+func printDocstringOptions() {
+	fmt.Println("Docstring Rules (Python):")
+	fmt.Println("  -enable-docstrings            Enable docstring quality detection (default true)")
+	fmt.Println("  -check-docstring-placeholders Check for template and TODO placeholder docstrings (default true)")
+	fmt.Println("  -check-docstring-params       Check docstring parameters against the signature (default true)")
+	fmt.Println()
+}
+
+func printTypeHintOptions() {
+	fmt.Println("Type Hint Rules (Python):")
+	fmt.Println("  -enable-type-hints       Enable type hint coverage detection (default false)")
+	fmt.Println("  -type-hint-min-coverage  Share of signatures that must be fully annotated (default 0.8)")
+	fmt.Println()
+}
+
+func printDependencyOptions() {
+	fmt.Println("Dependency Rules:")
+	fmt.Println("  -enable-dependencies  Enable import graph analysis (default true)")
+	fmt.Println("  -check-import-cycles  Check for circular imports (default true)")
+	fmt.Println("  -max-import-depth     Maximum length of an import chain, 0 to disable (default 10)")
+	fmt.Println()
+}
+
+func printBranchOptions() {
+	fmt.Println("Branch Rules:")
+	fmt.Println("  -enable-branches  Enable long switch and if-else chain detection (default true)")
+	fmt.Println("  -max-branches     Maximum branches in one switch, match or if-else chain (default 10)")
+	fmt.Println()
+}
+
+func printConcurrencyOptions() {
+	fmt.Println("Concurrency Rules:")
+	fmt.Println("  -enable-concurrency  Enable goroutine leak, mutex copy, defer-in-loop and sleep-based synchronization detection (default true)")
+	fmt.Println()
+}
+
+func printDuplicateErrorOptions() {
+	fmt.Println("Duplicate Error Rules (Go):")
+	fmt.Println("  -enable-duplicate-errors    Enable repeated error message detection (default true)")
+	fmt.Println("  -duplicate-error-min        Occurrences in a package before a message is reported (default 3)")
+	fmt.Println("  -duplicate-error-threshold  Word similarity at which messages are near-identical (default 0.85)")
+	fmt.Println()
+}
+
+func printSimilarityOptions() {
+	fmt.Println("Similarity Rules (Go):")
+	fmt.Println("  -enable-similarity          Enable similar function detection (default false)")
+	fmt.Println("  -similarity-threshold       Jaccard similarity of the token shingles of similar functions (default 0.8)")
+	fmt.Println("  -similarity-min-statements  Statements a function needs to be compared (default 5)")
+	fmt.Println("  -similarity-min-tokens      Normalized tokens a function needs to be compared (default 10)")
+	fmt.Println()
+}
+
+func printLayoutOptions() {
+	fmt.Println("Layout Rules (Go):")
+	fmt.Println("  -enable-layout        Enable package and file layout detection (default true)")
+	fmt.Println("  -generic-packages     Comma-separated catch-all package names (default utils,util,helpers,helper,common,misc)")
+	fmt.Println("  -generic-files        Comma-separated uninformative file names (default misc,stuff,things,other,temp,tmp,various,extra)")
+	fmt.Println("  -check-stub-files     Check for one file of code next to files that declare nothing (default true)")
+	fmt.Println("  -check-package-names  Check for packages named differently from their directory (default true)")
+	fmt.Println()
+}
+
+func printEndpointOptions() {
+	fmt.Println("Endpoint Rules:")
+	fmt.Println("  -enable-endpoints  Enable hardcoded URL, IP address and port detection (default true)")
+	fmt.Println("  -allow-endpoints   Comma-separated hosts or endpoints that may be hardcoded")
+	fmt.Println()
+}
+
+func printTestQualityOptions() {
+	fmt.Println("Test Quality Rules:")
+	fmt.Println("  -enable-test-quality       Enable assertion-free, duplicated and trivial test detection (default true)")
+	fmt.Println("  -test-duplicate-threshold  Share of code near-duplicate tests have in common (default 0.9)")
+	fmt.Println("  -check-untested            Check for exported Go functions no test refers to (default false)")
+	fmt.Println()
+}
+
+func printComponentOptions() {
+	fmt.Println("Component Rules (React Native):")
+	fmt.Println("  -enable-components        Enable React component complexity detection (default true)")
+	fmt.Println("  -component-max-hooks      Maximum hook calls in one function component (default 10)")
+	fmt.Println("  -component-max-props      Maximum props destructured by one component (default 10)")
+	fmt.Println("  -component-max-jsx-depth  Maximum nesting depth of the JSX a component renders (default 8)")
+	fmt.Println()
+}
+
+func printHeaderOptions() {
+	fmt.Println("Header Rules:")
+	fmt.Println("  -enable-headers    Enable drifted file header detection (default true)")
+	fmt.Println("  -header-min-files  Files that must share a header before near copies are reported (default 3)")
+	fmt.Println("  -header-threshold  Word similarity at which a header is a near copy (default 0.7)")
+	fmt.Println("  -header-template   File holding the expected header as plain text (default: most common headers)")
+	fmt.Println("  -fix-headers       Replace drifted headers with the template")
+	fmt.Println()
+}
+
+func printTypeSafetyOptions() {
+	fmt.Println("Type Safety Rules (TypeScript):")
+	fmt.Println("  -enable-type-safety   Enable 'any' and @ts-ignore detection (default true)")
+	fmt.Println("  -max-any              Maximum 'any' annotations and casts per file (default 5)")
+	fmt.Println("  -max-ts-suppressions  Maximum @ts-ignore/@ts-expect-error/@ts-nocheck comments per file (default 2)")
+	fmt.Println()
+}
+
+func printSystemicOptions() {
+	fmt.Println("Systemic Smells:")
+	fmt.Println("  -enable-systemic        Report pervasive smells with an additional error-level finding (default true)")
+	fmt.Println("  -systemic-max-findings  Findings of one rule allowed per file, 0 to disable (default 20)")
+	fmt.Println("  -systemic-max-ratio     Share of a package's functions one rule may report, 0 to disable (default 0.3)")
+	fmt.Println()
+}
+
+func printOrphanedOptions() {
+	fmt.Println("Orphaned Code Rules:")
+	fmt.Println("  -enable-orphaned    Enable orphaned code detection (default true)")
+	fmt.Println("  -check-unused-funcs  Check for unused functions (default true)")
+	fmt.Println("  -check-unused-vars   Check for unused variables (default true)")
+	fmt.Println("  -check-unreachable   Check for unreachable code (default true)")
+	fmt.Println("  -check-dead-imports  Check for dead imports (default true)")
+	fmt.Println("  -include-exported    Also report unused exported Go functions and types (default false)")
+	fmt.Println("  -crossfile-index     File keeping the project-wide Go index between runs")
+	fmt.Println("  -ignore-functions    Globs or /regexp/ of Go functions never reported as unused (default Test*,Benchmark*,Example*)")
+	fmt.Println("  -ignore-receivers    Globs or /regexp/ of receiver types whose methods are never reported")
+	fmt.Println("  -unused-min-statements  Statements a Go function needs to be reported as unused (default 0, any size)")
+	fmt.Println()
+}
+
+func printLanguageOverrideOptions() {
+	fmt.Println("Per-language Size Overrides (0 = use the global limit):")
+	fmt.Println("  -go-func-max-lines       Maximum function size for Go files")
+	fmt.Println("  -go-file-max-lines       Maximum file size for Go files")
+	fmt.Println("  -python-func-max-lines   Maximum function size for Python files")
+	fmt.Println("  -python-file-max-lines   Maximum file size for Python files")
+	fmt.Println("  -js-func-max-lines       Maximum function size for JavaScript/TypeScript files")
+	fmt.Println("  -js-file-max-lines       Maximum file size for JavaScript/TypeScript files")
+	fmt.Println()
+}
+
+func printFileRoutingOptions() {
+	fmt.Println("File Routing:")
+	fmt.Println("  -map-extensions string  Comma-separated ext=language pairs, e.g. .mjs=javascript,.pyi=python")
+	fmt.Println("  -include glob           Analyze only files matching the glob, e.g. 'src/**/*.ts' (repeatable)")
+	fmt.Println("  -exclude glob           Skip files and directories matching the glob, e.g. '**/generated/**' (repeatable)")
+	fmt.Println("  -external               Also analyze vendor directories, reporting vendored and third-party findings separately")
+	fmt.Println("  -list-files             Print the files that would be analyzed, by language, and the skipped paths and why, without analyzing them")
+	fmt.Println()
+}
+
+func printTestFileOptions() {
+	fmt.Println("Test File Relaxation (_test.go, test_*.py, *.test.js, *.spec.ts, __tests__/):")
+	fmt.Println("  -test-disable-rules      Comma-separated rule IDs to skip in test files")
+	fmt.Println("  -test-func-max-lines     Maximum function size in test files (0 = use the language limit)")
+	fmt.Println("  -test-file-max-lines     Maximum file size in test files (0 = use the language limit)")
+	fmt.Println()
+}
+
+func printAIFileOptions() {
+	fmt.Println("AI-Generated Files (a \"Generated by Copilot\" style header comment or a matching glob):")
+	fmt.Println("  -ai-paths glob           Treat files matching the glob, relative to the working directory, as AI-generated (repeatable)")
+	fmt.Println("  -ai-func-max-lines       Maximum function size in AI-generated files (0 = use the language limit)")
+	fmt.Println("  -ai-file-max-lines       Maximum file size in AI-generated files (0 = use the language limit)")
+	fmt.Println()
+}
+
+func printGoOptions() {
+	fmt.Println("Go-specific Options:")
+	fmt.Println("  -ignore-tests        Ignore test files during analysis (default false)")
+	fmt.Println("  -module string       Analyze only the Go module with this module path or directory")
+	fmt.Println()
+}
+
+func printGitOptions() {
+	fmt.Println("Git Hook Options:")
+	fmt.Println("  -staged              Analyze the staged contents of files staged in the git index")
+	fmt.Println("  -patch               Read a unified diff from stdin and report only the findings on the lines it adds or changes")
+	fmt.Println("  -fail-on string      Minimum severity that causes a non-zero exit (default \"info\")")
+	fmt.Println("  -fail-on-parse-errors  Exit non-zero when a file cannot be parsed or analyzed")
+	fmt.Println("  -blocking-rules string  Comma-separated rule IDs that always cause a non-zero exit")
+	fmt.Println("  -advisory-rules string  Comma-separated rule IDs that never cause a non-zero exit")
+	fmt.Println("  -tolerant            Run size and comment checks on files with syntax errors instead of skipping them")
+	fmt.Println("  -stream-threshold int  Size in bytes above which Python and JS/TS files are analyzed in one streaming pass (0 = never)")
+	fmt.Println("  -max-line-length int   Length in bytes above which Python and JS/TS lines are truncated and JS/TS files skipped as minified (0 = never)")
+	fmt.Println()
+}
+
+func printPerformanceOptions() {
+	fmt.Println("Performance Options:")
+	fmt.Println("  -workers int         Number of files analyzed in parallel (0 = one per CPU)")
+	fmt.Println("  -max-memory string   Heap size to keep the analysis under, e.g. 512MB or 2GB (default: no limit)")
+	fmt.Println()
+}
+
+func printGeneralOptions() {
+	fmt.Println("General Options:")
+	fmt.Println("  -config string       Path to the project configuration file (default: agentlint.yaml in the current directory)")
+	fmt.Println("  -profile string      Rule profile applied under the configuration files: strict, default, relaxed or one defined under profiles")
+	fmt.Println("  -log-level string    Minimum level of diagnostics written to stderr (debug, info, warn, error) (default \"info\")")
+	fmt.Println("  -log-format string   Format of diagnostics written to stderr (text, json) (default \"text\")")
+	fmt.Println("  -version             Show version information")
+	fmt.Println("  -help                Show help information")
+	fmt.Println()
+}
+
+func printExamples() {
+	fmt.Println("Examples:")
+	fmt.Println("  agentlint ./myproject")
+	fmt.Println("  agentlint -format json -output report.json ./myproject")
+	fmt.Println("  agentlint -func-max-lines 30 -file-max-lines 200 ./myproject")
+	fmt.Println("  agentlint -enable-comments=false -check-unused-funcs=false ./myproject")
+	fmt.Println("  agentlint install-hook -fail-on warning")
+}
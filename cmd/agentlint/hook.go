@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// hookMarker identifies pre-commit hooks written by install-hook so they can be safely replaced
+const hookMarker = "# Installed by agentlint install-hook"
+
+// runInstallHook implements the install-hook subcommand and returns the process exit code
+func runInstallHook(args []string) int {
+	fs := flag.NewFlagSet("install-hook", flag.ContinueOnError)
+	failOn := fs.String("fail-on", "warning", "Minimum severity that blocks the commit (error, warning, info)")
+	force := fs.Bool("force", false, "Overwrite an existing pre-commit hook")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if !isValidFailOn(*failOn) {
+		slog.Error("invalid -fail-on value (expected error, warning, info or none)", "value", *failOn)
+		return 2
+	}
+
+	hookPath, err := preCommitHookPath()
+	if err != nil {
+		slog.Error("locating git hooks directory failed", "error", err)
+		return 1
+	}
+
+	if err := installHook(hookPath, *failOn, *force); err != nil {
+		slog.Error("installing hook failed", "error", err)
+		return 1
+	}
+
+	fmt.Printf("Installed pre-commit hook at %s\n", hookPath)
+	return 0
+}
+
+// installHook writes the pre-commit script, refusing to clobber foreign hooks unless forced
+func installHook(hookPath, failOn string, force bool) error {
+	if existing, err := os.ReadFile(hookPath); err == nil {
+		if !force && !strings.Contains(string(existing), hookMarker) {
+			return fmt.Errorf("a pre-commit hook already exists at %s (use -force to overwrite)", hookPath)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(hookPath), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(hookPath, []byte(hookScript(failOn)), 0755)
+}
+
+// hookScript renders the shell script run by git before each commit
+func hookScript(failOn string) string {
+	var sb strings.Builder
+	sb.WriteString("#!/bin/sh\n")
+	sb.WriteString(hookMarker + "\n")
+	sb.WriteString("# Analyzes staged files and blocks the commit on findings at or above the threshold.\n")
+	fmt.Fprintf(&sb, "exec %s -staged -fail-on %s\n", hookCommand(), failOn)
+	return sb.String()
+}
+
+// hookCommand returns the command the hook should invoke, preferring agentlint on PATH
+func hookCommand() string {
+	if _, err := exec.LookPath("agentlint"); err == nil {
+		return "agentlint"
+	}
+	if exe, err := os.Executable(); err == nil {
+		return "'" + strings.ReplaceAll(exe, "'", `'\''`) + "'"
+	}
+	return "agentlint"
+}
+
+// preCommitHookPath resolves the pre-commit hook location, honouring core.hooksPath
+func preCommitHookPath() (string, error) {
+	out, err := gitOutput("", "rev-parse", "--git-path", "hooks/pre-commit")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(strings.TrimSpace(out))
+}
+
+// stagedSnapshot is the git index checked out into a temporary directory. The hook analyzes
+// the snapshot rather than the working tree, which may hold changes left out of the commit
+// with git add -p, and maps the paths it reports back to the working tree.
+type stagedSnapshot struct {
+	dir      string   // temporary directory the index is checked out into
+	toplevel string   // root of the working tree, the counterpart of dir
+	workDir  string   // the counterpart of the working directory in dir
+	files    []string // files added, copied, modified or renamed in the index, under dir
+}
+
+// checkoutStaged checks out the files of the index under the working directory, along with
+// the staged files, into a temporary directory. Close removes it.
+func checkoutStaged() (*stagedSnapshot, error) {
+	toplevel, err := gitOutput("", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := gitOutput("", "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	staged, err := gitOutput("", "diff", "--cached", "--name-only", "--diff-filter=ACMR", "-z")
+	if err != nil {
+		return nil, err
+	}
+	toplevel = strings.TrimSpace(toplevel)
+	prefix = strings.TrimSpace(prefix)
+	pathspec := prefix
+	if pathspec == "" {
+		pathspec = "."
+	}
+	// the rest of the project is needed too, for the analyses that look across files
+	tracked, err := gitOutput(toplevel, "ls-files", "-z", "--", pathspec)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "agentlint-staged-")
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &stagedSnapshot{dir: dir, toplevel: toplevel, workDir: filepath.Join(dir, filepath.FromSlash(prefix))}
+	cmd := exec.Command("git", "checkout-index", "-f", "-z", "--stdin", "--prefix="+dir+string(filepath.Separator))
+	cmd.Dir = toplevel
+	cmd.Stdin = strings.NewReader(tracked + staged)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		snapshot.Close()
+		return nil, fmt.Errorf("git checkout-index: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	if err := os.MkdirAll(snapshot.workDir, 0o755); err != nil {
+		snapshot.Close()
+		return nil, err
+	}
+
+	for _, name := range strings.Split(staged, "\x00") {
+		if name != "" {
+			snapshot.files = append(snapshot.files, filepath.Join(dir, filepath.FromSlash(name)))
+		}
+	}
+	return snapshot, nil
+}
+
+// worktreePath returns the working tree path of a path in the snapshot, and other paths as
+// they are
+func (s *stagedSnapshot) worktreePath(path string) string {
+	rel, err := filepath.Rel(s.dir, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return path
+	}
+	return filepath.Join(s.toplevel, rel)
+}
+
+// restorePaths maps the paths of findings and file errors from the snapshot to the working tree
+func (s *stagedSnapshot) restorePaths(results []core.Result, fileErrors []core.FileError) {
+	for i := range results {
+		results[i].FilePath = s.worktreePath(results[i].FilePath)
+		if related := results[i].Related; related != nil {
+			related.FilePath = s.worktreePath(related.FilePath)
+		}
+	}
+	for i := range fileErrors {
+		fileErrors[i].FilePath = s.worktreePath(fileErrors[i].FilePath)
+	}
+}
+
+// Close removes the snapshot
+func (s *stagedSnapshot) Close() error {
+	return os.RemoveAll(s.dir)
+}
+
+// closeSnapshot removes the snapshot of a -staged run once the files have been analyzed
+func closeSnapshot(flags *parsedFlags) {
+	if flags.snapshot == nil {
+		return
+	}
+	if err := flags.snapshot.Close(); err != nil {
+		slog.Warn("removing the checked out index failed", "dir", flags.snapshot.dir, "error", err)
+	}
+	flags.snapshot = nil
+}
+
+// gitOutput runs git with the given arguments in dir, or the working directory when dir is
+// "", and returns its standard output
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// isValidGroupBy checks a -group-by value
+func isValidGroupBy(value string) bool {
+	return value == "file" || value == "rule"
+}
+
+// isValidFailOn checks a -fail-on threshold value
+func isValidFailOn(value string) bool {
+	switch value {
+	case "error", "warning", "info", "none":
+		return true
+	}
+	return false
+}
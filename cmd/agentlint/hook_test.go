@@ -0,0 +1,239 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// newGitRepo creates a git repository in a temporary directory and changes into it for the
+// rest of the test
+func newGitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	git(t, "init", "-q")
+	git(t, "config", "user.email", "dev@example.com")
+	git(t, "config", "user.name", "dev")
+	return dir
+}
+
+// git runs a git command in the working directory
+func git(t *testing.T, args ...string) {
+	t.Helper()
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHookScript(t *testing.T) {
+	script := hookScript("error")
+	if !strings.HasPrefix(script, "#!/bin/sh\n") || !strings.Contains(script, hookMarker) {
+		t.Errorf("Expected a shell script carrying the marker, got %q", script)
+	}
+	if !strings.Contains(script, " -staged -fail-on error\n") {
+		t.Errorf("Expected the script to analyze staged files with the threshold, got %q", script)
+	}
+}
+
+func TestInstallHook(t *testing.T) {
+	hookPath := filepath.Join(t.TempDir(), "hooks", "pre-commit")
+	if err := installHook(hookPath, "warning", false); err != nil {
+		t.Fatalf("installHook failed: %v", err)
+	}
+	info, err := os.Stat(hookPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("Expected an executable hook, got mode %v", info.Mode())
+	}
+
+	// a hook of ours is replaced, a foreign one only when forced
+	if err := installHook(hookPath, "error", false); err != nil {
+		t.Errorf("Expected our own hook to be replaced, got %v", err)
+	}
+	writeFile(t, hookPath, "#!/bin/sh\nmake lint\n")
+	if err := installHook(hookPath, "error", false); err == nil {
+		t.Error("Expected a foreign hook to be kept")
+	}
+	if err := installHook(hookPath, "error", true); err != nil {
+		t.Errorf("Expected -force to replace a foreign hook, got %v", err)
+	}
+	if data, _ := os.ReadFile(hookPath); !strings.Contains(string(data), hookMarker) {
+		t.Errorf("Expected our hook after -force, got %q", data)
+	}
+}
+
+func TestCheckoutStaged(t *testing.T) {
+	repo := newGitRepo(t)
+	writeFile(t, filepath.Join(repo, "app", "main.go"), "package main\n\nfunc main() {}\n")
+	writeFile(t, filepath.Join(repo, "app", "util.go"), "package main\n")
+	writeFile(t, filepath.Join(repo, "README"), "readme\n")
+	git(t, "add", ".")
+	git(t, "commit", "-q", "-m", "init")
+
+	// main.go is staged with a change and then changed again without staging
+	writeFile(t, filepath.Join(repo, "app", "main.go"), "package main\n\nfunc main() { run() }\n")
+	git(t, "add", "app/main.go")
+	writeFile(t, filepath.Join(repo, "app", "main.go"), "package main\n\nfunc main() { broken(\n")
+	writeFile(t, filepath.Join(repo, "app", "new.go"), "package main\n") // untracked
+
+	snapshot, err := checkoutStaged()
+	if err != nil {
+		t.Fatalf("checkoutStaged failed: %v", err)
+	}
+	defer snapshot.Close()
+
+	staged := filepath.Join(snapshot.dir, "app", "main.go")
+	if len(snapshot.files) != 1 || snapshot.files[0] != staged {
+		t.Fatalf("Expected only app/main.go staged, got %v", snapshot.files)
+	}
+	if data, _ := os.ReadFile(staged); string(data) != "package main\n\nfunc main() { run() }\n" {
+		t.Errorf("Expected the staged content, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(snapshot.dir, "app", "util.go")); err != nil {
+		t.Errorf("Expected the unchanged files of the index, for project-wide analyses: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(snapshot.dir, "app", "new.go")); err == nil {
+		t.Error("Expected untracked files to be left out")
+	}
+	if snapshot.workDir != snapshot.dir {
+		t.Errorf("Expected the working directory at the top of the snapshot, got %s", snapshot.workDir)
+	}
+
+	results := []core.Result{{FilePath: staged, Related: &core.Location{FilePath: filepath.Join(snapshot.dir, "app", "util.go")}}}
+	fileErrors := []core.FileError{{FilePath: staged}}
+	snapshot.restorePaths(results, fileErrors)
+	if want := filepath.Join(repo, "app", "main.go"); results[0].FilePath != want || fileErrors[0].FilePath != want {
+		t.Errorf("Expected paths mapped to %s, got %s and %s", want, results[0].FilePath, fileErrors[0].FilePath)
+	}
+	if want := filepath.Join(repo, "app", "util.go"); results[0].Related.FilePath != want {
+		t.Errorf("Expected the related path mapped to %s, got %s", want, results[0].Related.FilePath)
+	}
+
+	dir := snapshot.dir
+	snapshot.Close()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected Close to remove the snapshot, got %v", err)
+	}
+}
+
+func TestCheckoutStaged_Subdirectory(t *testing.T) {
+	repo := newGitRepo(t)
+	writeFile(t, filepath.Join(repo, "app", "main.go"), "package main\n")
+	writeFile(t, filepath.Join(repo, "lib", "lib.go"), "package lib\n")
+	git(t, "add", ".")
+	if err := os.Chdir(filepath.Join(repo, "app")); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := checkoutStaged()
+	if err != nil {
+		t.Fatalf("checkoutStaged failed: %v", err)
+	}
+	defer snapshot.Close()
+
+	if want := filepath.Join(snapshot.dir, "app"); snapshot.workDir != want {
+		t.Errorf("Expected the working directory at %s, got %s", want, snapshot.workDir)
+	}
+	// files staged outside the working directory are still analyzed
+	if len(snapshot.files) != 2 {
+		t.Errorf("Expected both staged files, got %v", snapshot.files)
+	}
+	for _, file := range snapshot.files {
+		if _, err := os.Stat(file); err != nil {
+			t.Errorf("Expected %s to be checked out: %v", file, err)
+		}
+	}
+}
+
+func TestCheckPathArgs(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.go")
+	writeFile(t, file, "package a\n")
+
+	tests := []struct {
+		name  string
+		args  []string
+		valid bool
+	}{
+		{"no arguments", nil, true},
+		{"directories", []string{dir, dir}, true},
+		{"files", []string{file, file}, true},
+		{"files and directories", []string{file, dir}, false},
+		{"missing file", []string{file, filepath.Join(dir, "missing.go")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkPathArgs(tt.args); (err == nil) != tt.valid {
+				t.Errorf("Expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestStagedRun(t *testing.T) {
+	repo := newGitRepo(t)
+	writeFile(t, filepath.Join(repo, "main.go"), "package main\n\nfunc main() {}\n")
+	git(t, "add", ".")
+	git(t, "commit", "-q", "-m", "init")
+
+	// the staged main.go is too large, the working tree copy is not
+	writeFile(t, filepath.Join(repo, "main.go"), "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(1)\n\tfmt.Println(2)\n}\n")
+	git(t, "add", "main.go")
+	writeFile(t, filepath.Join(repo, "main.go"), "package main\n\nfunc main() {}\n")
+
+	report, code := runJSON(t, repo, "-staged", "-func-max-lines", "3", "-fail-on", "warning")
+	if code != 1 {
+		t.Errorf("Expected the staged function to block the commit, got exit code %d", code)
+	}
+	if len(report.Results) != 1 || report.Results[0].RuleID != "large-function" ||
+		report.Results[0].FilePath != filepath.Join(repo, "main.go") || report.Results[0].Line != 5 {
+		t.Errorf("Expected large-function at main.go:5, got %+v", report.Results)
+	}
+
+	// once the change is staged, the commit passes whatever the working tree holds
+	git(t, "add", "main.go")
+	writeFile(t, filepath.Join(repo, "main.go"), "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(1)\n\tfmt.Println(2)\n}\n")
+	if _, code := runJSON(t, repo, "-staged", "-func-max-lines", "3", "-fail-on", "warning"); code != 0 {
+		t.Errorf("Expected the staged content to pass, got exit code %d", code)
+	}
+}
+
+func TestMixedPathArgs(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.go"), "package a\n")
+	cmd := exec.Command(agentlintBinary(t), "a.go", ".")
+	cmd.Dir = dir
+	if err := cmd.Run(); cmd.ProcessState.ExitCode() != 2 {
+		t.Errorf("Expected files mixed with directories to be refused with exit code 2, got %v", err)
+	}
+}
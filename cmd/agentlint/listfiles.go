@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang"
+)
+
+// listFiles implements -list-files: it runs only the scanning phase of each project and prints
+// the files that would be analyzed, grouped by language, followed by the paths that were
+// skipped and why
+func listFiles(ctx context.Context, w io.Writer, flags *parsedFlags, scanner *languages.MultiScanner, cfg core.Config, roots []string) error {
+	scanner.RecordSkipped(true)
+	defer scanner.RecordSkipped(false)
+
+	dirs := roots
+	if len(dirs) == 0 {
+		dirs = []string{""}
+	}
+	for i, dir := range dirs {
+		filesByLanguage, root, _, err := projectFiles(ctx, flags, scanner, dir)
+		if err != nil {
+			return err
+		}
+		skipped := scanner.Skipped()
+		filesByLanguage, ignored := ignoredGoFiles(filesByLanguage, cfg)
+		skipped = append(skipped, ignored...)
+		if flags.snapshot != nil {
+			for _, files := range filesByLanguage {
+				for i := range files {
+					files[i] = flags.snapshot.worktreePath(files[i])
+				}
+			}
+			for i := range skipped {
+				skipped[i].Path = flags.snapshot.worktreePath(skipped[i].Path)
+			}
+		}
+		sort.SliceStable(skipped, func(i, j int) bool { return skipped[i].Path < skipped[j].Path })
+
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if dir != "" {
+			fmt.Fprintf(w, "Root %s:\n\n", dir)
+		}
+		if flags.module != "" {
+			fmt.Fprintf(w, "Module %s (%s), files of other modules are not listed\n\n", flags.module, root)
+		}
+		printFileList(w, filesByLanguage, skipped)
+	}
+	return nil
+}
+
+// ignoredGoFiles moves the Go files the analyzer skips out of filesByLanguage, see
+// golang.IgnoreReason
+func ignoredGoFiles(filesByLanguage map[string][]string, cfg core.Config) (map[string][]string, []languages.SkippedPath) {
+	var kept []string
+	var ignored []languages.SkippedPath
+	for _, file := range filesByLanguage["go"] {
+		if reason := golang.IgnoreReason(file, cfg); reason != "" {
+			ignored = append(ignored, languages.SkippedPath{Path: file, Reason: reason})
+		} else {
+			kept = append(kept, file)
+		}
+	}
+	if len(kept) == 0 {
+		delete(filesByLanguage, "go")
+	} else if len(ignored) > 0 {
+		filesByLanguage["go"] = kept
+	}
+	return filesByLanguage, ignored
+}
+
+// printFileList prints the files of each language, languages sorted by name, and the skipped
+// paths
+func printFileList(w io.Writer, filesByLanguage map[string][]string, skipped []languages.SkippedPath) {
+	languageNames := make([]string, 0, len(filesByLanguage))
+	total := 0
+	for language, files := range filesByLanguage {
+		languageNames = append(languageNames, language)
+		total += len(files)
+	}
+	sort.Strings(languageNames)
+
+	for _, language := range languageNames {
+		files := filesByLanguage[language]
+		fmt.Fprintf(w, "%s (%s):\n", language, plural(len(files), "file"))
+		for _, file := range files {
+			fmt.Fprintf(w, "  %s\n", file)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(skipped) > 0 {
+		fmt.Fprintf(w, "Skipped (%s):\n", plural(len(skipped), "path"))
+		for _, path := range skipped {
+			fmt.Fprintf(w, "  %s: %s\n", path.Path, path.Reason)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%s would be analyzed, %s skipped\n", plural(total, "file"), plural(len(skipped), "path"))
+}
+
+// plural formats a count with a noun, adding an s unless the count is one
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+	"github.com/CiaranMcAleer/AgentLint/internal/llm"
+)
+
+// Defaults of the LLM settings left at 0
+const (
+	defaultLLMMaxFindings  = 10
+	defaultLLMContextLines = 10
+)
+
+// llmAPIKeyVariable is the environment variable holding the API key of the model
+const llmAPIKeyVariable = "AGENTLINT_LLM_API_KEY"
+
+// llmFlags are the command-line flags asking a language model for tailored suggestions
+type llmFlags struct {
+	llmSuggest  string
+	llmEndpoint string
+	llmModel    string
+	llmTimeout  int
+}
+
+// register defines the LLM flags, defaulting to the settings of base
+func (f *llmFlags) register(base core.LLMConfig) {
+	flag.StringVar(&f.llmSuggest, "llm-suggest", "", "Ask a language model for a suggestion tailored to the findings with these comma-separated fingerprints or rule IDs, or all")
+	flag.StringVar(&f.llmEndpoint, "llm-endpoint", base.Endpoint, "Base URL of the OpenAI-compatible API -llm-suggest asks, e.g. http://localhost:11434/v1")
+	flag.StringVar(&f.llmModel, "llm-model", base.Model, "Model -llm-suggest asks")
+	flag.IntVar(&f.llmTimeout, "llm-timeout", base.Timeout, "Seconds -llm-suggest allows for each answer (0 allows 60)")
+}
+
+// valid logs the first invalid LLM flag or setting of base, if any, and reports whether they
+// were all valid
+func (f *llmFlags) valid(base core.LLMConfig) bool {
+	if f.llmSuggest != "" && (f.llmEndpoint == "" || f.llmModel == "") {
+		slog.Error("-llm-suggest needs the -llm-endpoint and -llm-model of the model to ask")
+		return false
+	}
+	if f.llmTimeout < 0 {
+		slog.Error("invalid -llm-timeout value (expected seconds, 0 or more)", "value", f.llmTimeout)
+		return false
+	}
+	if _, err := llm.NewRedactor(base.Redact); err != nil {
+		slog.Error("invalid output.llm.redact value", "error", err)
+		return false
+	}
+	return true
+}
+
+// config returns the LLM settings given by the flags. Settings without a flag, such as the
+// redaction patterns, come from base.
+func (f *llmFlags) config(base core.LLMConfig) core.LLMConfig {
+	base.Endpoint = f.llmEndpoint
+	base.Model = f.llmModel
+	base.Timeout = f.llmTimeout
+	return base
+}
+
+// apiKey returns the key in llmAPIKeyVariable when -llm-endpoint names the endpoint. An
+// endpoint left to the configuration gets no key, so that a configuration file, possibly
+// fetched with extends, cannot send it to a host of its choosing.
+func (f *llmFlags) apiKey() string {
+	key := os.Getenv(llmAPIKeyVariable)
+	if key == "" {
+		return ""
+	}
+	given := false
+	flag.Visit(func(fl *flag.Flag) {
+		given = given || fl.Name == "llm-endpoint"
+	})
+	if !given {
+		slog.Warn("the API key is only sent to an endpoint given with -llm-endpoint", "variable", llmAPIKeyVariable)
+		return ""
+	}
+	return key
+}
+
+// suggestWithLLM replaces the suggestion of each finding selected by -llm-suggest with one a
+// language model tailored to its code, up to settings.MaxFindings findings. A finding the
+// model gives no answer for keeps the rule's suggestion, and the failure is logged without
+// failing the run. apiKey, when not "", is sent along.
+func suggestWithLLM(settings core.LLMConfig, apiKey, selection string, results []core.Result) {
+	redactor, err := llm.NewRedactor(settings.Redact)
+	if err != nil {
+		slog.Warn("asking for suggestions failed", "error", err)
+		return
+	}
+	timeout := llm.DefaultTimeout
+	if settings.Timeout > 0 {
+		timeout = time.Duration(settings.Timeout) * time.Second
+	}
+	client := &llm.Client{Endpoint: settings.Endpoint, Model: settings.Model, APIKey: apiKey, Redactor: redactor, HTTP: &http.Client{Timeout: timeout}, Timeout: timeout}
+	maxFindings, contextLines := settings.MaxFindings, settings.ContextLines
+	if maxFindings <= 0 {
+		maxFindings = defaultLLMMaxFindings
+	}
+	if contextLines <= 0 {
+		contextLines = defaultLLMContextLines
+	}
+
+	wd, _ := os.Getwd()
+	selected := splitList(selection)
+	sources := make(map[string][]string)
+	asked := 0
+	for i := range results {
+		if !selectedForLLM(results[i], selected) {
+			continue
+		}
+		if asked == maxFindings {
+			slog.Warn("more findings selected than output.llm.maxFindings; the rest keep the rule's suggestion", "max", maxFindings)
+			break
+		}
+		asked++
+
+		excerpt := ""
+		if !settings.OmitSource {
+			lines, ok := sources[results[i].FilePath]
+			if !ok {
+				lines = readSourceLines(results[i].FilePath)
+				sources[results[i].FilePath] = lines
+			}
+			excerpt = llm.Excerpt(lines, results[i].Line, contextLines)
+		}
+		finding := results[i]
+		if rel, err := filepath.Rel(wd, finding.FilePath); err == nil && filepath.IsAbs(finding.FilePath) && !strings.HasPrefix(rel, "..") {
+			finding.FilePath = rel // the layout of this machine is none of the model's business
+		}
+		suggestion, err := client.Suggest(context.Background(), finding, excerpt)
+		if err != nil {
+			slog.Warn("asking for a suggestion failed", "rule", results[i].RuleID, "file", results[i].FilePath, "error", err)
+			continue
+		}
+		results[i].Suggestion = suggestion
+	}
+	if asked == 0 {
+		slog.Warn("-llm-suggest selects none of the findings", "selection", selection)
+	}
+}
+
+// selectedForLLM reports whether -llm-suggest selects a result: by rule ID, by a prefix of its
+// fingerprint, or all results
+func selectedForLLM(result core.Result, selected []string) bool {
+	for _, s := range selected {
+		if s == "all" || s == result.RuleID || (result.Fingerprint != "" && strings.HasPrefix(result.Fingerprint, s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// readSourceLines returns the lines of a source file, or nil when it cannot be read
+func readSourceLines(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(string(languages.DecodeSource(data)), "\r\n", "\n"), "\n")
+}
+
+func printLLMOptions() {
+	fmt.Println("LLM Suggestions:")
+	fmt.Println("  -llm-suggest list     Ask a language model for tailored suggestions for the findings with these fingerprints or rule IDs, or all")
+	fmt.Println("  -llm-endpoint url     Base URL of the OpenAI-compatible API -llm-suggest asks")
+	fmt.Println("  -llm-model string     Model -llm-suggest asks")
+	fmt.Println("  -llm-timeout n        Seconds -llm-suggest allows for each answer (default: 60)")
+	fmt.Println()
+}
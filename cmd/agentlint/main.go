@@ -2,23 +2,56 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/CiaranMcAleer/AgentLint/internal/config"
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
 	"github.com/CiaranMcAleer/AgentLint/internal/languages"
 	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang"
 	"github.com/CiaranMcAleer/AgentLint/internal/languages/python"
 	"github.com/CiaranMcAleer/AgentLint/internal/languages/reactnative"
+	"github.com/CiaranMcAleer/AgentLint/internal/logging"
 	"github.com/CiaranMcAleer/AgentLint/internal/output"
 	"github.com/CiaranMcAleer/AgentLint/internal/profiling"
 )
 
+// subcommands are run instead of an analysis when named by the first argument. Each parses
+// the arguments after its name and returns the process exit code.
+var subcommands = map[string]func(args []string) int{
+	"install-hook": runInstallHook,
+	"explain":      runExplain,
+	"config":       runConfig,
+	"benchmark":    runBenchmark,
+	"worker":       runWorker,
+	"schema":       runSchema,
+	"diff":         runDiff,
+	"export":       runExport,
+}
+
 func main() {
-	flags := parseFlags()
+	setupLogging(logging.FormatText, "info")
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			os.Exit(run(os.Args[2:]))
+		}
+	}
+
+	loaded := loadConfig(earlyFlag(os.Args[1:], "config"), earlyFlag(os.Args[1:], "profile"))
+	if loaded == nil {
+		os.Exit(2)
+	}
+	flags := parseFlags(loaded.Config)
 	if flags.showHelp {
 		showHelp()
 		return
@@ -28,63 +61,440 @@ func main() {
 		return
 	}
 
-	setupProfiling(flags)
+	if !setupLogging(flags.logFormat, flags.logLevel) {
+		os.Exit(2)
+	}
+	if !reportConfigDiagnostics(loaded.Diagnostics) {
+		os.Exit(2)
+	}
+	if !isValidFailOn(flags.failOn) {
+		slog.Error("invalid -fail-on value (expected error, warning, info or none)", "value", flags.failOn)
+		os.Exit(2)
+	}
+	if !flags.outputFlags.valid() || !flags.notifyFlags.valid() || !flags.llmFlags.valid(loaded.Config.Output.LLM) {
+		os.Exit(2)
+	}
+	if err := golang.ValidateNamePatterns(splitList(flags.orphanedIgnoreFunctions)); err != nil {
+		slog.Error("invalid -ignore-functions value", "error", err)
+		os.Exit(2)
+	}
+	if err := golang.ValidateNamePatterns(splitList(flags.orphanedIgnoreReceivers)); err != nil {
+		slog.Error("invalid -ignore-receivers value", "error", err)
+		os.Exit(2)
+	}
+	memoryLimit, err := parseMemorySize(flags.maxMemory)
+	if err != nil {
+		slog.Error("invalid -max-memory value", "error", err)
+		os.Exit(2)
+	}
+	flags.memoryLimit = memoryLimit
+	if flags.fixHeaders && flags.headerTemplate == "" {
+		slog.Error("-fix-headers requires a header template, set with -header-template or headers.template")
+		os.Exit(2)
+	}
+	if err := checkPathArgs(flag.Args()); err != nil {
+		slog.Error("invalid path arguments", "error", err)
+		os.Exit(2)
+	}
+
+	stopProfiling := flags.profilingFlags.start()
 	setupWorkers(flags)
 
-	path := resolvePath()
-	cfg := buildConfig(flags)
+	cfg := buildConfig(flags, loaded.Config)
 	cfg.Language.Go.IgnoreTests = flags.goIgnoreTests
 	ctx := context.Background()
 
-	registry := setupAnalyzer(cfg)
+	// Go files are parsed once and shared by the per-file and cross-file analyses
+	astCache := golang.NewASTCache(0)
+	registry := setupAnalyzer(cfg, astCache)
 	scanner := languages.NewMultiScanner(registry)
+	if err := scanner.SetExtensionMap(cfg.Language.Extensions); err != nil {
+		slog.Error("invalid -map-extensions value", "error", err)
+		os.Exit(2)
+	}
+	if err := scanner.SetPathFilter(cfg.Files.Include, cfg.Files.Exclude); err != nil {
+		slog.Error("invalid -include or -exclude glob", "error", err)
+		os.Exit(2)
+	}
+	if cfg.Files.External {
+		scanner.IncludeVendored()
+	}
+	resultPaths, err := languages.NewPathFilter(cfg.Output.PathFilter, nil)
+	if err != nil {
+		slog.Error("invalid -path-filter glob", "error", err)
+		os.Exit(2)
+	}
+	if _, err := languages.NewPathFilter(cfg.AIFiles.Paths, nil); err != nil {
+		slog.Error("invalid -ai-paths glob", "error", err)
+		os.Exit(2)
+	}
+	roots := analysisRoots(flags)
+	if len(roots) > 0 && flags.module != "" {
+		slog.Error("-module cannot be combined with several paths")
+		os.Exit(2)
+	}
+	if flags.patch && (flags.staged || flags.module != "" || flags.listFiles || flag.NArg() > 1 || len(fileArgs()) > 0) {
+		slog.Error("-patch takes at most the directory the diff applies to, and cannot be combined with -staged, -module or -list-files")
+		os.Exit(2)
+	}
+	if flags.staged {
+		snapshot, err := checkoutStaged()
+		if err != nil {
+			fatal("reading the git index failed", "error", err)
+		}
+		flags.snapshot = snapshot
+		// the snapshot is new on every run, so an index of it would only be rebuilt
+		cfg.Rules.OrphanedCode.IndexFile = ""
+	}
+	if flags.listFiles {
+		err := listFiles(ctx, os.Stdout, flags, scanner, cfg, roots)
+		closeSnapshot(flags)
+		if err != nil {
+			fatal("listing files failed", "error", err)
+		}
+		return
+	}
 	timing := profiling.NewTimingStats()
 
-	filesByLanguage, err := scanFiles(ctx, path, scanner)
+	var allResults []core.Result
+	var fileErrors []core.FileError
+	kinds := make(map[string]string)
+	if flags.patch {
+		var err error
+		if allResults, fileErrors, err = analyzePatch(ctx, os.Stdin, resolvePath(), flags, scanner, registry, cfg); err != nil {
+			stopProfiling()
+			fatal("analysis failed", "error", err)
+		}
+	} else if len(roots) == 0 {
+		var err error
+		var kind golang.ProjectKind
+		allResults, fileErrors, kind, err = analyzeProject(ctx, flags, scanner, registry, cfg, astCache, timing, "")
+		if flags.snapshot != nil {
+			// fingerprinted while the lines they point at are those of the staged files
+			fingerprintRelativeTo(allResults, flags.snapshot.dir)
+			flags.snapshot.restorePaths(allResults, fileErrors)
+			// the path globs and the coverage profile name the files where they are checked out
+			annotateCoverage(allResults, cfg.Output.Coverage)
+			annotateAIGenerated(allResults, cfg.AIFiles)
+		}
+		closeSnapshot(flags)
+		if err != nil {
+			stopProfiling()
+			fatal("analysis failed", "error", err)
+		}
+		kinds[""] = string(kind)
+	}
+	for _, root := range roots {
+		results, errs, kind, err := analyzeProject(ctx, flags, scanner, registry, rootConfig(cfg, root), astCache, timing, root)
+		if err != nil {
+			stopProfiling()
+			fatal("analysis failed", "root", root, "error", err)
+		}
+		kinds[root] = string(kind)
+		for i := range results {
+			results[i].Root = root
+		}
+		allResults = append(allResults, results...)
+		fileErrors = append(fileErrors, errs...)
+	}
+	if !flags.patch && !flags.staged {
+		// patches and staged files are fingerprinted as analyzed; several roots, or files,
+		// share the directory they are named from
+		dir := "."
+		if len(roots) == 0 && len(fileArgs()) == 0 {
+			dir = resolvePath()
+		}
+		fingerprintResults(allResults, dir)
+	}
+	allResults = filterResults(allResults, cfg.Output)
+	allResults = filterPaths(allResults, resultPaths)
+	applySuggestions(allResults, cfg.Output.Suggestions)
+	stopProfiling()
+	if flags.llmSuggest != "" {
+		suggestWithLLM(cfg.Output.LLM, flags.llmFlags.apiKey(), flags.llmSuggest, allResults)
+	}
+	printResults(timing, allResults, fileErrors, roots, kinds, flags, cfg, scanner.Language)
+}
+
+// filterResults sets the confidence of each result and drops those the output settings leave
+// out by confidence, category, rule or severity, keeping their order
+func filterResults(results []core.Result, out core.OutputConfig) []core.Result {
+	core.SetConfidence(results)
+	results = core.FilterConfidence(results, core.Confidence(out.MinConfidence))
+	results = core.FilterCategories(results, out.OnlyCategories, out.SkipCategories)
+	results = core.FilterRules(results, out.OnlyRules)
+	return core.FilterSeverity(results, core.Severity(out.Severity))
+}
+
+// applySuggestions replaces the suggestions of results with the project's suggestion
+// templates, if any. The templates were checked with the configuration, so a failure to
+// apply one only leaves the rules' suggestions.
+func applySuggestions(results []core.Result, texts map[string]string) {
+	templates, err := core.ParseSuggestionTemplates(texts)
+	if err == nil {
+		err = templates.Apply(results)
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error scanning files: %v\n", err)
-		os.Exit(1)
+		slog.Warn("applying the suggestion templates failed", "error", err)
 	}
+}
 
-	allResults := analyzeFiles(ctx, filesByLanguage, registry, cfg)
-	printResults(timing, allResults, flags, cfg)
+// filterPaths returns the results in files selected by filter, matched by their path relative
+// to the working directory, keeping their order
+func filterPaths(results []core.Result, filter *languages.PathFilter) []core.Result {
+	if filter == nil {
+		return results
+	}
+	wd, _ := os.Getwd()
+	kept := results[:0]
+	for _, result := range results {
+		relPath := filepath.Clean(result.FilePath)
+		if rel, err := filepath.Rel(wd, relPath); err == nil && filepath.IsAbs(relPath) && !strings.HasPrefix(rel, "..") {
+			relPath = rel
+		}
+		if filter.Match(relPath) {
+			kept = append(kept, result)
+		}
+	}
+	return kept
 }
 
-func printVersion() {
-	fmt.Println("AgentLint v0.0.40")
-	fmt.Println("A linter for detecting LLM code bad smells")
+// analyzeProject runs every analysis over one project: the files named by the command line,
+// or everything under dir when several directories are analyzed, counting them in timing. It
+// also tells whether the project builds a binary or is a library.
+func analyzeProject(ctx context.Context, flags *parsedFlags, scanner *languages.MultiScanner, registry *languages.Registry, cfg core.Config, astCache *golang.ASTCache, timing *profiling.TimingStats, dir string) ([]core.Result, []core.FileError, golang.ProjectKind, error) {
+	filesByLanguage, root, modules, err := projectFiles(ctx, flags, scanner, dir)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	for language, files := range filesByLanguage {
+		timing.AddFiles(language, len(files))
+	}
+	// External code gets the per-file checks, but stays out of the project-wide analyses, whose
+	// findings are about how the project's own code fits together
+	analyzed := filesByLanguage
+	if cfg.Files.External {
+		filesByLanguage = ownFiles(root, analyzed)
+	}
+
+	var results []core.Result
+	var fileErrors []core.FileError
+	if flags.remoteFlags.enabled() {
+		if results, fileErrors, err = flags.remoteFlags.analyze(ctx, root, analyzed, cfg); err != nil {
+			return nil, nil, "", err
+		}
+	} else {
+		results, fileErrors = analyzeFiles(ctx, analyzed, registry, cfg, newEngine(flags))
+	}
+	results = append(results, analyzeModules(ctx, root, filesByLanguage["go"], cfg, astCache)...)
+	results = append(results, analyzeErrorStrings(ctx, filesByLanguage["go"], cfg, astCache)...)
+	results = append(results, analyzeSimilarity(ctx, filesByLanguage["go"], cfg, astCache)...)
+	results = append(results, analyzeUntested(ctx, filesByLanguage["go"], cfg, astCache)...)
+	results = append(results, analyzeLayout(ctx, flags, scanner, root, filesByLanguage, modules, cfg, astCache)...)
+	results = append(results, analyzeHeaders(ctx, flags, scanner, root, filesByLanguage, cfg)...)
+	logCacheStats(registry, astCache)
+	results = append(results, analyzeDependencies(ctx, flags, scanner, root, filesByLanguage, modules, cfg)...)
+	results = core.Dedupe(results)
+	results = core.Escalate(results, cfg.Rules.Systemic, functionCounter(ctx, analyzed, registry))
+	annotateModules(results, modules)
+	annotateOwners(results, cfg.Output.Codeowners, root)
+	annotateCoverage(results, cfg.Output.Coverage)
+	annotateAIGenerated(results, cfg.AIFiles)
+	if cfg.Files.External {
+		annotateExternal(results, root)
+	}
+	return results, fileErrors, classifyProject(ctx, flags, scanner, root, filesByLanguage, astCache), nil
+}
+
+// earlyFlag returns the value of the flag called name on the command line. -config and
+// -profile are needed before the other flags are defined, since their defaults come from
+// the configuration they select.
+func earlyFlag(args []string, name string) string {
+	found := ""
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		flagName, value, hasValue := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-"), "=")
+		if flagName != name || !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if hasValue {
+			found = value
+		} else if i+1 < len(args) {
+			found = args[i+1]
+		}
+	}
+	return found
+}
+
+// reportConfigDiagnostics logs the problems found in the configuration files and reports
+// whether the run can go ahead, which it cannot when a setting could not be applied
+func reportConfigDiagnostics(diags []config.Diagnostic) bool {
+	ok := true
+	for _, diag := range diags {
+		args := []any{"file", diag.Path}
+		if diag.Line > 0 {
+			args = append(args, "line", diag.Line)
+		}
+		switch diag.Severity {
+		case core.SeverityError:
+			slog.Error(diag.Message, args...)
+			ok = false
+		case core.SeverityWarning:
+			slog.Warn(diag.Message, args...)
+		default:
+			slog.Debug(diag.Message, args...)
+		}
+	}
+	if !ok {
+		slog.Error("invalid configuration; run agentlint config validate for details")
+	}
+	return ok
 }
 
-func setupProfiling(flags *parsedFlags) {
-	if flags.cpuProfile != "" {
-		if err := profiling.StartCPUProfile(flags.cpuProfile); err != nil {
-			fmt.Fprintf(os.Stderr, "Error starting CPU profile: %v\n", err)
-			os.Exit(1)
+// projectFiles returns the files of one project by language, as analyzeProject takes it, with
+// the directory the project is analyzed from and its Go modules
+func projectFiles(ctx context.Context, flags *parsedFlags, scanner *languages.MultiScanner, dir string) (map[string][]string, string, []golang.Module, error) {
+	var filesByLanguage map[string][]string
+	var err error
+	root := dir
+	if dir == "" {
+		filesByLanguage, err = collectFiles(ctx, flags, scanner)
+		root = moduleRoot(flags)
+	} else {
+		if root, err = filepath.Abs(dir); err == nil {
+			filesByLanguage, err = scanFiles(ctx, root, scanner)
 		}
-		defer profiling.StopCPUProfile()
+	}
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("scanning files failed: %w", err)
 	}
 
-	if flags.memProfile != "" {
-		if err := profiling.StartMemProfile(flags.memProfile); err != nil {
-			fmt.Fprintf(os.Stderr, "Error starting memory profile: %v\n", err)
-			os.Exit(1)
+	modules, err := golang.DiscoverModules(root)
+	if err != nil {
+		slog.Warn("discovering Go modules failed", "error", err)
+	}
+	if flags.module != "" {
+		var selected *golang.Module
+		filesByLanguage, selected, err = filterModule(filesByLanguage, modules, flags.module)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("selecting module failed: %w", err)
 		}
-		defer profiling.CloseMemProfile()
+		root = selected.Dir
+	}
+	return filesByLanguage, root, modules, nil
+}
+
+// analysisRoots returns the directories named on the command line when there are several,
+// each analyzed as a project of its own. A single path, a list of files and -staged give nil.
+func analysisRoots(flags *parsedFlags) []string {
+	if flags.staged || flag.NArg() < 2 || len(fileArgs()) > 0 {
+		return nil
 	}
 
-	if flags.traceProfile != "" {
-		if err := profiling.StartTrace(flags.traceProfile); err != nil {
-			fmt.Fprintf(os.Stderr, "Error starting trace: %v\n", err)
-			os.Exit(1)
+	var roots []string
+	seen := make(map[string]bool)
+	for _, arg := range flag.Args() {
+		info, err := os.Stat(arg)
+		if err != nil {
+			fatal("path does not exist", "path", arg)
+		}
+		if !info.IsDir() {
+			fatal("when analyzing several paths, each must be a directory", "path", arg)
+		}
+		if root := filepath.Clean(arg); !seen[root] {
+			seen[root] = true
+			roots = append(roots, root)
 		}
-		defer profiling.StopTrace()
 	}
+	return roots
+}
+
+// rootConfig adjusts the configuration for one of several roots: each keeps its own
+// cross-file index, since an index follows the files of a single root
+func rootConfig(cfg core.Config, root string) core.Config {
+	if indexFile := cfg.Rules.OrphanedCode.IndexFile; indexFile != "" {
+		absRoot, _ := filepath.Abs(root)
+		sum := sha256.Sum256([]byte(absRoot))
+		cfg.Rules.OrphanedCode.IndexFile = indexFile + "." + hex.EncodeToString(sum[:4])
+	}
+	return cfg
+}
+
+// version is the release of agentlint, reported by -version and in the JSON output
+const version = "0.0.40"
+
+func printVersion() {
+	fmt.Println("AgentLint v" + version)
+	fmt.Println("A linter for detecting LLM code bad smells")
+}
+
+// setupLogging installs the default logger for diagnostics on stderr and reports whether the
+// level and format were valid
+func setupLogging(format, level string) bool {
+	logger, err := logging.New(os.Stderr, level, format)
+	if err != nil {
+		slog.Error("invalid logging options", "error", err)
+		return false
+	}
+	slog.SetDefault(logger)
+	return true
+}
+
+// fatal logs an error and exits with status 1
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
 
 func setupWorkers(flags *parsedFlags) {
 	if flags.workers > 0 {
 		runtime.GOMAXPROCS(flags.workers)
 	}
+	if flags.memoryLimit > 0 {
+		// the garbage collector works harder as the heap nears the cap
+		debug.SetMemoryLimit(int64(flags.memoryLimit))
+	}
+}
+
+// newEngine creates the engine analyzing files on this machine, kept under -max-memory
+func newEngine(flags *parsedFlags) *core.Engine {
+	engine := core.NewEngine(flags.workers)
+	engine.SetMemoryLimit(flags.memoryLimit)
+	return engine
+}
+
+// memoryUnits are the suffixes accepted by -max-memory, in powers of 1024 as container memory
+// limits are
+var memoryUnits = []struct {
+	suffix string
+	size   uint64
+}{
+	{"KIB", 1 << 10}, {"MIB", 1 << 20}, {"GIB", 1 << 30},
+	{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30},
+	{"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30},
+	{"B", 1},
+}
+
+// parseMemorySize parses a size such as 512MB, 2GiB or 1073741824; "" and 0 mean no limit
+func parseMemorySize(value string) (uint64, error) {
+	number := strings.ToUpper(strings.TrimSpace(value))
+	if number == "" {
+		return 0, nil
+	}
+	unit := uint64(1)
+	for _, u := range memoryUnits {
+		if strings.HasSuffix(number, u.suffix) {
+			number, unit = strings.TrimSpace(strings.TrimSuffix(number, u.suffix)), u.size
+			break
+		}
+	}
+	size, err := strconv.ParseFloat(number, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("%q is not a size such as 512MB or 2GB", value)
+	}
+	return uint64(size * float64(unit)), nil
 }
 
 func resolvePath() string {
@@ -95,91 +505,324 @@ func resolvePath() string {
 
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Failed to get absolute path: %v\n", err)
-		os.Exit(1)
+		fatal("failed to get absolute path", "path", path, "error", err)
 	}
 
 	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Error: Path does not exist: %s\n", absPath)
-		os.Exit(1)
+		fatal("path does not exist", "path", absPath)
 	}
 
 	return absPath
 }
 
-func printResults(timing *profiling.TimingStats, allResults []core.Result, flags *parsedFlags, cfg core.Config) {
-	timing.Finish(len(allResults), len(allResults))
+func printResults(timing *profiling.TimingStats, allResults []core.Result, fileErrors []core.FileError, roots []string, kinds map[string]string, flags *parsedFlags, cfg core.Config, languageOf func(path string) string) {
+	timing.Finish(timing.FileCount, len(allResults))
 	if flags.verbose {
 		timing.Print()
 		profiling.PrintStats(profiling.GetStats())
 	}
+	if flags.profileRules {
+		profiling.PrintRuleProfile(os.Stderr, 0)
+	}
 
-	if flags.memProfile != "" {
-		profiling.WriteMemProfile()
+	if len(fileErrors) > 0 {
+		slog.Warn("some files could not be analyzed", "files", len(fileErrors))
 	}
 
-	outputResults(cfg, allResults)
+	failed := markBlocking(allResults, cfg.Output)
+	run := output.RunInfo{
+		Started:     timing.StartTime,
+		Finished:    timing.EndTime,
+		ToolVersion: version,
+		ConfigHash:  cfg.Hash(),
+		Languages:   timing.Languages,
+	}
+	if cfg.Output.MetricsFile != "" {
+		if err := output.WritePrometheusFile(cfg.Output.MetricsFile, allResults, run, languageOf); err != nil {
+			fatal("writing metrics file failed", "error", err)
+		}
+	}
+	outputResults(cfg, allResults, fileErrors, roots, kinds, run, flags.outputFile)
+	notifyWebhook(cfg.Output.Notify, allResults, projectName(roots))
 
-	if len(allResults) > 0 {
+	if failed {
+		os.Exit(1)
+	}
+	if cfg.Output.FailOnParseErrors && len(fileErrors) > 0 {
 		os.Exit(1)
 	}
 }
 
+// markBlocking sets Blocking on each result so formatters can tell enforced findings from
+// advisory ones, and reports whether any result blocks
+func markBlocking(results []core.Result, output core.OutputConfig) bool {
+	failed := false
+	for i := range results {
+		results[i].Blocking = output.IsBlocking(results[i])
+		failed = failed || results[i].Blocking
+	}
+	return failed
+}
+
 type parsedFlags struct {
-	outputFormat             string
-	outputFile               string
-	verbose                  bool
+	outputFlags
+	notifyFlags
+	llmFlags
+	remoteFlags
+	profilingFlags
+
 	funcSizeEnabled          bool
 	funcSizeMaxLines         int
+	funcSizeMetric           string
 	fileSizeEnabled          bool
 	fileSizeMaxLines         int
+	fileSizeCountMode        string
+	typeSizeEnabled          bool
+	structMaxFields          int
+	classMaxLines            int
+	typeMaxMethods           int
+	returnsEnabled           bool
+	maxReturnValues          int
+	nakedReturnMaxLines      int
+	aiCommentsEnabled        bool
+	aiCommentPhrases         string
+	docstringsEnabled        bool
+	docstringPlaceholders    bool
+	docstringParams          bool
+	dependenciesEnabled      bool
+	importCycles             bool
+	maxImportDepth           int
+	typeSafetyEnabled        bool
+	maxAny                   int
+	maxTSSuppressions        int
+	typeHintsEnabled         bool
+	typeHintMinCoverage      float64
+	systemicEnabled          bool
+	systemicMaxFindings      int
+	systemicMaxRatio         float64
+	branchesEnabled          bool
+	concurrencyEnabled       bool
+	maxBranches              int
+	duplicateErrorsEnabled   bool
+	duplicateErrorMin        int
+	duplicateErrorThreshold  float64
+	similarityEnabled        bool
+	similarityThreshold      float64
+	similarityMinStatements  int
+	similarityMinTokens      int
+	layoutEnabled            bool
+	genericPackages          string
+	genericFiles             string
+	checkStubFiles           bool
+	checkPackageNames        bool
+	endpointsEnabled         bool
+	allowEndpoints           string
+	testQualityEnabled       bool
+	testDuplicateThreshold   float64
+	checkUntested            bool
+	componentsEnabled        bool
+	componentMaxHooks        int
+	componentMaxProps        int
+	componentMaxJSXDepth     int
+	headersEnabled           bool
+	headerMinFiles           int
+	headerThreshold          float64
+	headerTemplate           string
+	fixHeaders               bool
 	commentEnabled           bool
 	commentMaxRatio          float64
 	commentCheckRedundant    bool
+	commentRedundantThresh   float64
 	commentCheckDoc          bool
 	orphanedEnabled          bool
 	orphanedCheckUnusedFuncs bool
 	orphanedCheckUnusedVars  bool
 	orphanedCheckUnreachable bool
 	orphanedCheckDeadImports bool
+	orphanedIncludeExported  bool
+	orphanedIndexFile        string
+	orphanedIgnoreFunctions  string
+	orphanedIgnoreReceivers  string
+	orphanedMinStatements    int
 	goIgnoreTests            bool
+	goFuncMaxLines           int
+	goFileMaxLines           int
+	pythonFuncMaxLines       int
+	pythonFileMaxLines       int
+	jsFuncMaxLines           int
+	jsFileMaxLines           int
+	testDisabledRules        string
+	testFuncMaxLines         int
+	testFileMaxLines         int
+	aiPaths                  stringList
+	aiFuncMaxLines           int
+	aiFileMaxLines           int
+	staged                   bool
+	snapshot                 *stagedSnapshot // the index checked out for -staged
+	patch                    bool
+	failOn                   string
+	failOnParseErrors        bool
+	blockingRules            string
+	advisoryRules            string
+	configFile               string
+	profile                  string // applied by loadConfig, see earlyFlag
+	tolerant                 bool
+	streamThreshold          int
+	maxLineLength            int
+	module                   string
+	mapExtensions            string
+	include                  stringList
+	listFiles                bool
+	exclude                  stringList
+	external                 bool
 	showVersion              bool
 	showHelp                 bool
-	cpuProfile               string
-	memProfile               string
-	traceProfile             string
+	logLevel                 string
+	logFormat                string
 	workers                  int
+	maxMemory                string
+	memoryLimit              uint64 // maxMemory in bytes
 }
 
-func parseFlags() *parsedFlags {
+// parseFlags parses the command line. Flags default to the settings of base, the
+// configuration loaded from files, so only the flags given override it.
+func parseFlags(base core.Config) *parsedFlags {
 	f := &parsedFlags{}
 
-	flag.StringVar(&f.outputFormat, "format", "console", "Output format (console, json)")
-	flag.StringVar(&f.outputFile, "output", "", "Output file (default: stdout)")
-	flag.BoolVar(&f.verbose, "verbose", false, "Verbose output")
+	flag.StringVar(&f.configFile, "config", "", "Path to configuration file (default: agentlint.yaml in the current directory)")
+	flag.StringVar(&f.profile, "profile", base.Profile, "Rule profile applied under the configuration files: strict, default, relaxed or one defined under profiles")
+	f.outputFlags.register(base.Output)
+	f.notifyFlags.register(base.Output.Notify)
+	f.llmFlags.register(base.Output.LLM)
+	f.remoteFlags.register()
+	f.profilingFlags.register()
+
+	flag.BoolVar(&f.funcSizeEnabled, "enable-func-size", base.Rules.FunctionSize.Enabled, "Enable large function detection")
+	flag.IntVar(&f.funcSizeMaxLines, "func-max-lines", base.Rules.FunctionSize.MaxLines, "Maximum number of lines for a function")
+	flag.StringVar(&f.funcSizeMetric, "func-metric", string(base.Rules.FunctionSize.Metric), "How function size is measured: lines, statements")
+
+	flag.BoolVar(&f.fileSizeEnabled, "enable-file-size", base.Rules.FileSize.Enabled, "Enable large file detection")
+	flag.IntVar(&f.fileSizeMaxLines, "file-max-lines", base.Rules.FileSize.MaxLines, "Maximum number of lines for a file")
+	flag.StringVar(&f.fileSizeCountMode, "file-count-mode", string(base.Rules.FileSize.CountMode), "Which lines count towards file size: total, code")
+
+	flag.BoolVar(&f.typeSizeEnabled, "enable-type-size", base.Rules.TypeSize.Enabled, "Enable god struct, god class and too many methods detection")
+	flag.IntVar(&f.structMaxFields, "struct-max-fields", base.Rules.TypeSize.MaxFields, "Maximum number of fields for a struct")
+	flag.IntVar(&f.typeMaxMethods, "type-max-methods", base.Rules.TypeSize.MaxMethods, "Maximum number of methods for a type")
+	flag.IntVar(&f.classMaxLines, "class-max-lines", base.Rules.TypeSize.MaxClassLines, "Maximum number of lines for a Python class")
+
+	flag.BoolVar(&f.returnsEnabled, "enable-returns", base.Rules.Returns.Enabled, "Enable return value detection")
+	flag.IntVar(&f.maxReturnValues, "max-return-values", base.Rules.Returns.MaxValues, "Maximum number of values a function may return")
+	flag.IntVar(&f.nakedReturnMaxLines, "naked-return-max-lines", base.Rules.Returns.NakedReturnMaxLines, "Maximum function length in which naked returns are allowed")
+
+	flag.BoolVar(&f.commentEnabled, "enable-comments", base.Rules.Overcommenting.Enabled, "Enable overcommenting detection")
+	flag.Float64Var(&f.commentMaxRatio, "comment-max-ratio", base.Rules.Overcommenting.MaxCommentRatio, "Maximum comment-to-code ratio")
+	flag.BoolVar(&f.commentCheckRedundant, "check-redundant", base.Rules.Overcommenting.CheckRedundant, "Check for redundant comments")
+	flag.Float64Var(&f.commentRedundantThresh, "redundant-threshold", base.Rules.Overcommenting.RedundantThreshold, "Comment/code word overlap at which a comment is redundant (0.0 to 1.0)")
+	flag.BoolVar(&f.commentCheckDoc, "check-docs", base.Rules.Overcommenting.CheckDocCoverage, "Check for missing documentation")
+
+	flag.BoolVar(&f.aiCommentsEnabled, "enable-ai-comments", base.Rules.AIComments.Enabled, "Enable LLM comment fingerprint detection")
+	flag.StringVar(&f.aiCommentPhrases, "ai-comment-phrases", strings.Join(base.Rules.AIComments.Phrases, ","), "Comma-separated extra phrases to flag in comments")
+
+	flag.BoolVar(&f.docstringsEnabled, "enable-docstrings", base.Rules.Docstrings.Enabled, "Enable docstring quality detection (Python)")
+	flag.BoolVar(&f.docstringPlaceholders, "check-docstring-placeholders", base.Rules.Docstrings.CheckPlaceholders, "Check for template and TODO placeholder docstrings")
+	flag.BoolVar(&f.docstringParams, "check-docstring-params", base.Rules.Docstrings.CheckParameters, "Check docstring parameters against the signature")
+	flag.BoolVar(&f.typeHintsEnabled, "enable-type-hints", base.Rules.TypeHints.Enabled, "Enable type hint coverage detection (Python)")
+	flag.Float64Var(&f.typeHintMinCoverage, "type-hint-min-coverage", base.Rules.TypeHints.MinCoverage, "Share of function signatures that must be fully annotated (0.0 to 1.0)")
+	flag.BoolVar(&f.dependenciesEnabled, "enable-dependencies", base.Rules.Dependencies.Enabled, "Enable import graph analysis")
+	flag.BoolVar(&f.importCycles, "check-import-cycles", base.Rules.Dependencies.CheckCycles, "Check for circular imports")
+	flag.IntVar(&f.maxImportDepth, "max-import-depth", base.Rules.Dependencies.MaxDepth, "Maximum length of an import chain (0 disables the check)")
+
+	flag.BoolVar(&f.branchesEnabled, "enable-branches", base.Rules.Branches.Enabled, "Enable long switch and if-else chain detection")
+	flag.IntVar(&f.maxBranches, "max-branches", base.Rules.Branches.MaxBranches, "Maximum branches in one switch, match statement or if-else chain")
+
+	flag.BoolVar(&f.concurrencyEnabled, "enable-concurrency", base.Rules.Concurrency.Enabled, "Enable goroutine leak, mutex copy, defer-in-loop and sleep-based synchronization detection")
+
+	flag.BoolVar(&f.duplicateErrorsEnabled, "enable-duplicate-errors", base.Rules.DuplicateErrors.Enabled, "Enable repeated error message detection (Go)")
+	flag.IntVar(&f.duplicateErrorMin, "duplicate-error-min", base.Rules.DuplicateErrors.MinOccurrences, "Occurrences of an error message in a package before it is reported")
+	flag.Float64Var(&f.duplicateErrorThreshold, "duplicate-error-threshold", base.Rules.DuplicateErrors.Threshold, "Word similarity at which error messages are near-identical (0.0 to 1.0)")
+
+	flag.BoolVar(&f.similarityEnabled, "enable-similarity", base.Rules.Similarity.Enabled, "Enable similar function detection (Go)")
+	flag.Float64Var(&f.similarityThreshold, "similarity-threshold", base.Rules.Similarity.Threshold, "Jaccard similarity of the token shingles of two functions at which they are reported (0.0 to 1.0)")
+	flag.IntVar(&f.similarityMinStatements, "similarity-min-statements", base.Rules.Similarity.MinStatements, "Statements a function needs to be compared with others")
+	flag.IntVar(&f.similarityMinTokens, "similarity-min-tokens", base.Rules.Similarity.MinTokens, "Normalized tokens a function needs to be compared with others")
+
+	flag.BoolVar(&f.layoutEnabled, "enable-layout", base.Rules.Layout.Enabled, "Enable package and file layout detection (Go)")
+	flag.StringVar(&f.genericPackages, "generic-packages", strings.Join(base.Rules.Layout.GenericPackages, ","), "Comma-separated catch-all package names, reported when two or more packages use them")
+	flag.StringVar(&f.genericFiles, "generic-files", strings.Join(base.Rules.Layout.GenericFiles, ","), "Comma-separated file names that say nothing about their contents")
+	flag.BoolVar(&f.checkStubFiles, "check-stub-files", base.Rules.Layout.CheckStubFiles, "Check for packages with one file of code next to files that declare nothing")
+	flag.BoolVar(&f.checkPackageNames, "check-package-names", base.Rules.Layout.CheckPackageNames, "Check for packages named differently from their directory")
 
-	flag.BoolVar(&f.funcSizeEnabled, "enable-func-size", true, "Enable large function detection")
-	flag.IntVar(&f.funcSizeMaxLines, "func-max-lines", 50, "Maximum number of lines for a function")
+	flag.BoolVar(&f.endpointsEnabled, "enable-endpoints", base.Rules.Endpoints.Enabled, "Enable hardcoded URL, IP address and port detection")
+	flag.StringVar(&f.allowEndpoints, "allow-endpoints", strings.Join(base.Rules.Endpoints.Allow, ","), "Comma-separated hosts, with their subdomains, or whole endpoints that may be hardcoded")
 
-	flag.BoolVar(&f.fileSizeEnabled, "enable-file-size", true, "Enable large file detection")
-	flag.IntVar(&f.fileSizeMaxLines, "file-max-lines", 500, "Maximum number of lines for a file")
+	flag.BoolVar(&f.testQualityEnabled, "enable-test-quality", base.Rules.TestQuality.Enabled, "Enable assertion-free, duplicated and trivial test detection")
+	flag.Float64Var(&f.testDuplicateThreshold, "test-duplicate-threshold", base.Rules.TestQuality.Threshold, "Share of code two tests of the same functions have in common to be near duplicates (0.0 to 1.0)")
+	flag.BoolVar(&f.checkUntested, "check-untested", base.Rules.TestQuality.CheckUntested, "Check for exported Go functions that no test of their package refers to")
 
-	flag.BoolVar(&f.commentEnabled, "enable-comments", true, "Enable overcommenting detection")
-	flag.Float64Var(&f.commentMaxRatio, "comment-max-ratio", 0.3, "Maximum comment-to-code ratio")
-	flag.BoolVar(&f.commentCheckRedundant, "check-redundant", true, "Check for redundant comments")
-	flag.BoolVar(&f.commentCheckDoc, "check-docs", true, "Check for missing documentation")
+	flag.BoolVar(&f.componentsEnabled, "enable-components", base.Rules.Components.Enabled, "Enable React component complexity detection")
+	flag.IntVar(&f.componentMaxHooks, "component-max-hooks", base.Rules.Components.MaxHooks, "Maximum hook calls in one function component")
+	flag.IntVar(&f.componentMaxProps, "component-max-props", base.Rules.Components.MaxProps, "Maximum props destructured by one component")
+	flag.IntVar(&f.componentMaxJSXDepth, "component-max-jsx-depth", base.Rules.Components.MaxJSXDepth, "Maximum nesting depth of the JSX elements one component renders")
 
-	flag.BoolVar(&f.orphanedEnabled, "enable-orphaned", true, "Enable orphaned code detection")
-	flag.BoolVar(&f.orphanedCheckUnusedFuncs, "check-unused-funcs", true, "Check for unused functions")
-	flag.BoolVar(&f.orphanedCheckUnusedVars, "check-unused-vars", true, "Check for unused variables")
-	flag.BoolVar(&f.orphanedCheckUnreachable, "check-unreachable", true, "Check for unreachable code")
-	flag.BoolVar(&f.orphanedCheckDeadImports, "check-dead-imports", true, "Check for dead imports")
+	flag.BoolVar(&f.headersEnabled, "enable-headers", base.Rules.Headers.Enabled, "Enable drifted file header detection")
+	flag.IntVar(&f.headerMinFiles, "header-min-files", base.Rules.Headers.MinFiles, "Files that must share a header before near copies of it are reported")
+	flag.Float64Var(&f.headerThreshold, "header-threshold", base.Rules.Headers.Threshold, "Word similarity at which a header is a near copy (0.0 to 1.0)")
+	flag.StringVar(&f.headerTemplate, "header-template", base.Rules.Headers.Template, "File holding the expected header as plain text (default: the project's most common headers)")
+	flag.BoolVar(&f.fixHeaders, "fix-headers", false, "Replace drifted headers with the -header-template text")
 
-	flag.BoolVar(&f.goIgnoreTests, "ignore-tests", false, "Ignore test files during analysis")
-	flag.StringVar(&f.cpuProfile, "cpuprofile", "", "Write CPU profile to file")
-	flag.StringVar(&f.memProfile, "memprofile", "", "Write memory profile to file")
-	flag.StringVar(&f.traceProfile, "trace", "", "Write execution trace to file")
-	flag.IntVar(&f.workers, "workers", 0, "Number of worker threads (0 = auto)")
+	flag.BoolVar(&f.typeSafetyEnabled, "enable-type-safety", base.Rules.TypeSafety.Enabled, "Enable 'any' and @ts-ignore detection (TypeScript)")
+	flag.IntVar(&f.maxAny, "max-any", base.Rules.TypeSafety.MaxAny, "Maximum number of 'any' annotations and casts per file")
+	flag.IntVar(&f.maxTSSuppressions, "max-ts-suppressions", base.Rules.TypeSafety.MaxSuppressions, "Maximum number of @ts-ignore, @ts-expect-error and @ts-nocheck comments per file")
+
+	flag.BoolVar(&f.systemicEnabled, "enable-systemic", base.Rules.Systemic.Enabled, "Report pervasive smells with an additional error-level finding")
+	flag.IntVar(&f.systemicMaxFindings, "systemic-max-findings", base.Rules.Systemic.MaxFileFindings, "Findings of one rule allowed per file before the smell is systemic (0 disables the check)")
+	flag.Float64Var(&f.systemicMaxRatio, "systemic-max-ratio", base.Rules.Systemic.MaxPackageRatio, "Share of a package's functions one rule may report before the smell is systemic (0 disables the check)")
+
+	flag.BoolVar(&f.orphanedEnabled, "enable-orphaned", base.Rules.OrphanedCode.Enabled, "Enable orphaned code detection")
+	flag.BoolVar(&f.orphanedCheckUnusedFuncs, "check-unused-funcs", base.Rules.OrphanedCode.CheckUnusedFunctions, "Check for unused functions")
+	flag.BoolVar(&f.orphanedCheckUnusedVars, "check-unused-vars", base.Rules.OrphanedCode.CheckUnusedVariables, "Check for unused variables")
+	flag.BoolVar(&f.orphanedCheckUnreachable, "check-unreachable", base.Rules.OrphanedCode.CheckUnreachableCode, "Check for unreachable code")
+	flag.BoolVar(&f.orphanedCheckDeadImports, "check-dead-imports", base.Rules.OrphanedCode.CheckDeadImports, "Check for dead imports")
+	flag.BoolVar(&f.orphanedIncludeExported, "include-exported", base.Rules.OrphanedCode.IncludeExported, "Also report unused exported Go functions and types in modules nothing imports")
+	flag.StringVar(&f.orphanedIndexFile, "crossfile-index", base.Rules.OrphanedCode.IndexFile, "File keeping the project-wide Go index between runs, so only changed files are parsed again")
+	flag.StringVar(&f.orphanedIgnoreFunctions, "ignore-functions", strings.Join(base.Rules.OrphanedCode.IgnoreFunctionPatterns, ","), "Comma-separated globs or /regexp/ of Go function names never reported as unused")
+	flag.StringVar(&f.orphanedIgnoreReceivers, "ignore-receivers", strings.Join(base.Rules.OrphanedCode.IgnoreReceivers, ","), "Comma-separated globs or /regexp/ of receiver types whose methods are never reported as unused")
+	flag.IntVar(&f.orphanedMinStatements, "unused-min-statements", base.Rules.OrphanedCode.MinStatements, "Statements a Go function needs to be reported as unused (0 = any size)")
+
+	flag.BoolVar(&f.goIgnoreTests, "ignore-tests", base.Language.Go.IgnoreTests, "Ignore test files during analysis")
+	flag.StringVar(&f.module, "module", "", "Analyze only the Go module with this module path or directory")
+
+	flag.IntVar(&f.goFuncMaxLines, "go-func-max-lines", base.Language.Go.Rules.FunctionSize.MaxLines, "Maximum function size for Go files (0 = use -func-max-lines)")
+	flag.IntVar(&f.goFileMaxLines, "go-file-max-lines", base.Language.Go.Rules.FileSize.MaxLines, "Maximum file size for Go files (0 = use -file-max-lines)")
+	flag.IntVar(&f.pythonFuncMaxLines, "python-func-max-lines", base.Language.Python.Rules.FunctionSize.MaxLines, "Maximum function size for Python files (0 = use -func-max-lines)")
+	flag.IntVar(&f.pythonFileMaxLines, "python-file-max-lines", base.Language.Python.Rules.FileSize.MaxLines, "Maximum file size for Python files (0 = use -file-max-lines)")
+	flag.IntVar(&f.jsFuncMaxLines, "js-func-max-lines", base.Language.ReactNative.Rules.FunctionSize.MaxLines, "Maximum function size for JavaScript/TypeScript files (0 = use -func-max-lines)")
+	flag.IntVar(&f.jsFileMaxLines, "js-file-max-lines", base.Language.ReactNative.Rules.FileSize.MaxLines, "Maximum file size for JavaScript/TypeScript files (0 = use -file-max-lines)")
+	flag.StringVar(&f.mapExtensions, "map-extensions", joinExtensions(base.Language.Extensions), "Comma-separated ext=language pairs routing extensions to an analyzer, e.g. .mjs=javascript,.pyi=python")
+	flag.Var(&f.include, "include", "Analyze only files matching this glob, e.g. 'src/**/*.ts' (repeatable)")
+	flag.Var(&f.exclude, "exclude", "Skip files and directories matching this glob, e.g. '**/generated/**' (repeatable)")
+	flag.BoolVar(&f.external, "external", base.Files.External, "Also analyze vendor directories, reporting findings in vendored and third-party code separately as external")
+	flag.BoolVar(&f.listFiles, "list-files", false, "Print the files that would be analyzed, by language, and the skipped paths, without analyzing them")
+
+	flag.StringVar(&f.testDisabledRules, "test-disable-rules", strings.Join(base.TestFiles.DisabledRules, ","), "Comma-separated rule IDs to skip in test files")
+	flag.IntVar(&f.testFuncMaxLines, "test-func-max-lines", base.TestFiles.Rules.FunctionSize.MaxLines, "Maximum function size in test files (0 = use the language limit)")
+	flag.IntVar(&f.testFileMaxLines, "test-file-max-lines", base.TestFiles.Rules.FileSize.MaxLines, "Maximum file size in test files (0 = use the language limit)")
+	flag.Var(&f.aiPaths, "ai-paths", "Treat files matching this glob, relative to the working directory, as AI-generated (repeatable)")
+	flag.IntVar(&f.aiFuncMaxLines, "ai-func-max-lines", base.AIFiles.Rules.FunctionSize.MaxLines, "Maximum function size in AI-generated files (0 = use the language limit)")
+	flag.IntVar(&f.aiFileMaxLines, "ai-file-max-lines", base.AIFiles.Rules.FileSize.MaxLines, "Maximum file size in AI-generated files (0 = use the language limit)")
+	flag.BoolVar(&f.staged, "staged", false, "Analyze the staged contents of files staged in the git index")
+	flag.BoolVar(&f.patch, "patch", false, "Read a unified diff from stdin and report only the findings on the lines it adds or changes")
+	flag.StringVar(&f.failOn, "fail-on", base.Output.FailOn, "Minimum severity that causes a non-zero exit (error, warning, info, none)")
+	flag.BoolVar(&f.failOnParseErrors, "fail-on-parse-errors", base.Output.FailOnParseErrors, "Exit non-zero when a file cannot be parsed or analyzed")
+	flag.StringVar(&f.blockingRules, "blocking-rules", blockingList(base.Output.Blocking, true), "Comma-separated rule IDs that always cause a non-zero exit, whatever -fail-on says")
+	flag.StringVar(&f.advisoryRules, "advisory-rules", blockingList(base.Output.Blocking, false), "Comma-separated rule IDs that are reported but never cause a non-zero exit")
+	flag.BoolVar(&f.tolerant, "tolerant", base.Parsing.Tolerant, "Run size and comment checks on files with syntax errors instead of skipping them")
+	flag.IntVar(&f.streamThreshold, "stream-threshold", base.Parsing.StreamThreshold, "Size in bytes above which Python and JS/TS files are analyzed in one streaming pass (0 = never)")
+	flag.IntVar(&f.maxLineLength, "max-line-length", base.Parsing.MaxLineLength, "Length in bytes above which Python and JS/TS lines are truncated and JS/TS files skipped as minified (0 = never)")
+	flag.IntVar(&f.workers, "workers", 0, "Number of files analyzed in parallel (0 = one per CPU)")
+	flag.StringVar(&f.maxMemory, "max-memory", "", "Heap size to keep the analysis under, e.g. 512MB or 2GB; fewer files are analyzed at once near it (default: no limit)")
+	flag.StringVar(&f.logLevel, "log-level", "info", "Minimum level of diagnostics written to stderr (debug, info, warn, error)")
+	flag.StringVar(&f.logFormat, "log-format", "text", "Format of diagnostics written to stderr (text, json)")
 	flag.BoolVar(&f.showVersion, "version", false, "Show version information")
 	flag.BoolVar(&f.showHelp, "help", false, "Show help information")
 
@@ -188,48 +831,300 @@ func parseFlags() *parsedFlags {
 	return f
 }
 
-func buildConfig(f *parsedFlags) core.Config {
+// buildConfig builds the configuration of the run from the flags, which default to the
+// settings of base. Settings without a flag, such as the per-language metrics, come from base.
+func buildConfig(f *parsedFlags, base core.Config) core.Config {
 	return core.Config{
 		Rules: core.RulesConfig{
 			FunctionSize: core.FunctionSizeConfig{
 				Enabled:  f.funcSizeEnabled,
 				MaxLines: f.funcSizeMaxLines,
+				Metric:   core.FunctionSizeMetric(f.funcSizeMetric),
 			},
 			FileSize: core.FileSizeConfig{
-				Enabled:  f.fileSizeEnabled,
-				MaxLines: f.fileSizeMaxLines,
+				Enabled:   f.fileSizeEnabled,
+				MaxLines:  f.fileSizeMaxLines,
+				CountMode: core.FileSizeCountMode(f.fileSizeCountMode),
 			},
 			Overcommenting: core.OvercommentingConfig{
-				Enabled:          f.commentEnabled,
-				MaxCommentRatio:  f.commentMaxRatio,
-				CheckRedundant:   f.commentCheckRedundant,
-				CheckDocCoverage: f.commentCheckDoc,
+				Enabled:            f.commentEnabled,
+				MaxCommentRatio:    f.commentMaxRatio,
+				CheckRedundant:     f.commentCheckRedundant,
+				RedundantThreshold: f.commentRedundantThresh,
+				CheckDocCoverage:   f.commentCheckDoc,
 			},
 			OrphanedCode: core.OrphanedCodeConfig{
-				Enabled:              f.orphanedEnabled,
-				CheckUnusedFunctions: f.orphanedCheckUnusedFuncs,
-				CheckUnusedVariables: f.orphanedCheckUnusedVars,
-				CheckUnreachableCode: f.orphanedCheckUnreachable,
-				CheckDeadImports:     f.orphanedCheckDeadImports,
+				Enabled:                f.orphanedEnabled,
+				CheckUnusedFunctions:   f.orphanedCheckUnusedFuncs,
+				CheckUnusedVariables:   f.orphanedCheckUnusedVars,
+				CheckUnreachableCode:   f.orphanedCheckUnreachable,
+				CheckDeadImports:       f.orphanedCheckDeadImports,
+				IncludeExported:        f.orphanedIncludeExported,
+				IndexFile:              f.orphanedIndexFile,
+				IgnoreFunctionPatterns: splitList(f.orphanedIgnoreFunctions),
+				IgnoreReceivers:        splitList(f.orphanedIgnoreReceivers),
+				MinStatements:          f.orphanedMinStatements,
+			},
+			TypeSize: core.TypeSizeConfig{
+				Enabled:       f.typeSizeEnabled,
+				MaxFields:     f.structMaxFields,
+				MaxMethods:    f.typeMaxMethods,
+				MaxClassLines: f.classMaxLines,
+			},
+			Returns: core.ReturnsConfig{
+				Enabled:             f.returnsEnabled,
+				MaxValues:           f.maxReturnValues,
+				NakedReturnMaxLines: f.nakedReturnMaxLines,
+			},
+			AIComments: core.AICommentsConfig{
+				Enabled: f.aiCommentsEnabled,
+				Phrases: splitList(f.aiCommentPhrases),
+			},
+			Docstrings: core.DocstringsConfig{
+				Enabled:           f.docstringsEnabled,
+				CheckPlaceholders: f.docstringPlaceholders,
+				CheckParameters:   f.docstringParams,
+			},
+			Dependencies: core.DependenciesConfig{
+				Enabled:     f.dependenciesEnabled,
+				CheckCycles: f.importCycles,
+				MaxDepth:    f.maxImportDepth,
+			},
+			TypeSafety: core.TypeSafetyConfig{
+				Enabled:         f.typeSafetyEnabled,
+				MaxAny:          f.maxAny,
+				MaxSuppressions: f.maxTSSuppressions,
+			},
+			TypeHints: core.TypeHintsConfig{
+				Enabled:     f.typeHintsEnabled,
+				MinCoverage: f.typeHintMinCoverage,
+			},
+			Systemic: core.SystemicConfig{
+				Enabled:         f.systemicEnabled,
+				MaxFileFindings: f.systemicMaxFindings,
+				MaxPackageRatio: f.systemicMaxRatio,
+			},
+			Branches: core.BranchesConfig{
+				Enabled:     f.branchesEnabled,
+				MaxBranches: f.maxBranches,
+			},
+			Concurrency: core.ConcurrencyConfig{
+				Enabled: f.concurrencyEnabled,
+			},
+			DuplicateErrors: core.DuplicateErrorsConfig{
+				Enabled:        f.duplicateErrorsEnabled,
+				MinOccurrences: f.duplicateErrorMin,
+				Threshold:      f.duplicateErrorThreshold,
+			},
+			Similarity: core.SimilarityConfig{
+				Enabled:       f.similarityEnabled,
+				Threshold:     f.similarityThreshold,
+				MinStatements: f.similarityMinStatements,
+				MinTokens:     f.similarityMinTokens,
+			},
+			Layout: core.LayoutConfig{
+				Enabled:           f.layoutEnabled,
+				GenericPackages:   splitList(f.genericPackages),
+				GenericFiles:      splitList(f.genericFiles),
+				CheckStubFiles:    f.checkStubFiles,
+				CheckPackageNames: f.checkPackageNames,
+			},
+			Endpoints: core.EndpointsConfig{
+				Enabled: f.endpointsEnabled,
+				Allow:   splitList(f.allowEndpoints),
+			},
+			TestQuality: core.TestQualityConfig{
+				Enabled:       f.testQualityEnabled,
+				Threshold:     f.testDuplicateThreshold,
+				CheckUntested: f.checkUntested,
+			},
+			Components: core.ComponentsConfig{
+				Enabled:     f.componentsEnabled,
+				MaxHooks:    f.componentMaxHooks,
+				MaxProps:    f.componentMaxProps,
+				MaxJSXDepth: f.componentMaxJSXDepth,
+			},
+			Headers: core.HeadersConfig{
+				Enabled:   f.headersEnabled,
+				MinFiles:  f.headerMinFiles,
+				Threshold: f.headerThreshold,
+				Template:  f.headerTemplate,
 			},
 		},
-		Output: core.OutputConfig{
-			Format:  f.outputFormat,
-			Verbose: f.verbose,
-		},
+		Output: f.outputConfig(base.Output),
 		Language: core.LanguageConfig{
 			Go: core.GoConfig{
 				IgnoreTests: f.goIgnoreTests,
+				Rules:       base.Language.Go.Rules.Merge(sizeOverrides(f.goFuncMaxLines, f.goFileMaxLines)),
+			},
+			Python: core.PythonConfig{
+				IgnoreTests: base.Language.Python.IgnoreTests,
+				Rules:       base.Language.Python.Rules.Merge(sizeOverrides(f.pythonFuncMaxLines, f.pythonFileMaxLines)),
 			},
+			ReactNative: core.ReactNativeConfig{
+				IgnoreTests: base.Language.ReactNative.IgnoreTests,
+				Rules:       base.Language.ReactNative.Rules.Merge(sizeOverrides(f.jsFuncMaxLines, f.jsFileMaxLines)),
+			},
+			Extensions: extensionMap(f.mapExtensions),
+		},
+		TestFiles: core.TestFilesConfig{
+			DisabledRules: splitList(f.testDisabledRules),
+			Rules:         base.TestFiles.Rules.Merge(sizeOverrides(f.testFuncMaxLines, f.testFileMaxLines)),
+		},
+		AIFiles: core.AIFilesConfig{
+			Markers: base.AIFiles.Markers,
+			Paths:   globsOr(f.aiPaths, base.AIFiles.Paths),
+			Rules:   base.AIFiles.Rules.Merge(sizeOverrides(f.aiFuncMaxLines, f.aiFileMaxLines)),
+		},
+		Parsing: core.ParsingConfig{
+			Tolerant:        f.tolerant,
+			StreamThreshold: f.streamThreshold,
+			MaxLineLength:   f.maxLineLength,
+		},
+		Files: core.FilesConfig{
+			Include:  globsOr(f.include, base.Files.Include),
+			Exclude:  globsOr(f.exclude, base.Files.Exclude),
+			External: f.external,
 		},
 	}
 }
 
-func setupAnalyzer(cfg core.Config) *languages.Registry {
+// outputConfig combines the output flags with those of the exit status, the notification and
+// the LLM suggestions, which are output settings too
+func (f *parsedFlags) outputConfig(base core.OutputConfig) core.OutputConfig {
+	out := f.outputFlags.config(base)
+	out.FailOn = f.failOn
+	out.FailOnParseErrors = f.failOnParseErrors
+	out.Blocking = blockingOverrides(f.blockingRules, f.advisoryRules)
+	out.Notify = f.notifyFlags.config()
+	out.LLM = f.llmFlags.config(base.LLM)
+	return out
+}
+
+// stringList is a flag that may be given several times, collecting every value
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// sizeOverrides builds a language's size threshold overrides from its flags
+func sizeOverrides(funcMaxLines, fileMaxLines int) core.LanguageRulesConfig {
+	return core.LanguageRulesConfig{
+		FunctionSize: core.FunctionSizeOverride{MaxLines: funcMaxLines},
+		FileSize:     core.FileSizeOverride{MaxLines: fileMaxLines},
+	}
+}
+
+// blockingOverrides builds the per-rule blocking map from the -blocking-rules and
+// -advisory-rules lists; a rule in both lists is advisory
+func blockingOverrides(blockingRules, advisoryRules string) map[string]bool {
+	overrides := make(map[string]bool)
+	for _, id := range splitList(blockingRules) {
+		overrides[id] = true
+	}
+	for _, id := range splitList(advisoryRules) {
+		overrides[id] = false
+	}
+	if len(overrides) == 0 {
+		return nil
+	}
+	return overrides
+}
+
+// blockingList returns the rules of a blocking map set to blocking, sorted, as the default of
+// -blocking-rules or -advisory-rules
+func blockingList(overrides map[string]bool, blocking bool) string {
+	var ids []string
+	for id, value := range overrides {
+		if value == blocking {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+// joinExtensions formats an extension map as the ext=language pairs of -map-extensions
+func joinExtensions(extensions map[string]string) string {
+	pairs := make([]string, 0, len(extensions))
+	for ext, language := range extensions {
+		pairs = append(pairs, ext+"="+language)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// globsOr returns the globs given on the command line, or those of the configuration when
+// there are none
+func globsOr(flagGlobs stringList, configGlobs []string) []string {
+	if len(flagGlobs) > 0 {
+		return flagGlobs
+	}
+	return configGlobs
+}
+
+// extensionMap parses the -map-extensions list of ext=language pairs; a pair without a
+// language maps to "", which SetExtensionMap rejects
+func extensionMap(value string) map[string]string {
+	items := splitList(value)
+	if len(items) == 0 {
+		return nil
+	}
+	extensions := make(map[string]string, len(items))
+	for _, item := range items {
+		ext, language, _ := strings.Cut(item, "=")
+		extensions[strings.TrimSpace(ext)] = strings.TrimSpace(language)
+	}
+	return extensions
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// logCacheStats logs at debug level how often the parse caches answered a lookup
+func logCacheStats(registry *languages.Registry, astCache *golang.ASTCache) {
+	if stats := astCache.Stats(); stats.Hits+stats.Misses > 0 {
+		slog.Debug("go parse cache", "hits", stats.Hits, "misses", stats.Misses, "hit_rate", stats.HitRate(),
+			"evictions", stats.Evictions, "entries", stats.Entries, "fileset_bytes", stats.FileSetSize)
+	}
+	analyzers := registry.GetAllAnalyzers()
+	names := make([]string, 0, len(analyzers))
+	for name := range analyzers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		reporter, ok := analyzers[name].(languages.CacheReporter)
+		if !ok {
+			continue
+		}
+		if stats := reporter.CacheStats(); stats.Hits+stats.Misses > 0 {
+			slog.Debug(name+" parse cache", "hits", stats.Hits, "misses", stats.Misses, "hit_rate", stats.HitRate(),
+				"evictions", stats.Evictions, "entries", stats.Entries)
+		}
+	}
+}
+
+func setupAnalyzer(cfg core.Config, astCache *golang.ASTCache) *languages.Registry {
 	registry := languages.NewRegistry()
 
 	// Register Go analyzer
 	goAnalyzer := golang.NewAnalyzer(cfg)
+	goAnalyzer.SetCache(astCache)
 	registry.Register(goAnalyzer)
 
 	// Register Python analyzer
@@ -243,156 +1138,120 @@ func setupAnalyzer(cfg core.Config) *languages.Registry {
 	return registry
 }
 
+// collectFiles returns the files to analyze grouped by language, taken from the git index
+// (-staged, as checked out into flags.snapshot), from explicit file arguments (as passed by the pre-commit framework), or by
+// scanning the target directory
+func collectFiles(ctx context.Context, flags *parsedFlags, scanner *languages.MultiScanner) (map[string][]string, error) {
+	if flags.staged {
+		return scanner.GroupFilesUnder(flags.snapshot.workDir, flags.snapshot.files), nil
+	}
+
+	if files := fileArgs(); len(files) > 0 {
+		return scanner.GroupFiles(files), nil
+	}
+
+	return scanFiles(ctx, resolvePath(), scanner)
+}
+
+// fileArgs returns the positional arguments as absolute paths when every one names a regular
+// file, see checkPathArgs
+func fileArgs() []string {
+	var files []string
+	for _, arg := range flag.Args() {
+		info, err := os.Stat(arg)
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+		absPath, err := filepath.Abs(arg)
+		if err != nil {
+			return nil
+		}
+		files = append(files, absPath)
+	}
+	return files
+}
+
+// checkPathArgs checks that the positional arguments are either directories or regular files,
+// as the pre-commit framework passes them, so that a list of files naming something else is
+// not taken for a directory to scan
+func checkPathArgs(args []string) error {
+	files, dirs := 0, 0
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		switch {
+		case err != nil:
+			return fmt.Errorf("%s: %w", arg, err)
+		case info.IsDir():
+			dirs++
+		case info.Mode().IsRegular():
+			files++
+		default:
+			return fmt.Errorf("%s is neither a regular file nor a directory", arg)
+		}
+	}
+	if files > 0 && dirs > 0 {
+		return fmt.Errorf("paths must all be files or all be directories")
+	}
+	return nil
+}
+
 func scanFiles(ctx context.Context, absPath string, scanner *languages.MultiScanner) (map[string][]string, error) {
-	fmt.Printf("Scanning %s...\n", absPath)
+	slog.Info("scanning", "path", absPath)
 	return scanner.Scan(ctx, absPath)
 }
 
-func analyzeFiles(ctx context.Context, filesByLanguage map[string][]string, registry *languages.Registry, cfg core.Config) []core.Result {
-	var allResults []core.Result
+// analyzeFiles runs the analyzers over the files on the engine's worker pool, shared by every
+// language, and returns their findings along with the files that could not be analyzed
+func analyzeFiles(ctx context.Context, filesByLanguage map[string][]string, registry *languages.Registry, cfg core.Config, engine *core.Engine) ([]core.Result, []core.FileError) {
+	languageNames := make([]string, 0, len(filesByLanguage))
+	for language := range filesByLanguage {
+		languageNames = append(languageNames, language)
+	}
+	sort.Strings(languageNames)
 
-	for language, files := range filesByLanguage {
+	var jobs []core.AnalysisJob
+	for _, language := range languageNames {
 		analyzer, exists := registry.GetAnalyzer(language)
 		if !exists {
 			continue
 		}
+		files := filesByLanguage[language]
+		slog.Info("analyzing", "language", language, "files", len(files))
+		for _, file := range files {
+			jobs = append(jobs, core.AnalysisJob{Analyzer: analyzer, FilePath: file})
+		}
+	}
 
-		fmt.Printf("Analyzing %d %s files...\n", len(files), language)
+	allResults, fileErrors := engine.Run(ctx, jobs, cfg)
+	for _, fileErr := range fileErrors {
+		slog.Debug("analyzing file failed", "file", fileErr.FilePath, "error", fileErr.Message)
+	}
+	return allResults, fileErrors
+}
 
-		if language == "go" && len(files) > 1 {
-			parallelAnalyzer := golang.NewParallelAnalyzer(cfg, 0)
-			results := parallelAnalyzer.AnalyzeFiles(ctx, files, cfg)
-			allResults = append(allResults, results...)
-		} else {
+// functionCounter returns a function counting the functions of the analyzed files in a
+// directory, for core.Escalate. Files are counted only for the directories asked about.
+func functionCounter(ctx context.Context, filesByLanguage map[string][]string, registry *languages.Registry) func(dir string) int {
+	return func(dir string) int {
+		count := 0
+		for language, files := range filesByLanguage {
+			analyzer, exists := registry.GetAnalyzer(language)
+			counter, ok := analyzer.(core.FunctionCounter)
+			if !exists || !ok {
+				continue
+			}
 			for _, file := range files {
-				results, err := analyzer.Analyze(ctx, file, cfg)
+				if filepath.Dir(file) != dir {
+					continue
+				}
+				n, err := counter.CountFunctions(ctx, file)
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error analyzing file %s: %v\n", file, err)
+					slog.Debug("counting functions failed", "file", file, "error", err)
 					continue
 				}
-				allResults = append(allResults, results...)
+				count += n
 			}
 		}
+		return count
 	}
-
-	return allResults
-}
-
-func outputResults(cfg core.Config, allResults []core.Result) {
-	var formatter output.Formatter
-	switch cfg.Output.Format {
-	case "json":
-		formatter = output.NewJSONFormatter(cfg.Output.Verbose)
-	case "console":
-		fallthrough
-	default:
-		formatter = output.NewConsoleFormatter(cfg.Output.Verbose)
-	}
-
-	var outputFileHandle *os.File
-	if cfg.Output.Format == "json" && cfg.Output.Format != "console" {
-		var err error
-		outputFileHandle, err = os.Create(cfg.Output.Format)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
-			os.Exit(1)
-		}
-		defer outputFileHandle.Close()
-		os.Stdout = outputFileHandle
-	}
-
-	formatter.PrintHeader()
-	if err := formatter.Format(allResults); err != nil {
-		formatter.FormatError(err)
-		os.Exit(1)
-	}
-	formatter.PrintFooter()
-}
-
-func showHelp() {
-	fmt.Println("AgentLint - A linter for detecting LLM code bad smells")
-	fmt.Println()
-	fmt.Println("Usage:")
-	fmt.Println("  agentlint [flags] [path]")
-	fmt.Println()
-	printOutputOptions()
-	printFunctionSizeOptions()
-	printFileSizeOptions()
-	printCommentOptions()
-	printOrphanedOptions()
-	printGoOptions()
-	printPerformanceOptions()
-	printGeneralOptions()
-	printExamples()
-}
-
-func printOutputOptions() {
-	fmt.Println("Output Options:")
-	fmt.Println("  -format string       Output format (console, json) (default \"console\")")
-	fmt.Println("  -output string       Output file (default: stdout)")
-	fmt.Println("  -verbose             Verbose output")
-	fmt.Println()
-}
-
-func printFunctionSizeOptions() {
-	fmt.Println("Function Size Rules:")
-	fmt.Println("  -enable-func-size    Enable large function detection (default true)")
-	fmt.Println("  -func-max-lines      Maximum number of lines for a function (default 50)")
-	fmt.Println()
-}
-
-func printFileSizeOptions() {
-	fmt.Println("File Size Rules:")
-	fmt.Println("  -enable-file-size    Enable large file detection (default true)")
-	fmt.Println("  -file-max-lines      Maximum number of lines for a file (default 500)")
-	fmt.Println()
-}
-
-func printCommentOptions() {
-	fmt.Println("Comment Rules:")
-	fmt.Println("  -enable-comments     Enable overcommenting detection (default true)")
-	fmt.Println("  -comment-max-ratio   Maximum comment-to-code ratio (default 0.3)")
-	fmt.Println("  -check-redundant     Check for redundant comments (default true)")
-	fmt.Println("  -check-docs          Check for missing documentation (default true)")
-	fmt.Println()
-}
-
-func printOrphanedOptions() {
-	fmt.Println("Orphaned Code Rules:")
-	fmt.Println("  -enable-orphaned    Enable orphaned code detection (default true)")
-	fmt.Println("  -check-unused-funcs  Check for unused functions (default true)")
-	fmt.Println("  -check-unused-vars   Check for unused variables (default true)")
-	fmt.Println("  -check-unreachable   Check for unreachable code (default true)")
-	fmt.Println("  -check-dead-imports  Check for dead imports (default true)")
-	fmt.Println()
-}
-
-func printGoOptions() {
-	fmt.Println("Go-specific Options:")
-	fmt.Println("  -ignore-tests        Ignore test files during analysis (default false)")
-	fmt.Println()
-}
-
-func printPerformanceOptions() {
-	fmt.Println("Performance Options:")
-	fmt.Println("  -cpuprofile string   Write CPU profile to file")
-	fmt.Println("  -memprofile string   Write memory profile to file")
-	fmt.Println("  -trace string        Write execution trace to file")
-	fmt.Println("  -workers int         Number of worker threads (0 = auto)")
-	fmt.Println()
-}
-
-func printGeneralOptions() {
-	fmt.Println("General Options:")
-	fmt.Println("  -version             Show version information")
-	fmt.Println("  -help                Show help information")
-	fmt.Println()
-}
-
-func printExamples() {
-	fmt.Println("Examples:")
-	fmt.Println("  agentlint ./myproject")
-	fmt.Println("  agentlint -format json -output report.json ./myproject")
-	fmt.Println("  agentlint -func-max-lines 30 -file-max-lines 200 ./myproject")
-	fmt.Println("  agentlint -enable-comments=false -check-unused-funcs=false ./myproject")
 }
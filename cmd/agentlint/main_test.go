@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+var (
+	buildOnce sync.Once
+	binary    string
+	buildErr  error
+
+	// packageDir is the directory of the command's sources, which tests may change out of
+	packageDir, _ = os.Getwd()
+)
+
+// TestMain removes the binary the tests built, see agentlintBinary
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if binary != "" {
+		os.RemoveAll(filepath.Dir(binary))
+	}
+	os.Exit(code)
+}
+
+// agentlintBinary builds the agentlint command once for the tests that run it end to end,
+// since main reads the process flags and exits with the result
+func agentlintBinary(t *testing.T) string {
+	t.Helper()
+	buildOnce.Do(func() {
+		var dir string
+		if dir, buildErr = os.MkdirTemp("", "agentlint-test-"); buildErr != nil {
+			return
+		}
+		binary = filepath.Join(dir, "agentlint")
+		build := exec.Command("go", "build", "-o", binary, ".")
+		build.Dir = packageDir
+		if out, err := build.CombinedOutput(); err != nil {
+			buildErr = errors.New(string(out))
+		}
+	})
+	if buildErr != nil {
+		t.Fatalf("building agentlint failed: %v", buildErr)
+	}
+	return binary
+}
+
+// runAgentlint runs agentlint with args in dir and returns its standard output and exit code
+func runAgentlint(t *testing.T, dir string, args ...string) ([]byte, int) {
+	t.Helper()
+	cmd := exec.Command(agentlintBinary(t), args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		t.Fatalf("running agentlint failed: %v", err)
+	}
+	if code := cmd.ProcessState.ExitCode(); code == 2 {
+		t.Fatalf("agentlint %v failed: %s", args, stderr.String())
+	}
+	return stdout.Bytes(), cmd.ProcessState.ExitCode()
+}
+
+// jsonReport is the part of the JSON output the tests read
+type jsonReport struct {
+	Results []core.Result              `json:"results"`
+	Roots   map[string]json.RawMessage `json:"roots"`
+}
+
+// runJSON runs agentlint with JSON output and decodes the report
+func runJSON(t *testing.T, dir string, args ...string) (jsonReport, int) {
+	t.Helper()
+	out, code := runAgentlint(t, dir, append([]string{"-format", "json"}, args...)...)
+	var report jsonReport
+	if err := json.Unmarshal(out, &report); err != nil {
+		t.Fatalf("decoding the JSON output failed: %v\n%s", err, out)
+	}
+	return report, code
+}
+
+func TestBlockingOverrides(t *testing.T) {
+	tests := []struct {
+		name               string
+		blocking, advisory string
+		want               map[string]bool
+	}{
+		{"none", "", "", nil},
+		{"blocking", "unused-function", "", map[string]bool{"unused-function": true}},
+		{"advisory", "", "print-debug, console-log", map[string]bool{"print-debug": false, "console-log": false}},
+		{"both lists", "print-debug,unused-function", "print-debug", map[string]bool{"print-debug": false, "unused-function": true}},
+		{"empty entries", " ,unused-function,", ",", map[string]bool{"unused-function": true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := blockingOverrides(tt.blocking, tt.advisory); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMarkBlocking(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   core.OutputConfig
+		result   core.Result
+		blocking bool
+	}{
+		{"meets fail-on", core.OutputConfig{FailOn: "warning"}, core.Result{RuleID: "large-function", Severity: "warning"}, true},
+		{"below fail-on", core.OutputConfig{FailOn: "warning"}, core.Result{RuleID: "print-debug", Severity: "info"}, false},
+		{"fail-on none", core.OutputConfig{FailOn: "none"}, core.Result{RuleID: "large-function", Severity: "error"}, false},
+		{"blocking rule", core.OutputConfig{FailOn: "none", Blocking: map[string]bool{"print-debug": true}}, core.Result{RuleID: "print-debug", Severity: "info"}, true},
+		{"advisory rule", core.OutputConfig{FailOn: "info", Blocking: map[string]bool{"large-function": false}}, core.Result{RuleID: "large-function", Severity: "error"}, false},
+		{"external", core.OutputConfig{FailOn: "info", Blocking: map[string]bool{"large-function": true}}, core.Result{RuleID: "large-function", Severity: "error", External: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := []core.Result{tt.result}
+			failed := markBlocking(results, tt.output)
+			if results[0].Blocking != tt.blocking || failed != tt.blocking {
+				t.Errorf("Expected blocking %v, got %v (run failed: %v)", tt.blocking, results[0].Blocking, failed)
+			}
+		})
+	}
+
+	results := []core.Result{{RuleID: "print-debug", Severity: "info"}, {RuleID: "large-function", Severity: "warning"}}
+	if !markBlocking(results, core.OutputConfig{FailOn: "warning"}) || results[0].Blocking || !results[1].Blocking {
+		t.Errorf("Expected only the warning to block and the run to fail, got %+v", results)
+	}
+}
+
+func TestBlockingRules_ExitCode(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\nfunc main() {\n\t_ = 1\n\t_ = 2\n\t_ = 3\n}\n")
+	base := []string{"-func-max-lines", "3", "-only-rules", "large-function"}
+
+	tests := []struct {
+		name     string
+		args     []string
+		code     int
+		blocking bool
+	}{
+		{"fail-on", []string{"-fail-on", "warning"}, 1, true},
+		{"advisory rule", []string{"-fail-on", "warning", "-advisory-rules", "large-function"}, 0, false},
+		{"blocking rule", []string{"-fail-on", "none", "-blocking-rules", "large-function"}, 1, true},
+		{"in both lists", []string{"-fail-on", "none", "-blocking-rules", "large-function", "-advisory-rules", "large-function"}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, code := runAgentlint(t, dir, append(append([]string{"-format", "json"}, base...), append(tt.args, ".")...)...)
+			if code != tt.code {
+				t.Errorf("Expected exit code %d, got %d", tt.code, code)
+			}
+			var report struct {
+				Results []map[string]interface{} `json:"results"`
+				Summary map[string]interface{}   `json:"summary"`
+			}
+			if err := json.Unmarshal(out, &report); err != nil {
+				t.Fatalf("decoding the JSON output failed: %v\n%s", err, out)
+			}
+			if len(report.Results) != 1 || report.Results[0]["blocking"] != tt.blocking {
+				t.Fatalf("Expected one result with \"blocking\": %v, got %v", tt.blocking, report.Results)
+			}
+			wantCount := 0.0
+			if tt.blocking {
+				wantCount = 1
+			}
+			if report.Summary["blocking_count"] != wantCount {
+				t.Errorf("Expected blocking_count %v, got %v", wantCount, report.Summary["blocking_count"])
+			}
+		})
+	}
+}
+
+func TestMultiRoot(t *testing.T) {
+	dir := t.TempDir()
+	for _, root := range []string{"a", "b"} {
+		writeFile(t, filepath.Join(dir, root, "go.mod"), "module example.com/"+root+"\n\ngo 1.21\n")
+		writeFile(t, filepath.Join(dir, root, "util.go"), "package main\n\nfunc helper() int {\n\tx := 1\n\ty := 2\n\treturn x + y\n}\n")
+	}
+	// only a calls helper, so the call must not count for b's copy
+	writeFile(t, filepath.Join(dir, "a", "main.go"), "package main\n\nfunc main() {\n\thelper()\n}\n")
+	writeFile(t, filepath.Join(dir, "b", "main.go"), "package main\n\nfunc main() {\n}\n")
+
+	report, code := runJSON(t, dir, "-func-max-lines", "3", "-only-rules", "large-function,cross-file-unused-function", "a", "b")
+	if code != 1 {
+		t.Errorf("Expected exit code 1, got %d", code)
+	}
+
+	found := make(map[string]string)
+	fingerprints := make(map[string]string)
+	for _, result := range report.Results {
+		key := result.Root + ":" + result.RuleID
+		found[key] = filepath.Base(result.FilePath)
+		if other, seen := fingerprints[result.Fingerprint]; seen {
+			t.Errorf("Expected distinct fingerprints, %s and %s share %s", other, key, result.Fingerprint)
+		}
+		fingerprints[result.Fingerprint] = key
+	}
+	want := map[string]string{"a:large-function": "util.go", "b:large-function": "util.go", "b:cross-file-unused-function": "util.go"}
+	if !reflect.DeepEqual(found, want) {
+		t.Errorf("Expected findings %v, got %v", want, found)
+	}
+
+	for root, total := range map[string]int{"a": 1, "b": 2} {
+		var summary struct {
+			TotalIssues int `json:"total_issues"`
+			FileCount   int `json:"file_count"`
+		}
+		if err := json.Unmarshal(report.Roots[root], &summary); err != nil {
+			t.Fatalf("Expected a summary for root %s, got %s", root, report.Roots[root])
+		}
+		if summary.TotalIssues != total || summary.FileCount != 1 {
+			t.Errorf("Expected %d issues in 1 file under %s, got %+v", total, root, summary)
+		}
+	}
+	if len(report.Roots) != 2 {
+		t.Errorf("Expected a summary per root, got %v", report.Roots)
+	}
+}
+
+func TestLLMAPIKey(t *testing.T) {
+	var mu sync.Mutex
+	var authorization []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		authorization = append(authorization, r.Header.Get("Authorization"))
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"Split it."}}]}`))
+	}))
+	defer server.Close()
+	t.Setenv("AGENTLINT_LLM_API_KEY", "key")
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\nfunc main() {\n\t_ = 1\n\t_ = 2\n\t_ = 3\n}\n")
+	writeFile(t, filepath.Join(dir, "agentlint.yaml"), "output:\n  llm:\n    endpoint: "+server.URL+"\n    model: local\n")
+	base := []string{"-fail-on", "none", "-func-max-lines", "3", "-only-rules", "large-function", "-llm-suggest", "all"}
+
+	runJSON(t, dir, append(base, ".")...)
+	runJSON(t, dir, append(base, "-llm-endpoint", server.URL, ".")...)
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"", "Bearer key"}; !reflect.DeepEqual(authorization, want) {
+		t.Errorf("Expected the key only sent to the endpoint given with -llm-endpoint, got %q", authorization)
+	}
+}
+
+func TestFingerprints_RelativeToRepository(t *testing.T) {
+	repo := newGitRepo(t)
+	sub := filepath.Join(repo, "sub")
+	writeFile(t, filepath.Join(sub, "main.go"), "package main\n\nfunc main() {\n\t_ = 1\n\t_ = 2\n\t_ = 3\n}\n")
+	git(t, "add", ".")
+	base := []string{"-fail-on", "none", "-func-max-lines", "3", "-only-rules", "large-function"}
+
+	runs := []struct {
+		name string
+		dir  string
+		args []string
+	}{
+		{"repository", repo, []string{"."}},
+		{"subdirectory", sub, []string{"."}},
+		{"directory argument", repo, []string{"sub"}},
+		{"file argument", repo, []string{filepath.Join("sub", "main.go")}},
+		{"staged", sub, []string{"-staged"}},
+	}
+	want := ""
+	for _, run := range runs {
+		report, _ := runJSON(t, run.dir, append(base, run.args...)...)
+		if len(report.Results) != 1 {
+			t.Fatalf("%s: expected one finding, got %+v", run.name, report.Results)
+		}
+		if want == "" {
+			want = report.Results[0].Fingerprint
+		} else if got := report.Results[0].Fingerprint; got != want {
+			t.Errorf("%s: expected fingerprint %s, got %s", run.name, want, got)
+		}
+	}
+}
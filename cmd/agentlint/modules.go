@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/dependencies"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang"
+)
+
+// moduleRoot returns the directory in which Go modules are discovered: the analyzed path, the
+// working directory when analyzing a list of files, or its counterpart in the snapshot of the
+// index when analyzing staged files
+func moduleRoot(flags *parsedFlags) string {
+	if flags.staged {
+		return flags.snapshot.workDir
+	}
+	if len(fileArgs()) > 0 {
+		wd, err := os.Getwd()
+		if err != nil {
+			fatal("failed to get working directory", "error", err)
+		}
+		return wd
+	}
+	return resolvePath()
+}
+
+// filterModule keeps only the files inside the named module, excluding modules nested in it
+func filterModule(filesByLanguage map[string][]string, modules []golang.Module, name string) (map[string][]string, *golang.Module, error) {
+	selected := golang.SelectModule(modules, name)
+	if selected == nil {
+		return nil, nil, fmt.Errorf("no Go module matches %q", name)
+	}
+
+	filtered := make(map[string][]string, len(filesByLanguage))
+	for language, files := range filesByLanguage {
+		for _, file := range files {
+			if golang.FindModule(modules, file) == selected {
+				filtered[language] = append(filtered[language], file)
+			}
+		}
+	}
+	return filtered, selected, nil
+}
+
+// analyzeModules finds functions unused anywhere in their module and keeps the findings for
+// the analyzed files
+func analyzeModules(ctx context.Context, root string, goFiles []string, cfg core.Config, astCache *golang.ASTCache) []core.Result {
+	orphaned := cfg.Rules.OrphanedCode
+	if !orphaned.Enabled || !orphaned.CheckUnusedFunctions || len(goFiles) == 0 {
+		return nil
+	}
+
+	analyzer := golang.NewCrossFileAnalyzer()
+	analyzer.SetCache(astCache)
+	analyzer.SetIncludeExported(orphaned.IncludeExported)
+	if err := analyzer.SetIgnorePatterns(orphaned.IgnoreFunctionPatterns, orphaned.IgnoreReceivers); err != nil {
+		slog.Warn("skipping cross-file unused function analysis", "error", err)
+		return nil
+	}
+	analyzer.SetMinStatements(orphaned.MinStatements)
+	if err := buildCrossFileIndex(ctx, analyzer, root, orphaned.IndexFile); err != nil {
+		slog.Warn("skipping cross-file unused function analysis", "error", err)
+		return nil
+	}
+
+	analyzed := make(map[string]bool, len(goFiles))
+	for _, file := range goFiles {
+		analyzed[file] = true
+	}
+
+	var results []core.Result
+	for _, result := range analyzer.FindUnusedFunctions() {
+		if analyzed[result.FilePath] {
+			results = append(results, result)
+		}
+	}
+
+	if orphaned.IndexFile != "" {
+		if err := saveCrossFileIndex(analyzer, orphaned.IndexFile); err != nil {
+			slog.Warn("failed to save cross-file index", "file", orphaned.IndexFile, "error", err)
+		}
+	}
+	return results
+}
+
+// analyzeErrorStrings reports error messages repeated across the packages of the analyzed Go
+// files
+func analyzeErrorStrings(ctx context.Context, goFiles []string, cfg core.Config, astCache *golang.ASTCache) []core.Result {
+	duplicates := cfg.Rules.DuplicateErrors
+	if !duplicates.Enabled || len(goFiles) == 0 {
+		return nil
+	}
+
+	var files []string
+	for _, file := range goFiles {
+		if golang.IgnoreReason(file, cfg) == "" {
+			files = append(files, file)
+		}
+	}
+
+	analyzer := golang.NewErrorStringAnalyzer(duplicates.MinOccurrences, duplicates.Threshold)
+	analyzer.SetCache(astCache)
+	results, err := analyzer.AnalyzeFiles(ctx, files)
+	if err != nil {
+		slog.Warn("skipping duplicate error string analysis", "error", err)
+		return nil
+	}
+	return results
+}
+
+// analyzeSimilarity reports the functions of the analyzed Go files whose bodies follow the
+// same pattern, leaving out those too small for the likeness to mean anything
+func analyzeSimilarity(ctx context.Context, goFiles []string, cfg core.Config, astCache *golang.ASTCache) []core.Result {
+	similarity := cfg.Rules.Similarity
+	if !similarity.Enabled || len(goFiles) == 0 {
+		return nil
+	}
+
+	var files []string
+	for _, file := range goFiles {
+		if golang.IgnoreReason(file, cfg) == "" && !strings.HasSuffix(file, "_test.go") {
+			files = append(files, file)
+		}
+	}
+
+	analyzer := golang.NewSimilarityAnalyzer()
+	analyzer.SetCache(astCache)
+	analyzer.SetMinSize(similarity.MinStatements, similarity.MinTokens)
+	results, err := analyzer.AnalyzeFiles(ctx, files, similarity.Threshold)
+	if err != nil {
+		slog.Warn("skipping code similarity analysis", "error", err)
+		return nil
+	}
+	return results
+}
+
+// analyzeUntested reports the exported functions of the analyzed Go files' packages that no
+// test of the package refers to
+func analyzeUntested(ctx context.Context, goFiles []string, cfg core.Config, astCache *golang.ASTCache) []core.Result {
+	testQuality := cfg.Rules.TestQuality
+	if !testQuality.Enabled || !testQuality.CheckUntested || len(goFiles) == 0 {
+		return nil
+	}
+
+	var files []string
+	for _, file := range goFiles {
+		if !strings.HasSuffix(file, "_test.go") && golang.IgnoreReason(file, cfg) == "" {
+			files = append(files, file)
+		}
+	}
+
+	analyzer := golang.NewTestMapAnalyzer()
+	analyzer.SetCache(astCache)
+	results, err := analyzer.AnalyzeFiles(ctx, files)
+	if err != nil {
+		slog.Warn("skipping untested function analysis", "error", err)
+		return nil
+	}
+	return results
+}
+
+// buildCrossFileIndex analyzes the Go files under root, starting from the index saved in
+// indexFile when there is a usable one, so only the files changed since are parsed
+func buildCrossFileIndex(ctx context.Context, analyzer *golang.CrossFileAnalyzer, root, indexFile string) error {
+	if indexFile != "" {
+		if file, err := os.Open(indexFile); err == nil {
+			err = analyzer.LoadIndex(bufio.NewReader(file))
+			file.Close()
+			if err == nil {
+				changed, err := analyzer.Update(ctx, root)
+				slog.Debug("updated cross-file index", "file", indexFile, "changed", len(changed))
+				return err
+			}
+			slog.Debug("rebuilding cross-file index", "file", indexFile, "error", err)
+		}
+	}
+	return analyzer.AnalyzeDirectory(ctx, root)
+}
+
+// saveCrossFileIndex writes the analyzer's index to indexFile, replacing it only once the
+// whole index is written so an interrupted run never leaves a truncated index behind
+func saveCrossFileIndex(analyzer *golang.CrossFileAnalyzer, indexFile string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(indexFile), filepath.Base(indexFile)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	if err := analyzer.SaveIndex(writer); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), indexFile)
+}
+
+// analyzeDependencies reports import cycles and deep import chains and keeps the findings for
+// the analyzed files. When only some files are analyzed, the graph is still built from every
+// file under root, since a cycle usually spans files that were not changed.
+func analyzeDependencies(ctx context.Context, flags *parsedFlags, scanner *languages.MultiScanner, root string, filesByLanguage map[string][]string, modules []golang.Module, cfg core.Config) []core.Result {
+	if !cfg.Rules.Dependencies.Enabled {
+		return nil
+	}
+
+	projectFiles, err := allProjectFiles(ctx, flags, scanner, root, filesByLanguage)
+	if err != nil {
+		slog.Warn("skipping import graph analysis", "error", err)
+		return nil
+	}
+	analyzed := analyzedFiles(filesByLanguage)
+
+	var results []core.Result
+	for _, result := range dependencies.Analyze(root, projectFiles, modules, cfg.Rules.Dependencies) {
+		if analyzed[result.FilePath] {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// analyzeLayout reports catch-all packages, uninformative file names, stub files and package
+// names that differ from their directory, keeping the findings for the analyzed Go files. Like
+// the import graph, the layout is always taken from every Go file under root.
+func analyzeLayout(ctx context.Context, flags *parsedFlags, scanner *languages.MultiScanner, root string, filesByLanguage map[string][]string, modules []golang.Module, cfg core.Config, astCache *golang.ASTCache) []core.Result {
+	if !cfg.Rules.Layout.Enabled || len(filesByLanguage["go"]) == 0 {
+		return nil
+	}
+
+	projectFiles, err := allProjectFiles(ctx, flags, scanner, root, filesByLanguage)
+	if err != nil {
+		slog.Warn("skipping package layout analysis", "error", err)
+		return nil
+	}
+	analyzed := make(map[string]bool)
+	for _, file := range filesByLanguage["go"] {
+		if golang.IgnoreReason(file, cfg) == "" {
+			analyzed[file] = true
+		}
+	}
+
+	analyzer := golang.NewLayoutAnalyzer(cfg.Rules.Layout)
+	analyzer.SetCache(astCache)
+	return analyzer.Analyze(root, projectFiles["go"], modules, analyzed)
+}
+
+// allProjectFiles returns the files of every language under root: filesByLanguage when the
+// whole project is analyzed, or a fresh scan when only staged or listed files are
+func allProjectFiles(ctx context.Context, flags *parsedFlags, scanner *languages.MultiScanner, root string, filesByLanguage map[string][]string) (map[string][]string, error) {
+	if flags.staged || len(fileArgs()) > 0 {
+		return scanner.Scan(ctx, root)
+	}
+	return filesByLanguage, nil
+}
+
+// classifyProject tells whether the project's Go code builds a binary or is a library. Every Go
+// file under root is looked at, so analyzing only a few files does not change the answer.
+func classifyProject(ctx context.Context, flags *parsedFlags, scanner *languages.MultiScanner, root string, filesByLanguage map[string][]string, astCache *golang.ASTCache) golang.ProjectKind {
+	projectFiles, err := allProjectFiles(ctx, flags, scanner, root, filesByLanguage)
+	if err != nil {
+		slog.Debug("classifying project failed", "error", err)
+		return ""
+	}
+	return golang.ClassifyProject(projectFiles["go"], astCache)
+}
+
+// analyzedFiles returns the set of files being analyzed, of every language
+func analyzedFiles(filesByLanguage map[string][]string) map[string]bool {
+	analyzed := make(map[string]bool)
+	for _, files := range filesByLanguage {
+		for _, file := range files {
+			analyzed[file] = true
+		}
+	}
+	return analyzed
+}
+
+// annotateModules records the Go module of each result's file when the project spans several
+// modules, so reports can be grouped per module
+func annotateModules(results []core.Result, modules []golang.Module) {
+	if len(modules) < 2 {
+		return
+	}
+	for i := range results {
+		if module := golang.FindModule(modules, results[i].FilePath); module != nil {
+			results[i].Module = module.Path
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/notify"
+	"github.com/CiaranMcAleer/AgentLint/internal/output"
+)
+
+// notifyFlags are the command-line flags posting a summary of the run to a chat webhook
+type notifyFlags struct {
+	notifyWebhook   string
+	notifyFormat    string
+	notifyBaseline  string
+	notifyReportURL string
+}
+
+// register defines the notification flags, defaulting to the settings of base
+func (f *notifyFlags) register(base core.NotifyConfig) {
+	flag.StringVar(&f.notifyWebhook, "notify-webhook", base.Webhook, "Slack or Microsoft Teams incoming webhook URL to post a summary of the run to")
+	flag.StringVar(&f.notifyFormat, "notify-format", base.Format, "Message format of -notify-webhook: slack, teams (default: told from the URL)")
+	flag.StringVar(&f.notifyBaseline, "notify-baseline", base.Baseline, "JSON report of an earlier run to count new and fixed findings against in the summary")
+	flag.StringVar(&f.notifyReportURL, "notify-report-url", base.ReportURL, "Link to the full report added to the summary, such as a CI artifact")
+}
+
+// valid logs an invalid notification flag, if any, and reports whether they were all valid
+func (f *notifyFlags) valid() bool {
+	if f.notifyFormat != "" && f.notifyFormat != notify.FormatSlack && f.notifyFormat != notify.FormatTeams {
+		slog.Error("invalid -notify-format value (expected slack or teams)", "value", f.notifyFormat)
+		return false
+	}
+	return true
+}
+
+// config returns the notification settings given by the flags
+func (f *notifyFlags) config() core.NotifyConfig {
+	return core.NotifyConfig{
+		Webhook:   f.notifyWebhook,
+		Format:    f.notifyFormat,
+		Baseline:  f.notifyBaseline,
+		ReportURL: f.notifyReportURL,
+	}
+}
+
+// notifyWebhook posts a summary of the results to the webhook of settings, if any. A failed
+// post is logged and does not fail the run, which has been reported already.
+func notifyWebhook(settings core.NotifyConfig, results []core.Result, project string) {
+	if settings.Webhook == "" {
+		return
+	}
+
+	var baseline []core.Result
+	if settings.Baseline != "" {
+		var err error
+		if baseline, err = loadBaseline(settings.Baseline); err != nil {
+			slog.Warn("comparing with the notification baseline failed", "error", err)
+		}
+	}
+	summary := notify.Summarize(project, results, baseline, settings.ReportURL)
+	ctx, cancel := context.WithTimeout(context.Background(), notify.Timeout)
+	defer cancel()
+	client := &http.Client{Timeout: notify.Timeout}
+	if err := notify.Post(ctx, client, settings.Webhook, settings.Format, summary); err != nil {
+		slog.Warn("posting the run summary failed", "error", err)
+	}
+}
+
+// loadBaseline reads the results of a report written by -format json
+func loadBaseline(path string) ([]core.Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report output.JSONOutput
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("%s is not a JSON report: %w", path, err)
+	}
+	if report.Results == nil {
+		return []core.Result{}, nil // an empty report is a baseline without findings
+	}
+	return report.Results, nil
+}
+
+// projectName names what was analyzed in notifications: the directory analyzed, or the
+// working directory when several roots or a list of files are
+func projectName(roots []string) string {
+	if len(roots) == 0 && len(fileArgs()) == 0 {
+		return filepath.Base(resolvePath())
+	}
+	wd, _ := os.Getwd()
+	return filepath.Base(wd)
+}
+
+func printNotifyOptions() {
+	fmt.Println("Notification Options:")
+	fmt.Println("  -notify-webhook url   Slack or Microsoft Teams incoming webhook to post a summary of the run to")
+	fmt.Println("  -notify-format string  Message format of -notify-webhook: slack, teams (default: told from the URL)")
+	fmt.Println("  -notify-baseline file  JSON report of an earlier run to count new and fixed findings against")
+	fmt.Println("  -notify-report-url url  Link to the full report added to the summary")
+	fmt.Println()
+}
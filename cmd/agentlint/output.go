@@ -0,0 +1,187 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/output"
+)
+
+// outputFlags are the command-line flags choosing how findings are reported and which of them
+type outputFlags struct {
+	outputFormat   string
+	outputFile     string
+	verbose        bool
+	noColor        bool
+	groupBy        string
+	top            int
+	template       string
+	minConfidence  string
+	onlyCategories string
+	skipCategories string
+	onlyRules      string
+	severity       string
+	pathFilter     stringList
+	codeowners     string
+	coverage       string
+	metricsFile    string
+}
+
+// register defines the output flags, defaulting to the settings of base
+func (f *outputFlags) register(base core.OutputConfig) {
+	flag.StringVar(&f.outputFormat, "format", base.Format, "Output format (console, json, csv, tsv, rdjsonl, template)")
+	flag.StringVar(&f.outputFile, "output", "", "Output file (default: stdout)")
+	flag.BoolVar(&f.verbose, "verbose", base.Verbose, "Verbose output")
+	flag.BoolVar(&f.noColor, "no-color", base.NoColor, "Disable colored console output")
+	flag.StringVar(&f.template, "template", base.Template, "Go text/template applied to each result with -format template")
+	flag.StringVar(&f.groupBy, "group-by", base.GroupBy, "How console output groups findings: file, rule")
+	flag.IntVar(&f.top, "top", base.Top, "List the N files and rules with the most findings after the summary (0 = none)")
+	flag.StringVar(&f.onlyCategories, "only-categories", strings.Join(base.OnlyCategories, ","), "Comma-separated rule categories to report, e.g. performance,bug (default: all)")
+	flag.StringVar(&f.skipCategories, "skip-categories", strings.Join(base.SkipCategories, ","), "Comma-separated rule categories never reported, e.g. style")
+	flag.StringVar(&f.onlyRules, "only-rules", strings.Join(base.OnlyRules, ","), "Comma-separated rule IDs to report, e.g. large-function,console-log (default: all)")
+	flag.StringVar(&f.severity, "severity", base.Severity, "Report only findings of at least this severity (error, warning, info)")
+	flag.Var(&f.pathFilter, "path-filter", "Report only findings in files matching this glob, relative to the working directory, e.g. 'src/**' (repeatable)")
+	flag.StringVar(&f.minConfidence, "min-confidence", base.MinConfidence, "Report only findings at least this likely to be real problems (high, medium, low)")
+	flag.StringVar(&f.codeowners, "codeowners", base.Codeowners, "CODEOWNERS file naming the owners of each finding (default: found in the repository, none to disable)")
+	flag.StringVar(&f.coverage, "coverage", base.Coverage, "Go coverprofile, LCOV or coverage.py XML report marking findings in code the tests never ran")
+	flag.StringVar(&f.metricsFile, "metrics-file", base.MetricsFile, "File to write gauges of the run to in Prometheus text format, e.g. for node_exporter's textfile collector")
+}
+
+// valid logs the first invalid output flag, if any, and reports whether they were all valid
+func (f *outputFlags) valid() bool {
+	if f.top < 0 {
+		slog.Error("invalid -top value (expected 0 or more)", "value", f.top)
+		return false
+	}
+	if !isValidGroupBy(f.groupBy) {
+		slog.Error("invalid -group-by value (expected file or rule)", "value", f.groupBy)
+		return false
+	}
+	if f.minConfidence != "" && core.Confidence(f.minConfidence).Rank() == 0 {
+		slog.Error("invalid -min-confidence value (expected high, medium or low)", "value", f.minConfidence)
+		return false
+	}
+	if err := core.ValidateCategories(splitList(f.onlyCategories)); err != nil {
+		slog.Error("invalid -only-categories value", "error", err)
+		return false
+	}
+	if err := core.ValidateCategories(splitList(f.skipCategories)); err != nil {
+		slog.Error("invalid -skip-categories value", "error", err)
+		return false
+	}
+	if err := validateRuleIDs(splitList(f.onlyRules)); err != nil {
+		slog.Error("invalid -only-rules value", "error", err)
+		return false
+	}
+	if f.severity != "" && core.Severity(f.severity).Rank() == 0 {
+		slog.Error("invalid -severity value (expected error, warning or info)", "value", f.severity)
+		return false
+	}
+	if f.outputFormat == "template" {
+		if _, err := output.NewTemplateFormatter(f.template); err != nil {
+			slog.Error("invalid -template value", "error", err)
+			return false
+		}
+	}
+	return true
+}
+
+// config returns the output settings given by the flags. Settings without a flag, such as the
+// suggestion templates, come from base.
+func (f *outputFlags) config(base core.OutputConfig) core.OutputConfig {
+	return core.OutputConfig{
+		Format:         f.outputFormat,
+		Verbose:        f.verbose,
+		NoColor:        f.noColor,
+		GroupBy:        f.groupBy,
+		Template:       f.template,
+		MinConfidence:  f.minConfidence,
+		OnlyCategories: splitList(f.onlyCategories),
+		SkipCategories: splitList(f.skipCategories),
+		OnlyRules:      splitList(f.onlyRules),
+		Severity:       f.severity,
+		PathFilter:     globsOr(f.pathFilter, base.PathFilter),
+		Top:            f.top,
+		Codeowners:     f.codeowners,
+		Coverage:       f.coverage,
+		MetricsFile:    f.metricsFile,
+		Suggestions:    base.Suggestions,
+	}
+}
+
+func outputResults(cfg core.Config, allResults []core.Result, fileErrors []core.FileError, roots []string, kinds map[string]string, run output.RunInfo, outputFile string) {
+	if outputFile != "" {
+		outputFileHandle, err := os.Create(outputFile)
+		if err != nil {
+			fatal("creating output file failed", "error", err)
+		}
+		defer outputFileHandle.Close()
+		os.Stdout = outputFileHandle
+	}
+
+	var formatter output.Formatter
+	switch cfg.Output.Format {
+	case "json":
+		jsonFormatter := output.NewJSONFormatter(cfg.Output.Verbose)
+		jsonFormatter.SetRoots(roots)
+		jsonFormatter.SetProjectKinds(kinds)
+		jsonFormatter.SetRunInfo(run)
+		jsonFormatter.SetTop(cfg.Output.Top)
+		formatter = jsonFormatter
+	case "csv":
+		formatter = output.NewCSVFormatter()
+	case "tsv":
+		formatter = output.NewTSVFormatter()
+	case "rdjsonl":
+		formatter = output.NewRDFormatter()
+	case "template":
+		tmpl, err := output.NewTemplateFormatter(cfg.Output.Template)
+		if err != nil {
+			fatal("creating output formatter failed", "error", err)
+		}
+		formatter = tmpl
+	case "console":
+		fallthrough
+	default:
+		console := output.NewConsoleFormatter(cfg.Output.Verbose)
+		console.SetColor(!cfg.Output.NoColor && output.ColorEnabled(os.Stdout))
+		console.SetGroupBy(cfg.Output.GroupBy)
+		console.SetTop(cfg.Output.Top)
+		console.SetRoots(roots)
+		console.SetProjectKinds(kinds)
+		formatter = console
+	}
+
+	formatter.SetFileErrors(fileErrors)
+	formatter.PrintHeader()
+	if err := formatter.Format(allResults); err != nil {
+		formatter.FormatError(err)
+		os.Exit(1)
+	}
+	formatter.PrintFooter()
+}
+
+func printOutputOptions() {
+	fmt.Println("Output Options:")
+	fmt.Println("  -format string       Output format (console, json, csv, tsv, rdjsonl, template) (default \"console\")")
+	fmt.Println("  -template string     Go text/template applied to each result with -format template")
+	fmt.Println("  -output string       Output file (default: stdout)")
+	fmt.Println("  -verbose             Verbose output")
+	fmt.Println("  -no-color            Disable colored console output (also set by the NO_COLOR environment variable)")
+	fmt.Println("  -group-by string     How console output groups findings: file, rule (default \"file\")")
+	fmt.Println("  -top n               List the n files and rules with the most findings after the summary (default 0, none)")
+	fmt.Println("  -codeowners string   CODEOWNERS file naming the owners of each finding (default: found in the repository, none to disable)")
+	fmt.Println("  -coverage string     Go coverprofile, LCOV or coverage.py XML report marking findings in untested code")
+	fmt.Println("  -metrics-file string  File to write gauges of the run to in Prometheus text format")
+	fmt.Println("  -only-categories list   Comma-separated rule categories to report, e.g. performance,bug (default: all)")
+	fmt.Println("  -skip-categories list   Comma-separated rule categories never reported, e.g. style")
+	fmt.Println("  -only-rules list        Comma-separated rule IDs to report, e.g. large-function,console-log (default: all)")
+	fmt.Println("  -severity string        Report only findings of at least this severity (error, warning, info)")
+	fmt.Println("  -path-filter glob       Report only findings in files matching this glob, e.g. 'src/**' (repeatable)")
+	fmt.Println("  -min-confidence string  Report only findings at least this likely to be real problems (high, medium, low)")
+	fmt.Println()
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/owners"
+)
+
+// annotateOwners records the owners of each result's file, read from codeownersFile or, when
+// it is empty, from the CODEOWNERS file of the repository holding root. Patterns of a named
+// file are relative to that repository, or to the working directory outside one.
+func annotateOwners(results []core.Result, codeownersFile, root string) {
+	if codeownersFile == "none" || len(results) == 0 {
+		return
+	}
+
+	var codeowners *owners.Codeowners
+	var err error
+	if codeownersFile == "" {
+		codeowners, err = owners.Discover(root)
+	} else {
+		repoRoot := owners.RepositoryRoot(root)
+		if repoRoot == "" {
+			repoRoot, _ = os.Getwd()
+		}
+		codeowners, err = owners.Load(codeownersFile, repoRoot)
+	}
+	if err != nil {
+		slog.Warn("skipping code ownership", "error", err)
+		return
+	}
+	if codeowners == nil {
+		return
+	}
+	for i := range results {
+		results[i].Owners = codeowners.Owners(results[i].FilePath)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+	"github.com/CiaranMcAleer/AgentLint/internal/patch"
+)
+
+// analyzePatch analyzes the files changed by the unified diff read from r, as they are after
+// the change, and returns the findings on the lines the diff adds or changes, fingerprinted.
+// Paths in the diff are relative to dir. The diff is applied to copies of the files, leaving
+// dir untouched, so a change can be checked before it is applied; a diff already applied to
+// dir is recognized and its files analyzed as they are. Only the per-file analyses run, since
+// the project-wide ones need the whole tree after the change.
+func analyzePatch(ctx context.Context, r io.Reader, dir string, flags *parsedFlags, scanner *languages.MultiScanner, registry *languages.Registry, cfg core.Config) ([]core.Result, []core.FileError, error) {
+	files, err := patch.Parse(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading patch failed: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "agentlint-patch-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var fileErrors []core.FileError
+	var patched []string
+	changes := make(map[string]patch.File) // by path of the patched copy
+	for _, file := range files {
+		if file.NewPath == "" {
+			continue // deleted files have nothing left to report
+		}
+		content, err := patchedContent(dir, file)
+		if err != nil {
+			fileErrors = append(fileErrors, core.FileError{FilePath: filepath.Join(dir, file.NewPath), Message: err.Error()})
+			continue
+		}
+		copyPath := filepath.Join(tmpDir, filepath.FromSlash(file.NewPath))
+		if err := os.MkdirAll(filepath.Dir(copyPath), 0o755); err != nil {
+			return nil, nil, err
+		}
+		if err := os.WriteFile(copyPath, []byte(content), 0o644); err != nil {
+			return nil, nil, err
+		}
+		patched = append(patched, copyPath)
+		changes[copyPath] = file
+	}
+	slog.Info("analyzing patch", "files", len(patched))
+
+	results, errs := analyzeFiles(ctx, scanner.GroupFilesUnder(tmpDir, patched), registry, cfg, newEngine(flags))
+	results = core.Dedupe(results)
+	fingerprintRelativeTo(results, tmpDir)
+	annotateAIGenerated(results, cfg.AIFiles)
+
+	added := make(map[string]map[int]bool, len(changes))
+	kept := results[:0]
+	for _, result := range results {
+		file, ok := changes[result.FilePath]
+		if !ok {
+			continue
+		}
+		if added[result.FilePath] == nil {
+			added[result.FilePath] = file.AddedLines()
+		}
+		// every finding in a file the patch creates is the patch's; elsewhere, only those on
+		// the lines it adds or changes
+		if file.OldPath != "" && !added[result.FilePath][result.Line] {
+			continue
+		}
+		result.FilePath = filepath.Join(dir, filepath.FromSlash(file.NewPath))
+		if result.Related != nil {
+			if rel, err := filepath.Rel(tmpDir, result.Related.FilePath); err == nil {
+				result.Related.FilePath = filepath.Join(dir, rel)
+			}
+		}
+		kept = append(kept, result)
+	}
+	for _, fileErr := range errs {
+		if rel, err := filepath.Rel(tmpDir, fileErr.FilePath); err == nil {
+			fileErr.FilePath = filepath.Join(dir, rel)
+		}
+		fileErrors = append(fileErrors, fileErr)
+	}
+	annotateOwners(kept, cfg.Output.Codeowners, dir)
+	return kept, fileErrors, nil
+}
+
+// patchedContent returns the text of a file changed by a patch after the change: the patch
+// applied to the file in dir, or the file as it is when the patch has already been applied
+func patchedContent(dir string, file patch.File) (string, error) {
+	var old []byte
+	if file.OldPath != "" {
+		var err error
+		if old, err = os.ReadFile(filepath.Join(dir, filepath.FromSlash(file.OldPath))); err != nil {
+			return "", fmt.Errorf("patch does not apply: %w", err)
+		}
+	}
+	text := strings.ReplaceAll(string(languages.DecodeSource(old)), "\r\n", "\n")
+	content, err := file.Apply(text)
+	if err == nil {
+		return content, nil
+	}
+
+	current, readErr := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file.NewPath)))
+	if readErr != nil {
+		return "", fmt.Errorf("patch does not apply: %w", err)
+	}
+	currentText := strings.ReplaceAll(string(languages.DecodeSource(current)), "\r\n", "\n")
+	if _, reverseErr := file.Reverse().Apply(currentText); reverseErr != nil {
+		return "", fmt.Errorf("patch does not apply: %w", err)
+	}
+	return currentText, nil
+}
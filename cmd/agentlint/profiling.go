@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/profiling"
+)
+
+// profilingFlags are the command-line flags profiling agentlint itself
+type profilingFlags struct {
+	cpuProfile   string
+	memProfile   string
+	traceProfile string
+	profileRules bool
+}
+
+// register defines the profiling flags
+func (f *profilingFlags) register() {
+	flag.StringVar(&f.cpuProfile, "cpuprofile", "", "Write CPU profile to file")
+	flag.StringVar(&f.memProfile, "memprofile", "", "Write memory profile to file")
+	flag.StringVar(&f.traceProfile, "trace", "", "Write execution trace to file")
+	flag.BoolVar(&f.profileRules, "profile-rules", false, "Print the slowest rules and files after analysis")
+}
+
+// start starts the profiles requested on the command line and returns a function that writes
+// and closes them. It must be called once the analysis run has finished.
+func (flags *profilingFlags) start() func() {
+	if flags.profileRules {
+		profiling.EnableRuleProfiling()
+	}
+
+	var stops []func()
+	stop := func() {
+		for i := len(stops) - 1; i >= 0; i-- {
+			stops[i]()
+		}
+	}
+
+	if flags.cpuProfile != "" {
+		if err := profiling.StartCPUProfile(flags.cpuProfile); err != nil {
+			fatal("starting CPU profile failed", "error", err)
+		}
+		stops = append(stops, profiling.StopCPUProfile)
+	}
+
+	if flags.memProfile != "" {
+		if err := profiling.StartMemProfile(flags.memProfile); err != nil {
+			stop()
+			fatal("starting memory profile failed", "error", err)
+		}
+		stops = append(stops, func() {
+			if err := profiling.WriteMemProfile(); err != nil {
+				slog.Error("writing memory profile failed", "error", err)
+			}
+			profiling.CloseMemProfile()
+		})
+	}
+
+	if flags.traceProfile != "" {
+		if err := profiling.StartTrace(flags.traceProfile); err != nil {
+			stop()
+			fatal("starting trace failed", "error", err)
+		}
+		stops = append(stops, profiling.StopTrace)
+	}
+
+	return stop
+}
+
+func printProfilingOptions() {
+	fmt.Println("Profiling Options:")
+	fmt.Println("  -cpuprofile string   Write CPU profile to file")
+	fmt.Println("  -memprofile string   Write memory profile to file")
+	fmt.Println("  -trace string        Write execution trace to file")
+	fmt.Println("  -profile-rules       Print the slowest rules and files after analysis")
+	fmt.Println()
+}
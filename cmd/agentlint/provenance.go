@@ -0,0 +1,56 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+)
+
+// annotateAIGenerated marks the results in files an AI assistant wrote, looking at each file
+// once
+func annotateAIGenerated(results []core.Result, aiFiles core.AIFilesConfig) {
+	generated := make(map[string]bool)
+	for i := range results {
+		aiGenerated, ok := generated[results[i].FilePath]
+		if !ok {
+			aiGenerated = languages.IsAIGenerated(results[i].FilePath, aiFiles)
+			generated[results[i].FilePath] = aiGenerated
+		}
+		results[i].AIGenerated = aiGenerated
+	}
+}
+
+// annotateExternal marks the results in vendored and third-party code under root, see
+// core.FilesConfig.External
+func annotateExternal(results []core.Result, root string) {
+	for i := range results {
+		results[i].External = isExternal(root, results[i].FilePath)
+	}
+}
+
+// ownFiles returns the files of each language outside vendored and third-party directories
+// under root, which the project-wide analyses are limited to
+func ownFiles(root string, filesByLanguage map[string][]string) map[string][]string {
+	own := make(map[string][]string, len(filesByLanguage))
+	for language, files := range filesByLanguage {
+		for _, file := range files {
+			if !isExternal(root, file) {
+				own[language] = append(own[language], file)
+			}
+		}
+	}
+	return own
+}
+
+// isExternal reports whether a file lies in a vendored dependency directory below root, so a
+// project that itself sits in a directory named vendor is not taken for external code
+func isExternal(root, path string) bool {
+	if abs, err := filepath.Abs(path); err == nil {
+		if rel, err := filepath.Rel(root, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	return languages.IsVendored(path)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/remote"
+)
+
+// remoteFlags are the command-line flags sharding the per-file analysis across agentlint
+// workers, see runWorker
+type remoteFlags struct {
+	remoteWorkers string
+	remoteTimeout time.Duration
+}
+
+// register defines the remote worker flags
+func (f *remoteFlags) register() {
+	flag.StringVar(&f.remoteWorkers, "remote-workers", "", "Comma-separated host:port addresses of agentlint workers to shard the files across")
+	flag.DurationVar(&f.remoteTimeout, "remote-timeout", 10*time.Minute, "How long to wait for a remote worker to analyze its share of the files")
+}
+
+// enabled reports whether files are analyzed on remote workers rather than on this machine
+func (f *remoteFlags) enabled() bool {
+	return len(splitList(f.remoteWorkers)) > 0
+}
+
+// analyze runs the per-file analysis of the files under root on the remote workers, which
+// must hold the token in AGENTLINT_WORKER_TOKEN
+func (f *remoteFlags) analyze(ctx context.Context, root string, filesByLanguage map[string][]string, cfg core.Config) ([]core.Result, []core.FileError, error) {
+	token := os.Getenv(remote.TokenEnv)
+	if token == "" {
+		return nil, nil, fmt.Errorf("%s must hold the token the remote workers were started with", remote.TokenEnv)
+	}
+	workers := splitList(f.remoteWorkers)
+	slog.Info("analyzing on remote workers", "workers", len(workers))
+	results, fileErrors, err := remote.NewCoordinator(workers, token, f.remoteTimeout).Run(ctx, root, filesByLanguage, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("remote analysis failed: %w", err)
+	}
+	return results, fileErrors, nil
+}
+
+func printRemoteOptions() {
+	fmt.Println("Remote Workers:")
+	fmt.Println("  -remote-workers string  Comma-separated host:port addresses of agentlint workers to shard the files across")
+	fmt.Println("  -remote-timeout duration  How long to wait for a remote worker (default 10m)")
+	fmt.Println()
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/output"
+)
+
+// runSchema implements the schema subcommand and returns the process exit code. It prints the
+// JSON Schema that -format json output follows, for consumers to validate against.
+func runSchema(args []string) int {
+	fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		slog.Error("unexpected arguments", "args", fs.Args())
+		return 2
+	}
+	if _, err := os.Stdout.Write(output.JSONSchema); err != nil {
+		slog.Error("failed to write the schema", "error", err)
+		return 1
+	}
+	return 0
+}
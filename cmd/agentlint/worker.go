@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang"
+	"github.com/CiaranMcAleer/AgentLint/internal/logging"
+	"github.com/CiaranMcAleer/AgentLint/internal/remote"
+)
+
+// runWorker implements the worker subcommand and returns the process exit code. It serves
+// analysis requests from coordinators run with -remote-workers until the process is stopped,
+// analyzing the files under -root, its copy of the directory the coordinators analyze, for
+// coordinators holding the token in AGENTLINT_WORKER_TOKEN.
+func runWorker(args []string) int {
+	fset := flag.NewFlagSet("worker", flag.ContinueOnError)
+	listen := fset.String("listen", "127.0.0.1:9000", "Address to serve analysis requests on")
+	root := fset.String("root", ".", "This machine's copy of the directory coordinators analyze")
+	workers := fset.Int("workers", 0, "Number of files analyzed in parallel (0 = one per CPU)")
+	cacheFiles := fset.Int("cache-files", 10000, "Go files kept parsed between requests, least recently used dropped first (0 = no limit)")
+	logLevel := fset.String("log-level", "info", "Minimum level of diagnostics written to stderr (debug, info, warn, error)")
+	if err := fset.Parse(args); err != nil {
+		return 2
+	}
+	if !setupLogging(logging.FormatText, *logLevel) {
+		return 2
+	}
+	if fset.NArg() > 0 {
+		slog.Error("unexpected arguments", "args", fset.Args())
+		return 2
+	}
+
+	token := os.Getenv(remote.TokenEnv)
+	if token == "" {
+		slog.Error("a worker needs a token shared with its coordinators", "variable", remote.TokenEnv)
+		return 2
+	}
+
+	absRoot, err := filepath.Abs(*root)
+	if err != nil {
+		slog.Error("failed to get absolute path", "path", *root, "error", err)
+		return 2
+	}
+	// Go files are parsed once for every request that analyzes them until they change
+	astCache := golang.NewASTCache(workerCacheAge)
+	astCache.SetMaxEntries(*cacheFiles)
+	astCache.SetMaxFileSetSize(workerFileSetSize)
+	server := remote.NewServer(absRoot, *workers, token, func(cfg core.Config) *languages.Registry {
+		return setupAnalyzer(cfg, astCache)
+	})
+
+	slog.Info("worker listening", "address", *listen, "root", absRoot)
+	if err := http.ListenAndServe(*listen, recycleAfter(server, astCache)); err != nil {
+		slog.Error("worker stopped", "error", err)
+		return 1
+	}
+	return 0
+}
+
+const (
+	// workerCacheAge is how long a worker keeps a parsed Go file nothing has asked for again
+	workerCacheAge = time.Hour
+	// workerFileSetSize is the size, in bytes of Go source, past which a worker starts a new
+	// FileSet; a FileSet keeps the line table of every file ever parsed into it
+	workerFileSetSize = 256 << 20
+)
+
+// recycleAfter serves requests with next, then logs the parse cache statistics and starts a
+// new FileSet once the cache's has grown too large. Per-file analysis resolves positions
+// through the FileSet each file was parsed into, so requests still running are not affected.
+func recycleAfter(next http.Handler, astCache *golang.ASTCache) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+		stats := astCache.Stats()
+		slog.Debug("go parse cache", "hits", stats.Hits, "misses", stats.Misses, "hit_rate", stats.HitRate(),
+			"evictions", stats.Evictions, "entries", stats.Entries, "fileset_bytes", stats.FileSetSize)
+		if astCache.Recycle() {
+			slog.Info("started a new go parse FileSet", "bytes", stats.FileSetSize)
+		}
+	})
+}
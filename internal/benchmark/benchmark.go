@@ -0,0 +1,183 @@
+// Package benchmark scores the rules against a corpus of characteristic LLM-generated code,
+// so rule changes that lose findings or add false ones show up before they ship
+package benchmark
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// ExpectationsFile names the file listing the findings expected on a corpus, at its root
+const ExpectationsFile = "expected.txt"
+
+//go:embed testdata/corpus
+var bundled embed.FS
+
+// Bundled returns the corpus shipped with agentlint: bad and good samples of each language
+func Bundled() fs.FS {
+	corpus, err := fs.Sub(bundled, "testdata/corpus")
+	if err != nil {
+		panic(err)
+	}
+	return corpus
+}
+
+// Expectation is a finding a corpus expects: rule RuleID reporting line Line of File, a
+// slash-separated path relative to the corpus
+type Expectation struct {
+	File   string
+	Line   int
+	RuleID string
+}
+
+func (e Expectation) String() string {
+	return fmt.Sprintf("%s:%d %s", e.File, e.Line, e.RuleID)
+}
+
+// ReadExpectations reads the expectations file of corpus
+func ReadExpectations(corpus fs.FS) ([]Expectation, error) {
+	f, err := corpus.Open(ExpectationsFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	expected, err := ParseExpectations(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ExpectationsFile, err)
+	}
+	return expected, nil
+}
+
+// ParseExpectations parses lines of the form path:line rule-id. Blank lines and lines
+// starting with # are skipped.
+func ParseExpectations(r io.Reader) ([]Expectation, error) {
+	var expected []Expectation
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected path:line rule-id, found %q", lineNum, line)
+		}
+		file, lineText, ok := strings.Cut(fields[0], ":")
+		findingLine, err := strconv.Atoi(lineText)
+		if !ok || err != nil || findingLine < 1 {
+			return nil, fmt.Errorf("line %d: expected path:line, found %q", lineNum, fields[0])
+		}
+		expected = append(expected, Expectation{File: file, Line: findingLine, RuleID: fields[1]})
+	}
+	return expected, scanner.Err()
+}
+
+// Extract writes the files of corpus under dir, so the analyzers can read them from disk
+func Extract(corpus fs.FS, dir string) error {
+	return fs.WalkDir(corpus, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(path))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		data, err := fs.ReadFile(corpus, path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0o644)
+	})
+}
+
+// RuleScore is how well one rule did on a corpus
+type RuleScore struct {
+	RuleID string
+
+	Missed     []Expectation // expected findings the rule did not report
+	Unexpected []core.Result // findings the rule reported that were not expected, with corpus paths
+	Found      int           // expected findings the rule reported
+}
+
+// Precision is the share of the rule's findings that were expected, 1 when it reported none
+func (s RuleScore) Precision() float64 {
+	reported := s.Found + len(s.Unexpected)
+	if reported == 0 {
+		return 1
+	}
+	return float64(s.Found) / float64(reported)
+}
+
+// Recall is the share of the expected findings the rule reported, 1 when none were expected
+func (s RuleScore) Recall() float64 {
+	expected := s.Found + len(s.Missed)
+	if expected == 0 {
+		return 1
+	}
+	return float64(s.Found) / float64(expected)
+}
+
+// Score compares the results of analyzing a corpus extracted under root with its expected
+// findings, for each rule named by an expectation, sorted by rule ID. Results of other rules
+// are not scored.
+func Score(expected []Expectation, results []core.Result, root string) []RuleScore {
+	scores := make(map[string]*RuleScore)
+	wanted := make(map[Expectation]bool, len(expected))
+	for _, e := range expected {
+		if scores[e.RuleID] == nil {
+			scores[e.RuleID] = &RuleScore{RuleID: e.RuleID}
+		}
+		wanted[e] = true
+	}
+
+	reported := make(map[Expectation]bool)
+	for _, result := range results {
+		score := scores[result.RuleID]
+		if score == nil {
+			continue
+		}
+		e := Expectation{File: relativePath(root, result.FilePath), Line: result.Line, RuleID: result.RuleID}
+		switch {
+		case reported[e]:
+			// a second finding at the same place is a duplicate, not a new one
+		case wanted[e]:
+			score.Found++
+		default:
+			result.FilePath = e.File
+			score.Unexpected = append(score.Unexpected, result)
+		}
+		reported[e] = true
+	}
+	for _, e := range expected {
+		if !reported[e] {
+			scores[e.RuleID].Missed = append(scores[e.RuleID].Missed, e)
+		}
+	}
+
+	sorted := make([]RuleScore, 0, len(scores))
+	for _, score := range scores {
+		sorted = append(sorted, *score)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].RuleID < sorted[j].RuleID })
+	return sorted
+}
+
+// relativePath returns path relative to root with forward slashes, as expectations name files
+func relativePath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,84 @@
+package benchmark_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/benchmark"
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+func TestParseExpectations(t *testing.T) {
+	expected, err := benchmark.ParseExpectations(strings.NewReader(`# comment
+
+go/bad.go:12 large-function
+python/bad.py:3 bare-except
+`))
+	if err != nil {
+		t.Fatalf("ParseExpectations failed: %v", err)
+	}
+	want := []benchmark.Expectation{
+		{File: "go/bad.go", Line: 12, RuleID: "large-function"},
+		{File: "python/bad.py", Line: 3, RuleID: "bare-except"},
+	}
+	if len(expected) != len(want) {
+		t.Fatalf("expected %v, got %v", want, expected)
+	}
+	for i := range want {
+		if expected[i] != want[i] {
+			t.Errorf("expectation %d: expected %v, got %v", i, want[i], expected[i])
+		}
+	}
+
+	for _, bad := range []string{"go/bad.go large-function", "go/bad.go:x large-function", "go/bad.go:3"} {
+		if _, err := benchmark.ParseExpectations(strings.NewReader(bad)); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	root := filepath.FromSlash("/corpus")
+	expected := []benchmark.Expectation{
+		{File: "go/bad.go", Line: 12, RuleID: "large-function"},
+		{File: "go/bad.go", Line: 40, RuleID: "large-function"},
+		{File: "python/bad.py", Line: 3, RuleID: "bare-except"},
+	}
+	results := []core.Result{
+		{RuleID: "large-function", FilePath: filepath.Join(root, "go", "bad.go"), Line: 12},
+		{RuleID: "large-function", FilePath: filepath.Join(root, "go", "good.go"), Line: 5},
+		{RuleID: "bare-except", FilePath: filepath.Join(root, "python", "bad.py"), Line: 3},
+		{RuleID: "bare-except", FilePath: filepath.Join(root, "python", "bad.py"), Line: 3},
+		{RuleID: "print-debug", FilePath: filepath.Join(root, "python", "bad.py"), Line: 7},
+	}
+
+	scores := benchmark.Score(expected, results, root)
+	if len(scores) != 2 {
+		t.Fatalf("expected scores for the two rules with expectations, got %+v", scores)
+	}
+
+	bareExcept, largeFunction := scores[0], scores[1]
+	if bareExcept.RuleID != "bare-except" || bareExcept.Precision() != 1 || bareExcept.Recall() != 1 {
+		t.Errorf("expected bare-except to score 1/1 despite its duplicate finding, got %+v", bareExcept)
+	}
+	if largeFunction.Precision() != 0.5 || largeFunction.Recall() != 0.5 {
+		t.Errorf("expected large-function to score 0.5/0.5, got %.2f/%.2f", largeFunction.Precision(), largeFunction.Recall())
+	}
+	if len(largeFunction.Missed) != 1 || largeFunction.Missed[0].Line != 40 {
+		t.Errorf("expected the finding at line 40 to be missed, got %v", largeFunction.Missed)
+	}
+	if len(largeFunction.Unexpected) != 1 || largeFunction.Unexpected[0].FilePath != "go/good.go" {
+		t.Errorf("expected the finding in go/good.go to be unexpected, got %+v", largeFunction.Unexpected)
+	}
+}
+
+func TestBundledCorpusExpectations(t *testing.T) {
+	expected, err := benchmark.ReadExpectations(benchmark.Bundled())
+	if err != nil {
+		t.Fatalf("reading the bundled expectations failed: %v", err)
+	}
+	if len(expected) == 0 {
+		t.Fatal("expected the bundled corpus to expect findings")
+	}
+}
@@ -0,0 +1,80 @@
+package orders
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Store keeps the orders in memory
+type Store struct {
+	mu     sync.Mutex
+	orders map[string]int
+}
+
+// Snapshot returns a copy of the store
+func (s Store) Snapshot() map[string]int {
+	copied := make(map[string]int, len(s.orders))
+	for id, qty := range s.orders {
+		copied[id] = qty
+	}
+	return copied
+}
+
+// Process counts one more of each order. Here's the updated code with locking.
+func Process(s *Store, ids []string) error {
+	for _, id := range ids {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.orders[id]++
+	}
+	return nil
+}
+
+// Watch prints the changes to the store for a second
+func Watch(s *Store) {
+	changes := make(chan string)
+	go func() {
+		for change := range changes {
+			fmt.Println(change)
+		}
+	}()
+	time.Sleep(time.Second)
+}
+
+// Fetch returns the quantity of the order, or an error after a second
+func Fetch(s *Store, id string) (int, error) {
+	result := make(chan int)
+	go func() { result <- s.orders[id] }()
+	select {
+	case qty := <-result:
+		return qty, nil
+	case <-time.After(time.Second):
+		return 0, fmt.Errorf("fetching order %s timed out", id)
+	}
+}
+
+// Totals sums the quantities of the orders
+func Totals(s *Store) (count, sum, max int, err error) {
+	for _, qty := range s.orders {
+		count++
+		sum += qty
+		if qty > max {
+			max = qty
+		}
+	}
+	return count, sum, max, nil
+}
+
+// Average returns the mean quantity of the orders
+func Average(s *Store) (avg float64) {
+	count, sum, _, err := Totals(s)
+	if err != nil {
+		return
+	}
+	if count == 0 {
+		return
+	}
+	avg = float64(sum) / float64(count)
+	return
+}
@@ -0,0 +1,44 @@
+package orders
+
+import (
+	"context"
+	"sync"
+)
+
+// Ledger records order quantities and is safe for concurrent use
+type Ledger struct {
+	mu     sync.Mutex
+	orders map[string]int
+}
+
+// Add records qty more of the order id
+func (l *Ledger) Add(id string, qty int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.orders[id] += qty
+}
+
+// AddAll records one more of each order, taking the lock once
+func (l *Ledger) AddAll(ids []string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range ids {
+		l.orders[id]++
+	}
+}
+
+// Stream sends the order IDs on the returned channel until ctx is done
+func (l *Ledger) Stream(ctx context.Context, ids []string) <-chan string {
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		for _, id := range ids {
+			select {
+			case out <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,15 @@
+from os.path import *
+
+
+def load_users(path, cache={}):
+    """TODO: Add docstring."""
+    if path in cache:
+        return cache[path]
+    try:
+        with open(path) as f:
+            users = f.read().splitlines()
+    except:
+        users = []
+    print("loaded users:", users)
+    cache[path] = users
+    return users
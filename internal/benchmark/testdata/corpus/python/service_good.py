@@ -0,0 +1,20 @@
+import logging
+import os.path
+
+logger = logging.getLogger(__name__)
+
+
+def load_users(path, cache=None):
+    """Return the user names listed one per line in the file at path."""
+    if cache is None:
+        cache = {}
+    if path in cache:
+        return cache[path]
+    try:
+        with open(path) as f:
+            users = f.read().splitlines()
+    except OSError:
+        logger.warning("cannot read %s", os.path.basename(path))
+        users = []
+    cache[path] = users
+    return users
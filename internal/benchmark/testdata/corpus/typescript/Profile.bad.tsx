@@ -0,0 +1,30 @@
+import React, { useEffect, useState } from 'react';
+import { Text, View } from 'react-native';
+
+export function Profile(props: any) {
+  const [name, setName] = useState('');
+
+  useEffect(() => {
+    fetch(props.url).then((res) => res.json()).then((data) => setName(data.name));
+  }, []);
+
+  console.log('rendering profile', name);
+
+  // @ts-ignore
+  const age: number = props.user.age;
+  // @ts-ignore
+  const city: string = props.user.address.city;
+  // @ts-ignore
+  const email: string = props.user.contact.email;
+
+  const save = async () => {
+    await fetch(props.url, { method: 'POST', body: JSON.stringify({ name }) });
+  };
+  save();
+
+  return (
+    <View style={{ padding: 16, margin: 8 }}>
+      <Text>{name} ({age}), {city}, {email}</Text>
+    </View>
+  );
+}
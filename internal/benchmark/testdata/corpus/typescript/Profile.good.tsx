@@ -0,0 +1,28 @@
+import React, { useEffect, useState } from 'react';
+import { StyleSheet, Text, View } from 'react-native';
+
+interface ProfileProps {
+  url: string;
+  age: number;
+}
+
+export function Profile({ url, age }: ProfileProps) {
+  const [name, setName] = useState('');
+
+  useEffect(() => {
+    fetch(url)
+      .then((res) => res.json())
+      .then((data) => setName(data.name))
+      .catch(() => setName(''));
+  }, [url]);
+
+  return (
+    <View style={styles.container}>
+      <Text>{name} ({age})</Text>
+    </View>
+  );
+}
+
+const styles = StyleSheet.create({
+  container: { padding: 16, margin: 8 },
+});
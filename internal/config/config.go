@@ -2,8 +2,12 @@ package config
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
 )
@@ -60,10 +64,18 @@ func NewFileError(code ErrorCode, message string, path string, line int, err err
 
 type ConfigLoader struct {
 	globalConfigPaths []string
+	profile           string   // overrides the profile setting of the configuration files
+	environ           []string // the environment, whose AGENTLINT_* variables override the files
+	cacheDir          string   // where fetched configurations are cached; "" caches none
+	httpClient        *http.Client
 }
 
 func NewConfigLoader() *ConfigLoader {
 	homeDir := os.Getenv("HOME")
+	cacheDir, err := os.UserCacheDir()
+	if err == nil {
+		cacheDir = filepath.Join(cacheDir, "agentlint", "config")
+	}
 	return &ConfigLoader{
 		globalConfigPaths: []string{
 			"/etc/agentlint.yaml",
@@ -72,6 +84,8 @@ func NewConfigLoader() *ConfigLoader {
 			homeDir + "/.agentlint.yml",
 			os.Getenv("AGENTLINT_CONFIG"),
 		},
+		environ:  os.Environ(),
+		cacheDir: cacheDir,
 	}
 }
 
@@ -123,10 +137,284 @@ func (c *ConfigLoader) LoadConfig(path string) (core.Config, error) {
 	return config, nil
 }
 
+// parseConfig decodes a configuration file onto config, failing on the first error
 func parseConfig(data []byte, config *core.Config) error {
+	_, diags, _ := decodeConfig("", data, config)
+	for _, diag := range diags {
+		if diag.Severity == core.SeverityError {
+			return fmt.Errorf("line %d: %s", diag.Line, diag.Message)
+		}
+	}
 	return nil
 }
 
+// projectConfigNames are the configuration files looked for in the current directory
+var projectConfigNames = []string{"agentlint.yaml", "agentlint.yml"}
+
+// LoadedConfig is the effective configuration built by LoadHierarchy
+type LoadedConfig struct {
+	Config      core.Config
+	Sources     []string           // configuration files and URLs applied over the defaults, global first
+	Settings    map[string]Setting // the file and line each key was last set on
+	Diagnostics []Diagnostic
+}
+
+// HasErrors reports whether a configuration file holds settings that could not be applied
+func (l *LoadedConfig) HasErrors() bool {
+	for _, diag := range l.Diagnostics {
+		if diag.Severity == core.SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// SetHTTPClient sets the client fetching the configurations that configuration files extend
+func (c *ConfigLoader) SetHTTPClient(client *http.Client) {
+	c.httpClient = client
+}
+
+// SetProfile selects the profile applied by LoadHierarchy, whatever the profile setting of
+// the configuration files; "" keeps their setting
+func (c *ConfigLoader) SetProfile(name string) {
+	c.profile = name
+}
+
+// LoadHierarchy applies the first global configuration file found and then the project's
+// onto the defaults. The project file is path when given, otherwise agentlint.yaml or
+// agentlint.yml in the current directory. A profile, named by SetProfile or the files, is
+// applied over the defaults first, so the files' own settings win over it. A file that
+// extends another configuration, local or fetched, is applied over it, and the AGENTLINT_*
+// environment variables are applied over the files. Problems in the
+// files are returned as diagnostics; settings naming rule IDs are checked against
+// knownRules unless it is nil.
+func (c *ConfigLoader) LoadHierarchy(path string, knownRules []string) (*LoadedConfig, error) {
+	loaded := &LoadedConfig{Config: DefaultConfig(), Settings: make(map[string]Setting)}
+
+	var files []string
+	for _, configPath := range c.globalConfigPaths {
+		if configPath == "" {
+			continue
+		}
+		if _, err := os.Stat(configPath); err == nil {
+			files = append(files, configPath)
+			break
+		}
+	}
+	if path != "" {
+		projectPath, err := c.FindConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, projectPath)
+	} else {
+		for _, name := range projectConfigNames {
+			if _, err := os.Stat(name); err == nil {
+				files = append(files, name)
+				break
+			}
+		}
+	}
+
+	var contents [][]byte
+	profiles := make(map[string]profileDef)
+	for i, file := range files {
+		if i > 0 && sameFile(files[0], file) {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, NewConfigError(ErrCodeConfigNotFound, "failed to read config file", file, err)
+		}
+		layers, diags := c.extendedLayers(file, data)
+		loaded.Diagnostics = append(loaded.Diagnostics, diags...)
+		for _, layer := range append(layers, configLayer{source: file, data: data}) {
+			contents = append(contents, layer.data)
+			settings, diags, defs := decodeConfig(layer.source, layer.data, &loaded.Config)
+			for name, def := range defs {
+				profiles[name] = def
+			}
+			loaded.Sources = append(loaded.Sources, layer.source)
+			loaded.Diagnostics = append(loaded.Diagnostics, diags...)
+			loaded.addSettings(settings)
+		}
+	}
+	settings, diags := decodeEnvironment(c.environ, &loaded.Config)
+	loaded.Diagnostics = append(loaded.Diagnostics, diags...)
+	loaded.addSettings(settings)
+
+	if c.profile != "" {
+		loaded.Config.Profile = c.profile
+	}
+	if profile := loaded.Config.Profile; profile != "" && profile != DefaultProfile {
+		// the files were checked above, so applying them again only repeats their diagnostics
+		config := DefaultConfig()
+		if err := applyProfile(profile, &config, profiles, nil); err != nil {
+			if c.profile != "" {
+				return nil, err
+			}
+			setting := loaded.Settings["profile"]
+			loaded.Diagnostics = append(loaded.Diagnostics, Diagnostic{Path: setting.Path, Line: setting.Line, Severity: core.SeverityError, Message: err.Error()})
+		} else {
+			for i, data := range contents {
+				decodeConfig(loaded.Sources[i], data, &config)
+			}
+			decodeEnvironment(c.environ, &config)
+			config.Profile = profile
+			loaded.Config = config
+		}
+	}
+
+	loaded.Diagnostics = append(loaded.Diagnostics, checkDisabledSections(loaded)...)
+	loaded.Diagnostics = append(loaded.Diagnostics, checkSuggestionTemplates(loaded)...)
+	if knownRules != nil {
+		loaded.Diagnostics = append(loaded.Diagnostics, checkRuleIDs(loaded, knownRules)...)
+	}
+	sortDiagnostics(loaded.Diagnostics, loaded.Sources)
+	return loaded, nil
+}
+
+// addSettings records the keys set by a file or the environment, noting those that override
+// a different value set earlier
+func (l *LoadedConfig) addSettings(settings map[string]Setting) {
+	for _, key := range sortedKeys(settings) {
+		setting := settings[key]
+		if previous, ok := l.Settings[key]; ok && previous.Value != setting.Value {
+			l.Diagnostics = append(l.Diagnostics, Diagnostic{Path: setting.Path, Line: setting.Line, Severity: core.SeverityInfo,
+				Message: fmt.Sprintf("%s overrides %s set at %s", key, previous.Value, previous.Location())})
+		}
+		l.Settings[key] = setting
+	}
+}
+
+// checkDisabledSections notes settings of rules that end up disabled, including the
+// per-language and test file overrides of those rules
+func checkDisabledSections(loaded *LoadedConfig) []Diagnostic {
+	rules := reflect.ValueOf(loaded.Config.Rules)
+	var diags []Diagnostic
+	for _, key := range sortedKeys(loaded.Settings) {
+		parts := strings.Split(key, ".")
+		for i := 0; i+2 < len(parts); i++ {
+			if parts[i] != "rules" || parts[i+2] == "enabled" {
+				continue
+			}
+			section := fieldByTag(rules, parts[i+1])
+			if !section.IsValid() {
+				break
+			}
+			if enabled := section.FieldByName("Enabled"); enabled.IsValid() && !enabled.Bool() {
+				setting := loaded.Settings[key]
+				diags = append(diags, Diagnostic{Path: setting.Path, Line: setting.Line, Severity: core.SeverityInfo,
+					Message: fmt.Sprintf("%s has no effect because rules.%s.enabled is false", key, parts[i+1])})
+			}
+			break
+		}
+	}
+	return diags
+}
+
+// checkSuggestionTemplates reports suggestion templates that do not parse, or that refer to
+// fields results do not have
+func checkSuggestionTemplates(loaded *LoadedConfig) []Diagnostic {
+	var diags []Diagnostic
+	for _, key := range sortedKeys(loaded.Settings) {
+		id, ok := strings.CutPrefix(key, "output.suggestions[")
+		if !ok {
+			continue
+		}
+		id = strings.TrimSuffix(id, "]")
+		if _, err := core.ParseSuggestionTemplate(id, loaded.Config.Output.Suggestions[id]); err != nil {
+			setting := loaded.Settings[key]
+			diags = append(diags, Diagnostic{Path: setting.Path, Line: setting.Line, Severity: core.SeverityError, Message: err.Error()})
+		}
+	}
+	return diags
+}
+
+// checkRuleIDs warns about settings naming rules that do not exist
+func checkRuleIDs(loaded *LoadedConfig, knownRules []string) []Diagnostic {
+	known := make(map[string]bool, len(knownRules))
+	for _, id := range knownRules {
+		known[id] = true
+	}
+
+	var diags []Diagnostic
+	unknown := func(key, id string) {
+		setting := loaded.Settings[key]
+		message := fmt.Sprintf("%s names unknown rule %q", key, id)
+		if suggestion := closestName(id, knownRules); suggestion != "" {
+			message += fmt.Sprintf(" (did you mean %s?)", suggestion)
+		}
+		diags = append(diags, Diagnostic{Path: setting.Path, Line: setting.Line, Severity: core.SeverityWarning, Message: message})
+	}
+	if _, ok := loaded.Settings["testFiles.disabledRules"]; ok {
+		for _, id := range loaded.Config.TestFiles.DisabledRules {
+			if !known[id] {
+				unknown("testFiles.disabledRules", id)
+			}
+		}
+	}
+	for _, key := range sortedKeys(loaded.Settings) {
+		if id, ok := strings.CutPrefix(key, "output.blocking["); ok && !known[strings.TrimSuffix(id, "]")] {
+			unknown(key, strings.TrimSuffix(id, "]"))
+		}
+		if id, ok := strings.CutPrefix(key, "output.suggestions["); ok && id != core.AnyRule+"]" && !known[strings.TrimSuffix(id, "]")] {
+			unknown(key, strings.TrimSuffix(id, "]"))
+		}
+	}
+	return diags
+}
+
+// fieldByTag returns the field of a struct with the given yaml tag, or the zero Value
+func fieldByTag(v reflect.Value, tag string) reflect.Value {
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Tag.Get("yaml") == tag {
+			return v.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+func sortedKeys(settings map[string]Setting) []string {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortDiagnostics orders diagnostics by file, in the order the files were applied, and line,
+// followed by those of environment variables
+func sortDiagnostics(diags []Diagnostic, sources []string) {
+	order := make(map[string]int, len(sources))
+	for i, source := range sources {
+		order[source] = i
+	}
+	rank := func(path string) int {
+		if i, ok := order[path]; ok {
+			return i
+		}
+		return len(sources) // environment variables apply after the files
+	}
+	sort.SliceStable(diags, func(i, j int) bool {
+		if a, b := rank(diags[i].Path), rank(diags[j].Path); a != b {
+			return a < b
+		}
+		if diags[i].Line != diags[j].Line {
+			return diags[i].Line < diags[j].Line
+		}
+		return diags[i].Path < diags[j].Path
+	})
+}
+
+// sameFile reports whether two paths name the same file
+func sameFile(a, b string) bool {
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
+
 type ConfigHierarchy struct {
 	defaults core.Config
 	global   core.Config
@@ -149,6 +437,9 @@ func (h *ConfigHierarchy) Merge() core.Config {
 	if h.global.Rules.FunctionSize.MaxLines > 0 {
 		config.Rules.FunctionSize.MaxLines = h.global.Rules.FunctionSize.MaxLines
 	}
+	if h.global.Rules.FunctionSize.Metric != "" {
+		config.Rules.FunctionSize.Metric = h.global.Rules.FunctionSize.Metric
+	}
 
 	if h.project.Rules.FunctionSize.Enabled {
 		config.Rules.FunctionSize.Enabled = h.project.Rules.FunctionSize.Enabled
@@ -156,6 +447,9 @@ func (h *ConfigHierarchy) Merge() core.Config {
 	if h.project.Rules.FunctionSize.MaxLines > 0 {
 		config.Rules.FunctionSize.MaxLines = h.project.Rules.FunctionSize.MaxLines
 	}
+	if h.project.Rules.FunctionSize.Metric != "" {
+		config.Rules.FunctionSize.Metric = h.project.Rules.FunctionSize.Metric
+	}
 
 	if h.cli.Rules.FunctionSize.Enabled {
 		config.Rules.FunctionSize.Enabled = h.cli.Rules.FunctionSize.Enabled
@@ -163,6 +457,41 @@ func (h *ConfigHierarchy) Merge() core.Config {
 	if h.cli.Rules.FunctionSize.MaxLines > 0 {
 		config.Rules.FunctionSize.MaxLines = h.cli.Rules.FunctionSize.MaxLines
 	}
+	if h.cli.Rules.FunctionSize.Metric != "" {
+		config.Rules.FunctionSize.Metric = h.cli.Rules.FunctionSize.Metric
+	}
+
+	for _, level := range []core.Config{h.global, h.project, h.cli} {
+		config.Language.Go.Rules = config.Language.Go.Rules.Merge(level.Language.Go.Rules)
+		config.Language.Python.Rules = config.Language.Python.Rules.Merge(level.Language.Python.Rules)
+		config.Language.ReactNative.Rules = config.Language.ReactNative.Rules.Merge(level.Language.ReactNative.Rules)
+		config.TestFiles.Rules = config.TestFiles.Rules.Merge(level.TestFiles.Rules)
+		if len(level.TestFiles.DisabledRules) > 0 {
+			config.TestFiles.DisabledRules = level.TestFiles.DisabledRules
+		}
+		config.AIFiles.Rules = config.AIFiles.Rules.Merge(level.AIFiles.Rules)
+		if len(level.AIFiles.Markers) > 0 {
+			config.AIFiles.Markers = level.AIFiles.Markers
+		}
+		if len(level.AIFiles.Paths) > 0 {
+			config.AIFiles.Paths = level.AIFiles.Paths
+		}
+		if level.Parsing.Tolerant {
+			config.Parsing.Tolerant = true
+		}
+		if level.Parsing.StreamThreshold > 0 {
+			config.Parsing.StreamThreshold = level.Parsing.StreamThreshold
+		}
+		if level.Parsing.MaxLineLength > 0 {
+			config.Parsing.MaxLineLength = level.Parsing.MaxLineLength
+		}
+		if len(level.Files.Include) > 0 {
+			config.Files.Include = level.Files.Include
+		}
+		if len(level.Files.Exclude) > 0 {
+			config.Files.Exclude = level.Files.Exclude
+		}
+	}
 
 	return config
 }
@@ -189,33 +518,126 @@ func DefaultConfig() core.Config {
 			FunctionSize: core.FunctionSizeConfig{
 				Enabled:  true,
 				MaxLines: 50,
+				Metric:   core.MetricLines,
 			},
 			FileSize: core.FileSizeConfig{
-				Enabled:  true,
-				MaxLines: 500,
+				Enabled:   true,
+				MaxLines:  500,
+				CountMode: core.CountTotal,
 			},
 			Overcommenting: core.OvercommentingConfig{
-				Enabled:          true,
-				MaxCommentRatio:  0.3,
-				CheckRedundant:   true,
-				CheckDocCoverage: true,
+				Enabled:            true,
+				MaxCommentRatio:    0.3,
+				CheckRedundant:     true,
+				CheckDocCoverage:   true,
+				RedundantThreshold: 0.6,
 			},
 			OrphanedCode: core.OrphanedCodeConfig{
-				Enabled:              true,
-				CheckUnusedFunctions: true,
-				CheckUnusedVariables: true,
-				CheckUnreachableCode: true,
-				CheckDeadImports:     true,
+				Enabled:                true,
+				CheckUnusedFunctions:   true,
+				CheckUnusedVariables:   true,
+				CheckUnreachableCode:   true,
+				CheckDeadImports:       true,
+				IgnoreFunctionPatterns: []string{"Test*", "Benchmark*", "Example*"},
+			},
+			TypeSize: core.TypeSizeConfig{
+				Enabled:       true,
+				MaxFields:     15,
+				MaxMethods:    20,
+				MaxClassLines: 500,
+			},
+			Returns: core.ReturnsConfig{
+				Enabled:             true,
+				MaxValues:           3,
+				NakedReturnMaxLines: 5,
+			},
+			AIComments: core.AICommentsConfig{
+				Enabled: true,
+			},
+			Docstrings: core.DocstringsConfig{
+				Enabled:           true,
+				CheckPlaceholders: true,
+				CheckParameters:   true,
+			},
+			Dependencies: core.DependenciesConfig{
+				Enabled:     true,
+				CheckCycles: true,
+				MaxDepth:    10,
+			},
+			TypeSafety: core.TypeSafetyConfig{
+				Enabled:         true,
+				MaxAny:          5,
+				MaxSuppressions: 2,
+			},
+			TypeHints: core.TypeHintsConfig{
+				Enabled:     false,
+				MinCoverage: 0.8,
+			},
+			Systemic: core.SystemicConfig{
+				Enabled:         true,
+				MaxFileFindings: 20,
+				MaxPackageRatio: 0.3,
+			},
+			Branches: core.BranchesConfig{
+				Enabled:     true,
+				MaxBranches: 10,
+			},
+			Concurrency: core.ConcurrencyConfig{
+				Enabled: true,
+			},
+			DuplicateErrors: core.DuplicateErrorsConfig{
+				Enabled:        true,
+				MinOccurrences: 3,
+				Threshold:      0.85,
+			},
+			Similarity: core.SimilarityConfig{
+				Enabled:       false,
+				Threshold:     0.8,
+				MinStatements: 5,
+				MinTokens:     10,
+			},
+			Layout: core.LayoutConfig{
+				Enabled:           true,
+				GenericPackages:   []string{"utils", "util", "helpers", "helper", "common", "misc"},
+				GenericFiles:      []string{"misc", "stuff", "things", "other", "temp", "tmp", "various", "extra"},
+				CheckStubFiles:    true,
+				CheckPackageNames: true,
+			},
+			Endpoints: core.EndpointsConfig{
+				Enabled: true,
+			},
+			TestQuality: core.TestQualityConfig{
+				Enabled:   true,
+				Threshold: 0.9,
+			},
+			Headers: core.HeadersConfig{
+				Enabled:   true,
+				MinFiles:  3,
+				Threshold: 0.7,
+			},
+			Components: core.ComponentsConfig{
+				Enabled:     true,
+				MaxHooks:    10,
+				MaxProps:    10,
+				MaxJSXDepth: 8,
 			},
 		},
 		Output: core.OutputConfig{
 			Format:  "console",
 			Verbose: false,
+			GroupBy: "file",
+			FailOn:  "info",
+
+			FailOnParseErrors: false,
 		},
 		Language: core.LanguageConfig{
 			Go: core.GoConfig{
 				IgnoreTests: false,
 			},
 		},
+		Parsing: core.ParsingConfig{
+			StreamThreshold: 4 << 20,
+			MaxLineLength:   64 << 10,
+		},
 	}
 }
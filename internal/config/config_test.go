@@ -0,0 +1,550 @@
+package config_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/config"
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// writeConfig writes a configuration file into dir and returns its path
+func writeConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// isolate points the global configuration lookup at an empty directory
+func isolate(t *testing.T) string {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("AGENTLINT_CONFIG", "")
+	return dir
+}
+
+func TestLoadHierarchy(t *testing.T) {
+	home := isolate(t)
+	writeConfig(t, home, ".agentlint.yaml", `
+rules:
+  functionSize:
+    maxLines: 80
+    metric: statements
+`)
+	project := writeConfig(t, t.TempDir(), "agentlint.yaml", `# project settings
+rules:
+  functionSize:
+    maxLines: 60   # overrides the global file
+  fileSize: {enabled: false, maxLines: 300}
+language:
+  python:
+    rules:
+      functionSize:
+        maxLines: 30
+  extensions:
+    ".mjs": javascript
+output:
+  format: json
+  blocking:
+    large-function: true
+testFiles:
+  disabledRules: [large-function]
+files:
+  exclude:
+    - "**/generated/**"
+    - vendor/
+`)
+
+	loaded, err := config.NewConfigLoader().LoadHierarchy(project, []string{"large-function"})
+	if err != nil {
+		t.Fatalf("LoadHierarchy failed: %v", err)
+	}
+	if loaded.HasErrors() {
+		t.Fatalf("unexpected errors: %v", loaded.Diagnostics)
+	}
+
+	cfg := loaded.Config
+	if cfg.Rules.FunctionSize.MaxLines != 60 || cfg.Rules.FunctionSize.Metric != core.MetricStatements || !cfg.Rules.FunctionSize.Enabled {
+		t.Errorf("expected functionSize merged from both files, got %+v", cfg.Rules.FunctionSize)
+	}
+	if cfg.Rules.FileSize.Enabled || cfg.Rules.FileSize.MaxLines != 300 || cfg.Rules.FileSize.CountMode != core.CountTotal {
+		t.Errorf("expected fileSize from the flow mapping over the defaults, got %+v", cfg.Rules.FileSize)
+	}
+	if cfg.Language.Python.Rules.FunctionSize.MaxLines != 30 || cfg.Language.Extensions[".mjs"] != "javascript" {
+		t.Errorf("expected language settings, got %+v", cfg.Language)
+	}
+	if cfg.Output.Format != "json" || !cfg.Output.Blocking["large-function"] || cfg.Output.FailOn != "info" {
+		t.Errorf("expected output settings over the defaults, got %+v", cfg.Output)
+	}
+	if !reflect.DeepEqual(cfg.Files.Exclude, []string{"**/generated/**", "vendor/"}) {
+		t.Errorf("expected exclude globs, got %v", cfg.Files.Exclude)
+	}
+	if !reflect.DeepEqual(cfg.Rules.OrphanedCode.IgnoreFunctionPatterns, []string{"Test*", "Benchmark*", "Example*"}) {
+		t.Errorf("expected defaults for keys no file sets, got %v", cfg.Rules.OrphanedCode.IgnoreFunctionPatterns)
+	}
+
+	if len(loaded.Sources) != 2 || loaded.Sources[1] != project {
+		t.Errorf("expected the global and project files, got %v", loaded.Sources)
+	}
+	if setting := loaded.Settings["rules.functionSize.metric"]; setting.Line != 5 || !strings.HasSuffix(setting.Path, ".agentlint.yaml") {
+		t.Errorf("expected metric set on line 5 of the global file, got %+v", setting)
+	}
+
+	var messages []string
+	for _, diag := range loaded.Diagnostics {
+		messages = append(messages, diag.String())
+	}
+	want := []string{
+		project + ":4: info: rules.functionSize.maxLines overrides 80 set at " + filepath.Join(home, ".agentlint.yaml") + ":4",
+		project + ":5: info: rules.fileSize.maxLines has no effect because rules.fileSize.enabled is false",
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("diagnostics:\n got %q\nwant %q", messages, want)
+	}
+}
+
+func TestLoadHierarchyDiagnostics(t *testing.T) {
+	isolate(t)
+	project := writeConfig(t, t.TempDir(), "agentlint.yaml", `rules:
+  functionSize:
+    maxLine: 60
+    metric: statement
+  fileSize:
+    maxLines: "100"
+  typeHints:
+    minCoverage: 1.5
+  overcommenting:
+    enabled: yes
+  rules: {}
+output:
+  failOn: warnings
+  blocking:
+    larg-function: true
+  format: json
+  format: csv
+  suggestions:
+    large-function: "{{.Advice}}"
+    larg-function: See CONTRIBUTING.md
+    "*": "{{.Suggestion}}, see CONTRIBUTING.md"
+language:
+  extensions: {.foo: cobol}
+testFiles:
+  disabledRules: large-function
+rulez: {}
+`)
+
+	loaded, err := config.NewConfigLoader().LoadHierarchy(project, []string{"large-function"})
+	if err != nil {
+		t.Fatalf("LoadHierarchy failed: %v", err)
+	}
+
+	want := []string{
+		"3: error: unknown key rules.functionSize.maxLine (did you mean rules.functionSize.maxLines?)",
+		"4: error: rules.functionSize.metric must be one of lines, statements, found \"statement\"",
+		"6: error: rules.fileSize.maxLines must be a whole number, found \"100\"",
+		"8: error: rules.typeHints.minCoverage must be between 0.0 and 1.0, found 1.5",
+		"10: error: rules.overcommenting.enabled must be true or false, found \"yes\"",
+		"11: error: unknown key rules.rules",
+		"13: error: output.failOn must be one of error, warning, info, none, found \"warnings\"",
+		"15: warning: output.blocking[larg-function] names unknown rule \"larg-function\" (did you mean large-function?)",
+		"17: error: output.format is set twice, first on line 16",
+		"19: error: invalid suggestion template for large-function: template: large-function:1:2: executing \"large-function\" at <.Advice>: can't evaluate field Advice in type core.Result",
+		"20: warning: output.suggestions[larg-function] names unknown rule \"larg-function\" (did you mean large-function?)",
+		"23: error: language.extensions[.foo] names unknown language \"cobol\"",
+		"25: error: testFiles.disabledRules must be a list; write [large-function] for a single entry",
+		"26: error: unknown key rulez (did you mean rules?)",
+	}
+	var got []string
+	for _, diag := range loaded.Diagnostics {
+		got = append(got, strings.TrimPrefix(diag.String(), project+":"))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diagnostics:\n got %q\nwant %q", got, want)
+	}
+	if !loaded.HasErrors() {
+		t.Error("expected HasErrors to report the errors")
+	}
+	if loaded.Config.Rules.FunctionSize.Metric != core.MetricLines || loaded.Config.Output.FailOn != "info" {
+		t.Errorf("expected invalid settings to keep their defaults, got %+v", loaded.Config)
+	}
+}
+
+func TestLoadHierarchySyntaxErrors(t *testing.T) {
+	isolate(t)
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"rules:\n\tfunctionSize: {}\n", ":2: error: tabs cannot be used for indentation"},
+		{"output:\n  template: |\n    {{.Message}}\n", ":2: error: multi-line strings are not supported"},
+		{"files:\n  include:\n    - path: src\n", ":3: error: mappings inside lists are not supported"},
+		{"files:\n  include: [src, lib\n", ":2: error: flow collections must be closed on the line they start"},
+		{"rules:\n  functionSize:\n      maxLines: 10\n    metric: lines\n", ":4: error: unexpected indentation"},
+	}
+	for _, tt := range tests {
+		path := writeConfig(t, t.TempDir(), "agentlint.yaml", tt.content)
+		loaded, err := config.NewConfigLoader().LoadHierarchy(path, nil)
+		if err != nil {
+			t.Fatalf("LoadHierarchy failed: %v", err)
+		}
+		if len(loaded.Diagnostics) != 1 || loaded.Diagnostics[0].String() != path+tt.want {
+			t.Errorf("%q: expected %s, got %v", tt.content, tt.want, loaded.Diagnostics)
+		}
+	}
+
+	if _, err := config.NewConfigLoader().LoadHierarchy(filepath.Join(t.TempDir(), "missing.yaml"), nil); err == nil {
+		t.Error("expected an error for a missing configuration file")
+	}
+}
+
+func TestWriteEffective(t *testing.T) {
+	isolate(t)
+	dir := t.TempDir()
+	project := writeConfig(t, dir, "agentlint.yaml", `rules:
+  aiComments:
+    phrases: ["it's worth noting", "as an AI"]
+output:
+  template: "{{.FilePath}}: {{.Message}}"
+  blocking: {"deep-dependency-chain": false}
+language:
+  extensions: {".mjs": javascript}
+`)
+	loaded, err := config.NewConfigLoader().LoadHierarchy(project, nil)
+	if err != nil || loaded.HasErrors() {
+		t.Fatalf("LoadHierarchy failed: %v %v", err, loaded.Diagnostics)
+	}
+
+	var out strings.Builder
+	if err := loaded.WriteEffective(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `    phrases: ["it's worth noting", "as an AI"]  # `+project+":3\n") {
+		t.Errorf("expected phrases annotated with their file and line, got:\n%s", out.String())
+	}
+
+	// the effective configuration reads back as the same configuration
+	effective := writeConfig(t, dir, "effective.yaml", out.String())
+	reloaded, err := config.NewConfigLoader().LoadHierarchy(effective, nil)
+	if err != nil || reloaded.HasErrors() {
+		t.Fatalf("reloading the effective configuration failed: %v %v", err, reloaded.Diagnostics)
+	}
+	if !reflect.DeepEqual(reloaded.Config, loaded.Config) {
+		t.Errorf("effective configuration did not round-trip:\n got %+v\nwant %+v", reloaded.Config, loaded.Config)
+	}
+}
+
+func TestLoadHierarchyProfiles(t *testing.T) {
+	isolate(t)
+	for _, name := range config.ProfileNames() {
+		loader := config.NewConfigLoader()
+		loader.SetProfile(name)
+		loaded, err := loader.LoadHierarchy("", nil)
+		if err != nil || loaded.HasErrors() {
+			t.Fatalf("profile %s failed: %v %v", name, err, loaded.Diagnostics)
+		}
+		defaults := config.DefaultConfig()
+		defaults.Profile = name
+		if name == config.DefaultProfile && !reflect.DeepEqual(loaded.Config, defaults) {
+			t.Errorf("expected the default profile to keep the defaults, got %+v", loaded.Config)
+		}
+	}
+
+	project := writeConfig(t, t.TempDir(), "agentlint.yaml", `profile: relaxed
+profiles:
+  team:
+    extends: strict
+    rules:
+      functionSize:
+        maxLines: 40
+rules:
+  fileSize:
+    maxLines: 200
+`)
+	tests := []struct {
+		profile       string
+		funcMaxLines  int
+		typeHints     bool
+		systemic      bool
+		wantedProfile string
+	}{
+		{"", 100, false, false, "relaxed"}, // set by the file
+		{"strict", 30, true, true, "strict"},
+		{"team", 40, true, true, "team"},
+		{"default", 50, false, true, "default"},
+	}
+	for _, tt := range tests {
+		loader := config.NewConfigLoader()
+		loader.SetProfile(tt.profile)
+		loaded, err := loader.LoadHierarchy(project, nil)
+		if err != nil || loaded.HasErrors() {
+			t.Fatalf("profile %q failed: %v %v", tt.profile, err, loaded.Diagnostics)
+		}
+		cfg := loaded.Config
+		if cfg.Profile != tt.wantedProfile || cfg.Rules.FunctionSize.MaxLines != tt.funcMaxLines ||
+			cfg.Rules.TypeHints.Enabled != tt.typeHints || cfg.Rules.Systemic.Enabled != tt.systemic {
+			t.Errorf("profile %q: got %s with functionSize %+v, typeHints %v, systemic %v", tt.profile,
+				cfg.Profile, cfg.Rules.FunctionSize, cfg.Rules.TypeHints.Enabled, cfg.Rules.Systemic.Enabled)
+		}
+		if cfg.Rules.FileSize.MaxLines != 200 {
+			t.Errorf("profile %q: expected the file's own setting to win, got %+v", tt.profile, cfg.Rules.FileSize)
+		}
+	}
+
+	loader := config.NewConfigLoader()
+	loader.SetProfile("stricter")
+	if _, err := loader.LoadHierarchy(project, nil); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+}
+
+func TestLoadHierarchyProfileErrors(t *testing.T) {
+	isolate(t)
+	project := writeConfig(t, t.TempDir(), "agentlint.yaml", `profile: loop
+profiles:
+  strict:
+    rules: {}
+  loop:
+    extends: other
+  other:
+    extends: loop
+    rules:
+      functionSize:
+        maxLine: 10
+`)
+	loaded, err := config.NewConfigLoader().LoadHierarchy(project, nil)
+	if err != nil {
+		t.Fatalf("LoadHierarchy failed: %v", err)
+	}
+	want := []string{
+		"1: error: profile loop extends itself: loop extends other extends loop",
+		"3: error: profiles.strict redefines a bundled profile; give it another name and extend strict",
+		"11: error: unknown key rules.functionSize.maxLine (did you mean rules.functionSize.maxLines?)",
+	}
+	var got []string
+	for _, diag := range loaded.Diagnostics {
+		got = append(got, strings.TrimPrefix(diag.String(), project+":"))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diagnostics:\n got %q\nwant %q", got, want)
+	}
+	if loaded.Config.Rules.FunctionSize.MaxLines != 50 {
+		t.Errorf("expected a profile that cannot be applied to keep the defaults, got %+v", loaded.Config.Rules.FunctionSize)
+	}
+}
+
+func TestLoadHierarchyInterpolation(t *testing.T) {
+	isolate(t)
+	t.Setenv("AGENTLINT_FUNC_MAX", "60")
+	t.Setenv("AGENTLINT_FAIL_ON", "")
+	t.Setenv("AGENTLINT_TEAM", "payments")
+	t.Setenv("AGENTLINT_WORKER_TOKEN", "worker-secret")
+	t.Setenv("DEPLOY_TOKEN", "deploy-secret")
+	project := writeConfig(t, t.TempDir(), "agentlint.yaml", `rules:
+  functionSize:
+    maxLines: ${AGENTLINT_FUNC_MAX:-50}
+  fileSize:
+    maxLines: "${AGENTLINT_FILE_MAX:-400}"
+output:
+  failOn: ${AGENTLINT_FAIL_ON:-warning}
+  template: "$${{.FilePath}} ${AGENTLINT_TEAM}"
+  skipCategories: [style, "${AGENTLINT_EXTRA_CATEGORY-}"]
+  codeowners: ${AGENTLINT_MISSING_OWNERS}
+  coverage: ${DEPLOY_TOKEN}
+  metricsFile: ${AGENTLINT_WORKER_TOKEN}
+  notify:
+    webhook: https://hooks.example.com/${AGENTLINT_TEAM}
+`)
+	loaded, err := config.NewConfigLoader().LoadHierarchy(project, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := loaded.Config
+	if cfg.Rules.FunctionSize.MaxLines != 60 || cfg.Rules.FileSize.MaxLines != 400 {
+		t.Errorf("Expected the variable and the default, got %d and %d", cfg.Rules.FunctionSize.MaxLines, cfg.Rules.FileSize.MaxLines)
+	}
+	if cfg.Output.FailOn != "warning" {
+		t.Errorf("Expected the default for an empty variable, got %q", cfg.Output.FailOn)
+	}
+	if cfg.Output.Template != "${{.FilePath}} payments" {
+		t.Errorf("Expected $$ kept as $, got %q", cfg.Output.Template)
+	}
+	if !reflect.DeepEqual(cfg.Output.SkipCategories, []string{"style", ""}) {
+		t.Errorf("Expected list items interpolated, got %q", cfg.Output.SkipCategories)
+	}
+	want := []string{
+		project + ":10: error: environment variable AGENTLINT_MISSING_OWNERS is not set",
+		project + ":11: error: ${DEPLOY_TOKEN} cannot be referenced; only AGENTLINT_* variables that are not reserved can",
+		project + ":12: error: ${AGENTLINT_WORKER_TOKEN} cannot be referenced; only AGENTLINT_* variables that are not reserved can",
+		project + ":14: error: output.notify.webhook cannot refer to environment variables",
+	}
+	var got []string
+	for _, diag := range loaded.Diagnostics {
+		got = append(got, diag.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diagnostics:\n got %q\nwant %q", got, want)
+	}
+
+	var effective bytes.Buffer
+	if err := loaded.WriteEffective(&effective); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(effective.String(), "secret") {
+		t.Errorf("Expected no variable outside AGENTLINT_* in the effective configuration, got:\n%s", effective.String())
+	}
+}
+
+func TestLoadHierarchyEnvironment(t *testing.T) {
+	isolate(t)
+	project := writeConfig(t, t.TempDir(), "agentlint.yaml", `rules:
+  functionSize:
+    maxLines: 80
+`)
+	t.Setenv("AGENTLINT_RULES_FUNCTIONSIZE_MAXLINES", "40")
+	t.Setenv("AGENTLINT_OUTPUT_SKIPCATEGORIES", "style, documentation")
+	t.Setenv("AGENTLINT_PROFILE", "strict")
+	t.Setenv("AGENTLINT_OUTPUT_FAILON", "")
+	t.Setenv("AGENTLINT_RULES_FILESIZE_MAXLINE", "350")
+	t.Setenv("AGENTLINT_RULES_TYPEHINTS_MINCOVERAGE", "2")
+	t.Setenv("AGENTLINT_OUTPUT_BLOCKING", "large-function")
+	t.Setenv("AGENTLINT_CACHE_DIR", "/tmp/cache") // not a setting
+
+	loaded, err := config.NewConfigLoader().LoadHierarchy(project, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := loaded.Config
+	if cfg.Rules.FunctionSize.MaxLines != 40 || cfg.Profile != "strict" || cfg.Rules.FileSize.MaxLines != 300 { // strict's, as the misspelled key is ignored
+		t.Errorf("Expected the environment over the file and the strict profile, got %+v, %+v and %q", cfg.Rules.FunctionSize, cfg.Rules.FileSize, cfg.Profile)
+	}
+	if !reflect.DeepEqual(cfg.Output.SkipCategories, []string{"style", "documentation"}) || cfg.Output.FailOn != "info" {
+		t.Errorf("Expected the list split and the empty variable ignored, got %q and %q", cfg.Output.SkipCategories, cfg.Output.FailOn)
+	}
+
+	var messages []string
+	for _, diag := range loaded.Diagnostics {
+		messages = append(messages, diag.String())
+	}
+	want := []string{
+		"$AGENTLINT_OUTPUT_BLOCKING: warning: output.blocking cannot be set from the environment",
+		"$AGENTLINT_RULES_FILESIZE_MAXLINE: warning: unknown key rules.fileSize.maxline (did you mean rules.fileSize.maxLines?)",
+		"$AGENTLINT_RULES_FUNCTIONSIZE_MAXLINES: info: rules.functionSize.maxLines overrides 80 set at " + project + ":3",
+		"$AGENTLINT_RULES_TYPEHINTS_MINCOVERAGE: error: rules.typeHints.minCoverage must be between 0.0 and 1.0, found 2",
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("diagnostics:\n got %q\nwant %q", messages, want)
+	}
+
+	var out strings.Builder
+	if err := loaded.WriteEffective(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "    maxLines: 40  # $AGENTLINT_RULES_FUNCTIONSIZE_MAXLINES\n") {
+		t.Errorf("Expected the setting annotated with its variable, got:\n%s", out.String())
+	}
+}
+
+// rewriteTransport sends every request to a test server, recording the URLs asked for
+type rewriteTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+	asked  []string
+}
+
+func (r *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.asked = append(r.asked, req.URL.String())
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = r.target.Scheme, r.target.Host
+	return r.next.RoundTrip(req)
+}
+
+func TestLoadHierarchyExtends(t *testing.T) {
+	isolate(t)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("AGENTLINT_TEAM", "payments")
+	policy := "rules:\n  functionSize:\n    maxLines: 40\n  fileSize:\n    maxLines: 300\noutput:\n  failOn: warning\n  codeowners: ${AGENTLINT_TEAM}\n"
+	sum := sha256.Sum256([]byte(policy))
+	checksum := hex.EncodeToString(sum[:])
+	up := true
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up || r.URL.Path != "/org/lint-config/v2/agentlint.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(policy))
+	}))
+	defer server.Close()
+	target, _ := url.Parse(server.URL)
+	transport := &rewriteTransport{target: target, next: server.Client().Transport}
+
+	dir := t.TempDir()
+	writeConfig(t, dir, "team.yaml", "extends:\n  url: github.com/org/lint-config/agentlint.yaml@v2\n  sha256: "+checksum+"\nrules:\n  fileSize:\n    maxLines: 350\n")
+	project := writeConfig(t, dir, "agentlint.yaml", "extends: team.yaml\nrules:\n  functionSize:\n    maxLines: 45\n")
+	load := func() *config.LoadedConfig {
+		t.Helper()
+		loader := config.NewConfigLoader()
+		loader.SetHTTPClient(&http.Client{Transport: transport})
+		loaded, err := loader.LoadHierarchy(project, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return loaded
+	}
+
+	remote := "https://raw.githubusercontent.com/org/lint-config/v2/agentlint.yaml"
+	loaded := load()
+	if loaded.HasErrors() {
+		t.Fatalf("Unexpected diagnostics %v", loaded.Diagnostics)
+	}
+	if !strings.Contains(loaded.Diagnostics[0].String(), remote+":8: warning: environment variables are not expanded in fetched configurations") {
+		t.Errorf("Expected a warning for the reference in the fetched configuration, got %v", loaded.Diagnostics)
+	}
+	cfg := loaded.Config
+	if cfg.Output.Codeowners != "${AGENTLINT_TEAM}" {
+		t.Errorf("Expected the reference in the fetched configuration left as written, got %q", cfg.Output.Codeowners)
+	}
+	if cfg.Rules.FunctionSize.MaxLines != 45 || cfg.Rules.FileSize.MaxLines != 350 || cfg.Output.FailOn != "warning" {
+		t.Errorf("Expected each file over the one it extends, got %d, %d and %q", cfg.Rules.FunctionSize.MaxLines, cfg.Rules.FileSize.MaxLines, cfg.Output.FailOn)
+	}
+	want := []string{remote, filepath.Join(dir, "team.yaml"), project}
+	if !reflect.DeepEqual(loaded.Sources, want) || !reflect.DeepEqual(transport.asked, []string{remote}) {
+		t.Errorf("Expected the shared configuration fetched from GitHub and applied first, got %q, asked %q", loaded.Sources, transport.asked)
+	}
+
+	// a pinned configuration is served from the cache
+	up = false
+	if loaded := load(); loaded.HasErrors() || len(transport.asked) != 1 || loaded.Config.Output.FailOn != "warning" {
+		t.Errorf("Expected the cached configuration without fetching it again, got %v, asked %q", loaded.Diagnostics, transport.asked)
+	}
+
+	// a checksum that does not match stops the run
+	writeConfig(t, dir, "team.yaml", "extends:\n  url: github.com/org/lint-config/agentlint.yaml@v2\n  sha256: "+strings.Repeat("0", 64)+"\n")
+	up = true
+	loaded = load()
+	if !loaded.HasErrors() || !strings.Contains(loaded.Diagnostics[0].String(), "team.yaml:1: error: extends "+remote+": checksum mismatch") {
+		t.Errorf("Expected a checksum mismatch, got %v", loaded.Diagnostics)
+	}
+
+	// configurations extending each other are refused
+	writeConfig(t, dir, "team.yaml", "extends: agentlint.yaml\n")
+	if loaded := load(); !loaded.HasErrors() || !strings.Contains(loaded.Diagnostics[0].Message, "which extends this configuration") {
+		t.Errorf("Expected a cycle to be refused, got %v", loaded.Diagnostics)
+	}
+
+	writeConfig(t, dir, "team.yaml", "extends: http://example.com/agentlint.yaml\n")
+	if loaded := load(); !loaded.HasErrors() || !strings.Contains(loaded.Diagnostics[0].Message, "only fetched over https") {
+		t.Errorf("Expected plain HTTP to be refused, got %v", loaded.Diagnostics)
+	}
+}
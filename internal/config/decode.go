@@ -0,0 +1,390 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+)
+
+// Diagnostic is a problem found in a configuration file
+type Diagnostic struct {
+	Path     string
+	Line     int
+	Severity core.Severity // error for settings that cannot be applied, warning and info otherwise
+	Message  string
+}
+
+// String formats the diagnostic as path:line: severity: message
+func (d Diagnostic) String() string {
+	if d.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s: %s", d.Path, d.Line, d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", d.Path, d.Severity, d.Message)
+}
+
+// Setting records where a configuration key was set
+type Setting struct {
+	Path  string // the file, or $NAME for an environment variable
+	Line  int    // 0 for an environment variable
+	Value string // the value as written to the effective configuration
+}
+
+// Location names where a setting was made: path:line in a file, or the environment variable
+func (s Setting) Location() string {
+	if s.Line > 0 {
+		return fmt.Sprintf("%s:%d", s.Path, s.Line)
+	}
+	return s.Path
+}
+
+// enumValues are the values accepted by string settings, by setting type or by key
+var enumValues = map[string][]string{
+	"FunctionSizeMetric":   {string(core.MetricLines), string(core.MetricStatements)},
+	"FileSizeCountMode":    {string(core.CountTotal), string(core.CountCode)},
+	"output.format":        {"console", "json", "csv", "tsv", "rdjsonl", "template"},
+	"output.groupBy":       {"file", "rule"},
+	"output.failOn":        {"error", "warning", "info", "none"},
+	"output.minConfidence": {string(core.ConfidenceHigh), string(core.ConfidenceMedium), string(core.ConfidenceLow)},
+	"output.severity":      {string(core.SeverityError), string(core.SeverityWarning), string(core.SeverityInfo)},
+	"output.notify.format": {"slack", "teams"},
+}
+
+// decoder applies a parsed configuration file onto a core.Config. Only the keys present in
+// the file are changed: maps are merged entry by entry and lists are replaced.
+type decoder struct {
+	path     string
+	diags    []Diagnostic
+	settings map[string]Setting
+	profiles map[string]profileDef // defined in the profiles section
+}
+
+// decodeConfig decodes a configuration file onto config and returns the keys it set and the
+// profiles it defines. References to environment variables are only expanded in local
+// files: a fetched configuration is written by someone else and must not read the
+// environment of the run.
+func decodeConfig(path string, data []byte, config *core.Config) (map[string]Setting, []Diagnostic, map[string]profileDef) {
+	d := &decoder{path: path, settings: make(map[string]Setting), profiles: make(map[string]profileDef)}
+	root, err := parseYAML(data)
+	if err != nil {
+		line := 0
+		if yerr, ok := err.(*yamlError); ok {
+			line, err = yerr.line, fmt.Errorf("%s", yerr.msg)
+		}
+		d.report(line, core.SeverityError, "%v", err)
+		return d.settings, d.diags, d.profiles
+	}
+	if root.kind != mappingNode {
+		d.report(root.line, core.SeverityError, "expected a mapping of configuration sections")
+		return d.settings, d.diags, d.profiles
+	}
+	if strings.HasPrefix(path, "https://") {
+		d.reportReferences(root)
+	} else {
+		d.interpolateNode(root, "")
+	}
+	d.decode(root, reflect.ValueOf(config).Elem(), "")
+	return d.settings, d.diags, d.profiles
+}
+
+func (d *decoder) report(line int, severity core.Severity, format string, args ...any) {
+	d.diags = append(d.diags, Diagnostic{Path: d.path, Line: line, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// mapKey names an entry of the map at key, such as output.blocking[large-function]
+func mapKey(key, entry string) string {
+	return key + "[" + entry + "]"
+}
+
+// decode stores node into v, the field at key
+func (d *decoder) decode(node *yamlNode, v reflect.Value, key string) {
+	switch v.Kind() {
+	case reflect.Struct:
+		d.decodeStruct(node, v, key)
+	case reflect.Map:
+		d.decodeMap(node, v, key)
+	default:
+		if node.null {
+			d.report(node.line, core.SeverityWarning, "%s has no value and is ignored", key)
+			return
+		}
+		if v.Kind() == reflect.Slice {
+			d.decodeList(node, v, key)
+		} else if !d.decodeScalar(node, v, key) {
+			return
+		}
+		d.settings[key] = Setting{Path: d.path, Line: node.line, Value: formatValue(v)}
+	}
+}
+
+func (d *decoder) decodeStruct(node *yamlNode, v reflect.Value, key string) {
+	if node.null {
+		return
+	}
+	if node.kind != mappingNode {
+		d.report(node.line, core.SeverityError, "%s must be a mapping", sectionName(key))
+		return
+	}
+
+	fields := make(map[string]int)
+	var names []string
+	for i := 0; i < v.NumField(); i++ {
+		if name := v.Type().Field(i).Tag.Get("yaml"); name != "" {
+			fields[name] = i
+			names = append(names, name)
+		}
+	}
+	if key == "" {
+		names = append(names, "profiles", "extends")
+	}
+
+	seen := make(map[string]int)
+	for _, entry := range node.entries {
+		fieldKey := joinKey(key, entry.key)
+		if line, ok := seen[entry.key]; ok {
+			d.report(entry.line, core.SeverityError, "%s is set twice, first on line %d", fieldKey, line)
+			continue
+		}
+		seen[entry.key] = entry.line
+
+		if key == "" && entry.key == "profiles" {
+			d.decodeProfiles(entry.value)
+			continue
+		}
+		if key == "" && entry.key == "extends" {
+			continue // applied by LoadHierarchy before the file, see findExtends
+		}
+		i, ok := fields[entry.key]
+		if !ok {
+			message := fmt.Sprintf("unknown key %s", fieldKey)
+			if suggestion := closestName(entry.key, names); suggestion != "" {
+				message += fmt.Sprintf(" (did you mean %s?)", joinKey(key, suggestion))
+			}
+			d.report(entry.line, core.SeverityError, "%s", message)
+			continue
+		}
+		d.decode(entry.value, v.Field(i), fieldKey)
+	}
+}
+
+func (d *decoder) decodeMap(node *yamlNode, v reflect.Value, key string) {
+	if node.null {
+		return
+	}
+	if node.kind != mappingNode {
+		d.report(node.line, core.SeverityError, "%s must be a mapping", key)
+		return
+	}
+	if v.IsNil() && len(node.entries) > 0 { // an empty mapping leaves the map nil, as writing it out found it
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+	seen := make(map[string]int)
+	for _, entry := range node.entries {
+		entryKey := mapKey(key, entry.key)
+		if line, ok := seen[entry.key]; ok {
+			d.report(entry.line, core.SeverityError, "%s is set twice, first on line %d", entryKey, line)
+			continue
+		}
+		seen[entry.key] = entry.line
+
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if entry.value.null {
+			d.report(entry.line, core.SeverityWarning, "%s has no value and is ignored", entryKey)
+			continue
+		}
+		if !d.decodeScalar(entry.value, elem, entryKey) {
+			continue
+		}
+		v.SetMapIndex(reflect.ValueOf(entry.key), elem)
+		d.settings[entryKey] = Setting{Path: d.path, Line: entry.line, Value: formatValue(elem)}
+	}
+}
+
+func (d *decoder) decodeList(node *yamlNode, v reflect.Value, key string) {
+	switch node.kind {
+	case scalarNode:
+		d.report(node.line, core.SeverityError, "%s must be a list; write [%s] for a single entry", key, node.value)
+		return
+	case mappingNode:
+		d.report(node.line, core.SeverityError, "%s must be a list, not a mapping", key)
+		return
+	}
+	list := reflect.Zero(v.Type())
+	for _, item := range node.items {
+		if item.kind != scalarNode || item.null {
+			d.report(item.line, core.SeverityError, "%s must be a list of strings", key)
+			return
+		}
+		list = reflect.Append(list, reflect.ValueOf(item.value).Convert(v.Type().Elem()))
+	}
+	v.Set(list)
+}
+
+// decodeScalar stores a scalar node into v and reports whether it was valid
+func (d *decoder) decodeScalar(node *yamlNode, v reflect.Value, key string) bool {
+	if node.kind != scalarNode {
+		d.report(node.line, core.SeverityError, "%s must be a %s, not a %s", key, kindName(v.Kind()), nodeName(node.kind))
+		return false
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		value := strings.ToLower(node.value)
+		if node.quoted || (value != "true" && value != "false") {
+			d.report(node.line, core.SeverityError, "%s must be true or false, found %q", key, node.value)
+			return false
+		}
+		v.SetBool(value == "true")
+	case reflect.Int:
+		value, err := strconv.Atoi(node.value)
+		if err != nil || node.quoted {
+			d.report(node.line, core.SeverityError, "%s must be a whole number, found %q", key, node.value)
+			return false
+		}
+		if value < 0 {
+			d.report(node.line, core.SeverityError, "%s cannot be negative, found %d", key, value)
+			return false
+		}
+		v.SetInt(int64(value))
+	case reflect.Float64:
+		value, err := strconv.ParseFloat(node.value, 64)
+		if err != nil || node.quoted || math.IsNaN(value) || math.IsInf(value, 0) {
+			d.report(node.line, core.SeverityError, "%s must be a number, found %q", key, node.value)
+			return false
+		}
+		if value < 0 || value > 1 {
+			d.report(node.line, core.SeverityError, "%s must be between 0.0 and 1.0, found %v", key, value)
+			return false
+		}
+		v.SetFloat(value)
+	case reflect.String:
+		if !d.checkEnum(node, v.Type(), key) {
+			return false
+		}
+		v.SetString(node.value)
+	default:
+		d.report(node.line, core.SeverityError, "%s cannot be set from a configuration file", key)
+		return false
+	}
+	return true
+}
+
+// checkEnum reports whether a string setting holds one of its accepted values. Empty values
+// are accepted, since per-language overrides use them to inherit the global setting.
+func (d *decoder) checkEnum(node *yamlNode, typ reflect.Type, key string) bool {
+	if node.value == "" {
+		return true
+	}
+	if strings.HasPrefix(key, "language.extensions[") {
+		if _, ok := languages.LanguageExtension(node.value); !ok {
+			d.report(node.line, core.SeverityError, "%s names unknown language %q", key, node.value)
+			return false
+		}
+		return true
+	}
+
+	allowed, ok := enumValues[key]
+	if !ok {
+		allowed = enumValues[typ.Name()]
+	}
+	if allowed == nil {
+		return true
+	}
+	for _, value := range allowed {
+		if node.value == value {
+			return true
+		}
+	}
+	d.report(node.line, core.SeverityError, "%s must be one of %s, found %q", key, strings.Join(allowed, ", "), node.value)
+	return false
+}
+
+// sectionName names the section at key in messages
+func sectionName(key string) string {
+	if key == "" {
+		return "the configuration"
+	}
+	return key
+}
+
+func kindName(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int:
+		return "whole number"
+	case reflect.Float64:
+		return "number"
+	}
+	return "string"
+}
+
+func nodeName(kind nodeKind) string {
+	if kind == mappingNode {
+		return "mapping"
+	}
+	return "list"
+}
+
+// formatValue formats a setting as it is written to the effective configuration
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatValue(v.Index(i))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// closestName returns the name closest to key, when it is within two edits
+func closestName(key string, names []string) string {
+	best, bestDistance := "", 3
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		if distance := editDistance(strings.ToLower(key), strings.ToLower(name)); distance < bestDistance {
+			best, bestDistance = name, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
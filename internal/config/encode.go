@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// WriteEffective writes the configuration as YAML, in the order of the configuration schema.
+// Values set by a configuration file are followed by a comment naming the file and line.
+func (l *LoadedConfig) WriteEffective(w io.Writer) error {
+	e := &encoder{w: w, settings: l.Settings}
+	e.encodeStruct(reflect.ValueOf(l.Config), "", 0)
+	return e.err
+}
+
+type encoder struct {
+	w        io.Writer
+	settings map[string]Setting
+	err      error
+}
+
+func (e *encoder) line(indent int, key, value, fullKey string) {
+	if e.err != nil {
+		return
+	}
+	text := strings.Repeat("  ", indent) + key + ":"
+	if value != "" {
+		text += " " + value
+	}
+	if setting, ok := e.settings[fullKey]; ok {
+		text += "  # " + setting.Location()
+	}
+	_, e.err = fmt.Fprintln(e.w, text)
+}
+
+func (e *encoder) encodeStruct(v reflect.Value, prefix string, indent int) {
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Tag.Get("yaml")
+		if name == "" {
+			continue
+		}
+		key := joinKey(prefix, name)
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.Struct:
+			e.line(indent, name, "", key)
+			e.encodeStruct(field, key, indent+1)
+		case reflect.Map:
+			if field.Len() == 0 {
+				e.line(indent, name, "{}", key)
+				continue
+			}
+			e.line(indent, name, "", key)
+			keys := make([]string, 0, field.Len())
+			for _, k := range field.MapKeys() {
+				keys = append(keys, k.String())
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				e.line(indent+1, formatKey(k), formatValue(field.MapIndex(reflect.ValueOf(k))), mapKey(key, k))
+			}
+		default:
+			e.line(indent, name, formatValue(field), key)
+		}
+	}
+}
+
+// formatKey quotes a map key that would not read back as a plain scalar
+func formatKey(key string) string {
+	if key == "" || strings.ContainsAny(key, ":#{}[],&*!|>'\"%@`") || strings.TrimSpace(key) != key {
+		return fmt.Sprintf("%q", key)
+	}
+	return key
+}
@@ -0,0 +1,240 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// envPrefix starts the names of the environment variables that override settings, such as
+// AGENTLINT_RULES_FUNCTIONSIZE_MAXLINES for rules.functionSize.maxLines
+const envPrefix = "AGENTLINT_"
+
+// envReserved are the variables with envPrefix that are not settings
+var envReserved = map[string]bool{
+	"AGENTLINT_CONFIG":       true,
+	"AGENTLINT_WORKER_TOKEN": true,
+	"AGENTLINT_LLM_API_KEY":  true,
+}
+
+// uninterpolatedKeys are the settings naming where findings and source code are sent, which
+// take no references, so that a variable cannot send them somewhere else
+var uninterpolatedKeys = map[string]bool{
+	"output.notify.webhook":   true,
+	"output.notify.reportURL": true,
+	"output.llm.endpoint":     true,
+}
+
+// interpolate expands references to environment variables in a value: ${NAME} is the value
+// of NAME, ${NAME:-default} is default when NAME is unset or empty, ${NAME-default} only when
+// it is unset, and $$ is a literal $. A reference to an unset variable without a default is
+// an error, so a missing CI variable does not silently become an empty setting. Only the
+// AGENTLINT_* variables that are not reserved can be referenced, so that a configuration
+// cannot copy credentials from the environment into settings that are printed or sent.
+func interpolate(value string, lookup func(string) (string, bool)) (string, error) {
+	if !strings.Contains(value, "$") {
+		return value, nil
+	}
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 == len(value) {
+			sb.WriteByte(value[i])
+			continue
+		}
+		switch value[i+1] {
+		case '$':
+			sb.WriteByte('$')
+			i++
+			continue
+		case '{':
+		default:
+			sb.WriteByte('$')
+			continue
+		}
+		end := strings.IndexByte(value[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated ${ in %q", value)
+		}
+		reference := value[i+2 : i+end]
+		i += end
+
+		name, fallback, hasFallback := reference, "", false
+		emptyIsUnset := false
+		if j := strings.IndexAny(reference, ":-"); j >= 0 {
+			name = reference[:j]
+			switch {
+			case strings.HasPrefix(reference[j:], ":-"):
+				fallback, hasFallback, emptyIsUnset = reference[j+2:], true, true
+			case reference[j] == '-':
+				fallback, hasFallback = reference[j+1:], true
+			default:
+				return "", fmt.Errorf("unsupported reference ${%s}; use ${NAME}, ${NAME:-default} or ${NAME-default}", reference)
+			}
+		}
+		if !isEnvName(name) {
+			return "", fmt.Errorf("invalid environment variable name %q in ${%s}", name, reference)
+		}
+		if !strings.HasPrefix(name, envPrefix) || envReserved[name] {
+			return "", fmt.Errorf("${%s} cannot be referenced; only %s* variables that are not reserved can", reference, envPrefix)
+		}
+		resolved, ok := lookup(name)
+		if !ok || (emptyIsUnset && resolved == "") {
+			if !hasFallback {
+				return "", fmt.Errorf("environment variable %s is not set", name)
+			}
+			resolved = fallback
+		}
+		sb.WriteString(resolved)
+	}
+	return sb.String(), nil
+}
+
+// isEnvName reports whether name is a valid environment variable name
+func isEnvName(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for _, c := range name {
+		if c != '_' && (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// interpolateNode expands the references to environment variables in the scalars under
+// node, the value at key. A scalar quoted only to hold a reference takes the type of what it
+// expands to, so that maxLines: "${MAX}" is a number.
+func (d *decoder) interpolateNode(node *yamlNode, key string) {
+	switch node.kind {
+	case scalarNode:
+		if node.null {
+			return
+		}
+		if uninterpolatedKeys[key] {
+			if strings.Contains(node.value, "${") {
+				d.report(node.line, core.SeverityError, "%s cannot refer to environment variables", key)
+			}
+			return
+		}
+		value, err := interpolate(node.value, os.LookupEnv)
+		if err != nil {
+			d.report(node.line, core.SeverityError, "%v", err)
+			return
+		}
+		if value != node.value && strings.Contains(node.value, "${") {
+			node.quoted = false
+		}
+		node.value = value
+	case mappingNode:
+		for _, entry := range node.entries {
+			d.interpolateNode(entry.value, joinKey(key, entry.key))
+		}
+	case sequenceNode:
+		for _, item := range node.items {
+			d.interpolateNode(item, key)
+		}
+	}
+}
+
+// reportReferences warns about the references to environment variables under node, in a
+// fetched configuration where they are left as written
+func (d *decoder) reportReferences(node *yamlNode) {
+	switch node.kind {
+	case scalarNode:
+		if strings.Contains(node.value, "${") {
+			d.report(node.line, core.SeverityWarning, "environment variables are not expanded in fetched configurations; %s is left as written", node.value)
+		}
+	case mappingNode:
+		for _, entry := range node.entries {
+			d.reportReferences(entry.value)
+		}
+	case sequenceNode:
+		for _, item := range node.items {
+			d.reportReferences(item)
+		}
+	}
+}
+
+// decodeEnvironment applies the settings of the AGENTLINT_* variables in environ onto config
+// and returns the keys they set. A variable names its key with the sections and name joined
+// by underscores, matched whatever their case; lists are comma-separated. Empty variables are
+// ignored, as CI systems often define variables they leave empty, and so are variables that
+// do not start with a section name, which may belong to scripts rather than to agentlint.
+func decodeEnvironment(environ []string, config *core.Config) (map[string]Setting, []Diagnostic) {
+	d := &decoder{settings: make(map[string]Setting), profiles: make(map[string]profileDef)}
+	sorted := append([]string(nil), environ...)
+	sort.Strings(sorted)
+	for _, variable := range sorted {
+		name, value, _ := strings.Cut(variable, "=")
+		if !strings.HasPrefix(name, envPrefix) || envReserved[name] || value == "" {
+			continue
+		}
+		d.path = "$" + name
+		d.decodeVariable(strings.Split(strings.TrimPrefix(name, envPrefix), "_"), value, reflect.ValueOf(config).Elem())
+	}
+	return d.settings, d.diags
+}
+
+// decodeVariable stores the value of an environment variable into the setting its name
+// segments lead to under v
+func (d *decoder) decodeVariable(segments []string, value string, v reflect.Value) {
+	key := ""
+	for _, segment := range segments {
+		if v.Kind() != reflect.Struct {
+			d.report(0, core.SeverityWarning, "%s cannot be set from the environment", key)
+			return
+		}
+		field, name, names := fieldByTagFold(v, segment)
+		if !field.IsValid() {
+			if key == "" {
+				return
+			}
+			message := fmt.Sprintf("unknown key %s", joinKey(key, strings.ToLower(segment)))
+			if suggestion := closestName(segment, names); suggestion != "" {
+				message += fmt.Sprintf(" (did you mean %s?)", joinKey(key, suggestion))
+			}
+			d.report(0, core.SeverityWarning, "%s", message)
+			return
+		}
+		key, v = joinKey(key, name), field
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		d.report(0, core.SeverityWarning, "%s is a section; name one of its settings", key)
+	case reflect.Map:
+		d.report(0, core.SeverityWarning, "%s cannot be set from the environment", key)
+	case reflect.Slice:
+		node := &yamlNode{kind: sequenceNode}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				node.items = append(node.items, &yamlNode{kind: scalarNode, value: item})
+			}
+		}
+		d.decode(node, v, key)
+	default:
+		d.decode(&yamlNode{kind: scalarNode, value: value}, v, key)
+	}
+}
+
+// fieldByTagFold returns the field of a struct whose yaml tag matches name whatever its case,
+// with the tag, and the tags of every field
+func fieldByTagFold(v reflect.Value, name string) (reflect.Value, string, []string) {
+	var names []string
+	for i := 0; i < v.NumField(); i++ {
+		tag := v.Type().Field(i).Tag.Get("yaml")
+		if tag == "" {
+			continue
+		}
+		names = append(names, tag)
+		if strings.EqualFold(tag, name) {
+			return v.Field(i), tag, nil
+		}
+	}
+	return reflect.Value{}, "", names
+}
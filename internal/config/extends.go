@@ -0,0 +1,270 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+const (
+	// maxExtendsDepth bounds a chain of configuration files extending each other
+	maxExtendsDepth = 5
+	// remoteConfigTTL is how long a fetched configuration without a checksum is used before
+	// it is fetched again
+	remoteConfigTTL = time.Hour
+	// remoteConfigTimeout bounds fetching a configuration
+	remoteConfigTimeout = 10 * time.Second
+	// maxRemoteConfigSize bounds the size of a fetched configuration
+	maxRemoteConfigSize = 1 << 20
+)
+
+// configLayer is a configuration applied over the defaults: a file, or one a file extends
+type configLayer struct {
+	source string // the file or URL
+	data   []byte
+}
+
+// extendsRef is the extends setting of a configuration file: the location of the
+// configuration it extends and, to pin its contents, their SHA-256 checksum
+type extendsRef struct {
+	location string
+	sha256   string
+	line     int
+}
+
+// findExtends returns the extends setting of a configuration file, if any. It is written as
+// a location, or as a mapping of the location under url and its checksum under sha256.
+func findExtends(data []byte) (extendsRef, bool, error) {
+	root, err := parseYAML(data)
+	if err != nil || root.kind != mappingNode {
+		return extendsRef{}, false, nil // reported when the file is decoded
+	}
+	for _, entry := range root.entries {
+		if entry.key != "extends" || entry.value.null {
+			continue
+		}
+		ref := extendsRef{line: entry.line}
+		switch entry.value.kind {
+		case scalarNode:
+			ref.location = entry.value.value
+		case mappingNode:
+			for _, field := range entry.value.entries {
+				switch {
+				case field.value.kind != scalarNode:
+					return ref, true, fmt.Errorf("extends.%s must be a string", field.key)
+				case field.key == "url":
+					ref.location = field.value.value
+				case field.key == "sha256":
+					ref.sha256 = strings.ToLower(strings.TrimPrefix(field.value.value, "sha256:"))
+				default:
+					return ref, true, fmt.Errorf("unknown key extends.%s (expected url or sha256)", field.key)
+				}
+			}
+		default:
+			return ref, true, fmt.Errorf("extends must be a location, or a mapping of url and sha256")
+		}
+		if ref.location == "" {
+			return ref, true, fmt.Errorf("extends has no location")
+		}
+		return ref, true, nil
+	}
+	return extendsRef{}, false, nil
+}
+
+// resolveLocation returns where the configuration a file at base extends is: an HTTPS URL, a
+// file under a github.com/owner/repo/path@ref shorthand, which is read from the raw content
+// of the repository at ref (HEAD by default), or a path relative to base
+func resolveLocation(base, location string) (string, error) {
+	switch {
+	case strings.HasPrefix(location, "https://"):
+		return location, nil
+	case strings.HasPrefix(location, "http://"):
+		return "", fmt.Errorf("extends %s: configurations are only fetched over https", location)
+	case strings.HasPrefix(location, "github.com/"):
+		path, ref, found := strings.Cut(strings.TrimPrefix(location, "github.com/"), "@")
+		if !found || ref == "" {
+			ref = "HEAD"
+		}
+		parts := strings.SplitN(path, "/", 3)
+		if len(parts) < 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return "", fmt.Errorf("extends %s: expected github.com/owner/repo/path/to/agentlint.yaml@ref", location)
+		}
+		return fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s/%s", parts[0], parts[1], ref, parts[2]), nil
+	case strings.HasPrefix(base, "https://"):
+		baseURL, err := url.Parse(base)
+		if err != nil {
+			return "", err
+		}
+		ref, err := url.Parse(location)
+		if err != nil {
+			return "", fmt.Errorf("extends %s: %w", location, err)
+		}
+		return baseURL.ResolveReference(ref).String(), nil
+	case filepath.IsAbs(location):
+		return location, nil
+	}
+	return filepath.Join(filepath.Dir(base), location), nil
+}
+
+// extendedLayers returns the configurations a file extends, directly or through the files it
+// extends, the furthest first, so that each file's own settings win over those it extends
+func (c *ConfigLoader) extendedLayers(source string, data []byte) ([]configLayer, []Diagnostic) {
+	var layers []configLayer
+	var diags []Diagnostic
+	seen := map[string]bool{source: true}
+	for depth := 0; ; depth++ {
+		ref, found, err := findExtends(data)
+		if !found {
+			return layers, diags
+		}
+		fail := func(severity core.Severity, format string, args ...any) {
+			diags = append(diags, Diagnostic{Path: source, Line: ref.line, Severity: severity, Message: fmt.Sprintf(format, args...)})
+		}
+		if err != nil {
+			fail(core.SeverityError, "%v", err)
+			return layers, diags
+		}
+		if depth == maxExtendsDepth {
+			fail(core.SeverityError, "configurations extend each other more than %d deep", maxExtendsDepth)
+			return layers, diags
+		}
+		location, err := resolveLocation(source, ref.location)
+		if err != nil {
+			fail(core.SeverityError, "%v", err)
+			return layers, diags
+		}
+		if seen[location] {
+			fail(core.SeverityError, "extends %s, which extends this configuration", location)
+			return layers, diags
+		}
+		seen[location] = true
+
+		var warning string
+		data, warning, err = c.readExtended(location, ref.sha256)
+		if err != nil {
+			fail(core.SeverityError, "%v", err)
+			return layers, diags
+		}
+		if warning != "" {
+			fail(core.SeverityWarning, "%s", warning)
+		}
+		layers = append([]configLayer{{source: location, data: data}}, layers...)
+		source = location
+	}
+}
+
+// readExtended reads an extended configuration, checking it against checksum when one is
+// given. A fetched configuration is cached: with a checksum the cached copy is used for as
+// long as it matches, without one it is fetched again after remoteConfigTTL, and the cached
+// copy stands in, with a warning, when fetching fails.
+func (c *ConfigLoader) readExtended(location, checksum string) ([]byte, string, error) {
+	if !strings.HasPrefix(location, "https://") {
+		data, err := os.ReadFile(location)
+		if err != nil {
+			return nil, "", fmt.Errorf("extends %s: %w", location, err)
+		}
+		return data, "", verifyChecksum(location, data, checksum)
+	}
+
+	cachePath := ""
+	if c.cacheDir != "" {
+		sum := sha256.Sum256([]byte(location))
+		cachePath = filepath.Join(c.cacheDir, hex.EncodeToString(sum[:12])+".yaml")
+	}
+	cached, cacheErr := os.ReadFile(cachePath)
+	if cacheErr == nil {
+		if checksum != "" && verifyChecksum(location, cached, checksum) == nil {
+			return cached, "", nil
+		}
+		if info, err := os.Stat(cachePath); checksum == "" && err == nil && time.Since(info.ModTime()) < remoteConfigTTL {
+			return cached, "", nil
+		}
+	}
+
+	data, err := c.fetch(location)
+	if err != nil {
+		if cacheErr == nil && verifyChecksum(location, cached, checksum) == nil {
+			return cached, fmt.Sprintf("fetching %s failed, using the copy cached at %s: %v", location, cachePath, err), nil
+		}
+		return nil, "", fmt.Errorf("fetching %s failed: %w", location, err)
+	}
+	if err := verifyChecksum(location, data, checksum); err != nil {
+		return nil, "", err
+	}
+	if cachePath != "" {
+		writeCache(cachePath, data)
+	}
+	return data, "", nil
+}
+
+// fetch downloads a configuration
+func (c *ConfigLoader) fetch(location string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteConfigTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := c.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteConfigSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxRemoteConfigSize {
+		return nil, fmt.Errorf("larger than %d bytes", maxRemoteConfigSize)
+	}
+	return data, nil
+}
+
+// verifyChecksum checks the contents of a configuration against its pinned SHA-256 checksum,
+// if any
+func verifyChecksum(location string, data []byte, checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != checksum {
+		return fmt.Errorf("extends %s: checksum mismatch, pinned sha256 %s but the configuration has %s", location, checksum, got)
+	}
+	return nil
+}
+
+// writeCache stores a fetched configuration; a cache that cannot be written only means it is
+// fetched again next time
+func writeCache(path string, data []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".agentlint-config-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || os.Rename(tmp.Name(), path) != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// bundledProfiles holds the profiles shipped with agentlint, each a configuration file
+// applied over the defaults before the configuration files are
+//
+//go:embed profiles/*.yaml
+var bundledProfiles embed.FS
+
+// DefaultProfile is the bundled profile that keeps the defaults
+const DefaultProfile = "default"
+
+// ProfileNames returns the names of the bundled profiles, sorted
+func ProfileNames() []string {
+	files, _ := fs.Glob(bundledProfiles, "profiles/*.yaml")
+	names := make([]string, len(files))
+	for i, file := range files {
+		names[i] = strings.TrimSuffix(strings.TrimPrefix(file, "profiles/"), ".yaml")
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isBundledProfile(name string) bool {
+	for _, bundled := range ProfileNames() {
+		if name == bundled {
+			return true
+		}
+	}
+	return false
+}
+
+// profileDef is a profile defined in the profiles section of a configuration file
+type profileDef struct {
+	extends string    // the profile applied first, "" for the defaults
+	path    string    // the file defining the profile
+	line    int       // the line of the profile's name
+	body    *yamlNode // the profile's settings, laid out as in a configuration file
+}
+
+// decodeProfiles records the profiles defined in a profiles section. Their settings are
+// checked as those of a configuration file are, but only applied by applyProfile.
+func (d *decoder) decodeProfiles(node *yamlNode) {
+	if node.null {
+		return
+	}
+	if node.kind != mappingNode {
+		d.report(node.line, core.SeverityError, "profiles must be a mapping of profile names to settings")
+		return
+	}
+
+	seen := make(map[string]int)
+	for _, entry := range node.entries {
+		key := joinKey("profiles", entry.key)
+		if line, ok := seen[entry.key]; ok {
+			d.report(entry.line, core.SeverityError, "%s is set twice, first on line %d", key, line)
+			continue
+		}
+		seen[entry.key] = entry.line
+		if isBundledProfile(entry.key) {
+			d.report(entry.line, core.SeverityError, "%s redefines a bundled profile; give it another name and extend %s", key, entry.key)
+			continue
+		}
+		if entry.value.kind != mappingNode && !entry.value.null {
+			d.report(entry.line, core.SeverityError, "%s must be a mapping of settings", key)
+			continue
+		}
+
+		def := profileDef{path: d.path, line: entry.line, body: &yamlNode{kind: mappingNode, line: entry.line}}
+		for _, setting := range entry.value.entries {
+			switch setting.key {
+			case "extends":
+				if setting.value.kind != scalarNode || setting.value.null {
+					d.report(setting.line, core.SeverityError, "%s.extends must name a profile", key)
+					continue
+				}
+				def.extends = setting.value.value
+			case "profile", "profiles":
+				d.report(setting.line, core.SeverityError, "%s cannot set %s; use extends", key, setting.key)
+			default:
+				def.body.entries = append(def.body.entries, setting)
+			}
+		}
+		check := &decoder{path: d.path, settings: make(map[string]Setting), profiles: make(map[string]profileDef)}
+		check.decode(def.body, reflect.ValueOf(&core.Config{}).Elem(), "")
+		d.diags = append(d.diags, check.diags...)
+		d.profiles[entry.key] = def
+	}
+}
+
+// applyProfile applies the profile called name onto config, after the profile it extends.
+// defs are the profiles defined in the configuration files; the bundled ones are looked up
+// otherwise. chain lists the profiles extending this one.
+func applyProfile(name string, config *core.Config, defs map[string]profileDef, chain []string) error {
+	chain = append(chain, name)
+	for _, extending := range chain[:len(chain)-1] {
+		if extending == name {
+			return fmt.Errorf("profile %s extends itself: %s", name, strings.Join(chain, " extends "))
+		}
+	}
+
+	if def, ok := defs[name]; ok {
+		if def.extends != "" {
+			if err := applyProfile(def.extends, config, defs, chain); err != nil {
+				return err
+			}
+		}
+		// the settings were checked when the file was decoded
+		d := &decoder{path: def.path, settings: make(map[string]Setting), profiles: make(map[string]profileDef)}
+		d.decode(def.body, reflect.ValueOf(config).Elem(), "")
+		return nil
+	}
+
+	data, err := bundledProfiles.ReadFile("profiles/" + name + ".yaml")
+	if err != nil {
+		return fmt.Errorf("unknown profile %q (expected %s or one defined under profiles)", name, strings.Join(ProfileNames(), ", "))
+	}
+	if _, diags, _ := decodeConfig("profile "+name, data, config); len(diags) > 0 {
+		return fmt.Errorf("bundled profile %s: %s", name, diags[0].Message)
+	}
+	return nil
+}
@@ -0,0 +1,393 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The reader below handles the subset of YAML that configuration files need: mappings and
+// sequences nested by indentation, flow sequences and mappings written on one line, and
+// plain, single- and double-quoted scalars. Anchors, tags, multi-line scalars and further
+// documents are rejected with the line they appear on rather than misread.
+
+type nodeKind int
+
+const (
+	scalarNode nodeKind = iota
+	mappingNode
+	sequenceNode
+)
+
+// yamlNode is a parsed YAML value and the line it starts on
+type yamlNode struct {
+	kind    nodeKind
+	line    int
+	value   string      // scalar text, without quotes
+	quoted  bool        // the scalar was quoted, so it is a string whatever it holds
+	null    bool        // the value was left empty or written as null or ~
+	entries []yamlEntry // mapping entries, in file order
+	items   []*yamlNode // sequence items
+}
+
+// yamlEntry is one key of a mapping
+type yamlEntry struct {
+	key   string
+	line  int
+	value *yamlNode
+}
+
+// yamlError is a syntax error at a line of the file
+type yamlError struct {
+	line int
+	msg  string
+}
+
+func (e *yamlError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.line, e.msg)
+}
+
+// yamlLine is a line holding content, with its comment removed
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+// parseYAML parses a document into its root node, an empty mapping for an empty document
+func parseYAML(data []byte) (*yamlNode, error) {
+	lines, err := splitYAMLLines(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return &yamlNode{kind: mappingNode, line: 1}, nil
+	}
+	p := &yamlParser{lines: lines}
+	root, err := p.parseBlock(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(lines) {
+		return nil, &yamlError{lines[p.pos].num, "unexpected indentation"}
+	}
+	return root, nil
+}
+
+// splitYAMLLines returns the lines holding content, skipping the document start marker
+func splitYAMLLines(src string) ([]yamlLine, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		num := i + 1
+		text := stripYAMLComment(raw)
+		trimmed := strings.TrimLeft(text, " ")
+		if strings.TrimSpace(trimmed) == "" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, &yamlError{num, "tabs cannot be used for indentation"}
+		}
+		if strings.TrimSpace(trimmed) == "---" && len(lines) == 0 {
+			continue
+		}
+		if trimmed == "---" || trimmed == "..." || strings.HasPrefix(trimmed, "--- ") {
+			return nil, &yamlError{num, "only one document is supported"}
+		}
+		lines = append(lines, yamlLine{num: num, indent: len(text) - len(trimmed), text: strings.TrimRight(trimmed, " \t")})
+	}
+	return lines, nil
+}
+
+// stripYAMLComment removes a comment: a # at the start of the line or after a space, outside
+// quotes
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// parseBlock parses the mapping or sequence whose lines start at indent
+func (p *yamlParser) parseBlock(indent int) (*yamlNode, error) {
+	if isSequenceItem(p.lines[p.pos].text) {
+		return p.parseSequence(indent)
+	}
+	return p.parseMapping(indent)
+}
+
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func (p *yamlParser) parseMapping(indent int) (*yamlNode, error) {
+	node := &yamlNode{kind: mappingNode, line: p.lines[p.pos].num}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, &yamlError{line.num, "unexpected indentation"}
+		}
+		if isSequenceItem(line.text) {
+			return nil, &yamlError{line.num, "expected a key, found a list item"}
+		}
+		key, rest, err := splitYAMLKey(line)
+		if err != nil {
+			return nil, err
+		}
+		p.pos++
+
+		var value *yamlNode
+		switch {
+		case rest != "":
+			if value, err = parseInlineValue(rest, line.num); err != nil {
+				return nil, err
+			}
+		case p.pos < len(p.lines) && p.lines[p.pos].indent > indent:
+			if value, err = p.parseBlock(p.lines[p.pos].indent); err != nil {
+				return nil, err
+			}
+		case p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isSequenceItem(p.lines[p.pos].text):
+			if value, err = p.parseSequence(indent); err != nil {
+				return nil, err
+			}
+		default:
+			value = &yamlNode{kind: scalarNode, line: line.num, null: true}
+		}
+		node.entries = append(node.entries, yamlEntry{key: key, line: line.num, value: value})
+	}
+	return node, nil
+}
+
+func (p *yamlParser) parseSequence(indent int) (*yamlNode, error) {
+	node := &yamlNode{kind: sequenceNode, line: p.lines[p.pos].num}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent || (line.indent == indent && !isSequenceItem(line.text)) {
+			break
+		}
+		if line.indent > indent {
+			return nil, &yamlError{line.num, "unexpected indentation"}
+		}
+		p.pos++
+
+		text := strings.TrimSpace(strings.TrimPrefix(line.text, "-"))
+		if text == "" {
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				return nil, &yamlError{line.num, "nested blocks inside lists are not supported"}
+			}
+			node.items = append(node.items, &yamlNode{kind: scalarNode, line: line.num, null: true})
+			continue
+		}
+		if _, _, err := splitYAMLKey(yamlLine{num: line.num, text: text}); err == nil {
+			return nil, &yamlError{line.num, "mappings inside lists are not supported"}
+		}
+		item, err := parseInlineValue(text, line.num)
+		if err != nil {
+			return nil, err
+		}
+		node.items = append(node.items, item)
+	}
+	return node, nil
+}
+
+// splitYAMLKey splits "key: value" at the first colon followed by a space or the end of the
+// line, outside quotes and brackets
+func splitYAMLKey(line yamlLine) (key, rest string, err error) {
+	text := line.text
+	var quote byte
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == ':' && depth == 0 && (i+1 == len(text) || text[i+1] == ' '):
+			key = strings.TrimSpace(text[:i])
+			if key == "" {
+				return "", "", &yamlError{line.num, "missing key before ':'"}
+			}
+			if key[0] == '"' || key[0] == '\'' {
+				keyNode, err := parseScalar(key, line.num)
+				if err != nil {
+					return "", "", err
+				}
+				key = keyNode.value
+			}
+			return key, strings.TrimSpace(text[i+1:]), nil
+		}
+	}
+	return "", "", &yamlError{line.num, fmt.Sprintf("expected \"key: value\", found %q", text)}
+}
+
+// parseInlineValue parses the value written after a key or a list dash
+func parseInlineValue(text string, line int) (*yamlNode, error) {
+	switch text[0] {
+	case '|', '>':
+		return nil, &yamlError{line, "multi-line strings are not supported"}
+	case '&', '*', '!':
+		return nil, &yamlError{line, "anchors, aliases and tags are not supported"}
+	case '[', '{':
+		f := &flowParser{text: text, line: line}
+		node, err := f.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		f.skipSpaces()
+		if f.pos < len(f.text) {
+			return nil, &yamlError{line, fmt.Sprintf("unexpected %q after %c", f.text[f.pos:], text[0])}
+		}
+		return node, nil
+	}
+	return parseScalar(text, line)
+}
+
+// parseScalar parses a whole plain or quoted scalar
+func parseScalar(text string, line int) (*yamlNode, error) {
+	node := &yamlNode{kind: scalarNode, line: line}
+	switch text[0] {
+	case '"':
+		value, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, &yamlError{line, fmt.Sprintf("invalid double-quoted string %s", text)}
+		}
+		node.value, node.quoted = value, true
+	case '\'':
+		if len(text) < 2 || text[len(text)-1] != '\'' || strings.Contains(strings.ReplaceAll(text[1:len(text)-1], "''", ""), "'") {
+			return nil, &yamlError{line, fmt.Sprintf("invalid single-quoted string %s", text)}
+		}
+		node.value, node.quoted = strings.ReplaceAll(text[1:len(text)-1], "''", "'"), true
+	default:
+		node.value = text
+		node.null = text == "~" || text == "null" || text == "Null" || text == "NULL"
+	}
+	return node, nil
+}
+
+// flowParser parses a flow collection such as [a, "b"] or {key: value} on a single line
+type flowParser struct {
+	text string
+	pos  int
+	line int
+}
+
+func (f *flowParser) skipSpaces() {
+	for f.pos < len(f.text) && f.text[f.pos] == ' ' {
+		f.pos++
+	}
+}
+
+func (f *flowParser) errorf(format string, args ...any) error {
+	return &yamlError{f.line, fmt.Sprintf(format, args...)}
+}
+
+func (f *flowParser) parseValue() (*yamlNode, error) {
+	f.skipSpaces()
+	if f.pos == len(f.text) {
+		return nil, f.errorf("flow collections must be closed on the line they start")
+	}
+	switch f.text[f.pos] {
+	case '[':
+		return f.parseCollection(']')
+	case '{':
+		return f.parseCollection('}')
+	case '"', '\'':
+		return f.parseQuoted()
+	}
+	start := f.pos
+	for f.pos < len(f.text) && !strings.ContainsRune(",]}", rune(f.text[f.pos])) &&
+		!(f.text[f.pos] == ':' && (f.pos+1 == len(f.text) || f.text[f.pos+1] == ' ')) {
+		f.pos++
+	}
+	return parseScalar(strings.TrimSpace(f.text[start:f.pos]), f.line)
+}
+
+func (f *flowParser) parseQuoted() (*yamlNode, error) {
+	quote := f.text[f.pos]
+	start := f.pos
+	for f.pos++; f.pos < len(f.text); f.pos++ {
+		switch {
+		case quote == '"' && f.text[f.pos] == '\\':
+			f.pos++
+		case f.text[f.pos] == quote && quote == '\'' && f.pos+1 < len(f.text) && f.text[f.pos+1] == '\'':
+			f.pos++
+		case f.text[f.pos] == quote:
+			f.pos++
+			return parseScalar(f.text[start:f.pos], f.line)
+		}
+	}
+	return nil, f.errorf("unterminated string %s", f.text[start:])
+}
+
+// parseCollection parses a flow sequence or mapping up to its closing bracket
+func (f *flowParser) parseCollection(closing byte) (*yamlNode, error) {
+	node := &yamlNode{kind: sequenceNode, line: f.line}
+	if closing == '}' {
+		node.kind = mappingNode
+	}
+	f.pos++
+	for {
+		f.skipSpaces()
+		if f.pos == len(f.text) {
+			return nil, f.errorf("flow collections must be closed on the line they start")
+		}
+		if f.text[f.pos] == closing {
+			f.pos++
+			return node, nil
+		}
+
+		item, err := f.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if node.kind == mappingNode {
+			if item.kind != scalarNode || f.pos == len(f.text) || f.text[f.pos] != ':' {
+				return nil, f.errorf("expected \"key: value\" in {...}")
+			}
+			f.pos++
+			value, err := f.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			node.entries = append(node.entries, yamlEntry{key: item.value, line: f.line, value: value})
+		} else {
+			node.items = append(node.items, item)
+		}
+
+		f.skipSpaces()
+		if f.pos < len(f.text) && f.text[f.pos] == ',' {
+			f.pos++
+		} else if f.pos < len(f.text) && f.text[f.pos] != closing {
+			return nil, f.errorf("expected ',' or '%c', found %q", closing, f.text[f.pos:])
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Categories lists every rule category, in the order they are documented
+var Categories = []RuleCategory{
+	CategorySize,
+	CategoryComplexity,
+	CategoryComments,
+	CategoryOrphaned,
+	CategoryPerformance,
+	CategoryDeprecated,
+	CategoryStyle,
+	CategoryBug,
+}
+
+// ValidateCategories returns an error naming the first entry of names that is not a category
+func ValidateCategories(names []string) error {
+	for _, name := range names {
+		if !isCategory(name) {
+			return fmt.Errorf("unknown category %q (expected one of %s)", name, categoryList())
+		}
+	}
+	return nil
+}
+
+// FilterCategories returns the results whose category is in only, or every category when only
+// is empty, and not in skip, keeping their order
+func FilterCategories(results []Result, only, skip []string) []Result {
+	if len(only) == 0 && len(skip) == 0 {
+		return results
+	}
+	kept := results[:0]
+	for _, result := range results {
+		if (len(only) == 0 || contains(only, result.Category)) && !contains(skip, result.Category) {
+			kept = append(kept, result)
+		}
+	}
+	return kept
+}
+
+func isCategory(name string) bool {
+	for _, category := range Categories {
+		if string(category) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// categoryList formats the categories for messages
+func categoryList() string {
+	names := make([]string, len(Categories))
+	for i, category := range Categories {
+		names[i] = string(category)
+	}
+	return strings.Join(names, ", ")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
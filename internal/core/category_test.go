@@ -0,0 +1,50 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+func TestFilterCategories(t *testing.T) {
+	newResults := func() []core.Result {
+		return []core.Result{
+			{RuleID: "large-function", Category: string(core.CategorySize)},
+			{RuleID: "inline-style", Category: string(core.CategoryPerformance)},
+			{RuleID: "bare-except", Category: string(core.CategoryBug)},
+			{RuleID: "wildcard-import", Category: string(core.CategoryStyle)},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		only, skip []string
+		want       []string
+	}{
+		{"no filter", nil, nil, []string{"large-function", "inline-style", "bare-except", "wildcard-import"}},
+		{"only", []string{"performance", "bug"}, nil, []string{"inline-style", "bare-except"}},
+		{"skip", nil, []string{"style"}, []string{"large-function", "inline-style", "bare-except"}},
+		{"skip wins", []string{"bug", "style"}, []string{"style"}, []string{"bare-except"}},
+	}
+	for _, tt := range tests {
+		got := core.FilterCategories(newResults(), tt.only, tt.skip)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %+v", tt.name, tt.want, got)
+			continue
+		}
+		for i, id := range tt.want {
+			if got[i].RuleID != id {
+				t.Errorf("%s: result %d is %s, expected %s", tt.name, i, got[i].RuleID, id)
+			}
+		}
+	}
+}
+
+func TestValidateCategories(t *testing.T) {
+	if err := core.ValidateCategories([]string{"performance", "complexity"}); err != nil {
+		t.Errorf("expected known categories to be valid, got %v", err)
+	}
+	if err := core.ValidateCategories([]string{"bug", "perf"}); err == nil {
+		t.Error("expected an unknown category to be rejected")
+	}
+}
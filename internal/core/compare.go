@@ -0,0 +1,36 @@
+package core
+
+// Comparison splits the findings of two runs over snapshots of the same code, such as a tree
+// before and after a change
+type Comparison struct {
+	Introduced []Result // reported in the second run only
+	Fixed      []Result // reported in the first run only
+	Unchanged  []Result // reported in both runs, as the second run reports them
+}
+
+// Compare matches the results of two runs by fingerprint, so a finding moved by lines added
+// above it counts as unchanged. Both runs must fingerprint paths relative to the root of their
+// snapshot. Each list keeps the order of the run it comes from.
+func Compare(before, after []Result) Comparison {
+	remaining := make(map[string]int, len(before))
+	for _, result := range before {
+		remaining[result.Fingerprint]++
+	}
+
+	var comparison Comparison
+	for _, result := range after {
+		if remaining[result.Fingerprint] > 0 {
+			remaining[result.Fingerprint]--
+			comparison.Unchanged = append(comparison.Unchanged, result)
+		} else {
+			comparison.Introduced = append(comparison.Introduced, result)
+		}
+	}
+	for _, result := range before {
+		if remaining[result.Fingerprint] > 0 {
+			remaining[result.Fingerprint]--
+			comparison.Fixed = append(comparison.Fixed, result)
+		}
+	}
+	return comparison
+}
@@ -0,0 +1,52 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+func TestCompare(t *testing.T) {
+	before := []core.Result{
+		{RuleID: "large-function", Line: 10, Fingerprint: "a"},
+		{RuleID: "console-log", Line: 20, Fingerprint: "b"},
+		{RuleID: "magic-number", Line: 30, Fingerprint: "c"},
+		{RuleID: "magic-number", Line: 31, Fingerprint: "e"},
+	}
+	after := []core.Result{
+		{RuleID: "large-function", Line: 14, Fingerprint: "a"},
+		{RuleID: "bare-except", Line: 2, Fingerprint: "d"},
+		{RuleID: "magic-number", Line: 34, Fingerprint: "c"},
+	}
+	got := core.Compare(before, after)
+
+	lines := func(results []core.Result) []int {
+		var l []int
+		for _, result := range results {
+			l = append(l, result.Line)
+		}
+		return l
+	}
+	tests := []struct {
+		name    string
+		results []core.Result
+		want    []int
+	}{
+		{"introduced", got.Introduced, []int{2}},
+		{"fixed", got.Fixed, []int{20, 31}},
+		{"unchanged", got.Unchanged, []int{14, 34}},
+	}
+	for _, tt := range tests {
+		gotLines := lines(tt.results)
+		if len(gotLines) != len(tt.want) {
+			t.Errorf("%s: expected lines %v, got %v", tt.name, tt.want, gotLines)
+			continue
+		}
+		for i := range tt.want {
+			if gotLines[i] != tt.want[i] {
+				t.Errorf("%s: expected lines %v, got %v", tt.name, tt.want, gotLines)
+				break
+			}
+		}
+	}
+}
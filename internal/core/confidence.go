@@ -0,0 +1,56 @@
+package core
+
+// Confidence is how likely a finding is to be a real problem rather than a false positive of
+// the heuristic that reported it
+type Confidence string
+
+const (
+	ConfidenceHigh   Confidence = "high"   // exact analysis of the syntax tree or of a count
+	ConfidenceMedium Confidence = "medium" // sound heuristic, such as name matching without type information
+	ConfidenceLow    Confidence = "low"    // guess from patterns in the text, such as word similarity
+)
+
+// confidenceRanks orders confidences from least to most certain
+var confidenceRanks = map[Confidence]int{
+	ConfidenceLow:    1,
+	ConfidenceMedium: 2,
+	ConfidenceHigh:   3,
+}
+
+// Rank returns the ordinal of the confidence, or 0 if it is unknown
+func (c Confidence) Rank() int {
+	return confidenceRanks[c]
+}
+
+// MeetsMinimum reports whether the confidence is at or above minimum. An empty confidence
+// counts as high, and an empty minimum accepts everything.
+func (c Confidence) MeetsMinimum(minimum Confidence) bool {
+	if c == "" {
+		c = ConfidenceHigh
+	}
+	return minimum == "" || c.Rank() >= minimum.Rank()
+}
+
+// SetConfidence marks the results whose rule did not set a confidence as high: rules that
+// guess set their confidence themselves
+func SetConfidence(results []Result) {
+	for i := range results {
+		if results[i].Confidence == "" {
+			results[i].Confidence = string(ConfidenceHigh)
+		}
+	}
+}
+
+// FilterConfidence returns the results whose confidence meets minimum, keeping their order
+func FilterConfidence(results []Result, minimum Confidence) []Result {
+	if minimum == "" || minimum == ConfidenceLow {
+		return results
+	}
+	kept := results[:0]
+	for _, result := range results {
+		if Confidence(result.Confidence).MeetsMinimum(minimum) {
+			kept = append(kept, result)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,53 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+func TestFilterConfidence(t *testing.T) {
+	newResults := func() []core.Result {
+		results := []core.Result{
+			{RuleID: "large-function"},
+			{RuleID: "cross-file-unused-function", Confidence: string(core.ConfidenceMedium)},
+			{RuleID: "code-similarity", Confidence: string(core.ConfidenceLow)},
+		}
+		core.SetConfidence(results)
+		return results
+	}
+
+	tests := []struct {
+		minimum core.Confidence
+		want    []string
+	}{
+		{"", []string{"large-function", "cross-file-unused-function", "code-similarity"}},
+		{core.ConfidenceLow, []string{"large-function", "cross-file-unused-function", "code-similarity"}},
+		{core.ConfidenceMedium, []string{"large-function", "cross-file-unused-function"}},
+		{core.ConfidenceHigh, []string{"large-function"}},
+	}
+	for _, tt := range tests {
+		got := core.FilterConfidence(newResults(), tt.minimum)
+		if len(got) != len(tt.want) {
+			t.Errorf("minimum %q: expected %v, got %+v", tt.minimum, tt.want, got)
+			continue
+		}
+		for i, id := range tt.want {
+			if got[i].RuleID != id {
+				t.Errorf("minimum %q: result %d is %s, expected %s", tt.minimum, i, got[i].RuleID, id)
+			}
+		}
+	}
+}
+
+func TestSetConfidenceDefaultsToHigh(t *testing.T) {
+	results := []core.Result{{RuleID: "large-function"}, {RuleID: "redundant-comment", Confidence: string(core.ConfidenceLow)}}
+	core.SetConfidence(results)
+
+	if results[0].Confidence != string(core.ConfidenceHigh) {
+		t.Errorf("expected a result without confidence to be high, got %q", results[0].Confidence)
+	}
+	if results[1].Confidence != string(core.ConfidenceLow) {
+		t.Errorf("expected the rule's confidence to be kept, got %q", results[1].Confidence)
+	}
+}
@@ -0,0 +1,75 @@
+package core
+
+// ruleFamilies maps the rules of whole-project analyses to the per-file rule that reports the
+// same problem
+var ruleFamilies = map[string]string{
+	"cross-file-unused-function": "unused-function",
+	"cross-file-unused-method":   "unused-function",
+}
+
+// RuleFamily returns the family of a rule: rules in one family report the same problem, so
+// their findings at one place are a single issue
+func RuleFamily(ruleID string) string {
+	if family, ok := ruleFamilies[ruleID]; ok {
+		return family
+	}
+	return ruleID
+}
+
+// Dedupe merges results that report the same problem at the same place: results for one file
+// and line from rules of one family. Results of a single rule are merged only when their
+// messages are identical, so distinct findings on one line are kept. Merged results keep the
+// position of the first one reported.
+func Dedupe(results []Result) []Result {
+	type key struct {
+		file   string
+		line   int
+		family string
+	}
+	groups := make(map[key][]int, len(results))
+	merged := make([]Result, 0, len(results))
+
+	for _, result := range results {
+		k := key{result.FilePath, result.Line, RuleFamily(result.RuleID)}
+		duplicate := -1
+		for _, i := range groups[k] {
+			if merged[i].RuleID != result.RuleID || merged[i].Message == result.Message {
+				duplicate = i
+				break
+			}
+		}
+		if duplicate < 0 {
+			groups[k] = append(groups[k], len(merged))
+			merged = append(merged, result)
+			continue
+		}
+		merged[duplicate] = mergeResults(merged[duplicate], result)
+	}
+	return merged
+}
+
+// mergeResults keeps the result that takes precedence and fills in the suggestion and column
+// from the other when it has none
+func mergeResults(a, b Result) Result {
+	if precedes(b, a) {
+		a, b = b, a
+	}
+	if a.Suggestion == "" {
+		a.Suggestion = b.Suggestion
+	}
+	if a.Column == 0 {
+		a.Column = b.Column
+	}
+	return a
+}
+
+// precedes reports whether a takes precedence over b: the higher severity wins, then the
+// whole-project analysis, which has seen every caller, over the per-file rule
+func precedes(a, b Result) bool {
+	if rankA, rankB := Severity(a.Severity).Rank(), Severity(b.Severity).Rank(); rankA != rankB {
+		return rankA > rankB
+	}
+	_, projectA := ruleFamilies[a.RuleID]
+	_, projectB := ruleFamilies[b.RuleID]
+	return projectA && !projectB
+}
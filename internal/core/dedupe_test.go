@@ -0,0 +1,54 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+func TestDedupe(t *testing.T) {
+	results := []core.Result{
+		{RuleID: "unused-function", Severity: "warning", FilePath: "a.go", Line: 10, Message: "Function 'f' is never called", Suggestion: "Remove it"},
+		{RuleID: "large-function", Severity: "warning", FilePath: "a.go", Line: 10, Message: "Function 'f' is too large"},
+		{RuleID: "cross-file-unused-function", Severity: "warning", FilePath: "a.go", Line: 10, Message: "Function 'f' is not called anywhere in the project"},
+		{RuleID: "cross-file-unused-function", Severity: "warning", FilePath: "b.go", Line: 10, Message: "Function 'g' is not called anywhere in the project"},
+		{RuleID: "magic-number", Severity: "info", FilePath: "a.go", Line: 20, Message: "Magic number 42"},
+		{RuleID: "magic-number", Severity: "info", FilePath: "a.go", Line: 20, Message: "Magic number 7"},
+		{RuleID: "magic-number", Severity: "info", FilePath: "a.go", Line: 20, Message: "Magic number 42"},
+	}
+
+	got := core.Dedupe(results)
+
+	want := []struct {
+		ruleID, file, message string
+	}{
+		{"cross-file-unused-function", "a.go", "Function 'f' is not called anywhere in the project"},
+		{"large-function", "a.go", "Function 'f' is too large"},
+		{"cross-file-unused-function", "b.go", "Function 'g' is not called anywhere in the project"},
+		{"magic-number", "a.go", "Magic number 42"},
+		{"magic-number", "a.go", "Magic number 7"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d results, got %d: %+v", len(want), len(got), got)
+	}
+	for i, w := range want {
+		if got[i].RuleID != w.ruleID || got[i].FilePath != w.file || got[i].Message != w.message {
+			t.Errorf("result %d: expected %s in %s (%q), got %s in %s (%q)", i, w.ruleID, w.file, w.message, got[i].RuleID, got[i].FilePath, got[i].Message)
+		}
+	}
+	if got[0].Suggestion != "Remove it" {
+		t.Errorf("expected the merged result to take the per-file suggestion, got %q", got[0].Suggestion)
+	}
+}
+
+func TestDedupe_HigherSeverityWins(t *testing.T) {
+	results := []core.Result{
+		{RuleID: "cross-file-unused-method", Severity: "warning", FilePath: "a.go", Line: 5},
+		{RuleID: "unused-function", Severity: "error", FilePath: "a.go", Line: 5},
+	}
+
+	got := core.Dedupe(results)
+	if len(got) != 1 || got[0].RuleID != "unused-function" {
+		t.Errorf("expected the error-level unused-function result to be kept, got %+v", got)
+	}
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// AnalysisJob is one file to be analyzed by one analyzer
+type AnalysisJob struct {
+	Analyzer Analyzer
+	FilePath string
+}
+
+// Engine analyzes files concurrently. Every (analyzer, file) pair is a job for one shared
+// worker pool, so a repository mixing languages keeps every worker busy instead of analyzing
+// one language after another. Analyzers must be safe for concurrent use.
+type Engine struct {
+	workers     int
+	memoryLimit uint64 // heap size to stay under, 0 for no limit, see SetMemoryLimit
+}
+
+// NewEngine creates an engine with the given number of workers; 0 or less uses DefaultWorkers
+func NewEngine(workers int) *Engine {
+	if workers <= 0 {
+		workers = DefaultWorkers()
+	}
+	return &Engine{workers: workers}
+}
+
+// DefaultWorkers returns the default worker count: one per CPU, leaving one CPU free on
+// machines with more than four
+func DefaultWorkers() int {
+	maxProcs := runtime.NumCPU()
+	if maxProcs > 4 {
+		return maxProcs - 1
+	}
+	return maxProcs
+}
+
+// Workers returns the number of workers
+func (e *Engine) Workers() int {
+	return e.workers
+}
+
+// SetMemoryLimit caps the heap of an analysis run, in bytes. Near the cap the engine analyzes
+// fewer files at once and flushes the caches of analyzers implementing CacheFlusher; well below
+// it, it scales back up to its workers. 0 removes the cap.
+func (e *Engine) SetMemoryLimit(limit uint64) {
+	e.memoryLimit = limit
+}
+
+// Run analyzes every job and returns the findings along with the files that could not be
+// analyzed. Results are returned in job order whatever order the workers finish in, so output
+// is stable between runs. Jobs not yet started when ctx is cancelled are skipped.
+func (e *Engine) Run(ctx context.Context, jobs []AnalysisJob, config Config) ([]Result, []FileError) {
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	results := make([][]Result, len(jobs))
+	errs := make([]error, len(jobs))
+	next := make(chan int, len(jobs))
+	for i := range jobs {
+		next <- i
+	}
+	close(next)
+
+	workers := e.workers
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+	var memory *memoryGovernor
+	if e.memoryLimit > 0 {
+		memory = newMemoryGovernor(e.memoryLimit, workers, jobs)
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				if ctx.Err() != nil || memory != nil && !memory.acquire(ctx) {
+					return
+				}
+				results[i], errs[i] = jobs[i].Analyzer.Analyze(ctx, jobs[i].FilePath, config)
+				if memory != nil {
+					memory.release()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Pre-allocate with estimated capacity (avg 2 results per file)
+	allResults := make([]Result, 0, len(jobs)*2)
+	var fileErrors []FileError
+	for i, err := range errs {
+		if err != nil {
+			fileErrors = append(fileErrors, FileError{FilePath: jobs[i].FilePath, Message: err.Error()})
+			continue
+		}
+		allResults = append(allResults, results[i]...)
+	}
+	return allResults, fileErrors
+}
@@ -0,0 +1,118 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// fakeAnalyzer reports one result per file and fails on files named "broken"
+type fakeAnalyzer struct {
+	name string
+}
+
+func (a fakeAnalyzer) Analyze(ctx context.Context, filePath string, config core.Config) ([]core.Result, error) {
+	if strings.HasPrefix(filePath, "broken") {
+		return nil, errors.New("syntax error")
+	}
+	return []core.Result{{RuleID: a.name, FilePath: filePath}}, nil
+}
+
+func (a fakeAnalyzer) SupportedExtensions() []string { return nil }
+func (a fakeAnalyzer) Name() string                  { return a.name }
+
+func TestEngine_Run(t *testing.T) {
+	goAnalyzer, pyAnalyzer := fakeAnalyzer{"go"}, fakeAnalyzer{"python"}
+	var jobs []core.AnalysisJob
+	var expected []string
+	for i := 0; i < 50; i++ {
+		jobs = append(jobs, core.AnalysisJob{Analyzer: goAnalyzer, FilePath: fmt.Sprintf("f%d.go", i)})
+		jobs = append(jobs, core.AnalysisJob{Analyzer: pyAnalyzer, FilePath: fmt.Sprintf("f%d.py", i)})
+		expected = append(expected, fmt.Sprintf("go:f%d.go", i), fmt.Sprintf("python:f%d.py", i))
+	}
+	jobs = append(jobs, core.AnalysisJob{Analyzer: pyAnalyzer, FilePath: "broken.py"})
+
+	results, fileErrors := core.NewEngine(4).Run(context.Background(), jobs, core.Config{})
+
+	var got []string
+	for _, r := range results {
+		got = append(got, r.RuleID+":"+r.FilePath)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("Expected results in job order %v, got %v", expected, got)
+	}
+	if len(fileErrors) != 1 || fileErrors[0].FilePath != "broken.py" || fileErrors[0].Message != "syntax error" {
+		t.Errorf("Expected a single error for broken.py, got %v", fileErrors)
+	}
+}
+
+func TestEngine_Workers(t *testing.T) {
+	if got := core.NewEngine(3).Workers(); got != 3 {
+		t.Errorf("Expected 3 workers, got %d", got)
+	}
+	if got := core.NewEngine(0).Workers(); got != core.DefaultWorkers() {
+		t.Errorf("Expected %d default workers, got %d", core.DefaultWorkers(), got)
+	}
+}
+
+// flushingAnalyzer counts the files analyzed at once and the times its cache was flushed
+type flushingAnalyzer struct {
+	mu            sync.Mutex
+	running, peak int
+	flushes       int
+}
+
+func (a *flushingAnalyzer) Analyze(ctx context.Context, filePath string, config core.Config) ([]core.Result, error) {
+	a.mu.Lock()
+	a.running++
+	a.peak = max(a.peak, a.running)
+	a.mu.Unlock()
+	time.Sleep(time.Millisecond)
+	a.mu.Lock()
+	a.running--
+	a.mu.Unlock()
+	return []core.Result{{RuleID: "flushing", FilePath: filePath}}, nil
+}
+
+func (a *flushingAnalyzer) SupportedExtensions() []string { return nil }
+func (a *flushingAnalyzer) Name() string                  { return "flushing" }
+
+func (a *flushingAnalyzer) FlushCache() {
+	a.mu.Lock()
+	a.flushes++
+	a.mu.Unlock()
+}
+
+func TestEngine_MemoryLimit(t *testing.T) {
+	analyzer := &flushingAnalyzer{}
+	var jobs []core.AnalysisJob
+	for i := 0; i < 100; i++ {
+		jobs = append(jobs, core.AnalysisJob{Analyzer: analyzer, FilePath: fmt.Sprintf("f%d.go", i)})
+	}
+
+	// a limit every heap exceeds halves the files in flight before the first one starts
+	engine := core.NewEngine(8)
+	engine.SetMemoryLimit(1)
+	results, fileErrors := engine.Run(context.Background(), jobs, core.Config{})
+
+	if len(results) != len(jobs) || len(fileErrors) != 0 {
+		t.Fatalf("Expected %d results and no errors, got %d results and %v", len(jobs), len(results), fileErrors)
+	}
+	for i, result := range results {
+		if result.FilePath != jobs[i].FilePath {
+			t.Fatalf("Expected results in job order, got %s at %d", result.FilePath, i)
+		}
+	}
+	if analyzer.flushes == 0 {
+		t.Error("Expected the analyzer's cache to be flushed")
+	}
+	if analyzer.peak > 4 {
+		t.Errorf("Expected at most 4 of 8 files in flight, got %d", analyzer.peak)
+	}
+}
@@ -0,0 +1,31 @@
+package core
+
+// FilterRules returns the results of the rules in only, or of every rule when only is empty,
+// keeping their order
+func FilterRules(results []Result, only []string) []Result {
+	if len(only) == 0 {
+		return results
+	}
+	kept := results[:0]
+	for _, result := range results {
+		if contains(only, result.RuleID) {
+			kept = append(kept, result)
+		}
+	}
+	return kept
+}
+
+// FilterSeverity returns the results whose severity meets minimum, keeping their order; an
+// empty minimum keeps every result
+func FilterSeverity(results []Result, minimum Severity) []Result {
+	if minimum == "" {
+		return results
+	}
+	kept := results[:0]
+	for _, result := range results {
+		if Severity(result.Severity).MeetsThreshold(minimum) {
+			kept = append(kept, result)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,63 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+func filterTestResults() []core.Result {
+	return []core.Result{
+		{RuleID: "large-function", Severity: string(core.SeverityWarning)},
+		{RuleID: "console-log", Severity: string(core.SeverityInfo)},
+		{RuleID: "bare-except", Severity: string(core.SeverityError)},
+		{RuleID: "large-function", Severity: string(core.SeverityError)},
+	}
+}
+
+func TestFilterRules(t *testing.T) {
+	tests := []struct {
+		name string
+		only []string
+		want []string
+	}{
+		{"no filter", nil, []string{"large-function", "console-log", "bare-except", "large-function"}},
+		{"only", []string{"large-function", "console-log"}, []string{"large-function", "console-log", "large-function"}},
+		{"unreported rule", []string{"print-debug"}, nil},
+	}
+	for _, tt := range tests {
+		got := core.FilterRules(filterTestResults(), tt.only)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %+v", tt.name, tt.want, got)
+			continue
+		}
+		for i, id := range tt.want {
+			if got[i].RuleID != id {
+				t.Errorf("%s: result %d is %s, expected %s", tt.name, i, got[i].RuleID, id)
+			}
+		}
+	}
+}
+
+func TestFilterSeverity(t *testing.T) {
+	tests := []struct {
+		minimum core.Severity
+		want    int
+	}{
+		{"", 4},
+		{core.SeverityInfo, 4},
+		{core.SeverityWarning, 3},
+		{core.SeverityError, 2},
+	}
+	for _, tt := range tests {
+		got := core.FilterSeverity(filterTestResults(), tt.minimum)
+		if len(got) != tt.want {
+			t.Errorf("minimum %q: expected %d results, got %+v", tt.minimum, tt.want, got)
+		}
+		for _, result := range got {
+			if tt.minimum != "" && !core.Severity(result.Severity).MeetsThreshold(tt.minimum) {
+				t.Errorf("minimum %q: kept a %s result", tt.minimum, result.Severity)
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Fingerprint returns a stable identity for a finding: a hash of the rule ID, the file path
+// and the source line the finding points at. The path should be relative to the project root;
+// it is compared with forward slashes, and whitespace in the snippet is collapsed, so the
+// fingerprint survives line shifts, reindentation and checkouts on other platforms. occurrence
+// tells apart identical snippets reported by one rule in one file, counting from 0.
+func Fingerprint(ruleID, filePath, snippet string, occurrence int) string {
+	h := sha256.New()
+	h.Write([]byte(ruleID))
+	h.Write([]byte{0})
+	h.Write([]byte(path.Clean(strings.ReplaceAll(filePath, `\`, "/"))))
+	h.Write([]byte{0})
+	h.Write([]byte(strings.Join(strings.Fields(snippet), " ")))
+	if occurrence > 0 {
+		h.Write([]byte{0})
+		h.Write([]byte(strconv.Itoa(occurrence)))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// Hash returns a short hash of the settings of c, so that reports can tell whether two runs
+// were made with the same configuration
+func (c Config) Hash() string {
+	data, _ := json.Marshal(c)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,41 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+func TestFingerprint(t *testing.T) {
+	base := core.Fingerprint("magic-number", "pkg/server.go", "\ttimeout := 30", 0)
+
+	same := []struct {
+		name                  string
+		ruleID, path, snippet string
+	}{
+		{"reindented", "magic-number", "pkg/server.go", "        timeout  :=  30  "},
+		{"windows path", "magic-number", `pkg\server.go`, "\ttimeout := 30"},
+		{"unclean path", "magic-number", "./pkg/server.go", "\ttimeout := 30"},
+	}
+	for _, tt := range same {
+		if got := core.Fingerprint(tt.ruleID, tt.path, tt.snippet, 0); got != base {
+			t.Errorf("%s: expected fingerprint %s, got %s", tt.name, base, got)
+		}
+	}
+
+	different := []struct {
+		name                  string
+		ruleID, path, snippet string
+		occurrence            int
+	}{
+		{"other rule", "large-function", "pkg/server.go", "\ttimeout := 30", 0},
+		{"other file", "magic-number", "pkg/client.go", "\ttimeout := 30", 0},
+		{"edited line", "magic-number", "pkg/server.go", "\ttimeout := 60", 0},
+		{"second occurrence", "magic-number", "pkg/server.go", "\ttimeout := 30", 1},
+	}
+	for _, tt := range different {
+		if got := core.Fingerprint(tt.ruleID, tt.path, tt.snippet, tt.occurrence); got == base {
+			t.Errorf("%s: expected a fingerprint other than %s", tt.name, base)
+		}
+	}
+}
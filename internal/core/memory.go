@@ -0,0 +1,106 @@
+package core
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// CacheFlusher is implemented by analyzers that keep parsed files in memory and can drop them
+// when memory runs short
+type CacheFlusher interface {
+	FlushCache()
+}
+
+const (
+	// memoryHigh is the share of the memory limit at which the engine halves the files in flight
+	// and flushes caches
+	memoryHigh = 0.9
+	// memoryLow is the share of the memory limit below which the engine lets one more file in flight
+	memoryLow = 0.6
+	// memoryCheckInterval is how often the heap is measured; reading it briefly stops the world
+	memoryCheckInterval = 20 * time.Millisecond
+)
+
+// memoryGovernor scales the number of files analyzed at once to keep the heap under a limit
+type memoryGovernor struct {
+	limit     uint64
+	heap      func() uint64 // bytes of heap in use
+	analyzers []CacheFlusher
+
+	mu      sync.Mutex
+	done    *sync.Cond // signalled when a file finishes
+	running int        // files being analyzed
+	active  int        // files allowed to be analyzed at once
+	workers int
+	checked time.Time
+}
+
+func newMemoryGovernor(limit uint64, workers int, jobs []AnalysisJob) *memoryGovernor {
+	g := &memoryGovernor{limit: limit, heap: heapInUse, active: workers, workers: workers}
+	g.done = sync.NewCond(&g.mu)
+	seen := make(map[CacheFlusher]bool)
+	for _, job := range jobs {
+		if flusher, ok := job.Analyzer.(CacheFlusher); ok && !seen[flusher] {
+			seen[flusher] = true
+			g.analyzers = append(g.analyzers, flusher)
+		}
+	}
+	return g
+}
+
+// acquire blocks until another file may be analyzed, and reports false when ctx is cancelled
+// first. One file may always be analyzed, so the run keeps progressing however little memory
+// is left.
+func (g *memoryGovernor) acquire(ctx context.Context) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for {
+		if ctx.Err() != nil {
+			return false
+		}
+		g.adjust()
+		if g.running < g.active {
+			g.running++
+			return true
+		}
+		g.done.Wait()
+	}
+}
+
+// release records that a file has been analyzed
+func (g *memoryGovernor) release() {
+	g.mu.Lock()
+	g.running--
+	g.mu.Unlock()
+	g.done.Broadcast()
+}
+
+// adjust measures the heap and scales the files allowed at once: near the limit it halves them
+// and flushes the analyzers' caches, well below it it lets one more in
+func (g *memoryGovernor) adjust() {
+	if now := time.Now(); now.Sub(g.checked) >= memoryCheckInterval {
+		g.checked = now
+	} else {
+		return
+	}
+
+	heap := float64(g.heap())
+	switch {
+	case heap >= memoryHigh*float64(g.limit):
+		g.active = max(g.active/2, 1)
+		for _, analyzer := range g.analyzers {
+			analyzer.FlushCache()
+		}
+		runtime.GC()
+	case heap < memoryLow*float64(g.limit) && g.active < g.workers:
+		g.active++
+	}
+}
+
+func heapInUse() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
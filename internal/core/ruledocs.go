@@ -0,0 +1,39 @@
+package core
+
+import "sync"
+
+// RuleDoc documents a rule reported by a project-wide analysis, such as import cycles or
+// cross-file unused code, which has no Rule of its own to read the documentation from
+type RuleDoc struct {
+	ID          string
+	Name        string
+	Description string
+	Rationale   string
+	Category    RuleCategory // "" for a rule taking the category of the findings it reports on
+	Severity    Severity
+	// Languages are the analyzer names the rule applies to, as registered in the language
+	// registry; none for a rule that applies to every language
+	Languages []string
+	Examples  []RuleExample
+	Options   []RuleOption
+}
+
+var (
+	ruleDocsMu sync.Mutex
+	ruleDocs   []RuleDoc
+)
+
+// RegisterRuleDoc records the documentation of a project-wide rule. The packages reporting
+// such rules call it from init, so that every rule they report can be explained.
+func RegisterRuleDoc(doc RuleDoc) {
+	ruleDocsMu.Lock()
+	defer ruleDocsMu.Unlock()
+	ruleDocs = append(ruleDocs, doc)
+}
+
+// RuleDocs returns the documentation registered with RegisterRuleDoc, in registration order
+func RuleDocs() []RuleDoc {
+	ruleDocsMu.Lock()
+	defer ruleDocsMu.Unlock()
+	return append([]RuleDoc(nil), ruleDocs...)
+}
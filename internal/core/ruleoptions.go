@@ -0,0 +1,117 @@
+package core
+
+// Options read by the rules of every language, for their Options methods. The language
+// argument is the key of the language in the language section of the configuration and
+// flagPrefix the prefix of its override flags, e.g. "reactnative" and "js".
+
+// FunctionSizeOptions returns the options of a language's large-function rule
+func FunctionSizeOptions(language, flagPrefix string) []RuleOption {
+	return []RuleOption{
+		{Key: "rules.functionSize.enabled", Flag: "-enable-func-size", Default: "true", Description: "Report functions that are too large"},
+		{Key: "rules.functionSize.maxLines", Flag: "-func-max-lines", Default: "50", Description: "Largest allowed function"},
+		{Key: "rules.functionSize.metric", Flag: "-func-metric", Default: "lines", Description: "How functions are measured: lines, or statements (logical lines outside Go)"},
+		{Key: "language." + language + ".rules.functionSize.maxLines", Flag: "-" + flagPrefix + "-func-max-lines", Default: "0", Description: "Largest allowed function in this language, 0 uses rules.functionSize.maxLines"},
+		{Key: "testFiles.rules.functionSize.maxLines", Flag: "-test-func-max-lines", Default: "0", Description: "Largest allowed function in test files, 0 uses the language limit"},
+	}
+}
+
+// FileSizeOptions returns the options of a language's large-file rule
+func FileSizeOptions(language, flagPrefix string) []RuleOption {
+	return []RuleOption{
+		{Key: "rules.fileSize.enabled", Flag: "-enable-file-size", Default: "true", Description: "Report files that are too large"},
+		{Key: "rules.fileSize.maxLines", Flag: "-file-max-lines", Default: "500", Description: "Largest allowed file"},
+		{Key: "rules.fileSize.countMode", Flag: "-file-count-mode", Default: "total", Description: "Which lines count: total, or code to skip comments and blank lines"},
+		{Key: "language." + language + ".rules.fileSize.maxLines", Flag: "-" + flagPrefix + "-file-max-lines", Default: "0", Description: "Largest allowed file in this language, 0 uses rules.fileSize.maxLines"},
+		{Key: "testFiles.rules.fileSize.maxLines", Flag: "-test-file-max-lines", Default: "0", Description: "Largest allowed test file, 0 uses the language limit"},
+	}
+}
+
+// BranchesOptions returns the options of a language's long-branch-chain rule
+func BranchesOptions() []RuleOption {
+	return []RuleOption{
+		{Key: "rules.branches.enabled", Flag: "-enable-branches", Default: "true", Description: "Report long switch statements and if-else chains"},
+		{Key: "rules.branches.maxBranches", Flag: "-max-branches", Default: "10", Description: "Most branches allowed in one switch, match statement or if-else chain"},
+	}
+}
+
+// ConcurrencyOptions returns the options of a language's concurrency rules
+func ConcurrencyOptions() []RuleOption {
+	return []RuleOption{
+		{Key: "rules.concurrency.enabled", Flag: "-enable-concurrency", Default: "true", Description: "Report leaked goroutines, copied locks, defers in loops and sleeps used to synchronize"},
+	}
+}
+
+// OvercommentingOptions returns the options of the overcommenting rule
+func OvercommentingOptions() []RuleOption {
+	return []RuleOption{
+		{Key: "rules.overcommenting.enabled", Flag: "-enable-comments", Default: "true", Description: "Run the comment rules"},
+		{Key: "rules.overcommenting.maxCommentRatio", Flag: "-comment-max-ratio", Default: "0.3", Description: "Highest allowed share of comment lines in a file"},
+	}
+}
+
+// RedundantCommentOptions returns the options of the redundant-comment rule
+func RedundantCommentOptions() []RuleOption {
+	return []RuleOption{
+		{Key: "rules.overcommenting.enabled", Flag: "-enable-comments", Default: "true", Description: "Run the comment rules"},
+		{Key: "rules.overcommenting.checkRedundant", Flag: "-check-redundant", Default: "true", Description: "Report comments that restate the code"},
+		{Key: "rules.overcommenting.redundantThreshold", Flag: "-redundant-threshold", Default: "0.6", Description: "Share of a comment's words found in the code at which it is redundant"},
+	}
+}
+
+// AICommentOptions returns the options of the ai-comment-fingerprint rule
+func AICommentOptions() []RuleOption {
+	return []RuleOption{
+		{Key: "rules.aiComments.enabled", Flag: "-enable-ai-comments", Default: "true", Description: "Report comments with chat-assistant boilerplate"},
+		{Key: "rules.aiComments.phrases", Flag: "-ai-comment-phrases", Default: "none", Description: "Extra phrases to report, matched case-insensitively"},
+	}
+}
+
+// EndpointOptions returns the options of a language's hardcoded-endpoint rule
+func EndpointOptions() []RuleOption {
+	return []RuleOption{
+		{Key: "rules.endpoints.enabled", Flag: "-enable-endpoints", Default: "true", Description: "Report hardcoded http:// URLs, example hosts, IP addresses and ports"},
+		{Key: "rules.endpoints.allow", Flag: "-allow-endpoints", Default: "none", Description: "Hosts, with their subdomains, or whole endpoints that may be hardcoded"},
+	}
+}
+
+// TestQualityOptions returns the options of a language's assertion-free-test and
+// trivial-getter-test rules
+func TestQualityOptions() []RuleOption {
+	return []RuleOption{
+		{Key: "rules.testQuality.enabled", Flag: "-enable-test-quality", Default: "true", Description: "Report tests without assertions, near-duplicate tests and tests of trivial getters"},
+	}
+}
+
+// DuplicateTestOptions returns the options of a language's duplicate-test rule
+func DuplicateTestOptions() []RuleOption {
+	return append(TestQualityOptions(),
+		RuleOption{Key: "rules.testQuality.threshold", Flag: "-test-duplicate-threshold", Default: "0.9", Description: "Share of code two tests of the same functions have in common to be near duplicates"})
+}
+
+// UnusedFunctionOptions returns the options of a language's unused-function rule
+func UnusedFunctionOptions() []RuleOption {
+	return orphanedCodeOptions(RuleOption{Key: "rules.orphanedCode.checkUnusedFunctions", Flag: "-check-unused-funcs", Default: "true", Description: "Report functions that are never called"})
+}
+
+// UnusedVariableOptions returns the options of a language's unused-variable rule
+func UnusedVariableOptions() []RuleOption {
+	return orphanedCodeOptions(RuleOption{Key: "rules.orphanedCode.checkUnusedVariables", Flag: "-check-unused-vars", Default: "true", Description: "Report variables that are never read"})
+}
+
+// UnreachableCodeOptions returns the options of a language's unreachable-code rule
+func UnreachableCodeOptions() []RuleOption {
+	return orphanedCodeOptions(RuleOption{Key: "rules.orphanedCode.checkUnreachableCode", Flag: "-check-unreachable", Default: "true", Description: "Report statements that can never run"})
+}
+
+// DeadImportOptions returns the options of a language's dead-import rule
+func DeadImportOptions() []RuleOption {
+	return orphanedCodeOptions(RuleOption{Key: "rules.orphanedCode.checkDeadImports", Flag: "-check-dead-imports", Default: "true", Description: "Report imports that are never used"})
+}
+
+// orphanedCodeOptions returns the options of an orphaned code rule switched on and off by check
+func orphanedCodeOptions(check RuleOption) []RuleOption {
+	return []RuleOption{
+		{Key: "rules.orphanedCode.enabled", Flag: "-enable-orphaned", Default: "true", Description: "Run the orphaned code rules"},
+		check,
+	}
+}
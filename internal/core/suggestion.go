@@ -0,0 +1,61 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// AnyRule keys the suggestion template applied to the rules without one of their own
+const AnyRule = "*"
+
+// SuggestionTemplates replace the generic suggestions of rules with a project's own guidance,
+// by rule ID
+type SuggestionTemplates map[string]*template.Template
+
+// ParseSuggestionTemplate parses the suggestion template of a rule: a text/template over
+// Result, in which {{.Suggestion}} is the rule's own suggestion. The template is also run
+// against an empty result, so a reference to a field that does not exist is reported here.
+func ParseSuggestionTemplate(ruleID, text string) (*template.Template, error) {
+	tmpl, err := template.New(ruleID).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid suggestion template for %s: %w", ruleID, err)
+	}
+	if err := tmpl.Execute(new(strings.Builder), Result{}); err != nil {
+		return nil, fmt.Errorf("invalid suggestion template for %s: %w", ruleID, err)
+	}
+	return tmpl, nil
+}
+
+// ParseSuggestionTemplates parses the suggestion templates of output.suggestions, see
+// ParseSuggestionTemplate
+func ParseSuggestionTemplates(texts map[string]string) (SuggestionTemplates, error) {
+	templates := make(SuggestionTemplates, len(texts))
+	for ruleID, text := range texts {
+		tmpl, err := ParseSuggestionTemplate(ruleID, text)
+		if err != nil {
+			return nil, err
+		}
+		templates[ruleID] = tmpl
+	}
+	return templates, nil
+}
+
+// Apply replaces the suggestion of each result with its rule's template, or the AnyRule
+// template, executed over the result. Results of rules without a template keep theirs.
+func (t SuggestionTemplates) Apply(results []Result) error {
+	for i := range results {
+		tmpl, ok := t[results[i].RuleID]
+		if !ok {
+			if tmpl, ok = t[AnyRule]; !ok {
+				continue
+			}
+		}
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, results[i]); err != nil {
+			return fmt.Errorf("applying the suggestion template for %s: %w", results[i].RuleID, err)
+		}
+		results[i].Suggestion = strings.TrimSpace(sb.String())
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+func TestSuggestionTemplates_Apply(t *testing.T) {
+	templates, err := core.ParseSuggestionTemplates(map[string]string{
+		"large-function": "{{.Suggestion}}; extract helpers into internal/util, see CONTRIBUTING.md",
+		core.AnyRule:     "{{.Suggestion}} ({{.RuleID}} in {{.FilePath}})",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := []core.Result{
+		{RuleID: "large-function", FilePath: "main.go", Suggestion: "Split the function"},
+		{RuleID: "magic-number", FilePath: "util.go", Suggestion: "Name the constant"},
+	}
+	if err := templates.Apply(results); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Split the function; extract helpers into internal/util, see CONTRIBUTING.md"; results[0].Suggestion != want {
+		t.Errorf("Expected the rule's template, got %q", results[0].Suggestion)
+	}
+	if want := "Name the constant (magic-number in util.go)"; results[1].Suggestion != want {
+		t.Errorf("Expected the template for any rule, got %q", results[1].Suggestion)
+	}
+
+	results = []core.Result{{RuleID: "console-log", Suggestion: "Remove it"}}
+	if err := core.SuggestionTemplates(nil).Apply(results); err != nil || results[0].Suggestion != "Remove it" {
+		t.Errorf("Expected the suggestion kept without templates, got %q (%v)", results[0].Suggestion, err)
+	}
+}
+
+func TestParseSuggestionTemplate_Invalid(t *testing.T) {
+	for _, text := range []string{"{{.Suggestion", "{{.Advice}}"} {
+		if _, err := core.ParseSuggestionTemplate("large-function", text); err == nil {
+			t.Errorf("Expected %q to be refused", text)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+)
+
+// SystemicRuleID is the rule of the findings Escalate adds for pervasive smells
+const SystemicRuleID = "systemic-smell"
+
+func init() {
+	RegisterRuleDoc(RuleDoc{
+		ID:          SystemicRuleID,
+		Name:        "Systemic Smell",
+		Description: "Reports smells that are pervasive in a file or package rather than isolated",
+		Rationale: "A smell that shows up everywhere usually comes from a habit or a generator and is " +
+			"better fixed at its source than one finding at a time. An error-level finding is added, with " +
+			"the category of the findings it groups, when one rule reports more than maxFileFindings " +
+			"findings in a file, or large-function reports more than maxPackageRatio of the functions in " +
+			"a directory. The individual findings are still reported.",
+		Severity: SeverityError,
+		Examples: []RuleExample{{
+			Bad:  "// Screen.tsx: 25 console.log calls, one console-log finding each",
+			Good: "// Screen.tsx: a logger that is silenced in production, called where it helps",
+		}},
+		Options: []RuleOption{
+			{Key: "rules.systemic.enabled", Flag: "-enable-systemic", Default: "true", Description: "Report pervasive smells with an additional error-level finding"},
+			{Key: "rules.systemic.maxFileFindings", Flag: "-systemic-max-findings", Default: "20", Description: "Findings of one rule allowed per file, 0 disables the check"},
+			{Key: "rules.systemic.maxPackageRatio", Flag: "-systemic-max-ratio", Default: "0.3", Description: "Share of a package's functions one rule may report, 0 disables the check"},
+		},
+	})
+}
+
+// minPackageFindings is the fewest findings of a function rule in a directory that Escalate
+// treats as pervasive, so a package of two functions is not escalated for one large function
+const minPackageFindings = 3
+
+// packageRules are the rules reporting one finding per function, whose findings Escalate
+// compares with the number of functions in the directory
+var packageRules = map[string]bool{
+	"large-function": true,
+}
+
+// FunctionCounter is implemented by analyzers that can count the functions of a file, so the
+// share of a package's functions reported by a rule can be computed
+type FunctionCounter interface {
+	CountFunctions(ctx context.Context, filePath string) (int, error)
+}
+
+// Escalate adds an error-level systemic-smell finding for each smell that is pervasive rather
+// than isolated: a rule reporting more than MaxFileFindings results in one file, or a function
+// rule such as large-function reporting more than MaxPackageRatio of the functions in one
+// directory. countFunctions returns the number of functions analyzed in a directory. The
+// individual findings are kept, and the systemic findings are appended in order of the first
+// finding of each group.
+func Escalate(results []Result, config SystemicConfig, countFunctions func(dir string) int) []Result {
+	if !config.Enabled {
+		return results
+	}
+
+	type key struct {
+		path   string
+		ruleID string
+	}
+	var fileKeys, dirKeys []key
+	fileFindings := make(map[key][]Result)
+	dirFindings := make(map[key][]Result)
+	for _, result := range results {
+		if result.RuleID == SystemicRuleID {
+			continue
+		}
+		k := key{result.FilePath, result.RuleID}
+		if _, ok := fileFindings[k]; !ok {
+			fileKeys = append(fileKeys, k)
+		}
+		fileFindings[k] = append(fileFindings[k], result)
+
+		if packageRules[result.RuleID] {
+			k = key{filepath.Dir(result.FilePath), result.RuleID}
+			if _, ok := dirFindings[k]; !ok {
+				dirKeys = append(dirKeys, k)
+			}
+			dirFindings[k] = append(dirFindings[k], result)
+		}
+	}
+
+	escalated := results
+	if config.MaxFileFindings > 0 {
+		for _, k := range fileKeys {
+			findings := fileFindings[k]
+			if len(findings) <= config.MaxFileFindings {
+				continue
+			}
+			escalated = append(escalated, systemicResult(findings[0],
+				fmt.Sprintf("%d %s findings in one file exceed %d: the smell is pervasive", len(findings), k.ruleID, config.MaxFileFindings),
+				"Fix the cause across the whole file, e.g. with a shared helper or a single refactoring, rather than one finding at a time"))
+		}
+	}
+	if config.MaxPackageRatio > 0 && countFunctions != nil {
+		for _, k := range dirKeys {
+			findings := dirFindings[k]
+			if len(findings) < minPackageFindings {
+				continue
+			}
+			total := countFunctions(k.path)
+			if total == 0 || float64(len(findings))/float64(total) <= config.MaxPackageRatio {
+				continue
+			}
+			escalated = append(escalated, systemicResult(findings[0],
+				fmt.Sprintf("%d of %d functions in %s (%.0f%%) are reported by %s, more than %.0f%%: the smell is pervasive",
+					len(findings), total, k.path, 100*float64(len(findings))/float64(total), k.ruleID, 100*config.MaxPackageRatio),
+				"Review how the package is structured rather than splitting one function at a time"))
+		}
+	}
+	return escalated
+}
+
+// systemicResult builds the systemic-smell finding for a group of results, reported at the
+// first of them
+func systemicResult(first Result, message, suggestion string) Result {
+	return Result{
+		RuleID:     SystemicRuleID,
+		RuleName:   "Systemic Smell",
+		Category:   first.Category,
+		Severity:   string(SeverityError),
+		Confidence: first.Confidence,
+		FilePath:   first.FilePath,
+		Line:       first.Line,
+		Message:    message,
+		Suggestion: suggestion,
+	}
+}
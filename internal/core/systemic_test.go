@@ -0,0 +1,60 @@
+package core_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+func TestEscalate(t *testing.T) {
+	var results []core.Result
+	for i := 0; i < 21; i++ {
+		results = append(results, core.Result{RuleID: "console-log", Category: "style", Severity: "warning", FilePath: "app/Screen.js", Line: 10 + i})
+	}
+	for i := 0; i < 20; i++ {
+		results = append(results, core.Result{RuleID: "console-log", Category: "style", Severity: "warning", FilePath: "app/Other.js", Line: 10 + i})
+	}
+	for i := 0; i < 4; i++ {
+		results = append(results, core.Result{RuleID: "large-function", Category: "size", Severity: "warning", FilePath: fmt.Sprintf("pkg/big/f%d.go", i%2), Line: 5 + i})
+	}
+	for i := 0; i < 4; i++ {
+		results = append(results, core.Result{RuleID: "large-function", Category: "size", Severity: "warning", FilePath: "pkg/fine/f.go", Line: 5 + i})
+	}
+	results = append(results, core.Result{RuleID: "large-function", Category: "size", Severity: "warning", FilePath: "pkg/small/f.go", Line: 5})
+
+	counts := map[string]int{"pkg/big": 10, "pkg/fine": 20, "pkg/small": 1}
+	config := core.SystemicConfig{Enabled: true, MaxFileFindings: 20, MaxPackageRatio: 0.3}
+	got := core.Escalate(results, config, func(dir string) int { return counts[dir] })
+
+	if len(got) != len(results)+2 {
+		t.Fatalf("expected 2 systemic findings after %d results, got %d results", len(results), len(got))
+	}
+	want := []struct {
+		file     string
+		line     int
+		category string
+	}{
+		{"app/Screen.js", 10, "style"},
+		{"pkg/big/f0.go", 5, "size"},
+	}
+	for i, w := range want {
+		result := got[len(results)+i]
+		if result.RuleID != core.SystemicRuleID || result.Severity != string(core.SeverityError) {
+			t.Errorf("result %d: expected an error-level %s finding, got %+v", i, core.SystemicRuleID, result)
+		}
+		if result.FilePath != w.file || result.Line != w.line || result.Category != w.category {
+			t.Errorf("result %d: expected %s:%d (%s), got %s:%d (%s)", i, w.file, w.line, w.category, result.FilePath, result.Line, result.Category)
+		}
+	}
+
+	config.Enabled = false
+	if got := core.Escalate(results, config, nil); len(got) != len(results) {
+		t.Errorf("expected no systemic findings when disabled, got %d results", len(got))
+	}
+
+	config = core.SystemicConfig{Enabled: true}
+	if got := core.Escalate(results, config, func(string) int { return 1 }); len(got) != len(results) {
+		t.Errorf("expected zero thresholds to disable the checks, got %d results", len(got))
+	}
+}
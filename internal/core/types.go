@@ -4,28 +4,72 @@ import "context"
 
 // Result represents a finding from a rule
 type Result struct {
-	RuleID     string `json:"rule_id"`
-	RuleName   string `json:"rule_name"`
-	Category   string `json:"category"`
-	Severity   string `json:"severity"`
-	FilePath   string `json:"file_path"`
-	Line       int    `json:"line"`
-	Column     int    `json:"column"`
-	Message    string `json:"message"`
-	Suggestion string `json:"suggestion,omitempty"`
-}
-
-// RuleCategory defines the category of a rule
+	RuleID     string   `json:"rule_id"`
+	RuleName   string   `json:"rule_name"`
+	Category   string   `json:"category"`
+	Severity   string   `json:"severity"`
+	Confidence string   `json:"confidence,omitempty"` // how likely the finding is a real problem, see Confidence
+	FilePath   string   `json:"file_path"`
+	Line       int      `json:"line"`
+	Column     int      `json:"column"`               // 1-based, in characters rather than bytes; 0 when unknown
+	EndLine    int      `json:"end_line,omitempty"`   // last line of the region the finding covers, such as a whole function; 0 for a single position
+	EndColumn  int      `json:"end_column,omitempty"` // column just past the region on EndLine, in characters; 0 for the end of the line
+	Message    string   `json:"message"`
+	Suggestion string   `json:"suggestion,omitempty"`
+	Module     string   `json:"module,omitempty"` // Go module path, set when the project contains modules
+	Root       string   `json:"root,omitempty"`   // path given on the command line, set when several are analyzed
+	Owners     []string `json:"owners,omitempty"` // owners of the file in the repository's CODEOWNERS file
+	Blocking   bool     `json:"blocking"`         // whether this result fails the run, see OutputConfig.IsBlocking
+
+	// Uncovered marks a finding in code the tests never ran, according to the coverage report
+	// given with -coverage; such findings are the riskiest to leave
+	Uncovered bool `json:"uncovered,omitempty"`
+
+	// AIGenerated marks a finding in a file an AI assistant wrote, see AIFilesConfig
+	AIGenerated bool `json:"ai_generated,omitempty"`
+
+	// External marks a finding in vendored or third-party code, analyzed only when
+	// FilesConfig.External is set and reported apart from the project's own code
+	External bool `json:"external,omitempty"`
+
+	// Related points at a second place the finding is about, such as the other function of a
+	// pair of similar functions; nil when the finding has one location
+	Related *Location `json:"related,omitempty"`
+
+	// Fingerprint identifies the finding across runs independently of its line number, see Fingerprint
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// Location is a place in a source file
+type Location struct {
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line"`
+}
+
+// FileError records a file that could not be analyzed
+type FileError struct {
+	FilePath string `json:"file_path"`
+	Message  string `json:"message"`
+}
+
+// Error implements the error interface
+func (e FileError) Error() string {
+	return e.FilePath + ": " + e.Message
+}
+
+// RuleCategory defines the category of a rule. Every rule of every language uses one of the
+// categories below, so results can be filtered by category, see FilterCategories.
 type RuleCategory string
 
 const (
-	CategorySize        RuleCategory = "size"
-	CategoryComments    RuleCategory = "comments"
-	CategoryOrphaned    RuleCategory = "orphaned"
-	CategoryPerformance RuleCategory = "performance"
-	CategoryDeprecated  RuleCategory = "deprecated"
-	CategoryStyle       RuleCategory = "style"
-	CategoryBug         RuleCategory = "bug"
+	CategorySize        RuleCategory = "size"        // functions, files and types that grew too large
+	CategoryComplexity  RuleCategory = "complexity"  // deep nesting, long branch chains, duplicated code
+	CategoryComments    RuleCategory = "comments"    // too many, redundant or missing comments and docstrings
+	CategoryOrphaned    RuleCategory = "orphaned"    // code nothing uses or can reach
+	CategoryPerformance RuleCategory = "performance" // code that is slow at run time, such as re-rendering React components
+	CategoryDeprecated  RuleCategory = "deprecated"  // APIs that are going away
+	CategoryStyle       RuleCategory = "style"       // code that works but reads badly
+	CategoryBug         RuleCategory = "bug"         // code likely to misbehave
 )
 
 // Severity defines the severity level of a result
@@ -37,6 +81,25 @@ const (
 	SeverityInfo    Severity = "info"
 )
 
+// severityRanks orders severities from least to most severe
+var severityRanks = map[Severity]int{
+	SeverityInfo:    1,
+	SeverityWarning: 2,
+	SeverityError:   3,
+}
+
+// Rank returns the ordinal of the severity, or 0 if it is unknown
+func (s Severity) Rank() int {
+	return severityRanks[s]
+}
+
+// MeetsThreshold reports whether the severity is at or above the given threshold.
+// Unknown thresholds (such as "none") are never met.
+func (s Severity) MeetsThreshold(threshold Severity) bool {
+	rank := threshold.Rank()
+	return rank > 0 && s.Rank() >= rank
+}
+
 // Analyzer interface for language-specific implementations
 type Analyzer interface {
 	Analyze(ctx context.Context, filePath string, config Config) ([]Result, error)
@@ -44,64 +107,409 @@ type Analyzer interface {
 	Name() string
 }
 
-// Rule interface for individual detection rules
+// Rule interface for individual detection rules. Rationale, Examples and Options document
+// the rule for agentlint explain.
 type Rule interface {
 	ID() string
 	Name() string
 	Description() string
+	Rationale() string
+	Examples() []RuleExample
+	Options() []RuleOption
 	Category() RuleCategory
 	Severity() Severity
 	Check(ctx context.Context, node interface{}, config Config) *Result
 }
 
+// RuleExample pairs code a rule reports with a version of it the rule accepts
+type RuleExample struct {
+	Bad  string
+	Good string
+}
+
+// RuleOption is a configuration setting that changes what a rule reports
+type RuleOption struct {
+	Key         string // path in the configuration file, e.g. rules.functionSize.maxLines
+	Flag        string // equivalent command-line flag, e.g. -func-max-lines
+	Default     string
+	Description string
+}
+
 // Config represents the configuration for AgentLint
 type Config struct {
-	Rules    RulesConfig    `yaml:"rules"`
-	Output   OutputConfig   `yaml:"output"`
-	Language LanguageConfig `yaml:"language"`
+	// Profile names a bundled or custom set of rule settings applied under the configuration
+	// files: strict, default, relaxed, or a profile defined in their profiles section
+	Profile string `yaml:"profile"`
+
+	Rules     RulesConfig     `yaml:"rules"`
+	Output    OutputConfig    `yaml:"output"`
+	Language  LanguageConfig  `yaml:"language"`
+	TestFiles TestFilesConfig `yaml:"testFiles"`
+	AIFiles   AIFilesConfig   `yaml:"aiFiles"`
+	Parsing   ParsingConfig   `yaml:"parsing"`
+	Files     FilesConfig     `yaml:"files"`
+
+	testFile bool // set by ForTestFile
+}
+
+// ForTestFile returns the configuration for analyzing a test file: thresholds are relaxed by
+// TestFiles.Rules and the rules listed in TestFiles.DisabledRules are switched off
+func (c Config) ForTestFile() Config {
+	c.Rules = c.TestFiles.Rules.Apply(c.Rules)
+	c.testFile = true
+	return c
+}
+
+// ForAIFile returns the configuration for analyzing a file an AI assistant wrote: thresholds
+// are tightened by AIFiles.Rules
+func (c Config) ForAIFile() Config {
+	c.Rules = c.AIFiles.Rules.Apply(c.Rules)
+	return c
+}
+
+// RuleDisabled reports whether a rule is switched off for the file being analyzed
+func (c Config) RuleDisabled(ruleID string) bool {
+	if !c.testFile {
+		return false
+	}
+	for _, id := range c.TestFiles.DisabledRules {
+		if id == ruleID {
+			return true
+		}
+	}
+	return false
 }
 
 // RulesConfig contains configuration for all rules
 type RulesConfig struct {
-	FunctionSize   FunctionSizeConfig   `yaml:"functionSize"`
-	FileSize       FileSizeConfig       `yaml:"fileSize"`
-	Overcommenting OvercommentingConfig `yaml:"overcommenting"`
-	OrphanedCode   OrphanedCodeConfig   `yaml:"orphanedCode"`
+	FunctionSize    FunctionSizeConfig    `yaml:"functionSize"`
+	FileSize        FileSizeConfig        `yaml:"fileSize"`
+	Overcommenting  OvercommentingConfig  `yaml:"overcommenting"`
+	OrphanedCode    OrphanedCodeConfig    `yaml:"orphanedCode"`
+	TypeSize        TypeSizeConfig        `yaml:"typeSize"`
+	Returns         ReturnsConfig         `yaml:"returns"`
+	AIComments      AICommentsConfig      `yaml:"aiComments"`
+	Docstrings      DocstringsConfig      `yaml:"docstrings"`
+	Dependencies    DependenciesConfig    `yaml:"dependencies"`
+	TypeSafety      TypeSafetyConfig      `yaml:"typeSafety"`
+	TypeHints       TypeHintsConfig       `yaml:"typeHints"`
+	Systemic        SystemicConfig        `yaml:"systemic"`
+	DuplicateErrors DuplicateErrorsConfig `yaml:"duplicateErrors"`
+	Similarity      SimilarityConfig      `yaml:"similarity"`
+	Branches        BranchesConfig        `yaml:"branches"`
+	Concurrency     ConcurrencyConfig     `yaml:"concurrency"`
+	Layout          LayoutConfig          `yaml:"layout"`
+	Headers         HeadersConfig         `yaml:"headers"`
+	Endpoints       EndpointsConfig       `yaml:"endpoints"`
+	TestQuality     TestQualityConfig     `yaml:"testQuality"`
+	Components      ComponentsConfig      `yaml:"components"`
 }
 
 // FunctionSizeConfig contains configuration for function size rules
 type FunctionSizeConfig struct {
-	Enabled  bool `yaml:"enabled"`
-	MaxLines int  `yaml:"maxLines"`
+	Enabled  bool               `yaml:"enabled"`
+	MaxLines int                `yaml:"maxLines"`
+	Metric   FunctionSizeMetric `yaml:"metric"` // how MaxLines is measured, defaults to lines
 }
 
+// FunctionSizeMetric selects how the size of a function is measured
+type FunctionSizeMetric string
+
+const (
+	// MetricLines counts physical lines, including comments and blank lines
+	MetricLines FunctionSizeMetric = "lines"
+	// MetricStatements counts statements in Go and logical lines in Python and JavaScript
+	MetricStatements FunctionSizeMetric = "statements"
+)
+
 // FileSizeConfig contains configuration for file size rules
 type FileSizeConfig struct {
-	Enabled  bool `yaml:"enabled"`
-	MaxLines int  `yaml:"maxLines"`
+	Enabled   bool              `yaml:"enabled"`
+	MaxLines  int               `yaml:"maxLines"`
+	CountMode FileSizeCountMode `yaml:"countMode"` // which lines count towards MaxLines, defaults to total
+}
+
+// FileSizeCountMode selects which lines of a file count towards its size
+type FileSizeCountMode string
+
+const (
+	// CountTotal counts every line of the file
+	CountTotal FileSizeCountMode = "total"
+	// CountCode counts only lines holding code, skipping comments, docstrings and blank lines
+	CountCode FileSizeCountMode = "code"
+)
+
+// TypeSizeConfig contains configuration for type-level size rules
+type TypeSizeConfig struct {
+	Enabled       bool `yaml:"enabled"`
+	MaxFields     int  `yaml:"maxFields"` // struct fields, or attributes of a Python class
+	MaxMethods    int  `yaml:"maxMethods"`
+	MaxClassLines int  `yaml:"maxClassLines"` // lines a Python class may span
+}
+
+// ReturnsConfig contains configuration for return value rules
+type ReturnsConfig struct {
+	Enabled             bool `yaml:"enabled"`
+	MaxValues           int  `yaml:"maxValues"`
+	NakedReturnMaxLines int  `yaml:"nakedReturnMaxLines"`
 }
 
 // OvercommentingConfig contains configuration for comment analysis rules
 type OvercommentingConfig struct {
-	Enabled           bool    `yaml:"enabled"`
-	MaxCommentRatio   float64 `yaml:"maxCommentRatio"`
-	CheckRedundant    bool    `yaml:"checkRedundant"`
-	CheckDocCoverage  bool    `yaml:"checkDocCoverage"`
+	Enabled            bool    `yaml:"enabled"`
+	MaxCommentRatio    float64 `yaml:"maxCommentRatio"`
+	CheckRedundant     bool    `yaml:"checkRedundant"`
+	CheckDocCoverage   bool    `yaml:"checkDocCoverage"`
+	RedundantThreshold float64 `yaml:"redundantThreshold"` // minimum comment/code word overlap, 0.0 to 1.0
+}
+
+// AICommentsConfig contains configuration for LLM comment fingerprint detection
+type AICommentsConfig struct {
+	Enabled bool     `yaml:"enabled"`
+	Phrases []string `yaml:"phrases"` // additional phrases, matched case-insensitively
+}
+
+// DocstringsConfig contains configuration for docstring quality rules (Python)
+type DocstringsConfig struct {
+	Enabled           bool `yaml:"enabled"`
+	CheckPlaceholders bool `yaml:"checkPlaceholders"`
+	CheckParameters   bool `yaml:"checkParameters"`
+}
+
+// DependenciesConfig contains configuration for import graph analysis
+type DependenciesConfig struct {
+	Enabled     bool `yaml:"enabled"`
+	CheckCycles bool `yaml:"checkCycles"`
+	MaxDepth    int  `yaml:"maxDepth"` // longest allowed import chain, 0 disables the check
+}
+
+// BranchesConfig contains configuration for long switch and if-else chain detection
+type BranchesConfig struct {
+	Enabled     bool `yaml:"enabled"`
+	MaxBranches int  `yaml:"maxBranches"` // branches allowed in one switch, match statement or if-else chain
+}
+
+// ConcurrencyConfig contains configuration for goroutine leak, lock copy, defer-in-loop and
+// sleep-based synchronization detection
+type ConcurrencyConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// LayoutConfig contains configuration for package and file layout detection (Go)
+type LayoutConfig struct {
+	Enabled           bool     `yaml:"enabled"`
+	GenericPackages   []string `yaml:"genericPackages"`   // catch-all package names, reported when two or more packages use them
+	GenericFiles      []string `yaml:"genericFiles"`      // file names, without .go or trailing digits, that say nothing about their contents
+	CheckStubFiles    bool     `yaml:"checkStubFiles"`    // report packages with one file of code next to files that declare nothing
+	CheckPackageNames bool     `yaml:"checkPackageNames"` // report packages named differently from their directory
+}
+
+// EndpointsConfig contains configuration for hardcoded URL, IP address and port detection
+type EndpointsConfig struct {
+	Enabled bool     `yaml:"enabled"`
+	Allow   []string `yaml:"allow"` // hosts, with their subdomains, or whole endpoints such as localhost:8080 that may be hardcoded
+}
+
+// TestQualityConfig contains configuration for assertion-free, duplicated and trivial test detection
+type TestQualityConfig struct {
+	Enabled       bool    `yaml:"enabled"`
+	Threshold     float64 `yaml:"threshold"`     // share of code two tests of the same functions have in common to be near duplicates, 0.0 to 1.0
+	CheckUntested bool    `yaml:"checkUntested"` // report exported Go functions no test of their package refers to
+}
+
+// ComponentsConfig contains configuration for React component complexity detection. A limit of
+// zero or less is not checked.
+type ComponentsConfig struct {
+	Enabled     bool `yaml:"enabled"`
+	MaxHooks    int  `yaml:"maxHooks"`    // hook calls in one function component
+	MaxProps    int  `yaml:"maxProps"`    // props destructured by one component
+	MaxJSXDepth int  `yaml:"maxJSXDepth"` // nesting depth of the JSX elements one component renders
+}
+
+// HeadersConfig contains configuration for drifted file header detection
+type HeadersConfig struct {
+	Enabled   bool    `yaml:"enabled"`
+	MinFiles  int     `yaml:"minFiles"`  // files that must share a header before near copies of it are reported
+	Threshold float64 `yaml:"threshold"` // word similarity at which a header is a near copy, 0.0 to 1.0
+	Template  string  `yaml:"template"`  // file holding the expected header as plain text; "" takes the project's most common headers
+}
+
+// DuplicateErrorsConfig contains configuration for repeated error message detection (Go)
+type DuplicateErrorsConfig struct {
+	Enabled        bool    `yaml:"enabled"`
+	MinOccurrences int     `yaml:"minOccurrences"` // occurrences in a package before a message is reported
+	Threshold      float64 `yaml:"threshold"`      // word similarity at which messages are near-identical, 0.0 to 1.0
+}
+
+// SimilarityConfig contains configuration for similar function detection (Go)
+type SimilarityConfig struct {
+	Enabled       bool    `yaml:"enabled"`
+	Threshold     float64 `yaml:"threshold"`     // Jaccard similarity of two functions' token shingles at which they are reported, 0.0 to 1.0
+	MinStatements int     `yaml:"minStatements"` // functions with fewer statements are not compared
+	MinTokens     int     `yaml:"minTokens"`     // functions with fewer normalized tokens are not compared
+}
+
+// TypeSafetyConfig contains TypeScript type safety detection configuration
+type TypeSafetyConfig struct {
+	Enabled         bool `yaml:"enabled"`
+	MaxAny          int  `yaml:"maxAny"`          // 'any' annotations and casts allowed per file
+	MaxSuppressions int  `yaml:"maxSuppressions"` // @ts-ignore, @ts-expect-error and @ts-nocheck comments allowed per file
+}
+
+// TypeHintsConfig contains Python type hint coverage configuration
+type TypeHintsConfig struct {
+	Enabled     bool    `yaml:"enabled"`
+	MinCoverage float64 `yaml:"minCoverage"` // share of fully annotated signatures required per file, 0.0 to 1.0
+}
+
+// SystemicConfig controls the escalation of pervasive smells, see Escalate
+type SystemicConfig struct {
+	Enabled         bool    `yaml:"enabled"`
+	MaxFileFindings int     `yaml:"maxFileFindings"` // findings of one rule allowed per file, 0 disables the check
+	MaxPackageRatio float64 `yaml:"maxPackageRatio"` // share of a directory's functions one rule may report, 0 disables the check
 }
 
 // OrphanedCodeConfig contains configuration for orphaned code detection
 type OrphanedCodeConfig struct {
-	Enabled              bool `yaml:"enabled"`
-	CheckUnusedFunctions bool `yaml:"checkUnusedFunctions"`
-	CheckUnusedVariables bool `yaml:"checkUnusedVariables"`
-	CheckUnreachableCode bool `yaml:"checkUnreachableCode"`
-	CheckDeadImports     bool `yaml:"checkDeadImports"`
+	Enabled                bool     `yaml:"enabled"`
+	CheckUnusedFunctions   bool     `yaml:"checkUnusedFunctions"`
+	CheckUnusedVariables   bool     `yaml:"checkUnusedVariables"`
+	CheckUnreachableCode   bool     `yaml:"checkUnreachableCode"`
+	CheckDeadImports       bool     `yaml:"checkDeadImports"`
+	IncludeExported        bool     `yaml:"includeExported"`        // also report dead exported Go symbols in modules nothing imports
+	IndexFile              string   `yaml:"indexFile"`              // where the project-wide Go index is kept between runs, "" to rebuild it each run
+	IgnoreFunctionPatterns []string `yaml:"ignoreFunctionPatterns"` // globs or /regexp/ of Go function names never reported as unused
+	IgnoreReceivers        []string `yaml:"ignoreReceivers"`        // globs or /regexp/ of receiver types whose methods are never reported
+	MinStatements          int      `yaml:"minStatements"`          // Go functions with fewer statements are never reported as unused
+}
+
+// NotifyConfig contains the settings of the run summary posted to a Slack or Microsoft Teams
+// incoming webhook
+type NotifyConfig struct {
+	Webhook   string `yaml:"webhook"`   // incoming webhook URL; "" posts nothing
+	Format    string `yaml:"format"`    // slack, teams, or "" to tell from the webhook URL
+	Baseline  string `yaml:"baseline"`  // JSON report of an earlier run to count new and fixed findings against
+	ReportURL string `yaml:"reportURL"` // link to the full report, such as a CI artifact
+}
+
+// LLMConfig contains the settings of the refactoring suggestions asked of a language model
+// behind an OpenAI-compatible API for the findings selected by -llm-suggest. Nothing is sent
+// unless a run selects findings.
+type LLMConfig struct {
+	Endpoint     string   `yaml:"endpoint"`     // base URL of the API, e.g. http://localhost:11434/v1
+	Model        string   `yaml:"model"`        // model to ask, e.g. gpt-4o-mini or llama3
+	MaxFindings  int      `yaml:"maxFindings"`  // most findings sent per run; 0 sends up to 10
+	ContextLines int      `yaml:"contextLines"` // source lines sent on each side of a finding; 0 sends 10
+	OmitSource   bool     `yaml:"omitSource"`   // send the finding without any source code
+	Redact       []string `yaml:"redact"`       // regular expressions masked in what is sent, on top of the built-in secret patterns
+	Timeout      int      `yaml:"timeout"`      // seconds allowed for each answer; 0 allows 60
 }
 
 // OutputConfig contains configuration for output formatting
 type OutputConfig struct {
-	Format  string `yaml:"format"` // console, json
+	Format  string `yaml:"format"` // console, json, csv, tsv, rdjsonl, template
 	Verbose bool   `yaml:"verbose"`
+	NoColor bool   `yaml:"noColor"` // never color console output; it is colored only on a terminal anyway
+	GroupBy string `yaml:"groupBy"` // how the console groups results: file, rule
+
+	Template string `yaml:"template"` // text/template over Result used by the template format
+	FailOn  string `yaml:"failOn"`  // error, warning, info, none
+
+	FailOnParseErrors bool `yaml:"failOnParseErrors"` // exit non-zero when a file cannot be analyzed
+
+	// MinConfidence drops the results less likely to be real problems: high, medium, or low
+	// and "" to keep every result
+	MinConfidence string `yaml:"minConfidence"`
+
+	// OnlyCategories keeps only the results of these rule categories, all when empty, and
+	// SkipCategories drops the results of these categories
+	OnlyCategories []string `yaml:"onlyCategories"`
+	SkipCategories []string `yaml:"skipCategories"`
+
+	// OnlyRules keeps only the results of these rule IDs, all when empty
+	OnlyRules []string `yaml:"onlyRules"`
+
+	// Severity drops the results less severe than it: error, warning, or info, and "" to
+	// keep every result
+	Severity string `yaml:"severity"`
+
+	// PathFilter keeps only the results in files matching one of these globs, matched against
+	// their path relative to the working directory; all when empty
+	PathFilter []string `yaml:"pathFilter"`
+
+	// Top is the number of files and of rules with the most results listed after the summary,
+	// to prioritize cleanup; 0 leaves the list out
+	Top int `yaml:"top"`
+
+	// Codeowners is the CODEOWNERS file naming the owners of each result's file; "" looks for
+	// it in the repository being analyzed and "none" disables ownership
+	Codeowners string `yaml:"codeowners"`
+
+	// Coverage is a Go coverprofile, LCOV tracefile or coverage.py XML report marking the
+	// results in code the tests never ran; "" leaves coverage out
+	Coverage string `yaml:"coverage"`
+
+	// MetricsFile is where gauges of the run are written in the Prometheus text format, for
+	// node_exporter's textfile collector; "" writes none
+	MetricsFile string `yaml:"metricsFile"`
+
+	// Notify posts a summary of the run to a chat webhook
+	Notify NotifyConfig `yaml:"notify"`
+
+	// LLM asks a language model for suggestions tailored to selected findings
+	LLM LLMConfig `yaml:"llm"`
+
+	// Suggestions replaces the suggestions of rules with a project's own guidance: a
+	// text/template over Result by rule ID, or "*" for the other rules, in which
+	// {{.Suggestion}} is the rule's own suggestion
+	Suggestions map[string]string `yaml:"suggestions"`
+
+	// Blocking overrides FailOn per rule ID: true always fails the run, false never does
+	Blocking map[string]bool `yaml:"blocking"`
+}
+
+// IsBlocking reports whether a result fails the run. Findings in external code never do, as
+// the project cannot fix them in place. A Blocking entry for the rule wins; otherwise the
+// result blocks when its severity meets FailOn.
+func (o OutputConfig) IsBlocking(result Result) bool {
+	if result.External {
+		return false
+	}
+	if blocking, ok := o.Blocking[result.RuleID]; ok {
+		return blocking
+	}
+	return Severity(result.Severity).MeetsThreshold(Severity(o.FailOn))
+}
+
+// TestFilesConfig relaxes rules for test files (_test.go, test_*.py, *.spec.ts, ...) in every language
+type TestFilesConfig struct {
+	DisabledRules []string            `yaml:"disabledRules"` // rule IDs not applied to test files
+	Rules         LanguageRulesConfig `yaml:"rules"`         // threshold overrides for test files
+}
+
+// AIFilesConfig recognizes files written by AI assistants, so they can be held to stricter
+// thresholds and their findings told apart from those in code people wrote
+type AIFilesConfig struct {
+	Markers []string            `yaml:"markers"` // phrases marking a file as AI-generated in a header comment, on top of the built-in ones such as "Generated by Copilot"
+	Paths   []string            `yaml:"paths"`   // globs of AI-generated files, relative to the working directory
+	Rules   LanguageRulesConfig `yaml:"rules"`   // threshold overrides for AI-generated files
+}
+
+// ParsingConfig controls how files that do not parse, very large files and very long lines are handled
+type ParsingConfig struct {
+	Tolerant        bool `yaml:"tolerant"`        // analyze files with syntax errors using line-based rules only
+	StreamThreshold int  `yaml:"streamThreshold"` // size in bytes above which Python and JS/TS files are analyzed in one streaming pass; 0 never streams
+	MaxLineLength   int  `yaml:"maxLineLength"`   // length in bytes above which Python and JS/TS lines are truncated, and JS/TS files skipped as minified; 0 never truncates
+}
+
+// FilesConfig selects the files that are analyzed, see languages.PathFilter
+type FilesConfig struct {
+	Include []string `yaml:"include"` // globs of files to analyze; empty analyzes every supported file
+	Exclude []string `yaml:"exclude"` // globs of files and directories to skip
+
+	// External analyzes vendor directories too, and marks the findings in vendor, third_party
+	// and node_modules directories as external, reported apart from the project's own code
+	External bool `yaml:"external"`
 }
 
 // LanguageConfig contains language-specific configuration
@@ -109,19 +517,78 @@ type LanguageConfig struct {
 	Go          GoConfig          `yaml:"go"`
 	Python      PythonConfig      `yaml:"python"`
 	ReactNative ReactNativeConfig `yaml:"reactnative"`
+
+	// Extensions routes files by extension to another language, e.g. ".mjs": "javascript"
+	Extensions map[string]string `yaml:"extensions"`
 }
 
 // GoConfig contains Go-specific configuration
 type GoConfig struct {
-	IgnoreTests bool `yaml:"ignoreTests"`
+	IgnoreTests bool                `yaml:"ignoreTests"`
+	Rules       LanguageRulesConfig `yaml:"rules"`
 }
 
 // PythonConfig contains Python-specific configuration
 type PythonConfig struct {
-	IgnoreTests bool `yaml:"ignoreTests"`
+	IgnoreTests bool                `yaml:"ignoreTests"`
+	Rules       LanguageRulesConfig `yaml:"rules"`
 }
 
 // ReactNativeConfig contains React Native/JavaScript/TypeScript configuration
 type ReactNativeConfig struct {
-	IgnoreTests bool `yaml:"ignoreTests"`
+	IgnoreTests bool                `yaml:"ignoreTests"`
+	Rules       LanguageRulesConfig `yaml:"rules"`
+}
+
+// LanguageRulesConfig overrides rule thresholds for a single language.
+// Zero values inherit the global rule configuration.
+type LanguageRulesConfig struct {
+	FunctionSize FunctionSizeOverride `yaml:"functionSize"`
+	FileSize     FileSizeOverride     `yaml:"fileSize"`
+}
+
+// FunctionSizeOverride contains per-language overrides for function size rules
+type FunctionSizeOverride struct {
+	MaxLines int                `yaml:"maxLines"`
+	Metric   FunctionSizeMetric `yaml:"metric"`
+}
+
+// FileSizeOverride contains per-language overrides for file size rules
+type FileSizeOverride struct {
+	MaxLines  int               `yaml:"maxLines"`
+	CountMode FileSizeCountMode `yaml:"countMode"`
+}
+
+// Apply returns the rule configuration with the language's overrides applied
+func (o LanguageRulesConfig) Apply(rules RulesConfig) RulesConfig {
+	if o.FunctionSize.MaxLines > 0 {
+		rules.FunctionSize.MaxLines = o.FunctionSize.MaxLines
+	}
+	if o.FunctionSize.Metric != "" {
+		rules.FunctionSize.Metric = o.FunctionSize.Metric
+	}
+	if o.FileSize.MaxLines > 0 {
+		rules.FileSize.MaxLines = o.FileSize.MaxLines
+	}
+	if o.FileSize.CountMode != "" {
+		rules.FileSize.CountMode = o.FileSize.CountMode
+	}
+	return rules
+}
+
+// Merge overlays the non-zero overrides of other onto o
+func (o LanguageRulesConfig) Merge(other LanguageRulesConfig) LanguageRulesConfig {
+	if other.FunctionSize.MaxLines > 0 {
+		o.FunctionSize.MaxLines = other.FunctionSize.MaxLines
+	}
+	if other.FunctionSize.Metric != "" {
+		o.FunctionSize.Metric = other.FunctionSize.Metric
+	}
+	if other.FileSize.MaxLines > 0 {
+		o.FileSize.MaxLines = other.FileSize.MaxLines
+	}
+	if other.FileSize.CountMode != "" {
+		o.FileSize.CountMode = other.FileSize.CountMode
+	}
+	return o
 }
\ No newline at end of file
@@ -0,0 +1,219 @@
+// Package coverage reads test coverage reports and tells which lines of a file the tests ran
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Report holds the lines a coverage report records for each file. Files are named as in the
+// report: import paths in a Go profile, and paths relative to where the tests ran, or
+// absolute, in LCOV and coverage.py reports.
+type Report struct {
+	files  map[string]map[int]bool // run state of each recorded line, by slash-separated path
+	byBase map[string][]string     // paths of files, by base name
+}
+
+// Load reads a coverage report: a Go coverprofile, an LCOV tracefile or a coverage.py XML
+// report, told apart by their first line
+func Load(path string) (*Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Parse reads a coverage report in any of the formats Load accepts
+func Parse(reader io.Reader) (*Report, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{files: make(map[string]map[int]bool), byBase: make(map[string][]string)}
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		err = r.parseGo(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		err = r.parseXML(trimmed)
+	default:
+		err = r.parseLCOV(trimmed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for file := range r.files {
+		base := path.Base(file)
+		r.byBase[base] = append(r.byBase[base], file)
+	}
+	for _, files := range r.byBase {
+		sort.Strings(files)
+	}
+	return r, nil
+}
+
+// record notes whether a line of a file ran; a line recorded several times ran if any record
+// says so
+func (r *Report) record(file string, line int, run bool) {
+	file = filepath.ToSlash(file)
+	if r.files[file] == nil {
+		r.files[file] = make(map[int]bool)
+	}
+	r.files[file][line] = r.files[file][line] || run
+}
+
+// parseGo reads a profile written by go test -coverprofile. Each line after the mode holds a
+// block, file.go:startLine.startCol,endLine.endCol statements count, whose lines all count as
+// run when count is above zero.
+func (r *Report) parseGo(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Scan() // mode line
+	for lineNum := 2; scanner.Scan(); lineNum++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		colon := strings.LastIndex(text, ":")
+		fields := strings.Fields(text[colon+1:])
+		if colon < 0 || len(fields) != 3 {
+			return fmt.Errorf("line %d: invalid Go coverage block %q", lineNum, text)
+		}
+		start, end, ok := strings.Cut(fields[0], ",")
+		startLine, err1 := strconv.Atoi(strings.Split(start, ".")[0])
+		endLine, err2 := strconv.Atoi(strings.Split(end, ".")[0])
+		count, err3 := strconv.Atoi(fields[2])
+		if !ok || err1 != nil || err2 != nil || err3 != nil {
+			return fmt.Errorf("line %d: invalid Go coverage block %q", lineNum, text)
+		}
+		for line := startLine; line <= endLine; line++ {
+			r.record(text[:colon], line, count > 0)
+		}
+	}
+	return scanner.Err()
+}
+
+// parseLCOV reads an LCOV tracefile: SF names a file and each DA line gives the execution
+// count of one of its lines
+func (r *Report) parseLCOV(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	file := ""
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(text, "SF:"):
+			file = strings.TrimPrefix(text, "SF:")
+		case text == "end_of_record":
+			file = ""
+		case strings.HasPrefix(text, "DA:"):
+			fields := strings.Split(strings.TrimPrefix(text, "DA:"), ",")
+			if file == "" || len(fields) < 2 {
+				return fmt.Errorf("line %d: DA record outside a file", lineNum)
+			}
+			line, err1 := strconv.Atoi(fields[0])
+			count, err2 := strconv.ParseFloat(fields[1], 64)
+			if err1 != nil || err2 != nil {
+				return fmt.Errorf("line %d: invalid DA record %q", lineNum, text)
+			}
+			r.record(file, line, count > 0)
+		}
+	}
+	return scanner.Err()
+}
+
+// coberturaReport is the part of a coverage.py XML report, in the Cobertura format, that
+// records lines
+type coberturaReport struct {
+	XMLName xml.Name `xml:"coverage"`
+	Classes []struct {
+		Filename string `xml:"filename,attr"`
+		Lines    []struct {
+			Number int `xml:"number,attr"`
+			Hits   int `xml:"hits,attr"`
+		} `xml:"lines>line"`
+	} `xml:"packages>package>classes>class"`
+}
+
+// parseXML reads a coverage.py XML report, whose file names are relative to a source directory
+func (r *Report) parseXML(data []byte) error {
+	var report coberturaReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return fmt.Errorf("invalid XML coverage report: %w", err)
+	}
+	for _, class := range report.Classes {
+		for _, line := range class.Lines {
+			r.record(class.Filename, line.Number, line.Hits > 0)
+		}
+	}
+	return nil
+}
+
+// Uncovered reports whether the tests never ran the code at line of file: the line itself,
+// or the first line after it the report records, so a finding on a function declaration
+// counts as uncovered when the body never ran. Files the report does not name are not
+// uncovered, since nothing is known about them.
+func (r *Report) Uncovered(file string, line int) bool {
+	lines := r.lines(file)
+	if lines == nil {
+		return false
+	}
+	if run, ok := lines[line]; ok && !run {
+		return true
+	}
+	next := 0
+	for recorded := range lines {
+		if recorded > line && (next == 0 || recorded < next) {
+			next = recorded
+		}
+	}
+	return next > 0 && !lines[next]
+}
+
+// lines returns the recorded lines of the report's file whose path shares the longest
+// suffix with file, matching at least its directory as well as its name when the report
+// names the file in a directory
+func (r *Report) lines(file string) map[int]bool {
+	if r == nil {
+		return nil
+	}
+	parts := strings.Split(filepath.ToSlash(file), "/")
+	best, bestLength := "", 0
+	for _, candidate := range r.byBase[parts[len(parts)-1]] {
+		candidateParts := strings.Split(candidate, "/")
+		length := commonSuffixLength(parts, candidateParts)
+		if length < min(2, len(candidateParts)) {
+			continue
+		}
+		if length > bestLength {
+			best, bestLength = candidate, length
+		}
+	}
+	if best == "" {
+		return nil
+	}
+	return r.files[best]
+}
+
+// commonSuffixLength returns the number of trailing elements two paths have in common
+func commonSuffixLength(a, b []string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,146 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testGoProfile = `mode: set
+github.com/acme/shop/cart/cart.go:10.30,12.2 1 1
+github.com/acme/shop/cart/cart.go:14.34,15.16 1 1
+github.com/acme/shop/cart/cart.go:15.16,17.3 1 0
+github.com/acme/shop/cart/cart.go:20.28,22.2 2 0
+github.com/acme/shop/tax/cart.go:5.20,7.2 1 0
+`
+
+const testLCOV = `TN:
+SF:src/cart.js
+DA:3,4
+DA:4,0
+DA:8,0
+DA:9,0
+end_of_record
+`
+
+const testCoberturaXML = `<?xml version="1.0" ?>
+<coverage version="7.4.0" line-rate="0.5">
+	<sources><source>/home/ci/shop</source></sources>
+	<packages>
+		<package name="shop">
+			<classes>
+				<class name="cart.py" filename="shop/cart.py">
+					<lines>
+						<line number="1" hits="1"/>
+						<line number="4" hits="1"/>
+						<line number="5" hits="0"/>
+						<line number="8" hits="1"/>
+						<line number="9" hits="1"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>
+`
+
+func TestUncovered(t *testing.T) {
+	tests := []struct {
+		name   string
+		report string
+		file   string
+		lines  map[int]bool
+	}{
+		{
+			name:   "go",
+			report: testGoProfile,
+			file:   "/src/shop/cart/cart.go",
+			// line 15 ran, but the branch it opens did not
+			lines: map[int]bool{10: false, 11: false, 14: false, 15: true, 16: true, 19: true, 21: true, 30: false},
+		},
+		{
+			name:   "lcov",
+			report: testLCOV,
+			file:   "/work/app/src/cart.js",
+			lines:  map[int]bool{1: false, 3: true, 4: true, 7: true, 9: true, 10: false},
+		},
+		{
+			name:   "coverage.py",
+			report: testCoberturaXML,
+			file:   "/work/shop/shop/cart.py",
+			// the def on line 4 ran at import, its body did not
+			lines: map[int]bool{1: false, 4: true, 5: true, 8: false, 9: false},
+		},
+	}
+	for _, tt := range tests {
+		report, err := Parse(strings.NewReader(tt.report))
+		if err != nil {
+			t.Fatalf("%s: Parse failed: %v", tt.name, err)
+		}
+		for line, want := range tt.lines {
+			if got := report.Uncovered(tt.file, line); got != want {
+				t.Errorf("%s: Uncovered(%q, %d) = %v, want %v", tt.name, tt.file, line, got, want)
+			}
+		}
+	}
+}
+
+func TestUncoveredMatchesPaths(t *testing.T) {
+	report, err := Parse(strings.NewReader(testGoProfile))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{"/src/shop/cart/cart.go", false},
+		{"/src/shop/tax/cart.go", true},
+		{"/src/shop/orders/cart.go", false},
+		{"cart.go", false},
+		{"/src/shop/cart/other.go", false},
+	}
+	for _, tt := range tests {
+		if got := report.Uncovered(tt.file, 5); got != tt.want {
+			t.Errorf("Uncovered(%q, 5) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+
+	var nilReport *Report
+	if nilReport.Uncovered("/src/shop/tax/cart.go", 5) {
+		t.Error("Expected a nil report to know nothing")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"mode: set\ncart.go:10.30 1 1\n",
+		"DA:3,4\n",
+		"<coverage><packages>",
+	}
+	for _, report := range tests {
+		if _, err := Parse(strings.NewReader(report)); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", report)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lcov.info")
+	if err := os.WriteFile(path, []byte(testLCOV), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !report.Uncovered("src/cart.js", 4) {
+		t.Error("Expected line 4 of src/cart.js to be uncovered")
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.out")); err == nil {
+		t.Error("Expected an error for a missing report")
+	}
+}
@@ -0,0 +1,147 @@
+package dependencies
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang"
+)
+
+// Rule IDs reported by the import graph analysis
+const (
+	CycleRuleID = "circular-dependency"
+	DepthRuleID = "deep-dependency-chain"
+)
+
+func init() {
+	languages := []string{"go", "python", "reactnative"}
+	core.RegisterRuleDoc(core.RuleDoc{
+		ID:          CycleRuleID,
+		Name:        "Circular Dependency",
+		Description: "Detects packages and files that import each other, directly or through others",
+		Rationale: "Import cycles do not compile in Go and cause half-initialized modules in Python and " +
+			"JavaScript. Each cycle is reported once, at the import that starts the shortest one. Python " +
+			"imports inside functions and TypeScript import type statements are not followed, as they are " +
+			"the usual way to break a cycle.",
+		Category:  core.CategoryBug,
+		Severity:  core.SeverityWarning,
+		Languages: languages,
+		Examples: []core.RuleExample{{
+			Bad: `# models.py
+from views import render
+# views.py
+from models import User`,
+			Good: `# views.py
+def render(user):
+    from models import User  # imported when called, after both modules loaded`,
+		}},
+		Options: []core.RuleOption{
+			{Key: "rules.dependencies.enabled", Flag: "-enable-dependencies", Default: "true", Description: "Build the import graph"},
+			{Key: "rules.dependencies.checkCycles", Flag: "-check-import-cycles", Default: "true", Description: "Report circular imports"},
+		},
+	})
+	core.RegisterRuleDoc(core.RuleDoc{
+		ID:          DepthRuleID,
+		Name:        "Deep Dependency Chain",
+		Description: "Detects import chains longer than the configured depth",
+		Rationale: "A long chain of imports couples the top of the chain to every module along it, so a " +
+			"change at the bottom ripples through all of them. Only the top of each chain is reported, " +
+			"with the full chain in the message.",
+		Category:  core.CategoryStyle,
+		Severity:  core.SeverityInfo,
+		Languages: languages,
+		Examples: []core.RuleExample{{
+			Bad:  `api -> service -> repository -> store -> driver -> pool -> conn -> ...`,
+			Good: `api -> service -> repository, with the repository given its store by main`,
+		}},
+		Options: []core.RuleOption{
+			{Key: "rules.dependencies.enabled", Flag: "-enable-dependencies", Default: "true", Description: "Build the import graph"},
+			{Key: "rules.dependencies.maxDepth", Flag: "-max-import-depth", Default: "10", Description: "Longest allowed import chain, 0 disables the check"},
+		},
+	})
+}
+
+// Analyze builds the import graph of the files, grouped by language as returned by the
+// scanner, and reports import cycles and chains longer than the configured depth. Each
+// finding is located at the import statement that starts the cycle or chain.
+func Analyze(root string, filesByLanguage map[string][]string, modules []golang.Module, cfg core.DependenciesConfig) []core.Result {
+	if !cfg.Enabled || (!cfg.CheckCycles && cfg.MaxDepth <= 0) {
+		return nil
+	}
+
+	g := NewGraph()
+	addGoImports(g, filesByLanguage["go"], modules)
+	addPythonImports(g, root, filesByLanguage["python"])
+	addJavaScriptImports(g, filesByLanguage["reactnative"])
+
+	names := nodeNamer{root: root, modules: modules, packages: make(map[string]bool)}
+	for _, file := range filesByLanguage["go"] {
+		names.packages[filepath.Dir(file)] = true
+	}
+	var results []core.Result
+	if cfg.CheckCycles {
+		for _, cycle := range g.Cycles() {
+			results = append(results, core.Result{
+				RuleID:     CycleRuleID,
+				RuleName:   "Circular Dependency",
+				Category:   string(core.CategoryBug),
+				Severity:   string(core.SeverityWarning),
+				FilePath:   cycle[0].File,
+				Line:       cycle[0].Line,
+				Message:    fmt.Sprintf("Import cycle: %s", names.path(cycle)),
+				Suggestion: "Break the cycle by moving the shared code into a separate module or inverting one dependency",
+			})
+		}
+	}
+	if cfg.MaxDepth > 0 {
+		for _, chain := range g.LongestChains(cfg.MaxDepth) {
+			results = append(results, core.Result{
+				RuleID:     DepthRuleID,
+				RuleName:   "Deep Dependency Chain",
+				Category:   string(core.CategoryStyle),
+				Severity:   string(core.SeverityInfo),
+				FilePath:   chain[0].File,
+				Line:       chain[0].Line,
+				Message:    fmt.Sprintf("Import chain of depth %d exceeds %d: %s", len(chain), cfg.MaxDepth, names.path(chain)),
+				Suggestion: "Flatten the dependency chain so modules depend on fewer layers",
+			})
+		}
+	}
+	return results
+}
+
+// nodeNamer renders graph nodes for messages: Go packages by import path, files relative
+// to the analyzed root
+type nodeNamer struct {
+	root     string
+	modules  []golang.Module
+	packages map[string]bool // Go package directories
+}
+
+func (n nodeNamer) name(node string) string {
+	if n.packages[node] {
+		if module := golang.FindModule(n.modules, node); module != nil {
+			if rel, err := filepath.Rel(module.Dir, node); err == nil {
+				if rel == "." {
+					return module.Path
+				}
+				return module.Path + "/" + filepath.ToSlash(rel)
+			}
+		}
+	}
+	if rel, err := filepath.Rel(n.root, node); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return node
+}
+
+// path renders a chain of edges as "a -> b -> c"
+func (n nodeNamer) path(edges []Edge) string {
+	parts := []string{n.name(edges[0].From)}
+	for _, edge := range edges {
+		parts = append(parts, n.name(edge.To))
+	}
+	return strings.Join(parts, " -> ")
+}
@@ -0,0 +1,112 @@
+package dependencies
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) map[string][]string {
+	t.Helper()
+	byLanguage := make(map[string][]string)
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		switch filepath.Ext(name) {
+		case ".go":
+			byLanguage["go"] = append(byLanguage["go"], path)
+		case ".py":
+			byLanguage["python"] = append(byLanguage["python"], path)
+		case ".js", ".ts":
+			byLanguage["reactnative"] = append(byLanguage["reactnative"], path)
+		}
+	}
+	return byLanguage
+}
+
+func TestGraph_CyclesAndChains(t *testing.T) {
+	g := NewGraph()
+	for _, edge := range [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"c", "d"}, {"d", "e"}, {"e", "f"}, {"x", "a"}} {
+		g.AddEdge(Edge{From: edge[0], To: edge[1]})
+	}
+
+	cycles := g.Cycles()
+	if len(cycles) != 1 || len(cycles[0]) != 3 || cycles[0][0].From != "a" || cycles[0][2].To != "a" {
+		t.Fatalf("Expected the cycle a -> b -> c -> a, got %v", cycles)
+	}
+
+	// x only reaches the cycle's entry point, since imports inside a cycle are not followed
+	chains := g.LongestChains(2)
+	if len(chains) != 1 || chains[0][0].From != "c" || len(chains[0]) != 3 {
+		t.Errorf("Expected only the chain c -> d -> e -> f, got %v", chains)
+	}
+}
+
+func TestAnalyze_DetectsCycles(t *testing.T) {
+	root := t.TempDir()
+	files := writeFiles(t, root, map[string]string{
+		"go.mod":              "module example.com/app\n\ngo 1.21\n",
+		"a/a.go":              "package a\n\nimport \"example.com/app/b\"\n\nvar _ = b.X\n",
+		"b/b.go":              "package b\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/app/a\"\n)\n",
+		"b/b_test.go":         "package b_test\n\nimport \"example.com/app/b\"\n",
+		"pkg/__init__.py":     "",
+		"pkg/models.py":       "from pkg import views\n",
+		"pkg/views.py":        "from .models import User\n\ndef render():\n    import pkg.lazy\n",
+		"pkg/lazy.py":         "from pkg.views import render\n",
+		"web/api.js":          "import { get } from './client';\n",
+		"web/client.js":       "const api = require('./api');\n",
+		"web/types.ts":        "import type { Api } from './schema';\n",
+		"web/schema/index.ts": "import { Types } from '../types';\n",
+	})
+
+	results := Analyze(root, files, []golang.Module{{Path: "example.com/app", Dir: root}}, core.DependenciesConfig{Enabled: true, CheckCycles: true})
+
+	want := map[string]bool{
+		"Import cycle: example.com/app/a -> example.com/app/b -> example.com/app/a": false,
+		"Import cycle: pkg/models.py -> pkg/views.py -> pkg/models.py":              false,
+		"Import cycle: web/api.js -> web/client.js -> web/api.js":                   false,
+	}
+	for _, r := range results {
+		if _, ok := want[r.Message]; !ok {
+			t.Errorf("Unexpected result: %s", r.Message)
+		}
+		want[r.Message] = true
+		if r.RuleID != CycleRuleID || r.Line == 0 {
+			t.Errorf("Expected a located %s result, got %+v", CycleRuleID, r)
+		}
+	}
+	for message, found := range want {
+		if !found {
+			t.Errorf("Expected to find: %s", message)
+		}
+	}
+}
+
+func TestAnalyze_ReportsDeepChains(t *testing.T) {
+	root := t.TempDir()
+	files := writeFiles(t, root, map[string]string{
+		"main.py":  "import a\n",
+		"a.py":     "import b\n",
+		"b.py":     "import c\n",
+		"c.py":     "import os\n",
+		"other.py": "import c\n",
+	})
+
+	results := Analyze(root, files, nil, core.DependenciesConfig{Enabled: true, MaxDepth: 2})
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d: %v", len(results), results)
+	}
+	if r := results[0]; r.FilePath != filepath.Join(root, "main.py") || r.Line != 1 ||
+		!strings.HasSuffix(r.Message, "main.py -> a.py -> b.py -> c.py") {
+		t.Errorf("Unexpected result: %+v", r)
+	}
+}
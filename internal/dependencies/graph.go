@@ -0,0 +1,227 @@
+package dependencies
+
+import "sort"
+
+// Edge is an import from one node to another, located at the import statement
+type Edge struct {
+	From string
+	To   string
+	File string
+	Line int
+}
+
+// Graph is a directed import graph. Nodes are Go package directories or Python and
+// JavaScript file paths.
+type Graph struct {
+	edges map[string][]Edge
+	nodes map[string]bool
+}
+
+// NewGraph creates an empty import graph
+func NewGraph() *Graph {
+	return &Graph{
+		edges: make(map[string][]Edge),
+		nodes: make(map[string]bool),
+	}
+}
+
+// AddNode adds a node without imports
+func (g *Graph) AddNode(node string) {
+	g.nodes[node] = true
+}
+
+// AddEdge records an import, keeping only the first location of repeated imports.
+// Self-imports are ignored.
+func (g *Graph) AddEdge(edge Edge) {
+	g.nodes[edge.From] = true
+	g.nodes[edge.To] = true
+	if edge.From == edge.To {
+		return
+	}
+	for _, existing := range g.edges[edge.From] {
+		if existing.To == edge.To {
+			return
+		}
+	}
+	g.edges[edge.From] = append(g.edges[edge.From], edge)
+}
+
+// sortedNodes returns the nodes in a stable order, so reports are deterministic
+func (g *Graph) sortedNodes() []string {
+	nodes := make([]string, 0, len(g.nodes))
+	for node := range g.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// Cycles returns one import cycle per strongly connected component of the graph. Each cycle
+// starts at the smallest node of its component, and its edges lead back to that node.
+func (g *Graph) Cycles() [][]Edge {
+	var cycles [][]Edge
+	for _, component := range g.components() {
+		if len(component) > 1 {
+			cycles = append(cycles, g.cycleThrough(component))
+		}
+	}
+	return cycles
+}
+
+// components finds the strongly connected components with Tarjan's algorithm, each sorted
+func (g *Graph) components() [][]string {
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var connect func(node string)
+	connect = func(node string) {
+		index[node] = len(index)
+		lowLink[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, edge := range g.edges[node] {
+			if _, visited := index[edge.To]; !visited {
+				connect(edge.To)
+				lowLink[node] = min(lowLink[node], lowLink[edge.To])
+			} else if onStack[edge.To] {
+				lowLink[node] = min(lowLink[node], index[edge.To])
+			}
+		}
+
+		if lowLink[node] != index[node] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == node {
+				break
+			}
+		}
+		sort.Strings(component)
+		components = append(components, component)
+	}
+
+	for _, node := range g.sortedNodes() {
+		if _, visited := index[node]; !visited {
+			connect(node)
+		}
+	}
+	return components
+}
+
+// cycleThrough finds the shortest cycle through the first node of a component, using only
+// edges inside the component
+func (g *Graph) cycleThrough(component []string) []Edge {
+	inComponent := make(map[string]bool, len(component))
+	for _, node := range component {
+		inComponent[node] = true
+	}
+
+	start := component[0]
+	via := map[string]Edge{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, edge := range g.edges[node] {
+			if !inComponent[edge.To] {
+				continue
+			}
+			if edge.To == start {
+				cycle := []Edge{edge}
+				for at := node; at != start; at = via[at].From {
+					cycle = append(cycle, via[at])
+				}
+				reverse(cycle)
+				return cycle
+			}
+			if _, seen := via[edge.To]; !seen {
+				via[edge.To] = edge
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+	return nil
+}
+
+// LongestChains returns, for each node that starts an import chain longer than maxDepth
+// edges and is not itself imported by such a node, its longest chain. Imports inside a cycle
+// are not followed, since cycles are reported separately.
+func (g *Graph) LongestChains(maxDepth int) [][]Edge {
+	componentOf := make(map[string]int)
+	for i, component := range g.components() {
+		for _, node := range component {
+			componentOf[node] = i
+		}
+	}
+	nodes := g.sortedNodes()
+	depth, next := g.depths(nodes, componentOf)
+
+	importedByDeep := make(map[string]bool)
+	for _, node := range nodes {
+		if depth[node] <= maxDepth {
+			continue
+		}
+		for _, edge := range g.edges[node] {
+			if componentOf[edge.To] != componentOf[node] {
+				importedByDeep[edge.To] = true
+			}
+		}
+	}
+
+	var chains [][]Edge
+	for _, node := range nodes {
+		if depth[node] <= maxDepth || importedByDeep[node] {
+			continue
+		}
+		var chain []Edge
+		for at := node; depth[at] > 0; at = next[at].To {
+			chain = append(chain, next[at])
+		}
+		chains = append(chains, chain)
+	}
+	return chains
+}
+
+// depths computes the length of the longest import chain from each node, skipping edges
+// inside a component, and the first edge of that chain
+func (g *Graph) depths(nodes []string, componentOf map[string]int) (map[string]int, map[string]Edge) {
+	depth := make(map[string]int)
+	next := make(map[string]Edge)
+	var measure func(node string) int
+	measure = func(node string) int {
+		if d, ok := depth[node]; ok {
+			return d
+		}
+		depth[node] = 0
+		for _, edge := range g.edges[node] {
+			if componentOf[edge.To] == componentOf[node] {
+				continue
+			}
+			if d := measure(edge.To) + 1; d > depth[node] {
+				depth[node] = d
+				next[node] = edge
+			}
+		}
+		return depth[node]
+	}
+
+	for _, node := range nodes {
+		measure(node)
+	}
+	return depth, next
+}
+
+func reverse(edges []Edge) {
+	for i, j := 0, len(edges)-1; i < j; i, j = i+1, j-1 {
+		edges[i], edges[j] = edges[j], edges[i]
+	}
+}
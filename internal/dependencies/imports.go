@@ -0,0 +1,244 @@
+package dependencies
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang"
+)
+
+var (
+	// Only unindented imports are followed: imports inside functions are deferred until the
+	// call, which is the usual way of breaking a cycle
+	pythonImportPattern = regexp.MustCompile(`^import\s+(.+)`)
+	pythonFromPattern   = regexp.MustCompile(`^from\s+(\S+)\s+import\s+\(?([^)#]*)`)
+
+	jsImportPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^(?:import|export)\s+(?:[\w*${},\s]+?\s+from\s+)?['"](\.{1,2}/[^'"]*)['"]`),
+		regexp.MustCompile(`(?m)^(?:(?:const|let|var)\s+[^=\n]+=\s*)?require\(\s*['"](\.{1,2}/[^'"]*)['"]\s*\)`),
+	}
+	// Type-only imports are erased at compile time and cannot form runtime cycles
+	jsTypeImportPattern = regexp.MustCompile(`^(?:import|export)\s+type\s`)
+	jsExtensions        = []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs"}
+)
+
+// addGoImports adds an edge for every import between packages of the analyzed modules.
+// Nodes are package directories; test files are skipped, as external test packages may import
+// the package they test.
+func addGoImports(g *Graph, files []string, modules []golang.Module) {
+	packages := make(map[string]bool)
+	for _, file := range files {
+		if !strings.HasSuffix(file, "_test.go") {
+			packages[filepath.Dir(file)] = true
+		}
+	}
+
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		dir := filepath.Dir(file)
+		g.AddNode(dir)
+
+		module := golang.FindModule(modules, file)
+		if module == nil {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, spec := range f.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+			target, ok := goPackageDir(importPath, module)
+			if !ok || !packages[target] {
+				continue
+			}
+			g.AddEdge(Edge{From: dir, To: target, File: file, Line: fset.Position(spec.Pos()).Line})
+		}
+	}
+}
+
+// goPackageDir returns the directory of an import path inside module
+func goPackageDir(importPath string, module *golang.Module) (string, bool) {
+	if importPath == module.Path {
+		return module.Dir, true
+	}
+	rest, ok := strings.CutPrefix(importPath, module.Path+"/")
+	if !ok {
+		return "", false
+	}
+	return filepath.Join(module.Dir, filepath.FromSlash(rest)), true
+}
+
+// addPythonImports adds an edge for every import of another analyzed file. Nodes are
+// files; absolute imports are resolved against each directory between the importing file and
+// root, nearest first.
+func addPythonImports(g *Graph, root string, files []string) {
+	known := fileSet(files)
+	for _, file := range files {
+		g.AddNode(file)
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for i, line := range strings.Split(string(languages.DecodeSource(content)), "\n") {
+			for _, target := range pythonImportTargets(root, file, line) {
+				if !known[target] {
+					continue
+				}
+				g.AddEdge(Edge{From: file, To: target, File: file, Line: i + 1})
+			}
+		}
+	}
+}
+
+// pythonImportTargets resolves the modules imported by one line of a Python file
+func pythonImportTargets(root, file, line string) []string {
+	if matches := pythonFromPattern.FindStringSubmatch(line); matches != nil {
+		module := matches[1]
+		var targets []string
+		for _, name := range strings.Split(matches[2], ",") {
+			name = strings.TrimSpace(strings.Split(strings.TrimSpace(name), " as ")[0])
+			if name == "" || name == "*" {
+				continue
+			}
+			// from package import submodule
+			submodule := module + "." + name
+			if strings.HasSuffix(module, ".") {
+				submodule = module + name
+			}
+			if target, ok := resolvePythonModule(root, file, submodule); ok {
+				targets = append(targets, target)
+			}
+		}
+		if len(targets) == 0 {
+			if target, ok := resolvePythonModule(root, file, module); ok {
+				targets = append(targets, target)
+			}
+		}
+		return targets
+	}
+
+	matches := pythonImportPattern.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+	var targets []string
+	for _, module := range strings.Split(strings.Split(matches[1], "#")[0], ",") {
+		module = strings.TrimSpace(strings.Split(strings.TrimSpace(module), " as ")[0])
+		if target, ok := resolvePythonModule(root, file, module); ok {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// resolvePythonModule finds the file of a dotted module name, which is relative to the
+// importing file when it starts with dots
+func resolvePythonModule(root, file, module string) (string, bool) {
+	if module == "" {
+		return "", false
+	}
+
+	var bases []string
+	if trimmed := strings.TrimLeft(module, "."); trimmed != module {
+		base := filepath.Dir(file)
+		for i := 1; i < len(module)-len(trimmed); i++ {
+			base = filepath.Dir(base)
+		}
+		bases = append(bases, base)
+		module = trimmed
+	} else {
+		for dir := filepath.Dir(file); ; dir = filepath.Dir(dir) {
+			bases = append(bases, dir)
+			if dir == root || dir == filepath.Dir(dir) || !strings.HasPrefix(dir, root) {
+				break
+			}
+		}
+	}
+
+	for _, base := range bases {
+		path := filepath.Join(base, filepath.FromSlash(strings.ReplaceAll(module, ".", "/")))
+		if module == "" {
+			path = base
+		}
+		for _, candidate := range []string{path + ".py", filepath.Join(path, "__init__.py")} {
+			if isFile(candidate) {
+				return candidate, true
+			}
+		}
+	}
+	return "", false
+}
+
+// addJavaScriptImports adds an edge for every relative import or require of another analyzed
+// file. Package imports are external and never form project cycles.
+func addJavaScriptImports(g *Graph, files []string) {
+	known := fileSet(files)
+	for _, file := range files {
+		g.AddNode(file)
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		content = languages.DecodeSource(content)
+		for _, pattern := range jsImportPatterns {
+			for _, match := range pattern.FindAllSubmatchIndex(content, -1) {
+				if jsTypeImportPattern.Match(content[match[0]:match[1]]) {
+					continue
+				}
+				specifier := string(content[match[2]:match[3]])
+				target, ok := resolveJavaScriptImport(file, specifier)
+				if !ok || !known[target] {
+					continue
+				}
+				line := 1 + strings.Count(string(content[:match[2]]), "\n")
+				g.AddEdge(Edge{From: file, To: target, File: file, Line: line})
+			}
+		}
+	}
+}
+
+// resolveJavaScriptImport finds the file a relative specifier refers to, trying the usual
+// extensions and index files
+func resolveJavaScriptImport(file, specifier string) (string, bool) {
+	path := filepath.Join(filepath.Dir(file), filepath.FromSlash(specifier))
+	if isFile(path) {
+		return path, true
+	}
+	for _, ext := range jsExtensions {
+		if isFile(path + ext) {
+			return path + ext, true
+		}
+	}
+	for _, ext := range jsExtensions {
+		if index := filepath.Join(path, "index"+ext); isFile(index) {
+			return index, true
+		}
+	}
+	return "", false
+}
+
+func fileSet(files []string) map[string]bool {
+	set := make(map[string]bool, len(files))
+	for _, file := range files {
+		set[file] = true
+	}
+	return set
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
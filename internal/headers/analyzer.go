@@ -0,0 +1,243 @@
+package headers
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+)
+
+// RuleID identifies findings of file headers that drifted from the project's header
+const RuleID = "header-drift"
+
+func init() {
+	core.RegisterRuleDoc(core.RuleDoc{
+		ID:          RuleID,
+		Name:        "Header Drift",
+		Description: "Detects file headers that are near copies of the project's header with a different year, name or wording",
+		Rationale: "Headers are copied from file to file rather than written, so one pasted from another " +
+			"project or an old file keeps its year, project name or license wording. Release tooling and " +
+			"lawyers care about those differences; reviewers rarely spot them. Without a template, every " +
+			"wording shared by minFiles files is one of the project's headers.",
+		Category:  core.CategoryStyle,
+		Severity:  core.SeverityWarning,
+		Languages: []string{"go", "python", "reactnative"},
+		Examples: []core.RuleExample{{
+			Bad: `// Copyright 2021 Acme Corp. All rights reserved.
+// Use of this source code is governed by the LICENSE file.`,
+			Good: `// Copyright 2023 Acme Corp. All rights reserved.
+// Use of this source code is governed by the LICENSE file.`,
+		}},
+		Options: []core.RuleOption{
+			{Key: "rules.headers.enabled", Flag: "-enable-headers", Default: "true", Description: "Report drifted file headers"},
+			{Key: "rules.headers.minFiles", Flag: "-header-min-files", Default: "3", Description: "Files that must share a header before near copies of it are reported"},
+			{Key: "rules.headers.threshold", Flag: "-header-threshold", Default: "0.7", Description: "Word similarity at which a header is a near copy"},
+			{Key: "rules.headers.template", Flag: "-header-template", Default: "none", Description: "File holding the expected header; -fix-headers replaces drifted headers with it"},
+		},
+	})
+}
+
+// minHeaderWords is the number of words below which a comment such as "// Package main" is
+// not boilerplate worth comparing
+const minHeaderWords = 5
+
+// maxListedDifferences caps the differences described in a finding
+const maxListedDifferences = 3
+
+// variant is one wording of a header and the files that use it
+type variant struct {
+	words []string
+	files []string       // sorted
+	lines map[string]int // first line of the header in each file
+}
+
+// Analyze compares the headers of the files and reports the analyzed files whose header is a
+// near copy of the project's header. Without a template, the project's headers are the
+// wordings shared by at least cfg.MinFiles files, most common first; with a template, the
+// template is the only header. Headers are near copies when their word similarity reaches
+// cfg.Threshold without being equal.
+func Analyze(files []string, analyzed map[string]bool, cfg core.HeadersConfig, template []string) []core.Result {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	variants := collectVariants(files)
+	var canonical []*variant
+	if template != nil {
+		canonical = append(canonical, &variant{words: strings.Fields(strings.Join(template, " "))})
+	}
+
+	var results []core.Result
+	for _, v := range variants {
+		best, score := closest(canonical, v.words)
+		switch {
+		case best != nil && equalWords(best.words, v.words):
+		case best != nil && score >= cfg.Threshold:
+			results = append(results, driftResults(v, best, template != nil, analyzed)...)
+		case template == nil && len(v.files) >= cfg.MinFiles:
+			canonical = append(canonical, v)
+		}
+	}
+	return results
+}
+
+// collectVariants reads the header of each file and groups the files by wording, most used
+// wording first
+func collectVariants(files []string) []*variant {
+	byWording := make(map[string]*variant)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		lines := strings.Split(string(languages.DecodeSource(content)), "\n")
+		if languages.IsGenerated(lines) {
+			continue
+		}
+		header := Extract(file, lines)
+		if header == nil {
+			continue
+		}
+		words := header.Words()
+		if len(words) < minHeaderWords {
+			continue
+		}
+		key := strings.Join(words, " ")
+		if byWording[key] == nil {
+			byWording[key] = &variant{words: words, lines: make(map[string]int)}
+		}
+		byWording[key].files = append(byWording[key].files, file)
+		byWording[key].lines[file] = header.Start
+	}
+
+	variants := make([]*variant, 0, len(byWording))
+	for _, v := range byWording {
+		sort.Strings(v.files)
+		variants = append(variants, v)
+	}
+	sort.Slice(variants, func(i, j int) bool {
+		if len(variants[i].files) != len(variants[j].files) {
+			return len(variants[i].files) > len(variants[j].files)
+		}
+		return variants[i].files[0] < variants[j].files[0]
+	})
+	return variants
+}
+
+// closest returns the canonical header most similar to words, and the similarity
+func closest(canonical []*variant, words []string) (*variant, float64) {
+	var best *variant
+	bestScore := 0.0
+	for _, c := range canonical {
+		if score := similarity(c.words, words); best == nil || score > bestScore {
+			best, bestScore = c, score
+		}
+	}
+	return best, bestScore
+}
+
+// similarity is the share of the words of both headers that are part of their longest common
+// subsequence, from 0 for unrelated headers to 1 for equal ones
+func similarity(words1, words2 []string) float64 {
+	if len(words1)+len(words2) == 0 {
+		return 1
+	}
+	return 2 * float64(len(commonSubsequence(words1, words2))) / float64(len(words1)+len(words2))
+}
+
+// commonSubsequence returns the index pairs of a longest common subsequence of two word lists
+func commonSubsequence(a, b []string) [][2]int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	var pairs [][2]int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			pairs = append(pairs, [2]int{i, j})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
+
+// differences describes how words differ from the expected header, such as
+// `"2021" instead of "2023"`
+func differences(expected, words []string) []string {
+	var diffs []string
+	add := func(expectedWords, actualWords []string) {
+		want, got := strings.Join(expectedWords, " "), strings.Join(actualWords, " ")
+		switch {
+		case want == "" && got == "":
+		case want == "":
+			diffs = append(diffs, fmt.Sprintf("extra %q", got))
+		case got == "":
+			diffs = append(diffs, fmt.Sprintf("missing %q", want))
+		default:
+			diffs = append(diffs, fmt.Sprintf("%q instead of %q", got, want))
+		}
+	}
+
+	i, j := 0, 0
+	for _, pair := range commonSubsequence(expected, words) {
+		add(expected[i:pair[0]], words[j:pair[1]])
+		i, j = pair[0]+1, pair[1]+1
+	}
+	add(expected[i:], words[j:])
+	return diffs
+}
+
+func equalWords(words1, words2 []string) bool {
+	return strings.Join(words1, " ") == strings.Join(words2, " ")
+}
+
+// driftResults reports the analyzed files of a drifted wording, at the start of their header
+func driftResults(v, expected *variant, fromTemplate bool, analyzed map[string]bool) []core.Result {
+	diffs := differences(expected.words, v.words)
+	if len(diffs) > maxListedDifferences {
+		diffs = append(diffs[:maxListedDifferences], fmt.Sprintf("and %d more", len(diffs)-maxListedDifferences))
+	}
+	source := fmt.Sprintf("the header of %d other files", len(expected.files))
+	suggestion := "Copy the header from one of the other files, or configure headers.template and run with -fix-headers"
+	if fromTemplate {
+		source = "the configured template"
+		suggestion = "Run with -fix-headers to replace the header with the template"
+	}
+
+	var results []core.Result
+	for _, file := range v.files {
+		if !analyzed[file] {
+			continue
+		}
+		results = append(results, core.Result{
+			RuleID:     RuleID,
+			RuleName:   "Header Drift",
+			Category:   string(core.CategoryStyle),
+			Severity:   string(core.SeverityWarning),
+			FilePath:   file,
+			Line:       v.lines[file],
+			Message:    fmt.Sprintf("Header differs from %s: %s", source, strings.Join(diffs, ", ")),
+			Suggestion: suggestion,
+		})
+	}
+	return results
+}
@@ -0,0 +1,163 @@
+package headers
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+const license = "Copyright 2023 Acme Corp. All rights reserved.\nUse of this source code is governed by the MIT license.\n"
+
+func writeFiles(t *testing.T, root string, files map[string]string) []string {
+	t.Helper()
+	var paths []string
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// comment writes text as line comments with the given marker
+func comment(marker, text string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		b.WriteString(marker + " " + line + "\n")
+	}
+	return b.String()
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    []string
+		start   int
+	}{
+		{"go line comments", "a.go", comment("//", license) + "\npackage a\n", strings.Split(strings.TrimSuffix(license, "\n"), "\n"), 1},
+		{"python after shebang and encoding", "a.py", "#!/usr/bin/env python3\n# -*- coding: utf-8 -*-\n" + comment("#", license), strings.Split(strings.TrimSuffix(license, "\n"), "\n"), 3},
+		{"block comment", "a.ts", "/**\n * Copyright 2023 Acme Corp.\n *\n * MIT licensed.\n */\nexport {}\n", []string{"Copyright 2023 Acme Corp.", "", "MIT licensed."}, 1},
+		{"go package doc", "a.go", "// Package a does things well.\npackage a\n", nil, 0},
+		{"build constraint", "a.go", "//go:build linux\n\npackage a\n", nil, 0},
+		{"tool directive", "a.ts", "// @ts-nocheck\n// Copyright 2023 Acme Corp.\nexport {}\n", nil, 0},
+		{"no comment", "a.py", "import os\n", nil, 0},
+		{"unsupported file", "a.rb", comment("#", license), nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := Extract(tt.path, strings.Split(tt.content, "\n"))
+			if tt.want == nil {
+				if header != nil {
+					t.Fatalf("Expected no header, got %q", header.Lines)
+				}
+				return
+			}
+			if header == nil {
+				t.Fatal("Expected a header")
+			}
+			if !reflect.DeepEqual(header.Lines, tt.want) || header.Start != tt.start {
+				t.Errorf("Expected %q at line %d, got %q at line %d", tt.want, tt.start, header.Lines, header.Start)
+			}
+		})
+	}
+}
+
+func TestDifferences(t *testing.T) {
+	expected := strings.Fields("Copyright 2023 Acme Corp. All rights reserved.")
+	words := strings.Fields("Copyright 2021 Acme Inc. All rights reserved. Really.")
+	want := []string{`"2021" instead of "2023"`, `"Inc." instead of "Corp."`, `extra "Really."`}
+	if got := differences(expected, words); !reflect.DeepEqual(got, want) {
+		t.Errorf("differences() = %q, want %q", got, want)
+	}
+}
+
+func TestAnalyze_ReportsDriftFromMajority(t *testing.T) {
+	root := t.TempDir()
+	files := writeFiles(t, root, map[string]string{
+		"a.go":        comment("//", license) + "\npackage app\n",
+		"b.go":        comment("//", license) + "\npackage app\n",
+		"c.py":        comment("#", license),
+		"d.go":        comment("//", strings.Replace(license, "2023", "2021", 1)) + "\npackage app\n",
+		"e.ts":        "/*\n" + comment(" *", strings.Replace(license, "Acme Corp.", "Widget LLC", 1)) + " */\nexport {}\n",
+		"f.go":        comment("//", "This file is part of another project entirely.\nIt has nothing in common with the rest.") + "\npackage app\n",
+		"gen.go":      "// Code generated by stringer. DO NOT EDIT.\n\n" + "package app\n",
+		"vendored.go": comment("//", strings.Replace(license, "2023", "2019", 1)) + "\npackage app\n",
+	})
+	analyzed := make(map[string]bool)
+	for _, file := range files {
+		if !strings.HasSuffix(file, "vendored.go") {
+			analyzed[file] = true
+		}
+	}
+
+	cfg := core.HeadersConfig{Enabled: true, MinFiles: 3, Threshold: 0.7}
+	results := Analyze(files, analyzed, cfg, nil)
+
+	found := make(map[string]string)
+	for _, result := range results {
+		found[filepath.Base(result.FilePath)] = result.Message
+	}
+	if len(found) != 2 {
+		t.Fatalf("Expected findings for d.go and e.ts, got %v", found)
+	}
+	if found["d.go"] != `Header differs from the header of 3 other files: "2021" instead of "2023"` {
+		t.Errorf("Unexpected message for d.go: %s", found["d.go"])
+	}
+	if found["e.ts"] != `Header differs from the header of 3 other files: "Widget LLC" instead of "Acme Corp."` {
+		t.Errorf("Unexpected message for e.ts: %s", found["e.ts"])
+	}
+
+	// fewer files than MinFiles share the header, so no wording is the project's header
+	cfg.MinFiles = 4
+	if results := Analyze(files, analyzed, cfg, nil); len(results) != 0 {
+		t.Errorf("Expected no findings without a shared header, got %v", results)
+	}
+}
+
+func TestAnalyze_TemplateAndFix(t *testing.T) {
+	root := t.TempDir()
+	files := writeFiles(t, root, map[string]string{
+		"a.go": comment("//", strings.Replace(license, "2023", "2022", 1)) + "\npackage app\n",
+		"b.ts": "/*\n" + comment(" *", strings.Replace(license, "MIT", "BSD", 1)) + " */\nexport {}\n",
+		"c.py": "#!/usr/bin/env python3\n" + comment("#", license) + "import os\n",
+	})
+	analyzed := map[string]bool{files[0]: true, files[1]: true, files[2]: true}
+	template := strings.Split(strings.TrimSuffix(license, "\n"), "\n")
+
+	cfg := core.HeadersConfig{Enabled: true, MinFiles: 3, Threshold: 0.7}
+	results := Analyze(files, analyzed, cfg, template)
+	if len(results) != 2 {
+		t.Fatalf("Expected a.go and b.ts to differ from the template, got %v", results)
+	}
+	for _, result := range results {
+		if !strings.HasPrefix(result.Message, "Header differs from the configured template: ") {
+			t.Errorf("Unexpected message: %s", result.Message)
+		}
+		if err := Fix(result.FilePath, template); err != nil {
+			t.Fatalf("Failed to fix %s: %v", result.FilePath, err)
+		}
+	}
+
+	content, _ := os.ReadFile(filepath.Join(root, "a.go"))
+	if string(content) != comment("//", license)+"\npackage app\n" {
+		t.Errorf("Unexpected fixed a.go:\n%s", content)
+	}
+	content, _ = os.ReadFile(filepath.Join(root, "b.ts"))
+	if string(content) != "/*\n"+comment(" *", license)+" */\nexport {}\n" {
+		t.Errorf("Unexpected fixed b.ts:\n%s", content)
+	}
+	if results := Analyze(files, analyzed, cfg, template); len(results) != 0 {
+		t.Errorf("Expected no findings after fixing, got %v", results)
+	}
+}
@@ -0,0 +1,178 @@
+// Package headers finds the boilerplate comment blocks, such as license headers, at the top
+// of source files and the copies of them that drifted from the rest of the project
+package headers
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// directivePrefixes start comment lines that configure tools rather than describe the file. A
+// comment block holding one is not a header.
+var directivePrefixes = []string{
+	"go:", "+build", "nolint", "Code generated",
+	"@ts-", "@flow", "@format", "@jsx", "eslint", "prettier-ignore", "/ <reference",
+	"type:", "pylint:", "noqa", "mypy:", "fmt:",
+}
+
+// codingLine matches Python's source encoding declaration, which precedes a header
+var codingLine = regexp.MustCompile(`^#.*coding[:=]`)
+
+// Header is the comment block at the top of a file
+type Header struct {
+	Lines []string // comment text without comment markers, outer blank lines removed
+	Start int      // first line of the block, 1-based
+	End   int      // last line of the block, 1-based
+	Block bool     // written as a /* */ comment rather than line comments
+}
+
+// Words returns the words of the header, so headers that differ only in how their lines are
+// wrapped compare equal
+func (h *Header) Words() []string {
+	return strings.Fields(strings.Join(h.Lines, " "))
+}
+
+// lineMarker returns the line comment marker of a source file, or "" for unsupported files
+func lineMarker(path string) string {
+	switch filepath.Ext(path) {
+	case ".py", ".pyi":
+		return "#"
+	case ".go", ".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx", ".mts", ".cts":
+		return "//"
+	}
+	return ""
+}
+
+// Extract returns the header of a file, or nil when it has none. The header is the first
+// comment block, after a shebang and Python's encoding declaration. Blocks holding tool
+// directives are not headers, nor is a Go comment attached to the package clause, since that
+// documents the package.
+func Extract(path string, lines []string) *Header {
+	marker := lineMarker(path)
+	if marker == "" {
+		return nil
+	}
+
+	i := 0
+	for i < len(lines) {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || (i == 0 && strings.HasPrefix(line, "#!")) || (marker == "#" && i < 2 && codingLine.MatchString(line)) {
+			i++
+			continue
+		}
+		break
+	}
+	if i == len(lines) {
+		return nil
+	}
+
+	header := &Header{Start: i + 1}
+	if marker == "//" && strings.HasPrefix(strings.TrimSpace(lines[i]), "/*") {
+		header.Block = true
+		for ; i < len(lines); i++ {
+			text, closed := strings.CutSuffix(strings.TrimSpace(lines[i]), "*/")
+			if !closed && strings.Contains(text, "*/") {
+				return nil // code follows the comment on its closing line
+			}
+			text = strings.TrimPrefix(strings.TrimPrefix(text, "/*"), "*")
+			header.Lines = append(header.Lines, strings.TrimSpace(text))
+			if closed {
+				break
+			}
+		}
+		if i == len(lines) {
+			return nil
+		}
+		i++
+	} else {
+		for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), marker); i++ {
+			header.Lines = append(header.Lines, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), marker)))
+		}
+	}
+	header.End = i
+
+	if marker == "//" && strings.HasSuffix(path, ".go") && i < len(lines) && strings.HasPrefix(lines[i], "package ") {
+		return nil
+	}
+	for _, line := range header.Lines {
+		for _, prefix := range directivePrefixes {
+			if strings.HasPrefix(line, prefix) {
+				return nil
+			}
+		}
+	}
+
+	for len(header.Lines) > 0 && header.Lines[0] == "" {
+		header.Lines = header.Lines[1:]
+	}
+	for len(header.Lines) > 0 && header.Lines[len(header.Lines)-1] == "" {
+		header.Lines = header.Lines[:len(header.Lines)-1]
+	}
+	if len(header.Lines) == 0 {
+		return nil
+	}
+	return header
+}
+
+// render writes template as a comment in the style of the header it replaces
+func render(path string, header *Header, template []string) []string {
+	marker := lineMarker(path)
+	var lines []string
+	if header.Block {
+		lines = append(lines, "/*")
+		for _, line := range template {
+			lines = append(lines, strings.TrimRight(" * "+line, " "))
+		}
+		return append(lines, " */")
+	}
+	for _, line := range template {
+		lines = append(lines, strings.TrimRight(marker+" "+line, " "))
+	}
+	return lines
+}
+
+// Fix replaces the header of a file with template, a list of lines without comment markers,
+// written in the comment style of the header it replaces. Files without a header are left
+// unchanged.
+func Fix(path string, template []string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	header := Extract(path, lines)
+	if header == nil {
+		return nil
+	}
+	fixed := append([]string{}, lines[:header.Start-1]...)
+	fixed = append(fixed, render(path, header, template)...)
+	fixed = append(fixed, lines[header.End:]...)
+	return os.WriteFile(path, []byte(strings.Join(fixed, "\n")), info.Mode().Perm())
+}
+
+// LoadTemplate reads a header template: plain text, without comment markers. Outer blank
+// lines are dropped.
+func LoadTemplate(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t")
+	}
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines, nil
+}
@@ -0,0 +1,146 @@
+package languages
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// interpreterExtensions maps interpreters named on a shebang line, with any version suffix
+// removed, to the extension of the language they run
+var interpreterExtensions = map[string]string{
+	"python":  ".py",
+	"pypy":    ".py",
+	"node":    ".js",
+	"nodejs":  ".js",
+	"bun":     ".js",
+	"deno":    ".ts",
+	"ts-node": ".ts",
+	"tsx":     ".ts",
+}
+
+// modeLinePatterns match Emacs (-*- mode: python -*-) and Vim (vim: set ft=python:) mode lines
+var modeLinePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`-\*-\s*(?:.*\bmode:\s*)?([\w-]+)\s*;?.*-\*-`),
+	regexp.MustCompile(`\b(?:vim?|ex):.*\b(?:ft|filetype)=([\w-]+)`),
+}
+
+// modeLineExtensions maps editor mode names to the extension of their language
+var modeLineExtensions = map[string]string{
+	"python":     ".py",
+	"javascript": ".js",
+	"js":         ".js",
+	"typescript": ".ts",
+}
+
+// modeLineSearch is the number of lines at the start of a file searched for a mode line
+const modeLineSearch = 5
+
+var (
+	// directivePattern matches a language directive such as "// agentlint:language=typescript"
+	directivePattern = regexp.MustCompile(`\bagentlint:language=([\w-]+)`)
+
+	// languageExtensions maps the language names accepted by directives and extension
+	// remapping to the extension routed to that language's analyzer
+	languageExtensions = map[string]string{
+		"go":          ".go",
+		"python":      ".py",
+		"javascript":  ".js",
+		"js":          ".js",
+		"jsx":         ".jsx",
+		"typescript":  ".ts",
+		"ts":          ".ts",
+		"tsx":         ".tsx",
+		"reactnative": ".js",
+	}
+)
+
+// LanguageExtension returns the extension routed to the named language, accepting analyzer
+// names (go, python, reactnative) and the javascript, typescript, jsx and tsx dialects
+func LanguageExtension(language string) (string, bool) {
+	ext, ok := languageExtensions[strings.ToLower(language)]
+	return ext, ok
+}
+
+// DetectExtension returns the extension of the language a file without one is written in,
+// judged from its shebang line (#!/usr/bin/env python3) or an editor mode line near the top,
+// or "" when neither names a supported language
+func DetectExtension(path string) string {
+	return detectFromHeader(readHeader(path))
+}
+
+// readHeader returns the first lines of a file, or nil when it cannot be read or is binary
+func readHeader(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(NewSourceReader(file))
+	for len(lines) < modeLineSearch && scanner.Scan() {
+		line := scanner.Text()
+		if strings.IndexByte(line, 0) >= 0 {
+			return nil // binary file
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// directiveFromHeader finds an agentlint:language directive on the first line of a file, or
+// on the second after a shebang
+func directiveFromHeader(lines []string) string {
+	for i, line := range lines {
+		if i > 1 || (i == 1 && !strings.HasPrefix(lines[0], "#!")) {
+			break
+		}
+		if match := directivePattern.FindStringSubmatch(line); match != nil {
+			ext, _ := LanguageExtension(match[1])
+			return ext
+		}
+	}
+	return ""
+}
+
+// detectFromHeader finds a shebang or mode line naming a supported language
+func detectFromHeader(lines []string) string {
+	for i, line := range lines {
+		if i == 0 && strings.HasPrefix(line, "#!") {
+			if ext := interpreterExtensions[shebangInterpreter(line)]; ext != "" {
+				return ext
+			}
+		}
+		for _, pattern := range modeLinePatterns {
+			if match := pattern.FindStringSubmatch(line); match != nil {
+				if ext := modeLineExtensions[strings.ToLower(match[1])]; ext != "" {
+					return ext
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// shebangInterpreter returns the interpreter a shebang line runs, looking through env and its
+// options, without a version suffix: "#!/usr/bin/env -S python3.12 -u" gives "python"
+func shebangInterpreter(line string) string {
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") && !strings.Contains(field, "=") {
+				interpreter = filepath.Base(field)
+				break
+			}
+		}
+	}
+	return strings.TrimRight(interpreter, "0123456789.")
+}
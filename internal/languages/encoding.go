@@ -0,0 +1,130 @@
+package languages
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// encodingSniffLength is how many bytes at the start of a file are looked at to tell UTF-16
+// without a byte order mark from UTF-8
+const encodingSniffLength = 512
+
+// NewSourceReader returns a reader of the UTF-8 text of a source file, whatever its encoding:
+// a UTF-8 byte order mark is dropped, and UTF-16 is converted, whether it starts with a byte
+// order mark or is recognized by the zero bytes ASCII characters leave in it. Any other input
+// is passed through unchanged.
+func NewSourceReader(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(encodingSniffLength)
+	switch {
+	case bytes.HasPrefix(head, utf8BOM):
+		br.Discard(len(utf8BOM))
+	case bytes.HasPrefix(head, utf16LEBOM):
+		br.Discard(len(utf16LEBOM))
+		return &utf16Reader{r: br, order: binary.LittleEndian}
+	case bytes.HasPrefix(head, utf16BEBOM):
+		br.Discard(len(utf16BEBOM))
+		return &utf16Reader{r: br, order: binary.BigEndian}
+	default:
+		if order := sniffUTF16(head); order != nil {
+			return &utf16Reader{r: br, order: order}
+		}
+	}
+	return br
+}
+
+// DecodeSource returns the UTF-8 text of a source file read whole, see NewSourceReader
+func DecodeSource(data []byte) []byte {
+	decoded, err := io.ReadAll(NewSourceReader(bytes.NewReader(data)))
+	if err != nil {
+		return data
+	}
+	return decoded
+}
+
+// sniffUTF16 recognizes UTF-16 text without a byte order mark from the start of a file: UTF-8
+// text has no zero bytes, while source code is mostly ASCII, which UTF-16 encodes with a zero
+// byte in every other position. It returns nil for anything else, including binary files,
+// whose zero bytes fall anywhere.
+func sniffUTF16(head []byte) binary.ByteOrder {
+	if len(head) < 4 {
+		return nil
+	}
+	var even, odd int
+	for i := 0; i+1 < len(head); i += 2 {
+		if head[i] == 0 {
+			even++
+		}
+		if head[i+1] == 0 {
+			odd++
+		}
+	}
+	pairs := len(head) / 2
+	switch {
+	case odd*2 >= pairs && even*8 < odd:
+		return binary.LittleEndian
+	case even*2 >= pairs && odd*8 < even:
+		return binary.BigEndian
+	}
+	return nil
+}
+
+// utf16Reader converts UTF-16 text to UTF-8
+type utf16Reader struct {
+	r       *bufio.Reader
+	order   binary.ByteOrder
+	pending []byte // converted text not read yet
+}
+
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	for len(u.pending) < len(p) {
+		c, err := u.next()
+		if err != nil {
+			if len(u.pending) == 0 {
+				return 0, err
+			}
+			break
+		}
+		if c >= 0xD800 && c < 0xDC00 { // the first half of a surrogate pair
+			if low, err := u.next(); err == nil {
+				c = utf16.DecodeRune(c, low)
+			}
+		}
+		u.pending = utf8.AppendRune(u.pending, c) // a lone surrogate becomes utf8.RuneError
+	}
+	n := copy(p, u.pending)
+	u.pending = u.pending[n:]
+	return n, nil
+}
+
+// next reads a UTF-16 code unit; an odd byte at the end of the input is dropped
+func (u *utf16Reader) next() (rune, error) {
+	var unit [2]byte
+	if _, err := io.ReadFull(u.r, unit[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		return 0, err
+	}
+	return rune(u.order.Uint16(unit[:])), nil
+}
+
+// Column returns the 1-based column, counted in characters, of the byte at the 1-based byte
+// column of line. Parsers count columns in bytes, which puts findings after a multi-byte
+// character, such as an emoji, too far to the right.
+func Column(line string, byteColumn int) int {
+	if byteColumn < 1 || byteColumn > len(line)+1 {
+		return byteColumn
+	}
+	return utf8.RuneCountInString(line[:byteColumn-1]) + 1
+}
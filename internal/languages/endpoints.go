@@ -0,0 +1,174 @@
+package languages
+
+import (
+	"net"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EndpointRuleID identifies findings of URLs, IP addresses and ports written into code
+const EndpointRuleID = "hardcoded-endpoint"
+
+// DefaultAllowedHosts are hosts whose URLs name a standard or an XML namespace rather than a
+// service the code talks to. Teams can allow more hosts through configuration.
+var DefaultAllowedHosts = []string{
+	"www.w3.org",
+	"w3.org",
+	"json-schema.org",
+	"schemas.android.com",
+	"schemas.xmlsoap.org",
+	"schemas.openxmlformats.org",
+	"purl.org",
+	"xmlns.com",
+}
+
+// placeholderDomains are reserved for documentation and local use, so URLs on them are
+// examples that were never replaced
+var placeholderDomains = []string{"localhost", "example.com", "example.net", "example.org", "example", "test", "invalid", "local"}
+
+// configDirs hold files whose purpose is to name endpoints
+var configDirs = map[string]bool{"config": true, "configs": true, "conf": true, "settings": true, "fixtures": true, "testdata": true, "examples": true, "mocks": true}
+
+// configStems are the names, without extension, of files whose purpose is to name endpoints
+var configStems = map[string]bool{"config": true, "configs": true, "conf": true, "settings": true, "constants": true, "env": true, "environment": true, "defaults": true, "conftest": true}
+
+var (
+	urlPattern      = regexp.MustCompile(`(?i)\bhttps?://[^\s"'` + "`" + `<>(){}\[\]\\]+`)
+	ipv4Pattern     = regexp.MustCompile(`(?:^|[^\w.])(\d{1,3}(?:\.\d{1,3}){3})(?:$|[^\w.])`)
+	hostPortPattern = regexp.MustCompile(`^(localhost|[\w-]+(?:\.[\w-]+)+)?:(\d{2,5})$`)
+)
+
+// Endpoint is a network address written into a string literal
+type Endpoint struct {
+	Kind  string // "URL", "IP address" or "address"
+	Value string
+}
+
+// FindEndpoint returns the first hardcoded endpoint in the text of a string literal: an
+// http:// URL, a URL on a placeholder host such as localhost or api.example.com or with an
+// explicit port, an IPv4 address, or a whole string that is a host and port such as
+// localhost:8080 or :8080. URLs built from placeholders such as %s or {host} are skipped, as
+// are the hosts of DefaultAllowedHosts and allowed, with their subdomains, and endpoints
+// listed in allowed verbatim.
+func FindEndpoint(text string, allowed []string) (Endpoint, bool) {
+	for _, match := range urlPattern.FindAllString(text, -1) {
+		match = strings.TrimRight(match, ".,;:")
+		u, err := url.Parse(match)
+		if err != nil || u.Host == "" || strings.ContainsAny(u.Host, "%{}$<") {
+			continue
+		}
+		host := strings.ToLower(u.Hostname())
+		if isAllowedEndpoint(host, match, allowed) {
+			continue
+		}
+		if strings.EqualFold(u.Scheme, "http") || u.Port() != "" || isPlaceholderHost(host) {
+			return Endpoint{Kind: "URL", Value: match}, true
+		}
+	}
+	text = urlPattern.ReplaceAllString(text, " ")
+
+	if m := hostPortPattern.FindStringSubmatch(strings.TrimSpace(text)); m != nil {
+		if port, _ := strconv.Atoi(m[2]); port > 0 && port <= 65535 && !isAllowedEndpoint(strings.ToLower(m[1]), m[0], allowed) {
+			if m[1] == "" || net.ParseIP(m[1]) != nil || !isNumeric(m[1]) {
+				return Endpoint{Kind: "address", Value: m[0]}, true
+			}
+		}
+	}
+
+	for _, m := range ipv4Pattern.FindAllStringSubmatch(text, -1) {
+		if net.ParseIP(m[1]) != nil && !isAllowedEndpoint(m[1], m[1], allowed) {
+			return Endpoint{Kind: "IP address", Value: m[1]}, true
+		}
+	}
+	return Endpoint{}, false
+}
+
+// isAllowedEndpoint reports whether a host, or the whole endpoint, is allowed
+func isAllowedEndpoint(host, endpoint string, allowed []string) bool {
+	for _, lists := range [][]string{DefaultAllowedHosts, allowed} {
+		for _, entry := range lists {
+			entry = strings.ToLower(strings.TrimSpace(entry))
+			if entry == "" {
+				continue
+			}
+			if entry == strings.ToLower(endpoint) || (host != "" && (host == entry || strings.HasSuffix(host, "."+entry))) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isPlaceholderHost reports whether a host is local, an IP address, or on a domain reserved
+// for documentation
+func isPlaceholderHost(host string) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	for _, domain := range placeholderDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNumeric reports whether a dotted host is made of digits only, like a version number
+func isNumeric(host string) bool {
+	return strings.Trim(host, "0123456789.") == ""
+}
+
+// IsConfigFile reports whether a file exists to hold configuration or sample data, where
+// endpoints belong: settings.py, constants.ts, vite.config.js, or anything under a directory
+// such as config/, fixtures/ or examples/
+func IsConfigFile(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	stem := strings.TrimSuffix(base, filepath.Ext(base))
+	if configStems[stem] || strings.HasSuffix(stem, ".config") || strings.HasSuffix(stem, "_config") || strings.HasSuffix(stem, "_settings") {
+		return true
+	}
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		if configDirs[strings.ToLower(dir)] {
+			return true
+		}
+	}
+	return false
+}
+
+// QuotedString is the contents of a string literal on one line of code
+type QuotedString struct {
+	Text   string
+	Column int // 1-based column of the opening quote, in characters
+}
+
+// QuotedStrings returns the string literals that open and close on a line, delimited by any of
+// quotes, up to the first comment marker outside a string. Escaped quotes are kept in the
+// text; strings left open at the end of the line are ignored.
+func QuotedStrings(line, quotes, comment string) []QuotedString {
+	var strs []QuotedString
+	for i := 0; i < len(line); i++ {
+		if strings.HasPrefix(line[i:], comment) {
+			break
+		}
+		if !strings.ContainsRune(quotes, rune(line[i])) {
+			continue
+		}
+		quote := line[i]
+		end := i + 1
+		for end < len(line) && line[end] != quote {
+			if line[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(line) {
+			break
+		}
+		strs = append(strs, QuotedString{Text: line[i+1 : end], Column: Column(line, i+1)})
+		i = end
+	}
+	return strs
+}
@@ -0,0 +1,98 @@
+package languages
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultAIPhrases are phrases that commonly leak from chat-assistant answers into code comments.
+// Matching is case-insensitive; teams can add their own through configuration.
+var DefaultAIPhrases = []string{
+	"here's the updated code",
+	"here is the updated code",
+	"here's the modified code",
+	"here is the modified code",
+	"here's the corrected code",
+	"as an ai",
+	"as a language model",
+	"note: you may need to",
+	"you may need to adjust",
+	"i hope this helps",
+	"let me know if you",
+	"rest of the code remains",
+	"rest of your code",
+	"replace with your actual",
+	"in a real application",
+	"in a real-world application",
+}
+
+// restatingHeaders are comment openings that describe the code generically instead of naming it
+var restatingHeaders = []string{
+	"this function ",
+	"this method ",
+	"this class ",
+	"this code ",
+	"the following function ",
+	"the following code ",
+}
+
+// MatchAIFingerprint checks a single comment line (with or without its comment marker) for
+// LLM boilerplate fingerprints. It returns a short description of what matched, or "" if nothing did.
+func MatchAIFingerprint(comment string, extraPhrases []string) string {
+	text := stripCommentMarker(comment)
+	if text == "" {
+		return ""
+	}
+
+	lower := strings.ToLower(text)
+	for _, phrase := range DefaultAIPhrases {
+		if strings.Contains(lower, phrase) {
+			return fmt.Sprintf("phrase %q", phrase)
+		}
+	}
+	for _, phrase := range extraPhrases {
+		phrase = strings.ToLower(strings.TrimSpace(phrase))
+		if phrase != "" && strings.Contains(lower, phrase) {
+			return fmt.Sprintf("phrase %q", phrase)
+		}
+	}
+
+	for _, header := range restatingHeaders {
+		if strings.HasPrefix(lower, header) {
+			return fmt.Sprintf("generic header %q", strings.TrimSpace(text[:len(header)]))
+		}
+	}
+
+	if r, _ := utf8.DecodeRuneInString(text); isEmoji(r) {
+		return "emoji-prefixed comment"
+	}
+
+	return ""
+}
+
+// stripCommentMarker removes leading comment syntax (//, #, /*, /**, *) and a trailing */
+func stripCommentMarker(comment string) string {
+	text := strings.TrimSpace(comment)
+	for _, marker := range []string{"/**", "/*", "//", "#", "*"} {
+		if strings.HasPrefix(text, marker) {
+			text = text[len(marker):]
+			break
+		}
+	}
+	text = strings.TrimSuffix(text, "*/")
+	return strings.TrimSpace(text)
+}
+
+// isEmoji reports whether r falls in the pictograph and symbol ranges used for emoji
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F300 && r <= 0x1FAFF: // pictographs, emoticons, transport, supplemental symbols
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // miscellaneous symbols and dingbats (✅, ⚠, ✨)
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // arrows and stars (⭐)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package languages
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// generatedMarkers match the whole text of a header comment line that code generators put
+// in their output: Go's convention, in whatever comment syntax the language uses, and the
+// @generated tag, which Relay follows with a signature of the file
+var generatedMarkers = []*regexp.Regexp{
+	regexp.MustCompile(`^Code generated .* DO NOT EDIT\.$`),
+	regexp.MustCompile(`^@generated( SignedSource<<[0-9a-f]+>>)?$`),
+}
+
+// vendoredDirs hold third-party code copied into a project
+var vendoredDirs = map[string]bool{
+	"vendor":       true,
+	"node_modules": true,
+	"third_party":  true,
+}
+
+// GeneratedHeaderLines is how far into a file generation markers are looked for
+const GeneratedHeaderLines = 20
+
+// IsGenerated reports whether a comment line among the first lines of a file is a code
+// generation marker, such as Go's "// Code generated ... DO NOT EDIT." or a "@generated"
+// tag. Comments that only mention generated code, or quote a marker, do not count.
+func IsGenerated(lines []string) bool {
+	for i, line := range lines {
+		if i >= GeneratedHeaderLines {
+			break
+		}
+		trimmed := strings.TrimSpace(line)
+		text := stripCommentMarker(trimmed)
+		if text == trimmed {
+			continue // not a comment
+		}
+		for _, marker := range generatedMarkers {
+			if marker.MatchString(text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsVendored reports whether a path lies inside a vendored dependency directory
+func IsVendored(path string) bool {
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		if vendoredDirs[dir] {
+			return true
+		}
+	}
+	return false
+}
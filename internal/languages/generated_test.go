@@ -0,0 +1,33 @@
+package languages_test
+
+import (
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+)
+
+func TestIsGenerated(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"go marker", "// Code generated by stringer. DO NOT EDIT.", true},
+		{"python marker", "# Code generated by protoc-gen-python. DO NOT EDIT.", true},
+		{"block comment marker", "/* Code generated by swagger. DO NOT EDIT. */", true},
+		{"generated tag", " * @generated", true},
+		{"relay signature", " * @generated SignedSource<<0123abcd>>", true},
+		{"mention of generated code", "// Code generated by hand, feel free to edit", false},
+		{"do not edit alone", "// DO NOT EDIT without telling the platform team", false},
+		{"quoted marker", "// files with \"// Code generated ... DO NOT EDIT.\" are skipped", false},
+		{"tag in a sentence", "// the @generated tag marks Relay output", false},
+		{"not a comment", "var header = \"Code generated by x. DO NOT EDIT.\"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := languages.IsGenerated([]string{"package a", tt.header}); got != tt.want {
+				t.Errorf("IsGenerated(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
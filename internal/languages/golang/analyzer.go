@@ -2,19 +2,26 @@ package golang
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go/ast"
+	"go/scanner"
 	"go/token"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
 	"github.com/CiaranMcAleer/AgentLint/internal/languages"
 	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang/rules"
+	"github.com/CiaranMcAleer/AgentLint/internal/profiling"
 )
 
-// Analyzer implements the core.Analyzer interface for Go
+// Analyzer implements the core.Analyzer interface for Go. Its rules keep no state between
+// files, so one Analyzer may analyze files from many goroutines, as the core.Engine workers
+// do; call SetCache before sharing it.
 type Analyzer struct {
 	parser *Parser
 	rules  []core.Rule
@@ -33,6 +40,21 @@ func NewAnalyzer(config core.Config) *Analyzer {
 		rules.NewUnusedVariableRule(config),
 		rules.NewUnreachableCodeRule(config),
 		rules.NewDeadImportRule(config),
+		rules.NewGodStructRule(config),
+		rules.NewTooManyMethodsRule(config),
+		rules.NewTooManyReturnValuesRule(config),
+		rules.NewNakedReturnRule(config),
+		rules.NewLongBranchChainRule(config),
+		rules.NewSleepSynchronizationRule(config),
+		rules.NewGoroutineLeakRule(config),
+		rules.NewMutexCopyRule(config),
+		rules.NewDeferInLoopRule(config),
+		rules.NewAICommentRule(config),
+		rules.NewRedundantCommentRule(config),
+		rules.NewHardcodedEndpointRule(config),
+		rules.NewAssertionFreeTestRule(config),
+		rules.NewDuplicateTestRule(config),
+		rules.NewTrivialGetterTestRule(config),
 	}
 
 	return &Analyzer{
@@ -43,8 +65,19 @@ func NewAnalyzer(config core.Config) *Analyzer {
 
 // Analyze analyzes a Go file and returns results
 func (a *Analyzer) Analyze(ctx context.Context, filePath string, config core.Config) ([]core.Result, error) {
+	defer profiling.TrackFile(filePath, time.Now())
+
+	config.Rules = config.Language.Go.Rules.Apply(config.Rules)
+	if languages.IsTestFile(filePath) {
+		config = config.ForTestFile()
+	}
+	config = languages.ForAIFile(filePath, config)
+
 	file, fset, err := a.parser.ParseFile(ctx, filePath)
 	if err != nil {
+		if config.Parsing.Tolerant && file != nil {
+			return a.analyzePartial(ctx, file, fset, filePath, config, err)
+		}
 		return nil, fmt.Errorf("failed to parse file %s: %w", filePath, err)
 	}
 
@@ -57,32 +90,63 @@ func (a *Analyzer) Analyze(ctx context.Context, filePath string, config core.Con
 	results := make([]core.Result, 0, 8)
 	results = a.applyFileRules(ctx, results, fileMetrics, config)
 	results = a.applyFunctionRules(ctx, results, file, fset, filePath, config)
+	results = a.applyTypeRules(ctx, results, file, fset, filePath, config)
+	results = a.applyCommentRules(ctx, results, file, fset, filePath, config)
+	results = a.applyStringRules(ctx, results, file, fset, filePath, config)
+	results = a.applyTestRules(ctx, results, file, fset, filePath, config)
 
 	return results, nil
 }
 
+// analyzePartial analyzes the partial AST of a file with syntax errors. Declarations after
+// an error may be missing, so only the line-based file and comment rules are applied.
+func (a *Analyzer) analyzePartial(ctx context.Context, file *ast.File, fset *token.FileSet, filePath string, config core.Config, parseErr error) ([]core.Result, error) {
+	fileMetrics, err := a.parser.CalculateMetrics(ctx, filePath, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate metrics for file %s: %w", filePath, err)
+	}
+
+	line, detail := 0, parseErr.Error()
+	var errList scanner.ErrorList
+	if errors.As(parseErr, &errList) && len(errList) > 0 {
+		line, detail = errList[0].Pos.Line, errList[0].Msg
+	}
+
+	config.Rules.OrphanedCode.Enabled = false
+	results := []core.Result{languages.SyntaxErrorResult(filePath, line, detail)}
+	results = a.applyFileRules(ctx, results, fileMetrics, config)
+	results = a.applyCommentRules(ctx, results, file, fset, filePath, config)
+	return results, nil
+}
+
 // applyFileRules applies file-level rules and returns accumulated results
 func (a *Analyzer) applyFileRules(ctx context.Context, results []core.Result, metrics *rules.FileMetrics, config core.Config) []core.Result {
 	for _, rule := range a.rules {
-		if !isRuleEnabled(rule, config) || isFunctionRule(rule) {
+		if !isRuleEnabled(rule, config) || isFunctionRule(rule) || isTypeRule(rule) || isCommentRule(rule) || isStringRule(rule) || isTestRule(rule) {
 			continue
 		}
+		start := time.Now()
 		if result := rule.Check(ctx, metrics, config); result != nil {
 			if result.FilePath == "" {
 				result.FilePath = metrics.Path
 			}
 			results = append(results, *result)
 		}
+		profiling.TrackRule(rule.ID(), start)
 	}
 	return results
 }
 
-// applyFunctionRules applies function-level rules to each function in the file
+// applyFunctionRules applies function-level rules to each function in the file. Tests sleep to
+// let timers and deadlines expire, so sleep-synchronization skips test files.
 func (a *Analyzer) applyFunctionRules(ctx context.Context, results []core.Result, file *ast.File, fset *token.FileSet, filePath string, config core.Config) []core.Result {
+	testFile := languages.IsTestFile(filePath)
+	var srcLines []string // read for the first finding covering a range
 	for _, rule := range a.rules {
-		if !isRuleEnabled(rule, config) || !isFunctionRule(rule) {
+		if !isRuleEnabled(rule, config) || !isFunctionRule(rule) || (testFile && skipsTestFiles(rule)) {
 			continue
 		}
+		start := time.Now()
 		ast.Inspect(file, func(n ast.Node) bool {
 			funcDecl, ok := n.(*ast.FuncDecl)
 			if !ok {
@@ -96,26 +160,292 @@ func (a *Analyzer) applyFunctionRules(ctx context.Context, results []core.Result
 				if result.FilePath == "" {
 					result.FilePath = filePath
 				}
+				if result.EndColumn > 0 {
+					if srcLines == nil {
+						srcLines = readLines(filePath)
+					}
+					result.EndColumn = charColumn(srcLines, token.Position{Line: result.EndLine, Column: result.EndColumn})
+				}
 				results = append(results, *result)
 			}
 			return true
 		})
+		profiling.TrackRule(rule.ID(), start)
+	}
+	return results
+}
+
+// applyTypeRules applies type-level rules to each type declared in the file
+func (a *Analyzer) applyTypeRules(ctx context.Context, results []core.Result, file *ast.File, fset *token.FileSet, filePath string, config core.Config) []core.Result {
+	var typeMetrics []*rules.TypeMetrics
+	for _, rule := range a.rules {
+		if !isRuleEnabled(rule, config) || !isTypeRule(rule) {
+			continue
+		}
+		start := time.Now()
+		if typeMetrics == nil {
+			typeMetrics = a.parser.CalculateTypeMetrics(ctx, filePath, file, fset)
+		}
+		for _, metrics := range typeMetrics {
+			if result := rule.Check(ctx, metrics, config); result != nil {
+				if result.FilePath == "" {
+					result.FilePath = filePath
+				}
+				results = append(results, *result)
+			}
+		}
+		profiling.TrackRule(rule.ID(), start)
+	}
+	return results
+}
+
+// applyCommentRules applies comment rules to every line of every comment in the file
+func (a *Analyzer) applyCommentRules(ctx context.Context, results []core.Result, file *ast.File, fset *token.FileSet, filePath string, config core.Config) []core.Result {
+	var commentLines []*rules.CommentGroup
+	for _, rule := range a.rules {
+		if !isRuleEnabled(rule, config) || !isCommentRule(rule) {
+			continue
+		}
+		start := time.Now()
+		if commentLines == nil {
+			commentLines = collectCommentLines(file, fset, filePath)
+		}
+		for _, commentLine := range commentLines {
+			if result := rule.Check(ctx, commentLine, config); result != nil {
+				result.FilePath = filePath
+				results = append(results, *result)
+			}
+		}
+		profiling.TrackRule(rule.ID(), start)
 	}
 	return results
 }
 
+// applyStringRules applies string rules to every string literal in the file. Endpoints belong
+// in test and configuration files, so those are skipped.
+func (a *Analyzer) applyStringRules(ctx context.Context, results []core.Result, file *ast.File, fset *token.FileSet, filePath string, config core.Config) []core.Result {
+	if languages.IsTestFile(filePath) || languages.IsConfigFile(filePath) {
+		return results
+	}
+	var literals []*rules.StringLiteral
+	for _, rule := range a.rules {
+		if !isRuleEnabled(rule, config) || !isStringRule(rule) {
+			continue
+		}
+		start := time.Now()
+		if literals == nil {
+			literals = collectStringLiterals(file, fset, filePath)
+		}
+		for _, literal := range literals {
+			if result := rule.Check(ctx, literal, config); result != nil {
+				result.FilePath = filePath
+				results = append(results, *result)
+			}
+		}
+		profiling.TrackRule(rule.ID(), start)
+	}
+	return results
+}
+
+// applyTestRules applies the test quality rules to the Test functions of a _test.go file
+func (a *Analyzer) applyTestRules(ctx context.Context, results []core.Result, file *ast.File, fset *token.FileSet, filePath string, config core.Config) []core.Result {
+	if !languages.IsTestFile(filePath) {
+		return results
+	}
+	var tests []*rules.TestFunction
+	for _, rule := range a.rules {
+		if !isRuleEnabled(rule, config) || !isTestRule(rule) {
+			continue
+		}
+		start := time.Now()
+		if tests == nil {
+			tests = collectTestFunctions(file, fset, config.Rules.TestQuality.Threshold)
+		}
+		for _, test := range tests {
+			if result := rule.Check(ctx, test, config); result != nil {
+				result.FilePath = filePath
+				results = append(results, *result)
+			}
+		}
+		profiling.TrackRule(rule.ID(), start)
+	}
+	return results
+}
+
+// collectStringLiterals returns the string literals of the file, except import paths and
+// struct tags
+func collectStringLiterals(file *ast.File, fset *token.FileSet, filePath string) []*rules.StringLiteral {
+	srcLines := readLines(filePath)
+	var literals []*rules.StringLiteral
+	tags := make(map[*ast.BasicLit]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ImportSpec:
+			return false
+		case *ast.Field:
+			tags[node.Tag] = true
+		case *ast.BasicLit:
+			if node.Kind != token.STRING || tags[node] {
+				return true
+			}
+			if value, err := strconv.Unquote(node.Value); err == nil {
+				pos := fset.Position(node.Pos())
+				pos.Column = charColumn(srcLines, pos)
+				literals = append(literals, &rules.StringLiteral{Value: value, Position: pos})
+			}
+		}
+		return true
+	})
+	return literals
+}
+
+// readLines returns the lines of a file, or nil when it cannot be read
+func readLines(filePath string) []string {
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(string(src), "\n")
+}
+
+// charColumn returns the column of pos in characters rather than the bytes go/token counts,
+// given the lines of its file. The parser skips a byte order mark, which does not count.
+func charColumn(srcLines []string, pos token.Position) int {
+	if pos.Line < 1 || pos.Line > len(srcLines) {
+		return pos.Column
+	}
+	line := srcLines[pos.Line-1]
+	column := languages.Column(line, pos.Column)
+	if pos.Line == 1 && strings.HasPrefix(line, "\uFEFF") {
+		column--
+	}
+	return column
+}
+
+// collectCommentLines splits every comment in the file into lines. The last line of each
+// comment is paired with the code it describes, except for doc comments on declarations.
+func collectCommentLines(file *ast.File, fset *token.FileSet, filePath string) []*rules.CommentGroup {
+	srcLines := readLines(filePath)
+	docGroups := collectDocComments(file)
+
+	var commentLines []*rules.CommentGroup
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			pos := fset.Position(comment.Pos())
+			lines := strings.Split(comment.Text, "\n")
+			for i, line := range lines {
+				commentLine := &rules.CommentGroup{Text: line, Position: pos}
+				commentLine.Position.Line += i
+				commentLines = append(commentLines, commentLine)
+			}
+
+			if docGroups[group] || pos.Line > len(srcLines) {
+				continue
+			}
+			last := commentLines[len(commentLines)-1]
+			if before := strings.TrimSpace(srcLines[pos.Line-1][:pos.Column-1]); before != "" {
+				last.Code = before
+			} else if comment == group.List[len(group.List)-1] {
+				last.Code = languages.CodeAfter(srcLines, last.Position.Line, "//", "/*", "*")
+			}
+		}
+	}
+	return commentLines
+}
+
+// collectDocComments returns the comment groups documenting the package or a declaration
+func collectDocComments(file *ast.File) map[*ast.CommentGroup]bool {
+	docs := map[*ast.CommentGroup]bool{file.Doc: true}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			docs[node.Doc] = true
+		case *ast.GenDecl:
+			docs[node.Doc] = true
+		case *ast.TypeSpec:
+			docs[node.Doc] = true
+		case *ast.ValueSpec:
+			docs[node.Doc] = true
+		case *ast.Field:
+			docs[node.Doc] = true
+		}
+		return true
+	})
+	delete(docs, nil)
+	return docs
+}
+
 // SupportedExtensions returns the file extensions supported by this analyzer
 func (a *Analyzer) SupportedExtensions() []string {
 	return []string{".go"}
 }
 
+// SetCache makes the analyzer parse files through a cache shared with the cross-file analysis
+func (a *Analyzer) SetCache(cache *ASTCache) {
+	a.parser.SetCache(cache)
+}
+
+// FlushCache drops the parsed files held by the analyzer's cache, which may be shared with the
+// other Go analyses; they are parsed again when needed
+func (a *Analyzer) FlushCache() {
+	if a.parser.cache != nil {
+		a.parser.cache.InvalidateAll()
+	}
+}
+
 // Name returns the name of this analyzer
 func (a *Analyzer) Name() string {
 	return "go"
 }
 
+// Rules returns the rules applied to each file, for agentlint explain
+func (a *Analyzer) Rules() []core.Rule {
+	return a.rules
+}
+
+// CountFunctions returns the number of functions and methods declared in a Go file, for
+// core.Escalate
+func (a *Analyzer) CountFunctions(ctx context.Context, filePath string) (int, error) {
+	file, _, err := a.parser.ParseFile(ctx, filePath)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, decl := range file.Decls {
+		if _, ok := decl.(*ast.FuncDecl); ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
 // isRuleEnabled checks if a rule is enabled in the configuration
 func isRuleEnabled(rule core.Rule, config core.Config) bool {
+	if config.RuleDisabled(rule.ID()) {
+		return false
+	}
+	if isTypeRule(rule) {
+		return config.Rules.TypeSize.Enabled
+	}
+	if isReturnRule(rule) {
+		return config.Rules.Returns.Enabled
+	}
+	if rule.ID() == "ai-comment-fingerprint" {
+		return config.Rules.AIComments.Enabled
+	}
+	if rule.ID() == "long-branch-chain" {
+		return config.Rules.Branches.Enabled
+	}
+	if isStringRule(rule) {
+		return config.Rules.Endpoints.Enabled
+	}
+	if isTestRule(rule) {
+		return config.Rules.TestQuality.Enabled
+	}
+	if isConcurrencyRule(rule) {
+		return config.Rules.Concurrency.Enabled
+	}
+
 	switch rule.Category() {
 	case core.CategorySize:
 		if strings.Contains(rule.ID(), "function") {
@@ -136,7 +466,10 @@ func isRuleEnabled(rule core.Rule, config core.Config) bool {
 func isFunctionRule(rule core.Rule) bool {
 	return strings.Contains(rule.ID(), "function") ||
 		strings.Contains(rule.ID(), "unused") ||
-		strings.Contains(rule.ID(), "unreachable")
+		strings.Contains(rule.ID(), "unreachable") ||
+		isReturnRule(rule) ||
+		rule.ID() == "long-branch-chain" ||
+		isConcurrencyRule(rule)
 }
 
 // FileScanner scans directories for Go files
@@ -208,3 +541,42 @@ func (s *FileScanner) ScanForRegistry(ctx context.Context, rootPath string, regi
 
 	return filesByLanguage, nil
 }
+
+// isReturnRule checks if a rule inspects function return values
+func isReturnRule(rule core.Rule) bool {
+	return rule.ID() == "too-many-return-values" || rule.ID() == "naked-return"
+}
+
+// isCommentRule checks if a rule inspects individual comment lines
+func isCommentRule(rule core.Rule) bool {
+	return rule.ID() == "ai-comment-fingerprint" || rule.ID() == "redundant-comment"
+}
+
+// isStringRule checks if a rule inspects string literals
+func isStringRule(rule core.Rule) bool {
+	return rule.ID() == languages.EndpointRuleID
+}
+
+// isTestRule checks if a rule inspects the Test functions of test files
+func isTestRule(rule core.Rule) bool {
+	return languages.IsTestQualityRule(rule.ID())
+}
+
+// isConcurrencyRule checks if a rule inspects goroutines, locks and what waits for them
+func isConcurrencyRule(rule core.Rule) bool {
+	switch rule.ID() {
+	case "sleep-synchronization", "goroutine-leak", "mutex-copy", "defer-in-loop":
+		return true
+	}
+	return false
+}
+
+// skipsTestFiles checks if a rule leaves test files alone
+func skipsTestFiles(rule core.Rule) bool {
+	return rule.ID() == "sleep-synchronization"
+}
+
+// isTypeRule checks if a rule applies to type declarations
+func isTypeRule(rule core.Rule) bool {
+	return rule.ID() == "god-struct" || rule.ID() == "too-many-methods"
+}
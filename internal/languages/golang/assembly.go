@@ -0,0 +1,88 @@
+package golang
+
+import (
+	"go/ast"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// asmSymbolPattern matches a symbol of the file's own package in Go assembly, such as the
+// ·helper in CALL ·helper(SB) or TEXT ·add<ABIInternal>(SB)
+var asmSymbolPattern = regexp.MustCompile(`(?:^|[^\w./])·(\w+)(?:<\w+>)?\(SB\)`)
+
+// isAssemblyFile reports whether path is a non-test Go assembly file
+func isAssemblyFile(path string) bool {
+	return strings.HasSuffix(path, ".s") && !strings.HasSuffix(path, "_test.s")
+}
+
+// analyzeSource analyzes a Go, assembly or template file
+func (a *CrossFileAnalyzer) analyzeSource(filePath string) error {
+	if isAssemblyFile(filePath) {
+		return a.analyzeAssembly(filePath)
+	}
+	if isTemplateFile(filePath) {
+		return a.analyzeTemplate(filePath)
+	}
+	return a.analyzeFile(filePath)
+}
+
+// analyzeAssembly records the Go functions an assembly file calls or defines. Assembly is
+// part of the package in its directory, so a Go function it calls is used.
+func (a *CrossFileAnalyzer) analyzeAssembly(filePath string) error {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stamps[filePath] = stampOf(info)
+	dir := filepath.Dir(filePath)
+	for _, match := range asmSymbolPattern.FindAllStringSubmatch(string(src), -1) {
+		a.assembly.add(assemblyKey(dir, match[1]), filePath)
+	}
+	a.markDirStale(dir)
+	return nil
+}
+
+// assemblyKey identifies a function named from the assembly files of dir
+func assemblyKey(dir, name string) string {
+	return dir + "·" + name
+}
+
+// isUsedFromAssembly reports whether an assembly file of the function's package names it
+func (a *CrossFileAnalyzer) isUsedFromAssembly(funcInfo *FunctionInfo) bool {
+	return a.assembly.has(assemblyKey(filepath.Dir(funcInfo.File), funcInfo.Name))
+}
+
+// markDirStale marks the packages in dir for the next FindUnusedFunctions
+func (a *CrossFileAnalyzer) markDirStale(dir string) {
+	for file, pkg := range a.packages {
+		if filepath.Dir(file) == dir {
+			a.stale[pkg] = true
+		}
+	}
+}
+
+// collectLinkerDirectives records functions used from outside Go code: those exported to C
+// with a cgo //export comment and those named by //go:linkname, which the linker resolves by
+// symbol name
+func (a *CrossFileAnalyzer) collectLinkerDirectives(f *ast.File, filePath string) {
+	for _, group := range f.Comments {
+		for _, comment := range group.List {
+			fields := strings.Fields(comment.Text)
+			if len(fields) < 2 {
+				continue
+			}
+			if fields[0] == "//go:linkname" || fields[0] == "//export" {
+				a.funcReferences.add(a.scopeKey(filePath, fields[1]), filePath)
+			}
+		}
+	}
+}
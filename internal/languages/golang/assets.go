@@ -0,0 +1,246 @@
+package golang
+
+import (
+	"go/ast"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// templateExtensions are the file extensions of text/template and html/template sources,
+// which are analyzed wherever they are found
+var templateExtensions = []string{".tmpl", ".tpl", ".gotmpl", ".gohtml"}
+
+// templateActionPattern matches a template action such as {{ .User.FullName }}
+var templateActionPattern = regexp.MustCompile(`(?s)\{\{(.*?)\}\}`)
+
+// templateNamePattern matches a field, method or function name inside an action. Names after
+// $ are template variables and names after a word character are part of a number.
+var templateNamePattern = regexp.MustCompile(`(?:^|[^\w$])([A-Za-z_]\w*)`)
+
+// templateStringPattern matches the string and character literals of an action
+var templateStringPattern = regexp.MustCompile("\"(?:[^\"\\\\]|\\\\.)*\"|`[^`]*`|'(?:[^'\\\\]|\\\\.)*'")
+
+// templateKeywords are the keywords and predefined functions of the template language
+var templateKeywords = map[string]bool{
+	"if": true, "else": true, "range": true, "with": true, "end": true, "define": true,
+	"template": true, "block": true, "break": true, "continue": true, "nil": true,
+	"true": true, "false": true, "and": true, "or": true, "not": true, "len": true,
+	"index": true, "slice": true, "print": true, "printf": true, "println": true,
+	"html": true, "js": true, "urlquery": true, "call": true, "eq": true, "ne": true,
+	"lt": true, "le": true, "gt": true, "ge": true,
+}
+
+// templateParseFuncs are the template functions whose string arguments name template files
+var templateParseFuncs = map[string]bool{"ParseFiles": true, "ParseGlob": true, "ParseFS": true}
+
+// tagNamePattern matches a word in a struct tag value
+var tagNamePattern = regexp.MustCompile(`[A-Za-z_]\w*`)
+
+// encodingTagKeys are struct tag keys whose values name encoded fields or columns rather than
+// functions, so their words are not references
+var encodingTagKeys = map[string]bool{
+	"json": true, "yaml": true, "xml": true, "toml": true, "db": true, "bson": true,
+	"protobuf": true, "msgpack": true, "mapstructure": true, "gorm": true, "sql": true,
+}
+
+// isTemplateFile reports whether path is a Go template by its extension
+func isTemplateFile(path string) bool {
+	for _, ext := range templateExtensions {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeTemplate records the names a template file uses. Templates are executed from any
+// package of their module, so the names are kept per module.
+func (a *CrossFileAnalyzer) analyzeTemplate(filePath string) error {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stamps[filePath] = stampOf(info)
+	moduleDir := a.moduleDir(filePath)
+	for _, name := range templateNames(string(src)) {
+		a.templates.add(templateKey(moduleDir, name), filePath)
+	}
+	a.markModuleStale(moduleDir)
+	return nil
+}
+
+// templateNames returns the field, method and function names used by the actions of a
+// template, without the keywords of the template language
+func templateNames(src string) []string {
+	var names []string
+	for _, action := range templateActionPattern.FindAllStringSubmatch(src, -1) {
+		body := templateStringPattern.ReplaceAllString(action[1], " ")
+		for _, match := range templateNamePattern.FindAllStringSubmatch(body, -1) {
+			if !templateKeywords[match[1]] {
+				names = append(names, match[1])
+			}
+		}
+	}
+	return names
+}
+
+// templateKey identifies a name used from the templates of the module in moduleDir
+func templateKey(moduleDir, name string) string {
+	return moduleDir + "{{" + name
+}
+
+// moduleDir returns the directory of the module holding filePath, or "" outside any module
+func (a *CrossFileAnalyzer) moduleDir(filePath string) string {
+	if module := a.moduleOf(filePath); module != nil {
+		return module.Dir
+	}
+	return ""
+}
+
+// isUsedFromTemplate reports whether a template of the function's module names it. Templates
+// can only call exported names, so unexported functions are never matched.
+func (a *CrossFileAnalyzer) isUsedFromTemplate(funcInfo *FunctionInfo) bool {
+	return funcInfo.Exported && a.templates.has(templateKey(a.moduleDir(funcInfo.File), funcInfo.Name))
+}
+
+// markModuleStale marks the packages of the module in moduleDir for the next
+// FindUnusedFunctions
+func (a *CrossFileAnalyzer) markModuleStale(moduleDir string) {
+	for file, pkg := range a.packages {
+		if a.moduleDir(file) == moduleDir {
+			a.stale[pkg] = true
+		}
+	}
+}
+
+// collectAssetReferences records names used from outside Go code that the file points at:
+// templates embedded with //go:embed or loaded by ParseFiles, ParseGlob or ParseFS with
+// literal patterns, and words in struct tags, which reflection-driven libraries such as
+// validators and CLI parsers resolve to functions and methods by name
+func (a *CrossFileAnalyzer) collectAssetReferences(f *ast.File, filePath string) {
+	var patterns []string
+	for _, group := range f.Comments {
+		for _, comment := range group.List {
+			fields := strings.Fields(comment.Text)
+			if len(fields) > 1 && fields[0] == "//go:embed" {
+				patterns = append(patterns, fields[1:]...)
+			}
+		}
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			patterns = append(patterns, templateFileArgs(node)...)
+		case *ast.Field:
+			if node.Tag != nil {
+				a.collectTagReferences(node.Tag, filePath)
+			}
+		}
+		return true
+	})
+
+	moduleDir := a.moduleDir(filePath)
+	for _, asset := range a.resolveAssets(filePath, moduleDir, patterns) {
+		src, err := os.ReadFile(asset)
+		if err != nil {
+			continue
+		}
+		for _, name := range templateNames(string(src)) {
+			a.templates.add(templateKey(moduleDir, name), filePath)
+		}
+	}
+}
+
+// templateFileArgs returns the string literal arguments of a ParseFiles, ParseGlob or ParseFS
+// call
+func templateFileArgs(call *ast.CallExpr) []string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !templateParseFuncs[sel.Sel.Name] {
+		return nil
+	}
+	var patterns []string
+	for _, arg := range call.Args {
+		if lit, ok := arg.(*ast.BasicLit); ok {
+			if value, err := strconv.Unquote(lit.Value); err == nil {
+				patterns = append(patterns, value)
+			}
+		}
+	}
+	return patterns
+}
+
+// collectTagReferences records every word of a struct tag's values as a reference in the
+// file's package, except for the values of encodingTagKeys
+func (a *CrossFileAnalyzer) collectTagReferences(tag *ast.BasicLit, filePath string) {
+	value, err := strconv.Unquote(tag.Value)
+	if err != nil {
+		return
+	}
+	for _, key := range tagKeys(value) {
+		if encodingTagKeys[key] {
+			continue
+		}
+		for _, name := range tagNamePattern.FindAllString(reflect.StructTag(value).Get(key), -1) {
+			a.funcReferences.add(a.scopeKey(filePath, name), filePath)
+		}
+	}
+}
+
+// tagKeys returns the keys of a conventional struct tag such as `json:"id" validate:"slug"`
+func tagKeys(tag string) []string {
+	var keys []string
+	for _, field := range strings.Fields(tag) {
+		if key, _, ok := strings.Cut(field, ":"); ok && key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// resolveAssets returns the files matched by embed or template patterns. Embed patterns are
+// relative to the file's directory; ParseFiles and ParseGlob paths are relative to the
+// working directory, usually the module root, so both are tried. Directories match every
+// file beneath them.
+func (a *CrossFileAnalyzer) resolveAssets(filePath, moduleDir string, patterns []string) []string {
+	bases := []string{filepath.Dir(filePath)}
+	if moduleDir != "" && moduleDir != bases[0] {
+		bases = append(bases, moduleDir)
+	}
+
+	var files []string
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(strings.Trim(pattern, "\"`"), "all:")
+		for _, base := range bases {
+			matches, _ := filepath.Glob(filepath.Join(base, filepath.FromSlash(pattern)))
+			for _, match := range matches {
+				files = append(files, filesUnder(match)...)
+			}
+		}
+	}
+	return files
+}
+
+// filesUnder returns path itself when it is a file, or the files beneath it when it is a
+// directory
+func filesUnder(path string) []string {
+	var files []string
+	filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			files = append(files, file)
+		}
+		return nil
+	})
+	return files
+}
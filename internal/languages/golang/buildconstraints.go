@@ -0,0 +1,169 @@
+package golang
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"path/filepath"
+	"strings"
+)
+
+// platformSet is a bitset over build configs: bits 2i and 2i+1 are targetPlatforms[i]
+// with custom build tags enabled and disabled respectively
+type platformSet uint64
+
+type platform struct {
+	goos   string
+	goarch string
+}
+
+// targetPlatforms are the GOOS/GOARCH pairs used to decide which files can be built together.
+// Each platform is evaluated twice: once with all custom build tags enabled and once disabled.
+var targetPlatforms = []platform{
+	{"linux", "amd64"},
+	{"linux", "arm64"},
+	{"linux", "386"},
+	{"linux", "arm"},
+	{"darwin", "amd64"},
+	{"darwin", "arm64"},
+	{"windows", "amd64"},
+	{"windows", "arm64"},
+	{"windows", "386"},
+	{"freebsd", "amd64"},
+	{"openbsd", "amd64"},
+	{"netbsd", "amd64"},
+	{"dragonfly", "amd64"},
+	{"solaris", "amd64"},
+	{"illumos", "amd64"},
+	{"aix", "ppc64"},
+	{"android", "arm64"},
+	{"ios", "arm64"},
+	{"plan9", "amd64"},
+	{"js", "wasm"},
+	{"wasip1", "wasm"},
+}
+
+// allPlatforms is the set of every build config in targetPlatforms
+var allPlatforms = platformSet(1)<<(2*len(targetPlatforms)) - 1
+
+var knownOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+	"hurd": true, "illumos": true, "ios": true, "js": true, "linux": true, "nacl": true,
+	"netbsd": true, "openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+	"windows": true, "zos": true,
+}
+
+var knownArch = map[string]bool{
+	"386": true, "amd64": true, "arm": true, "arm64": true, "loong64": true, "mips": true,
+	"mipsle": true, "mips64": true, "mips64le": true, "ppc64": true, "ppc64le": true,
+	"riscv64": true, "s390x": true, "wasm": true,
+}
+
+var unixOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+	"hurd": true, "illumos": true, "ios": true, "linux": true, "netbsd": true,
+	"openbsd": true, "solaris": true,
+}
+
+// filePlatforms returns the set of build configs in which a file is compiled, combining its
+// //go:build (or legacy // +build) constraint with any _GOOS/_GOARCH filename suffix
+func filePlatforms(f *ast.File, filePath string) platformSet {
+	expr := fileConstraint(f)
+	goos, goarch := filenamePlatform(filePath)
+
+	var set platformSet
+	for i, p := range targetPlatforms {
+		if goos != "" && !matchesOS(p, goos) {
+			continue
+		}
+		if goarch != "" && p.goarch != goarch {
+			continue
+		}
+		for variant, customTags := range []bool{true, false} {
+			if expr == nil || expr.Eval(func(tag string) bool { return evalTag(p, tag, customTags) }) {
+				set |= 1 << (2*i + variant)
+			}
+		}
+	}
+	return set
+}
+
+// fileConstraint parses the build constraint in the file header, if any
+func fileConstraint(f *ast.File) constraint.Expr {
+	var plusBuild constraint.Expr
+	for _, group := range f.Comments {
+		if group.Pos() >= f.Package {
+			break
+		}
+		for _, c := range group.List {
+			if constraint.IsGoBuild(c.Text) {
+				if expr, err := constraint.Parse(c.Text); err == nil {
+					return expr
+				}
+			}
+			if constraint.IsPlusBuild(c.Text) {
+				expr, err := constraint.Parse(c.Text)
+				if err != nil {
+					continue
+				}
+				if plusBuild == nil {
+					plusBuild = expr
+				} else {
+					plusBuild = &constraint.AndExpr{X: plusBuild, Y: expr}
+				}
+			}
+		}
+	}
+	return plusBuild
+}
+
+// filenamePlatform extracts the implicit GOOS/GOARCH constraint from a file name,
+// following the same rules as the go tool (e.g. foo_windows.go, foo_linux_arm64.go)
+func filenamePlatform(filePath string) (goos, goarch string) {
+	name := strings.TrimSuffix(filepath.Base(filePath), ".go")
+	name = strings.TrimSuffix(name, "_test")
+
+	parts := strings.Split(name, "_")
+	if len(parts) < 2 {
+		return "", ""
+	}
+
+	last := parts[len(parts)-1]
+	if len(parts) >= 3 && knownOS[parts[len(parts)-2]] && knownArch[last] {
+		return parts[len(parts)-2], last
+	}
+	if knownOS[last] {
+		return last, ""
+	}
+	if knownArch[last] {
+		return "", last
+	}
+	return "", ""
+}
+
+// matchesOS reports whether a platform satisfies a GOOS, including the implied
+// android => linux and ios => darwin relationships
+func matchesOS(p platform, goos string) bool {
+	return p.goos == goos ||
+		(goos == "linux" && p.goos == "android") ||
+		(goos == "darwin" && p.goos == "ios")
+}
+
+// evalTag decides whether a build tag is satisfied for a platform. Tags the analyzer
+// does not know about are treated as custom tags and follow customTags.
+func evalTag(p platform, tag string, customTags bool) bool {
+	switch {
+	case tag == "ignore":
+		return false
+	case knownOS[tag]:
+		return matchesOS(p, tag)
+	case knownArch[tag]:
+		return p.goarch == tag
+	case tag == "unix":
+		return unixOS[p.goos]
+	case tag == "gc" || tag == "cgo" || strings.HasPrefix(tag, "go1."):
+		return true
+	case tag == "gccgo":
+		return false
+	}
+	return customTags
+}
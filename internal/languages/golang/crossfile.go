@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"go/ast"
-	"go/parser"
 	"go/token"
 	"os"
 	"path/filepath"
@@ -14,15 +13,90 @@ import (
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
 )
 
+func init() {
+	options := append(core.UnusedFunctionOptions(),
+		core.RuleOption{Key: "rules.orphanedCode.ignoreFunctionPatterns", Flag: "-ignore-functions", Default: "Test*,Benchmark*,Example*", Description: "Globs or /regexp/ of function names never reported"},
+		core.RuleOption{Key: "rules.orphanedCode.ignoreReceivers", Flag: "-ignore-receivers", Default: "none", Description: "Globs or /regexp/ of receiver types whose methods are never reported"},
+		core.RuleOption{Key: "rules.orphanedCode.minStatements", Flag: "-unused-min-statements", Default: "0", Description: "Statements a function needs to be reported, 0 reports any size"},
+		core.RuleOption{Key: "rules.orphanedCode.indexFile", Flag: "-crossfile-index", Default: "none", Description: "File keeping the project-wide index between runs"},
+	)
+	core.RegisterRuleDoc(core.RuleDoc{
+		ID:          "cross-file-unused-function",
+		Name:        "Cross-File Unused Function",
+		Description: "Detects unexported Go functions nothing in their package calls or references",
+		Rationale: "Functions nothing calls are left behind when code is rewritten rather than edited. " +
+			"The project-wide pass builds a call graph over every non-test file of a package, so a call " +
+			"from any file counts, as do functions passed as values, listed in tables, called from " +
+			"assembly or named by //export and //go:linkname.",
+		Category:  core.CategoryOrphaned,
+		Severity:  core.SeverityWarning,
+		Languages: []string{"go"},
+		Examples: []core.RuleExample{{
+			Bad: `// a.go
+func parseLegacy(s string) Record { ... } // nothing in the package calls it`,
+			Good: `// b.go
+func load(s string) Record { return parseLegacy(s) }`,
+		}},
+		Options: options,
+	})
+	core.RegisterRuleDoc(core.RuleDoc{
+		ID:          "cross-file-unused-method",
+		Name:        "Cross-File Unused Method",
+		Description: "Detects unexported Go methods nothing in their package calls",
+		Rationale: "Methods outlive the code that called them as easily as functions do. Exported " +
+			"methods are never reported, as they often satisfy interfaces implicitly.",
+		Category:  core.CategoryOrphaned,
+		Severity:  core.SeverityWarning,
+		Languages: []string{"go"},
+		Examples: []core.RuleExample{{
+			Bad:  `func (c *Cache) evictAll() { c.items = nil } // never called`,
+			Good: `func (c *Cache) Reset() { c.evictAll() }`,
+		}},
+		Options: options,
+	})
+	core.RegisterRuleDoc(core.RuleDoc{
+		ID:          "cross-file-unused-type",
+		Name:        "Cross-File Unused Type",
+		Description: "Detects exported Go types nothing references in a module that builds a binary",
+		Rationale: "Nothing outside an application module can use its exported types, so one nothing " +
+			"inside refers to is dead. Reported only with includeExported, and only in modules with a " +
+			"main package that no other analyzed module imports, so a library's API is never reported.",
+		Category:  core.CategoryOrphaned,
+		Severity:  core.SeverityWarning,
+		Languages: []string{"go"},
+		Examples: []core.RuleExample{{
+			Bad:  `type LegacyConfig struct{ Path string } // in package main, never used`,
+			Good: `type Config struct{ Path string } // used by main`,
+		}},
+		Options: []core.RuleOption{
+			{Key: "rules.orphanedCode.includeExported", Flag: "-include-exported", Default: "false", Description: "Report exported functions and types in modules nothing imports"},
+		},
+	})
+}
+
 type CrossFileAnalyzer struct {
 	fset            *token.FileSet
 	functions       map[string]map[string]*FunctionInfo
 	methods         map[string]map[string]*FunctionInfo // receiver type -> method name -> info
 	calls           map[string][]string
-	methodCalls     map[string][]string // tracks method calls separately
-	funcReferences  map[string]bool     // tracks functions used as references (callbacks, etc.)
+	methodCalls     map[string][]string      // tracks method calls separately
+	funcReferences  usageSet                 // tracks functions used as references (callbacks, etc.)
+	assembly        usageSet                 // functions named in assembly files, see assemblyKey
+	templates       usageSet                 // names used from template files, see templateKey
+	platforms       map[string]platformSet   // file path -> build configs the file is compiled in
+	packages        map[string]string        // file path -> package key, see packageKey
+	stamps          map[string]fileStamp     // file path -> state of the file when it was analyzed
+	modules         []Module                 // Go modules under the analyzed directory
+	exported        exportedUsage            // uses of exported names, see SetIncludeExported
+	unused          map[string][]core.Result // package key -> findings, nil until first computed
+	stale           map[string]bool          // package keys whose findings must be recomputed
+	binaries        map[string]bool          // module dirs holding a main function, see binaryModules
+	includeExported bool
+	minStatements   int       // functions with fewer statements are never reported, see SetMinStatements
+	cache           *ASTCache // shared parse cache, see SetCache
 	mu              sync.RWMutex
-	ignoredPrefixes []string
+	ignoredFuncs    []namePattern // functions never reported, see SetIgnorePatterns
+	ignoredRecvs    []namePattern // receiver types whose methods are never reported
 }
 
 type FunctionInfo struct {
@@ -34,23 +108,56 @@ type FunctionInfo struct {
 	IsInit     bool
 	IsMethod   bool
 	Receiver   string // receiver type name for methods
+	External   bool   // declared without a body: implemented in assembly or linked in by name
+	Statements int    // statements in the body, see countStatements
 	Line       int
 	Package    string
+	Module     *Module // module declaring the function, nil outside any module
 }
 
 func NewCrossFileAnalyzer() *CrossFileAnalyzer {
 	return &CrossFileAnalyzer{
-		fset:            token.NewFileSet(),
-		functions:       make(map[string]map[string]*FunctionInfo),
-		methods:         make(map[string]map[string]*FunctionInfo),
-		calls:           make(map[string][]string),
-		methodCalls:     make(map[string][]string),
-		funcReferences:  make(map[string]bool),
-		ignoredPrefixes: []string{"Benchmark", "Example", "Test"},
+		fset:           token.NewFileSet(),
+		functions:      make(map[string]map[string]*FunctionInfo),
+		methods:        make(map[string]map[string]*FunctionInfo),
+		calls:          make(map[string][]string),
+		methodCalls:    make(map[string][]string),
+		funcReferences: newUsageSet(),
+		assembly:       newUsageSet(),
+		templates:      newUsageSet(),
+		platforms:      make(map[string]platformSet),
+		packages:       make(map[string]string),
+		stamps:         make(map[string]fileStamp),
+		stale:          make(map[string]bool),
+		exported:       newExportedUsage(),
+		ignoredFuncs:   defaultIgnoredFuncs,
 	}
 }
 
+// SetCache makes the analyzer take ASTs from a cache shared with the per-file Analyzer, so
+// files it has already parsed are not parsed again. Call it before AnalyzeDirectory.
+func (a *CrossFileAnalyzer) SetCache(cache *ASTCache) {
+	a.cache = cache
+	a.fset = cache.FileSet()
+}
+
+// AnalyzeDirectory collects the declarations and calls of every Go file under dirPath. When the
+// directory holds several packages or modules, names only resolve within their own package.
 func (a *CrossFileAnalyzer) AnalyzeDirectory(ctx context.Context, dirPath string) error {
+	modules, err := DiscoverModules(dirPath)
+	if err != nil {
+		return err
+	}
+	a.modules = modules
+
+	return walkSourceFiles(dirPath, func(path string, info os.FileInfo) error {
+		return a.analyzeSource(path)
+	})
+}
+
+// walkSourceFiles calls fn for every non-test Go, assembly and template file under dirPath, skipping
+// VCS, editor and vendored directories
+func walkSourceFiles(dirPath string, fn func(path string, info os.FileInfo) error) error {
 	return filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -63,14 +170,9 @@ func (a *CrossFileAnalyzer) AnalyzeDirectory(ctx context.Context, dirPath string
 			return nil
 		}
 
-		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
-			return nil
+		if (strings.HasSuffix(path, ".go") && !strings.HasSuffix(path, "_test.go")) || isAssemblyFile(path) || isTemplateFile(path) {
+			return fn(path, info)
 		}
-
-		if err := a.analyzeFile(path); err != nil {
-			return err
-		}
-
 		return nil
 	})
 }
@@ -86,24 +188,38 @@ func shouldSkipDir(name string) bool {
 }
 
 func (a *CrossFileAnalyzer) analyzeFile(filePath string) error {
-	src, err := os.ReadFile(filePath)
+	info, err := os.Stat(filePath)
 	if err != nil {
 		return err
 	}
-
-	f, err := parser.ParseFile(a.fset, filePath, src, parser.ParseComments)
+	f, err := parseWithCache(a.cache, a.fset, filePath)
 	if err != nil {
 		return err
 	}
 
 	a.mu.Lock()
 	defer a.mu.Unlock()
+	a.stamps[filePath] = stampOf(info)
 
+	// Files excluded from every build (e.g. //go:build ignore) are not part of the program
+	platforms := filePlatforms(f, filePath)
+	if platforms == 0 {
+		return nil
+	}
+
+	a.platforms[filePath] = platforms
 	a.functions[filePath] = make(map[string]*FunctionInfo)
 	pkgName := a.getPackageName(f)
+	a.packages[filePath] = packageKey(filePath, pkgName)
+	a.stale[a.packages[filePath]] = true
 
 	a.collectDeclarations(f, filePath, pkgName)
 	a.collectCalls(f, filePath)
+	a.collectLinkerDirectives(f, filePath)
+	a.collectAssetReferences(f, filePath)
+	if a.includeExported {
+		a.collectExportedUsage(f, filePath)
+	}
 
 	return nil
 }
@@ -132,23 +248,27 @@ func (a *CrossFileAnalyzer) registerFunction(node *ast.FuncDecl, filePath, pkgNa
 	isMethod := receiverType != ""
 
 	funcInfo := &FunctionInfo{
-		Name:     node.Name.Name,
-		File:     filePath,
-		Exported: node.Name.IsExported(),
-		IsMain:   node.Name.Name == "main",
-		IsTest:   strings.HasPrefix(node.Name.Name, "Test") || strings.HasSuffix(node.Name.Name, "Test"),
-		IsInit:   node.Name.Name == "init",
-		IsMethod: isMethod,
-		Receiver: receiverType,
-		Line:     a.fset.Position(node.Pos()).Line,
-		Package:  pkgName,
+		Name:       node.Name.Name,
+		File:       filePath,
+		Exported:   node.Name.IsExported(),
+		IsMain:     node.Name.Name == "main" && pkgName == "main" && !isMethod,
+		IsTest:     strings.HasPrefix(node.Name.Name, "Test") || strings.HasSuffix(node.Name.Name, "Test"),
+		IsInit:     node.Name.Name == "init",
+		IsMethod:   isMethod,
+		Receiver:   receiverType,
+		External:   node.Body == nil,
+		Statements: countStatements(node),
+		Line:       a.fset.Position(node.Pos()).Line,
+		Package:    pkgName,
+		Module:     a.moduleOf(filePath),
 	}
 
 	if isMethod {
-		if a.methods[receiverType] == nil {
-			a.methods[receiverType] = make(map[string]*FunctionInfo)
+		key := a.scopeKey(filePath, receiverType)
+		if a.methods[key] == nil {
+			a.methods[key] = make(map[string]*FunctionInfo)
 		}
-		a.methods[receiverType][node.Name.Name] = funcInfo
+		a.methods[key][node.Name.Name] = funcInfo
 	} else {
 		a.functions[filePath][node.Name.Name] = funcInfo
 	}
@@ -157,11 +277,54 @@ func (a *CrossFileAnalyzer) registerFunction(node *ast.FuncDecl, filePath, pkgNa
 // collectCalls collects all function calls from a file
 func (a *CrossFileAnalyzer) collectCalls(f *ast.File, filePath string) {
 	ast.Inspect(f, func(n ast.Node) bool {
-		if node, ok := n.(*ast.FuncDecl); ok {
+		// Declarations without a body have nothing to call
+		if node, ok := n.(*ast.FuncDecl); ok && node.Body != nil {
 			a.collectCallsFromNode(filePath, node.Name.Name, node.Body)
 		}
 		return true
 	})
+
+	for _, decl := range f.Decls {
+		if decl, ok := decl.(*ast.GenDecl); ok && decl.Tok == token.VAR {
+			a.collectInitializerUses(filePath, decl)
+		}
+	}
+}
+
+// collectInitializerUses records the functions named by package-level variable initializers,
+// such as var handlers = map[string]http.HandlerFunc{"/": serveRoot} or var cfg = load().
+// Initializers always run, so everything they call or refer to is used.
+func (a *CrossFileAnalyzer) collectInitializerUses(filePath string, decl *ast.GenDecl) {
+	for _, spec := range decl.Specs {
+		for _, value := range spec.(*ast.ValueSpec).Values {
+			ast.Inspect(value, func(n ast.Node) bool {
+				switch expr := n.(type) {
+				case *ast.Ident:
+					if expr.Obj == nil || expr.Obj.Kind == ast.Fun {
+						a.funcReferences.add(a.scopeKey(filePath, expr.Name), filePath)
+					}
+				case *ast.SelectorExpr:
+					a.funcReferences.add(a.scopeKey(filePath, expr.Sel.Name), filePath)
+				}
+				return true
+			})
+		}
+	}
+}
+
+// recordLiteralReferences records functions listed in a composite literal: registration
+// tables such as template.FuncMap{"upper": upper}, handler maps and test tables. These name
+// functions declared in other files, which the parser leaves unresolved (Obj is nil), so
+// any element that is not a local variable, constant or type counts.
+func (a *CrossFileAnalyzer) recordLiteralReferences(filePath string, lit *ast.CompositeLit) {
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			elt = kv.Value
+		}
+		if ident, ok := elt.(*ast.Ident); ok && (ident.Obj == nil || ident.Obj.Kind == ast.Fun) {
+			a.funcReferences.add(a.scopeKey(filePath, ident.Name), filePath)
+		}
+	}
 }
 
 // getReceiverTypeName extracts the receiver type name from a function declaration
@@ -170,14 +333,21 @@ func getReceiverTypeName(funcDecl *ast.FuncDecl) string {
 		return ""
 	}
 
-	recv := funcDecl.Recv.List[0]
-	switch t := recv.Type.(type) {
+	return baseTypeName(funcDecl.Recv.List[0].Type)
+}
+
+// baseTypeName returns the name of a named type, looking through pointers and
+// generic instantiations (e.g. *Stack[T] and Pair[K, V] yield Stack and Pair)
+func baseTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
 	case *ast.Ident:
 		return t.Name
 	case *ast.StarExpr:
-		if ident, ok := t.X.(*ast.Ident); ok {
-			return ident.Name
-		}
+		return baseTypeName(t.X)
+	case *ast.IndexExpr:
+		return baseTypeName(t.X)
+	case *ast.IndexListExpr:
+		return baseTypeName(t.X)
 	}
 	return ""
 }
@@ -193,18 +363,21 @@ func (a *CrossFileAnalyzer) collectCallsFromNode(filePath, callerName string, no
 		case *ast.CallExpr:
 			a.recordCallExpr(filePath, callerName, expr)
 
+		case *ast.CompositeLit:
+			a.recordLiteralReferences(filePath, expr)
+
 		case *ast.Ident:
 			// Check if this identifier is a function reference (not a call)
 			// This catches cases like: handler := myFunction
 			if expr.Obj != nil && expr.Obj.Kind == ast.Fun {
-				a.funcReferences[expr.Name] = true
+				a.funcReferences.add(a.scopeKey(filePath, expr.Name), filePath)
 			}
 
 		case *ast.SelectorExpr:
 			// Check for function references via selector (e.g., pkg.Function used as value)
 			// We'll be conservative and just record the method name
 			// expr.Sel is already *ast.Ident
-			a.funcReferences[expr.Sel.Name] = true
+			a.funcReferences.add(a.scopeKey(filePath, expr.Sel.Name), filePath)
 		}
 		return true
 	})
@@ -212,7 +385,20 @@ func (a *CrossFileAnalyzer) collectCallsFromNode(filePath, callerName string, no
 
 // recordCallExpr handles recording of a call expression
 func (a *CrossFileAnalyzer) recordCallExpr(filePath, callerName string, call *ast.CallExpr) {
-	switch fun := call.Fun.(type) {
+	a.recordCallee(filePath, callerName, call.Fun)
+
+	// Also check arguments for function references
+	for _, arg := range call.Args {
+		if ident, ok := arg.(*ast.Ident); ok {
+			// Function passed as argument
+			a.funcReferences.add(a.scopeKey(filePath, ident.Name), filePath)
+		}
+	}
+}
+
+// recordCallee records the function or method named by the callee expression of a call
+func (a *CrossFileAnalyzer) recordCallee(filePath, callerName string, fun ast.Expr) {
+	switch fun := fun.(type) {
 	case *ast.Ident:
 		// Direct function call: functionName()
 		a.recordCall(filePath, callerName, fun.Name)
@@ -225,18 +411,43 @@ func (a *CrossFileAnalyzer) recordCallExpr(filePath, callerName string, call *as
 		// Also record as a regular call in case it's a package-level function
 		a.recordCall(filePath, callerName, methodName)
 
+	case *ast.IndexExpr:
+		// Explicitly instantiated generic call: Map[int](...) or pkg.Map[int](...)
+		a.recordCallee(filePath, callerName, fun.X)
+
+	case *ast.IndexListExpr:
+		// Generic call with several type arguments: Map[int, string](...)
+		a.recordCallee(filePath, callerName, fun.X)
+
 	case *ast.FuncLit:
 		// Anonymous function - traverse its body too
 		a.collectCallsFromNode(filePath, callerName, fun.Body)
 	}
+}
 
-	// Also check arguments for function references
-	for _, arg := range call.Args {
-		if ident, ok := arg.(*ast.Ident); ok {
-			// Function passed as argument
-			a.funcReferences[ident.Name] = true
-		}
-	}
+// moduleOf returns the module containing filePath, or nil if it is in none
+func (a *CrossFileAnalyzer) moduleOf(filePath string) *Module {
+	return FindModule(a.modules, filePath)
+}
+
+// packageKey identifies the package a file belongs to by its directory and package name.
+// Directories are unique across modules, so the key also separates modules.
+func packageKey(filePath, pkgName string) string {
+	return filepath.Dir(filePath) + ":" + pkgName
+}
+
+// scopeKey qualifies a name with the package of filePath. Unexported functions, methods and
+// types can only be used inside their own package, so names in different packages never
+// resolve to each other.
+func (a *CrossFileAnalyzer) scopeKey(filePath, name string) string {
+	return a.packages[filePath] + "." + name
+}
+
+// isReferenced reports whether a function is used as a value in its own package or from
+// outside Go code
+func (a *CrossFileAnalyzer) isReferenced(funcInfo *FunctionInfo) bool {
+	return a.funcReferences.has(a.scopeKey(funcInfo.File, funcInfo.Name)) || a.isUsedFromAssembly(funcInfo) ||
+		a.isUsedFromTemplate(funcInfo)
 }
 
 func (a *CrossFileAnalyzer) recordCall(filePath, caller, callee string) {
@@ -249,19 +460,53 @@ func (a *CrossFileAnalyzer) recordMethodCall(filePath, caller, methodName string
 	a.methodCalls[key] = append(a.methodCalls[key], methodName)
 }
 
+// FindUnusedFunctions reports the functions, methods and (with SetIncludeExported) types that
+// nothing in the project uses. Findings are kept per package, so after Update only the
+// packages with changed files are checked again.
 func (a *CrossFileAnalyzer) FindUnusedFunctions() []core.Result {
-	a.mu.RLock()
-	defer a.mu.RUnlock()
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
-	results := a.findUnusedRegularFunctions()
-	results = append(results, a.findUnusedMethods()...)
+	// Exported names are used across packages, so any change can affect every package, and
+	// a module gaining or losing its main package changes how all of its code is judged
+	binaries := a.binaryModules()
+	if a.unused == nil || (a.includeExported && len(a.stale) > 0) || !sameSet(binaries, a.binaries) {
+		a.unused = make(map[string][]core.Result)
+		a.stale = nil
+	}
+	a.binaries = binaries
+	for pkg := range a.stale {
+		delete(a.unused, pkg)
+	}
+	found := a.findUnusedRegularFunctions(a.stale)
+	found = append(found, a.findUnusedMethods(a.stale)...)
+	for _, result := range found {
+		pkg := a.packages[result.FilePath]
+		a.unused[pkg] = append(a.unused[pkg], result)
+	}
+	a.stale = make(map[string]bool)
+
+	var results []core.Result
+	for _, pkgResults := range a.unused {
+		results = append(results, pkgResults...)
+	}
+	results = append(results, a.findUnusedTypes()...)
 	return results
 }
 
-// findUnusedRegularFunctions finds unused regular (non-method) functions
-func (a *CrossFileAnalyzer) findUnusedRegularFunctions() []core.Result {
+// isStale reports whether the findings for the package of filePath are to be recomputed; a
+// nil set of stale packages means all of them are
+func (a *CrossFileAnalyzer) isStale(stale map[string]bool, filePath string) bool {
+	return stale == nil || stale[a.packages[filePath]]
+}
+
+// findUnusedRegularFunctions finds unused regular (non-method) functions in the stale packages
+func (a *CrossFileAnalyzer) findUnusedRegularFunctions(stale map[string]bool) []core.Result {
 	var results []core.Result
 	for filePath, funcs := range a.functions {
+		if !a.isStale(stale, filePath) {
+			continue
+		}
 		for name, funcInfo := range funcs {
 			if a.isIgnoredFunction(funcInfo) || a.isCalled(funcInfo) {
 				continue
@@ -272,12 +517,12 @@ func (a *CrossFileAnalyzer) findUnusedRegularFunctions() []core.Result {
 	return results
 }
 
-// findUnusedMethods finds unused methods
-func (a *CrossFileAnalyzer) findUnusedMethods() []core.Result {
+// findUnusedMethods finds unused methods in the stale packages
+func (a *CrossFileAnalyzer) findUnusedMethods(stale map[string]bool) []core.Result {
 	var results []core.Result
 	for _, methods := range a.methods {
 		for name, funcInfo := range methods {
-			if a.isIgnoredFunction(funcInfo) || a.isMethodCalled(funcInfo) {
+			if !a.isStale(stale, funcInfo.File) || a.isIgnoredFunction(funcInfo) || a.isMethodCalled(funcInfo) {
 				continue
 			}
 			results = append(results, a.buildUnusedMethodResult(name, funcInfo))
@@ -291,12 +536,13 @@ func (a *CrossFileAnalyzer) buildUnusedFunctionResult(filePath, name string, fun
 	return core.Result{
 		RuleID:     "cross-file-unused-function",
 		RuleName:   "Cross-File Unused Function",
-		Category:   "orphaned",
+		Category:   string(core.CategoryOrphaned),
 		Severity:   "warning",
+		Confidence: string(core.ConfidenceMedium),
 		FilePath:   filePath,
 		Line:       funcInfo.Line,
 		Message:    fmt.Sprintf("Function '%s' is not called anywhere in the project", name),
-		Suggestion: "Review if this function is needed or if it should be exported/called",
+		Suggestion: a.unusedFunctionSuggestion(funcInfo),
 	}
 }
 
@@ -305,8 +551,9 @@ func (a *CrossFileAnalyzer) buildUnusedMethodResult(name string, funcInfo *Funct
 	return core.Result{
 		RuleID:     "cross-file-unused-method",
 		RuleName:   "Cross-File Unused Method",
-		Category:   "orphaned",
+		Category:   string(core.CategoryOrphaned),
 		Severity:   "warning",
+		Confidence: string(core.ConfidenceMedium),
 		FilePath:   funcInfo.File,
 		Line:       funcInfo.Line,
 		Message:    fmt.Sprintf("Method '%s' on receiver '%s' is not called anywhere in the project", name, funcInfo.Receiver),
@@ -319,19 +566,20 @@ func (a *CrossFileAnalyzer) isIgnoredFunction(funcInfo *FunctionInfo) bool {
 		return true
 	}
 
-	if funcInfo.IsTest {
+	if funcInfo.IsTest || funcInfo.External {
 		return true
 	}
 
-	for _, prefix := range a.ignoredPrefixes {
-		if strings.HasPrefix(funcInfo.Name, prefix) {
-			return true
-		}
+	if matchesAny(a.ignoredFuncs, funcInfo.Name) || funcInfo.Statements < a.minStatements {
+		return true
+	}
+	if funcInfo.IsMethod && matchesAny(a.ignoredRecvs, funcInfo.Receiver) {
+		return true
 	}
 
 	// Exported functions may be called from external packages,
 	// so we can't determine if they're unused from internal analysis alone
-	if funcInfo.Exported {
+	if funcInfo.Exported && !a.reportsExported(funcInfo) {
 		return true
 	}
 
@@ -344,18 +592,20 @@ func (a *CrossFileAnalyzer) isCalled(funcInfo *FunctionInfo) bool {
 	}
 
 	// Check if function is used as a reference (callback, assigned to variable, etc.)
-	if a.funcReferences[funcInfo.Name] {
+	if a.isReferenced(funcInfo) {
+		return true
+	}
+
+	// Exported functions may also be used from other packages of the module
+	if funcInfo.Exported && a.isExportedUsed(funcInfo.File, funcInfo.Name, funcInfo.Module) {
 		return true
 	}
 
 	// Check direct function calls
 	for _, funcs := range a.functions {
 		for _, caller := range funcs {
-			callerKey := caller.File + ":" + caller.Name
-			for _, callee := range a.calls[callerKey] {
-				if callee == funcInfo.Name {
-					return true
-				}
+			if a.callsFrom(caller, a.calls, funcInfo) {
+				return true
 			}
 		}
 	}
@@ -363,11 +613,8 @@ func (a *CrossFileAnalyzer) isCalled(funcInfo *FunctionInfo) bool {
 	// Also check calls from methods
 	for _, methods := range a.methods {
 		for _, caller := range methods {
-			callerKey := caller.File + ":" + caller.Name
-			for _, callee := range a.calls[callerKey] {
-				if callee == funcInfo.Name {
-					return true
-				}
+			if a.callsFrom(caller, a.calls, funcInfo) {
+				return true
 			}
 		}
 	}
@@ -375,6 +622,23 @@ func (a *CrossFileAnalyzer) isCalled(funcInfo *FunctionInfo) bool {
 	return false
 }
 
+// callsFrom reports whether caller invokes target according to the given call index.
+// Callers in other packages, or in files that are never compiled together with the target's
+// file (e.g. a _windows.go caller and a _linux.go target), are ignored.
+func (a *CrossFileAnalyzer) callsFrom(caller *FunctionInfo, calls map[string][]string, target *FunctionInfo) bool {
+	if a.packages[caller.File] != a.packages[target.File] || a.platforms[caller.File]&a.platforms[target.File] == 0 {
+		return false
+	}
+
+	callerKey := caller.File + ":" + caller.Name
+	for _, callee := range calls[callerKey] {
+		if callee == target.Name {
+			return true
+		}
+	}
+	return false
+}
+
 // isMethodCalled checks if a method is called anywhere in the project
 func (a *CrossFileAnalyzer) isMethodCalled(funcInfo *FunctionInfo) bool {
 	if funcInfo.IsMain || funcInfo.IsInit || funcInfo.IsTest {
@@ -382,24 +646,16 @@ func (a *CrossFileAnalyzer) isMethodCalled(funcInfo *FunctionInfo) bool {
 	}
 
 	// Check if method is used as a reference
-	if a.funcReferences[funcInfo.Name] {
+	if a.isReferenced(funcInfo) {
 		return true
 	}
 
 	// Check method calls from functions
 	for _, funcs := range a.functions {
 		for _, caller := range funcs {
-			callerKey := caller.File + ":" + caller.Name
-			for _, callee := range a.methodCalls[callerKey] {
-				if callee == funcInfo.Name {
-					return true
-				}
-			}
 			// Also check regular calls (methods can be called directly in some contexts)
-			for _, callee := range a.calls[callerKey] {
-				if callee == funcInfo.Name {
-					return true
-				}
+			if a.callsFrom(caller, a.methodCalls, funcInfo) || a.callsFrom(caller, a.calls, funcInfo) {
+				return true
 			}
 		}
 	}
@@ -407,16 +663,8 @@ func (a *CrossFileAnalyzer) isMethodCalled(funcInfo *FunctionInfo) bool {
 	// Check method calls from other methods
 	for _, methods := range a.methods {
 		for _, caller := range methods {
-			callerKey := caller.File + ":" + caller.Name
-			for _, callee := range a.methodCalls[callerKey] {
-				if callee == funcInfo.Name {
-					return true
-				}
-			}
-			for _, callee := range a.calls[callerKey] {
-				if callee == funcInfo.Name {
-					return true
-				}
+			if a.callsFrom(caller, a.methodCalls, funcInfo) || a.callsFrom(caller, a.calls, funcInfo) {
+				return true
 			}
 		}
 	}
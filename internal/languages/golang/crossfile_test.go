@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 
@@ -201,6 +202,9 @@ func TestCrossFileAnalyzer_DetectsRealOrphans(t *testing.T) {
 
 	results := analyzeForOrphans(t, tmpDir)
 
+	for _, r := range results {
+		t.Log(r.Message)
+	}
 	verifyOrphanCount(t, results, 2)
 	verifyExpectedOrphans(t, results, []string{
 		"Function 'orphanedFunction' is not called anywhere in the project",
@@ -246,7 +250,7 @@ func verifyExpectedOrphans(t *testing.T, results []core.Result, expectedOrphans
 	t.Helper()
 	orphanNames := make(map[string]bool)
 	for _, r := range results {
-		if r.RuleID == "cross-file-unused-function" {
+		if r.RuleID == "cross-file-unused-function" || r.RuleID == "cross-file-unused-method" {
 			orphanNames[r.Message] = true
 		}
 	}
@@ -486,3 +490,610 @@ func main() {
 		}
 	}
 }
+
+// writeGoFiles writes each named source file into dir
+func writeGoFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+// TestCrossFileAnalyzer_PlatformFilesNotFlagged ensures helpers only used by platform-specific files are kept
+func TestCrossFileAnalyzer_PlatformFilesNotFlagged(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeGoFiles(t, tmpDir, map[string]string{
+		"main.go": `package main
+
+func main() { openHandle() }
+`,
+		"handle_windows.go": `package main
+
+func openHandle() { winHelper() }
+func winHelper()  {}
+`,
+		"handle_linux.go": `package main
+
+func openHandle() {}
+`,
+		"poll.go": `//go:build darwin || freebsd
+
+package main
+
+func pollEvents() { bsdHelper() }
+`,
+		"poll_common.go": `package main
+
+func bsdHelper() {}
+func init()      { pollEvents() }
+`,
+	})
+
+	for _, r := range analyzeForOrphans(t, tmpDir) {
+		t.Errorf("False positive: %s at line %d - %s", r.FilePath, r.Line, r.Message)
+	}
+}
+
+// TestCrossFileAnalyzer_IgnoreBuildTagExcluded ensures files excluded from every build are skipped
+func TestCrossFileAnalyzer_IgnoreBuildTagExcluded(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeGoFiles(t, tmpDir, map[string]string{
+		"gen.go": `//go:build ignore
+
+package main
+
+func main()     { generate(); helper() }
+func generate() {}
+`,
+		"lib.go": `package lib
+
+func helper() {}
+`,
+	})
+
+	results := analyzeForOrphans(t, tmpDir)
+	verifyExpectedOrphans(t, results, []string{"Function 'helper' is not called anywhere in the project"})
+	for _, r := range results {
+		if strings.Contains(r.Message, "generate") {
+			t.Errorf("Function in ignored file should not be analyzed: %s", r.Message)
+		}
+	}
+}
+
+// TestCrossFileAnalyzer_IncompatiblePlatformCallers ensures calls from files never built together are not counted
+func TestCrossFileAnalyzer_IncompatiblePlatformCallers(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeGoFiles(t, tmpDir, map[string]string{
+		"main.go": `package main
+
+func main() { linuxOnly() }
+`,
+		"a_windows.go": `package main
+
+func winOnly() {}
+`,
+		"b_linux.go": `package main
+
+func linuxOnly() { winOnly() }
+`,
+	})
+
+	results := analyzeForOrphans(t, tmpDir)
+	verifyOrphanCount(t, results, 1)
+	verifyExpectedOrphans(t, results, []string{"Function 'winOnly' is not called anywhere in the project"})
+}
+
+func TestFilenamePlatform(t *testing.T) {
+	tests := []struct {
+		file   string
+		goos   string
+		goarch string
+	}{
+		{"file.go", "", ""},
+		{"linux.go", "", ""},
+		{"file_linux.go", "linux", ""},
+		{"file_windows_test.go", "windows", ""},
+		{"file_arm64.go", "", "arm64"},
+		{"file_darwin_amd64.go", "darwin", "amd64"},
+		{"file_helper.go", "", ""},
+	}
+
+	for _, tt := range tests {
+		goos, goarch := filenamePlatform(tt.file)
+		if goos != tt.goos || goarch != tt.goarch {
+			t.Errorf("filenamePlatform(%q) = (%q, %q), want (%q, %q)", tt.file, goos, goarch, tt.goos, tt.goarch)
+		}
+	}
+}
+
+// TestCrossFileAnalyzer_GenericCalls ensures instantiated generic calls and methods on generic types are tracked
+func TestCrossFileAnalyzer_GenericCalls(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeGoFiles(t, tmpDir, map[string]string{
+		"main.go": `package main
+
+func main() {
+	s := &stack[int]{}
+	s.push(1)
+	_ = mapSlice[int, string](nil, nil)
+	_ = first[int](nil)
+}
+`,
+		"generic.go": `package main
+
+type stack[T any] struct{ items []T }
+
+func (s *stack[T]) push(v T) { s.items = append(s.items, v) }
+func (s *stack[T]) pop() T   { return s.items[0] }
+
+func mapSlice[T, U any](in []T, f func(T) U) []U { return nil }
+func first[T any](in []T) T                      { var zero T; return zero }
+func unusedGeneric[T comparable](a, b T) bool    { return a == b }
+`,
+	})
+
+	results := analyzeForOrphans(t, tmpDir)
+	for _, r := range results {
+		t.Log(r.Message)
+	}
+	verifyOrphanCount(t, results, 2)
+	verifyExpectedOrphans(t, results, []string{
+		"Function 'unusedGeneric' is not called anywhere in the project",
+		"Method 'pop' on receiver 'stack' is not called anywhere in the project",
+	})
+}
+
+func TestCrossFileAnalyzer_ScopesModules(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"svc-a/go.mod":  "module example.com/svc-a\n\ngo 1.21\n",
+		"svc-a/main.go": "package main\n\nfunc helper() {}\n\nfunc main() {}\n",
+		"svc-b/go.mod":  "module example.com/svc-b\n\ngo 1.21\n",
+		"svc-b/main.go": "package main\n\nfunc main() {\n\thelper()\n}\n\nfunc helper() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	analyzer := NewCrossFileAnalyzer()
+	if err := analyzer.AnalyzeDirectory(context.Background(), tmpDir); err != nil {
+		t.Fatalf("Failed to analyze directory: %v", err)
+	}
+
+	results := analyzer.FindUnusedFunctions()
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 unused function, got %d: %v", len(results), results)
+	}
+	if want := filepath.Join(tmpDir, "svc-a", "main.go"); results[0].FilePath != want {
+		t.Errorf("Expected svc-a's helper to be unused despite svc-b calling its own helper, got %s", results[0].FilePath)
+	}
+}
+
+func TestCrossFileAnalyzer_ScopesPackages(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/app\n\ngo 1.21\n",
+		"a/a.go":         "package a\n\ntype store struct{}\n\nfunc (s *store) flush() {}\n\nfunc helper() {}\n",
+		"b/b.go":         "package b\n\ntype store struct{}\n\nfunc (s *store) flush() {}\n\nfunc helper() {}\n\nfunc Run(s *store) {\n\thelper()\n\ts.flush()\n}\n",
+		"c/callbacks.go": "package c\n\nvar hooks []func()\n\nfunc Register() {\n\thooks = append(hooks, helper)\n}\n",
+		"c/helper.go":    "package c\n\nfunc helper() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	analyzer := NewCrossFileAnalyzer()
+	if err := analyzer.AnalyzeDirectory(context.Background(), tmpDir); err != nil {
+		t.Fatalf("Failed to analyze directory: %v", err)
+	}
+
+	results := analyzer.FindUnusedFunctions()
+	verifyExpectedOrphans(t, results, []string{
+		"Function 'helper' is not called anywhere in the project",
+		"Method 'flush' on receiver 'store' is not called anywhere in the project",
+	})
+	for _, r := range results {
+		if want := filepath.Join(tmpDir, "a", "a.go"); r.FilePath != want {
+			t.Errorf("Expected only package a to have unused code, got %s: %s", r.FilePath, r.Message)
+		}
+	}
+}
+
+func TestCrossFileAnalyzer_IncludeExported(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"app/go.mod":            "module example.com/app\n\ngo 1.21\n",
+		"app/main.go":           "package main\n\nimport (\n\tstore \"example.com/app/internal/db\"\n\t\"example.com/lib\"\n)\n\nfunc main() {\n\tstore.Open(lib.Config{})\n}\n",
+		"app/internal/db/db.go": "package db\n\ntype Conn struct{}\n\nfunc (c *Conn) Close() {}\n\ntype Options struct{}\n\nfunc Open(cfg any) *Conn { return dial() }\n\nfunc Migrate() {}\n\nfunc dial() *Conn { return nil }\n",
+		"lib/go.mod":            "module example.com/lib\n\ngo 1.21\n",
+		"lib/lib.go":            "package lib\n\ntype Config struct{}\n\ntype Unused struct{}\n\nfunc Helper() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	analyzer := NewCrossFileAnalyzer()
+	analyzer.SetIncludeExported(true)
+	if err := analyzer.AnalyzeDirectory(context.Background(), tmpDir); err != nil {
+		t.Fatalf("Failed to analyze directory: %v", err)
+	}
+
+	results := analyzer.FindUnusedFunctions()
+	messages := make(map[string]bool)
+	for _, r := range results {
+		messages[r.Message] = true
+	}
+	want := []string{
+		"Function 'Migrate' is not called anywhere in the project",
+		"Type 'Options' is not used anywhere in the project",
+	}
+	if len(results) != len(want) {
+		t.Errorf("Expected %d results, got %d: %v", len(want), len(results), results)
+	}
+	for _, message := range want {
+		if !messages[message] {
+			t.Errorf("Expected to find: %s", message)
+		}
+	}
+}
+
+func TestDiscoverModules(t *testing.T) {
+	tmpDir := t.TempDir()
+	for dir, content := range map[string]string{
+		"":            "module example.com/root\n",
+		"tools":       "module \"example.com/root/tools\" // helper module\n",
+		"vendor/dep":  "module example.com/dep\n",
+		"tools/empty": "",
+	} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if content != "" {
+			if err := os.WriteFile(filepath.Join(tmpDir, dir, "go.mod"), []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write go.mod: %v", err)
+			}
+		}
+	}
+
+	modules, err := DiscoverModules(tmpDir)
+	if err != nil {
+		t.Fatalf("DiscoverModules failed: %v", err)
+	}
+	if len(modules) != 2 || modules[0].Path != "example.com/root" || modules[1].Path != "example.com/root/tools" {
+		t.Fatalf("Expected root and tools modules, got %+v", modules)
+	}
+
+	if module := FindModule(modules, filepath.Join(tmpDir, "tools", "empty", "x.go")); module == nil || module.Path != "example.com/root/tools" {
+		t.Errorf("Expected file to belong to the innermost module, got %+v", module)
+	}
+	if module := SelectModule(modules, "example.com/root"); module == nil || module.Dir != tmpDir {
+		t.Errorf("Expected to select the root module by path, got %+v", module)
+	}
+
+	// A subdirectory of a module still reports its enclosing module
+	nested, err := DiscoverModules(filepath.Join(tmpDir, "tools", "empty"))
+	if err != nil {
+		t.Fatalf("DiscoverModules failed: %v", err)
+	}
+	if len(nested) != 1 || nested[0].Path != "example.com/root/tools" {
+		t.Errorf("Expected the enclosing tools module, got %+v", nested)
+	}
+}
+
+func TestCrossFileAnalyzer_IgnorePatterns(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeGoFiles(t, tmpDir, map[string]string{
+		"main.go": `package main
+
+type server struct{}
+
+func (s *server) serveStatus() {}
+
+type handler struct{}
+
+func (h *handler) handleLogin() {}
+
+func handleHealth()   {}
+func runServe()       {}
+func provideDB()      {}
+func orphan()         {}
+func main()           {}
+`,
+	})
+
+	analyzer := NewCrossFileAnalyzer()
+	if err := analyzer.SetIgnorePatterns([]string{"handle*", "/^run[A-Z]/"}, []string{"serv*"}); err != nil {
+		t.Fatalf("Failed to set ignore patterns: %v", err)
+	}
+	if err := analyzer.AnalyzeDirectory(context.Background(), tmpDir); err != nil {
+		t.Fatalf("Failed to analyze directory: %v", err)
+	}
+
+	var names []string
+	for _, r := range analyzer.FindUnusedFunctions() {
+		names = append(names, r.Message)
+	}
+	sort.Strings(names)
+	want := []string{
+		"Function 'orphan' is not called anywhere in the project",
+		"Function 'provideDB' is not called anywhere in the project",
+	}
+	if strings.Join(names, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected %v, got %v", want, names)
+	}
+
+	if err := analyzer.SetIgnorePatterns([]string{"/(/"}, nil); err == nil {
+		t.Error("Expected an invalid regular expression to be rejected")
+	}
+	if err := analyzer.SetIgnorePatterns([]string{"[a-"}, nil); err == nil {
+		t.Error("Expected an invalid glob to be rejected")
+	}
+}
+
+func TestCrossFileAnalyzer_MinStatements(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeGoFiles(t, tmpDir, map[string]string{
+		"main.go": `package main
+
+type cache struct{ size int }
+
+func (c *cache) len() int { return c.size }
+
+func (c *cache) reset() {
+	c.size = 0
+	for i := 0; i < 3; i++ {
+		c.size++
+	}
+}
+
+func keep() string { return "kept on purpose" }
+
+func orphan(items []int) int {
+	total := 0
+	for _, item := range items {
+		total += item
+	}
+	return total
+}
+
+func main() {}
+`,
+	})
+
+	analyzer := NewCrossFileAnalyzer()
+	analyzer.SetMinStatements(3)
+	if err := analyzer.AnalyzeDirectory(context.Background(), tmpDir); err != nil {
+		t.Fatalf("Failed to analyze directory: %v", err)
+	}
+
+	var names []string
+	for _, r := range analyzer.FindUnusedFunctions() {
+		names = append(names, r.Message)
+	}
+	sort.Strings(names)
+	want := []string{
+		"Function 'orphan' is not called anywhere in the project",
+		"Method 'reset' on receiver 'cache' is not called anywhere in the project",
+	}
+	if strings.Join(names, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected %v, got %v", want, names)
+	}
+
+	analyzer.SetMinStatements(0)
+	if got := len(analyzer.FindUnusedFunctions()); got != 4 {
+		t.Errorf("Expected all 4 unused functions without a minimum, got %d", got)
+	}
+}
+
+// TestCrossFileAnalyzer_RegistrationReferences ensures functions registered through tables,
+// package-level variables and registration calls in other files count as used
+func TestCrossFileAnalyzer_RegistrationReferences(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeGoFiles(t, tmpDir, map[string]string{
+		"main.go": `package main
+
+import (
+	"flag"
+	"net/http"
+	"text/template"
+)
+
+var routes = map[string]http.HandlerFunc{"/health": serveHealth}
+
+var defaults = loadDefaults()
+
+func main() {
+	http.HandleFunc("/", serveRoot)
+	flag.Func("level", "log level", parseLevel)
+	_ = template.New("page").Funcs(template.FuncMap{"upper": upper})
+
+	cases := []struct {
+		name string
+		fn   func() int
+	}{
+		{name: "one", fn: one},
+		{"two", two},
+	}
+	_ = cases
+}
+`,
+		"handlers.go": `package main
+
+import "net/http"
+
+func serveRoot(w http.ResponseWriter, r *http.Request)   {}
+func serveHealth(w http.ResponseWriter, r *http.Request) {}
+func parseLevel(s string) error                          { return nil }
+func upper(s string) string                              { return s }
+func loadDefaults() int                                  { return 0 }
+func one() int                                           { return 1 }
+func two() int                                           { return 2 }
+func orphan()                                            {}
+`,
+	})
+
+	results := analyzeForOrphans(t, tmpDir)
+	verifyOrphanCount(t, results, 1)
+	verifyExpectedOrphans(t, results, []string{"Function 'orphan' is not called anywhere in the project"})
+}
+
+// TestCrossFileAnalyzer_AssemblyAndCgo ensures functions implemented in or called from
+// assembly, exported to C or named by go:linkname are not reported
+func TestCrossFileAnalyzer_AssemblyAndCgo(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeGoFiles(t, tmpDir, map[string]string{
+		"add_amd64.s": "#include \"textflag.h\"\n\nTEXT ·add(SB), NOSPLIT, $0-24\n\tCALL ·onOverflow(SB)\n\tRET\n",
+		"main.go": `package main
+
+import "C"
+
+import _ "unsafe"
+
+func add(a, b int) int
+
+func onOverflow() {}
+
+//export goLog
+func goLog() {}
+
+//go:linkname localHelper
+func localHelper() {}
+
+func orphan() {}
+
+func main() {}
+`,
+	})
+
+	results := analyzeForOrphans(t, tmpDir)
+	verifyOrphanCount(t, results, 1)
+	verifyExpectedOrphans(t, results, []string{"Function 'orphan' is not called anywhere in the project"})
+}
+
+// TestCrossFileAnalyzer_TemplatesAndStructTags ensures functions named only from template
+// files, embedded templates or struct tags are not reported
+func TestCrossFileAnalyzer_TemplatesAndStructTags(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":             "module example.com/site\n\ngo 1.21\n",
+		"layout.gohtml":      "<header>{{ Banner }}</header>\n",
+		"pages/product.html": "<h1>{{ .Title }}</h1>\n<p>{{ FormatPrice .Cents | printf \"%s\" }}</p>\n",
+		"views/list.txt":     "{{ range .Items }}{{ Summarize . }}{{ end }}\n",
+		"main.go": `package main
+
+import (
+	"embed"
+	"text/template"
+)
+
+//go:embed pages
+var pages embed.FS
+
+type Product struct {
+	Slug string ` + "`json:\"slug\" validate:\"required,isSlug\"`" + `
+}
+
+func isSlug(s string) bool { return s != "" }
+
+func Banner() string { return "" }
+
+func FormatPrice(cents int) string { return "" }
+
+func Summarize(item any) string { return "" }
+
+func Orphan() {}
+
+func main() {
+	template.Must(template.ParseFiles("views/list.txt")).Execute(nil, Product{})
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	analyzer := NewCrossFileAnalyzer()
+	analyzer.SetIncludeExported(true)
+	if err := analyzer.AnalyzeDirectory(context.Background(), tmpDir); err != nil {
+		t.Fatalf("Failed to analyze directory: %v", err)
+	}
+
+	results := analyzer.FindUnusedFunctions()
+	verifyOrphanCount(t, results, 1)
+	verifyExpectedOrphans(t, results, []string{"Function 'Orphan' is not called anywhere in the project"})
+}
+
+func TestTemplateNames(t *testing.T) {
+	got := templateNames(`{{- if .User.Admin }}{{ $name := .Name }}{{ upper $name "Quoted" 10 }}{{ end -}}`)
+	want := []string{"User", "Admin", "Name", "upper"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestCrossFileAnalyzer_LibraryModules ensures the exported API of a module without a main
+// package is not reported, while a main function outside package main is
+func TestCrossFileAnalyzer_LibraryModules(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":      "module example.com/lib\n\ngo 1.21\n",
+		"lib.go":      "package lib\n\nfunc Exported() {}\n\nfunc main() {}\n",
+		"util/str.go": "package util\n\nfunc Trim() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	analyzer := NewCrossFileAnalyzer()
+	analyzer.SetIncludeExported(true)
+	if err := analyzer.AnalyzeDirectory(context.Background(), tmpDir); err != nil {
+		t.Fatalf("Failed to analyze directory: %v", err)
+	}
+
+	results := analyzer.FindUnusedFunctions()
+	verifyOrphanCount(t, results, 1)
+	verifyExpectedOrphans(t, results, []string{"Function 'main' is not called anywhere in the project"})
+	if want := "Review if this helper is needed or should be part of the library's exported API"; len(results) == 1 && results[0].Suggestion != want {
+		t.Errorf("Expected the library suggestion, got %q", results[0].Suggestion)
+	}
+
+	if kind := ClassifyProject([]string{filepath.Join(tmpDir, "lib.go"), filepath.Join(tmpDir, "util", "str.go")}, nil); kind != ProjectLibrary {
+		t.Errorf("Expected a library, got %q", kind)
+	}
+	writeGoFiles(t, tmpDir, map[string]string{"main.go": "package main\n\nfunc main() {}\n"})
+	if kind := ClassifyProject([]string{filepath.Join(tmpDir, "lib.go"), filepath.Join(tmpDir, "main.go")}, nil); kind != ProjectBinary {
+		t.Errorf("Expected a binary, got %q", kind)
+	}
+}
@@ -0,0 +1,95 @@
+package golang
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// ProjectKind tells binaries, whose code is only reachable from their main packages, from
+// libraries, whose exported API is meant for code outside the project
+type ProjectKind string
+
+const (
+	// ProjectBinary is Go code with at least one main package declaring func main
+	ProjectBinary ProjectKind = "binary"
+	// ProjectLibrary is Go code without any main package
+	ProjectLibrary ProjectKind = "library"
+)
+
+// ClassifyProject reports whether the non-test Go files make up a binary or a library, or ""
+// when there are none. Files that cannot be parsed are skipped.
+func ClassifyProject(files []string, cache *ASTCache) ProjectKind {
+	fset := token.NewFileSet()
+	if cache != nil {
+		fset = cache.FileSet()
+	}
+
+	kind := ProjectKind("")
+	for _, file := range files {
+		if !strings.HasSuffix(file, ".go") || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parseWithCache(cache, fset, file)
+		if err != nil {
+			continue
+		}
+		if isEntrypoint(f) {
+			return ProjectBinary
+		}
+		kind = ProjectLibrary
+	}
+	return kind
+}
+
+// isEntrypoint reports whether f belongs to a main package and declares its main function
+func isEntrypoint(f *ast.File) bool {
+	if f.Name == nil || f.Name.Name != "main" {
+		return false
+	}
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+			return true
+		}
+	}
+	return false
+}
+
+// binaryModules returns the directories of the modules holding a main function, with "" for
+// Go files outside any module
+func (a *CrossFileAnalyzer) binaryModules() map[string]bool {
+	binaries := make(map[string]bool)
+	for filePath, funcs := range a.functions {
+		if funcInfo, ok := funcs["main"]; ok && funcInfo.IsMain {
+			binaries[a.moduleDir(filePath)] = true
+		}
+	}
+	return binaries
+}
+
+// sameSet reports whether two sets hold the same keys
+func sameSet(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key := range a {
+		if !b[key] {
+			return false
+		}
+	}
+	return true
+}
+
+// unusedFunctionSuggestion advises on an unused function according to the kind of code it is
+// part of: nothing outside a binary can call its functions, while a library's unexported
+// helper may be meant to back its API
+func (a *CrossFileAnalyzer) unusedFunctionSuggestion(funcInfo *FunctionInfo) string {
+	switch {
+	case funcInfo.Module == nil:
+		return "Review if this function is needed or if it should be exported/called"
+	case a.binaries[funcInfo.Module.Dir]:
+		return "Remove this function: nothing outside a binary can call it"
+	default:
+		return "Review if this helper is needed or should be part of the library's exported API"
+	}
+}
@@ -0,0 +1,341 @@
+package golang
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// DuplicateErrorRuleID identifies findings of error messages repeated across a package
+const DuplicateErrorRuleID = "duplicate-error-string"
+
+func init() {
+	core.RegisterRuleDoc(core.RuleDoc{
+		ID:          DuplicateErrorRuleID,
+		Name:        "Duplicate Error String",
+		Description: "Detects error messages repeated with the same or near-identical wording across a Go package",
+		Rationale: "Generated code wraps every error with a freshly typed message, so the same phrasing " +
+			"ends up in dozens of places with small variations. Callers cannot match such errors with " +
+			"errors.Is, and a change of wording has to be made everywhere. Messages are compared word by " +
+			"word with formatting verbs as placeholders; those of fewer than three words are too generic " +
+			"to report.",
+		Category:  core.CategoryStyle,
+		Severity:  core.SeverityInfo,
+		Languages: []string{"go"},
+		Examples: []core.RuleExample{{
+			Bad: `return fmt.Errorf("failed to read file %s: %w", path, err)
+// ... and elsewhere in the package
+return fmt.Errorf("Failed to read the file %q: %v", name, err)`,
+			Good: `var ErrRead = errors.New("read failed")
+
+func readError(path string, err error) error {
+	return fmt.Errorf("%w: %s: %w", ErrRead, path, err)
+}`,
+		}},
+		Options: []core.RuleOption{
+			{Key: "rules.duplicateErrors.enabled", Flag: "-enable-duplicate-errors", Default: "true", Description: "Report repeated error messages"},
+			{Key: "rules.duplicateErrors.minOccurrences", Flag: "-duplicate-error-min", Default: "3", Description: "Occurrences of a message in a package before it is reported"},
+			{Key: "rules.duplicateErrors.threshold", Flag: "-duplicate-error-threshold", Default: "0.85", Description: "Word similarity at which messages are near-identical"},
+		},
+	})
+}
+
+// minErrorWords is the number of words below which messages such as "invalid %s" are too
+// generic to count as duplicates
+const minErrorWords = 3
+
+// maxListedLocations caps the other occurrences named in a finding's suggestion
+const maxListedLocations = 5
+
+var (
+	formatVerbPattern = regexp.MustCompile(`%[-+# 0]*(\[\d+\])?(\d+|\*)?(\.(\d+|\*))?[a-zA-Z%]`)
+	errorWordPattern  = regexp.MustCompile(`[\pL\pN_]+|%`)
+)
+
+// ErrorStringAnalyzer finds error messages written out again and again across a package: the
+// format strings of fmt.Errorf and the messages of errors.New, errors.Wrap and errors.Wrapf
+// that normalize to the same or nearly the same words
+type ErrorStringAnalyzer struct {
+	fset           *token.FileSet
+	cache          *ASTCache // shared parse cache, see SetCache
+	minOccurrences int
+	threshold      float64
+}
+
+// errorMessage is one error message literal in the source
+type errorMessage struct {
+	text   string   // the literal as written
+	words  []string // normalized words, see normalizeErrorMessage
+	file   string
+	line   int
+	column int
+	pkg    string
+}
+
+// NewErrorStringAnalyzer creates an analyzer reporting messages that occur at least
+// minOccurrences times in a package. Messages are near-identical when the similarity of their
+// normalized words reaches threshold, between 0 and 1.
+func NewErrorStringAnalyzer(minOccurrences int, threshold float64) *ErrorStringAnalyzer {
+	return &ErrorStringAnalyzer{
+		fset:           token.NewFileSet(),
+		minOccurrences: minOccurrences,
+		threshold:      threshold,
+	}
+}
+
+// SetCache makes the analyzer take ASTs from a cache shared with the other Go analyses
+func (a *ErrorStringAnalyzer) SetCache(cache *ASTCache) {
+	a.cache = cache
+	a.fset = cache.FileSet()
+}
+
+// AnalyzeFiles checks the packages of the given files. Every non-test file in a package's
+// directory is read, so repetitions in files that were not given still count, but findings
+// are only reported in the given files.
+func (a *ErrorStringAnalyzer) AnalyzeFiles(ctx context.Context, files []string) ([]core.Result, error) {
+	analyzed := make(map[string]bool, len(files))
+	var dirs []string
+	for _, file := range files {
+		analyzed[file] = true
+		if dir := filepath.Dir(file); !containsString(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	var results []core.Result
+	for _, dir := range dirs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		messages, err := a.packageMessages(dir)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, a.findDuplicates(messages, analyzed)...)
+	}
+	return results, nil
+}
+
+// packageMessages returns the error messages of the non-test, non-generated Go files in dir,
+// in file and line order
+func (a *ErrorStringAnalyzer) packageMessages(dir string) ([]errorMessage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []errorMessage
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || strings.HasPrefix(name, "_") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		f, err := parseWithCache(a.cache, a.fset, path)
+		if err != nil || ast.IsGenerated(f) {
+			continue
+		}
+		messages = append(messages, a.fileMessages(f, path)...)
+	}
+	return messages, nil
+}
+
+// fileMessages returns the error message literals of a file
+func (a *ErrorStringAnalyzer) fileMessages(f *ast.File, filePath string) []errorMessage {
+	srcLines := readLines(filePath)
+	var messages []errorMessage
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		lit := errorMessageArg(call)
+		if lit == nil {
+			return true
+		}
+		text, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		words := normalizeErrorMessage(text)
+		if len(words) < minErrorWords {
+			return true
+		}
+		pos := a.fset.Position(lit.Pos())
+		messages = append(messages, errorMessage{
+			text: text, words: words, file: filePath, line: pos.Line, column: charColumn(srcLines, pos), pkg: f.Name.Name,
+		})
+		return true
+	})
+	return messages
+}
+
+// errorMessageArg returns the message literal of an error constructor call, or nil
+func errorMessageArg(call *ast.CallExpr) *ast.BasicLit {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+
+	arg := -1
+	switch pkg.Name + "." + sel.Sel.Name {
+	case "fmt.Errorf", "errors.New":
+		arg = 0
+	case "errors.Wrap", "errors.Wrapf":
+		arg = 1
+	}
+	if arg < 0 || len(call.Args) <= arg {
+		return nil
+	}
+	lit, ok := call.Args[arg].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return nil
+	}
+	return lit
+}
+
+// normalizeErrorMessage reduces a message to lower-case words, with every formatting verb
+// replaced by %, so "Failed to read file %s: %w" and "failed to read file %q: %v" match
+func normalizeErrorMessage(text string) []string {
+	text = formatVerbPattern.ReplaceAllString(strings.ToLower(text), " % ")
+	return errorWordPattern.FindAllString(text, -1)
+}
+
+// findDuplicates groups near-identical messages and reports each group that is repeated often
+// enough, at its first occurrence in an analyzed file
+func (a *ErrorStringAnalyzer) findDuplicates(messages []errorMessage, analyzed map[string]bool) []core.Result {
+	var groups [][]errorMessage
+	for _, message := range messages {
+		joined := false
+		for i, group := range groups {
+			if a.nearIdentical(group[0].words, message.words) {
+				groups[i] = append(group, message)
+				joined = true
+				break
+			}
+		}
+		if !joined {
+			groups = append(groups, []errorMessage{message})
+		}
+	}
+
+	var results []core.Result
+	for _, group := range groups {
+		if len(group) < a.minOccurrences {
+			continue
+		}
+		for i, message := range group {
+			if analyzed[message.file] {
+				results = append(results, duplicateErrorResult(message, group, i))
+				break
+			}
+		}
+	}
+	return results
+}
+
+// nearIdentical reports whether two normalized messages say the same thing: they have the
+// same placeholders, lengths within one word, and words similar enough to reach the threshold
+func (a *ErrorStringAnalyzer) nearIdentical(words1, words2 []string) bool {
+	if len(words1) > len(words2) {
+		words1, words2 = words2, words1
+	}
+	if len(words2)-len(words1) > 1 || countString(words1, "%") != countString(words2, "%") {
+		return false
+	}
+	return tokenSimilarity(words1, words2)*float64(len(words1))/float64(len(words2)) >= a.threshold
+}
+
+func duplicateErrorResult(message errorMessage, group []errorMessage, index int) core.Result {
+	var others []string
+	for i, other := range group {
+		if i == index {
+			continue
+		}
+		if len(others) == maxListedLocations {
+			others = append(others, fmt.Sprintf("and %d more", len(group)-1-maxListedLocations))
+			break
+		}
+		others = append(others, fmt.Sprintf("%s:%d", filepath.Base(other.file), other.line))
+	}
+
+	variants := make(map[string]bool)
+	for _, other := range group {
+		variants[other.text] = true
+	}
+	wording, confidence := "the same", core.ConfidenceHigh
+	if len(variants) > 1 {
+		wording, confidence = "near-identical", core.ConfidenceMedium
+	}
+
+	return core.Result{
+		RuleID:     DuplicateErrorRuleID,
+		RuleName:   "Duplicate Error String",
+		Category:   string(core.CategoryStyle),
+		Severity:   string(core.SeverityInfo),
+		Confidence: string(confidence),
+		FilePath:   message.file,
+		Line:       message.line,
+		Column:     message.column,
+		Message: fmt.Sprintf("Error message %q is repeated with %s wording %d times in package %s",
+			message.text, wording, len(group), message.pkg),
+		Suggestion: fmt.Sprintf("Declare a sentinel error or a shared helper that wraps errors with this message; other occurrences: %s",
+			strings.Join(others, ", ")),
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func countString(values []string, value string) int {
+	count := 0
+	for _, v := range values {
+		if v == value {
+			count++
+		}
+	}
+	return count
+}
+
+// tokenSimilarity counts the tokens of tokens1 that also appear in tokens2, relative to the
+// length of the shorter sequence. Pass the shorter sequence first for a score from 0 to 1.
+func tokenSimilarity(tokens1, tokens2 []string) float64 {
+	if len(tokens1) == 0 || len(tokens2) == 0 {
+		return 0
+	}
+
+	matchCount := 0
+	for _, t1 := range tokens1 {
+		for _, t2 := range tokens2 {
+			if t1 == t2 {
+				matchCount++
+				break
+			}
+		}
+	}
+
+	smaller := len(tokens1)
+	if len(tokens2) < smaller {
+		smaller = len(tokens2)
+	}
+
+	return float64(matchCount) / float64(smaller)
+}
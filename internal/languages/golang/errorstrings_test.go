@@ -0,0 +1,144 @@
+package golang
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeErrorMessage(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Failed to read file %s: %w", []string{"failed", "to", "read", "file", "%", "%"}},
+		{"failed to read file %q: %v", []string{"failed", "to", "read", "file", "%", "%"}},
+		{"value %-8.2f out of range (100%%)", []string{"value", "%", "out", "of", "range", "100", "%"}},
+		{"field %[1]s of %T", []string{"field", "%", "of", "%"}},
+	}
+	for _, tt := range tests {
+		if got := normalizeErrorMessage(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("normalizeErrorMessage(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestErrorStringAnalyzer(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	reader := write("reader.go", `package store
+
+import (
+	"errors"
+	"fmt"
+)
+
+func readConfig(path string) error {
+	return fmt.Errorf("failed to read file %s: %w", path, errors.New("missing"))
+}
+
+func readIndex(path string) error {
+	return fmt.Errorf("Failed to read file %q: %v", path, nil)
+}
+
+func closeAll() error {
+	return errors.New("close failed")
+}
+`)
+	writer := write("writer.go", `package store
+
+import "fmt"
+
+func readLock(path string) error {
+	return fmt.Errorf("failed to read the file %s: %w", path, nil)
+}
+
+func writeIndex(path string) error {
+	return fmt.Errorf("failed to write file %s: %w", path, nil)
+}
+
+func closeOne() error {
+	return fmt.Errorf("close failed")
+}
+
+func closeTwo() error {
+	return fmt.Errorf("close failed")
+}
+`)
+	write("reader_test.go", `package store
+
+import "fmt"
+
+func readFixture(path string) error {
+	return fmt.Errorf("failed to read file %s: %w", path, nil)
+}
+`)
+
+	analyzer := NewErrorStringAnalyzer(3, 0.85)
+	results, err := analyzer.AnalyzeFiles(context.Background(), []string{reader, writer})
+	if err != nil {
+		t.Fatalf("Failed to analyze files: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected one repeated message, got %v", results)
+	}
+	result := results[0]
+	if result.RuleID != DuplicateErrorRuleID || result.FilePath != reader || result.Line != 9 {
+		t.Errorf("Expected the finding at the first occurrence, got %s %s:%d", result.RuleID, result.FilePath, result.Line)
+	}
+	if !strings.Contains(result.Message, "near-identical wording 3 times in package store") {
+		t.Errorf("Unexpected message: %s", result.Message)
+	}
+	if !strings.HasSuffix(result.Suggestion, "other occurrences: reader.go:13, writer.go:6") {
+		t.Errorf("Expected the other occurrences listed, got: %s", result.Suggestion)
+	}
+
+	// findings are reported in an analyzed file even when the first occurrence is elsewhere
+	results, err = NewErrorStringAnalyzer(3, 0.85).AnalyzeFiles(context.Background(), []string{writer})
+	if err != nil {
+		t.Fatalf("Failed to analyze files: %v", err)
+	}
+	if len(results) != 1 || results[0].FilePath != writer || results[0].Line != 6 {
+		t.Errorf("Expected the finding in writer.go, got %v", results)
+	}
+
+	// at threshold 1.0 only messages that normalize to the same words are grouped, and short
+	// messages such as "close failed" are too generic to report
+	results, err = NewErrorStringAnalyzer(2, 1.0).AnalyzeFiles(context.Background(), []string{reader, writer})
+	if err != nil {
+		t.Fatalf("Failed to analyze files: %v", err)
+	}
+	if len(results) != 1 || !strings.HasSuffix(results[0].Message, "2 times in package store") {
+		t.Errorf("Expected only the read file messages of reader.go, got %v", results)
+	}
+}
+
+func TestErrorStringAnalyzer_Columns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reader.go")
+	content := "\uFEFFpackage store\n\nimport \"fmt\"\n\n" +
+		"func readConfig(path string) error {\n\t/* 🚀 */ return fmt.Errorf(\"failed to read file %s: %w\", path, nil)\n}\n\n" +
+		"func readIndex(path string) error {\n\treturn fmt.Errorf(\"failed to read file %s: %w\", path, nil)\n}\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := NewErrorStringAnalyzer(2, 1.0).AnalyzeFiles(context.Background(), []string{path})
+	if err != nil {
+		t.Fatalf("Failed to analyze files: %v", err)
+	}
+	// the emoji is one character but four bytes
+	if len(results) != 1 || results[0].Line != 6 || results[0].Column != 28 {
+		t.Errorf("Expected the finding at 6:28, got %v", results)
+	}
+}
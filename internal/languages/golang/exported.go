@@ -0,0 +1,236 @@
+package golang
+
+import (
+	"fmt"
+	"go/ast"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// exportedUsage tracks where exported functions and types are referenced, for reporting
+// dead exported symbols in modules that nothing else imports
+type exportedUsage struct {
+	types      []TypeInfo
+	local      usageSet // scopeKey of names used inside their own package
+	qualified  usageSet // import path + "." + name of names used through an import
+	importedBy usageSet // import paths used by each module, keyed by module dir + " " + path
+}
+
+// TypeInfo describes an exported type declaration
+type TypeInfo struct {
+	Name   string
+	File   string
+	Line   int
+	Module *Module
+}
+
+func newExportedUsage() exportedUsage {
+	return exportedUsage{
+		local:      newUsageSet(),
+		qualified:  newUsageSet(),
+		importedBy: newUsageSet(),
+	}
+}
+
+// removeFile forgets the types declared and the names used in filePath
+func (e *exportedUsage) removeFile(filePath string) {
+	types := e.types[:0]
+	for _, typeInfo := range e.types {
+		if typeInfo.File != filePath {
+			types = append(types, typeInfo)
+		}
+	}
+	e.types = types
+	e.local.removeFile(filePath)
+	e.qualified.removeFile(filePath)
+	e.importedBy.removeFile(filePath)
+}
+
+// SetIncludeExported enables reporting exported functions and types that nothing references.
+// Only modules with a main package that no other analyzed module imports are checked, since a
+// library's exported API is meant for callers outside the tree. Exported methods are never
+// reported, as they often satisfy interfaces implicitly. Call it before AnalyzeDirectory.
+func (a *CrossFileAnalyzer) SetIncludeExported(enabled bool) {
+	a.includeExported = enabled
+}
+
+// collectExportedUsage records exported type declarations and every identifier and
+// package-qualified name used in the file
+func (a *CrossFileAnalyzer) collectExportedUsage(f *ast.File, filePath string) {
+	imports, dotImports := a.fileImports(f, filePath)
+
+	declared := make(map[*ast.Ident]bool)
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			declared[decl.Name] = true
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if spec, ok := spec.(*ast.TypeSpec); ok {
+					declared[spec.Name] = true
+					a.registerType(spec, filePath)
+				}
+			}
+		}
+	}
+
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ImportSpec:
+			return false
+		case *ast.FuncDecl:
+			// A method's receiver does not make its type used
+			ast.Inspect(n.Type, visit)
+			if n.Body != nil {
+				ast.Inspect(n.Body, visit)
+			}
+			return false
+		case *ast.SelectorExpr:
+			if pkg, ok := n.X.(*ast.Ident); ok {
+				if importPath, ok := imports[pkg.Name]; ok {
+					a.exported.qualified.add(importPath+"."+n.Sel.Name, filePath)
+					return false
+				}
+			}
+			// Field and method selectors never name a package-level symbol
+			ast.Inspect(n.X, visit)
+			return false
+		case *ast.Ident:
+			if !declared[n] {
+				a.exported.local.add(a.scopeKey(filePath, n.Name), filePath)
+				for _, importPath := range dotImports {
+					a.exported.qualified.add(importPath+"."+n.Name, filePath)
+				}
+			}
+		}
+		return true
+	}
+	ast.Inspect(f, visit)
+}
+
+// registerType records an exported type declaration
+func (a *CrossFileAnalyzer) registerType(spec *ast.TypeSpec, filePath string) {
+	if !spec.Name.IsExported() {
+		return
+	}
+	a.exported.types = append(a.exported.types, TypeInfo{
+		Name:   spec.Name.Name,
+		File:   filePath,
+		Line:   a.fset.Position(spec.Pos()).Line,
+		Module: a.moduleOf(filePath),
+	})
+}
+
+// fileImports maps the local names of a file's imports to their paths, and lists the paths
+// of dot imports. Every import is also recorded against the importing module.
+func (a *CrossFileAnalyzer) fileImports(f *ast.File, filePath string) (map[string]string, []string) {
+	moduleDir := ""
+	if module := a.moduleOf(filePath); module != nil {
+		moduleDir = module.Dir
+	}
+
+	imports := make(map[string]string)
+	var dotImports []string
+	for _, spec := range f.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		a.exported.importedBy.add(moduleDir+" "+importPath, filePath)
+
+		name := importName(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		switch name {
+		case "_":
+		case ".":
+			dotImports = append(dotImports, importPath)
+		default:
+			imports[name] = importPath
+		}
+	}
+	return imports, dotImports
+}
+
+// importName guesses the package name of an unaliased import from its path, skipping a
+// trailing major version element (example.com/lib/v2 yields lib)
+func importName(importPath string) string {
+	name := path.Base(importPath)
+	if len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		if parent := path.Dir(importPath); parent != "." {
+			return path.Base(parent)
+		}
+	}
+	return name
+}
+
+// isApplication reports whether module is checked for dead exported symbols: it builds a
+// binary and no other analyzed module imports any of its packages. A module without a main
+// package is a library, whose exported API is meant for callers outside the tree.
+func (a *CrossFileAnalyzer) isApplication(module *Module) bool {
+	if module == nil || !a.binaries[module.Dir] {
+		return false
+	}
+	for key := range a.exported.importedBy.Files {
+		importerDir, importPath, _ := strings.Cut(key, " ")
+		if importerDir != module.Dir && (importPath == module.Path || strings.HasPrefix(importPath, module.Path+"/")) {
+			return false
+		}
+	}
+	return true
+}
+
+// reportsExported reports whether the exported function funcInfo should be checked
+func (a *CrossFileAnalyzer) reportsExported(funcInfo *FunctionInfo) bool {
+	return a.includeExported && !funcInfo.IsMethod && a.isApplication(funcInfo.Module)
+}
+
+// isExportedUsed reports whether an exported name declared in filePath is referenced inside
+// its package or through an import of the package
+func (a *CrossFileAnalyzer) isExportedUsed(filePath, name string, module *Module) bool {
+	if a.exported.local.has(a.scopeKey(filePath, name)) {
+		return true
+	}
+	return module != nil && a.exported.qualified.has(packageImportPath(module, filepath.Dir(filePath))+"."+name)
+}
+
+// packageImportPath returns the import path of the package in dir
+func packageImportPath(module *Module, dir string) string {
+	rel, err := filepath.Rel(module.Dir, dir)
+	if err != nil || rel == "." {
+		return module.Path
+	}
+	return module.Path + "/" + filepath.ToSlash(rel)
+}
+
+// findUnusedTypes finds exported types that nothing references
+func (a *CrossFileAnalyzer) findUnusedTypes() []core.Result {
+	if !a.includeExported {
+		return nil
+	}
+
+	var results []core.Result
+	for _, typeInfo := range a.exported.types {
+		if !a.isApplication(typeInfo.Module) || a.isExportedUsed(typeInfo.File, typeInfo.Name, typeInfo.Module) {
+			continue
+		}
+		results = append(results, core.Result{
+			RuleID:     "cross-file-unused-type",
+			RuleName:   "Cross-File Unused Type",
+			Category:   string(core.CategoryOrphaned),
+			Severity:   "warning",
+			Confidence: string(core.ConfidenceMedium),
+			FilePath:   typeInfo.File,
+			Line:       typeInfo.Line,
+			Message:    fmt.Sprintf("Type '%s' is not used anywhere in the project", typeInfo.Name),
+			Suggestion: "Review if this type is needed or remove it",
+		})
+	}
+	return results
+}
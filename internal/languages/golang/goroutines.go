@@ -0,0 +1,385 @@
+package golang
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang/rules"
+)
+
+// cancellationWords mark identifiers that can stop or wait for a goroutine: contexts, done and
+// quit channels, cancel functions and wait groups
+var cancellationWords = []string{"ctx", "context", "done", "quit", "stop", "cancel", "shutdown", "wait", "group"}
+
+// findGoroutineLeak returns the first goroutine of a function that may never exit or never be
+// waited for, see rules.GoroutineLeak, or a zero GoroutineLeak
+func findGoroutineLeak(funcDecl *ast.FuncDecl, fset *token.FileSet) rules.GoroutineLeak {
+	if funcDecl.Body == nil {
+		return rules.GoroutineLeak{}
+	}
+
+	var first rules.GoroutineLeak
+	for _, leak := range []rules.GoroutineLeak{
+		uncancelledGoroutine(funcDecl.Body, fset),
+		blockedChannelSend(funcDecl.Body, fset),
+		missingWaitGroupDone(funcDecl.Body, fset),
+	} {
+		if leak.Line != 0 && (first.Line == 0 || leak.Line < first.Line) {
+			first = leak
+		}
+	}
+	return first
+}
+
+// uncancelledGoroutine returns the first go statement in a loop where neither the goroutine
+// nor the loop body mentions a context, a done channel or a wait group
+func uncancelledGoroutine(body *ast.BlockStmt, fset *token.FileSet) rules.GoroutineLeak {
+	var leak rules.GoroutineLeak
+	inspectWithStack(body, func(n ast.Node, stack []ast.Node) bool {
+		if leak.Line != 0 {
+			return false
+		}
+		goStmt, ok := n.(*ast.GoStmt)
+		if !ok {
+			return true
+		}
+		loopBody := innermostLoopBody(stack)
+		if loopBody == nil || mentionsCancellation(goStmt) || mentionsCancellation(loopBody) {
+			return true
+		}
+		leak = rules.GoroutineLeak{Kind: rules.LeakUncancelled, Line: fset.Position(goStmt.Pos()).Line}
+		return false
+	})
+	return leak
+}
+
+// innermostLoopBody returns the body of the innermost for or range loop on the stack, or nil
+func innermostLoopBody(stack []ast.Node) *ast.BlockStmt {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch loop := stack[i].(type) {
+		case *ast.ForStmt:
+			return loop.Body
+		case *ast.RangeStmt:
+			return loop.Body
+		}
+	}
+	return nil
+}
+
+// mentionsCancellation reports whether a node refers to anything named like a context, a done
+// channel, a cancel function or a wait group
+func mentionsCancellation(node ast.Node) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || found {
+			return !found
+		}
+		name := strings.ToLower(ident.Name)
+		if name == "wg" || strings.HasSuffix(name, "wg") {
+			found = true
+		}
+		for _, word := range cancellationWords {
+			if strings.Contains(name, word) {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// channelUse records how a local unbuffered channel is used within its function
+type channelUse struct {
+	goroutineSend token.Pos // first send from inside a goroutine, token.NoPos if none
+	receives      int
+	selectOnly    bool // every receive is a case of a select with other cases
+	escapes       bool // passed, returned or assigned, so it may be received elsewhere
+}
+
+// blockedChannelSend returns the first goroutine that sends on a local unbuffered channel which
+// nothing in the function receives from, or which is only received in a select that can take
+// another case and stop listening. Channels that leave the function are skipped.
+func blockedChannelSend(body *ast.BlockStmt, fset *token.FileSet) rules.GoroutineLeak {
+	uses := unbufferedChannels(body)
+	if len(uses) == 0 {
+		return rules.GoroutineLeak{}
+	}
+	recordChannelUses(body, uses)
+
+	var leak rules.GoroutineLeak
+	for obj, use := range uses {
+		if use.escapes || use.goroutineSend == token.NoPos || (use.receives > 0 && !use.selectOnly) {
+			continue
+		}
+		kind := rules.LeakUnreceived
+		if use.receives > 0 {
+			kind = rules.LeakAbandoned
+		}
+		if line := fset.Position(use.goroutineSend).Line; leak.Line == 0 || line < leak.Line {
+			leak = rules.GoroutineLeak{Kind: kind, Name: obj.Name, Line: line}
+		}
+	}
+	return leak
+}
+
+// unbufferedChannels returns the channels a function creates with make(chan T) and :=
+func unbufferedChannels(body *ast.BlockStmt) map[*ast.Object]*channelUse {
+	uses := make(map[*ast.Object]*channelUse)
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+		for i, rhs := range assign.Rhs {
+			if ident, ok := assign.Lhs[i].(*ast.Ident); ok && isUnbufferedMake(rhs) && ident.Obj != nil {
+				uses[ident.Obj] = &channelUse{selectOnly: true}
+			}
+		}
+		return true
+	})
+	return uses
+}
+
+// recordChannelUses classifies every use of the channels in a function body
+func recordChannelUses(body *ast.BlockStmt, uses map[*ast.Object]*channelUse) {
+	inspectWithStack(body, func(n ast.Node, stack []ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || ident.Obj == nil || uses[ident.Obj] == nil || len(stack) == 0 {
+			return true
+		}
+		use := uses[ident.Obj]
+		switch parent := stack[len(stack)-1].(type) {
+		case *ast.SendStmt:
+			if parent.Chan != ident {
+				use.escapes = true
+			} else if use.goroutineSend == token.NoPos && inGoroutine(stack) {
+				use.goroutineSend = parent.Pos()
+			}
+		case *ast.UnaryExpr:
+			if parent.Op != token.ARROW {
+				use.escapes = true
+				break
+			}
+			use.receives++
+			use.selectOnly = use.selectOnly && inSelectWithAlternatives(parent, stack)
+		case *ast.RangeStmt:
+			if parent.X == ident {
+				use.receives++
+				use.selectOnly = false
+			}
+		case *ast.CallExpr:
+			if fun, ok := parent.Fun.(*ast.Ident); !ok || (fun.Name != "close" && fun.Name != "len" && fun.Name != "cap") {
+				use.escapes = true
+			}
+		case *ast.AssignStmt:
+			if !isDefinition(parent, ident) {
+				use.escapes = true
+			}
+		default:
+			use.escapes = true
+		}
+		return true
+	})
+}
+
+// isUnbufferedMake reports whether an expression is make(chan T) without a capacity
+func isUnbufferedMake(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return false
+	}
+	fun, ok := call.Fun.(*ast.Ident)
+	if !ok || fun.Name != "make" {
+		return false
+	}
+	_, ok = call.Args[0].(*ast.ChanType)
+	return ok
+}
+
+// isDefinition reports whether ident is defined, rather than read, by an assignment
+func isDefinition(assign *ast.AssignStmt, ident *ast.Ident) bool {
+	for _, lhs := range assign.Lhs {
+		if lhs == ident {
+			return true
+		}
+	}
+	return false
+}
+
+// inGoroutine reports whether the innermost function literal on the stack is started by a go
+// statement
+func inGoroutine(stack []ast.Node) bool {
+	for i := len(stack) - 1; i > 0; i-- {
+		if _, ok := stack[i].(*ast.FuncLit); ok {
+			call, ok := stack[i-1].(*ast.CallExpr)
+			if !ok || i < 2 {
+				return false
+			}
+			_, ok = stack[i-2].(*ast.GoStmt)
+			return ok && call.Fun == stack[i]
+		}
+	}
+	return false
+}
+
+// inSelectWithAlternatives reports whether a receive is the communication of a select case next
+// to other cases, so the select may finish without it
+func inSelectWithAlternatives(recv *ast.UnaryExpr, stack []ast.Node) bool {
+	for i := len(stack) - 1; i > 1; i-- {
+		clause, ok := stack[i].(*ast.CommClause)
+		if !ok {
+			continue
+		}
+		if clause.Comm == nil || !contains(clause.Comm, recv) {
+			return false
+		}
+		sel, ok := stack[i-2].(*ast.SelectStmt)
+		return ok && len(sel.Body.List) > 1
+	}
+	return false
+}
+
+// contains reports whether node is within root
+func contains(root, node ast.Node) bool {
+	return root.Pos() <= node.Pos() && node.End() <= root.End()
+}
+
+// missingWaitGroupDone returns the first goroutine added to a wait group, by an Add call just
+// before the go statement or before its loop, whose function literal never calls Done on it or
+// calls Done without defer after a return that skips it
+func missingWaitGroupDone(body *ast.BlockStmt, fset *token.FileSet) rules.GoroutineLeak {
+	var leak rules.GoroutineLeak
+	ast.Inspect(body, func(n ast.Node) bool {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok || leak.Line != 0 {
+			return leak.Line == 0
+		}
+		for i := 1; i < len(block.List); i++ {
+			group := waitGroupAdd(block.List[i-1])
+			if group == "" {
+				continue
+			}
+			for _, goStmt := range addedGoroutines(block.List[i]) {
+				if kind := waitGroupDone(goStmt, group); kind != "" {
+					leak = rules.GoroutineLeak{Kind: kind, Name: group, Line: fset.Position(goStmt.Pos()).Line}
+					return false
+				}
+			}
+		}
+		return true
+	})
+	return leak
+}
+
+// waitGroupAdd returns the name of the wait group of a statement such as wg.Add(1)
+func waitGroupAdd(stmt ast.Stmt) string {
+	expr, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return ""
+	}
+	call, ok := expr.X.(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Add" || len(call.Args) != 1 {
+		return ""
+	}
+	if ident, ok := sel.X.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// addedGoroutines returns the go statement following an Add call, or the go statements
+// directly in the body of the loop that follows it
+func addedGoroutines(stmt ast.Stmt) []*ast.GoStmt {
+	var body *ast.BlockStmt
+	switch s := stmt.(type) {
+	case *ast.GoStmt:
+		return []*ast.GoStmt{s}
+	case *ast.ForStmt:
+		body = s.Body
+	case *ast.RangeStmt:
+		body = s.Body
+	default:
+		return nil
+	}
+	var goStmts []*ast.GoStmt
+	for _, s := range body.List {
+		if goStmt, ok := s.(*ast.GoStmt); ok {
+			goStmts = append(goStmts, goStmt)
+		}
+	}
+	return goStmts
+}
+
+// waitGroupDone classifies how a goroutine calls Done on a wait group: "" when it defers it,
+// calls it on every path or hands the wait group to other code, rules.LeakMissingDone when it
+// never calls it, and rules.LeakSkippedDone when a return comes before a Done that is not deferred
+func waitGroupDone(goStmt *ast.GoStmt, group string) string {
+	lit, ok := goStmt.Call.Fun.(*ast.FuncLit)
+	if !ok {
+		return ""
+	}
+
+	done, deferred, other := false, false, false
+	var firstDone, firstReturn token.Pos
+	inspectWithStack(lit.Body, func(n ast.Node, stack []ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if firstReturn == token.NoPos {
+				firstReturn = node.Pos()
+			}
+		case *ast.Ident:
+			if node.Name != group {
+				break
+			}
+			sel, ok := stack[len(stack)-1].(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Done" {
+				other = true
+				break
+			}
+			done = true
+			if firstDone == token.NoPos {
+				firstDone = node.Pos()
+			}
+			for _, parent := range stack {
+				if _, ok := parent.(*ast.DeferStmt); ok {
+					deferred = true
+				}
+			}
+		}
+		return true
+	})
+
+	switch {
+	case other || deferred:
+		return ""
+	case !done:
+		return rules.LeakMissingDone
+	case firstReturn != token.NoPos && firstReturn < firstDone:
+		return rules.LeakSkippedDone
+	}
+	return ""
+}
+
+// inspectWithStack is ast.Inspect with the ancestors of each node, innermost last
+func inspectWithStack(root ast.Node, visit func(n ast.Node, stack []ast.Node) bool) {
+	var stack []ast.Node
+	ast.Inspect(root, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		if !visit(n, stack) {
+			return false
+		}
+		stack = append(stack, n)
+		return true
+	})
+}
@@ -0,0 +1,135 @@
+package golang
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang/rules"
+)
+
+func TestFindGoroutineLeak(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want rules.GoroutineLeak // Line is relative to the first line of the body
+	}{
+		{
+			name: "goroutines in a loop without cancellation",
+			body: `for _, url := range urls {
+	go fetch(url)
+}`,
+			want: rules.GoroutineLeak{Kind: rules.LeakUncancelled, Line: 2},
+		},
+		{
+			name: "goroutines in a loop with a context",
+			body: `for _, url := range urls {
+	go fetch(ctx, url)
+}`,
+		},
+		{
+			name: "goroutines in a loop tracked by a wait group",
+			body: `for _, url := range urls {
+	wg.Add(1)
+	go func(url string) {
+		defer wg.Done()
+		fetch(url)
+	}(url)
+}`,
+		},
+		{
+			name: "send nothing receives",
+			body: `results := make(chan int)
+go func() {
+	results <- compute()
+}()
+return nil`,
+			want: rules.GoroutineLeak{Kind: rules.LeakUnreceived, Name: "results", Line: 3},
+		},
+		{
+			name: "send received in a select with a timeout",
+			body: `ch := make(chan int)
+go func() { ch <- compute() }()
+select {
+case v := <-ch:
+	return v
+case <-time.After(time.Second):
+	return 0
+}`,
+			want: rules.GoroutineLeak{Kind: rules.LeakAbandoned, Name: "ch", Line: 2},
+		},
+		{
+			name: "buffered channel",
+			body: `ch := make(chan int, 1)
+go func() { ch <- compute() }()
+select {
+case v := <-ch:
+	return v
+case <-time.After(time.Second):
+	return 0
+}`,
+		},
+		{
+			name: "send received",
+			body: `ch := make(chan int)
+go func() { ch <- compute() }()
+return <-ch`,
+		},
+		{
+			name: "channel returned to the caller",
+			body: `ch := make(chan int)
+go func() { ch <- compute() }()
+return ch`,
+		},
+		{
+			name: "goroutine never calls Done",
+			body: `wg.Add(1)
+go func() {
+	work()
+}()
+wg.Wait()`,
+			want: rules.GoroutineLeak{Kind: rules.LeakMissingDone, Name: "wg", Line: 2},
+		},
+		{
+			name: "goroutine returns before Done",
+			body: `for _, job := range jobs {
+	wg.Add(1)
+	go func(job Job) {
+		if err := job.Run(); err != nil {
+			return
+		}
+		wg.Done()
+	}(job)
+}
+wg.Wait()`,
+			want: rules.GoroutineLeak{Kind: rules.LeakSkippedDone, Name: "wg", Line: 3},
+		},
+		{
+			name: "goroutine hands the wait group on",
+			body: `wg.Add(1)
+go func() {
+	worker(&wg)
+}()
+wg.Wait()`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "package p\n\nfunc f() interface{} {\n" + tt.body + "\n}\n"
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "p.go", src, 0)
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+			want := tt.want
+			if want.Line != 0 {
+				want.Line += 3
+			}
+			if got := findGoroutineLeak(file.Decls[0].(*ast.FuncDecl), fset); got != want {
+				t.Errorf("findGoroutineLeak() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package golang
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DefaultIgnoreFunctionPatterns are the entry points the go tool calls by name, which the
+// unused function analysis skips unless configured otherwise
+var DefaultIgnoreFunctionPatterns = []string{"Test*", "Benchmark*", "Example*"}
+
+var defaultIgnoredFuncs, _ = compileNamePatterns(DefaultIgnoreFunctionPatterns)
+
+// namePattern matches function or receiver type names: a glob such as Handle* that must
+// match the whole name, or a regular expression between slashes such as /^run[A-Z]/
+type namePattern struct {
+	source string
+	glob   string
+	re     *regexp.Regexp
+}
+
+func (p namePattern) match(name string) bool {
+	if p.re != nil {
+		return p.re.MatchString(name)
+	}
+	matched, _ := path.Match(p.glob, name)
+	return matched
+}
+
+// compileNamePatterns compiles globs and /regexp/ patterns, reporting the first invalid one
+func compileNamePatterns(patterns []string) ([]namePattern, error) {
+	compiled := make([]namePattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		p := namePattern{source: pattern, glob: pattern}
+		if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+			p.re = re
+		} else if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, p)
+	}
+	return compiled, nil
+}
+
+// ValidateNamePatterns reports the first pattern SetIgnorePatterns would reject
+func ValidateNamePatterns(patterns []string) error {
+	_, err := compileNamePatterns(patterns)
+	return err
+}
+
+// matchesAny reports whether name matches one of the patterns
+func matchesAny(patterns []namePattern, name string) bool {
+	for _, p := range patterns {
+		if p.match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// patternSources returns the patterns as they were written
+func patternSources(patterns []namePattern) []string {
+	sources := make([]string, len(patterns))
+	for i, p := range patterns {
+		sources[i] = p.source
+	}
+	return sources
+}
+
+// SetIgnorePatterns replaces the functions the unused analysis never reports, by default
+// DefaultIgnoreFunctionPatterns, and skips every method of receiver types matching one of
+// receivers. This keeps entry points a framework finds by name or reflection, such as
+// Handle* handlers or a cobra command's run* functions, from being reported.
+func (a *CrossFileAnalyzer) SetIgnorePatterns(functions, receivers []string) error {
+	names, err := compileNamePatterns(functions)
+	if err != nil {
+		return err
+	}
+	receiverPatterns, err := compileNamePatterns(receivers)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.ignoredFuncs = names
+	a.ignoredRecvs = receiverPatterns
+	a.unused = nil // earlier findings were computed with the old patterns
+	return nil
+}
+
+// SetMinStatements keeps functions and methods with fewer than n statements from being
+// reported, such as the one-line helpers a project keeps around on purpose. 0, the default,
+// reports functions of any size.
+func (a *CrossFileAnalyzer) SetMinStatements(n int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.minStatements = n
+	a.unused = nil // earlier findings were computed with the old minimum
+}
@@ -0,0 +1,366 @@
+package golang
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// indexVersion is bumped whenever the layout or the meaning of a saved index changes
+const indexVersion = 7
+
+// ErrIndexMismatch is returned by LoadIndex for an index written by another version of the
+// analyzer or with different settings; the caller should analyze the project from scratch
+var ErrIndexMismatch = errors.New("cross-file index does not match this analyzer")
+
+// usageSet is a set of names that remembers which files use each one, so the uses of a file
+// can be withdrawn when it changes. Fields are exported for encoding/gob.
+type usageSet struct {
+	Files map[string]map[string]bool // name -> files using it
+	Names map[string]map[string]bool // file -> names it uses
+}
+
+func newUsageSet() usageSet {
+	return usageSet{
+		Files: make(map[string]map[string]bool),
+		Names: make(map[string]map[string]bool),
+	}
+}
+
+// add records that filePath uses name
+func (s *usageSet) add(name, filePath string) {
+	if s.Files == nil {
+		*s = newUsageSet()
+	}
+	if s.Files[name] == nil {
+		s.Files[name] = make(map[string]bool)
+	}
+	s.Files[name][filePath] = true
+	if s.Names[filePath] == nil {
+		s.Names[filePath] = make(map[string]bool)
+	}
+	s.Names[filePath][name] = true
+}
+
+// has reports whether any file uses name
+func (s *usageSet) has(name string) bool {
+	return len(s.Files[name]) > 0
+}
+
+// removeFile withdraws every use recorded for filePath
+func (s *usageSet) removeFile(filePath string) {
+	for name := range s.Names[filePath] {
+		delete(s.Files[name], filePath)
+		if len(s.Files[name]) == 0 {
+			delete(s.Files, name)
+		}
+	}
+	delete(s.Names, filePath)
+}
+
+// fileStamp identifies the contents of a file as of its last analysis
+type fileStamp struct {
+	ModTime int64 // nanoseconds since the epoch, which survive encoding unlike time.Time's zone
+	Size    int64
+}
+
+func stampOf(info os.FileInfo) fileStamp {
+	return fileStamp{ModTime: info.ModTime().UnixNano(), Size: info.Size()}
+}
+
+// Update brings the analyzer up to date with the Go, assembly and template files under dirPath after an
+// earlier AnalyzeDirectory or LoadIndex: new and modified files are analyzed again, deleted
+// files are forgotten and unchanged files are not read at all. It returns the files that
+// changed. When the modules under dirPath have changed, every file is analyzed again.
+func (a *CrossFileAnalyzer) Update(ctx context.Context, dirPath string) ([]string, error) {
+	modules, err := DiscoverModules(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	if !sameModules(a.modules, modules) {
+		a.reset()
+		a.modules = modules
+	}
+	a.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var changed []string
+	err = walkSourceFiles(dirPath, func(path string, info os.FileInfo) error {
+		seen[path] = true
+		a.mu.RLock()
+		stamp, ok := a.stamps[path]
+		a.mu.RUnlock()
+		if !ok || stamp != stampOf(info) {
+			changed = append(changed, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.RLock()
+	for path := range a.stamps {
+		if !seen[path] {
+			changed = append(changed, path)
+		}
+	}
+	a.mu.RUnlock()
+
+	sort.Strings(changed)
+	return changed, a.UpdateFiles(ctx, changed)
+}
+
+// UpdateFiles analyzes the given files again, for callers such as file watchers that already
+// know what changed. A file that no longer exists is forgotten.
+func (a *CrossFileAnalyzer) UpdateFiles(ctx context.Context, paths []string) error {
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		a.RemoveFile(path)
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err := a.analyzeSource(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveFile forgets the declarations, calls and references of a file, marking its package
+// for the next FindUnusedFunctions
+func (a *CrossFileAnalyzer) RemoveFile(filePath string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.removeFile(filePath)
+}
+
+func (a *CrossFileAnalyzer) removeFile(filePath string) {
+	if pkg, ok := a.packages[filePath]; ok {
+		a.stale[pkg] = true
+	}
+	if isAssemblyFile(filePath) {
+		a.markDirStale(filepath.Dir(filePath))
+	}
+	if isTemplateFile(filePath) {
+		a.markModuleStale(a.moduleDir(filePath))
+	}
+	delete(a.functions, filePath)
+	delete(a.platforms, filePath)
+	delete(a.packages, filePath)
+	delete(a.stamps, filePath)
+
+	for key, methods := range a.methods {
+		for name, funcInfo := range methods {
+			if funcInfo.File == filePath {
+				delete(methods, name)
+			}
+		}
+		if len(methods) == 0 {
+			delete(a.methods, key)
+		}
+	}
+
+	// Call indexes are keyed by "file:caller", see recordCall
+	prefix := filePath + ":"
+	for key := range a.calls {
+		if strings.HasPrefix(key, prefix) {
+			delete(a.calls, key)
+		}
+	}
+	for key := range a.methodCalls {
+		if strings.HasPrefix(key, prefix) {
+			delete(a.methodCalls, key)
+		}
+	}
+
+	a.funcReferences.removeFile(filePath)
+	a.assembly.removeFile(filePath)
+	a.templates.removeFile(filePath)
+	a.exported.removeFile(filePath)
+}
+
+// reset forgets every analyzed file
+func (a *CrossFileAnalyzer) reset() {
+	a.functions = make(map[string]map[string]*FunctionInfo)
+	a.methods = make(map[string]map[string]*FunctionInfo)
+	a.calls = make(map[string][]string)
+	a.methodCalls = make(map[string][]string)
+	a.funcReferences = newUsageSet()
+	a.assembly = newUsageSet()
+	a.templates = newUsageSet()
+	a.platforms = make(map[string]platformSet)
+	a.packages = make(map[string]string)
+	a.stamps = make(map[string]fileStamp)
+	a.exported = newExportedUsage()
+	a.unused = nil
+	a.stale = make(map[string]bool)
+	a.binaries = nil
+}
+
+// sameModules reports whether two module lists are identical
+func sameModules(a, b []Module) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// crossFileIndex is the saved form of a CrossFileAnalyzer, see SaveIndex
+type crossFileIndex struct {
+	Version          int
+	IncludeExported  bool
+	IgnoredNames     []string
+	IgnoredReceivers []string
+	MinStatements    int
+	Modules          []Module
+	Functions        map[string]map[string]*FunctionInfo
+	Methods          map[string]map[string]*FunctionInfo
+	Calls            map[string][]string
+	MethodCalls      map[string][]string
+	FuncReferences   usageSet
+	Assembly         usageSet
+	Templates        usageSet
+	Platforms        map[string]platformSet
+	Packages         map[string]string
+	Stamps           map[string]fileStamp
+	Types            []TypeInfo
+	Local            usageSet
+	Qualified        usageSet
+	ImportedBy       usageSet
+	Checked          bool // Unused holds findings; gob cannot tell an empty map from none
+	Unused           map[string][]core.Result
+	Stale            map[string]bool
+	Binaries         map[string]bool
+}
+
+// SaveIndex writes the analyzer's indexes and its latest findings, so a later run can
+// LoadIndex them and Update only the files changed in between
+func (a *CrossFileAnalyzer) SaveIndex(w io.Writer) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return gob.NewEncoder(w).Encode(crossFileIndex{
+		Version:          indexVersion,
+		IncludeExported:  a.includeExported,
+		IgnoredNames:     patternSources(a.ignoredFuncs),
+		IgnoredReceivers: patternSources(a.ignoredRecvs),
+		MinStatements:    a.minStatements,
+		Modules:          a.modules,
+		Functions:        a.functions,
+		Methods:          a.methods,
+		Calls:            a.calls,
+		MethodCalls:      a.methodCalls,
+		FuncReferences:   a.funcReferences,
+		Assembly:         a.assembly,
+		Templates:        a.templates,
+		Platforms:        a.platforms,
+		Packages:         a.packages,
+		Stamps:           a.stamps,
+		Types:            a.exported.types,
+		Local:            a.exported.local,
+		Qualified:        a.exported.qualified,
+		ImportedBy:       a.exported.importedBy,
+		Checked:          a.unused != nil,
+		Unused:           a.unused,
+		Stale:            a.stale,
+		Binaries:         a.binaries,
+	})
+}
+
+// LoadIndex replaces the analyzer's indexes with ones written by SaveIndex. Call Update
+// afterwards to catch up with files changed since the index was saved. It returns
+// ErrIndexMismatch when the index was saved by another version or with different
+// SetIncludeExported, SetIgnorePatterns or SetMinStatements settings.
+func (a *CrossFileAnalyzer) LoadIndex(r io.Reader) error {
+	var index crossFileIndex
+	if err := gob.NewDecoder(r).Decode(&index); err != nil {
+		return fmt.Errorf("failed to read cross-file index: %w", err)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if index.Version != indexVersion || index.IncludeExported != a.includeExported ||
+		!equalStrings(index.IgnoredNames, patternSources(a.ignoredFuncs)) ||
+		!equalStrings(index.IgnoredReceivers, patternSources(a.ignoredRecvs)) ||
+		index.MinStatements != a.minStatements {
+		return ErrIndexMismatch
+	}
+
+	a.reset()
+	a.modules = index.Modules
+	a.calls = orEmpty(index.Calls)
+	a.methodCalls = orEmpty(index.MethodCalls)
+	a.funcReferences = index.FuncReferences
+	a.assembly = index.Assembly
+	a.templates = index.Templates
+	a.platforms = orEmpty(index.Platforms)
+	a.packages = orEmpty(index.Packages)
+	a.stamps = orEmpty(index.Stamps)
+	a.exported.types = index.Types
+	a.exported.local = index.Local
+	a.exported.qualified = index.Qualified
+	a.exported.importedBy = index.ImportedBy
+	if index.Checked {
+		a.unused = orEmpty(index.Unused)
+	}
+	a.stale = orEmpty(index.Stale)
+	a.binaries = index.Binaries
+
+	// Decoding copies each module, so point declarations back at the analyzer's modules
+	for file, funcs := range index.Functions {
+		a.functions[file] = orEmpty(funcs)
+		for _, funcInfo := range funcs {
+			funcInfo.Module = a.moduleOf(funcInfo.File)
+		}
+	}
+	for key, methods := range index.Methods {
+		a.methods[key] = methods
+		for _, funcInfo := range methods {
+			funcInfo.Module = a.moduleOf(funcInfo.File)
+		}
+	}
+	for i := range a.exported.types {
+		a.exported.types[i].Module = a.moduleOf(a.exported.types[i].File)
+	}
+	return nil
+}
+
+// equalStrings reports whether two string lists are identical
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// orEmpty returns m, or an empty map when decoding left it nil
+func orEmpty[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return make(map[K]V)
+	}
+	return m
+}
@@ -0,0 +1,111 @@
+package golang
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// unusedMessages returns the sorted messages of the analyzer's findings
+func unusedMessages(analyzer *CrossFileAnalyzer) []string {
+	var messages []string
+	for _, r := range analyzer.FindUnusedFunctions() {
+		messages = append(messages, r.Message)
+	}
+	sort.Strings(messages)
+	return messages
+}
+
+func TestCrossFileAnalyzer_Update(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, "util"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	writeGoFiles(t, tmpDir, map[string]string{
+		"main.go":      "package main\n\nfunc main() { helper() }\n",
+		"helper.go":    "package main\n\nfunc helper() {}\n",
+		"util/util.go": "package util\n\nfunc orphan() {}\n",
+	})
+
+	analyzer := NewCrossFileAnalyzer()
+	if err := analyzer.AnalyzeDirectory(ctx, tmpDir); err != nil {
+		t.Fatalf("Failed to analyze directory: %v", err)
+	}
+	if got := unusedMessages(analyzer); len(got) != 1 {
+		t.Fatalf("Expected only orphan to be unused, got %v", got)
+	}
+
+	// Dropping the call makes helper unused; only main.go is analyzed again
+	writeGoFiles(t, tmpDir, map[string]string{"main.go": "package main\n\nfunc main() {}\n"})
+	changed, err := analyzer.Update(ctx, tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+	if len(changed) != 1 || filepath.Base(changed[0]) != "main.go" {
+		t.Errorf("Expected only main.go to change, got %v", changed)
+	}
+	if len(analyzer.stale) != 1 {
+		t.Errorf("Expected one package to be checked again, got %v", analyzer.stale)
+	}
+	want := []string{
+		"Function 'helper' is not called anywhere in the project",
+		"Function 'orphan' is not called anywhere in the project",
+	}
+	if got := unusedMessages(analyzer); !equalStrings(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// Deleting a file forgets its declarations
+	if err := os.Remove(filepath.Join(tmpDir, "util", "util.go")); err != nil {
+		t.Fatalf("Failed to remove util.go: %v", err)
+	}
+	if _, err := analyzer.Update(ctx, tmpDir); err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+	if got := unusedMessages(analyzer); !equalStrings(got, want[:1]) {
+		t.Errorf("Expected %v, got %v", want[:1], got)
+	}
+
+	// A saved index restores the findings without analyzing unchanged files again
+	var buf bytes.Buffer
+	if err := analyzer.SaveIndex(&buf); err != nil {
+		t.Fatalf("Failed to save index: %v", err)
+	}
+	restored := NewCrossFileAnalyzer()
+	if err := restored.LoadIndex(&buf); err != nil {
+		t.Fatalf("Failed to load index: %v", err)
+	}
+	changed, err = restored.Update(ctx, tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+	if len(changed) != 0 {
+		t.Errorf("Expected no changed files after loading the index, got %v", changed)
+	}
+	if got := unusedMessages(restored); !equalStrings(got, want[:1]) {
+		t.Errorf("Expected %v, got %v", want[:1], got)
+	}
+}
+
+func TestCrossFileAnalyzer_LoadIndexMismatch(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewCrossFileAnalyzer().SaveIndex(&buf); err != nil {
+		t.Fatalf("Failed to save index: %v", err)
+	}
+
+	analyzer := NewCrossFileAnalyzer()
+	analyzer.SetIncludeExported(true)
+	if err := analyzer.LoadIndex(bytes.NewReader(buf.Bytes())); err != ErrIndexMismatch {
+		t.Errorf("Expected ErrIndexMismatch, got %v", err)
+	}
+
+	analyzer = NewCrossFileAnalyzer()
+	analyzer.SetMinStatements(2)
+	if err := analyzer.LoadIndex(bytes.NewReader(buf.Bytes())); err != ErrIndexMismatch {
+		t.Errorf("Expected ErrIndexMismatch for another minimum size, got %v", err)
+	}
+}
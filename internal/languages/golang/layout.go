@@ -0,0 +1,385 @@
+package golang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// Rules reported by the LayoutAnalyzer
+const (
+	GenericPackageRuleID = "generic-package-name"
+	GenericFileRuleID    = "generic-file-name"
+	StubFileRuleID       = "stub-files"
+	PackageNameRuleID    = "package-name-mismatch"
+)
+
+func init() {
+	enabled := core.RuleOption{Key: "rules.layout.enabled", Flag: "-enable-layout", Default: "true", Description: "Report package and file layout smells"}
+	docs := []core.RuleDoc{
+		{
+			ID:          GenericPackageRuleID,
+			Name:        "Generic Package Name",
+			Description: "Detects projects with several catch-all packages such as utils and helpers",
+			Rationale: "Code written one request at a time lands in a new helpers package next to the " +
+				"existing utils one, so related functions end up split between packages whose names say " +
+				"nothing about them. Each such package is reported once the project has two or more.",
+			Severity: core.SeverityInfo,
+			Examples: []core.RuleExample{{
+				Bad:  "internal/utils/strings.go\ninternal/helpers/format.go",
+				Good: "internal/textfmt/strings.go\ninternal/textfmt/format.go",
+			}},
+			Options: []core.RuleOption{enabled,
+				{Key: "rules.layout.genericPackages", Flag: "-generic-packages", Default: "utils,util,helpers,helper,common,misc", Description: "Catch-all package names"},
+			},
+		},
+		{
+			ID:          GenericFileRuleID,
+			Name:        "Generic File Name",
+			Description: "Detects files named misc.go, stuff.go and the like",
+			Rationale: "A file whose name says nothing about its contents collects whatever did not fit " +
+				"elsewhere, and readers cannot find code by file name. Trailing digits are ignored, so " +
+				"stuff2.go is reported too; generated files are skipped.",
+			Severity: core.SeverityInfo,
+			Examples: []core.RuleExample{{
+				Bad:  "server/misc.go",
+				Good: "server/timeouts.go",
+			}},
+			Options: []core.RuleOption{enabled,
+				{Key: "rules.layout.genericFiles", Flag: "-generic-files", Default: "misc,stuff,things,other,temp,tmp,various,extra", Description: "File names, without .go or trailing digits, that say nothing"},
+			},
+		},
+		{
+			ID:          StubFileRuleID,
+			Name:        "Stub Files",
+			Description: "Detects packages with all their code in one file next to files that declare nothing",
+			Rationale: "Files planned for later stay empty while one file keeps growing, so the layout " +
+				"promises a split the code does not have. Files with a package comment, a build " +
+				"constraint or a blank import are not stubs.",
+			Severity: core.SeverityWarning,
+			Examples: []core.RuleExample{{
+				Bad:  "store/store.go   // 900 lines\nstore/cache.go   // package store, nothing else",
+				Good: "store/store.go\nstore/cache.go   // the caching half of store.go",
+			}},
+			Options: []core.RuleOption{enabled,
+				{Key: "rules.layout.checkStubFiles", Flag: "-check-stub-files", Default: "true", Description: "Report packages with one file of code next to empty files"},
+			},
+		},
+		{
+			ID:          PackageNameRuleID,
+			Name:        "Package Name Mismatch",
+			Description: "Detects packages named differently from their directory",
+			Rationale: "Import paths name the directory, so readers expect the package to have the same " +
+				"name. Names are compared without case, dashes, dots and underscores, a major version " +
+				"directory may use its parent's name and main packages are skipped.",
+			Severity: core.SeverityWarning,
+			Examples: []core.RuleExample{{
+				Bad:  "// internal/storage/db.go\npackage database",
+				Good: "// internal/storage/db.go\npackage storage",
+			}},
+			Options: []core.RuleOption{enabled,
+				{Key: "rules.layout.checkPackageNames", Flag: "-check-package-names", Default: "true", Description: "Report packages named differently from their directory"},
+			},
+		},
+	}
+	for _, doc := range docs {
+		doc.Category = core.CategoryStyle
+		doc.Languages = []string{"go"}
+		core.RegisterRuleDoc(doc)
+	}
+}
+
+// majorVersionDir matches the directory of a module's major version, such as v2
+var majorVersionDir = regexp.MustCompile(`^v[0-9]+$`)
+
+// LayoutAnalyzer reports structural smells in how a project's Go code is split into packages
+// and files: several catch-all packages such as utils and helpers, files with names such as
+// misc.go, packages that keep all their code in one file next to empty stubs, and packages
+// named differently from their directory
+type LayoutAnalyzer struct {
+	fset   *token.FileSet
+	cache  *ASTCache // shared parse cache, see SetCache
+	config core.LayoutConfig
+}
+
+// layoutPackage is the non-test Go files of one directory
+type layoutPackage struct {
+	dir   string
+	name  string
+	files []layoutFile // ordered by path
+}
+
+// layoutFile is what the layout rules need to know about a file
+type layoutFile struct {
+	path        string
+	packageLine int
+	lines       int
+	empty       bool // declares nothing but imports
+	stub        bool // empty and without another purpose, see isStubFile
+	generated   bool
+}
+
+// NewLayoutAnalyzer creates a layout analyzer
+func NewLayoutAnalyzer(config core.LayoutConfig) *LayoutAnalyzer {
+	return &LayoutAnalyzer{
+		fset:   token.NewFileSet(),
+		config: config,
+	}
+}
+
+// SetCache makes the analyzer take ASTs from a cache shared with the other Go analyses
+func (a *LayoutAnalyzer) SetCache(cache *ASTCache) {
+	a.cache = cache
+	a.fset = cache.FileSet()
+}
+
+// Analyze checks the packages of the project's Go files under root. Findings are kept for the
+// files in analyzed only, but every package counts towards the catch-all package names.
+func (a *LayoutAnalyzer) Analyze(root string, files []string, modules []Module, analyzed map[string]bool) []core.Result {
+	packages := a.loadPackages(files)
+
+	var results []core.Result
+	results = append(results, a.checkGenericPackages(root, packages, analyzed)...)
+	for _, pkg := range packages {
+		results = append(results, a.checkGenericFiles(pkg, analyzed)...)
+		if a.config.CheckStubFiles {
+			results = append(results, checkStubFiles(pkg, analyzed)...)
+		}
+		if a.config.CheckPackageNames {
+			results = append(results, checkPackageName(pkg, modules, analyzed)...)
+		}
+	}
+	return results
+}
+
+// loadPackages parses the non-test files and groups them by directory, in directory order
+func (a *LayoutAnalyzer) loadPackages(files []string) []*layoutPackage {
+	byDir := make(map[string]*layoutPackage)
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parseWithCache(a.cache, a.fset, path)
+		if err != nil {
+			continue
+		}
+
+		dir := filepath.Dir(path)
+		pkg := byDir[dir]
+		if pkg == nil {
+			pkg = &layoutPackage{dir: dir, name: f.Name.Name}
+			byDir[dir] = pkg
+		}
+		pkg.files = append(pkg.files, layoutFile{
+			path:        path,
+			packageLine: a.fset.Position(f.Package).Line,
+			lines:       a.fset.File(f.Pos()).LineCount(),
+			empty:       declaresNothing(f),
+			stub:        isStubFile(f),
+			generated:   ast.IsGenerated(f),
+		})
+	}
+
+	packages := make([]*layoutPackage, 0, len(byDir))
+	for _, pkg := range byDir {
+		sort.Slice(pkg.files, func(i, j int) bool { return pkg.files[i].path < pkg.files[j].path })
+		packages = append(packages, pkg)
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].dir < packages[j].dir })
+	return packages
+}
+
+// declaresNothing reports whether a file has no declarations besides its imports
+func declaresNothing(f *ast.File) bool {
+	for _, decl := range f.Decls {
+		if gen, ok := decl.(*ast.GenDecl); !ok || gen.Tok != token.IMPORT {
+			return false
+		}
+	}
+	return true
+}
+
+// isStubFile reports whether a file declares nothing and has no other purpose. Files that
+// document the package, carry a build constraint or import packages for their side effects
+// are useful without declarations and are not stubs.
+func isStubFile(f *ast.File) bool {
+	if !declaresNothing(f) || f.Doc != nil || fileConstraint(f) != nil {
+		return false
+	}
+	for _, decl := range f.Decls {
+		for _, spec := range decl.(*ast.GenDecl).Specs {
+			if imp := spec.(*ast.ImportSpec); imp.Name != nil && imp.Name.Name == "_" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// firstAnalyzed returns the first of the files that is analyzed, or nil
+func firstAnalyzed(files []layoutFile, analyzed map[string]bool) *layoutFile {
+	for i := range files {
+		if analyzed[files[i].path] {
+			return &files[i]
+		}
+	}
+	return nil
+}
+
+// checkGenericPackages reports every package with a catch-all name when the project has two
+// or more of them. A single utils package is common enough to leave alone; several mean
+// helpers are piling up wherever the last change put them.
+func (a *LayoutAnalyzer) checkGenericPackages(root string, packages []*layoutPackage, analyzed map[string]bool) []core.Result {
+	var generic []*layoutPackage
+	for _, pkg := range packages {
+		if containsString(a.config.GenericPackages, strings.ToLower(pkg.name)) {
+			generic = append(generic, pkg)
+		}
+	}
+	if len(generic) < 2 {
+		return nil
+	}
+
+	dirs := make([]string, len(generic))
+	for i, pkg := range generic {
+		dirs[i] = pkg.dir
+		if rel, err := filepath.Rel(root, pkg.dir); err == nil {
+			dirs[i] = filepath.ToSlash(rel)
+		}
+	}
+
+	var results []core.Result
+	for _, pkg := range generic {
+		file := firstAnalyzed(pkg.files, analyzed)
+		if file == nil {
+			continue
+		}
+		result := layoutResult(GenericPackageRuleID, "Generic Package Name", core.SeverityInfo, file)
+		result.Message = fmt.Sprintf("Package %s is one of %d catch-all packages: %s", pkg.name, len(generic), strings.Join(dirs, ", "))
+		result.Suggestion = "Move each helper next to the code that uses it, or into a package named after what it provides"
+		results = append(results, result)
+	}
+	return results
+}
+
+// checkGenericFiles reports files whose name says nothing about their contents, such as
+// misc.go or stuff2.go
+func (a *LayoutAnalyzer) checkGenericFiles(pkg *layoutPackage, analyzed map[string]bool) []core.Result {
+	var results []core.Result
+	for i, file := range pkg.files {
+		if !analyzed[file.path] || file.generated {
+			continue
+		}
+		stem := strings.ToLower(strings.TrimSuffix(filepath.Base(file.path), ".go"))
+		stem = strings.TrimRight(stem, "0123456789_-")
+		if !containsString(a.config.GenericFiles, stem) {
+			continue
+		}
+		result := layoutResult(GenericFileRuleID, "Generic File Name", core.SeverityInfo, &pkg.files[i])
+		result.Message = fmt.Sprintf("File name %s says nothing about what the file contains", filepath.Base(file.path))
+		result.Suggestion = "Rename the file after the types or functions it declares, or move them into the files of the code that uses them"
+		results = append(results, result)
+	}
+	return results
+}
+
+// checkStubFiles reports a package that keeps every declaration in one file next to files
+// that declare nothing, once, at the first of those stubs
+func checkStubFiles(pkg *layoutPackage, analyzed map[string]bool) []core.Result {
+	var code *layoutFile
+	var stubs []layoutFile
+	for i, file := range pkg.files {
+		switch {
+		case file.stub && !file.generated:
+			stubs = append(stubs, file)
+		case file.empty:
+		case code != nil:
+			return nil
+		default:
+			code = &pkg.files[i]
+		}
+	}
+	if code == nil || len(stubs) == 0 {
+		return nil
+	}
+	stub := firstAnalyzed(stubs, analyzed)
+	if stub == nil {
+		return nil
+	}
+
+	names := make([]string, len(stubs))
+	for i, file := range stubs {
+		names[i] = filepath.Base(file.path)
+	}
+	result := layoutResult(StubFileRuleID, "Stub Files", core.SeverityWarning, stub)
+	result.Message = fmt.Sprintf("Package %s keeps all its code in %s (%d lines) next to files that declare nothing: %s",
+		pkg.name, filepath.Base(code.path), code.lines, strings.Join(names, ", "))
+	result.Suggestion = fmt.Sprintf("Delete the empty files, or split %s and move its declarations into them", filepath.Base(code.path))
+	return []core.Result{result}
+}
+
+// checkPackageName reports a package named differently from its directory. Main packages are
+// skipped. The directory name is compared without the dashes, dots and go- prefix or -go
+// suffix that cannot appear in package names; a major version directory such as v2 and the
+// root of a module are compared by the name they stand for.
+func checkPackageName(pkg *layoutPackage, modules []Module, analyzed map[string]bool) []core.Result {
+	if pkg.name == "main" {
+		return nil
+	}
+	file := firstAnalyzed(pkg.files, analyzed)
+	if file == nil {
+		return nil
+	}
+
+	dir := pkg.dir
+	expected := []string{filepath.Base(dir)}
+	if majorVersionDir.MatchString(expected[0]) {
+		expected = append(expected, filepath.Base(filepath.Dir(dir)))
+	}
+	if module := FindModule(modules, file.path); module != nil && module.Dir == dir {
+		elems := strings.Split(module.Path, "/")
+		last := elems[len(elems)-1]
+		if majorVersionDir.MatchString(last) && len(elems) > 1 {
+			last = elems[len(elems)-2]
+		}
+		expected = append(expected, last)
+	}
+	for _, name := range expected {
+		if normalizePackageName(name) == normalizePackageName(pkg.name) {
+			return nil
+		}
+	}
+
+	result := layoutResult(PackageNameRuleID, "Package Name Mismatch", core.SeverityWarning, file)
+	result.Message = fmt.Sprintf("Package name %s does not match its directory %s", pkg.name, filepath.Base(dir))
+	result.Suggestion = "Rename the package or its directory so that import paths tell readers which name to use"
+	return []core.Result{result}
+}
+
+// normalizePackageName reduces a package or directory name to the letters and digits a
+// package name can be compared by
+func normalizePackageName(name string) string {
+	name = strings.ToLower(name)
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(strings.TrimSuffix(name, "-go"), ".go")
+	return strings.NewReplacer("-", "", ".", "", "_", "").Replace(name)
+}
+
+// layoutResult returns a finding at the package clause of a file, for the caller to add the
+// message and suggestion to
+func layoutResult(ruleID, name string, severity core.Severity, file *layoutFile) core.Result {
+	return core.Result{
+		RuleID:   ruleID,
+		RuleName: name,
+		Category: string(core.CategoryStyle),
+		Severity: string(severity),
+		FilePath: file.path,
+		Line:     file.packageLine,
+	}
+}
@@ -0,0 +1,111 @@
+package golang
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+func TestNormalizePackageName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"go-yaml", "yaml"},
+		{"yaml-go", "yaml"},
+		{"client.go", "client"},
+		{"http_util", "httputil"},
+		{"Store", "store"},
+	}
+	for _, tt := range tests {
+		if got := normalizePackageName(tt.name); got != tt.want {
+			t.Errorf("normalizePackageName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLayoutAnalyzer(t *testing.T) {
+	root := t.TempDir()
+	var files []string
+	write := func(name, content string) string {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, path)
+		return path
+	}
+
+	write("go.mod", "module example.com/go-shop\n")
+	write("shop.go", "package shop\n\nfunc Open() {}\n")
+	apiUtils := write("api/utils/strings.go", "package utils\n\nfunc Trim() {}\n")
+	dbHelpers := write("db/helpers/helpers.go", "package helpers\n\nfunc Quote() {}\n")
+	misc := write("db/misc2.go", "package db\n\nfunc Close() {}\n")
+	write("db/db.go", "package db\n\nfunc Open() {}\n")
+	write("cache/cache.go", "package cache\n\nfunc Get() {}\n\nfunc Set() {}\n")
+	stub := write("cache/keys.go", "package cache\n\nimport \"strings\"\n")
+	write("cache/doc.go", "// Package cache keeps values in memory\npackage cache\n")
+	write("cache/register.go", "package cache\n\nimport _ \"embed\"\n")
+	mismatch := write("orders/store.go", "package order_store\n\nfunc Save() {}\n")
+	write("orders/v2/store.go", "package orders\n\nfunc Save() {}\n")
+	write("cmd/tool/main.go", "package main\n\nfunc main() {}\n")
+
+	var goFiles []string
+	analyzed := make(map[string]bool)
+	for _, file := range files {
+		if strings.HasSuffix(file, ".go") {
+			goFiles = append(goFiles, file)
+			analyzed[file] = true
+		}
+	}
+	modules := []Module{{Path: "example.com/go-shop", Dir: root}}
+
+	config := core.LayoutConfig{
+		Enabled:           true,
+		GenericPackages:   []string{"utils", "helpers"},
+		GenericFiles:      []string{"misc"},
+		CheckStubFiles:    true,
+		CheckPackageNames: true,
+	}
+	results := NewLayoutAnalyzer(config).Analyze(root, goFiles, modules, analyzed)
+
+	found := make(map[string]core.Result)
+	for _, result := range results {
+		found[result.RuleID+" "+result.FilePath] = result
+	}
+	if len(results) != 5 {
+		t.Errorf("Expected 5 findings, got %d: %v", len(results), results)
+	}
+	for _, want := range []string{
+		GenericPackageRuleID + " " + apiUtils,
+		GenericPackageRuleID + " " + dbHelpers,
+		GenericFileRuleID + " " + misc,
+		StubFileRuleID + " " + stub,
+		PackageNameRuleID + " " + mismatch,
+	} {
+		if _, ok := found[want]; !ok {
+			t.Errorf("Expected finding %s", want)
+		}
+	}
+
+	if result := found[GenericPackageRuleID+" "+apiUtils]; !strings.HasSuffix(result.Message, "2 catch-all packages: api/utils, db/helpers") {
+		t.Errorf("Unexpected message: %s", result.Message)
+	}
+	if result := found[StubFileRuleID+" "+stub]; !strings.HasSuffix(result.Message, "cache.go (5 lines) next to files that declare nothing: keys.go") {
+		t.Errorf("Expected only keys.go to count as a stub, got: %s", result.Message)
+	}
+
+	// a single catch-all package is left alone, and findings are kept for analyzed files only
+	config.GenericPackages = []string{"utils"}
+	config.CheckStubFiles = false
+	results = NewLayoutAnalyzer(config).Analyze(root, goFiles, modules, map[string]bool{apiUtils: true, mismatch: true})
+	if len(results) != 1 || results[0].RuleID != PackageNameRuleID {
+		t.Errorf("Expected only the package name finding, got %v", results)
+	}
+}
@@ -0,0 +1,160 @@
+package golang
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang/rules"
+)
+
+// syncLockTypes are the types of the sync package that must not be copied after first use
+var syncLockTypes = map[string]bool{"Mutex": true, "RWMutex": true, "WaitGroup": true, "Once": true}
+
+// lockTypes maps the names of the struct types declared in a file that hold a lock by value,
+// directly or through another such struct, to the lock they hold, such as "sync.Mutex"
+func lockTypes(file *ast.File) map[string]string {
+	syncName := importedName(file, "sync")
+	if syncName == "" {
+		return nil
+	}
+
+	structs := make(map[string]*ast.StructType)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+				if st, ok := typeSpec.Type.(*ast.StructType); ok {
+					structs[typeSpec.Name.Name] = st
+				}
+			}
+		}
+	}
+
+	// structs may hold each other, so repeat until no more lock holders are found
+	locks := make(map[string]string)
+	for changed := true; changed; {
+		changed = false
+		for name, st := range structs {
+			if locks[name] != "" {
+				continue
+			}
+			for _, field := range st.Fields.List {
+				if lock := heldLock(field.Type, syncName, locks); lock != "" {
+					locks[name] = lock
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return locks
+}
+
+// heldLock returns the lock a value of type expr holds, or "" if it holds none or is a pointer
+func heldLock(expr ast.Expr, syncName string, locks map[string]string) string {
+	switch t := expr.(type) {
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok && pkg.Name == syncName && syncLockTypes[t.Sel.Name] {
+			return "sync." + t.Sel.Name
+		}
+	case *ast.Ident:
+		return locks[t.Name]
+	}
+	return ""
+}
+
+// findMutexCopy returns the first receiver, parameter or result of a function whose type holds
+// a lock by value, so that calling the function copies the lock
+func findMutexCopy(funcDecl *ast.FuncDecl, fset *token.FileSet, file *ast.File) rules.MutexCopy {
+	syncName := importedName(file, "sync")
+	if syncName == "" {
+		return rules.MutexCopy{}
+	}
+	locks := lockTypes(file)
+
+	fields := []struct {
+		role string
+		list *ast.FieldList
+	}{
+		{rules.CopyReceiver, funcDecl.Recv},
+		{rules.CopyParameter, funcDecl.Type.Params},
+		{rules.CopyResult, funcDecl.Type.Results},
+	}
+	for _, f := range fields {
+		if f.list == nil {
+			continue
+		}
+		for _, field := range f.list.List {
+			if lock := heldLock(field.Type, syncName, locks); lock != "" {
+				return rules.MutexCopy{
+					Role: f.role,
+					Type: typeName(field.Type, syncName),
+					Lock: lock,
+					Line: fset.Position(field.Pos()).Line,
+				}
+			}
+		}
+	}
+	return rules.MutexCopy{}
+}
+
+// typeName returns the name of a named type as written
+func typeName(expr ast.Expr, syncName string) string {
+	switch t := expr.(type) {
+	case *ast.SelectorExpr:
+		return syncName + "." + t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// importedName returns the name under which a file imports a package, or "" if it does not or
+// imports it with a blank or dot import
+func importedName(file *ast.File, importPath string) string {
+	for _, spec := range file.Imports {
+		if spec.Path.Value != `"`+importPath+`"` {
+			continue
+		}
+		if spec.Name == nil {
+			return importName(importPath)
+		}
+		if spec.Name.Name != "_" && spec.Name.Name != "." {
+			return spec.Name.Name
+		}
+	}
+	return ""
+}
+
+// findDeferInLoop returns the line of the first defer statement directly inside a loop of a
+// function, not counting defers in function literals called from the loop
+func findDeferInLoop(funcDecl *ast.FuncDecl, fset *token.FileSet) int {
+	if funcDecl.Body == nil {
+		return 0
+	}
+
+	line := 0
+	inspectWithStack(funcDecl.Body, func(n ast.Node, stack []ast.Node) bool {
+		if line != 0 {
+			return false
+		}
+		deferStmt, ok := n.(*ast.DeferStmt)
+		if !ok {
+			return true
+		}
+		for i := len(stack) - 1; i >= 0; i-- {
+			switch stack[i].(type) {
+			case *ast.FuncLit:
+				return true
+			case *ast.ForStmt, *ast.RangeStmt:
+				line = fset.Position(deferStmt.Pos()).Line
+				return false
+			}
+		}
+		return true
+	})
+	return line
+}
@@ -0,0 +1,132 @@
+package golang
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Module is a Go module, identified by its go.mod file
+type Module struct {
+	Path string // module path declared in go.mod
+	Dir  string // absolute directory containing go.mod
+}
+
+// DiscoverModules finds the Go modules under root, including the module enclosing root
+// itself when root is a subdirectory of one. Modules are ordered by directory.
+func DiscoverModules(root string) ([]Module, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []Module
+	if dir, ok := enclosingModuleDir(filepath.Dir(root)); ok && !hasGoMod(root) {
+		if module, err := readModule(dir); err == nil {
+			modules = append(modules, module)
+		}
+	}
+
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != root && shouldSkipDir(info.Name()) {
+			return filepath.SkipDir
+		}
+		if hasGoMod(path) {
+			module, err := readModule(path)
+			if err != nil {
+				return err
+			}
+			modules = append(modules, module)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Dir < modules[j].Dir })
+	return modules, nil
+}
+
+// FindModule returns the innermost module containing filePath, or nil if it is in none
+func FindModule(modules []Module, filePath string) *Module {
+	var found *Module
+	for i := range modules {
+		if isWithin(filePath, modules[i].Dir) && (found == nil || len(modules[i].Dir) > len(found.Dir)) {
+			found = &modules[i]
+		}
+	}
+	return found
+}
+
+// SelectModule returns the module whose path or directory matches name. Directories may be
+// given relative to the working directory.
+func SelectModule(modules []Module, name string) *Module {
+	absName, _ := filepath.Abs(name)
+	for i := range modules {
+		if modules[i].Path == name || modules[i].Dir == absName {
+			return &modules[i]
+		}
+	}
+	return nil
+}
+
+// enclosingModuleDir walks up from dir to the nearest directory holding a go.mod file
+func enclosingModuleDir(dir string) (string, bool) {
+	for {
+		if hasGoMod(dir) {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+func hasGoMod(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, "go.mod"))
+	return err == nil && !info.IsDir()
+}
+
+// readModule reads the module path from the go.mod file in dir
+func readModule(dir string) (Module, error) {
+	f, err := os.Open(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return Module{}, err
+	}
+	defer f.Close()
+
+	module := Module{Dir: dir}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+		if rest, ok := strings.CutPrefix(line, "module"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			module.Path = strings.TrimSpace(rest)
+			if unquoted, err := strconv.Unquote(module.Path); err == nil {
+				module.Path = unquoted
+			}
+			break
+		}
+	}
+	return module, scanner.Err()
+}
+
+// isWithin reports whether path is dir or lies below it
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -6,7 +6,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
 )
@@ -44,6 +46,29 @@ func TestParallelAnalyzer(t *testing.T) {
 	}
 }
 
+func TestParallelAnalyzerCollectsFileErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	goodFile := filepath.Join(tmpDir, "good.go")
+	badFile := filepath.Join(tmpDir, "bad.go")
+	os.WriteFile(goodFile, []byte("package main\n\nfunc main() {}\n"), 0644)
+	os.WriteFile(badFile, []byte("package main\n\nfunc {\n"), 0644)
+
+	config := setupTestConfigForParallel()
+	analyzer := NewParallelAnalyzer(config, 2)
+
+	_, fileErrors := analyzer.AnalyzeFilesWithErrors(context.Background(), []string{goodFile, badFile}, config)
+
+	if len(fileErrors) != 1 {
+		t.Fatalf("Expected 1 file error, got %d: %v", len(fileErrors), fileErrors)
+	}
+	if fileErrors[0].FilePath != badFile {
+		t.Errorf("Expected error for %s, got %s", badFile, fileErrors[0].FilePath)
+	}
+	if fileErrors[0].Message == "" {
+		t.Error("Expected a non-empty error message")
+	}
+}
+
 func setupTestConfigForParallel() core.Config {
 	return core.Config{
 		Rules: core.RulesConfig{
@@ -99,6 +124,227 @@ func TestASTCache(t *testing.T) {
 	}
 }
 
+func TestASTCache_SharedParse(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	mainFile := filepath.Join(tmpDir, "main.go")
+	os.WriteFile(mainFile, []byte("package main\n\nfunc main() {\n\thelper()\n}\n\nfunc helper() {}\n\nfunc unused() {}\n"), 0644)
+
+	cache := NewASTCache(0)
+	config := core.Config{Rules: core.RulesConfig{FunctionSize: core.FunctionSizeConfig{Enabled: true, MaxLines: 50}}}
+	analyzer := NewAnalyzer(config)
+	analyzer.SetCache(cache)
+	if _, err := analyzer.Analyze(context.Background(), mainFile, config); err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	parsed := cache.Stats().Misses
+
+	crossFile := NewCrossFileAnalyzer()
+	crossFile.SetCache(cache)
+	if err := crossFile.AnalyzeDirectory(context.Background(), tmpDir); err != nil {
+		t.Fatalf("AnalyzeDirectory failed: %v", err)
+	}
+	if stats := cache.Stats(); stats.Misses != parsed || stats.Hits == 0 {
+		t.Errorf("Expected the cross-file analysis to reuse the cached AST, got %d hits and %d misses", stats.Hits, stats.Misses)
+	}
+
+	results := crossFile.FindUnusedFunctions()
+	if len(results) != 1 || results[0].Line != 9 {
+		t.Errorf("Expected 'unused' reported on line 9 through the shared FileSet, got %v", results)
+	}
+
+	// An edited file is parsed again
+	os.WriteFile(mainFile, []byte("package main\n\nfunc main() {}\n"), 0644)
+	os.Chtimes(mainFile, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+	file, _, err := cache.Parse(mainFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(file.Decls) != 1 {
+		t.Errorf("Expected the edited file to be parsed again with 1 declaration, got %d", len(file.Decls))
+	}
+}
+
+func TestASTCache_Eviction(t *testing.T) {
+	tmpDir := t.TempDir()
+	var files []string
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		file := filepath.Join(tmpDir, name)
+		os.WriteFile(file, []byte("package main\n"), 0644)
+		files = append(files, file)
+	}
+
+	cache := NewASTCache(0)
+	cache.SetMaxEntries(2)
+	for _, file := range files[:2] {
+		if _, _, err := cache.Parse(file); err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+	}
+	cache.Parse(files[0])
+	cache.Parse(files[2])
+
+	if _, _, ok := cache.Get(files[1]); ok {
+		t.Error("Expected the least recently used file to be evicted")
+	}
+	if _, _, ok := cache.Get(files[0]); !ok {
+		t.Error("Expected the file used again to stay cached")
+	}
+	stats := cache.Stats()
+	if stats.Entries != 2 || stats.Evictions != 1 {
+		t.Errorf("Expected 2 entries and 1 eviction, got %+v", stats.CacheStats)
+	}
+	if stats.Hits != 2 || stats.Misses != 4 || stats.HitRate() != 2.0/6 {
+		t.Errorf("Expected 2 hits and 4 misses, got %+v", stats.CacheStats)
+	}
+}
+
+func TestASTCache_Recycle(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "main.go")
+	os.WriteFile(file, []byte("package main\n\nfunc main() {}\n"), 0644)
+
+	cache := NewASTCache(0)
+	if _, _, err := cache.Parse(file); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if cache.Recycle() {
+		t.Error("Expected no new FileSet without a size limit")
+	}
+
+	old := cache.FileSet()
+	cache.SetMaxFileSetSize(1)
+	if !cache.Recycle() {
+		t.Fatal("Expected a new FileSet once the old one is past the limit")
+	}
+	if cache.FileSet() == old || cache.Size() != 0 {
+		t.Errorf("Expected a new FileSet and no entries, got %d entries", cache.Size())
+	}
+	if cache.Recycle() {
+		t.Error("Expected an empty FileSet to be kept")
+	}
+
+	_, fset, err := cache.Parse(file)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if fset != cache.FileSet() {
+		t.Error("Expected files to be parsed into the new FileSet")
+	}
+}
+
+// TestAnalyzerConcurrentUse analyzes the same files from many goroutines through one
+// Analyzer, with and without a cache; run it with -race
+func TestAnalyzerConcurrentUse(t *testing.T) {
+	tmpDir := t.TempDir()
+	var files []string
+	for i := 0; i < 4; i++ {
+		lines := []string{"package main", "", "import \"time\"", "", fmt.Sprintf("func function%d() {", i), "\ttime.Sleep(time.Second)"}
+		for j := 0; j < 60; j++ {
+			lines = append(lines, fmt.Sprintf("\tline%d := %d", j, j))
+		}
+		lines = append(lines, "}")
+		file := filepath.Join(tmpDir, fmt.Sprintf("file%d.go", i))
+		os.WriteFile(file, []byte(strings.Join(lines, "\n")), 0644)
+		files = append(files, file)
+	}
+	config := setupTestConfigForParallel()
+
+	for _, tt := range []struct {
+		name  string
+		cache *ASTCache
+	}{
+		{"cache", NewASTCache(0)},
+		{"no cache", nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := NewAnalyzer(config)
+			analyzer.SetCache(tt.cache)
+			want := make([]int, len(files))
+			for i, file := range files {
+				results, err := analyzer.Analyze(context.Background(), file, config)
+				if err != nil {
+					t.Fatalf("Analyze failed: %v", err)
+				}
+				want[i] = len(results)
+			}
+
+			var wg sync.WaitGroup
+			errs := make(chan error, 8*len(files))
+			for g := 0; g < 8; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i, file := range files {
+						results, err := analyzer.Analyze(context.Background(), file, config)
+						if err != nil {
+							errs <- err
+						} else if len(results) != want[i] {
+							errs <- fmt.Errorf("%s: expected %d results, got %d", file, want[i], len(results))
+						}
+						if tt.cache != nil && g == 0 {
+							analyzer.FlushCache()
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestParserFileSetPerCall(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "main.go")
+	os.WriteFile(file, []byte("package main\n\nfunc main() {}\n"), 0644)
+
+	parser := NewParser(core.Config{})
+	parser.SetCache(nil)
+	_, first, err := parser.ParseFile(context.Background(), file)
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+	_, second, err := parser.ParseFile(context.Background(), file)
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+	if first == second {
+		t.Error("Expected each uncached parse to use a FileSet of its own")
+	}
+	if base := second.Base(); base != first.Base() {
+		t.Errorf("Expected the second FileSet to hold only its own file, got base %d, want %d", base, first.Base())
+	}
+}
+
+func TestAnalyzer_FunctionRange(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "main.go")
+	src := "package main\n\nfunc short() {}\n\nfunc long() {\n\t_ = 1\n\t_ = 2\n\t_ = \"é\"}\n"
+	if err := os.WriteFile(filePath, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := core.Config{Rules: core.RulesConfig{FunctionSize: core.FunctionSizeConfig{Enabled: true, MaxLines: 3}}}
+	results, err := NewAnalyzer(config).Analyze(context.Background(), filePath, config)
+	if err != nil {
+		t.Fatalf("Failed to analyze: %v", err)
+	}
+
+	var found []core.Result
+	for _, result := range results {
+		if result.RuleID == "large-function" {
+			found = append(found, result)
+		}
+	}
+	// The brace closing the function is the ninth character of its line but the tenth byte
+	if len(found) != 1 || found[0].Line != 5 || found[0].EndLine != 8 || found[0].EndColumn != 10 {
+		t.Errorf("Expected the function to span line 5 to line 8, column 10, got %+v", found)
+	}
+}
+
 func TestCrossFileAnalyzer(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -185,6 +431,120 @@ func handleData() {
 	}
 }
 
+func TestSimilarityAnalyzer_MinSize(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeGoFiles(t, tmpDir, map[string]string{
+		"getters.go": `package main
+
+func (c *config) name() string { return c.n }
+
+func (c *config) path() string { return c.p }
+`,
+		"process.go": `package main
+
+func processData(items []int) int {
+	total := 0
+	for _, item := range items {
+		if item > 0 {
+			total += item
+		}
+	}
+	return total
+}
+
+func sumPositive(values []int) int {
+	sum := 0
+	for _, v := range values {
+		if v > 0 {
+			sum += v
+		}
+	}
+	return sum
+}
+`,
+	})
+
+	analyzer := NewSimilarityAnalyzer()
+	analyzer.SetMinSize(3, 5)
+	results, err := analyzer.AnalyzeDirectory(context.Background(), tmpDir, 0.8)
+	if err != nil {
+		t.Fatalf("Failed to analyze directory: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected only the two loops to be reported, got %v", results)
+	}
+	want := filepath.Join(tmpDir, "process.go")
+	if results[0].FilePath != want || results[0].Line != 3 {
+		t.Errorf("Expected the finding at %s:3, got %s:%d", want, results[0].FilePath, results[0].Line)
+	}
+	if related := results[0].Related; related == nil || related.FilePath != want || related.Line != 13 {
+		t.Errorf("Expected the second function at %s:13, got %+v", want, related)
+	}
+	if msg := "Function 'processData' is 100% similar to 'sumPositive' at process.go:13"; results[0].Message != msg {
+		t.Errorf("Expected %q, got %q", msg, results[0].Message)
+	}
+}
+
+func TestSimilarityAnalyzer_Order(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeGoFiles(t, tmpDir, map[string]string{
+		"order.go": `package main
+
+func load(path string) error {
+	data, err := read(path)
+	if err != nil {
+		return err
+	}
+	for _, line := range split(data) {
+		parse(line)
+	}
+	return nil
+}
+
+func save(path string) error {
+	for _, line := range lines() {
+		write(line)
+	}
+	data, err := encode(path)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+`,
+	})
+
+	// Both bodies consist of the same tokens, in a different order
+	results, err := NewSimilarityAnalyzer().AnalyzeDirectory(context.Background(), tmpDir, 0.8)
+	if err != nil {
+		t.Fatalf("Failed to analyze directory: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected functions with the same tokens in another order not to be similar, got %v", results)
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	tokens := strings.Fields("IF EXPR RETURN FOR ASSIGN CALL RETURN")
+	tests := []struct {
+		name  string
+		other string
+		want  float64
+	}{
+		{"same", "IF EXPR RETURN FOR ASSIGN CALL RETURN", 1},
+		{"one token changed", "IF EXPR RETURN FOR ASSIGN CALL BREAK", 0.6},
+		{"nothing shared", "SWITCH CALL CALL ASSIGN", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jaccard(shingles(tokens), shingles(strings.Fields(tt.other))); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
 func BenchmarkLargeAnalysis(b *testing.B) {
 	tmpDir := b.TempDir()
 
@@ -218,3 +578,48 @@ func function%d() {
 		analyzer.AnalyzeFiles(context.Background(), files, config)
 	}
 }
+
+func TestParser_PackageMethodCounts(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"store.go":         "package store\n\ntype Store struct{}\n\nfunc (s *Store) Get() {}\n",
+		"store_extra.go":   "package store\n\ntype Option struct{}\n\nfunc (s *Store) Put() {}\n",
+		"store_linux.go":   "package store\n\nfunc (s *Store) Sync() {}\n",
+		"store_windows.go": "package store\n\nfunc (s *Store) Sync() {}\n",
+		"gen.go":           "//go:build ignore\n\npackage store\n\nfunc (s *Store) Gen() {}\n",
+		"store_test.go":    "package store\n\nfunc (s *Store) Fake() {}\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	parser := NewParser(core.Config{})
+	ctx := context.Background()
+	filePath := filepath.Join(tmpDir, "store.go")
+	file, fset, err := parser.ParseFile(ctx, filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metrics := parser.CalculateTypeMetrics(ctx, filePath, file, fset)
+	// Get, Put and Sync: the platform files are never built together, and neither the ignored
+	// file nor the test file is part of the package
+	if len(metrics) != 1 || metrics[0].MethodCount != 3 {
+		t.Fatalf("Expected Store to have 3 methods, got %+v", metrics)
+	}
+
+	// the siblings are read once per directory, not once per file measured
+	entry := parser.methods[tmpDir]
+	extraPath := filepath.Join(tmpDir, "store_extra.go")
+	extra, fset, err := parser.ParseFile(ctx, extraPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metrics := parser.CalculateTypeMetrics(ctx, extraPath, extra, fset); len(metrics) != 1 || metrics[0].MethodCount != 0 {
+		t.Errorf("Expected Option to have no methods, got %+v", metrics)
+	}
+	if len(parser.methods) != 1 || parser.methods[tmpDir] != entry || len(entry.files) != 5 {
+		t.Errorf("Expected one index of the 5 non-test files, got %d directories", len(parser.methods))
+	}
+}
@@ -10,79 +10,197 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
 	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang/rules"
 )
 
 type cachedFile struct {
 	file     *ast.File
 	fset     *token.FileSet
-	modTime  time.Time
+	modTime  time.Time // modification time of the file when it was parsed
+	size     int64     // size of the file when it was parsed
+	cachedAt time.Time
 	filePath string
 }
 
+// ASTCache parses each Go file once for every analysis that needs it: the per-file Analyzer,
+// the CrossFileAnalyzer and the SimilarityAnalyzer. Entries are keyed by path and checked
+// against the file's modification time and size, so an edited file is parsed again. Files
+// parsed by Parse share one FileSet, so positions from any cached AST resolve through it.
+// An ASTCache is safe for concurrent use; goroutines that miss on the same file may both
+// parse it, and the last one stored wins.
+//
+// A cache kept across runs, as by a worker, would otherwise grow forever: SetMaxEntries evicts
+// the least recently used files beyond a cap, and Recycle starts a new FileSet once the old one
+// has grown past SetMaxFileSetSize, since a FileSet keeps every file ever added to it.
 type ASTCache struct {
-	cache  map[string]*cachedFile
-	mu     sync.RWMutex
-	maxAge time.Duration
+	cache          map[string]*cachedFile
+	lru            *languages.LRU
+	fset           *token.FileSet
+	mu             sync.RWMutex
+	maxAge         time.Duration
+	maxFileSetSize int // FileSet size, in bytes of source, past which Recycle starts a new one
+	hits           int64
+	misses         int64
+	evictions      int64
 }
 
+// NewASTCache creates a cache whose entries expire maxAge after they were stored; 0 keeps them
+// for 5 minutes, longer than any single analysis run
 func NewASTCache(maxAge time.Duration) *ASTCache {
 	if maxAge == 0 {
 		maxAge = 5 * time.Minute
 	}
 	return &ASTCache{
 		cache:  make(map[string]*cachedFile),
+		lru:    languages.NewLRU(0),
+		fset:   token.NewFileSet(),
 		maxAge: maxAge,
 	}
 }
 
-func (c *ASTCache) Get(filePath string) (*ast.File, *token.FileSet, bool) {
+// SetMaxEntries caps the number of files cached, evicting the least recently used ones beyond
+// it; 0, the default, caches any number
+func (c *ASTCache) SetMaxEntries(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evict(c.lru.SetCapacity(n))
+}
+
+// SetMaxFileSetSize sets the size of the FileSet, in bytes of the source added to it, past
+// which Recycle starts a new one; 0, the default, never does
+func (c *ASTCache) SetMaxFileSetSize(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxFileSetSize = n
+}
+
+// FileSet returns the FileSet that files parsed by Parse are added to
+func (c *ASTCache) FileSet() *token.FileSet {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
+	return c.fset
+}
 
-	cached, exists := c.cache[filePath]
-	if !exists {
-		return nil, nil, false
+// Parse returns the AST of a Go file parsed with comments, from the cache when the file has
+// not changed since it was stored. A file with syntax errors is returned with its partial AST
+// and the error, and is not cached.
+func (c *ASTCache) Parse(filePath string) (*ast.File, *token.FileSet, error) {
+	if file, fset, ok := c.Get(filePath); ok {
+		return file, fset, nil
 	}
 
-	if time.Since(cached.modTime) > c.maxAge {
-		delete(c.cache, filePath)
-		return nil, nil, false
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+	fset := c.FileSet()
+	file, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
+	if err != nil {
+		return file, fset, err
+	}
+	c.Set(filePath, file, fset)
+	return file, fset, nil
+}
+
+// Get returns the cached AST of a file, unless the entry has expired or the file has been
+// modified since it was stored
+func (c *ASTCache) Get(filePath string) (*ast.File, *token.FileSet, bool) {
+	c.mu.RLock()
+	cached, exists := c.cache[filePath]
+	c.mu.RUnlock()
+
+	if exists && time.Since(cached.cachedAt) <= c.maxAge {
+		if stat, err := os.Stat(filePath); err == nil && stat.ModTime().Equal(cached.modTime) && stat.Size() == cached.size {
+			c.mu.Lock()
+			if c.cache[filePath] == cached {
+				c.lru.Touch(filePath)
+			}
+			c.mu.Unlock()
+			atomic.AddInt64(&c.hits, 1)
+			return cached.file, cached.fset, true
+		}
 	}
 
-	return cached.file, cached.fset, true
+	if exists {
+		c.invalidateEntry(filePath, cached)
+	}
+	atomic.AddInt64(&c.misses, 1)
+	return nil, nil, false
 }
 
+// Set stores the AST of a file along with its current modification time and size
 func (c *ASTCache) Set(filePath string, file *ast.File, fset *token.FileSet) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	stat, err := os.Stat(filePath)
 	if err != nil {
 		return
 	}
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.cache[filePath] = &cachedFile{
 		file:     file,
 		fset:     fset,
 		modTime:  stat.ModTime(),
+		size:     stat.Size(),
+		cachedAt: time.Now(),
 		filePath: filePath,
 	}
+	c.evict(c.lru.Touch(filePath))
+}
+
+// evict drops the entries the LRU has let go of; c.mu must be held
+func (c *ASTCache) evict(filePaths []string) {
+	for _, filePath := range filePaths {
+		delete(c.cache, filePath)
+	}
+	atomic.AddInt64(&c.evictions, int64(len(filePaths)))
 }
 
 func (c *ASTCache) Invalidate(filePath string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	delete(c.cache, filePath)
+	c.lru.Remove(filePath)
+}
+
+// invalidateEntry removes a stale entry unless another goroutine has replaced it since it was
+// read
+func (c *ASTCache) invalidateEntry(filePath string, stale *cachedFile) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cache[filePath] == stale {
+		delete(c.cache, filePath)
+		c.lru.Remove(filePath)
+	}
 }
 
+// InvalidateAll drops every entry. Files parsed afterwards are still added to the same
+// FileSet, so analyses holding it keep resolving positions; see Recycle for a new one.
 func (c *ASTCache) InvalidateAll() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.cache = make(map[string]*cachedFile)
+	c.lru.Clear()
+}
+
+// Recycle drops every entry and starts a new FileSet when the current one has grown past the
+// size set by SetMaxFileSetSize, and reports whether it did. Call it between runs: analyses
+// that took the old FileSet from FileSet would not find files parsed into the new one.
+func (c *ASTCache) Recycle() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxFileSetSize == 0 || c.fset.Base() <= c.maxFileSetSize {
+		return false
+	}
+	c.cache = make(map[string]*cachedFile)
+	c.lru.Clear()
+	c.fset = token.NewFileSet()
+	return true
 }
 
 func (c *ASTCache) Size() int {
@@ -96,15 +214,21 @@ func (c *ASTCache) Stats() CacheStats {
 	defer c.mu.RUnlock()
 
 	stats := CacheStats{
-		Entries: len(c.cache),
+		CacheStats: languages.CacheStats{
+			Entries:   len(c.cache),
+			Hits:      atomic.LoadInt64(&c.hits),
+			Misses:    atomic.LoadInt64(&c.misses),
+			Evictions: atomic.LoadInt64(&c.evictions),
+		},
+		FileSetSize: c.fset.Base(),
 	}
 
 	for _, cached := range c.cache {
-		age := time.Since(cached.modTime)
+		age := time.Since(cached.cachedAt)
 		if age > stats.MaxAge {
 			stats.MaxAge = age
 		}
-		if age < stats.MinAge {
+		if stats.MinAge == 0 || age < stats.MinAge {
 			stats.MinAge = age
 		}
 		stats.TotalAge += age
@@ -118,73 +242,104 @@ func (c *ASTCache) Stats() CacheStats {
 }
 
 type CacheStats struct {
-	Entries  int
-	MaxAge   time.Duration
-	MinAge   time.Duration
-	AvgAge   time.Duration
-	TotalAge time.Duration
+	languages.CacheStats
+	FileSetSize int // bytes of source added to the FileSet since it was created
+	MaxAge      time.Duration
+	MinAge      time.Duration
+	AvgAge      time.Duration
+	TotalAge    time.Duration
+}
+
+// parseWithCache parses a file through cache when there is one, and otherwise into fset
+func parseWithCache(cache *ASTCache, fset *token.FileSet, filePath string) (*ast.File, error) {
+	if cache != nil {
+		file, _, err := cache.Parse(filePath)
+		return file, err
+	}
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return parser.ParseFile(fset, filePath, src, parser.ParseComments)
 }
 
+// Parser parses Go files and computes their metrics. A Parser is safe for concurrent use once
+// it is configured: call SetCache before sharing it between goroutines. The methods declared in
+// a package directory are read once per Parser, which therefore lives for one run.
 type Parser struct {
-	fset   *token.FileSet
 	config core.Config
 	cache  *ASTCache
+
+	methodsMu sync.Mutex
+	methods   map[string]*packageMethods // by package directory
+}
+
+// packageMethods holds the methods declared in each non-test file of a directory, read once
+// for every file of the directory whose types are measured
+type packageMethods struct {
+	once  sync.Once
+	files []fileMethods
+}
+
+type fileMethods struct {
+	path      string
+	pkg       string
+	platforms platformSet    // build configs the file is compiled in, see filePlatforms
+	counts    map[string]int // methods by receiver type
 }
 
 func NewParser(config core.Config) *Parser {
 	return &Parser{
-		fset:   token.NewFileSet(),
-		config: config,
-		cache:  NewASTCache(0),
+		config:  config,
+		cache:   NewASTCache(0),
+		methods: make(map[string]*packageMethods),
 	}
 }
 
+// SetCache replaces the parser's cache; nil parses every file afresh. It must not be called
+// while ParseFile is running.
 func (p *Parser) SetCache(cache *ASTCache) {
 	p.cache = cache
 }
 
+// ParseFile parses a Go file through the parser's cache, or directly when it has none. On a
+// syntax error the partial AST is still returned for tolerant analysis. Without a cache each
+// call parses into a FileSet of its own, which is returned with the AST, so positions never
+// accumulate across files.
 func (p *Parser) ParseFile(ctx context.Context, filePath string) (*ast.File, *token.FileSet, error) {
-	if p.cache != nil {
-		if file, fset, ok := p.cache.Get(filePath); ok {
-			return file, fset, nil
-		}
-	}
-
 	if p.shouldIgnoreFile(filePath) {
 		return nil, nil, fmt.Errorf("file ignored: %s", filePath)
 	}
 
-	src, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	if p.cache != nil {
+		return p.cache.Parse(filePath)
 	}
 
-	file, err := parser.ParseFile(p.fset, filePath, src, parser.ParseComments)
+	src, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, nil, err
-	}
-
-	if p.cache != nil {
-		p.cache.Set(filePath, file, p.fset)
+		return nil, nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
 	}
-
-	return file, p.fset, nil
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
+	return file, fset, err
 }
 
 func (p *Parser) shouldIgnoreFile(filePath string) bool {
-	if p.config.Language.Go.IgnoreTests {
-		base := filepath.Base(filePath)
-		if strings.HasSuffix(base, "_test.go") {
-			return true
-		}
-	}
+	return IgnoreReason(filePath, p.config) != ""
+}
 
+// IgnoreReason returns why the analyzer skips a Go file: underscore-prefixed files, which the
+// go tool ignores, and test files when Language.Go.IgnoreTests is set. It returns "" for
+// files that are analyzed.
+func IgnoreReason(filePath string, config core.Config) string {
 	base := filepath.Base(filePath)
+	if config.Language.Go.IgnoreTests && strings.HasSuffix(base, "_test.go") {
+		return "test file, ignored by -ignore-tests"
+	}
 	if strings.HasPrefix(base, "_") {
-		return true
+		return "name starts with _, ignored by the go tool"
 	}
-
-	return false
+	return ""
 }
 
 func (p *Parser) CalculateMetrics(ctx context.Context, filePath string, file *ast.File) (*rules.FileMetrics, error) {
@@ -213,6 +368,8 @@ func (p *Parser) CalculateMetrics(ctx context.Context, filePath string, file *as
 		FunctionCount: astCounts.functions,
 		ImportCount:   astCounts.imports,
 		ExportedCount: astCounts.exported,
+		Generated:     languages.IsGenerated(lines),
+		Vendored:      languages.IsVendored(filePath),
 	}, nil
 }
 
@@ -279,21 +436,24 @@ func (p *Parser) CalculateFunctionMetrics(ctx context.Context, funcDecl *ast.Fun
 		Exported:             funcDecl.Name.IsExported(),
 		IsMainPackage:        isMainPackage,
 		LineCount:            lineCount,
+		StatementCount:       countStatements(funcDecl),
 		ParameterCount:       countParams(funcDecl),
 		ReturnCount:          countReturns(funcDecl),
 		CyclomaticComplexity: p.calculateCyclomaticComplexity(funcDecl),
 		NestingDepth:         calculateNestingDepth(funcDecl),
+		NakedReturnLine:      findNakedReturnLine(funcDecl, fset),
+		BranchChain:          longestBranchChain(funcDecl, fset),
+		SyncSleep:            findSyncSleep(funcDecl, fset, file),
+		GoroutineLeak:        findGoroutineLeak(funcDecl, fset),
+		MutexCopy:            findMutexCopy(funcDecl, fset, file),
+		DeferInLoopLine:      findDeferInLoop(funcDecl, fset),
 		Position:             start,
+		End:                  end,
 	}, nil
 }
 
 func getReceiverName(funcDecl *ast.FuncDecl) string {
-	if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
-		if ident, ok := funcDecl.Recv.List[0].Type.(*ast.Ident); ok {
-			return ident.Name
-		}
-	}
-	return ""
+	return getReceiverTypeName(funcDecl)
 }
 
 func countParams(funcDecl *ast.FuncDecl) int {
@@ -303,11 +463,236 @@ func countParams(funcDecl *ast.FuncDecl) int {
 	return 0
 }
 
+// countReturns counts result values, so "(a, b int, err error)" is three
 func countReturns(funcDecl *ast.FuncDecl) int {
-	if funcDecl.Type.Results != nil {
-		return len(funcDecl.Type.Results.List)
+	if funcDecl.Type.Results == nil {
+		return 0
 	}
-	return 0
+	count := 0
+	for _, field := range funcDecl.Type.Results.List {
+		if len(field.Names) == 0 {
+			count++
+		} else {
+			count += len(field.Names)
+		}
+	}
+	return count
+}
+
+// findNakedReturnLine returns the line of the first bare return in a function with named
+// results, ignoring returns inside nested function literals
+func findNakedReturnLine(funcDecl *ast.FuncDecl, fset *token.FileSet) int {
+	results := funcDecl.Type.Results
+	if funcDecl.Body == nil || results == nil || len(results.List) == 0 || len(results.List[0].Names) == 0 {
+		return 0
+	}
+
+	line := 0
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		if line != 0 {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(node.Results) == 0 {
+				line = fset.Position(node.Pos()).Line
+			}
+		}
+		return true
+	})
+	return line
+}
+
+// countStatements counts the statements in a function body, including those nested in
+// blocks and function literals. Blocks and empty statements are not counted themselves.
+func countStatements(funcDecl *ast.FuncDecl) int {
+	if funcDecl.Body == nil {
+		return 0
+	}
+
+	count := 0
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.BlockStmt, *ast.EmptyStmt:
+		case ast.Stmt:
+			count++
+		}
+		return true
+	})
+	return count
+}
+
+// longestBranchChain returns the switch, type switch or if-else chain with the most branches
+// in a function, including those in function literals. An if-else chain counts the if and each
+// else if and else clause hanging off it.
+func longestBranchChain(funcDecl *ast.FuncDecl, fset *token.FileSet) rules.BranchChain {
+	var longest rules.BranchChain
+	if funcDecl.Body == nil {
+		return longest
+	}
+
+	record := func(kind string, branches int, pos token.Pos) {
+		if branches > longest.Branches {
+			longest = rules.BranchChain{Kind: kind, Branches: branches, Line: fset.Position(pos).Line}
+		}
+	}
+	elseIfs := make(map[*ast.IfStmt]bool)
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SwitchStmt:
+			record("switch", len(node.Body.List), node.Pos())
+		case *ast.TypeSwitchStmt:
+			record("type switch", len(node.Body.List), node.Pos())
+		case *ast.IfStmt:
+			if elseIfs[node] {
+				break
+			}
+			branches := 1
+			for clause := node; clause != nil; {
+				switch next := clause.Else.(type) {
+				case *ast.IfStmt:
+					elseIfs[next] = true
+					branches++
+					clause = next
+				case *ast.BlockStmt:
+					branches++
+					clause = nil
+				default:
+					clause = nil
+				}
+			}
+			record("if-else chain", branches, node.Pos())
+		}
+		return true
+	})
+	return longest
+}
+
+// findSyncSleep returns the first time.Sleep in a function that waits for other code: one that
+// follows a go statement in the same function or function literal, or a polling loop whose
+// body does nothing but sleep and check its exit condition
+func findSyncSleep(funcDecl *ast.FuncDecl, fset *token.FileSet, file *ast.File) rules.SleepCall {
+	timeName := importedName(file, "time")
+	if funcDecl.Body == nil || timeName == "" {
+		return rules.SleepCall{}
+	}
+
+	var found rules.SleepCall
+	bodies := []*ast.BlockStmt{funcDecl.Body}
+	for len(bodies) > 0 && found.Line == 0 {
+		body := bodies[0]
+		bodies = bodies[1:]
+
+		// each function literal is checked on its own, so a goroutine's own sleeps do not count
+		started := false
+		polling := make(map[ast.Node]bool)
+		ast.Inspect(body, func(n ast.Node) bool {
+			if found.Line != 0 {
+				return false
+			}
+			switch node := n.(type) {
+			case *ast.FuncLit:
+				bodies = append(bodies, node.Body)
+				return false
+			case *ast.GoStmt:
+				started = true
+			case *ast.ForStmt:
+				if call := pollingSleep(node, timeName); call != nil {
+					polling[call] = true
+				}
+			case *ast.CallExpr:
+				if !isSleepCall(node, timeName) {
+					break
+				}
+				if polling[node] {
+					found = rules.SleepCall{Reason: "in a polling loop", Line: fset.Position(node.Pos()).Line}
+				} else if started {
+					found = rules.SleepCall{Reason: "after starting a goroutine", Line: fset.Position(node.Pos()).Line}
+				}
+			}
+			return true
+		})
+	}
+	return found
+}
+
+// isSleepCall reports whether a call is time.Sleep
+func isSleepCall(call *ast.CallExpr, timeName string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Sleep" {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == timeName
+}
+
+// pollingSleep returns the sleep call of a loop that waits for a condition, such as
+// "for !done { time.Sleep(d) }" or a loop that sleeps next to "if done { break }". Loops
+// whose conditions call functions other than Load methods and len are retries, not polls.
+func pollingSleep(loop *ast.ForStmt, timeName string) *ast.CallExpr {
+	if loop.Init != nil || loop.Post != nil || (loop.Cond != nil && !isPlainCondition(loop.Cond)) {
+		return nil
+	}
+
+	var sleep *ast.CallExpr
+	for _, stmt := range loop.Body.List {
+		switch s := stmt.(type) {
+		case *ast.ExprStmt:
+			call, ok := s.X.(*ast.CallExpr)
+			if !ok || !isSleepCall(call, timeName) || sleep != nil {
+				return nil
+			}
+			sleep = call
+		case *ast.IfStmt:
+			if s.Init != nil || s.Else != nil || !isPlainCondition(s.Cond) || !exitsLoop(s.Body) {
+				return nil
+			}
+		default:
+			return nil
+		}
+	}
+	if loop.Cond == nil && len(loop.Body.List) < 2 {
+		return nil
+	}
+	return sleep
+}
+
+// isPlainCondition reports whether a condition reads state without doing work: it calls
+// nothing but Load methods, such as atomic loads, and len
+func isPlainCondition(cond ast.Expr) bool {
+	plain := true
+	ast.Inspect(cond, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return plain
+		}
+		switch fun := call.Fun.(type) {
+		case *ast.Ident:
+			plain = fun.Name == "len"
+		case *ast.SelectorExpr:
+			plain = strings.HasPrefix(fun.Sel.Name, "Load")
+		default:
+			plain = false
+		}
+		return plain
+	})
+	return plain
+}
+
+// exitsLoop reports whether a block does nothing but break or return
+func exitsLoop(block *ast.BlockStmt) bool {
+	if len(block.List) != 1 {
+		return false
+	}
+	switch s := block.List[0].(type) {
+	case *ast.BranchStmt:
+		return s.Tok == token.BREAK
+	case *ast.ReturnStmt:
+		return true
+	}
+	return false
 }
 
 func (p *Parser) calculateCyclomaticComplexity(funcDecl *ast.FuncDecl) int {
@@ -379,3 +764,130 @@ func countSelectCases(selectStmt *ast.SelectStmt) int {
 	}
 	return count
 }
+
+// CalculateTypeMetrics returns metrics for each type declared in the file. Method counts
+// include methods declared in the other non-test files of the same package built with it,
+// since Go types are frequently extended across several files.
+func (p *Parser) CalculateTypeMetrics(ctx context.Context, filePath string, file *ast.File, fset *token.FileSet) []*rules.TypeMetrics {
+	var metrics []*rules.TypeMetrics
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			m := &rules.TypeMetrics{
+				Name:     typeSpec.Name.Name,
+				Position: fset.Position(typeSpec.Pos()),
+			}
+			if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+				m.IsStruct = true
+				m.FieldCount = countFields(structType)
+			}
+			metrics = append(metrics, m)
+		}
+	}
+
+	if len(metrics) == 0 {
+		return metrics
+	}
+
+	methodCounts := p.countPackageMethods(ctx, filePath, file)
+	for _, m := range metrics {
+		m.MethodCount = methodCounts[m.Name]
+	}
+	return metrics
+}
+
+// countFields counts struct fields, treating each name in "a, b int" and each embedded type as one field
+func countFields(structType *ast.StructType) int {
+	count := 0
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 {
+			count++
+		} else {
+			count += len(field.Names)
+		}
+	}
+	return count
+}
+
+// countPackageMethods counts methods per receiver type across the file and the non-test files
+// of its package built with it. Methods are counted in each build config the file is compiled
+// in and the largest count is kept, so a method declared once per platform, as in foo_linux.go
+// and foo_windows.go, counts once, and files excluded from every build count for nothing.
+func (p *Parser) countPackageMethods(ctx context.Context, filePath string, file *ast.File) map[string]int {
+	own := make(map[string]int)
+	addMethodCounts(own, file)
+	platforms := filePlatforms(file, filePath)
+	if platforms == 0 {
+		platforms = allPlatforms // a file built nowhere, such as a program run by go generate, is measured with every sibling
+	}
+	siblings := p.packageMethods(ctx, filepath.Dir(filePath))
+
+	counts := make(map[string]int)
+	for config := platformSet(1); config <= allPlatforms; config <<= 1 {
+		if platforms&config == 0 {
+			continue
+		}
+		configCounts := make(map[string]int, len(own))
+		for receiver, n := range own {
+			configCounts[receiver] = n
+		}
+		for _, sibling := range siblings {
+			if sibling.path == filePath || sibling.pkg != file.Name.Name || sibling.platforms&config == 0 {
+				continue
+			}
+			for receiver, n := range sibling.counts {
+				configCounts[receiver] += n
+			}
+		}
+		for receiver, n := range configCounts {
+			counts[receiver] = max(counts[receiver], n)
+		}
+	}
+	return counts
+}
+
+// packageMethods returns the methods declared in the non-test Go files of dir, reading them
+// the first time any file of dir asks
+func (p *Parser) packageMethods(ctx context.Context, dir string) []fileMethods {
+	p.methodsMu.Lock()
+	entry, ok := p.methods[dir]
+	if !ok {
+		entry = &packageMethods{}
+		p.methods[dir] = entry
+	}
+	p.methodsMu.Unlock()
+
+	entry.once.Do(func() {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			return
+		}
+		for _, path := range paths {
+			if strings.HasSuffix(path, "_test.go") {
+				continue
+			}
+			file, _, err := p.ParseFile(ctx, path)
+			if err != nil {
+				continue
+			}
+			counts := make(map[string]int)
+			addMethodCounts(counts, file)
+			entry.files = append(entry.files, fileMethods{path: path, pkg: file.Name.Name, platforms: filePlatforms(file, path), counts: counts})
+		}
+	})
+	return entry.files
+}
+
+func addMethodCounts(counts map[string]int, file *ast.File) {
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+			if receiver := getReceiverTypeName(funcDecl); receiver != "" {
+				counts[receiver]++
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+const defaultMaxBranches = 10
+
+// BranchChain is the switch or if-else chain with the most branches in a function
+type BranchChain struct {
+	Kind     string // "switch", "type switch" or "if-else chain"
+	Branches int    // case clauses including default, or if, else if and else clauses
+	Line     int
+}
+
+// LongBranchChainRule detects switch statements and if-else chains with too many branches
+type LongBranchChainRule struct {
+	config core.Config
+}
+
+// NewLongBranchChainRule creates a new long branch chain rule
+func NewLongBranchChainRule(config core.Config) *LongBranchChainRule {
+	return &LongBranchChainRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *LongBranchChainRule) ID() string {
+	return "long-branch-chain"
+}
+
+// Name returns the name of this rule
+func (r *LongBranchChainRule) Name() string {
+	return "Long Branch Chain"
+}
+
+// Description returns a description of this rule
+func (r *LongBranchChainRule) Description() string {
+	return "Detects switch statements and if-else chains with too many branches"
+}
+
+// Rationale explains why this rule exists
+func (r *LongBranchChainRule) Rationale() string {
+	return "A switch or if-else chain that grows a branch for every new kind of input has to be found " +
+		"and edited each time a kind is added, and the branches tend to drift apart. A map from key to " +
+		"value or handler, or an interface with one implementation per kind, keeps each case in one place."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *LongBranchChainRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `switch code {
+case "US":
+	return "United States"
+case "GB":
+	return "United Kingdom"
+// ... a case for every country
+}`,
+		Good: `var countryNames = map[string]string{
+	"US": "United States",
+	"GB": "United Kingdom",
+}
+
+return countryNames[code]`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *LongBranchChainRule) Options() []core.RuleOption {
+	return core.BranchesOptions()
+}
+
+// Category returns the category of this rule
+func (r *LongBranchChainRule) Category() core.RuleCategory {
+	return core.CategoryComplexity
+}
+
+// Severity returns the severity of violations of this rule
+func (r *LongBranchChainRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a function violates this rule
+func (r *LongBranchChainRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	maxBranches := config.Rules.Branches.MaxBranches
+	if maxBranches <= 0 {
+		maxBranches = defaultMaxBranches
+	}
+
+	n, ok := node.(*FunctionMetrics)
+	if !ok || n.BranchChain.Branches <= maxBranches {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.BranchChain.Line,
+		Message:    fmt.Sprintf("Function '%s' has a long %s (%d branches, max %d)", n.Name, n.BranchChain.Kind, n.BranchChain.Branches, maxBranches),
+		Suggestion: fmt.Sprintf("Consider replacing the %s in '%s' with a map of values or handlers, or an interface with one implementation per case", n.BranchChain.Kind, n.Name),
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
 )
 
 // OvercommentingRule detects overcommented code
@@ -37,6 +38,37 @@ func (r *OvercommentingRule) Description() string {
 	return "Detects code with excessive comments"
 }
 
+// Rationale explains why this rule exists
+func (r *OvercommentingRule) Rationale() string {
+	return "When most lines of a file are comments, the comments narrate the code instead of explaining it, " +
+		"and they drift out of date as the code changes. Generated code often comments every line. The " +
+		"rule compares the comment lines of a file with its code lines."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *OvercommentingRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `// Loop over the users
+for _, u := range users {
+	// Check if the user is active
+	if u.Active {
+		// Add the user to the result
+		active = append(active, u)
+	}
+}`,
+		Good: `for _, u := range users {
+	if u.Active {
+		active = append(active, u)
+	}
+}`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *OvercommentingRule) Options() []core.RuleOption {
+	return core.OvercommentingOptions()
+}
+
 // Category returns the category of this rule
 func (r *OvercommentingRule) Category() core.RuleCategory {
 	return core.CategoryComments
@@ -80,6 +112,9 @@ func (r *OvercommentingRule) Check(ctx context.Context, node interface{}, config
 	return nil
 }
 
+// defaultRedundantThreshold is the comment/code word overlap at which a comment is redundant
+const defaultRedundantThreshold = 0.6
+
 // RedundantCommentRule detects redundant comments
 type RedundantCommentRule struct {
 	config core.Config
@@ -107,6 +142,28 @@ func (r *RedundantCommentRule) Description() string {
 	return "Detects comments that simply restate what the code does"
 }
 
+// Rationale explains why this rule exists
+func (r *RedundantCommentRule) Rationale() string {
+	return "A comment that repeats the statement it sits on tells the reader nothing the code does not, " +
+		"and it is one more thing to keep in sync. The rule compares the words of each comment with the " +
+		"identifiers of the code it describes and reports comments whose words mostly appear there."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *RedundantCommentRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `// increment the retry count
+retryCount++`,
+		Good: `// the first attempt does not count as a retry
+retryCount++`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *RedundantCommentRule) Options() []core.RuleOption {
+	return core.RedundantCommentOptions()
+}
+
 // Category returns the category of this rule
 func (r *RedundantCommentRule) Category() core.RuleCategory {
 	return core.CategoryComments
@@ -123,37 +180,31 @@ func (r *RedundantCommentRule) Check(ctx context.Context, node interface{}, conf
 		return nil
 	}
 
-	switch n := node.(type) {
-	case *CommentGroup:
-		commentText := strings.TrimSpace(n.Text)
-
-		// Check for common redundant patterns
-		redundantPatterns := []string{
-			"increment i",
-			"decrement i",
-			"return true",
-			"return false",
-			"check if",
-			"loop through",
-			"initialize variable",
-		}
+	threshold := config.Rules.Overcommenting.RedundantThreshold
+	if threshold <= 0 {
+		threshold = defaultRedundantThreshold
+	}
 
-		for _, pattern := range redundantPatterns {
-			if strings.Contains(strings.ToLower(commentText), pattern) {
-				return &core.Result{
-					RuleID:     r.ID(),
-					RuleName:   r.Name(),
-					Category:   string(r.Category()),
-					Severity:   string(r.Severity()),
-					Line:       n.Position.Line,
-					Message:    fmt.Sprintf("Comment appears to be redundant: %q", commentText),
-					Suggestion: "Consider removing this redundant comment or making it more meaningful",
-				}
-			}
-		}
+	n, ok := node.(*CommentGroup)
+	if !ok || n.Code == "" {
+		return nil
 	}
 
-	return nil
+	overlap := languages.CommentRedundancy(n.Text, n.Code)
+	if overlap < threshold {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Confidence: string(core.ConfidenceLow),
+		Line:       n.Position.Line,
+		Message:    fmt.Sprintf("Comment restates the code it describes (%.0f%% word overlap): %q", overlap*100, strings.TrimSpace(n.Text)),
+		Suggestion: "Consider removing this redundant comment or explaining why the code does this instead",
+	}
 }
 
 // MissingDocumentationRule detects missing documentation on exported functions
@@ -183,6 +234,28 @@ func (r *MissingDocumentationRule) Description() string {
 	return "Detects exported functions without documentation"
 }
 
+// Rationale explains why this rule exists
+func (r *MissingDocumentationRule) Rationale() string {
+	return "Exported functions are the API of a package; go doc and editors show their doc comments to " +
+		"every caller, so an undocumented one forces readers into its body."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *MissingDocumentationRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `func Parse(s string) (Duration, error) {`,
+		Good: `// Parse parses a duration such as "1h30m"
+func Parse(s string) (Duration, error) {`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *MissingDocumentationRule) Options() []core.RuleOption {
+	return []core.RuleOption{
+		{Key: "rules.overcommenting.checkDocCoverage", Flag: "-check-docs", Default: "true", Description: "Report exported functions without a doc comment"},
+	}
+}
+
 // Category returns the category of this rule
 func (r *MissingDocumentationRule) Category() core.RuleCategory {
 	return core.CategoryComments
@@ -227,4 +300,88 @@ func (r *MissingDocumentationRule) Check(ctx context.Context, node interface{},
 type CommentGroup struct {
 	Text     string
 	Position token.Position
+	Code     string // code the comment describes: the following statement, or the code before a trailing comment
+}
+
+// AICommentRule detects comments carrying LLM chat boilerplate fingerprints
+type AICommentRule struct {
+	config core.Config
+}
+
+// NewAICommentRule creates a new AI comment fingerprint rule
+func NewAICommentRule(config core.Config) *AICommentRule {
+	return &AICommentRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *AICommentRule) ID() string {
+	return "ai-comment-fingerprint"
+}
+
+// Name returns the name of this rule
+func (r *AICommentRule) Name() string {
+	return "AI Comment Fingerprint"
+}
+
+// Description returns a description of this rule
+func (r *AICommentRule) Description() string {
+	return "Detects comments containing chat-assistant boilerplate left behind by LLMs"
+}
+
+// Rationale explains why this rule exists
+func (r *AICommentRule) Rationale() string {
+	return "Code pasted from a chat assistant often keeps the assistant's side of the conversation: " +
+		"\"Here's the updated code\", \"In a real application you would...\", or comments that open with " +
+		"\"This function\" and narrate the obvious. They describe the conversation rather than the code " +
+		"and show that the change was not reviewed."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *AICommentRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `// In a real application, you would load this from a config file
+const apiKey = "changeme"`,
+		Good: `// apiKey is replaced at build time with -ldflags -X
+var apiKey = "changeme"`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *AICommentRule) Options() []core.RuleOption {
+	return core.AICommentOptions()
+}
+
+// Category returns the category of this rule
+func (r *AICommentRule) Category() core.RuleCategory {
+	return core.CategoryComments
+}
+
+// Severity returns the severity of violations of this rule
+func (r *AICommentRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a comment line violates this rule
+func (r *AICommentRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*CommentGroup)
+	if !ok {
+		return nil
+	}
+
+	match := languages.MatchAIFingerprint(n.Text, config.Rules.AIComments.Phrases)
+	if match == "" {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.Position.Line,
+		Message:    fmt.Sprintf("Comment looks like LLM boilerplate (%s): %q", match, strings.TrimSpace(n.Text)),
+		Suggestion: "Remove the comment or rewrite it to explain intent specific to this code",
+	}
 }
@@ -29,6 +29,22 @@ func (r *ParameterCountRule) Description() string {
 	return "Detects functions with too many parameters"
 }
 
+func (r *ParameterCountRule) Rationale() string {
+	return "Long parameter lists are easy to call with arguments in the wrong order and usually mean " +
+		"the function needs a type describing its input."
+}
+
+func (r *ParameterCountRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad:  `func send(to, from, subject, body string, cc []string, retries int) error {`,
+		Good: `func send(msg Message, retries int) error {`,
+	}}
+}
+
+func (r *ParameterCountRule) Options() []core.RuleOption {
+	return nil
+}
+
 func (r *ParameterCountRule) Category() core.RuleCategory {
 	return core.CategorySize
 }
@@ -80,8 +96,32 @@ func (r *NestingDepthRule) Description() string {
 	return "Detects functions with excessive nesting depth"
 }
 
+func (r *NestingDepthRule) Rationale() string {
+	return "Each level of nesting is another condition the reader has to keep in mind. Early returns " +
+		"and extracted helpers keep the main path of a function at the left margin."
+}
+
+func (r *NestingDepthRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `if user != nil {
+	if user.Active {
+		for _, o := range user.Orders {
+			if o.Open {
+				if o.Total > limit {`,
+		Good: `if user == nil || !user.Active {
+	return nil
+}
+for _, o := range openOrders(user) {
+	if o.Total > limit {`,
+	}}
+}
+
+func (r *NestingDepthRule) Options() []core.RuleOption {
+	return nil
+}
+
 func (r *NestingDepthRule) Category() core.RuleCategory {
-	return core.CategorySize
+	return core.CategoryComplexity
 }
 
 func (r *NestingDepthRule) Severity() core.Severity {
@@ -100,6 +140,8 @@ func (r *NestingDepthRule) Check(ctx context.Context, node interface{}, config c
 				Category:   string(r.Category()),
 				Severity:   string(r.Severity()),
 				Line:       n.Position.Line,
+				EndLine:    n.End.Line,
+				EndColumn:  n.End.Column,
 				Message:    fmt.Sprintf("Function '%s' has excessive nesting depth (%d, max %d)", n.Name, n.NestingDepth, maxDepth),
 				Suggestion: fmt.Sprintf("Consider flattening the control flow in function '%s' or extracting nested logic", n.Name),
 			}
@@ -131,6 +173,22 @@ func (r *CommentQualityRule) Description() string {
 	return "Detects low-quality comments that don't add value"
 }
 
+func (r *CommentQualityRule) Rationale() string {
+	return "Marker comments such as TODO, FIXME, HACK or \"this is broken\" record known problems " +
+		"in the code rather than in the issue tracker, where they are easy to forget."
+}
+
+func (r *CommentQualityRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad:  `// HACK: this is broken for empty input`,
+		Good: `// Empty input has no header line, see issue #412`,
+	}}
+}
+
+func (r *CommentQualityRule) Options() []core.RuleOption {
+	return nil
+}
+
 func (r *CommentQualityRule) Category() core.RuleCategory {
 	return core.CategoryComments
 }
@@ -223,8 +281,29 @@ func (r *ComplexityThresholdRule) Description() string {
 	return "Detects functions with excessive cyclomatic complexity"
 }
 
+func (r *ComplexityThresholdRule) Rationale() string {
+	return "Cyclomatic complexity counts the independent paths through a function. Every path needs " +
+		"a test, so a function with many of them is hard to test completely and hard to change safely."
+}
+
+func (r *ComplexityThresholdRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `func price(o Order) int {
+	// a dozen nested ifs and switches over customer type,
+	// region, coupon and shipping method
+}`,
+		Good: `func price(o Order) int {
+	return base(o) - discount(o) + shipping(o)
+}`,
+	}}
+}
+
+func (r *ComplexityThresholdRule) Options() []core.RuleOption {
+	return nil
+}
+
 func (r *ComplexityThresholdRule) Category() core.RuleCategory {
-	return core.CategorySize
+	return core.CategoryComplexity
 }
 
 func (r *ComplexityThresholdRule) Severity() core.Severity {
@@ -243,6 +322,8 @@ func (r *ComplexityThresholdRule) Check(ctx context.Context, node interface{}, c
 				Category:   string(r.Category()),
 				Severity:   string(r.Severity()),
 				Line:       n.Position.Line,
+				EndLine:    n.End.Line,
+				EndColumn:  n.End.Column,
 				Message:    fmt.Sprintf("Function '%s' has high cyclomatic complexity (%d, max %d)", n.Name, n.CyclomaticComplexity, maxComplexity),
 				Suggestion: fmt.Sprintf("Consider simplifying function '%s' by extracting logic or using early returns", n.Name),
 			}
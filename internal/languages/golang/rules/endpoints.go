@@ -0,0 +1,98 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+)
+
+// StringLiteral is the value of a string literal in code, with its position
+type StringLiteral struct {
+	Value    string
+	Position token.Position
+}
+
+// HardcodedEndpointRule detects URLs, IP addresses and ports written into string literals
+type HardcodedEndpointRule struct {
+	config core.Config
+}
+
+// NewHardcodedEndpointRule creates a new hardcoded endpoint rule
+func NewHardcodedEndpointRule(config core.Config) *HardcodedEndpointRule {
+	return &HardcodedEndpointRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *HardcodedEndpointRule) ID() string {
+	return languages.EndpointRuleID
+}
+
+// Name returns the name of this rule
+func (r *HardcodedEndpointRule) Name() string {
+	return "Hardcoded Endpoint"
+}
+
+// Description returns a description of this rule
+func (r *HardcodedEndpointRule) Description() string {
+	return "Detects http:// URLs, example hosts, IP addresses and ports hardcoded outside configuration and test files"
+}
+
+// Rationale explains why this rule exists
+func (r *HardcodedEndpointRule) Rationale() string {
+	return "Generated code fills in the endpoints it needs with whatever the example used: " +
+		"localhost:8080, api.example.com or 127.0.0.1. They work on the developer's machine and " +
+		"reach production unnoticed, where the service cannot be pointed anywhere else without a rebuild."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *HardcodedEndpointRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad:  `resp, err := http.Get("http://localhost:8080/api/users")`,
+		Good: `resp, err := http.Get(cfg.APIBaseURL + "/api/users")`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *HardcodedEndpointRule) Options() []core.RuleOption {
+	return core.EndpointOptions()
+}
+
+// Category returns the category of this rule
+func (r *HardcodedEndpointRule) Category() core.RuleCategory {
+	return core.CategoryBug
+}
+
+// Severity returns the severity of violations of this rule
+func (r *HardcodedEndpointRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a string literal holds a hardcoded endpoint. The analyzer skips test and
+// configuration files.
+func (r *HardcodedEndpointRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*StringLiteral)
+	if !ok {
+		return nil
+	}
+
+	endpoint, found := languages.FindEndpoint(n.Value, config.Rules.Endpoints.Allow)
+	if !found {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.Position.Line,
+		Column:     n.Position.Column,
+		Message:    fmt.Sprintf("Hardcoded %s %q", endpoint.Kind, endpoint.Value),
+		Suggestion: "Read the endpoint from configuration or an environment variable, or add it to rules.endpoints.allow",
+	}
+}
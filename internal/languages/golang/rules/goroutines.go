@@ -0,0 +1,146 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// Kinds of GoroutineLeak
+const (
+	LeakUncancelled = "uncancelled"  // started in a loop with no context, done channel or wait group
+	LeakUnreceived  = "unreceived"   // sends on an unbuffered channel nothing receives from
+	LeakAbandoned   = "abandoned"    // sends on an unbuffered channel only received by a select that can give up
+	LeakMissingDone = "missing-done" // added to a wait group but never calls Done
+	LeakSkippedDone = "skipped-done" // returns before a Done call that is not deferred
+)
+
+// GoroutineLeak is a goroutine that may never exit or never be waited for
+type GoroutineLeak struct {
+	Kind string
+	Name string // the channel or wait group involved, if any
+	Line int    // of the go statement, or of the send for channel leaks
+}
+
+// GoroutineLeakRule detects goroutines that may block forever or outlive their caller
+type GoroutineLeakRule struct {
+	config core.Config
+}
+
+// NewGoroutineLeakRule creates a new goroutine leak rule
+func NewGoroutineLeakRule(config core.Config) *GoroutineLeakRule {
+	return &GoroutineLeakRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *GoroutineLeakRule) ID() string {
+	return "goroutine-leak"
+}
+
+// Name returns the name of this rule
+func (r *GoroutineLeakRule) Name() string {
+	return "Goroutine Leak"
+}
+
+// Description returns a description of this rule
+func (r *GoroutineLeakRule) Description() string {
+	return "Detects goroutines started in loops without cancellation, blocked channel sends and missing WaitGroup.Done calls"
+}
+
+// Rationale explains why this rule exists
+func (r *GoroutineLeakRule) Rationale() string {
+	return "Asked to make code parallel, generators put a go statement in front of the work and stop " +
+		"there. A goroutine nothing can cancel keeps running after its caller gives up, a goroutine " +
+		"sending on an unbuffered channel nobody reads blocks forever, and a goroutine that skips " +
+		"WaitGroup.Done hangs the Wait. Each leaks memory, and the last deadlocks."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *GoroutineLeakRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `ch := make(chan result)
+go func() { ch <- fetch(url) }()
+select {
+case res := <-ch:
+	return res, nil
+case <-time.After(time.Second):
+	return result{}, errTimeout // the goroutine blocks on its send forever
+}`,
+		Good: `ch := make(chan result, 1) // the send succeeds even if nobody receives it`,
+	}, {
+		Bad: `for _, job := range jobs {
+	wg.Add(1)
+	go func() {
+		if err := job.Run(); err != nil {
+			return // Wait never returns
+		}
+		wg.Done()
+	}()
+}`,
+		Good: `for _, job := range jobs {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		job.Run()
+	}()
+}`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *GoroutineLeakRule) Options() []core.RuleOption {
+	return core.ConcurrencyOptions()
+}
+
+// Category returns the category of this rule
+func (r *GoroutineLeakRule) Category() core.RuleCategory {
+	return core.CategoryBug
+}
+
+// Severity returns the severity of violations of this rule
+func (r *GoroutineLeakRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a function starts a goroutine that may leak
+func (r *GoroutineLeakRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*FunctionMetrics)
+	if !ok || n.GoroutineLeak.Line == 0 {
+		return nil
+	}
+
+	var message, suggestion string
+	leak := n.GoroutineLeak
+	switch leak.Kind {
+	case LeakUncancelled:
+		message = fmt.Sprintf("Function '%s' starts goroutines in a loop with no context or done channel to stop them", n.Name)
+		suggestion = "Pass a context.Context or a done channel the goroutines select on, and wait for them with a sync.WaitGroup or errgroup"
+	case LeakUnreceived:
+		message = fmt.Sprintf("Function '%s' starts a goroutine that sends on unbuffered channel '%s', which nothing receives from", n.Name, leak.Name)
+		suggestion = "Receive from the channel, give it a buffer, or remove the send"
+	case LeakAbandoned:
+		message = fmt.Sprintf("Function '%s' starts a goroutine that blocks forever sending on unbuffered channel '%s' once the select takes another case", n.Name, leak.Name)
+		suggestion = "Give the channel a buffer of 1, or have the goroutine select on the context as well"
+	case LeakMissingDone:
+		message = fmt.Sprintf("Function '%s' adds a goroutine to '%s' that never calls %s.Done(), so Wait never returns", n.Name, leak.Name, leak.Name)
+		suggestion = fmt.Sprintf("Start the goroutine with defer %s.Done()", leak.Name)
+	case LeakSkippedDone:
+		message = fmt.Sprintf("Function '%s' starts a goroutine that returns before calling %s.Done(), so Wait may never return", n.Name, leak.Name)
+		suggestion = fmt.Sprintf("Start the goroutine with defer %s.Done()", leak.Name)
+	default:
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       leak.Line,
+		Message:    message,
+		Suggestion: suggestion,
+	}
+}
@@ -0,0 +1,205 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// Roles of the value in a MutexCopy
+const (
+	CopyReceiver  = "receiver"
+	CopyParameter = "parameter"
+	CopyResult    = "result"
+)
+
+// MutexCopy is a receiver, parameter or result whose type holds a lock by value
+type MutexCopy struct {
+	Role string
+	Type string // the type passed by value
+	Lock string // the lock it holds, such as "sync.Mutex"
+	Line int
+}
+
+// MutexCopyRule detects values holding a sync.Mutex or another lock that are passed, received
+// or returned by value
+type MutexCopyRule struct {
+	config core.Config
+}
+
+// NewMutexCopyRule creates a new mutex copy rule
+func NewMutexCopyRule(config core.Config) *MutexCopyRule {
+	return &MutexCopyRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *MutexCopyRule) ID() string {
+	return "mutex-copy"
+}
+
+// Name returns the name of this rule
+func (r *MutexCopyRule) Name() string {
+	return "Mutex Copy"
+}
+
+// Description returns a description of this rule
+func (r *MutexCopyRule) Description() string {
+	return "Detects structs holding a sync.Mutex, RWMutex, WaitGroup or Once that are passed, received or returned by value"
+}
+
+// Rationale explains why this rule exists
+func (r *MutexCopyRule) Rationale() string {
+	return "Passing a struct by value copies the mutex inside it, and the copy locks independently of " +
+		"the original: a method with a value receiver locks its own copy and protects nothing. The code " +
+		"compiles and the race only shows under load. Lock-holding types are recognized when they are " +
+		"declared in the same file."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *MutexCopyRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `func (c Counter) Inc() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n++
+}`,
+		Good: `func (c *Counter) Inc() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n++
+}`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *MutexCopyRule) Options() []core.RuleOption {
+	return core.ConcurrencyOptions()
+}
+
+// Category returns the category of this rule
+func (r *MutexCopyRule) Category() core.RuleCategory {
+	return core.CategoryBug
+}
+
+// Severity returns the severity of violations of this rule
+func (r *MutexCopyRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a function copies a lock through its receiver, parameters or results
+func (r *MutexCopyRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*FunctionMetrics)
+	if !ok || n.MutexCopy.Line == 0 {
+		return nil
+	}
+
+	var message string
+	copied := n.MutexCopy
+	switch copied.Role {
+	case CopyReceiver:
+		message = fmt.Sprintf("Method '%s' has a value receiver of type '%s', so each call copies its %s", n.Name, copied.Type, copied.Lock)
+	case CopyParameter:
+		message = fmt.Sprintf("Function '%s' takes '%s' by value, copying its %s", n.Name, copied.Type, copied.Lock)
+	default:
+		message = fmt.Sprintf("Function '%s' returns '%s' by value, copying its %s", n.Name, copied.Type, copied.Lock)
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       copied.Line,
+		Message:    message,
+		Suggestion: fmt.Sprintf("Use a pointer, *%s, so the lock is shared instead of copied", copied.Type),
+	}
+}
+
+// DeferInLoopRule detects defer statements inside loops
+type DeferInLoopRule struct {
+	config core.Config
+}
+
+// NewDeferInLoopRule creates a new defer in loop rule
+func NewDeferInLoopRule(config core.Config) *DeferInLoopRule {
+	return &DeferInLoopRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *DeferInLoopRule) ID() string {
+	return "defer-in-loop"
+}
+
+// Name returns the name of this rule
+func (r *DeferInLoopRule) Name() string {
+	return "Defer In Loop"
+}
+
+// Description returns a description of this rule
+func (r *DeferInLoopRule) Description() string {
+	return "Detects defer statements inside loops"
+}
+
+// Rationale explains why this rule exists
+func (r *DeferInLoopRule) Rationale() string {
+	return "Deferred calls run when the function returns, not at the end of the loop iteration. A loop " +
+		"that opens a file or takes a lock and defers the cleanup holds every file open, or every lock, " +
+		"until the loop ends, which runs out of file descriptors or deadlocks on large inputs."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *DeferInLoopRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `for _, path := range paths {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	process(f)
+}`,
+		Good: `for _, path := range paths {
+	if err := processFile(path); err != nil { // opens, defers Close and processes
+		return err
+	}
+}`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *DeferInLoopRule) Options() []core.RuleOption {
+	return core.ConcurrencyOptions()
+}
+
+// Category returns the category of this rule
+func (r *DeferInLoopRule) Category() core.RuleCategory {
+	return core.CategoryBug
+}
+
+// Severity returns the severity of violations of this rule
+func (r *DeferInLoopRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a function defers inside a loop
+func (r *DeferInLoopRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*FunctionMetrics)
+	if !ok || n.DeferInLoopLine == 0 {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.DeferInLoopLine,
+		Message:    fmt.Sprintf("Function '%s' defers inside a loop, so nothing deferred runs until the function returns", n.Name),
+		Suggestion: "Move the loop body into its own function so each iteration's defer runs at its end, or clean up without defer",
+	}
+}
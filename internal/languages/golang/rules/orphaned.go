@@ -37,6 +37,38 @@ func (r *UnusedFunctionRule) Description() string {
 	return "Detects functions that are defined but never called"
 }
 
+// Rationale explains why this rule exists
+func (r *UnusedFunctionRule) Rationale() string {
+	return "Functions nothing calls are left behind when code is rewritten rather than edited, and they " +
+		"mislead readers into thinking they matter. Go functions are checked across the whole module, " +
+		"so a call from any file counts. Functions referenced as values, listed in registration tables, " +
+		"called from assembly or named by //export and //go:linkname are treated as used, as are main, " +
+		"init and test entry points. Exported functions are only reported with includeExported."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *UnusedFunctionRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `func formatLegacy(r Record) string { // replaced by format, never called
+	return r.Name + ";" + r.ID
+}`,
+		Good: `var formatters = map[string]func(Record) string{
+	"legacy": formatLegacy, // referenced, so it is used
+}`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *UnusedFunctionRule) Options() []core.RuleOption {
+	return append(core.UnusedFunctionOptions(),
+		core.RuleOption{Key: "rules.orphanedCode.includeExported", Flag: "-include-exported", Default: "false", Description: "Also report exported functions and types in modules nothing imports"},
+		core.RuleOption{Key: "rules.orphanedCode.ignoreFunctionPatterns", Flag: "-ignore-functions", Default: "Test*,Benchmark*,Example*", Description: "Globs or /regexp/ of function names never reported"},
+		core.RuleOption{Key: "rules.orphanedCode.ignoreReceivers", Flag: "-ignore-receivers", Default: "none", Description: "Globs or /regexp/ of receiver types whose methods are never reported"},
+		core.RuleOption{Key: "rules.orphanedCode.minStatements", Flag: "-unused-min-statements", Default: "0", Description: "Statements a function needs to be reported, 0 reports any size"},
+		core.RuleOption{Key: "rules.orphanedCode.indexFile", Flag: "-crossfile-index", Default: "none", Description: "File keeping the project-wide index between runs"},
+	)
+}
+
 // Category returns the category of this rule
 func (r *UnusedFunctionRule) Category() core.RuleCategory {
 	return core.CategoryOrphaned
@@ -117,6 +149,34 @@ func (r *UnusedVariableRule) Description() string {
 	return "Detects variables that are declared but never used"
 }
 
+// Rationale explains why this rule exists
+func (r *UnusedVariableRule) Rationale() string {
+	return "A variable that is assigned but never read is either dead code or a sign that a result is " +
+		"being dropped by mistake. The Go compiler already rejects unused local variables, so this rule " +
+		"matters for package-level variables."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *UnusedVariableRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `var defaultTimeout = 30 * time.Second // nothing reads it
+
+func newClient() *http.Client {
+	return &http.Client{Timeout: 10 * time.Second}
+}`,
+		Good: `var defaultTimeout = 30 * time.Second
+
+func newClient() *http.Client {
+	return &http.Client{Timeout: defaultTimeout}
+}`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *UnusedVariableRule) Options() []core.RuleOption {
+	return core.UnusedVariableOptions()
+}
+
 // Category returns the category of this rule
 func (r *UnusedVariableRule) Category() core.RuleCategory {
 	return core.CategoryOrphaned
@@ -163,6 +223,31 @@ func (r *UnreachableCodeRule) Description() string {
 	return "Detects code that can never be executed"
 }
 
+// Rationale explains why this rule exists
+func (r *UnreachableCodeRule) Rationale() string {
+	return "Statements after a return can never run. They usually remain from an edit that added an early " +
+		"return, and readers waste time working out when they execute."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *UnreachableCodeRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `func load(path string) ([]byte, error) {
+	return os.ReadFile(path)
+	log.Printf("loaded %s", path)
+}`,
+		Good: `func load(path string) ([]byte, error) {
+	log.Printf("loading %s", path)
+	return os.ReadFile(path)
+}`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *UnreachableCodeRule) Options() []core.RuleOption {
+	return core.UnreachableCodeOptions()
+}
+
 // Category returns the category of this rule
 func (r *UnreachableCodeRule) Category() core.RuleCategory {
 	return core.CategoryOrphaned
@@ -227,6 +312,29 @@ func (r *DeadImportRule) Description() string {
 	return "Detects import statements that are never used"
 }
 
+// Rationale explains why this rule exists
+func (r *DeadImportRule) Rationale() string {
+	return "An import nothing uses adds a dependency, slows down builds and hints at code that was removed " +
+		"only halfway. The Go compiler rejects unused imports, so in Go this rule mostly flags files that " +
+		"do not build yet."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *DeadImportRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `import (
+	"fmt"
+	"strings" // nothing in the file uses strings
+)`,
+		Good: `import "fmt"`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *DeadImportRule) Options() []core.RuleOption {
+	return core.DeadImportOptions()
+}
+
 // Category returns the category of this rule
 func (r *DeadImportRule) Category() core.RuleCategory {
 	return core.CategoryOrphaned
@@ -0,0 +1,190 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+const (
+	defaultMaxReturnValues     = 3
+	defaultNakedReturnMaxLines = 5
+)
+
+// TooManyReturnValuesRule detects functions returning too many values
+type TooManyReturnValuesRule struct {
+	config core.Config
+}
+
+// NewTooManyReturnValuesRule creates a new too many return values rule
+func NewTooManyReturnValuesRule(config core.Config) *TooManyReturnValuesRule {
+	return &TooManyReturnValuesRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *TooManyReturnValuesRule) ID() string {
+	return "too-many-return-values"
+}
+
+// Name returns the name of this rule
+func (r *TooManyReturnValuesRule) Name() string {
+	return "Too Many Return Values"
+}
+
+// Description returns a description of this rule
+func (r *TooManyReturnValuesRule) Description() string {
+	return "Detects functions that return more than the maximum number of values"
+}
+
+// Rationale explains why this rule exists
+func (r *TooManyReturnValuesRule) Rationale() string {
+	return "Callers of a function with many results have to remember what each position means, and " +
+		"swapping two values of the same type compiles silently. Past a few values, a named struct " +
+		"documents the results and lets the function grow without breaking every caller."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *TooManyReturnValuesRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `func stats(xs []int) (min, max, sum int, mean float64, err error) {`,
+		Good: `type Stats struct {
+	Min, Max, Sum int
+	Mean          float64
+}
+
+func stats(xs []int) (Stats, error) {`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *TooManyReturnValuesRule) Options() []core.RuleOption {
+	return []core.RuleOption{
+		{Key: "rules.returns.enabled", Flag: "-enable-returns", Default: "true", Description: "Run the return value rules"},
+		{Key: "rules.returns.maxValues", Flag: "-max-return-values", Default: "3", Description: "Most values a function may return"},
+	}
+}
+
+// Category returns the category of this rule
+func (r *TooManyReturnValuesRule) Category() core.RuleCategory {
+	return core.CategoryStyle
+}
+
+// Severity returns the severity of violations of this rule
+func (r *TooManyReturnValuesRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a function violates this rule
+func (r *TooManyReturnValuesRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	maxValues := config.Rules.Returns.MaxValues
+	if maxValues <= 0 {
+		maxValues = defaultMaxReturnValues
+	}
+
+	n, ok := node.(*FunctionMetrics)
+	if !ok || n.ReturnCount <= maxValues {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.Position.Line,
+		Message:    fmt.Sprintf("Function '%s' returns too many values (%d, max %d)", n.Name, n.ReturnCount, maxValues),
+		Suggestion: fmt.Sprintf("Consider returning a struct from '%s' instead of multiple values", n.Name),
+	}
+}
+
+// NakedReturnRule detects naked returns in functions that are too long to read them safely
+type NakedReturnRule struct {
+	config core.Config
+}
+
+// NewNakedReturnRule creates a new naked return rule
+func NewNakedReturnRule(config core.Config) *NakedReturnRule {
+	return &NakedReturnRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *NakedReturnRule) ID() string {
+	return "naked-return"
+}
+
+// Name returns the name of this rule
+func (r *NakedReturnRule) Name() string {
+	return "Naked Return"
+}
+
+// Description returns a description of this rule
+func (r *NakedReturnRule) Description() string {
+	return "Detects naked returns in functions longer than the maximum number of lines"
+}
+
+// Rationale explains why this rule exists
+func (r *NakedReturnRule) Rationale() string {
+	return "A bare return sends back whatever the named results hold at that point. In a short function " +
+		"that is easy to follow, but in a long one the reader has to trace every assignment to know what " +
+		"is returned, which is why Go's style guides discourage naked returns outside short functions."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *NakedReturnRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `func parse(s string) (n int, err error) {
+	// 30 lines assigning n and err on different paths
+	return
+}`,
+		Good: `func parse(s string) (int, error) {
+	// 30 lines
+	return n, nil
+}`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *NakedReturnRule) Options() []core.RuleOption {
+	return []core.RuleOption{
+		{Key: "rules.returns.enabled", Flag: "-enable-returns", Default: "true", Description: "Run the return value rules"},
+		{Key: "rules.returns.nakedReturnMaxLines", Flag: "-naked-return-max-lines", Default: "5", Description: "Longest function in which naked returns are allowed"},
+	}
+}
+
+// Category returns the category of this rule
+func (r *NakedReturnRule) Category() core.RuleCategory {
+	return core.CategoryStyle
+}
+
+// Severity returns the severity of violations of this rule
+func (r *NakedReturnRule) Severity() core.Severity {
+	return core.SeverityInfo
+}
+
+// Check checks if a function violates this rule
+func (r *NakedReturnRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	maxLines := config.Rules.Returns.NakedReturnMaxLines
+	if maxLines <= 0 {
+		maxLines = defaultNakedReturnMaxLines
+	}
+
+	n, ok := node.(*FunctionMetrics)
+	if !ok || n.NakedReturnLine == 0 || n.LineCount <= maxLines {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.NakedReturnLine,
+		Message:    fmt.Sprintf("Naked return in function '%s' (%d lines, max %d for naked returns)", n.Name, n.LineCount, maxLines),
+		Suggestion: "Return the values explicitly so readers do not have to trace the named results",
+	}
+}
@@ -33,7 +33,42 @@ func (r *LargeFunctionRule) Name() string {
 
 // Description returns a description of this rule
 func (r *LargeFunctionRule) Description() string {
-	return "Detects functions that exceed the maximum number of lines"
+	return "Detects functions that exceed the maximum number of lines or statements"
+}
+
+// Rationale explains why this rule exists
+func (r *LargeFunctionRule) Rationale() string {
+	return "Long functions mix several steps and levels of abstraction, which makes them hard to review, " +
+		"test and change safely. Generated code tends to grow one function instead of adding new ones. " +
+		"A function is measured from its signature to its closing brace, or by its statements when the " +
+		"metric is statements, so comments and blank lines do not count against it."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *LargeFunctionRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `func handleOrder(w http.ResponseWriter, req *http.Request) {
+	// decode the request, validate every field, price the items,
+	// write to the database and render the response: 120 lines
+}`,
+		Good: `func handleOrder(w http.ResponseWriter, req *http.Request) {
+	order, err := decodeOrder(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := saveOrder(req.Context(), price(order)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	renderOrder(w, order)
+}`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *LargeFunctionRule) Options() []core.RuleOption {
+	return core.FunctionSizeOptions("go", "go")
 }
 
 // Category returns the category of this rule
@@ -52,15 +87,25 @@ func (r *LargeFunctionRule) Check(ctx context.Context, node interface{}, config
 
 	switch n := node.(type) {
 	case *FunctionMetrics:
-		if n.LineCount > maxLines {
+		size, unit := n.LineCount, "lines"
+		if config.Rules.FunctionSize.Metric == core.MetricStatements {
+			size, unit = n.StatementCount, "statements"
+		}
+		if size > maxLines {
+			suggestion := fmt.Sprintf("Consider breaking down function '%s' into smaller functions", n.Name)
+			if config.Output.Verbose {
+				suggestion += fmt.Sprintf(" (%d lines, %d statements)", n.LineCount, n.StatementCount)
+			}
 			return &core.Result{
 				RuleID:     r.ID(),
 				RuleName:   r.Name(),
 				Category:   string(r.Category()),
 				Severity:   string(r.Severity()),
-				Line:       0, // Will be set by caller
-				Message:    fmt.Sprintf("Function '%s' is too large (%d lines, max %d)", n.Name, n.LineCount, maxLines),
-				Suggestion: fmt.Sprintf("Consider breaking down function '%s' into smaller functions", n.Name),
+				Line:       n.Position.Line,
+				EndLine:    n.End.Line,
+				EndColumn:  n.End.Column,
+				Message:    fmt.Sprintf("Function '%s' is too large (%d %s, max %d)", n.Name, size, unit, maxLines),
+				Suggestion: suggestion,
 			}
 		}
 	case *ast.FuncDecl:
@@ -98,6 +143,30 @@ func (r *LargeFileRule) Description() string {
 	return "Detects files that exceed the maximum number of lines"
 }
 
+// Rationale explains why this rule exists
+func (r *LargeFileRule) Rationale() string {
+	return "A large file usually holds several unrelated concerns, so readers have to page through code " +
+		"they do not care about and changes collide in review. Files with a code generation marker and " +
+		"vendored dependencies are never reported."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *LargeFileRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `// server.go, 1800 lines: routing, handlers, storage,
+// validation and template rendering`,
+		Good: `// routes.go    routing
+// orders.go    order handlers
+// store.go     storage
+// validate.go  validation`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *LargeFileRule) Options() []core.RuleOption {
+	return core.FileSizeOptions("go", "go")
+}
+
 // Category returns the category of this rule
 func (r *LargeFileRule) Category() core.RuleCategory {
 	return core.CategorySize
@@ -114,14 +183,21 @@ func (r *LargeFileRule) Check(ctx context.Context, node interface{}, config core
 
 	switch n := node.(type) {
 	case *FileMetrics:
-		if n.TotalLines > maxLines {
+		if n.Generated || n.Vendored {
+			return nil
+		}
+		size, unit := n.TotalLines, "lines"
+		if config.Rules.FileSize.CountMode == core.CountCode {
+			size, unit = n.CodeLines, "code lines"
+		}
+		if size > maxLines {
 			return &core.Result{
 				RuleID:     r.ID(),
 				RuleName:   r.Name(),
 				Category:   string(r.Category()),
 				Severity:   string(r.Severity()),
 				Line:       1,
-				Message:    fmt.Sprintf("File is too large (%d lines, max %d)", n.TotalLines, maxLines),
+				Message:    fmt.Sprintf("File is too large (%d %s, max %d)", size, unit, maxLines),
 				Suggestion: "Consider splitting this file into multiple smaller files",
 			}
 		}
@@ -140,11 +216,19 @@ type FunctionMetrics struct {
 	Exported             bool
 	IsMainPackage        bool
 	LineCount            int
+	StatementCount       int
 	ParameterCount       int
 	ReturnCount          int
 	CyclomaticComplexity int
 	NestingDepth         int
+	NakedReturnLine      int           // line of the first naked return, 0 if none
+	BranchChain          BranchChain   // the switch or if-else chain with the most branches
+	SyncSleep            SleepCall     // the first time.Sleep that waits for other code, zero if none
+	GoroutineLeak        GoroutineLeak // the first goroutine that may never exit or be waited for, zero if none
+	MutexCopy            MutexCopy     // the first receiver, parameter or result that copies a lock, zero if none
+	DeferInLoopLine      int           // line of the first defer inside a loop, 0 if none
 	Position             token.Position
+	End                  token.Position // just past the closing brace
 }
 
 // FileMetrics contains metrics about a Go file
@@ -158,4 +242,15 @@ type FileMetrics struct {
 	FunctionCount int
 	ImportCount   int
 	ExportedCount int
+	Generated     bool // carries a code generation marker
+	Vendored      bool // lies in a vendored dependency directory
+}
+
+// TypeMetrics contains metrics about a Go type declaration
+type TypeMetrics struct {
+	Name        string
+	IsStruct    bool
+	FieldCount  int
+	MethodCount int // methods declared on the type across its package
+	Position    token.Position
 }
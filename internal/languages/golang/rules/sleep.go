@@ -0,0 +1,97 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// SleepCall is a time.Sleep call that waits for other code instead of synchronizing with it
+type SleepCall struct {
+	Reason string // "after starting a goroutine" or "in a polling loop"
+	Line   int
+}
+
+// SleepSynchronizationRule detects time.Sleep used to wait for goroutines or shared state
+type SleepSynchronizationRule struct {
+	config core.Config
+}
+
+// NewSleepSynchronizationRule creates a new sleep synchronization rule
+func NewSleepSynchronizationRule(config core.Config) *SleepSynchronizationRule {
+	return &SleepSynchronizationRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *SleepSynchronizationRule) ID() string {
+	return "sleep-synchronization"
+}
+
+// Name returns the name of this rule
+func (r *SleepSynchronizationRule) Name() string {
+	return "Sleep Synchronization"
+}
+
+// Description returns a description of this rule
+func (r *SleepSynchronizationRule) Description() string {
+	return "Detects time.Sleep after starting a goroutine or in a loop polling for a condition"
+}
+
+// Rationale explains why this rule exists
+func (r *SleepSynchronizationRule) Rationale() string {
+	return "Sleeping until a goroutine has probably finished is the usual way generated code makes a " +
+		"race condition go away. The race is still there: the sleep is too long on a fast machine and " +
+		"too short on a loaded one. A sync.WaitGroup, a channel or a context says what is being waited for " +
+		"and returns as soon as it happens."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *SleepSynchronizationRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `go worker.Run()
+time.Sleep(100 * time.Millisecond) // give the worker time to start`,
+		Good: `var wg sync.WaitGroup
+wg.Add(1)
+go func() {
+	defer wg.Done()
+	worker.Run()
+}()
+wg.Wait()`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *SleepSynchronizationRule) Options() []core.RuleOption {
+	return core.ConcurrencyOptions()
+}
+
+// Category returns the category of this rule
+func (r *SleepSynchronizationRule) Category() core.RuleCategory {
+	return core.CategoryBug
+}
+
+// Severity returns the severity of violations of this rule
+func (r *SleepSynchronizationRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a function sleeps to wait for other code. The analyzer skips test files.
+func (r *SleepSynchronizationRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*FunctionMetrics)
+	if !ok || n.SyncSleep.Line == 0 {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.SyncSleep.Line,
+		Message:    fmt.Sprintf("Function '%s' calls time.Sleep %s instead of synchronizing", n.Name, n.SyncSleep.Reason),
+		Suggestion: "Wait with a sync.WaitGroup, a channel or a context.Context instead of sleeping",
+	}
+}
@@ -0,0 +1,283 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+)
+
+// TestFunction describes a Test function of a _test.go file
+type TestFunction struct {
+	Name          string
+	Line          int
+	Asserts       bool     // calls a failure method of its *testing.T, an assertion function or a helper given t
+	Getters       []string // accessors called by a test that calls nothing else but constructors, nil otherwise
+	DuplicateOf   string   // earlier test of the file with nearly the same code, "" if none
+	DuplicateLine int
+	Similarity    float64
+}
+
+// AssertionFreeTestRule detects tests that can only fail by panicking
+type AssertionFreeTestRule struct {
+	config core.Config
+}
+
+// NewAssertionFreeTestRule creates a new assertion-free test rule
+func NewAssertionFreeTestRule(config core.Config) *AssertionFreeTestRule {
+	return &AssertionFreeTestRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *AssertionFreeTestRule) ID() string {
+	return languages.AssertionFreeTestRuleID
+}
+
+// Name returns the name of this rule
+func (r *AssertionFreeTestRule) Name() string {
+	return "Assertion-Free Test"
+}
+
+// Description returns a description of this rule
+func (r *AssertionFreeTestRule) Description() string {
+	return "Detects Test functions that never call t.Error, t.Fatal, an assertion function or a helper given t"
+}
+
+// Rationale explains why this rule exists
+func (r *AssertionFreeTestRule) Rationale() string {
+	return "A test that runs the code without checking the result passes whatever the code returns. " +
+		"It adds to the coverage figure while catching nothing but panics, and generated test suites " +
+		"contain many of them because calling a function is easier than working out what it should return."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *AssertionFreeTestRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `func TestParse(t *testing.T) {
+	cfg, _ := Parse("port: 80")
+	t.Log(cfg)
+}`,
+		Good: `func TestParse(t *testing.T) {
+	cfg, err := Parse("port: 80")
+	if err != nil || cfg.Port != 80 {
+		t.Fatalf("Parse() = %+v, %v, want port 80", cfg, err)
+	}
+}`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *AssertionFreeTestRule) Options() []core.RuleOption {
+	return core.TestQualityOptions()
+}
+
+// Category returns the category of this rule
+func (r *AssertionFreeTestRule) Category() core.RuleCategory {
+	return core.CategoryBug
+}
+
+// Severity returns the severity of violations of this rule
+func (r *AssertionFreeTestRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a test function asserts anything. The analyzer only applies it to test files.
+func (r *AssertionFreeTestRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*TestFunction)
+	if !ok || n.Asserts {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.Line,
+		Message:    fmt.Sprintf("Test '%s' never reports a failure, so it passes whatever the code does", n.Name),
+		Suggestion: "Compare the results with the expected values and call t.Errorf or t.Fatalf when they differ",
+	}
+}
+
+// DuplicateTestRule detects tests that repeat an earlier test of the same file
+type DuplicateTestRule struct {
+	config core.Config
+}
+
+// NewDuplicateTestRule creates a new duplicate test rule
+func NewDuplicateTestRule(config core.Config) *DuplicateTestRule {
+	return &DuplicateTestRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *DuplicateTestRule) ID() string {
+	return languages.DuplicateTestRuleID
+}
+
+// Name returns the name of this rule
+func (r *DuplicateTestRule) Name() string {
+	return "Duplicate Test"
+}
+
+// Description returns a description of this rule
+func (r *DuplicateTestRule) Description() string {
+	return "Detects Test functions that call the same functions as an earlier test with nearly the same code"
+}
+
+// Rationale explains why this rule exists
+func (r *DuplicateTestRule) Rationale() string {
+	return "Generated tests are often one test copied for every input, with only the values changed. " +
+		"Each copy has to be updated when the code changes, and a long run of them hides which cases " +
+		"are actually covered. A table-driven test states the cases once and the checks once."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *DuplicateTestRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `func TestAddPositive(t *testing.T) {
+	if got := Add(1, 2); got != 3 {
+		t.Errorf("Add(1, 2) = %d, want 3", got)
+	}
+}
+
+func TestAddNegative(t *testing.T) {
+	if got := Add(-1, -2); got != -3 {
+		t.Errorf("Add(-1, -2) = %d, want -3", got)
+	}
+}`,
+		Good: `func TestAdd(t *testing.T) {
+	tests := []struct{ a, b, want int }{{1, 2, 3}, {-1, -2, -3}}
+	for _, tt := range tests {
+		if got := Add(tt.a, tt.b); got != tt.want {
+			t.Errorf("Add(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *DuplicateTestRule) Options() []core.RuleOption {
+	return core.DuplicateTestOptions()
+}
+
+// Category returns the category of this rule
+func (r *DuplicateTestRule) Category() core.RuleCategory {
+	return core.CategoryStyle
+}
+
+// Severity returns the severity of violations of this rule
+func (r *DuplicateTestRule) Severity() core.Severity {
+	return core.SeverityInfo
+}
+
+// Check checks if a test function nearly repeats an earlier one. The analyzer only applies it
+// to test files.
+func (r *DuplicateTestRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*TestFunction)
+	if !ok || n.DuplicateOf == "" {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.Line,
+		Message:    fmt.Sprintf("Test '%s' repeats '%s' (line %d) with %.0f%% of the same code", n.Name, n.DuplicateOf, n.DuplicateLine, n.Similarity*100),
+		Suggestion: "Merge the tests into one table-driven test with a case for each input",
+	}
+}
+
+// TrivialGetterTestRule detects tests that only call getters and setters
+type TrivialGetterTestRule struct {
+	config core.Config
+}
+
+// NewTrivialGetterTestRule creates a new trivial getter test rule
+func NewTrivialGetterTestRule(config core.Config) *TrivialGetterTestRule {
+	return &TrivialGetterTestRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *TrivialGetterTestRule) ID() string {
+	return languages.TrivialTestRuleID
+}
+
+// Name returns the name of this rule
+func (r *TrivialGetterTestRule) Name() string {
+	return "Trivial Getter Test"
+}
+
+// Description returns a description of this rule
+func (r *TrivialGetterTestRule) Description() string {
+	return "Detects Test functions whose only calls besides constructors and assertions are getters and setters"
+}
+
+// Rationale explains why this rule exists
+func (r *TrivialGetterTestRule) Rationale() string {
+	return "Checking that a getter returns the value the constructor was given tests the language, not " +
+		"the code. Such tests are the easiest way to raise coverage and generated suites are full of " +
+		"them, but they only fail when a field is renamed, and the time is better spent on the logic."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *TrivialGetterTestRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `func TestUserName(t *testing.T) {
+	u := NewUser("ada")
+	if u.GetName() != "ada" {
+		t.Error("wrong name")
+	}
+}`,
+		Good: `func TestUserRename(t *testing.T) {
+	u := NewUser("ada")
+	if err := u.Rename(""); err == nil {
+		t.Error("Rename accepted an empty name")
+	}
+}`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *TrivialGetterTestRule) Options() []core.RuleOption {
+	return core.TestQualityOptions()
+}
+
+// Category returns the category of this rule
+func (r *TrivialGetterTestRule) Category() core.RuleCategory {
+	return core.CategoryStyle
+}
+
+// Severity returns the severity of violations of this rule
+func (r *TrivialGetterTestRule) Severity() core.Severity {
+	return core.SeverityInfo
+}
+
+// Check checks if a test function only exercises accessors. The analyzer only applies it to
+// test files.
+func (r *TrivialGetterTestRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*TestFunction)
+	if !ok || !n.Asserts || len(n.Getters) == 0 {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.Line,
+		Message:    fmt.Sprintf("Test '%s' only exercises the accessors %s", n.Name, strings.Join(n.Getters, ", ")),
+		Suggestion: "Test the behaviour that uses these values, or drop the test if the accessors have no logic",
+	}
+}
@@ -0,0 +1,199 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+const (
+	defaultMaxStructFields = 15
+	defaultMaxTypeMethods  = 20
+)
+
+// GodStructRule detects structs with an excessive number of fields
+type GodStructRule struct {
+	config core.Config
+}
+
+// NewGodStructRule creates a new god struct rule
+func NewGodStructRule(config core.Config) *GodStructRule {
+	return &GodStructRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *GodStructRule) ID() string {
+	return "god-struct"
+}
+
+// Name returns the name of this rule
+func (r *GodStructRule) Name() string {
+	return "God Struct"
+}
+
+// Description returns a description of this rule
+func (r *GodStructRule) Description() string {
+	return "Detects structs that exceed the maximum number of fields"
+}
+
+// Rationale explains why this rule exists
+func (r *GodStructRule) Rationale() string {
+	return "A struct with many fields tends to hold the state of several responsibilities, so every " +
+		"method can touch everything and the type becomes hard to construct and test. Grouping related " +
+		"fields into smaller types makes the dependencies visible."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *GodStructRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `type Server struct {
+	addr, certFile, keyFile   string
+	db                        *sql.DB
+	cache                     map[string][]byte
+	mailer                    *smtp.Client
+	templates                 *template.Template
+	// ... 12 more fields
+}`,
+		Good: `type Server struct {
+	addr   string
+	tls    TLSConfig
+	store  *Store
+	mailer *Mailer
+	views  *Views
+}`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *GodStructRule) Options() []core.RuleOption {
+	return []core.RuleOption{
+		{Key: "rules.typeSize.enabled", Flag: "-enable-type-size", Default: "true", Description: "Run the type size rules"},
+		{Key: "rules.typeSize.maxFields", Flag: "-struct-max-fields", Default: "15", Description: "Most fields a struct may have"},
+	}
+}
+
+// Category returns the category of this rule
+func (r *GodStructRule) Category() core.RuleCategory {
+	return core.CategorySize
+}
+
+// Severity returns the severity of violations of this rule
+func (r *GodStructRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a type violates this rule
+func (r *GodStructRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	maxFields := config.Rules.TypeSize.MaxFields
+	if maxFields <= 0 {
+		maxFields = defaultMaxStructFields
+	}
+
+	n, ok := node.(*TypeMetrics)
+	if !ok || !n.IsStruct || n.FieldCount <= maxFields {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.Position.Line,
+		Message:    fmt.Sprintf("Struct '%s' has too many fields (%d, max %d)", n.Name, n.FieldCount, maxFields),
+		Suggestion: fmt.Sprintf("Consider splitting '%s' into smaller types grouped by responsibility", n.Name),
+	}
+}
+
+// TooManyMethodsRule detects types with an excessive number of methods
+type TooManyMethodsRule struct {
+	config core.Config
+}
+
+// NewTooManyMethodsRule creates a new too many methods rule
+func NewTooManyMethodsRule(config core.Config) *TooManyMethodsRule {
+	return &TooManyMethodsRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *TooManyMethodsRule) ID() string {
+	return "too-many-methods"
+}
+
+// Name returns the name of this rule
+func (r *TooManyMethodsRule) Name() string {
+	return "Too Many Methods"
+}
+
+// Description returns a description of this rule
+func (r *TooManyMethodsRule) Description() string {
+	return "Detects types that exceed the maximum number of methods"
+}
+
+// Rationale explains why this rule exists
+func (r *TooManyMethodsRule) Rationale() string {
+	return "A type with a long method set is usually doing several jobs at once, and every consumer " +
+		"depends on all of it. Methods are counted across every file of the package, so splitting them " +
+		"into more files does not help; splitting the type does."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *TooManyMethodsRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `func (s *Service) CreateUser(...)
+func (s *Service) DeleteUser(...)
+func (s *Service) CreateInvoice(...)
+func (s *Service) SendInvoice(...)
+// ... 20 more methods`,
+		Good: `func (u *Users) Create(...)
+func (u *Users) Delete(...)
+func (b *Billing) CreateInvoice(...)
+func (b *Billing) SendInvoice(...)`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *TooManyMethodsRule) Options() []core.RuleOption {
+	return []core.RuleOption{
+		{Key: "rules.typeSize.enabled", Flag: "-enable-type-size", Default: "true", Description: "Run the type size rules"},
+		{Key: "rules.typeSize.maxMethods", Flag: "-type-max-methods", Default: "20", Description: "Most methods a type may have"},
+	}
+}
+
+// Category returns the category of this rule
+func (r *TooManyMethodsRule) Category() core.RuleCategory {
+	return core.CategorySize
+}
+
+// Severity returns the severity of violations of this rule
+func (r *TooManyMethodsRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a type violates this rule
+func (r *TooManyMethodsRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	maxMethods := config.Rules.TypeSize.MaxMethods
+	if maxMethods <= 0 {
+		maxMethods = defaultMaxTypeMethods
+	}
+
+	n, ok := node.(*TypeMetrics)
+	if !ok || n.MethodCount <= maxMethods {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.Position.Line,
+		Message:    fmt.Sprintf("Type '%s' has too many methods (%d, max %d)", n.Name, n.MethodCount, maxMethods),
+		Suggestion: fmt.Sprintf("Consider extracting related methods of '%s' into separate types", n.Name),
+	}
+}
@@ -2,35 +2,107 @@ package golang
 
 import (
 	"context"
+	"fmt"
 	"go/ast"
-	"go/parser"
 	"go/token"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
 )
 
+// SimilarityRuleID identifies findings of functions whose bodies follow the same pattern
+const SimilarityRuleID = "code-similarity"
+
+func init() {
+	core.RegisterRuleDoc(core.RuleDoc{
+		ID:          SimilarityRuleID,
+		Name:        "Code Similarity",
+		Description: "Detects pairs of Go functions whose bodies follow the same pattern",
+		Rationale: "Generated code is often copied and adjusted rather than factored out, leaving " +
+			"functions that differ only in names and literals. Each body is reduced to its control flow " +
+			"and operations and compared by the Jaccard similarity of its four-token shingles, so the " +
+			"finding names both functions and how alike they are.",
+		Category:  core.CategoryComplexity,
+		Severity:  core.SeverityInfo,
+		Languages: []string{"go"},
+		Examples: []core.RuleExample{{
+			Bad: `func saveUser(u User) error {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile("user.json", data, 0o644)
+}
+
+func saveOrder(o Order) error { /* the same statements for an Order */ }`,
+			Good: `func save(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}`,
+		}},
+		Options: []core.RuleOption{
+			{Key: "rules.similarity.enabled", Flag: "-enable-similarity", Default: "false", Description: "Report similar functions"},
+			{Key: "rules.similarity.threshold", Flag: "-similarity-threshold", Default: "0.8", Description: "Jaccard similarity of the shingles of two functions at which they are reported"},
+			{Key: "rules.similarity.minStatements", Flag: "-similarity-min-statements", Default: "5", Description: "Statements a function needs to be compared"},
+			{Key: "rules.similarity.minTokens", Flag: "-similarity-min-tokens", Default: "10", Description: "Normalized tokens a function needs to be compared"},
+		},
+	})
+}
+
+// shingleSize is the number of consecutive normalized tokens in a shingle. The bodies are
+// reduced to a dozen kinds of token, so shorter shingles would match in unrelated code.
+const shingleSize = 4
+
+// SimilarityAnalyzer reports pairs of functions whose bodies follow the same pattern. Each body
+// is reduced to its statements and operations, cut into overlapping shingles of shingleSize
+// tokens, and two functions are compared by the Jaccard similarity of their shingle sets.
 type SimilarityAnalyzer struct {
-	fset       *token.FileSet
-	funcSigs   map[string][]string
-	funcBodies map[string]string
-	mu         sync.RWMutex
+	fset          *token.FileSet
+	functions     map[string]*similarFunction // file path and function name -> its shingles
+	cache         *ASTCache                   // shared parse cache, see SetCache
+	minStatements int                         // functions with fewer statements are not compared, see SetMinSize
+	minTokens     int
+	mu            sync.RWMutex
+}
+
+// similarFunction is a function declaration as the similarity analysis sees it
+type similarFunction struct {
+	name     string // qualified with the receiver type for methods
+	position token.Position
+	shingles map[string]bool
 }
 
 func NewSimilarityAnalyzer() *SimilarityAnalyzer {
 	return &SimilarityAnalyzer{
-		fset:       token.NewFileSet(),
-		funcSigs:   make(map[string][]string),
-		funcBodies: make(map[string]string),
+		fset:      token.NewFileSet(),
+		functions: make(map[string]*similarFunction),
 	}
 }
 
-func (a *SimilarityAnalyzer) AnalyzeDirectory(ctx context.Context, dirPath string, threshold float64) ([]core.Result, error) {
-	var results []core.Result
+// SetCache makes the analyzer take ASTs from a cache shared with the other Go analyses. Call
+// it before AnalyzeDirectory.
+func (a *SimilarityAnalyzer) SetCache(cache *ASTCache) {
+	a.cache = cache
+	a.fset = cache.FileSet()
+}
 
+// SetMinSize leaves functions with fewer than statements statements or fewer than tokens
+// normalized tokens out of the comparison, since short functions such as getters and
+// one-line wrappers look alike without being copies. 0 compares functions of any size. Call
+// it before AnalyzeDirectory or AnalyzeFiles.
+func (a *SimilarityAnalyzer) SetMinSize(statements, tokens int) {
+	a.minStatements = statements
+	a.minTokens = tokens
+}
+
+func (a *SimilarityAnalyzer) AnalyzeDirectory(ctx context.Context, dirPath string, threshold float64) ([]core.Result, error) {
 	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -58,21 +130,40 @@ func (a *SimilarityAnalyzer) AnalyzeDirectory(ctx context.Context, dirPath strin
 		return nil, err
 	}
 
-	similarities := a.findSimilarFunctions(threshold)
-	for _, sim := range similarities {
+	return a.similarityResults(threshold), nil
+}
+
+// AnalyzeFiles compares the functions of the given Go files with each other and reports the
+// pairs at least threshold alike
+func (a *SimilarityAnalyzer) AnalyzeFiles(ctx context.Context, files []string, threshold float64) ([]core.Result, error) {
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := a.analyzeFile(file); err != nil {
+			return nil, err
+		}
+	}
+	return a.similarityResults(threshold), nil
+}
+
+func (a *SimilarityAnalyzer) similarityResults(threshold float64) []core.Result {
+	var results []core.Result
+	for _, sim := range a.findSimilarFunctions(threshold) {
 		results = append(results, core.Result{
-			RuleID:     "code-similarity",
+			RuleID:     SimilarityRuleID,
 			RuleName:   "Code Similarity",
-			Category:   "complexity",
+			Category:   string(core.CategoryComplexity),
 			Severity:   "info",
+			Confidence: string(core.ConfidenceLow),
 			FilePath:   sim.File1,
 			Line:       sim.Line1,
 			Message:    sim.Message,
 			Suggestion: sim.Suggestion,
+			Related:    &core.Location{FilePath: sim.File2, Line: sim.Line2},
 		})
 	}
-
-	return results, nil
+	return results
 }
 
 func shouldSkipDirForSimilarity(name string) bool {
@@ -86,12 +177,7 @@ func shouldSkipDirForSimilarity(name string) bool {
 }
 
 func (a *SimilarityAnalyzer) analyzeFile(filePath string) error {
-	src, err := os.ReadFile(filePath)
-	if err != nil {
-		return err
-	}
-
-	f, err := parser.ParseFile(a.fset, filePath, src, parser.ParseComments)
+	f, err := parseWithCache(a.cache, a.fset, filePath)
 	if err != nil {
 		return err
 	}
@@ -111,12 +197,22 @@ func (a *SimilarityAnalyzer) analyzeFile(filePath string) error {
 				return true
 			}
 
-			signature := a.getFunctionSignature(node)
-			body := a.getNormalizedBody(node.Body)
+			if countStatements(node) < a.minStatements {
+				return true
+			}
+			tokens := a.getNormalizedBody(node.Body)
+			if len(tokens) == 0 || len(tokens) < a.minTokens {
+				return true
+			}
 
-			key := filePath + ":" + funcName
-			a.funcSigs[key] = signature
-			a.funcBodies[key] = body
+			if receiver := getReceiverTypeName(node); receiver != "" {
+				funcName = receiver + "." + funcName
+			}
+			a.functions[filePath+":"+funcName] = &similarFunction{
+				name:     funcName,
+				position: a.fset.Position(node.Pos()),
+				shingles: shingles(tokens),
+			}
 		}
 		return true
 	})
@@ -134,29 +230,9 @@ func isIgnoredFunctionName(name string) bool {
 	return false
 }
 
-func (a *SimilarityAnalyzer) getFunctionSignature(node *ast.FuncDecl) []string {
-	var sig []string
-
-	if node.Recv != nil && len(node.Recv.List) > 0 {
-		sig = append(sig, "receiver")
-	}
-
-	if node.Type.Params != nil {
-		for range node.Type.Params.List {
-			sig = append(sig, "param")
-		}
-	}
-
-	if node.Type.Results != nil {
-		for range node.Type.Results.List {
-			sig = append(sig, "return")
-		}
-	}
-
-	return sig
-}
-
-func (a *SimilarityAnalyzer) getNormalizedBody(body *ast.BlockStmt) string {
+// getNormalizedBody reduces a function body to the kinds of its statements and operations,
+// in source order, so that code differing only in names and literals reads the same
+func (a *SimilarityAnalyzer) getNormalizedBody(body *ast.BlockStmt) []string {
 	var tokens []string
 
 	ast.Inspect(body, func(n ast.Node) bool {
@@ -191,7 +267,40 @@ func (a *SimilarityAnalyzer) getNormalizedBody(body *ast.BlockStmt) string {
 		return true
 	})
 
-	return strings.Join(tokens, " ")
+	return tokens
+}
+
+// shingles returns the set of runs of shingleSize consecutive tokens, or the whole sequence
+// when it is shorter
+func shingles(tokens []string) map[string]bool {
+	set := make(map[string]bool)
+	if len(tokens) <= shingleSize {
+		set[strings.Join(tokens, " ")] = true
+		return set
+	}
+	for i := 0; i+shingleSize <= len(tokens); i++ {
+		set[strings.Join(tokens[i:i+shingleSize], " ")] = true
+	}
+	return set
+}
+
+// jaccard returns the size of the intersection of two sets relative to that of their union,
+// from 0 for sets with nothing in common to 1 for equal sets
+func jaccard(set1, set2 map[string]bool) float64 {
+	if len(set1) > len(set2) {
+		set1, set2 = set2, set1
+	}
+	shared := 0
+	for shingle := range set1 {
+		if set2[shingle] {
+			shared++
+		}
+	}
+	union := len(set1) + len(set2) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
 }
 
 type Similarity struct {
@@ -210,22 +319,36 @@ func (a *SimilarityAnalyzer) findSimilarFunctions(threshold float64) []Similarit
 
 	var similarities []Similarity
 
-	keys := make([]string, 0, len(a.funcBodies))
-	for k := range a.funcBodies {
+	keys := make([]string, 0, len(a.functions))
+	for k := range a.functions {
 		keys = append(keys, k)
 	}
+	// Functions are taken in file and line order, so each pair is reported at the earlier one
+	sort.Slice(keys, func(i, j int) bool {
+		pos1, pos2 := a.functions[keys[i]].position, a.functions[keys[j]].position
+		if pos1.Filename != pos2.Filename {
+			return pos1.Filename < pos2.Filename
+		}
+		return pos1.Line < pos2.Line
+	})
 
 	for i := 0; i < len(keys); i++ {
 		for j := i + 1; j < len(keys); j++ {
-			key1, key2 := keys[i], keys[j]
+			fn1, fn2 := a.functions[keys[i]], a.functions[keys[j]]
+			if !canReach(len(fn1.shingles), len(fn2.shingles), threshold) {
+				continue
+			}
 
-			sim := a.calculateSimilarity(key1, key2)
+			sim := jaccard(fn1.shingles, fn2.shingles)
 			if sim >= threshold {
 				similarities = append(similarities, Similarity{
-					File1:      key1,
-					File2:      key2,
+					File1:      fn1.position.Filename,
+					Line1:      fn1.position.Line,
+					File2:      fn2.position.Filename,
+					Line2:      fn2.position.Line,
 					Similarity: sim,
-					Message:    "Similar code patterns detected",
+					Message: fmt.Sprintf("Function '%s' is %.0f%% similar to '%s' at %s:%d",
+						fn1.name, sim*100, fn2.name, filepath.Base(fn2.position.Filename), fn2.position.Line),
 					Suggestion: "Consider extracting common logic into a shared function",
 				})
 			}
@@ -235,39 +358,11 @@ func (a *SimilarityAnalyzer) findSimilarFunctions(threshold float64) []Similarit
 	return similarities
 }
 
-func (a *SimilarityAnalyzer) calculateSimilarity(key1, key2 string) float64 {
-	body1 := a.funcBodies[key1]
-	body2 := a.funcBodies[key2]
-
-	if len(body1) == 0 || len(body2) == 0 {
-		return 0
-	}
-
-	tokens1 := strings.Fields(body1)
-	tokens2 := strings.Fields(body2)
-
-	if len(tokens1) == 0 || len(tokens2) == 0 {
-		return 0
-	}
-
-	matchCount := 0
-	for _, t1 := range tokens1 {
-		for _, t2 := range tokens2 {
-			if t1 == t2 {
-				matchCount++
-				break
-			}
-		}
-	}
-
-	smaller := len(tokens1)
-	if len(tokens2) < smaller {
-		smaller = len(tokens2)
+// canReach reports whether sets of size1 and size2 elements can have a Jaccard similarity of
+// threshold, which is at most the ratio of the smaller size to the larger
+func canReach(size1, size2 int, threshold float64) bool {
+	if size1 > size2 {
+		size1, size2 = size2, size1
 	}
-
-	if smaller == 0 {
-		return 0
-	}
-
-	return float64(matchCount) / float64(smaller)
+	return float64(size1) >= threshold*float64(size2)
 }
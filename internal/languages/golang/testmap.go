@@ -0,0 +1,321 @@
+package golang
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// UntestedFunctionRuleID identifies findings of exported functions no test refers to
+const UntestedFunctionRuleID = "untested-function"
+
+func init() {
+	core.RegisterRuleDoc(core.RuleDoc{
+		ID:          UntestedFunctionRuleID,
+		Name:        "Untested Function",
+		Description: "Detects exported Go functions and methods no test of their package refers to",
+		Rationale: "Generated code often arrives with more exported API than tests. Each exported " +
+			"function is mapped to the Test, Benchmark, Example and Fuzz functions of its directory that " +
+			"call it, name it in their body, directly or through helpers and tables, or are named after " +
+			"it, as in TestParse_Empty. A package without test files is reported once.",
+		Category:  core.CategoryStyle,
+		Severity:  core.SeverityInfo,
+		Languages: []string{"go"},
+		Examples: []core.RuleExample{{
+			Bad: `// stack.go
+func (s *Stack) Push(v int) { s.items = append(s.items, v) } // no test mentions Push`,
+			Good: `// stack_test.go
+func TestStack_Push(t *testing.T) { ... }`,
+		}},
+		Options: []core.RuleOption{
+			{Key: "rules.testQuality.enabled", Flag: "-enable-test-quality", Default: "true", Description: "Run the test quality rules"},
+			{Key: "rules.testQuality.checkUntested", Flag: "-check-untested", Default: "false", Description: "Report exported functions no test refers to"},
+		},
+	})
+}
+
+// testEntryPrefixes start the names of the functions go test runs
+var testEntryPrefixes = []string{"Test", "Benchmark", "Example", "Fuzz"}
+
+// interfaceMethods are methods that standard library interfaces call, which tests exercise
+// through fmt, errors or encoding rather than by name
+var interfaceMethods = map[string]bool{
+	"String": true, "GoString": true, "Format": true, "Error": true, "Unwrap": true, "Is": true, "As": true,
+	"MarshalJSON": true, "UnmarshalJSON": true, "MarshalText": true, "UnmarshalText": true,
+	"MarshalYAML": true, "UnmarshalYAML": true, "Len": true, "Less": true, "Swap": true,
+}
+
+// TestMapAnalyzer maps the exported functions and methods of Go packages to the tests in the
+// same directory that refer to them, by name or by calling them, and reports the functions no
+// test refers to
+type TestMapAnalyzer struct {
+	fset  *token.FileSet
+	cache *ASTCache // shared parse cache, see SetCache
+}
+
+// FunctionTests is an exported function or method and the tests that refer to it
+type FunctionTests struct {
+	Name  string // Func, or Type.Method for a method
+	File  string
+	Line  int
+	Tests []string // sorted names of the Test, Benchmark, Example and Fuzz functions
+}
+
+// packageTests is what the test files of a package refer to
+type packageTests struct {
+	files int
+	refs  map[string]map[string]bool // names referred to by each test, through test helpers too
+}
+
+// NewTestMapAnalyzer creates a test map analyzer
+func NewTestMapAnalyzer() *TestMapAnalyzer {
+	return &TestMapAnalyzer{fset: token.NewFileSet()}
+}
+
+// SetCache makes the analyzer take ASTs from a cache shared with the other Go analyses
+func (a *TestMapAnalyzer) SetCache(cache *ASTCache) {
+	a.cache = cache
+	a.fset = cache.FileSet()
+}
+
+// AnalyzeFiles maps the packages of the given files and reports their exported functions that
+// no test refers to. A package without test files is reported once, at its first exported
+// function. Findings are only reported in the given files.
+func (a *TestMapAnalyzer) AnalyzeFiles(ctx context.Context, files []string) ([]core.Result, error) {
+	analyzed := make(map[string]bool, len(files))
+	var dirs []string
+	for _, file := range files {
+		analyzed[file] = true
+		if dir := filepath.Dir(file); !containsString(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	var results []core.Result
+	for _, dir := range dirs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		functions, tests, pkg, err := a.mapPackage(dir)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, untestedResults(functions, tests.files, pkg, analyzed)...)
+	}
+	return results, nil
+}
+
+// Map returns the exported functions and methods of the package in dir, in file and line
+// order, with the tests that refer to them
+func (a *TestMapAnalyzer) Map(dir string) ([]FunctionTests, error) {
+	functions, _, _, err := a.mapPackage(dir)
+	return functions, err
+}
+
+// mapPackage parses the Go files of dir and maps the exported functions of its non-test files
+// to the tests of its test files, returning the package name with them. Commands have no
+// exported API and give no functions.
+func (a *TestMapAnalyzer) mapPackage(dir string) ([]FunctionTests, packageTests, string, error) {
+	tests := packageTests{refs: make(map[string]map[string]bool)}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, tests, "", err
+	}
+
+	var sources []*ast.File
+	var paths []string
+	var testFiles []*ast.File
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasPrefix(name, "_") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		f, err := parseWithCache(a.cache, a.fset, path)
+		if err != nil {
+			continue
+		}
+		if strings.HasSuffix(name, "_test.go") {
+			testFiles = append(testFiles, f)
+		} else if !ast.IsGenerated(f) && f.Name.Name != "main" {
+			sources = append(sources, f)
+			paths = append(paths, path)
+		}
+	}
+	if len(sources) == 0 {
+		return nil, tests, "", nil
+	}
+	tests.files = len(testFiles)
+	tests.refs = testReferences(testFiles)
+
+	var functions []FunctionTests
+	for i, f := range sources {
+		for _, decl := range f.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || !isMappedFunction(funcDecl) {
+				continue
+			}
+			function := FunctionTests{
+				Name: funcDecl.Name.Name,
+				File: paths[i],
+				Line: a.fset.Position(funcDecl.Pos()).Line,
+			}
+			receiver := getReceiverTypeName(funcDecl)
+			if receiver != "" {
+				function.Name = receiver + "." + funcDecl.Name.Name
+			}
+			for test, refs := range tests.refs {
+				if refs[funcDecl.Name.Name] || testNameMatches(test, receiver, funcDecl.Name.Name) {
+					function.Tests = append(function.Tests, test)
+				}
+			}
+			sort.Strings(function.Tests)
+			functions = append(functions, function)
+		}
+	}
+	return functions, tests, sources[0].Name.Name, nil
+}
+
+// isMappedFunction reports whether a function is part of a package's exported API: an
+// exported function, or an exported method of an exported type that no standard interface
+// calls for the tests
+func isMappedFunction(funcDecl *ast.FuncDecl) bool {
+	if !funcDecl.Name.IsExported() {
+		return false
+	}
+	if funcDecl.Recv == nil {
+		return true
+	}
+	receiver := getReceiverTypeName(funcDecl)
+	return receiver != "" && ast.IsExported(receiver) && !interfaceMethods[funcDecl.Name.Name]
+}
+
+// testReferences returns the names each test of the test files refers to, with the names its
+// helpers refer to: the other functions and package-level variables of the test files, such as
+// a shared table of cases
+func testReferences(files []*ast.File) map[string]map[string]bool {
+	helpers := make(map[string]map[string]bool)
+	var entries []string
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Body == nil || decl.Recv != nil {
+					continue
+				}
+				helpers[decl.Name.Name] = identNames(decl.Body)
+				if isTestEntry(decl.Name.Name) {
+					entries = append(entries, decl.Name.Name)
+				}
+			case *ast.GenDecl:
+				if decl.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					valueSpec := spec.(*ast.ValueSpec)
+					for _, name := range valueSpec.Names {
+						helpers[name.Name] = identNames(valueSpec)
+					}
+				}
+			}
+		}
+	}
+
+	refs := make(map[string]map[string]bool, len(entries))
+	for _, entry := range entries {
+		reached := make(map[string]bool)
+		pending := []string{entry}
+		for len(pending) > 0 {
+			name := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+			for ref := range helpers[name] {
+				if !reached[ref] {
+					reached[ref] = true
+					if helpers[ref] != nil && !isTestEntry(ref) {
+						pending = append(pending, ref)
+					}
+				}
+			}
+		}
+		refs[entry] = reached
+	}
+	return refs
+}
+
+// identNames returns the identifiers used in a node, which include the functions it calls and
+// the methods it selects
+func identNames(node ast.Node) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			names[ident.Name] = true
+		}
+		return true
+	})
+	return names
+}
+
+// isTestEntry reports whether a function name is that of a function go test runs, such as
+// TestParse, BenchmarkParse or Example
+func isTestEntry(name string) bool {
+	for _, prefix := range testEntryPrefixes {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && (rest == "" || !unicode.IsLower(rune(rest[0]))) {
+			return true
+		}
+	}
+	return false
+}
+
+// testNameMatches reports whether a test is named after a function or method, as in
+// TestParse, TestParse_EmptyInput, TestStack_Push, TestStackPush or ExampleStack_Push
+func testNameMatches(test, receiver, name string) bool {
+	var subject string
+	for _, prefix := range testEntryPrefixes {
+		if rest, ok := strings.CutPrefix(test, prefix); ok {
+			subject = strings.TrimPrefix(rest, "_")
+			break
+		}
+	}
+	parts := strings.Split(subject, "_")
+	if receiver == "" {
+		return parts[0] == name
+	}
+	return parts[0] == receiver+name || (parts[0] == receiver && len(parts) > 1 && parts[1] == name)
+}
+
+// untestedResults reports the functions of a package in the analyzed files that no test refers
+// to, or only the first function when the package has no test files at all
+func untestedResults(functions []FunctionTests, testFiles int, pkg string, analyzed map[string]bool) []core.Result {
+	var results []core.Result
+	for _, function := range functions {
+		if len(function.Tests) > 0 || !analyzed[function.File] {
+			continue
+		}
+		result := core.Result{
+			RuleID:     UntestedFunctionRuleID,
+			RuleName:   "Untested Function",
+			Category:   string(core.CategoryStyle),
+			Severity:   string(core.SeverityInfo),
+			Confidence: string(core.ConfidenceMedium),
+			FilePath:   function.File,
+			Line:       function.Line,
+			Message:    fmt.Sprintf("Exported function '%s' is not referred to by any test of package %s", function.Name, pkg),
+			Suggestion: fmt.Sprintf("Add a test that calls %s, or unexport it if only the package uses it", function.Name),
+		}
+		if testFiles == 0 {
+			result.Message = fmt.Sprintf("Package %s has no tests for its %d exported functions", pkg, len(functions))
+			result.Suggestion = "Add a _test.go file that exercises the package's exported API"
+			return append(results, result)
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,146 @@
+package golang
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTestNameMatches(t *testing.T) {
+	tests := []struct {
+		test, receiver, name string
+		want                 bool
+	}{
+		{"TestParse", "", "Parse", true},
+		{"TestParse_EmptyInput", "", "Parse", true},
+		{"TestParser", "", "Parse", false},
+		{"ExampleParse", "", "Parse", true},
+		{"TestStack_Push", "Stack", "Push", true},
+		{"TestStackPush", "Stack", "Push", true},
+		{"Example_Stack_Push", "Stack", "Push", true},
+		{"TestStack", "Stack", "Push", false},
+		{"TestQueue_Push", "Stack", "Push", false},
+	}
+	for _, tt := range tests {
+		if got := testNameMatches(tt.test, tt.receiver, tt.name); got != tt.want {
+			t.Errorf("testNameMatches(%q, %q, %q) = %v, want %v", tt.test, tt.receiver, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTestMapAnalyzer(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(dir, name, content string) string {
+		path := filepath.Join(tmpDir, dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	stack := write("stack", "stack.go", `package stack
+
+type Stack struct{ items []int }
+
+func New() *Stack { return &Stack{} }
+
+func (s *Stack) Push(v int) { s.items = append(s.items, v) }
+
+func (s *Stack) Pop() int {
+	v := s.items[len(s.items)-1]
+	s.items = s.items[:len(s.items)-1]
+	return v
+}
+
+func (s *Stack) Peek() int { return s.items[len(s.items)-1] }
+
+func (s *Stack) String() string { return "stack" }
+
+func Sum(s *Stack) int { return 0 }
+
+func Drain(s *Stack) {}
+
+func helper() {}
+`)
+	write("stack", "stack_test.go", `package stack
+
+import "testing"
+
+var pushes = []int{1, 2, 3}
+
+func fill(s *Stack) {
+	for _, v := range pushes {
+		s.Push(v)
+	}
+}
+
+func TestStack_Pop(t *testing.T) {
+	s := New()
+	fill(s)
+	if s.Pop() != 3 {
+		t.Error("wrong value")
+	}
+}
+
+func TestSum(t *testing.T) {}
+`)
+	queue := write("queue", "queue.go", `package queue
+
+func Enqueue() {}
+
+func Dequeue() {}
+`)
+	write("cmd", "main.go", `package main
+
+func Run() {}
+
+func main() {}
+`)
+
+	analyzer := NewTestMapAnalyzer()
+	functions, err := analyzer.Map(filepath.Join(tmpDir, "stack"))
+	if err != nil {
+		t.Fatalf("Failed to map package: %v", err)
+	}
+	got := make(map[string][]string)
+	for _, function := range functions {
+		got[function.Name] = function.Tests
+	}
+	want := map[string][]string{
+		"New":        {"TestStack_Pop"},
+		"Stack.Push": {"TestStack_Pop"},
+		"Stack.Pop":  {"TestStack_Pop"},
+		"Stack.Peek": nil,
+		"Sum":        {"TestSum"},
+		"Drain":      nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected test map %v, got %v", want, got)
+	}
+
+	results, err := analyzer.AnalyzeFiles(context.Background(), []string{stack, queue, filepath.Join(tmpDir, "cmd", "main.go")})
+	if err != nil {
+		t.Fatalf("Failed to analyze files: %v", err)
+	}
+	var messages []string
+	for _, result := range results {
+		if result.RuleID != UntestedFunctionRuleID {
+			t.Errorf("Unexpected rule %s", result.RuleID)
+		}
+		messages = append(messages, filepath.Base(result.FilePath)+": "+result.Message)
+	}
+	wantMessages := []string{
+		"stack.go: Exported function 'Stack.Peek' is not referred to by any test of package stack",
+		"stack.go: Exported function 'Drain' is not referred to by any test of package stack",
+		"queue.go: Package queue has no tests for its 2 exported functions",
+	}
+	if strings.Join(messages, "\n") != strings.Join(wantMessages, "\n") {
+		t.Errorf("Expected findings:\n%s\ngot:\n%s", strings.Join(wantMessages, "\n"), strings.Join(messages, "\n"))
+	}
+}
@@ -0,0 +1,298 @@
+package golang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/golang/rules"
+)
+
+// outcomeMethods are the methods of testing.T that fail or skip the test
+var outcomeMethods = map[string]bool{
+	"Error": true, "Errorf": true, "Fatal": true, "Fatalf": true, "Fail": true, "FailNow": true,
+	"Skip": true, "Skipf": true, "SkipNow": true,
+}
+
+// assertionPackages are the import names of assertion libraries
+var assertionPackages = map[string]bool{"assert": true, "require": true, "is": true, "qt": true, "quicktest": true, "gomega": true, "check": true, "expect": true}
+
+// assertionPrefixes start the names of assertion functions and methods, such as assertEqual,
+// Expect or c.Assert
+var assertionPrefixes = []string{"assert", "require", "expect", "should", "must"}
+
+// builtinCalls are the builtin functions and conversions to predeclared types
+var builtinCalls = map[string]bool{
+	"append": true, "cap": true, "clear": true, "close": true, "complex": true, "copy": true, "delete": true,
+	"imag": true, "len": true, "make": true, "max": true, "min": true, "new": true, "print": true,
+	"println": true, "real": true, "recover": true, "bool": true, "byte": true, "rune": true, "string": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true, "uint": true, "uint8": true,
+	"uint16": true, "uint32": true, "uint64": true, "uintptr": true, "float32": true, "float64": true,
+	"complex64": true, "complex128": true, "error": true, "any": true,
+}
+
+// testScope is what a test function's calls are classified against
+type testScope struct {
+	testing    map[string]bool // names of the *testing.T parameters of the test and its subtests
+	assertions map[string]bool // import names of assertion libraries
+	stdlib     map[string]bool // import names of standard library packages
+	packages   map[string]bool // import names of the other packages
+	fields     map[string]bool // keys of the composite literals the test builds
+}
+
+// testCalls sorts the calls of a test function
+type testCalls struct {
+	asserts  bool
+	getters  []string
+	other    bool            // calls something that is not an accessor, a constructor or part of the harness
+	subjects map[string]bool // functions and methods called outside the harness and assertions
+}
+
+// collectTestFunctions describes the Test functions of a _test.go file. A test is a near
+// duplicate of an earlier one when both call the same functions and share threshold of their
+// code, see languages.FindDuplicateTests.
+func collectTestFunctions(file *ast.File, fset *token.FileSet, threshold float64) []*rules.TestFunction {
+	testingName := importedName(file, "testing")
+	if testingName == "" {
+		return nil
+	}
+	scope := newTestScope(file)
+
+	var tests []*rules.TestFunction
+	var bodies []languages.TestBody
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil || !isTestFunction(funcDecl, testingName) {
+			continue
+		}
+		calls := classifyTestCalls(funcDecl, scope, testingName)
+		test := &rules.TestFunction{Name: funcDecl.Name.Name, Line: fset.Position(funcDecl.Pos()).Line, Asserts: calls.asserts}
+		if !calls.other {
+			test.Getters = calls.getters
+		}
+		tests = append(tests, test)
+		bodies = append(bodies, languages.TestBody{Tokens: bodyTokens(funcDecl.Body), Subject: sortedKeys(calls.subjects)})
+	}
+
+	for i, duplicate := range languages.FindDuplicateTests(bodies, threshold) {
+		if duplicate.Index >= 0 {
+			tests[i].DuplicateOf = tests[duplicate.Index].Name
+			tests[i].DuplicateLine = tests[duplicate.Index].Line
+			tests[i].Similarity = duplicate.Similarity
+		}
+	}
+	return tests
+}
+
+// isTestFunction reports whether a function is a Test function run by go test
+func isTestFunction(funcDecl *ast.FuncDecl, testingName string) bool {
+	name := funcDecl.Name.Name
+	if funcDecl.Recv != nil || !strings.HasPrefix(name, "Test") {
+		return false
+	}
+	if len(name) > 4 && unicode.IsLower(rune(name[4])) {
+		return false
+	}
+	params := funcDecl.Type.Params.List
+	return len(params) == 1 && len(params[0].Names) <= 1 && isTestingType(params[0].Type, testingName)
+}
+
+// isTestingType reports whether a parameter type is *testing.T or testing.TB
+func isTestingType(expr ast.Expr, testingName string) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == testingName && (sel.Sel.Name == "T" || sel.Sel.Name == "TB")
+}
+
+// newTestScope sorts the imports of a test file into assertion libraries and the standard library
+func newTestScope(file *ast.File) testScope {
+	scope := testScope{assertions: make(map[string]bool), stdlib: make(map[string]bool), packages: make(map[string]bool)}
+	for _, spec := range file.Imports {
+		path := strings.Trim(spec.Path.Value, `"`)
+		name := importName(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if assertionPackages[importName(path)] {
+			scope.assertions[name] = true
+		} else if !strings.Contains(strings.Split(path, "/")[0], ".") {
+			scope.stdlib[name] = true
+		} else {
+			scope.packages[name] = true
+		}
+	}
+	return scope
+}
+
+// classifyTestCalls sorts every call in a test function, including its subtests, into
+// assertions, accessors and other calls
+func classifyTestCalls(funcDecl *ast.FuncDecl, scope testScope, testingName string) testCalls {
+	scope.testing = make(map[string]bool)
+	scope.fields = make(map[string]bool)
+	ast.Inspect(funcDecl, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncType:
+			for _, field := range node.Params.List {
+				for _, name := range field.Names {
+					if isTestingType(field.Type, testingName) {
+						scope.testing[name.Name] = true
+					}
+				}
+			}
+		case *ast.KeyValueExpr:
+			if key, ok := node.Key.(*ast.Ident); ok {
+				scope.fields[strings.ToLower(key.Name)] = true
+			}
+		}
+		return true
+	})
+
+	calls := testCalls{subjects: make(map[string]bool)}
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			calls.add(call, scope)
+		}
+		return true
+	})
+	return calls
+}
+
+// add classifies one call of a test
+func (c *testCalls) add(call *ast.CallExpr, scope testScope) {
+	for _, arg := range call.Args {
+		if ident, ok := arg.(*ast.Ident); ok && scope.testing[ident.Name] {
+			c.asserts = true
+			return
+		}
+	}
+
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		switch {
+		case fun.Name == "panic" || isAssertionName(fun.Name):
+			c.asserts = true
+		case builtinCalls[fun.Name]:
+		default:
+			c.subjects[fun.Name] = true
+			c.other = c.other || !isConstructorName(fun.Name)
+		}
+	case *ast.SelectorExpr:
+		c.addSelector(call, fun, scope)
+	case *ast.FuncLit, *ast.ArrayType, *ast.MapType, *ast.ChanType, *ast.InterfaceType, *ast.ParenExpr:
+	default:
+		c.other = true
+	}
+}
+
+// addSelector classifies a call of a package function or a method. Only methods count as
+// accessors: a package function named GetStats computes what it returns.
+func (c *testCalls) addSelector(call *ast.CallExpr, fun *ast.SelectorExpr, scope testScope) {
+	name := fun.Sel.Name
+	method := true
+	if x, ok := fun.X.(*ast.Ident); ok {
+		method = !scope.packages[x.Name]
+		switch {
+		case scope.testing[x.Name]:
+			if outcomeMethods[name] || (name == "Run" && len(call.Args) == 2 && !isFuncLit(call.Args[1])) {
+				c.asserts = true
+			}
+			return
+		case scope.assertions[x.Name]:
+			c.asserts = true
+			return
+		case scope.stdlib[x.Name]:
+			return
+		}
+	}
+	if isAssertionName(name) {
+		c.asserts = true
+		return
+	}
+
+	c.subjects[name] = true
+	switch {
+	case isConstructorName(name):
+	case method && len(call.Args) == 0 && (languages.IsGetterName(name) || scope.fields[strings.ToLower(name)]):
+		c.addGetter(name)
+	case method && len(call.Args) == 1 && languages.IsSetterName(name):
+		c.addGetter(name)
+	default:
+		c.other = true
+	}
+}
+
+// addGetter records an accessor the first time it is called
+func (c *testCalls) addGetter(name string) {
+	for _, getter := range c.getters {
+		if getter == name {
+			return
+		}
+	}
+	c.getters = append(c.getters, name)
+}
+
+// isAssertionName reports whether a function or method name is that of an assertion
+func isAssertionName(name string) bool {
+	lower := strings.ToLower(name)
+	for _, prefix := range assertionPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isConstructorName reports whether a function name is that of a constructor, such as NewUser
+func isConstructorName(name string) bool {
+	return strings.HasPrefix(name, "New") || strings.HasPrefix(name, "new")
+}
+
+func isFuncLit(expr ast.Expr) bool {
+	_, ok := expr.(*ast.FuncLit)
+	return ok
+}
+
+// bodyTokens flattens a function body into its node types, identifiers and operators, with
+// literals reduced to their kind
+func bodyTokens(body *ast.BlockStmt) []string {
+	var tokens []string
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case nil:
+		case *ast.Ident:
+			tokens = append(tokens, node.Name)
+		case *ast.BasicLit:
+			tokens = append(tokens, node.Kind.String())
+		case *ast.BinaryExpr:
+			tokens = append(tokens, node.Op.String())
+		case *ast.UnaryExpr:
+			tokens = append(tokens, node.Op.String())
+		case *ast.AssignStmt:
+			tokens = append(tokens, node.Tok.String())
+		default:
+			tokens = append(tokens, fmt.Sprintf("%T", n))
+		}
+		return true
+	})
+	return tokens
+}
+
+// sortedKeys returns the keys of a set, sorted and joined with spaces
+func sortedKeys(set map[string]bool) string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, " ")
+}
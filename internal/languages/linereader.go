@@ -0,0 +1,124 @@
+package languages
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// MinifiedFileRuleID identifies the result reported for a JavaScript/TypeScript file skipped
+// because its lines are too long to be hand-written code
+const MinifiedFileRuleID = "minified-file"
+
+func init() {
+	core.RegisterRuleDoc(core.RuleDoc{
+		ID:          MinifiedFileRuleID,
+		Name:        "Minified File Skipped",
+		Description: "Reports JavaScript/TypeScript files skipped because their lines are too long to be hand-written",
+		Rationale: "Minified and bundled code is generated from sources that are analyzed on their own, " +
+			"and its megabyte-long lines would only produce noise. No one writes lines that long by hand, " +
+			"so the file is not analyzed and this finding, at its first long line, says so.",
+		Category:  core.CategoryStyle,
+		Severity:  core.SeverityInfo,
+		Languages: []string{"reactnative"},
+		Examples: []core.RuleExample{{
+			Bad:  `dist/app.min.js: !function(e){var t={};function n(r){if(t[r])return t[r].exports;...`,
+			Good: `files.exclude: ["dist/**"], with src/app.ts analyzed instead`,
+		}},
+		Options: []core.RuleOption{
+			{Key: "parsing.maxLineLength", Flag: "-max-line-length", Default: "65536", Description: "Length in bytes above which lines are truncated and JS/TS files skipped as minified, 0 never"},
+		},
+	})
+}
+
+// LineReader reads the lines of a file like bufio.Scanner, but instead of failing on a line
+// longer than its buffer it truncates lines longer than a maximum length and records them
+type LineReader struct {
+	reader    *bufio.Reader
+	maxLength int // 0 reads lines of any length
+	line      string
+	lineNum   int
+	long      []int
+	err       error
+}
+
+// NewLineReader creates a reader of the lines of r, decoded by NewSourceReader, that truncates
+// lines longer than maxLength bytes, or never truncates when maxLength is 0 or less
+func NewLineReader(r io.Reader, maxLength int) *LineReader {
+	return &LineReader{reader: bufio.NewReader(NewSourceReader(r)), maxLength: maxLength}
+}
+
+// Scan reads the next line, reporting false at the end of the input or on an error. Like
+// bufio.ScanLines, it strips the line ending, including a carriage return before it.
+func (r *LineReader) Scan() bool {
+	var buf []byte
+	truncated := false
+	for first := true; ; first = false {
+		chunk, isPrefix, err := r.reader.ReadLine()
+		if err != nil {
+			if err != io.EOF {
+				r.err = err
+			}
+			if first {
+				return false
+			}
+			break
+		}
+		if r.maxLength > 0 && len(buf)+len(chunk) > r.maxLength {
+			chunk = chunk[:r.maxLength-len(buf)]
+			truncated = true
+		}
+		buf = append(buf, chunk...)
+		if !isPrefix {
+			break
+		}
+	}
+
+	r.lineNum++
+	if truncated {
+		// do not leave half a character at the cut
+		for i := 1; i < utf8.UTFMax && len(buf) > 0; i++ {
+			if c, size := utf8.DecodeLastRune(buf); c != utf8.RuneError || size > 1 {
+				break
+			}
+			buf = buf[:len(buf)-1]
+		}
+		r.long = append(r.long, r.lineNum)
+	}
+	r.line = string(buf)
+	return true
+}
+
+// Text returns the line read by the last call to Scan
+func (r *LineReader) Text() string {
+	return r.line
+}
+
+// Err returns the first error other than io.EOF met while reading
+func (r *LineReader) Err() error {
+	return r.err
+}
+
+// LongLines returns the numbers of the lines read so far that were truncated
+func (r *LineReader) LongLines() []int {
+	return r.long
+}
+
+// MinifiedFileResult reports a JavaScript/TypeScript file that was not analyzed because line
+// is longer than maxLength bytes, which only minified or bundled code has
+func MinifiedFileResult(filePath string, line, maxLength int) core.Result {
+	return core.Result{
+		RuleID:     MinifiedFileRuleID,
+		RuleName:   "Minified File Skipped",
+		Category:   string(core.CategoryStyle),
+		Severity:   string(core.SeverityInfo),
+		FilePath:   filePath,
+		Line:       line,
+		Message:    fmt.Sprintf("File looks minified, with lines longer than %d bytes, and was not analyzed", maxLength),
+		Suggestion: "Exclude minified and bundled files from analysis, or analyze their sources instead",
+		Confidence: string(core.ConfidenceHigh),
+	}
+}
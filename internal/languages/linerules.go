@@ -0,0 +1,25 @@
+package languages
+
+import "github.com/CiaranMcAleer/AgentLint/internal/core"
+
+// lineFinding identifies the finding of one rule on one line
+type lineFinding struct {
+	ruleID string
+	line   int
+}
+
+// LineFindings records the lines each line rule has reported, so that a file gets at most one
+// finding per rule and line even when several of a rule's patterns, or several rules sharing
+// an ID, match the same line
+type LineFindings map[lineFinding]bool
+
+// Add records the finding of a result and reports whether it is the first one of its rule on
+// its line
+func (f LineFindings) Add(result *core.Result) bool {
+	key := lineFinding{result.RuleID, result.Line}
+	if f[key] {
+		return false
+	}
+	f[key] = true
+	return true
+}
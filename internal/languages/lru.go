@@ -0,0 +1,85 @@
+package languages
+
+import "container/list"
+
+// LRU orders the keys of a parse cache from most to least recently used and picks the ones to
+// evict once the cache holds more than its capacity. It is not safe for concurrent use: the
+// caches call it under their own lock.
+type LRU struct {
+	capacity int
+	order    *list.List // front is the most recently used key
+	elements map[string]*list.Element
+}
+
+// NewLRU creates an LRU holding at most capacity keys; 0 holds any number
+func NewLRU(capacity int) *LRU {
+	return &LRU{capacity: capacity, order: list.New(), elements: make(map[string]*list.Element)}
+}
+
+// SetCapacity changes the number of keys held and returns the keys evicted to fit it
+func (l *LRU) SetCapacity(capacity int) []string {
+	l.capacity = capacity
+	return l.evict()
+}
+
+// Touch marks key as just used, adding it when it is new, and returns the keys evicted to make
+// room for it
+func (l *LRU) Touch(key string) []string {
+	if element, ok := l.elements[key]; ok {
+		l.order.MoveToFront(element)
+		return nil
+	}
+	l.elements[key] = l.order.PushFront(key)
+	return l.evict()
+}
+
+// Remove forgets key
+func (l *LRU) Remove(key string) {
+	if element, ok := l.elements[key]; ok {
+		l.order.Remove(element)
+		delete(l.elements, key)
+	}
+}
+
+// Clear forgets every key
+func (l *LRU) Clear() {
+	l.order.Init()
+	l.elements = make(map[string]*list.Element)
+}
+
+// Len returns the number of keys held
+func (l *LRU) Len() int {
+	return l.order.Len()
+}
+
+func (l *LRU) evict() []string {
+	var evicted []string
+	for l.capacity > 0 && l.order.Len() > l.capacity {
+		key := l.order.Remove(l.order.Back()).(string)
+		delete(l.elements, key)
+		evicted = append(evicted, key)
+	}
+	return evicted
+}
+
+// CacheReporter is implemented by analyzers that keep parsed files in a cache of their own,
+// so long runs can report how well it works
+type CacheReporter interface {
+	CacheStats() CacheStats
+}
+
+// CacheStats counts the lookups of a parse cache
+type CacheStats struct {
+	Entries   int
+	Hits      int64 // lookups answered from the cache
+	Misses    int64 // lookups that had to parse the file
+	Evictions int64 // entries dropped to stay within the cache's capacity
+}
+
+// HitRate returns the share of lookups answered from the cache, 0 before any lookup
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
@@ -0,0 +1,49 @@
+package languages_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+)
+
+func TestLRU(t *testing.T) {
+	lru := languages.NewLRU(2)
+	if evicted := lru.Touch("a.go"); evicted != nil {
+		t.Errorf("Expected nothing evicted below the capacity, got %v", evicted)
+	}
+	lru.Touch("b.go")
+	lru.Touch("a.go")
+	if evicted := lru.Touch("c.go"); !reflect.DeepEqual(evicted, []string{"b.go"}) {
+		t.Errorf("Expected the least recently used key evicted, got %v", evicted)
+	}
+
+	lru.Remove("a.go")
+	if lru.Len() != 1 {
+		t.Errorf("Expected 1 key after removing one, got %d", lru.Len())
+	}
+	lru.Touch("d.go")
+	if evicted := lru.SetCapacity(1); !reflect.DeepEqual(evicted, []string{"c.go"}) {
+		t.Errorf("Expected shrinking the capacity to evict c.go, got %v", evicted)
+	}
+
+	lru.SetCapacity(0)
+	for _, key := range []string{"e.go", "f.go", "g.go"} {
+		if evicted := lru.Touch(key); evicted != nil {
+			t.Errorf("Expected no evictions without a capacity, got %v", evicted)
+		}
+	}
+	lru.Clear()
+	if lru.Len() != 0 {
+		t.Errorf("Expected no keys after Clear, got %d", lru.Len())
+	}
+}
+
+func TestCacheStatsHitRate(t *testing.T) {
+	if rate := (languages.CacheStats{}).HitRate(); rate != 0 {
+		t.Errorf("Expected a hit rate of 0 before any lookup, got %v", rate)
+	}
+	if rate := (languages.CacheStats{Hits: 3, Misses: 1}).HitRate(); rate != 0.75 {
+		t.Errorf("Expected a hit rate of 0.75, got %v", rate)
+	}
+}
@@ -0,0 +1,200 @@
+package languages
+
+import "strings"
+
+// Syntax describes the comments and string literals of a language, for MaskCode
+type Syntax struct {
+	LineComment  string    // starts a comment that runs to the end of the line
+	BlockComment [2]string // open and close a comment that may span lines; empty when there is none
+	Quotes       string    // delimit string literals that end on their line
+	TripleQuotes bool      // a tripled quote delimits a string that may span lines, as in Python
+	Templates    bool      // backquotes delimit template literals that may span lines, whose ${} substitutions are code
+	Regex        bool      // a slash where a value is expected starts a regular expression literal
+}
+
+// JavaScript is the syntax of JavaScript and TypeScript
+var JavaScript = Syntax{LineComment: "//", BlockComment: [2]string{"/*", "*/"}, Quotes: `"'`, Templates: true, Regex: true}
+
+// Python is the syntax of Python
+var Python = Syntax{LineComment: "#", Quotes: `"'`, TripleQuotes: true}
+
+// regexPredecessors are the characters after which a slash starts a regular expression rather
+// than a division
+const regexPredecessors = "(,=:[!&|?{};+-*%<>~^"
+
+// RegexCanStart reports whether a slash following the significant character prev, or starting
+// a line when prev is 0, starts a regular expression literal
+func RegexCanStart(prev byte) bool {
+	return prev == 0 || strings.IndexByte(regexPredecessors, prev) >= 0
+}
+
+// jsxSlash reports whether the slash at index i of line belongs to a JSX tag, closing an element
+// as in </View> or ending a self-closing one as in <Row />, rather than starting a regular
+// expression
+func jsxSlash(line string, i int, prev byte) bool {
+	return prev == '<' || i+1 < len(line) && line[i+1] == '>'
+}
+
+// StringRule is implemented by line rules that match the contents of string literals, such as
+// URLs or module paths, and so are given the lines of a file unmasked
+type StringRule interface {
+	ReadsStrings() bool
+}
+
+// ReadsStrings reports whether a line rule needs the contents of string literals
+func ReadsStrings(rule interface{}) bool {
+	r, ok := rule.(StringRule)
+	return ok && r.ReadsStrings()
+}
+
+// MaskCode blanks out comments and the contents of strings, regular expressions and template
+// literals, keeping line lengths and the quotes themselves, so that rules matching lines of
+// code with regular expressions do not fire on text. Template substitutions stay visible as
+// code.
+func MaskCode(lines []string, syntax Syntax) []string {
+	masker := NewMasker(syntax)
+	masked := make([]string, len(lines))
+	for n, line := range lines {
+		masked[n] = masker.Mask(line)
+	}
+	return masked
+}
+
+// Masker masks the lines of a file one at a time, as MaskCode does, carrying comments and
+// strings that span lines over to the next line, so a file can be masked as it is read
+type Masker struct {
+	syntax         Syntax
+	open           []byte // '{', '`' or '$': braces, template literals and their substitutions
+	longQuote      string // the delimiter of an open triple-quoted string
+	inBlockComment bool
+}
+
+// NewMasker creates a masker for the first line of a file
+func NewMasker(syntax Syntax) *Masker {
+	return &Masker{syntax: syntax}
+}
+
+// Mask returns the next line of the file masked
+func (m *Masker) Mask(line string) string {
+	syntax := m.syntax
+	out := []byte(line)
+	blank := func(from, to int) {
+		for k := from; k < to && k < len(out); k++ {
+			out[k] = ' '
+		}
+	}
+
+	var prev byte
+scan:
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		if m.inBlockComment {
+			if end := syntax.BlockComment[1]; strings.HasPrefix(line[i:], end) {
+				m.inBlockComment = false
+				blank(i, i+len(end))
+				i += len(end) - 1
+			} else {
+				out[i] = ' '
+			}
+			continue
+		}
+		if m.longQuote != "" {
+			switch {
+			case strings.HasPrefix(line[i:], m.longQuote):
+				i += len(m.longQuote) - 1
+				m.longQuote = ""
+			case ch == '\\':
+				blank(i, i+2)
+				i++
+			default:
+				out[i] = ' '
+			}
+			continue
+		}
+		if k := len(m.open); k > 0 && m.open[k-1] == '`' {
+			switch {
+			case ch == '`':
+				m.open = m.open[:k-1]
+			case strings.HasPrefix(line[i:], "${"):
+				m.open = append(m.open, '$')
+				i++
+			case ch == '\\':
+				blank(i, i+2)
+				i++
+			default:
+				out[i] = ' '
+			}
+			continue
+		}
+
+		switch {
+		case ch == ' ' || ch == '\t':
+			continue
+		case strings.IndexByte(syntax.Quotes, ch) >= 0:
+			if triple := strings.Repeat(string(ch), 3); syntax.TripleQuotes && strings.HasPrefix(line[i:], triple) {
+				m.longQuote = triple
+				i += 2
+				break
+			}
+			end := SkipQuoted(line, i)
+			blank(i+1, end)
+			i = end
+		case syntax.Templates && ch == '`':
+			m.open = append(m.open, '`')
+		case syntax.LineComment != "" && strings.HasPrefix(line[i:], syntax.LineComment):
+			blank(i, len(line))
+			break scan
+		case syntax.BlockComment[0] != "" && strings.HasPrefix(line[i:], syntax.BlockComment[0]):
+			m.inBlockComment = true
+			blank(i, i+len(syntax.BlockComment[0]))
+			i += len(syntax.BlockComment[0]) - 1
+			continue
+		case syntax.Regex && ch == '/' && RegexCanStart(prev) && !jsxSlash(line, i, prev):
+			end := SkipRegex(line, i)
+			blank(i+1, end)
+			i = end
+		case syntax.Templates && ch == '{':
+			m.open = append(m.open, '{')
+		case syntax.Templates && ch == '}':
+			if k := len(m.open); k > 0 {
+				m.open = m.open[:k-1]
+			}
+		}
+		prev = ch
+	}
+	return string(out)
+}
+
+// SkipQuoted returns the index of the quote closing the string starting at start, or the end of
+// the line for an unterminated string
+func SkipQuoted(line string, start int) int {
+	quote := line[start]
+	for i := start + 1; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+		} else if line[i] == quote {
+			return i
+		}
+	}
+	return len(line)
+}
+
+// SkipRegex returns the index of the slash closing the regular expression starting at start
+func SkipRegex(line string, start int) int {
+	inClass := false
+	for i := start + 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				return i
+			}
+		}
+	}
+	return len(line)
+}
@@ -0,0 +1,118 @@
+package languages
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// PathFilter selects files by include and exclude globs matched against slash-separated paths
+// relative to the scanned directory. "*" matches within one path element, "**" matches any
+// number of directories, and a pattern without a slash matches the base name at any depth,
+// so "*.pb.go" excludes generated protobuf files anywhere in the tree.
+type PathFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// NewPathFilter compiles include and exclude globs. With no include patterns every file is
+// included; a file matching any exclude pattern is skipped, as is every file in a directory
+// matching one.
+func NewPathFilter(include, exclude []string) (*PathFilter, error) {
+	f := &PathFilter{}
+	var err error
+	if f.include, err = compileGlobs(include); err != nil {
+		return nil, err
+	}
+	if f.exclude, err = compileGlobs(exclude); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Match reports whether the file at relPath is selected
+func (f *PathFilter) Match(relPath string) bool {
+	if f == nil {
+		return true
+	}
+	relPath = filepath.ToSlash(relPath)
+	if matchesAnyGlob(f.exclude, relPath) {
+		return false
+	}
+	return len(f.include) == 0 || matchesAnyGlob(f.include, relPath)
+}
+
+// excluded reports whether the file at relPath matches an exclude glob
+func (f *PathFilter) excluded(relPath string) bool {
+	return matchesAnyGlob(f.exclude, filepath.ToSlash(relPath))
+}
+
+// SkipDir reports whether the directory at relPath is excluded, so it need not be walked
+func (f *PathFilter) SkipDir(relPath string) bool {
+	return f != nil && relPath != "." && matchesAnyGlob(f.exclude, filepath.ToSlash(relPath))
+}
+
+func matchesAnyGlob(patterns []*regexp.Regexp, path string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func compileGlobs(globs []string) ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(globs))
+	for _, glob := range globs {
+		pattern, err := regexp.Compile(globToRegexp(glob))
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", glob, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// globToRegexp translates a glob into an anchored regular expression. A trailing "/**" also
+// matches the directory itself, so "**/generated/**" excludes the generated directories.
+func globToRegexp(glob string) string {
+	glob = strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(glob), "./"), "/")
+	var b strings.Builder
+	b.WriteString("^")
+	if !strings.Contains(glob, "/") {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case glob[i:] == "/**":
+			b.WriteString("(?:/.*)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			if end := strings.IndexByte(glob[i+1:], ']'); end >= 0 {
+				class := glob[i+1 : i+1+end]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				b.WriteString("[" + class + "]")
+				i += end + 1
+				continue
+			}
+			b.WriteString(regexp.QuoteMeta("["))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
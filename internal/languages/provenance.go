@@ -0,0 +1,113 @@
+package languages
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// aiMarkers are phrases assistants and the tools around them leave in the header comment of
+// the files they write
+var aiMarkers = []string{
+	"ai-generated",
+	"ai generated",
+	"generated by ai",
+	"generated by an ai",
+	"generated by copilot",
+	"generated by github copilot",
+	"generated by chatgpt",
+	"generated by gpt",
+	"generated by claude",
+	"generated by gemini",
+	"generated by cursor",
+	"generated with copilot",
+	"generated with chatgpt",
+	"generated with claude",
+	"written by chatgpt",
+	"written by copilot",
+	"written by claude",
+}
+
+// aiPathFilters caches the compiled AIFilesConfig.Paths, keyed by the joined globs
+var aiPathFilters sync.Map
+
+// IsAIGenerated reports whether the file at filePath was written by an AI assistant: its path
+// relative to the working directory matches one of aiFiles.Paths, or a header comment in its
+// first lines carries a provenance marker, such as "Generated by Copilot", or one of
+// aiFiles.Markers. Markers are matched case-insensitively.
+func IsAIGenerated(filePath string, aiFiles core.AIFilesConfig) bool {
+	if filter := aiPathFilter(aiFiles.Paths); filter != nil && filter.Match(workingDirPath(filePath)) {
+		return true
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	reader := NewLineReader(file, 1<<10)
+	for i := 0; i < GeneratedHeaderLines && reader.Scan(); i++ {
+		trimmed := strings.TrimSpace(reader.Text())
+		if stripCommentMarker(trimmed) == trimmed {
+			continue // not a comment
+		}
+		lower := strings.ToLower(trimmed)
+		for _, marker := range aiMarkers {
+			if strings.Contains(lower, marker) {
+				return true
+			}
+		}
+		for _, marker := range aiFiles.Markers {
+			if marker != "" && strings.Contains(lower, strings.ToLower(marker)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ForAIFile returns config with the thresholds for AI-generated files applied when the file
+// at filePath is one. The file is only looked at when there are such thresholds.
+func ForAIFile(filePath string, config core.Config) core.Config {
+	if config.AIFiles.Rules == (core.LanguageRulesConfig{}) || !IsAIGenerated(filePath, config.AIFiles) {
+		return config
+	}
+	return config.ForAIFile()
+}
+
+// aiPathFilter returns the filter selecting the files matching globs, or nil when there are
+// none or they do not compile
+func aiPathFilter(globs []string) *PathFilter {
+	if len(globs) == 0 {
+		return nil
+	}
+	key := strings.Join(globs, "\x00")
+	if filter, ok := aiPathFilters.Load(key); ok {
+		return filter.(*PathFilter)
+	}
+	filter, err := NewPathFilter(globs, nil)
+	if err != nil {
+		filter = nil
+	}
+	aiPathFilters.Store(key, filter)
+	return filter
+}
+
+// workingDirPath returns path relative to the working directory when it lies inside it
+func workingDirPath(path string) string {
+	path = filepath.Clean(path)
+	if !filepath.IsAbs(path) {
+		return path
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
@@ -5,16 +5,21 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
 	"github.com/CiaranMcAleer/AgentLint/internal/languages/python/rules"
+	"github.com/CiaranMcAleer/AgentLint/internal/profiling"
 )
 
-// Analyzer implements the core.Analyzer interface for Python
+// Analyzer implements the core.Analyzer interface for Python. It is safe for concurrent use.
 type Analyzer struct {
-	parser *Parser
-	rules  []core.Rule
+	parser    *Parser
+	rules     []core.Rule
+	lineRules []rules.LineCheckRule
 }
 
 // NewAnalyzer creates a new Python analyzer
@@ -25,47 +30,98 @@ func NewAnalyzer(config core.Config) *Analyzer {
 	rulesList := []core.Rule{
 		rules.NewLargeFunctionRule(config),
 		rules.NewLargeFileRule(config),
+		rules.NewGodClassRule(config),
 		rules.NewOvercommentingRule(config),
 		rules.NewUnusedFunctionRule(config),
 		rules.NewUnusedVariableRule(config),
 		rules.NewUnreachableCodeRule(config),
 		rules.NewDeadImportRule(config),
+		rules.NewAICommentRule(config),
+		rules.NewRedundantCommentRule(config),
+		rules.NewPlaceholderDocstringRule(config),
+		rules.NewDocstringParamMismatchRule(config),
+		rules.NewMutableDefaultRule(config),
+		rules.NewSleepSynchronizationRule(config),
+		rules.NewTypeHintCoverageRule(config),
+		rules.NewLongBranchChainRule(config),
+		rules.NewAssertionFreeTestRule(config),
+		rules.NewDuplicateTestRule(config),
+		rules.NewTrivialGetterTestRule(config),
+	}
+
+	lineRulesList := []rules.LineCheckRule{
+		rules.NewBareExceptRule(config),
+		rules.NewPrintDebugRule(config),
+		rules.NewWildcardImportRule(config),
+		rules.NewHardcodedEndpointRule(config),
 	}
 
 	return &Analyzer{
-		parser: parser,
-		rules:  rulesList,
+		parser:    parser,
+		rules:     rulesList,
+		lineRules: lineRulesList,
 	}
 }
 
 // Analyze analyzes a Python file and returns results
 func (a *Analyzer) Analyze(ctx context.Context, filePath string, config core.Config) ([]core.Result, error) {
+	defer profiling.TrackFile(filePath, time.Now())
+
+	config.Rules = config.Language.Python.Rules.Apply(config.Rules)
+	if languages.IsTestFile(filePath) {
+		config = config.ForTestFile()
+	}
+	config = languages.ForAIFile(filePath, config)
+	if languages.ShouldStream(filePath, config) {
+		return a.analyzeStream(ctx, filePath, config)
+	}
+
 	parsed, err := a.parser.ParseFile(ctx, filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse file %s: %w", filePath, err)
 	}
 
 	fileMetrics := a.parser.CalculateFileMetrics(ctx, filePath, parsed)
+	if config.Parsing.Tolerant && parsed.SyntaxError != nil {
+		return a.analyzePartial(ctx, parsed, fileMetrics, filePath, config), nil
+	}
 	functionMetrics := a.parser.CalculateFunctionMetrics(ctx, parsed)
 
 	// Pre-allocate results slice with estimated capacity
 	results := make([]core.Result, 0, 8)
 	results = a.applyFileRules(ctx, results, fileMetrics, filePath, config)
 	results = a.applyFunctionRules(ctx, results, functionMetrics, filePath, config)
+	results = a.applyClassRules(ctx, results, parsed, filePath, config)
+	results = a.applyCommentRules(ctx, results, parsed, filePath, config)
+	results = a.applyLineRules(ctx, results, parsed, filePath, config)
+	results = a.applyTestRules(ctx, results, parsed, filePath, config)
 
 	return results, nil
 }
 
+// analyzePartial falls back to the line-based file, comment and line rules for a file whose
+// brackets or strings do not balance, since its function boundaries cannot be trusted
+func (a *Analyzer) analyzePartial(ctx context.Context, parsed *ParsedFile, metrics *rules.FileMetrics, filePath string, config core.Config) []core.Result {
+	config.Rules.OrphanedCode.Enabled = false
+	results := []core.Result{languages.SyntaxErrorResult(filePath, parsed.SyntaxError.Line, parsed.SyntaxError.Message)}
+	results = a.applyFileRules(ctx, results, metrics, filePath, config)
+	results = a.applyCommentRules(ctx, results, parsed, filePath, config)
+	results = a.applyLineRules(ctx, results, parsed, filePath, config)
+	return results
+}
+
 // applyFileRules applies file-level rules and returns accumulated results
 func (a *Analyzer) applyFileRules(ctx context.Context, results []core.Result, metrics *rules.FileMetrics, filePath string, config core.Config) []core.Result {
 	for _, rule := range a.rules {
-		if !isRuleEnabled(rule, config) || isFunctionRule(rule) {
+		if !isRuleEnabled(rule, config) || isFunctionRule(rule) || isClassRule(rule) || isCommentRule(rule) || isTestRule(rule) {
 			continue
 		}
+		start := time.Now()
 		if result := rule.Check(ctx, metrics, config); result != nil {
 			result.FilePath = filePath
 			results = append(results, *result)
 		}
+		profiling.TrackRule(rule.ID(), start)
 	}
 	return results
 }
@@ -76,6 +132,7 @@ func (a *Analyzer) applyFunctionRules(ctx context.Context, results []core.Result
 		if !isRuleEnabled(rule, config) || !isFunctionRule(rule) {
 			continue
 		}
+		start := time.Now()
 		for _, funcMetrics := range functionMetrics {
 			if result := rule.Check(ctx, funcMetrics, config); result != nil {
 				if result.FilePath == "" {
@@ -84,22 +141,252 @@ func (a *Analyzer) applyFunctionRules(ctx context.Context, results []core.Result
 				results = append(results, *result)
 			}
 		}
+		profiling.TrackRule(rule.ID(), start)
+	}
+	return results
+}
+
+// applyClassRules applies class-level rules to each class in the file
+func (a *Analyzer) applyClassRules(ctx context.Context, results []core.Result, parsed *ParsedFile, filePath string, config core.Config) []core.Result {
+	var classMetrics []*rules.ClassMetrics
+	for _, rule := range a.rules {
+		if !isRuleEnabled(rule, config) || !isClassRule(rule) {
+			continue
+		}
+		start := time.Now()
+		if classMetrics == nil {
+			classMetrics = a.parser.CalculateClassMetrics(ctx, parsed)
+		}
+		for _, metrics := range classMetrics {
+			if result := rule.Check(ctx, metrics, config); result != nil {
+				result.FilePath = filePath
+				results = append(results, *result)
+			}
+		}
+		profiling.TrackRule(rule.ID(), start)
+	}
+	return results
+}
+
+// applyCommentRules applies comment rules to each comment in the file
+func (a *Analyzer) applyCommentRules(ctx context.Context, results []core.Result, parsed *ParsedFile, filePath string, config core.Config) []core.Result {
+	for _, rule := range a.rules {
+		if !isRuleEnabled(rule, config) || !isCommentRule(rule) {
+			continue
+		}
+		start := time.Now()
+		for _, comment := range parsed.Comments {
+			commentLine := &rules.CommentLine{Text: comment.Text, Line: comment.Line, Code: commentCode(parsed, comment)}
+			if result := rule.Check(ctx, commentLine, config); result != nil {
+				result.FilePath = filePath
+				results = append(results, *result)
+			}
+		}
+		profiling.TrackRule(rule.ID(), start)
+	}
+	return results
+}
+
+// applyLineRules applies line rules to each line in the file. Printing is the output of a
+// command-line module, so print-debug skips scripts, and endpoints belong in test and
+// configuration files, so hardcoded-endpoint skips those. Comments and the contents of strings
+// are masked first, except for rules that read strings, and a rule reports a line at most once.
+// Line rules match text without a syntax tree, so their findings have medium confidence unless
+// the rule sets one.
+func (a *Analyzer) applyLineRules(ctx context.Context, results []core.Result, parsed *ParsedFile, filePath string, config core.Config) []core.Result {
+	script := isScript(filePath, parsed)
+	code := languages.MaskCode(parsed.Lines, languages.Python)
+	reported := make(languages.LineFindings)
+	for _, rule := range a.enabledLineRules(filePath, config) {
+		if script && rule.ID() == "print-debug" {
+			continue
+		}
+		start := time.Now()
+		lines := code
+		if languages.ReadsStrings(rule) {
+			lines = parsed.Lines
+		}
+		for lineNum, line := range lines {
+			if result := rule.CheckLine(line, lineNum+1); result != nil && reported.Add(result) {
+				results = append(results, lineResult(result, filePath))
+			}
+		}
+		profiling.TrackRule(rule.ID(), start)
+	}
+	return results
+}
+
+// enabledLineRules returns the line rules that apply to a file, whether or not it is a script
+func (a *Analyzer) enabledLineRules(filePath string, config core.Config) []rules.LineCheckRule {
+	endpoints := config.Rules.Endpoints.Enabled && !languages.IsTestFile(filePath) && !languages.IsConfigFile(filePath)
+	var enabled []rules.LineCheckRule
+	for _, rule := range a.lineRules {
+		if !config.RuleDisabled(rule.ID()) && (endpoints || rule.ID() != languages.EndpointRuleID) {
+			enabled = append(enabled, rule)
+		}
+	}
+	return enabled
+}
+
+// lineResult completes a finding of a line rule
+func lineResult(result *core.Result, filePath string) core.Result {
+	result.FilePath = filePath
+	if result.Confidence == "" {
+		result.Confidence = string(core.ConfidenceMedium)
+	}
+	return *result
+}
+
+// applyTestRules applies the test quality rules to the test functions of a test file
+func (a *Analyzer) applyTestRules(ctx context.Context, results []core.Result, parsed *ParsedFile, filePath string, config core.Config) []core.Result {
+	if !languages.IsTestFile(filePath) {
+		return results
+	}
+	var tests []*rules.TestFunction
+	for _, rule := range a.rules {
+		if !isRuleEnabled(rule, config) || !isTestRule(rule) {
+			continue
+		}
+		start := time.Now()
+		if tests == nil {
+			tests = collectTestFunctions(parsed, config.Rules.TestQuality.Threshold)
+		}
+		for _, test := range tests {
+			if result := rule.Check(ctx, test, config); result != nil {
+				result.FilePath = filePath
+				results = append(results, *result)
+			}
+		}
+		profiling.TrackRule(rule.ID(), start)
 	}
 	return results
 }
 
+var (
+	mainGuardPattern = regexp.MustCompile(`^if\s+__name__\s*==\s*['"]__main__['"]\s*:`)
+	scriptNames      = map[string]bool{"__main__.py": true, "cli.py": true, "manage.py": true, "setup.py": true}
+	cliModules       = map[string]bool{"argparse": true, "click": true, "typer": true, "fire": true}
+)
+
+// isScript reports whether a file is a command-line entry point: a module with a __main__
+// guard or a conventional script name, a file under bin/ or scripts/, or a module built on a
+// command-line parsing library
+func isScript(filePath string, parsed *ParsedFile) bool {
+	if scriptNames[filepath.Base(filePath)] {
+		return true
+	}
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(filePath)), "/") {
+		if dir == "bin" || dir == "scripts" {
+			return true
+		}
+	}
+	for _, imp := range parsed.Imports {
+		if cliModules[strings.Split(imp.Module, ".")[0]] {
+			return true
+		}
+	}
+	for _, line := range parsed.Lines {
+		if mainGuardPattern.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// commentCode returns the code a comment describes: the code before an inline comment, or the
+// next code line after a standalone one. Comments introducing a declaration describe intent
+// rather than code, so they return "".
+func commentCode(parsed *ParsedFile, comment Comment) string {
+	if comment.Line < 1 || comment.Line > len(parsed.Lines) {
+		return ""
+	}
+	if comment.IsInline {
+		return inlineCommentCode(parsed.Lines[comment.Line-1], comment)
+	}
+
+	code := languages.CodeAfter(parsed.Lines, comment.Line, "#")
+	if strings.HasPrefix(code, "def ") ||
+		strings.HasPrefix(code, "async def ") ||
+		strings.HasPrefix(code, "class ") ||
+		strings.HasPrefix(code, "@") {
+		return ""
+	}
+	return code
+}
+
+// inlineCommentCode returns the code before an inline comment on its line
+func inlineCommentCode(line string, comment Comment) string {
+	if idx := strings.Index(line, comment.Text); idx > 0 {
+		return strings.TrimSpace(line[:idx])
+	}
+	return ""
+}
+
 // SupportedExtensions returns the file extensions supported by this analyzer
 func (a *Analyzer) SupportedExtensions() []string {
 	return []string{".py", ".pyw"}
 }
 
+// FlushCache drops the parsed files held by the parser's cache
+func (a *Analyzer) FlushCache() {
+	a.parser.cache.Clear()
+}
+
+// CacheStats returns the hit and eviction counts of the parser's cache
+func (a *Analyzer) CacheStats() languages.CacheStats {
+	return a.parser.cache.Stats()
+}
+
 // Name returns the name of this analyzer
 func (a *Analyzer) Name() string {
 	return "python"
 }
 
+// Rules returns the rules applied to each file, for agentlint explain
+func (a *Analyzer) Rules() []core.Rule {
+	all := append([]core.Rule{}, a.rules...)
+	for _, rule := range a.lineRules {
+		all = append(all, rule)
+	}
+	return all
+}
+
+// CountFunctions returns the number of functions in a Python file, for core.Escalate
+func (a *Analyzer) CountFunctions(ctx context.Context, filePath string) (int, error) {
+	parsed, err := a.parser.ParseFile(ctx, filePath)
+	if err != nil {
+		return 0, err
+	}
+	return len(parsed.Functions), nil
+}
+
 // isRuleEnabled checks if a rule is enabled in the configuration
 func isRuleEnabled(rule core.Rule, config core.Config) bool {
+	if config.RuleDisabled(rule.ID()) {
+		return false
+	}
+	if rule.ID() == "ai-comment-fingerprint" {
+		return config.Rules.AIComments.Enabled
+	}
+	if strings.Contains(rule.ID(), "docstring") {
+		return config.Rules.Docstrings.Enabled
+	}
+	if rule.ID() == "type-hint-coverage" {
+		return config.Rules.TypeHints.Enabled
+	}
+	if rule.ID() == "long-branch-chain" {
+		return config.Rules.Branches.Enabled
+	}
+	if isClassRule(rule) {
+		return config.Rules.TypeSize.Enabled
+	}
+	if isTestRule(rule) {
+		return config.Rules.TestQuality.Enabled
+	}
+	if rule.ID() == "sleep-synchronization" {
+		return config.Rules.Concurrency.Enabled
+	}
+
 	switch rule.Category() {
 	case core.CategorySize:
 		if strings.Contains(rule.ID(), "function") {
@@ -120,7 +407,26 @@ func isRuleEnabled(rule core.Rule, config core.Config) bool {
 func isFunctionRule(rule core.Rule) bool {
 	return strings.Contains(rule.ID(), "function") ||
 		strings.Contains(rule.ID(), "unused") ||
-		strings.Contains(rule.ID(), "unreachable")
+		strings.Contains(rule.ID(), "unreachable") ||
+		strings.Contains(rule.ID(), "docstring") ||
+		rule.ID() == "mutable-default-argument" ||
+		rule.ID() == "long-branch-chain" ||
+		rule.ID() == "sleep-synchronization"
+}
+
+// isClassRule checks if a rule applies to classes
+func isClassRule(rule core.Rule) bool {
+	return rule.ID() == "god-class"
+}
+
+// isTestRule checks if a rule inspects the test functions of test files
+func isTestRule(rule core.Rule) bool {
+	return languages.IsTestQualityRule(rule.ID())
+}
+
+// isCommentRule checks if a rule inspects individual comments
+func isCommentRule(rule core.Rule) bool {
+	return rule.ID() == "ai-comment-fingerprint" || rule.ID() == "redundant-comment"
 }
 
 // FileScanner scans directories for Python files
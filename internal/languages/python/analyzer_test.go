@@ -2,9 +2,16 @@ package python
 
 import (
 	"context"
+	"encoding/binary"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode/utf16"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
 )
@@ -70,8 +77,8 @@ func TestAnalyzer_LargeFunctionRule(t *testing.T) {
 	for _, result := range results {
 		if result.RuleID == "large-function" {
 			found = true
-			if result.Line != 1 {
-				t.Errorf("Expected line 1, got %d", result.Line)
+			if result.Line != 1 || result.EndLine != 61 {
+				t.Errorf("Expected lines 1 to 61, got %d to %d", result.Line, result.EndLine)
 			}
 			break
 		}
@@ -172,6 +179,72 @@ x = 1
 	}
 }
 
+func TestAnalyzer_LargeFileExemptions(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	documented := `"""Settings module.
+
+Every value below is documented at length
+because operators tune them in production.
+"""
+
+# Request timeout in seconds
+TIMEOUT = 30
+
+# Number of retries before giving up
+RETRIES = 3
+`
+	generated := "# Code generated by protoc-gen-python. DO NOT EDIT.\n" + strings.Repeat("x = 1\n", 20)
+
+	files := map[string]string{
+		"settings.py": documented,
+		"api_pb2.py":  generated,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	flagged := func(countMode core.FileSizeCountMode) map[string]bool {
+		config := core.Config{
+			Rules: core.RulesConfig{
+				FileSize: core.FileSizeConfig{
+					Enabled:   true,
+					MaxLines:  5,
+					CountMode: countMode,
+				},
+			},
+		}
+		analyzer := NewAnalyzer(config)
+
+		found := make(map[string]bool)
+		for name := range files {
+			results, err := analyzer.Analyze(context.Background(), filepath.Join(tmpDir, name), config)
+			if err != nil {
+				t.Fatalf("Analyze failed: %v", err)
+			}
+			for _, result := range results {
+				if result.RuleID == "large-file" {
+					found[name] = true
+				}
+			}
+		}
+		return found
+	}
+
+	total := flagged(core.CountTotal)
+	if !total["settings.py"] {
+		t.Error("Expected settings.py to exceed 5 total lines")
+	}
+	if total["api_pb2.py"] {
+		t.Error("Did not expect a large-file violation for a generated file")
+	}
+	if code := flagged(core.CountCode); code["settings.py"] {
+		t.Error("Did not expect a large-file violation when only 2 code lines are counted")
+	}
+}
+
 func TestAnalyzer_NoFalsePositivesForSmallFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	filePath := filepath.Join(tmpDir, "small.py")
@@ -325,6 +398,12 @@ func TestAllRulesHaveRequiredMethods(t *testing.T) {
 		if rule.Description() == "" {
 			t.Errorf("Rule %s has empty Description", rule.ID())
 		}
+		if rule.Rationale() == "" {
+			t.Errorf("Rule %s has empty Rationale", rule.ID())
+		}
+		if len(rule.Examples()) == 0 {
+			t.Errorf("Rule %s has no Examples", rule.ID())
+		}
 		if rule.Category() == "" {
 			t.Errorf("Rule %s has empty Category", rule.ID())
 		}
@@ -339,13 +418,15 @@ func TestAnalyzer_HasAllExpectedRules(t *testing.T) {
 	analyzer := NewAnalyzer(config)
 
 	expectedRules := map[string]bool{
-		"large-function":   false,
-		"large-file":       false,
-		"overcommenting":   false,
-		"unused-function":  false,
-		"unused-variable":  false,
-		"unreachable-code": false,
-		"dead-import":      false,
+		"large-function":         false,
+		"large-file":             false,
+		"overcommenting":         false,
+		"unused-function":        false,
+		"unused-variable":        false,
+		"unreachable-code":       false,
+		"dead-import":            false,
+		"ai-comment-fingerprint": false,
+		"redundant-comment":      false,
 	}
 
 	for _, rule := range analyzer.rules {
@@ -360,3 +441,819 @@ func TestAnalyzer_HasAllExpectedRules(t *testing.T) {
 		}
 	}
 }
+
+func TestAnalyzer_AICommentFingerprints(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "fingerprints.py")
+
+	content := `# Here's the updated code with error handling
+def load(path):
+    # This function loads the file
+    with open(path) as f:  # TODO(team-x): stream instead
+        return f.read()
+
+# 🚀 Step 1: start the server
+def main():
+    # Reads settings before starting
+    load("settings.toml")
+`
+
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	config := core.Config{
+		Rules: core.RulesConfig{
+			AIComments: core.AICommentsConfig{
+				Enabled: true,
+				Phrases: []string{"TODO(team-x)"},
+			},
+		},
+	}
+
+	analyzer := NewAnalyzer(config)
+	results, err := analyzer.Analyze(context.Background(), filePath, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	flagged := make(map[int]bool)
+	for _, result := range results {
+		if result.RuleID == "ai-comment-fingerprint" {
+			flagged[result.Line] = true
+		}
+	}
+
+	for _, line := range []int{1, 3, 4, 7} {
+		if !flagged[line] {
+			t.Errorf("Expected ai-comment-fingerprint violation on line %d", line)
+		}
+	}
+	if flagged[9] {
+		t.Error("Did not expect a violation for a plain comment on line 9")
+	}
+}
+
+func TestAnalyzer_RedundantComments(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "redundant.py")
+
+	content := `# Loads the configuration file
+def load_config(path):
+    # open the config path
+    with open(path) as config_file:
+        return config_file.read()  # read config file
+
+# retry until the service responds
+for attempt in range(retries):
+    count += 1  # increment count
+`
+
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	config := core.Config{
+		Rules: core.RulesConfig{
+			Overcommenting: core.OvercommentingConfig{
+				Enabled:         true,
+				MaxCommentRatio: 1,
+				CheckRedundant:  true,
+			},
+		},
+	}
+
+	analyzer := NewAnalyzer(config)
+	results, err := analyzer.Analyze(context.Background(), filePath, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	flagged := make(map[int]bool)
+	for _, result := range results {
+		if result.RuleID == "redundant-comment" {
+			flagged[result.Line] = true
+		}
+	}
+
+	for _, line := range []int{3, 5, 9} {
+		if !flagged[line] {
+			t.Errorf("Expected redundant-comment violation on line %d", line)
+		}
+	}
+	for _, line := range []int{1, 7} {
+		if flagged[line] {
+			t.Errorf("Did not expect a redundant-comment violation on line %d", line)
+		}
+	}
+}
+
+func TestAnalyzer_DocstringRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "docstrings.py")
+
+	content := `def template(a):
+    """Function description."""
+    return a
+
+def todo_args(path, mode):
+    """Open a file.
+
+    Args:
+        path (str): TODO
+        mode: The open mode.
+    """
+    return open(path, mode)
+
+def stale(source, dest):
+    """Copy data.
+
+    :param source: Where to read from.
+    :param target: Where to write to.
+    """
+    return source
+
+def numpy_style(values, scale, **kwargs):
+    """Scale values.
+
+    Parameters
+    ----------
+    values : list
+        Input values.
+    scale : float
+        Factor applied to each value.
+    """
+    return values
+
+def documented(url, timeout=10):
+    """Fetch a URL.
+
+    Args:
+        url: Address to fetch.
+        timeout:
+            Seconds to wait before giving up.
+    """
+    return url
+`
+
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	config := core.Config{
+		Rules: core.RulesConfig{
+			Docstrings: core.DocstringsConfig{
+				Enabled:           true,
+				CheckPlaceholders: true,
+				CheckParameters:   true,
+			},
+		},
+	}
+
+	analyzer := NewAnalyzer(config)
+	results, err := analyzer.Analyze(context.Background(), filePath, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	found := make(map[string][]int)
+	for _, result := range results {
+		found[result.RuleID] = append(found[result.RuleID], result.Line)
+		if result.RuleID == "docstring-param-mismatch" && result.Line == 15 {
+			if !strings.Contains(result.Message, "missing dest") || !strings.Contains(result.Message, "unknown target") {
+				t.Errorf("Unexpected mismatch message: %s", result.Message)
+			}
+		}
+	}
+
+	if got := fmt.Sprint(found["placeholder-docstring"]); got != "[2 6]" {
+		t.Errorf("Expected placeholder-docstring on lines [2 6], got %s", got)
+	}
+	if got := fmt.Sprint(found["docstring-param-mismatch"]); got != "[15]" {
+		t.Errorf("Expected docstring-param-mismatch on line [15], got %s", got)
+	}
+}
+
+func TestAnalyzer_TolerantParsing(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "unfinished.py")
+
+	content := "def process(items):\n    total = sum(\n"
+	for i := 0; i < 60; i++ {
+		content += fmt.Sprintf("    x%d = %d\n", i, i)
+	}
+
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	config := core.Config{
+		Rules: core.RulesConfig{
+			FunctionSize: core.FunctionSizeConfig{Enabled: true, MaxLines: 20},
+			FileSize:     core.FileSizeConfig{Enabled: true, MaxLines: 30},
+		},
+		Parsing: core.ParsingConfig{Tolerant: true},
+	}
+
+	analyzer := NewAnalyzer(config)
+	results, err := analyzer.Analyze(context.Background(), filePath, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	found := make(map[string]int)
+	for _, result := range results {
+		found[result.RuleID] = result.Line
+	}
+	if line, ok := found["syntax-error"]; !ok || line != 2 {
+		t.Errorf("Expected syntax-error on line 2, got %v", found)
+	}
+	if _, ok := found["large-file"]; !ok {
+		t.Error("Expected large-file to still be reported")
+	}
+	if _, ok := found["large-function"]; ok {
+		t.Error("Did not expect function rules on a file with syntax errors")
+	}
+}
+
+func TestAnalyzer_PracticeRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"service.py": `def load(path, cache={}, tags=list(), key=lambda item: item[0], limit=10):
+    print("loading", path)
+    try:
+        return cache[path]
+    except:
+        pass
+    try:
+        return open(path)
+    except Exception:
+        return None
+
+class Store:
+    def add(self, item, seen: set = set(), label: str = "[]"):
+        print(item)
+`,
+		"tool.py": `import argparse
+from os.path import *
+print(argparse.ArgumentParser().parse_args())
+`,
+		"run.py": `def main():
+    print("done")
+
+if __name__ == "__main__":
+    main()
+`,
+	}
+
+	config := core.Config{}
+	analyzer := NewAnalyzer(config)
+
+	found := make(map[string]map[string][]int)
+	for name, content := range files {
+		filePath := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+		results, err := analyzer.Analyze(context.Background(), filePath, config)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		found[name] = make(map[string][]int)
+		for _, result := range results {
+			found[name][result.RuleID] = append(found[name][result.RuleID], result.Line)
+			if result.RuleID == "mutable-default-argument" && result.Line == 1 &&
+				!strings.HasSuffix(result.Message, "cache={}, tags=list()") {
+				t.Errorf("Unexpected mutable default message: %s", result.Message)
+			}
+		}
+	}
+
+	want := map[string]string{
+		"bare-except":              "[5]",
+		"print-debug":              "[2 14]",
+		"mutable-default-argument": "[1 13]",
+	}
+	for ruleID, lines := range want {
+		if got := fmt.Sprint(found["service.py"][ruleID]); got != lines {
+			t.Errorf("Expected %s on lines %s, got %s", ruleID, lines, got)
+		}
+	}
+	if got := fmt.Sprint(found["tool.py"]["wildcard-import"]); got != "[2]" {
+		t.Errorf("Expected wildcard-import on line [2], got %s", got)
+	}
+	for _, name := range []string{"tool.py", "run.py"} {
+		if got := found[name]["print-debug"]; len(got) != 0 {
+			t.Errorf("Expected no print-debug results in script %s, got %v", name, got)
+		}
+	}
+}
+
+func TestAnalyzer_MaskedStrings(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "report.py")
+	content := `def report(rows):
+    """Summarize rows.
+
+    Use print(rows) to inspect them, and never write except: pass.
+    """
+    usage = "print(report(rows))"  # print(rows) also works
+    print(usage)
+    return "https://api.example.com/v1"
+`
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	config := core.Config{Rules: core.RulesConfig{Endpoints: core.EndpointsConfig{Enabled: true}}}
+	results, err := NewAnalyzer(config).Analyze(context.Background(), filePath, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var got []string
+	for _, result := range results {
+		switch result.RuleID {
+		case "print-debug", "bare-except", "hardcoded-endpoint":
+			got = append(got, fmt.Sprintf("%d %s", result.Line, result.RuleID))
+		}
+	}
+	expected := []string{"7 print-debug", "8 hardcoded-endpoint"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestAnalyzer_StreamLargeFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "service.py")
+	content := `def load(path, cache={}):
+    print(path)  # print the path
+    try:
+        return cache[path]
+    except:
+        pass
+    usage = "print(path)"
+    return usage
+`
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	config := core.Config{Rules: core.RulesConfig{
+		FileSize:     core.FileSizeConfig{Enabled: true, MaxLines: 5},
+		FunctionSize: core.FunctionSizeConfig{Enabled: true, MaxLines: 3},
+	}}
+	config.Parsing.StreamThreshold = 1
+	results, err := NewAnalyzer(config).Analyze(context.Background(), filePath, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var got []string
+	for _, result := range results {
+		got = append(got, fmt.Sprintf("%d %s", result.Line, result.RuleID))
+	}
+	// the function rules need the whole file, so large-function and mutable-default-argument
+	// are not reported
+	expected := []string{"1 large-file", "2 print-debug", "5 bare-except"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestAnalyzer_LongLines(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "data.py")
+	// longer than bufio.Scanner's default limit; the print past the maximum length is dropped
+	content := "TABLE = [" + strings.Repeat("1, ", 30000) + "]; print(TABLE)\n" +
+		"def show():\n    print(TABLE)\n"
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	config := core.Config{Parsing: core.ParsingConfig{MaxLineLength: 64 << 10}}
+	for _, threshold := range []int{0, 1} {
+		config.Parsing.StreamThreshold = threshold
+		results, err := NewAnalyzer(config).Analyze(context.Background(), filePath, config)
+		if err != nil {
+			t.Fatalf("Analyze failed with stream threshold %d: %v", threshold, err)
+		}
+		var got []string
+		for _, result := range results {
+			if result.RuleID == "print-debug" {
+				got = append(got, fmt.Sprint(result.Line))
+			}
+		}
+		if !reflect.DeepEqual(got, []string{"3"}) {
+			t.Errorf("Expected print-debug on line 3 with stream threshold %d, got %v", threshold, got)
+		}
+	}
+}
+
+func TestAnalyzer_Encodings(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `from os.path import *  # 📦 everything
+# ✨ Fetch the profile 🚀
+URL = "https://api.example.com/v1"  # 🔥
+LABEL = "🎉"; BACKUP = "https://cdn.example.com/x"
+`
+	utf16LE := []byte{0xFF, 0xFE}
+	for _, unit := range utf16.Encode([]rune(content)) {
+		utf16LE = binary.LittleEndian.AppendUint16(utf16LE, unit)
+	}
+	encodings := map[string][]byte{
+		"utf-8":          []byte(content),
+		"utf-8 with BOM": append([]byte{0xEF, 0xBB, 0xBF}, content...),
+		"utf-16le":       utf16LE,
+	}
+
+	config := core.Config{Rules: core.RulesConfig{Endpoints: core.EndpointsConfig{Enabled: true}}}
+	analyzer := NewAnalyzer(config)
+	for name, data := range encodings {
+		filePath := filepath.Join(tmpDir, strings.ReplaceAll(name, " ", "_")+".py")
+		if err := os.WriteFile(filePath, data, 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+		results, err := analyzer.Analyze(context.Background(), filePath, config)
+		if err != nil {
+			t.Fatalf("Analyze failed for %s: %v", name, err)
+		}
+
+		var got []string
+		for _, result := range results {
+			switch result.RuleID {
+			case "hardcoded-endpoint", "wildcard-import":
+				got = append(got, fmt.Sprintf("%d:%d %s", result.Line, result.Column, result.RuleID))
+			}
+		}
+		expected := []string{"1:0 wildcard-import", "3:7 hardcoded-endpoint", "4:23 hardcoded-endpoint"}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected %v for %s, got %v", expected, name, got)
+		}
+	}
+}
+
+func TestAnalyzer_TypeHintCoverage(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `class Repo:
+    def __init__(self, url):
+        self.url = url
+
+    def fetch(self, ref: str, depth: int = 1) -> bytes:
+        return b""
+
+    def tags(self, pattern: str = "*"):
+        return []
+
+def parse(
+    data: bytes,
+    *args: str,
+    **kwargs: int,
+) -> dict[str, int]:  # parsed headers
+    return {}
+
+def render(template, values: dict) -> str:
+    return ""
+`
+
+	config := core.Config{
+		Rules: core.RulesConfig{
+			TypeHints: core.TypeHintsConfig{Enabled: true, MinCoverage: 0.8},
+		},
+	}
+	analyzer := NewAnalyzer(config)
+
+	for name, wantResult := range map[string]bool{"repo.py": true, "test_repo.py": false} {
+		filePath := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+		results, err := analyzer.Analyze(context.Background(), filePath, config)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+
+		var found []core.Result
+		for _, result := range results {
+			if result.RuleID == "type-hint-coverage" {
+				found = append(found, result)
+			}
+		}
+		if !wantResult {
+			if len(found) != 0 {
+				t.Errorf("Expected no type-hint-coverage results for %s, got %v", name, found)
+			}
+			continue
+		}
+		want := "Only 50% of function signatures have complete type hints (2 of 4, min 80%)"
+		if len(found) != 1 || found[0].Message != want || found[0].Line != 8 {
+			t.Errorf("Expected %q on line 8 for %s, got %v", want, name, found)
+		}
+	}
+}
+
+func TestAnalyzer_HardcodedEndpoints(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `import requests
+
+API = "http://localhost:8000/api"  # TODO: move to settings
+DOCS = "https://docs.python.org/3/"
+BACKUP = 'https://backup.example.com/v1'
+URL = f"http://{host}:{port}/health"
+# see "http://localhost:9000" for the admin UI
+
+def connect():
+    return socket.create_connection(("10.0.0.12", 5432))
+
+def serve():
+    app.run(bind=":8080")
+
+def internal():
+    return requests.get("http://metrics.corp.internal/push")
+`
+	filePath := filepath.Join(tmpDir, "client.py")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	config := core.Config{
+		Rules: core.RulesConfig{
+			Endpoints: core.EndpointsConfig{
+				Enabled: true,
+				Allow:   []string{"corp.internal"},
+			},
+		},
+	}
+
+	analyzer := NewAnalyzer(config)
+	results, err := analyzer.Analyze(context.Background(), filePath, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	messages := make(map[int]string)
+	for _, result := range results {
+		if result.RuleID == "hardcoded-endpoint" {
+			messages[result.Line] = result.Message
+		}
+	}
+	want := map[int]string{
+		3:  `Hardcoded URL "http://localhost:8000/api"`,
+		5:  `Hardcoded URL "https://backup.example.com/v1"`,
+		10: `Hardcoded IP address "10.0.0.12"`,
+		13: `Hardcoded address ":8080"`,
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("Expected findings %v, got %v", want, messages)
+	}
+
+	// endpoints belong in settings modules and tests
+	for _, name := range []string{"settings.py", "test_client.py"} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+		results, err := analyzer.Analyze(context.Background(), path, config)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		for _, result := range results {
+			if result.RuleID == "hardcoded-endpoint" {
+				t.Errorf("Did not expect a finding in %s, got %s", name, result.Message)
+			}
+		}
+	}
+}
+
+func TestAnalyzer_SleepSynchronization(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `import time
+from time import sleep
+
+async def poll(job):
+    """Wait with time.sleep(1) until the job is done."""
+    while not job.done:
+        time.sleep(1)
+
+async def poll_politely(job):
+    while not job.done:
+        await asyncio.sleep(1)
+
+async def schedule(job):
+    def retry():
+        time.sleep(5)
+    loop.run_in_executor(None, retry)
+
+def backoff(attempt):
+    sleep(2 ** attempt)
+
+def test_worker_processes_job():
+    worker.start()
+    # time.sleep(1) was not enough
+    sleep(2)
+    assert job.done
+
+class TestWorker:
+    def test_stops(self):
+        worker.stop()
+        time.sleep(0.5)
+`
+	filePath := filepath.Join(tmpDir, "worker.py")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	config := core.Config{Rules: core.RulesConfig{Concurrency: core.ConcurrencyConfig{Enabled: true}}}
+	analyzer := NewAnalyzer(config)
+	results, err := analyzer.Analyze(context.Background(), filePath, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	messages := make(map[int]string)
+	for _, result := range results {
+		if result.RuleID == "sleep-synchronization" {
+			messages[result.Line] = result.Message
+		}
+	}
+	want := map[int]string{
+		7:  "Async function 'poll' calls time.sleep(), which blocks the event loop",
+		24: "Test 'test_worker_processes_job' calls time.sleep() to wait, which makes it slow or flaky",
+		30: "Test 'test_stops' calls time.sleep() to wait, which makes it slow or flaky",
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("Expected findings %v, got %v", want, messages)
+	}
+}
+
+func TestAnalyzer_TestQuality(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `import pytest
+from users import User, rename
+
+
+def test_create_user():
+    user = User("ada")
+    print(user)
+
+
+def test_user_name():
+    user = User("ada")
+    assert user.get_name() == "ada"
+
+
+def test_rename_short():
+    user = User("ada")
+    result = rename(user, "bo")
+    assert result.ok
+    assert user.get_name() == "bo"
+    assert len(user.history) == 1
+
+
+def test_rename_long():
+    user = User("ada")
+    result = rename(user, "grace")
+    assert result.ok
+    assert user.get_name() == "grace"
+    assert len(user.history) == 1
+
+
+def test_rename_empty():
+    with pytest.raises(ValueError):
+        rename(User("ada"), "")
+
+
+@pytest.mark.skip(reason="needs a database")
+def test_save():
+    User("ada").save()
+
+
+class TestUser:
+    def test_history(self):
+        user = User("ada")
+        rename(user, "bo")
+        self.assertEqual(user.history, ["ada"])
+`
+	filePath := filepath.Join(tmpDir, "test_users.py")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	config := core.Config{Rules: core.RulesConfig{TestQuality: core.TestQualityConfig{Enabled: true, Threshold: 0.9}}}
+	analyzer := NewAnalyzer(config)
+	results, err := analyzer.Analyze(context.Background(), filePath, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	messages := make(map[int]string)
+	for _, result := range results {
+		switch result.RuleID {
+		case "assertion-free-test", "duplicate-test", "trivial-getter-test":
+			messages[result.Line] = result.Message
+		}
+	}
+	want := map[int]string{
+		5:  "Test 'test_create_user' never asserts anything, so it passes whatever the code does",
+		10: "Test 'test_user_name' only exercises the accessors get_name",
+		23: "Test 'test_rename_long' repeats 'test_rename_short' (line 15) with 100% of the same code",
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("Expected findings %v, got %v", want, messages)
+	}
+}
+
+func TestAnalyzer_GodClassRule(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "manager.py")
+
+	var content strings.Builder
+	content.WriteString("class OrderManager:\n")
+	for i := 0; i < 4; i++ {
+		fmt.Fprintf(&content, "    def step_%d(self):\n        self.value_%d = %d\n\n", i, i, i)
+	}
+	content.WriteString("class Small:\n    def run(self):\n        pass\n")
+	if err := os.WriteFile(filePath, []byte(content.String()), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	config := core.Config{
+		Rules: core.RulesConfig{
+			TypeSize: core.TypeSizeConfig{Enabled: true, MaxMethods: 3, MaxFields: 10, MaxClassLines: 5},
+		},
+	}
+	results, err := NewAnalyzer(config).Analyze(context.Background(), filePath, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var godClasses []core.Result
+	for _, r := range results {
+		if r.RuleID == "god-class" {
+			godClasses = append(godClasses, r)
+		}
+	}
+	if len(godClasses) != 1 {
+		t.Fatalf("Expected 1 god-class result, got %d: %v", len(godClasses), godClasses)
+	}
+	want := "Class 'OrderManager' is too large (4 methods, max 3; 12 lines, max 5)"
+	if godClasses[0].Message != want || godClasses[0].Line != 1 {
+		t.Errorf("Expected %q at line 1, got %q at line %d", want, godClasses[0].Message, godClasses[0].Line)
+	}
+}
+
+// TestAnalyzer_ConcurrentUse analyzes the same files from many goroutines through one
+// Analyzer whose cache entries expire at once, so entries are dropped while they are read;
+// run it with -race
+func TestAnalyzer_ConcurrentUse(t *testing.T) {
+	tmpDir := t.TempDir()
+	var files []string
+	for i := 0; i < 4; i++ {
+		lines := []string{"def process():"}
+		for j := 0; j < 60; j++ {
+			lines = append(lines, fmt.Sprintf("    value%d = %d", j, j))
+		}
+		lines = append(lines, "    return value0", "")
+		file := filepath.Join(tmpDir, fmt.Sprintf("module%d.py", i))
+		os.WriteFile(file, []byte(strings.Join(lines, "\n")), 0644)
+		files = append(files, file)
+	}
+	config := core.Config{Rules: core.RulesConfig{FunctionSize: core.FunctionSizeConfig{Enabled: true, MaxLines: 50}}}
+	analyzer := NewAnalyzer(config)
+	analyzer.parser.cache = NewCache(time.Nanosecond)
+
+	want := make([]int, len(files))
+	for i, file := range files {
+		results, err := analyzer.Analyze(context.Background(), file, config)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		if len(results) == 0 {
+			t.Fatalf("Expected findings for %s", file)
+		}
+		want[i] = len(results)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8*len(files))
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i, file := range files {
+				results, err := analyzer.Analyze(context.Background(), file, config)
+				if err != nil {
+					errs <- err
+				} else if len(results) != want[i] {
+					errs <- fmt.Errorf("%s: expected %d results, got %d", file, want[i], len(results))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,91 @@
+package python
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/python/rules"
+)
+
+var (
+	// selfAttributePattern matches an assignment to an instance attribute, such as
+	// self.total = 0 or self.items: list[str] = [], but not a comparison
+	selfAttributePattern = regexp.MustCompile(`\bself\.(\w+)\s*(?::[^=]*)?=(?:[^=]|$)`)
+	// classAttributePattern matches a name assigned or annotated in a class body, such as
+	// limit = 10 or name: str
+	classAttributePattern = regexp.MustCompile(`^(\w+)\s*(?::\s*[\w\[."']|=(?:[^=]|$))`)
+)
+
+// calculateClassBodies collects the methods defined directly in the body of each class
+func (p *Parser) calculateClassBodies(parsed *ParsedFile) {
+	for i := range parsed.Classes {
+		class := &parsed.Classes[i]
+		bodyIndent := classBodyIndent(parsed.Lines, *class)
+		class.Methods = nil
+		for _, fn := range parsed.Functions {
+			if fn.StartLine > class.StartLine && fn.StartLine <= class.EndLine && fn.Indent == bodyIndent {
+				class.Methods = append(class.Methods, fn)
+			}
+		}
+	}
+}
+
+// classBodyIndent returns the indentation of the first statement in a class body, or -1 for
+// a class whose body is on its own line
+func classBodyIndent(lines []string, class ClassDef) int {
+	for j := class.StartLine; j < class.EndLine && j < len(lines); j++ {
+		if trimmed := strings.TrimSpace(lines[j]); trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return countLeadingSpaces(lines[j])
+		}
+	}
+	return -1
+}
+
+// CalculateClassMetrics calculates metrics for all classes in a parsed file
+func (p *Parser) CalculateClassMetrics(ctx context.Context, parsed *ParsedFile) []*rules.ClassMetrics {
+	metrics := make([]*rules.ClassMetrics, 0, len(parsed.Classes))
+	for _, class := range parsed.Classes {
+		metrics = append(metrics, &rules.ClassMetrics{
+			Name:           class.Name,
+			StartLine:      class.StartLine,
+			LineCount:      class.EndLine - class.StartLine + 1,
+			MethodCount:    len(class.Methods),
+			AttributeCount: countAttributes(parsed, class),
+		})
+	}
+	return metrics
+}
+
+// countAttributes counts the distinct names a class defines: those assigned or annotated in
+// its body and those assigned through self in its methods. Lines inside multi-line strings,
+// such as docstrings listing the attributes, are skipped.
+func countAttributes(parsed *ParsedFile, class ClassDef) int {
+	bodyIndent := classBodyIndent(parsed.Lines, class)
+	names := make(map[string]bool)
+	depth, stringDelim := 0, ""
+	for i := class.StartLine; i < class.EndLine && i < len(parsed.Lines); i++ {
+		line := parsed.Lines[i]
+		inString := stringDelim != ""
+		depth, stringDelim = scanBrackets(strings.TrimSpace(line), depth, stringDelim)
+		if inString {
+			continue
+		}
+		code := stripStrings(line)
+		for _, match := range selfAttributePattern.FindAllStringSubmatch(code, -1) {
+			names[match[1]] = true
+		}
+		if countLeadingSpaces(line) != bodyIndent {
+			continue
+		}
+		if match := classAttributePattern.FindStringSubmatch(strings.TrimSpace(code)); match != nil && !clauseKeywords[match[1]] {
+			names[match[1]] = true
+		}
+	}
+	return len(names)
+}
+
+// clauseKeywords are the keywords that can start a line followed by a colon
+var clauseKeywords = map[string]bool{
+	"else": true, "try": true, "finally": true, "except": true, "lambda": true, "pass": true,
+}
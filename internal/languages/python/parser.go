@@ -1,14 +1,15 @@
 package python
 
 import (
-	"bufio"
 	"context"
+	"log/slog"
 	"os"
 	"regexp"
 	"strings"
 	"unicode"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
 	"github.com/CiaranMcAleer/AgentLint/internal/languages/python/rules"
 )
 
@@ -31,7 +32,7 @@ func NewParser(config core.Config) *Parser {
 	return &Parser{
 		config:           config,
 		cache:            NewCache(0),
-		funcPattern:      regexp.MustCompile(`^(\s*)def\s+(\w+)\s*\(`),
+		funcPattern:      regexp.MustCompile(`^(\s*)(async\s+)?def\s+(\w+)\s*\(`),
 		classPattern:     regexp.MustCompile(`^(\s*)class\s+(\w+)\s*(?:\(([^)]*)\))?:`),
 		importPattern:    regexp.MustCompile(`^import\s+(.+)`),
 		fromPattern:      regexp.MustCompile(`^from\s+(\S+)\s+import\s+(.+)`),
@@ -46,6 +47,27 @@ type lineParseState struct {
 	pendingDecorators    []string
 	inMultilineString    bool
 	multilineStringDelim string
+	scopes               []scope // enclosing classes and functions of the current line
+}
+
+// scope is a class or function header seen while parsing
+type scope struct {
+	indent int
+	class  string // the class name, or "" for a function
+}
+
+// enterScope records a class or function header at indent and returns the class or function
+// it is nested in, if any
+func (s *lineParseState) enterScope(indent int, class string) *scope {
+	for len(s.scopes) > 0 && s.scopes[len(s.scopes)-1].indent >= indent {
+		s.scopes = s.scopes[:len(s.scopes)-1]
+	}
+	var parent *scope
+	if len(s.scopes) > 0 {
+		parent = &s.scopes[len(s.scopes)-1]
+	}
+	s.scopes = append(s.scopes, scope{indent: indent, class: class})
+	return parent
 }
 
 // ParseFile parses a Python file
@@ -63,7 +85,7 @@ func (p *Parser) ParseFile(ctx context.Context, filePath string) (*ParsedFile, e
 	parsed := p.newParsedFile()
 	state := &lineParseState{}
 
-	scanner := bufio.NewScanner(file)
+	scanner := languages.NewLineReader(file, p.config.Parsing.MaxLineLength)
 	for scanner.Scan() {
 		state.lineNum++
 		line := scanner.Text()
@@ -73,12 +95,25 @@ func (p *Parser) ParseFile(ctx context.Context, filePath string) (*ParsedFile, e
 		p.processLine(line, state, parsed)
 	}
 
-	p.calculateFunctionEndLines(parsed)
+	p.calculateEndLines(parsed)
+	p.extractSignatures(parsed)
+	p.calculateClassBodies(parsed)
+	parsed.SyntaxError = findSyntaxError(parsed.Lines)
+	parsed.LongLines = scanner.LongLines()
+	warnLongLines(filePath, parsed.LongLines, p.config.Parsing.MaxLineLength)
 	p.cache.Set(filePath, parsed)
 
 	return parsed, scanner.Err()
 }
 
+// warnLongLines logs the lines of a file that were truncated to the maximum line length, and
+// are not analyzed past it
+func warnLongLines(filePath string, lines []int, maxLength int) {
+	if len(lines) > 0 {
+		slog.Warn("truncated long lines", "file", filePath, "lines", len(lines), "first", lines[0], "max_length", maxLength)
+	}
+}
+
 // newParsedFile creates a new initialized ParsedFile
 func (p *Parser) newParsedFile() *ParsedFile {
 	return &ParsedFile{
@@ -156,6 +191,9 @@ func (p *Parser) handleMultilineString(line, trimmed string, state *lineParseSta
 
 // handleComment handles standalone comment lines
 func (p *Parser) handleComment(line, trimmed string, state *lineParseState, parsed *ParsedFile) bool {
+	if state.lineNum == 1 && strings.HasPrefix(trimmed, "#!") {
+		return true // a shebang line is not a comment
+	}
 	if strings.HasPrefix(trimmed, "#") {
 		parsed.Comments = append(parsed.Comments, Comment{
 			Text:     trimmed,
@@ -206,7 +244,9 @@ func (p *Parser) handleClass(line string, state *lineParseState, parsed *ParsedF
 		StartLine:  state.lineNum,
 		Bases:      bases,
 		Decorators: state.pendingDecorators,
+		Indent:     countLeadingSpaces(matches[1]),
 	})
+	state.enterScope(countLeadingSpaces(matches[1]), matches[2])
 	state.pendingDecorators = nil
 	return true
 }
@@ -218,20 +258,22 @@ func (p *Parser) handleFunction(line string, state *lineParseState, parsed *Pars
 		return false
 	}
 
-	indent := len(matches[1])
-	funcName := matches[2]
+	indent := countLeadingSpaces(matches[1])
+	funcName := matches[3]
 
 	funcDef := FunctionDef{
 		Name:       funcName,
 		StartLine:  state.lineNum,
 		Decorators: state.pendingDecorators,
 		IsPrivate:  strings.HasPrefix(funcName, "_"),
+		IsAsync:    matches[2] != "",
 		Indent:     indent,
 	}
 
-	if len(parsed.Classes) > 0 && indent > 0 {
+	// Only functions defined directly in a class body are methods, not those nested in one
+	if parent := state.enterScope(indent, ""); parent != nil && parent.class != "" {
 		funcDef.IsMethod = true
-		funcDef.ClassName = parsed.Classes[len(parsed.Classes)-1].Name
+		funcDef.ClassName = parent.class
 	}
 
 	parsed.Functions = append(parsed.Functions, funcDef)
@@ -287,34 +329,245 @@ func (p *Parser) handleVariable(line string, state *lineParseState, parsed *Pars
 	}
 }
 
-// calculateFunctionEndLines determines where each function ends based on indentation
-func (p *Parser) calculateFunctionEndLines(parsed *ParsedFile) {
+// openBlock is a function or class whose end has not been found yet
+type openBlock struct {
+	indent  int
+	endLine *int
+}
+
+// calculateEndLines determines where each function and class ends. A block ends at its last
+// code line before the next statement indented no deeper than its header; lines continuing a
+// statement inside brackets, a multi-line string or after a backslash never end a block, so
+// multi-line signatures, decorators and docstrings at any indentation are kept inside it.
+// Trailing blank lines and comments are not part of a block.
+func (p *Parser) calculateEndLines(parsed *ParsedFile) {
+	starts := make(map[int][]*int)
+	for i := range parsed.Classes {
+		starts[parsed.Classes[i].StartLine] = append(starts[parsed.Classes[i].StartLine], &parsed.Classes[i].EndLine)
+	}
+	for i := range parsed.Functions {
+		starts[parsed.Functions[i].StartLine] = append(starts[parsed.Functions[i].StartLine], &parsed.Functions[i].EndLine)
+	}
+
+	var stack []openBlock
+	lastCode := 0
+	depth, stringDelim, continued := 0, "", false
+	for i, line := range parsed.Lines {
+		trimmed := strings.TrimSpace(line)
+		inStatement := depth > 0 || stringDelim != "" || continued
+		if !inStatement && (trimmed == "" || strings.HasPrefix(trimmed, "#")) {
+			continue
+		}
+
+		if !inStatement {
+			indent := countLeadingSpaces(line)
+			for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+				*stack[len(stack)-1].endLine = lastCode
+				stack = stack[:len(stack)-1]
+			}
+			for _, endLine := range starts[i+1] {
+				stack = append(stack, openBlock{indent: indent, endLine: endLine})
+			}
+		}
+
+		lastCode = i + 1
+		depth, stringDelim = scanBrackets(trimmed, depth, stringDelim)
+		continued = stringDelim == "" && strings.HasSuffix(trimmed, "\\")
+	}
+	for _, block := range stack {
+		*block.endLine = lastCode
+	}
+}
+
+// extractSignatures fills in each function's parameters and docstring. Signatures may span
+// several lines, so this runs after all lines have been read.
+func (p *Parser) extractSignatures(parsed *ParsedFile) {
 	for i := range parsed.Functions {
 		funcDef := &parsed.Functions[i]
-		funcIndent := funcDef.Indent
+		params, returns, bodyLine := parseSignature(parsed.Lines, funcDef.StartLine-1)
+		if funcDef.IsMethod && len(params) > 0 && (params[0].Name == "self" || params[0].Name == "cls") {
+			params = params[1:]
+		}
+		funcDef.Signature = params
+		funcDef.Returns = returns
+		funcDef.Parameters = make([]string, len(params))
+		for j, param := range params {
+			funcDef.Parameters[j] = param.Name
+		}
+
+		if docstring, ok := readDocstring(parsed.Lines, bodyLine); ok {
+			docstring.Owner = funcDef.Name
+			parsed.Docstrings = append(parsed.Docstrings, docstring)
+			funcDef.Docstring = &docstring
+		}
+	}
+}
+
+// returnAnnotationPattern matches the "-> type:" that may follow a parameter list
+var returnAnnotationPattern = regexp.MustCompile(`^\s*->\s*(.+?)\s*:\s*(?:#.*)?$`)
+
+// parseSignature collects the parameters and return annotation of the def starting at
+// lineIdx and returns them with the index of the first line after the signature
+func parseSignature(lines []string, lineIdx int) ([]rules.Parameter, string, int) {
+	var sig strings.Builder
+	depth := 0
+
+	for i := lineIdx; i < len(lines); i++ {
+		line := lines[i]
+		if i == lineIdx {
+			open := strings.Index(line, "(")
+			if open == -1 {
+				return nil, "", i + 1
+			}
+			line = line[open+1:]
+		}
 
-		// Find the end of the function by looking for the next line with same or less indentation
-		for j := funcDef.StartLine; j < len(parsed.Lines); j++ {
-			line := parsed.Lines[j]
-			if strings.TrimSpace(line) == "" {
+		for j, ch := range line {
+			switch ch {
+			case '(', '[', '{':
+				depth++
+			case ')', ']', '}':
+				if depth == 0 {
+					var returns string
+					if matches := returnAnnotationPattern.FindStringSubmatch(line[j+1:]); matches != nil {
+						returns = matches[1]
+					}
+					return splitParameters(sig.String()), returns, i + 1
+				}
+				depth--
+			}
+			sig.WriteRune(ch)
+		}
+		sig.WriteRune(' ')
+	}
+	return splitParameters(sig.String()), "", len(lines)
+}
+
+// splitParameters splits a parameter list on top-level commas and separates each entry into
+// its name, annotation and default, dropping the bare / and * markers. Star prefixes are kept
+// so *args and **kwargs stay distinguishable.
+func splitParameters(sig string) []rules.Parameter {
+	var params []rules.Parameter
+	depth := 0
+	start := 0
+	for i := 0; i <= len(sig); i++ {
+		if i < len(sig) {
+			switch sig[i] {
+			case '(', '[', '{':
+				depth++
+			case ')', ']', '}':
+				depth--
+			}
+			if sig[i] != ',' || depth > 0 {
 				continue
 			}
+		}
 
-			currentIndent := len(line) - len(strings.TrimLeft(line, " \t"))
-			// Convert tabs to spaces (assuming 4 spaces per tab)
-			currentIndent = strings.Count(line[:currentIndent], "\t")*4 + strings.Count(line[:currentIndent], " ")
+		param := parseParameter(sig[start:i])
+		start = i + 1
+		if param.Name != "" && param.Name != "/" && param.Name != "*" {
+			params = append(params, param)
+		}
+	}
+	return params
+}
 
-			if j > funcDef.StartLine && currentIndent <= funcIndent && strings.TrimSpace(line) != "" {
-				funcDef.EndLine = j
-				break
+// parseParameter splits one "name: annotation = default" entry. Only the first top-level ':'
+// before the '=' starts the annotation, since defaults may hold lambdas and slices.
+func parseParameter(entry string) rules.Parameter {
+	colon, equals := -1, -1
+	depth := 0
+	for i := 0; i < len(entry) && equals == -1; i++ {
+		switch entry[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ':':
+			if depth == 0 && colon == -1 {
+				colon = i
+			}
+		case '=':
+			if depth == 0 {
+				equals = i
 			}
 		}
+	}
+
+	var param rules.Parameter
+	nameEnd := len(entry)
+	if equals != -1 {
+		param.Default = strings.TrimSpace(entry[equals+1:])
+		nameEnd = equals
+	}
+	if colon != -1 {
+		param.Annotation = strings.TrimSpace(entry[colon+1 : nameEnd])
+		nameEnd = colon
+	}
+	param.Name = strings.TrimSpace(entry[:nameEnd])
+	return param
+}
+
+// readDocstring reads a docstring starting at the first non-blank line from lineIdx
+func readDocstring(lines []string, lineIdx int) (Docstring, bool) {
+	for lineIdx < len(lines) && strings.TrimSpace(lines[lineIdx]) == "" {
+		lineIdx++
+	}
+	if lineIdx >= len(lines) {
+		return Docstring{}, false
+	}
+
+	first := strings.TrimLeft(strings.TrimSpace(lines[lineIdx]), "rRuU")
+	var delim string
+	switch {
+	case strings.HasPrefix(first, `"""`):
+		delim = `"""`
+	case strings.HasPrefix(first, `'''`):
+		delim = `'''`
+	default:
+		return Docstring{}, false
+	}
+
+	rest := first[len(delim):]
+	if end := strings.Index(rest, delim); end != -1 {
+		return Docstring{Text: strings.TrimSpace(rest[:end]), StartLine: lineIdx + 1, EndLine: lineIdx + 1}, true
+	}
+
+	textLines := []string{rest}
+	for i := lineIdx + 1; i < len(lines); i++ {
+		if end := strings.Index(lines[i], delim); end != -1 {
+			textLines = append(textLines, lines[i][:end])
+			return Docstring{
+				Text:      strings.TrimSpace(dedentLines(textLines)),
+				StartLine: lineIdx + 1,
+				EndLine:   i + 1,
+			}, true
+		}
+		textLines = append(textLines, lines[i])
+	}
+	return Docstring{}, false
+}
+
+// dedentLines joins docstring lines, removing the indentation shared by all lines after the first
+func dedentLines(lines []string) string {
+	minIndent := -1
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if indent := len(line) - len(strings.TrimLeft(line, " \t")); minIndent == -1 || indent < minIndent {
+			minIndent = indent
+		}
+	}
 
-		// If we didn't find an end, use the last line
-		if funcDef.EndLine == 0 {
-			funcDef.EndLine = len(parsed.Lines)
+	result := []string{strings.TrimSpace(lines[0])}
+	for _, line := range lines[1:] {
+		if minIndent > 0 && len(line) >= minIndent {
+			line = line[minIndent:]
 		}
+		result = append(result, strings.TrimRight(line, " \t"))
 	}
+	return strings.Join(result, "\n")
 }
 
 // CalculateFileMetrics calculates metrics for a parsed file
@@ -334,7 +587,32 @@ func (p *Parser) CalculateFileMetrics(ctx context.Context, filePath string, pars
 		FunctionCount: len(parsed.Functions),
 		ImportCount:   len(parsed.Imports),
 		ClassCount:    len(parsed.Classes),
+		Generated:     languages.IsGenerated(parsed.Lines),
+		Vendored:      languages.IsVendored(filePath),
+		TypeHints:     typeHintCoverage(parsed),
+	}
+}
+
+// typeHintCoverage counts the function signatures that annotate every parameter and the
+// return type. Dunder methods are left out, since their types are fixed by the protocol.
+func typeHintCoverage(parsed *ParsedFile) rules.TypeHintCoverage {
+	var coverage rules.TypeHintCoverage
+	for _, fn := range parsed.Functions {
+		if strings.HasPrefix(fn.Name, "__") && strings.HasSuffix(fn.Name, "__") {
+			continue
+		}
+		coverage.Signatures++
+		annotated := fn.Returns != ""
+		for _, param := range fn.Signature {
+			annotated = annotated && param.Annotation != ""
+		}
+		if annotated {
+			coverage.Annotated++
+		} else if coverage.FirstUnannotated == 0 {
+			coverage.FirstUnannotated = fn.StartLine
+		}
 	}
+	return coverage
 }
 
 // CalculateFunctionMetrics calculates metrics for all functions in a parsed file
@@ -342,10 +620,7 @@ func (p *Parser) CalculateFunctionMetrics(ctx context.Context, parsed *ParsedFil
 	metrics := make([]*rules.FunctionMetrics, 0, len(parsed.Functions))
 
 	for _, fn := range parsed.Functions {
-		lineCount := fn.EndLine - fn.StartLine
-		if lineCount < 0 {
-			lineCount = 0
-		}
+		lineCount := fn.EndLine - fn.StartLine + 1
 
 		// Calculate nesting depth
 		nestingDepth := 0
@@ -362,21 +637,282 @@ func (p *Parser) CalculateFunctionMetrics(ctx context.Context, parsed *ParsedFil
 			}
 		}
 
+		var docstring string
+		var docstringLine int
+		if fn.Docstring != nil {
+			docstring = fn.Docstring.Text
+			docstringLine = fn.Docstring.StartLine
+		}
+
 		metrics = append(metrics, &rules.FunctionMetrics{
-			Name:         fn.Name,
-			IsMethod:     fn.IsMethod,
-			ClassName:    fn.ClassName,
-			IsPrivate:    fn.IsPrivate,
-			LineCount:    lineCount,
-			StartLine:    fn.StartLine,
-			NestingDepth: nestingDepth,
-			Decorators:   fn.Decorators,
+			Name:          fn.Name,
+			IsMethod:      fn.IsMethod,
+			ClassName:     fn.ClassName,
+			IsPrivate:     fn.IsPrivate,
+			LineCount:     lineCount,
+			LogicalLines:  countLogicalLines(parsed.Lines, fn),
+			StartLine:     fn.StartLine,
+			EndLine:       fn.EndLine,
+			NestingDepth:  nestingDepth,
+			Decorators:    fn.Decorators,
+			Parameters:    fn.Parameters,
+			Signature:     fn.Signature,
+			Docstring:     docstring,
+			DocstringLine: docstringLine,
+			BranchChain:   longestBranchChain(parsed.Lines, fn),
+			IsAsync:       fn.IsAsync,
+			SleepLine:     findSleepLine(parsed, fn),
 		})
 	}
 
 	return metrics
 }
 
+// countLogicalLines counts the logical lines of a function body. A statement continued over
+// several physical lines by brackets, backslashes or a multi-line string counts once; blank
+// lines, comments and the docstring are not counted.
+func countLogicalLines(lines []string, fn FunctionDef) int {
+	count := 0
+	depth := 0
+	continued := false
+	stringDelim := ""
+	for i := fn.StartLine - 1; i < fn.EndLine && i < len(lines); i++ {
+		if fn.Docstring != nil && i+1 >= fn.Docstring.StartLine && i+1 <= fn.Docstring.EndLine {
+			continue
+		}
+		trimmed := strings.TrimSpace(lines[i])
+		if stringDelim == "" && (trimmed == "" || strings.HasPrefix(trimmed, "#")) {
+			continue
+		}
+		if depth == 0 && !continued && stringDelim == "" {
+			count++
+		}
+		depth, stringDelim = scanBrackets(trimmed, depth, stringDelim)
+		continued = strings.HasSuffix(trimmed, "\\")
+	}
+
+	// The def line is not part of the body
+	if count > 0 {
+		count--
+	}
+	return count
+}
+
+var (
+	sleepCallPattern    = regexp.MustCompile(`(?:^|[^\w.])time\.sleep\s*\(`)
+	anySleepCallPattern = regexp.MustCompile(`(?:^|[^\w.])(?:time\.)?sleep\s*\(`)
+)
+
+// findSleepLine returns the first line of a function's own body that calls time.sleep, or
+// sleep imported from time, skipping nested functions, strings and comments
+func findSleepLine(parsed *ParsedFile, fn FunctionDef) int {
+	pattern := sleepCallPattern
+	for _, imp := range parsed.Imports {
+		if imp.IsFrom && imp.Module == "time" && containsString(imp.Names, "sleep") {
+			pattern = anySleepCallPattern
+		}
+	}
+
+	stringDelim := ""
+	for i := fn.StartLine; i < fn.EndLine && i < len(parsed.Lines); i++ {
+		line := parsed.Lines[i]
+		continued := stringDelim != ""
+		_, stringDelim = scanBrackets(line, 0, stringDelim)
+		if continued || inNestedFunction(parsed.Functions, fn, i+1) {
+			continue
+		}
+		if pattern.MatchString(stripStrings(line)) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// inNestedFunction reports whether a line belongs to a function defined inside fn
+func inNestedFunction(functions []FunctionDef, fn FunctionDef, line int) bool {
+	for _, other := range functions {
+		if other.StartLine > fn.StartLine && other.EndLine <= fn.EndLine && line >= other.StartLine && line <= other.EndLine {
+			return true
+		}
+	}
+	return false
+}
+
+// stripStrings removes the contents of the strings on a line and any trailing comment. A
+// string left open at the end of the line is removed to the end.
+func stripStrings(line string) string {
+	var b strings.Builder
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if c == '#' {
+			break
+		}
+		if c != '"' && c != '\'' {
+			b.WriteByte(c)
+			continue
+		}
+		delim := string(c)
+		if strings.HasPrefix(line[i:], strings.Repeat(delim, 3)) {
+			delim = strings.Repeat(delim, 3)
+		}
+		end := i + len(delim)
+		for end < len(line) && !strings.HasPrefix(line[end:], delim) {
+			if line[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		b.WriteString(delim + delim)
+		i = end + len(delim) - 1
+	}
+	return b.String()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// openChain is an if-elif chain or match statement whose clauses are still being counted
+type openChain struct {
+	rules.BranchChain
+	indent     int // of the if or match line
+	caseIndent int // of the case clauses of a match statement, -1 until the first
+}
+
+// longestBranchChain returns the if-elif chain or match statement with the most branches in a
+// function. A chain counts the if and each elif and else clause at its indentation, a match
+// statement the case clauses directly inside it. Continuation lines are skipped.
+func longestBranchChain(lines []string, fn FunctionDef) rules.BranchChain {
+	var longest rules.BranchChain
+	var open []openChain
+	closeChain := func() {
+		if chain := open[len(open)-1]; chain.Branches > longest.Branches {
+			longest = chain.BranchChain
+		}
+		open = open[:len(open)-1]
+	}
+
+	depth := 0
+	stringDelim := ""
+	for i := fn.StartLine - 1; i < fn.EndLine && i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		continuation := depth > 0 || stringDelim != ""
+		depth, stringDelim = scanBrackets(trimmed, depth, stringDelim)
+		if continuation || trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		indent := countLeadingSpaces(lines[i])
+		clause := false
+		for len(open) > 0 {
+			chain := &open[len(open)-1]
+			if indent > chain.indent {
+				if chain.Kind == "match statement" && startsClause(trimmed, "case") && (chain.caseIndent < 0 || indent == chain.caseIndent) {
+					chain.caseIndent = indent
+					chain.Branches++
+				}
+				break
+			}
+			if indent == chain.indent && chain.Kind == "if-elif chain" && (startsClause(trimmed, "elif") || startsClause(trimmed, "else")) {
+				chain.Branches++
+				clause = true
+				break
+			}
+			closeChain()
+		}
+		if clause {
+			continue
+		}
+
+		if startsClause(trimmed, "if") {
+			open = append(open, openChain{BranchChain: rules.BranchChain{Kind: "if-elif chain", Branches: 1, Line: i + 1}, indent: indent})
+		} else if startsClause(trimmed, "match") && strings.HasSuffix(trimmed, ":") {
+			open = append(open, openChain{BranchChain: rules.BranchChain{Kind: "match statement", Line: i + 1}, indent: indent, caseIndent: -1})
+		}
+	}
+	for len(open) > 0 {
+		closeChain()
+	}
+	return longest
+}
+
+// startsClause reports whether a line opens a compound statement clause with the keyword,
+// such as "elif x > 0:" or "else:"
+func startsClause(trimmed, keyword string) bool {
+	rest := strings.TrimPrefix(trimmed, keyword)
+	if len(rest) == len(trimmed) || !strings.Contains(rest, ":") {
+		return false
+	}
+	return strings.IndexAny(rest[:1], " (:[{'\"") == 0
+}
+
+// findSyntaxError reports a bracket or triple-quoted string still open at the end of the file,
+// which leaves the indentation-based function boundaries unreliable
+func findSyntaxError(lines []string) *SyntaxError {
+	depth := 0
+	stringDelim := ""
+	openLine := 0
+	for i, line := range lines {
+		wasOpen := depth > 0 || stringDelim != ""
+		depth, stringDelim = scanBrackets(strings.TrimSpace(line), depth, stringDelim)
+		if !wasOpen && (depth > 0 || stringDelim != "") {
+			openLine = i + 1
+		}
+	}
+
+	if stringDelim != "" {
+		return &SyntaxError{Line: openLine, Message: "unterminated triple-quoted string"}
+	}
+	if depth > 0 {
+		return &SyntaxError{Line: openLine, Message: "unclosed bracket"}
+	}
+	return nil
+}
+
+// scanBrackets tracks the open bracket depth and any unterminated triple-quoted string
+// through a line, ignoring brackets inside strings and comments
+func scanBrackets(line string, depth int, stringDelim string) (int, string) {
+	for i := 0; i < len(line); i++ {
+		if stringDelim != "" {
+			if strings.HasPrefix(line[i:], stringDelim) {
+				i += len(stringDelim) - 1
+				stringDelim = ""
+			} else if line[i] == '\\' {
+				i++
+			}
+			continue
+		}
+
+		switch c := line[i]; c {
+		case '#':
+			return depth, ""
+		case '"', '\'':
+			stringDelim = string(c)
+			if triple := strings.Repeat(stringDelim, 3); strings.HasPrefix(line[i:], triple) {
+				stringDelim = triple
+			}
+			i += len(stringDelim) - 1
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+
+	// Only triple-quoted strings span lines
+	if len(stringDelim) == 1 {
+		stringDelim = ""
+	}
+	return depth, stringDelim
+}
+
 // splitAndTrim splits a string by comma and trims each part
 func splitAndTrim(s string) []string {
 	parts := strings.Split(s, ",")
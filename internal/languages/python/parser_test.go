@@ -4,9 +4,12 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/python/rules"
 )
 
 func TestParser_ParsesFunctions(t *testing.T) {
@@ -176,6 +179,47 @@ func TestParser_CacheWorks(t *testing.T) {
 	if parsed1 != parsed2 {
 		t.Error("Expected cached result to be returned")
 	}
+
+	// An edited file is parsed again
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(filePath, later, later)
+	parsed3, err := parser.ParseFile(context.Background(), filePath)
+	if err != nil {
+		t.Fatalf("Third ParseFile failed: %v", err)
+	}
+	if parsed3 == parsed1 {
+		t.Error("Expected a modified file to be parsed again")
+	}
+	if stats := parser.cache.Stats(); stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("Expected 1 hit and 2 misses, got %+v", stats)
+	}
+}
+
+func TestCache_Eviction(t *testing.T) {
+	tmpDir := t.TempDir()
+	var files []string
+	for _, name := range []string{"a.py", "b.py", "c.py"} {
+		file := filepath.Join(tmpDir, name)
+		os.WriteFile(file, []byte("x = 1\n"), 0644)
+		files = append(files, file)
+	}
+
+	cache := NewCache(0)
+	cache.SetMaxEntries(2)
+	cache.Set(files[0], &ParsedFile{})
+	cache.Set(files[1], &ParsedFile{})
+	cache.Get(files[0])
+	cache.Set(files[2], &ParsedFile{})
+
+	if _, ok := cache.Get(files[1]); ok {
+		t.Error("Expected the least recently used file to be evicted")
+	}
+	if _, ok := cache.Get(files[0]); !ok {
+		t.Error("Expected the file used again to stay cached")
+	}
+	if stats := cache.Stats(); stats.Entries != 2 || stats.Evictions != 1 {
+		t.Errorf("Expected 2 entries and 1 eviction, got %+v", stats)
+	}
 }
 
 func TestParser_ParsesDecorators(t *testing.T) {
@@ -249,3 +293,322 @@ func TestParser_ParsesMethodsInClass(t *testing.T) {
 		t.Errorf("Expected 3 methods in MyClass, got %d", methodCount)
 	}
 }
+
+func TestParser_CalculatesClassMetrics(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "classes.py")
+
+	content := `class Account:
+    """A bank account.
+
+    owner: the holder
+    """
+
+    currency: str = "EUR"
+    limit = 100
+
+    def __init__(self, owner):
+        self.owner = owner
+        self.balance: int = 0
+        if self.balance == 0:
+            self.owner = owner.strip()
+
+    def deposit(self, amount):
+        def check(value):
+            return value > 0
+        self.history = [amount]
+
+
+def helper():
+    pass
+`
+
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	parser := NewParser(core.Config{})
+	parsed, err := parser.ParseFile(context.Background(), filePath)
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+
+	metrics := parser.CalculateClassMetrics(context.Background(), parsed)
+	if len(metrics) != 1 {
+		t.Fatalf("Expected 1 class, got %d", len(metrics))
+	}
+	got := *metrics[0]
+	// currency, limit, owner, balance and history; check is nested, not a method
+	if got.Name != "Account" || got.StartLine != 1 || got.LineCount != 19 || got.MethodCount != 2 || got.AttributeCount != 5 {
+		t.Errorf("Unexpected metrics: %+v", got)
+	}
+}
+
+func TestParser_ParsesParametersAndDocstrings(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "signatures.py")
+
+	content := `class Store:
+    def get(self, key: str, default: Dict[str, int] = None, *args, **kwargs):
+        """Return the value for key.
+
+        Args:
+            key: The lookup key.
+        """
+        return default
+
+def build(
+    name,
+    /,
+    size=10,
+    *,
+    verbose: bool = False,
+):
+    return name
+`
+
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	parser := NewParser(core.Config{})
+	parsed, err := parser.ParseFile(context.Background(), filePath)
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+
+	expected := map[string][]string{
+		"get":   {"key", "default", "*args", "**kwargs"},
+		"build": {"name", "size", "verbose"},
+	}
+	for _, fn := range parsed.Functions {
+		want := expected[fn.Name]
+		if strings.Join(fn.Parameters, ",") != strings.Join(want, ",") {
+			t.Errorf("Function %s: expected parameters %v, got %v", fn.Name, want, fn.Parameters)
+		}
+	}
+
+	get := parsed.Functions[0]
+	if get.Docstring == nil {
+		t.Fatal("Expected docstring for get")
+	}
+	if get.Docstring.StartLine != 3 || get.Docstring.EndLine != 7 {
+		t.Errorf("Expected docstring on lines 3-7, got %d-%d", get.Docstring.StartLine, get.Docstring.EndLine)
+	}
+	if !strings.HasPrefix(get.Docstring.Text, "Return the value for key.") || !strings.Contains(get.Docstring.Text, "\nArgs:\n    key:") {
+		t.Errorf("Unexpected docstring text: %q", get.Docstring.Text)
+	}
+	if parsed.Functions[1].Docstring != nil {
+		t.Error("Expected no docstring for build")
+	}
+}
+
+func TestParser_CountsLogicalLines(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "logical.py")
+
+	content := `def load(
+    path,
+    mode="r",
+):
+    """Load a file.
+
+    Returns its contents.
+    """
+    # open the file
+    with open(path,
+              mode) as f:
+
+        data = f.read()
+    query = """
+        SELECT *
+        FROM t
+    """
+    total = 1 + \
+        2
+    return data
+`
+
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	parser := NewParser(core.Config{})
+	parsed, err := parser.ParseFile(context.Background(), filePath)
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+
+	metrics := parser.CalculateFunctionMetrics(context.Background(), parsed)
+	if len(metrics) != 1 {
+		t.Fatalf("Expected 1 function, got %d", len(metrics))
+	}
+	if metrics[0].LogicalLines != 5 {
+		t.Errorf("Expected 5 logical lines, got %d (%d physical)", metrics[0].LogicalLines, metrics[0].LineCount)
+	}
+}
+
+func TestParser_LongestBranchChain(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "branches.py")
+
+	content := `def handle(event):
+    if event == "a":
+        pass
+    elif event == "b":
+        query = """
+        elif x:
+        """
+    elif event == "c":
+        if ready:
+            pass
+        else:
+            pass
+    else:
+        pass
+
+
+def dispatch(command):
+    match command:
+        case "start":
+            start()
+        case "stop":
+            match mode:
+                case "now":
+                    pass
+        case "pause" | "resume":
+            pass
+        case _:
+            pass
+    if command: run()
+    else: skip()
+`
+
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	parser := NewParser(core.Config{})
+	parsed, err := parser.ParseFile(context.Background(), filePath)
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+
+	expected := map[string]rules.BranchChain{
+		"handle":   {Kind: "if-elif chain", Branches: 4, Line: 2},
+		"dispatch": {Kind: "match statement", Branches: 4, Line: 18},
+	}
+	for _, metrics := range parser.CalculateFunctionMetrics(context.Background(), parsed) {
+		if metrics.BranchChain != expected[metrics.Name] {
+			t.Errorf("Expected %+v for %s, got %+v", expected[metrics.Name], metrics.Name, metrics.BranchChain)
+		}
+	}
+}
+
+func TestParser_FunctionEndLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string][2]int // function name -> start and end line
+		methods map[string]string // function name -> class, for methods
+	}{
+		{
+			name: "nested functions and closures",
+			content: `def outer(items):
+    def key(item):
+        return item.name
+
+    def wrap(fn):
+        async def inner(*args):
+            return await fn(*args)
+        return inner
+    return sorted(items, key=key)
+
+
+def after():
+    pass
+`,
+			want: map[string][2]int{"outer": {1, 9}, "key": {2, 3}, "wrap": {5, 8}, "inner": {6, 7}, "after": {12, 13}},
+		},
+		{
+			name: "multi-line signatures and decorators",
+			content: `@app.route(
+    "/orders",
+methods=["GET"],
+)
+async def list_orders(
+    request,
+    limit: int = 10,
+):
+    """List orders.
+
+Returns every order.
+"""
+    return []
+
+def last(): return 1
+`,
+			want: map[string][2]int{"list_orders": {5, 13}, "last": {15, 15}},
+		},
+		{
+			name: "methods ending at end of file",
+			content: `class Store:
+    def get(self, key):
+        def lookup():
+            return self.items[key]
+        return lookup()
+
+    def put(self, key, value):
+        self.items[key] = \
+            value
+
+   # trailing comment
+
+`,
+			want:    map[string][2]int{"get": {2, 5}, "lookup": {3, 4}, "put": {7, 9}},
+			methods: map[string]string{"get": "Store", "put": "Store"},
+		},
+		{
+			name: "function after a class is not a method",
+			content: `class Base:
+    pass
+
+def helper():
+    def inner():
+        pass
+    return inner
+`,
+			want: map[string][2]int{"helper": {4, 7}, "inner": {5, 6}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "code.py")
+			if err := os.WriteFile(filePath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+			parsed, err := NewParser(core.Config{}).ParseFile(context.Background(), filePath)
+			if err != nil {
+				t.Fatalf("ParseFile failed: %v", err)
+			}
+
+			if len(parsed.Functions) != len(tt.want) {
+				t.Errorf("Expected %d functions, got %d", len(tt.want), len(parsed.Functions))
+			}
+			for _, fn := range parsed.Functions {
+				want, ok := tt.want[fn.Name]
+				if !ok {
+					t.Errorf("Unexpected function %s", fn.Name)
+					continue
+				}
+				if fn.StartLine != want[0] || fn.EndLine != want[1] {
+					t.Errorf("%s: expected lines %d-%d, got %d-%d", fn.Name, want[0], want[1], fn.StartLine, fn.EndLine)
+				}
+				if class := tt.methods[fn.Name]; fn.IsMethod != (class != "") || fn.ClassName != class {
+					t.Errorf("%s: expected class %q, got method=%v class %q", fn.Name, class, fn.IsMethod, fn.ClassName)
+				}
+			}
+		})
+	}
+}
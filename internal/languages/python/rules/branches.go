@@ -0,0 +1,112 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+const defaultMaxBranches = 10
+
+// BranchChain is the if-elif chain or match statement with the most branches in a function
+type BranchChain struct {
+	Kind     string // "if-elif chain" or "match statement"
+	Branches int    // if, elif and else clauses, or case clauses
+	Line     int
+}
+
+// LongBranchChainRule detects if-elif chains and match statements with too many branches
+type LongBranchChainRule struct {
+	config core.Config
+}
+
+// NewLongBranchChainRule creates a new long branch chain rule
+func NewLongBranchChainRule(config core.Config) *LongBranchChainRule {
+	return &LongBranchChainRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *LongBranchChainRule) ID() string {
+	return "long-branch-chain"
+}
+
+// Name returns the name of this rule
+func (r *LongBranchChainRule) Name() string {
+	return "Long Branch Chain"
+}
+
+// Description returns a description of this rule
+func (r *LongBranchChainRule) Description() string {
+	return "Detects if-elif chains and match statements with too many branches"
+}
+
+// Rationale explains why this rule exists
+func (r *LongBranchChainRule) Rationale() string {
+	return "An if-elif chain that grows a branch for every new kind of input has to be found and " +
+		"edited each time a kind is added, and the branches tend to drift apart. A dict from key to " +
+		"value or handler, or a class per kind with a shared method, keeps each case in one place."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *LongBranchChainRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `def handle(event):
+    if event.type == "created":
+        on_created(event)
+    elif event.type == "updated":
+        on_updated(event)
+    # ... an elif for every event type`,
+		Good: `HANDLERS = {
+    "created": on_created,
+    "updated": on_updated,
+}
+
+def handle(event):
+    HANDLERS[event.type](event)`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *LongBranchChainRule) Options() []core.RuleOption {
+	return core.BranchesOptions()
+}
+
+// Category returns the category of this rule
+func (r *LongBranchChainRule) Category() core.RuleCategory {
+	return core.CategoryComplexity
+}
+
+// Severity returns the severity of violations of this rule
+func (r *LongBranchChainRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a function violates this rule
+func (r *LongBranchChainRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	maxBranches := config.Rules.Branches.MaxBranches
+	if maxBranches <= 0 {
+		maxBranches = defaultMaxBranches
+	}
+
+	n, ok := node.(*FunctionMetrics)
+	if !ok || n.BranchChain.Branches <= maxBranches {
+		return nil
+	}
+
+	funcType := "Function"
+	if n.IsMethod {
+		funcType = "Method"
+	}
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.BranchChain.Line,
+		Message:    fmt.Sprintf("%s '%s' has a long %s (%d branches, max %d)", funcType, n.Name, n.BranchChain.Kind, n.BranchChain.Branches, maxBranches),
+		Suggestion: fmt.Sprintf("Consider replacing the %s in '%s' with a dict of values or handlers, or a class per case", n.BranchChain.Kind, n.Name),
+	}
+}
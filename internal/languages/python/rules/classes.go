@@ -0,0 +1,126 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// ClassMetrics contains metrics about a Python class
+type ClassMetrics struct {
+	Name           string
+	StartLine      int
+	LineCount      int // from the class line to the last non-blank line of its body
+	MethodCount    int // functions defined directly in the class body
+	AttributeCount int // distinct class-level names and self attributes
+}
+
+// GodClassRule detects classes with too many methods, too many attributes or too many lines
+type GodClassRule struct {
+	config core.Config
+}
+
+// NewGodClassRule creates a new god class rule
+func NewGodClassRule(config core.Config) *GodClassRule {
+	return &GodClassRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *GodClassRule) ID() string {
+	return "god-class"
+}
+
+// Name returns the name of this rule
+func (r *GodClassRule) Name() string {
+	return "God Class"
+}
+
+// Description returns a description of this rule
+func (r *GodClassRule) Description() string {
+	return "Detects classes that exceed the maximum number of methods, attributes or lines"
+}
+
+// Rationale explains why this rule exists
+func (r *GodClassRule) Rationale() string {
+	return "A class with dozens of methods and attributes holds the state and behavior of several " +
+		"responsibilities, so every method can reach everything and the class cannot be understood or " +
+		"tested in parts. Generated code tends to keep adding methods to one manager or service class " +
+		"instead of introducing new ones."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *GodClassRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `class OrderManager:
+    def load_orders(self): ...
+    def price_order(self, order): ...
+    def send_invoice(self, order): ...
+    def render_report(self): ...
+    # ... 30 more methods over 900 lines`,
+		Good: `class OrderRepository:
+    def load_orders(self): ...
+
+class Pricing:
+    def price_order(self, order): ...
+
+class Invoicing:
+    def send_invoice(self, order): ...`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *GodClassRule) Options() []core.RuleOption {
+	return []core.RuleOption{
+		{Key: "rules.typeSize.enabled", Flag: "-enable-type-size", Default: "true", Description: "Run the type size rules"},
+		{Key: "rules.typeSize.maxMethods", Flag: "-type-max-methods", Default: "20", Description: "Most methods a class may have"},
+		{Key: "rules.typeSize.maxFields", Flag: "-struct-max-fields", Default: "15", Description: "Most attributes a class may have"},
+		{Key: "rules.typeSize.maxClassLines", Flag: "-class-max-lines", Default: "500", Description: "Most lines a class may span"},
+	}
+}
+
+// Category returns the category of this rule
+func (r *GodClassRule) Category() core.RuleCategory {
+	return core.CategorySize
+}
+
+// Severity returns the severity of violations of this rule
+func (r *GodClassRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a class violates this rule. A limit of zero or less is not checked.
+func (r *GodClassRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*ClassMetrics)
+	if !ok {
+		return nil
+	}
+
+	limits := config.Rules.TypeSize
+	var exceeded []string
+	if limits.MaxMethods > 0 && n.MethodCount > limits.MaxMethods {
+		exceeded = append(exceeded, fmt.Sprintf("%d methods, max %d", n.MethodCount, limits.MaxMethods))
+	}
+	if limits.MaxFields > 0 && n.AttributeCount > limits.MaxFields {
+		exceeded = append(exceeded, fmt.Sprintf("%d attributes, max %d", n.AttributeCount, limits.MaxFields))
+	}
+	if limits.MaxClassLines > 0 && n.LineCount > limits.MaxClassLines {
+		exceeded = append(exceeded, fmt.Sprintf("%d lines, max %d", n.LineCount, limits.MaxClassLines))
+	}
+	if len(exceeded) == 0 {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.StartLine,
+		Message:    fmt.Sprintf("Class '%s' is too large (%s)", n.Name, strings.Join(exceeded, "; ")),
+		Suggestion: fmt.Sprintf("Consider splitting '%s' into smaller classes grouped by responsibility", n.Name),
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
 )
 
 // OvercommentingRule detects overcommented code
@@ -34,6 +35,31 @@ func (r *OvercommentingRule) Description() string {
 	return "Detects code with excessive comments"
 }
 
+// Rationale explains why this rule exists
+func (r *OvercommentingRule) Rationale() string {
+	return "When most lines of a module are comments, the comments narrate the code instead of explaining " +
+		"it, and they drift out of date as the code changes. Generated code often comments every line. " +
+		"The rule compares the comment lines of a file with its code lines."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *OvercommentingRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `# Loop over the users
+for user in users:
+    # Check if the user is active
+    if user.active:
+        # Add the user to the result
+        active.append(user)`,
+		Good: `active = [user for user in users if user.active]`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *OvercommentingRule) Options() []core.RuleOption {
+	return core.OvercommentingOptions()
+}
+
 // Category returns the category of this rule
 func (r *OvercommentingRule) Category() core.RuleCategory {
 	return core.CategoryComments
@@ -65,3 +91,187 @@ func (r *OvercommentingRule) Check(ctx context.Context, node interface{}, config
 
 	return nil
 }
+
+// CommentLine represents a single comment line with its position
+type CommentLine struct {
+	Text string
+	Line int
+	Code string // the code the comment describes, empty when it documents a declaration
+}
+
+// AICommentRule detects comments carrying LLM chat boilerplate fingerprints
+type AICommentRule struct {
+	config core.Config
+}
+
+// NewAICommentRule creates a new AI comment fingerprint rule
+func NewAICommentRule(config core.Config) *AICommentRule {
+	return &AICommentRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *AICommentRule) ID() string {
+	return "ai-comment-fingerprint"
+}
+
+// Name returns the name of this rule
+func (r *AICommentRule) Name() string {
+	return "AI Comment Fingerprint"
+}
+
+// Description returns a description of this rule
+func (r *AICommentRule) Description() string {
+	return "Detects comments containing chat-assistant boilerplate left behind by LLMs"
+}
+
+// Rationale explains why this rule exists
+func (r *AICommentRule) Rationale() string {
+	return "Code pasted from a chat assistant often keeps the assistant's side of the conversation: " +
+		"\"Here's the updated code\", \"In a real application you would...\", or comments that open with " +
+		"\"This function\" and narrate the obvious. They describe the conversation rather than the code " +
+		"and show that the change was not reviewed."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *AICommentRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `# Replace with your actual database URL
+DATABASE_URL = "postgres://localhost/app"`,
+		Good: `DATABASE_URL = os.environ.get("DATABASE_URL", "postgres://localhost/app")`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *AICommentRule) Options() []core.RuleOption {
+	return core.AICommentOptions()
+}
+
+// Category returns the category of this rule
+func (r *AICommentRule) Category() core.RuleCategory {
+	return core.CategoryComments
+}
+
+// Severity returns the severity of violations of this rule
+func (r *AICommentRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a comment line violates this rule
+func (r *AICommentRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*CommentLine)
+	if !ok {
+		return nil
+	}
+
+	match := languages.MatchAIFingerprint(n.Text, config.Rules.AIComments.Phrases)
+	if match == "" {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.Line,
+		Message:    fmt.Sprintf("Comment looks like LLM boilerplate (%s): %q", match, n.Text),
+		Suggestion: "Remove the comment or rewrite it to explain intent specific to this code",
+	}
+}
+
+// defaultRedundantThreshold is the comment/code word overlap at which a comment is redundant
+const defaultRedundantThreshold = 0.6
+
+// RedundantCommentRule detects comments that restate the code they describe
+type RedundantCommentRule struct {
+	config core.Config
+}
+
+// NewRedundantCommentRule creates a new redundant comment rule
+func NewRedundantCommentRule(config core.Config) *RedundantCommentRule {
+	return &RedundantCommentRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *RedundantCommentRule) ID() string {
+	return "redundant-comment"
+}
+
+// Name returns the name of this rule
+func (r *RedundantCommentRule) Name() string {
+	return "Redundant Comment"
+}
+
+// Description returns a description of this rule
+func (r *RedundantCommentRule) Description() string {
+	return "Detects comments that simply restate what the code does"
+}
+
+// Rationale explains why this rule exists
+func (r *RedundantCommentRule) Rationale() string {
+	return "A comment that repeats the statement it sits on tells the reader nothing the code does not, " +
+		"and it is one more thing to keep in sync. The rule compares the words of each comment with the " +
+		"identifiers of the code it describes and reports comments whose words mostly appear there."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *RedundantCommentRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `# increment the retry count
+retry_count += 1`,
+		Good: `# the first attempt does not count as a retry
+retry_count += 1`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *RedundantCommentRule) Options() []core.RuleOption {
+	return core.RedundantCommentOptions()
+}
+
+// Category returns the category of this rule
+func (r *RedundantCommentRule) Category() core.RuleCategory {
+	return core.CategoryComments
+}
+
+// Severity returns the severity of violations of this rule
+func (r *RedundantCommentRule) Severity() core.Severity {
+	return core.SeverityInfo
+}
+
+// Check checks if a comment line violates this rule
+func (r *RedundantCommentRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	if !config.Rules.Overcommenting.CheckRedundant {
+		return nil
+	}
+
+	threshold := config.Rules.Overcommenting.RedundantThreshold
+	if threshold <= 0 {
+		threshold = defaultRedundantThreshold
+	}
+
+	n, ok := node.(*CommentLine)
+	if !ok || n.Code == "" {
+		return nil
+	}
+
+	overlap := languages.CommentRedundancy(n.Text, n.Code)
+	if overlap < threshold {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Confidence: string(core.ConfidenceLow),
+		Line:       n.Line,
+		Message:    fmt.Sprintf("Comment restates the code it describes (%.0f%% word overlap): %q", overlap*100, n.Text),
+		Suggestion: "Consider removing this redundant comment or explaining why the code does this instead",
+	}
+}
@@ -0,0 +1,435 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// placeholderTexts are docstring summaries and entry descriptions left by templates and generators
+var placeholderTexts = map[string]bool{
+	"":                        true,
+	"...":                     true,
+	"todo":                    true,
+	"tbd":                     true,
+	"fixme":                   true,
+	"xxx":                     true,
+	"description":             true,
+	"[description]":           true,
+	"_description_":           true,
+	"_summary_":               true,
+	"summary":                 true,
+	"docstring":               true,
+	"function description":    true,
+	"method description":      true,
+	"function docstring":      true,
+	"brief description":       true,
+	"short description":       true,
+	"add description here":    true,
+	"insert description here": true,
+	"your docstring here":     true,
+	"todo: add docstring":     true,
+	"todo: add description":   true,
+	"todo: write docstring":   true,
+}
+
+var (
+	googleSectionPattern = regexp.MustCompile(`^(Args|Arguments|Parameters|Params|Keyword Args|Keyword Arguments|Returns|Return|Yields|Raises):\s*$`)
+	googleEntryPattern   = regexp.MustCompile(`^(\*{0,2}\w+)\s*(?:\([^)]*\))?\s*:\s*(.*)$`)
+	sphinxParamPattern   = regexp.MustCompile(`^:param\s+(?:[^:]*\s)?(\*{0,2}\w+)\s*:\s*(.*)$`)
+	sphinxFieldPattern   = regexp.MustCompile(`^:(returns?|raises?\s*\w*|rtype|type\s+\w+)\s*:\s*(.*)$`)
+	numpyEntryPattern    = regexp.MustCompile(`^(\*{0,2}\w+)\s*(?::.*)?$`)
+)
+
+// docstringEntry is a documented parameter or section item
+type docstringEntry struct {
+	section     string
+	name        string
+	description string
+}
+
+// PlaceholderDocstringRule detects docstrings that are unfilled templates
+type PlaceholderDocstringRule struct {
+	config core.Config
+}
+
+// NewPlaceholderDocstringRule creates a new placeholder docstring rule
+func NewPlaceholderDocstringRule(config core.Config) *PlaceholderDocstringRule {
+	return &PlaceholderDocstringRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *PlaceholderDocstringRule) ID() string {
+	return "placeholder-docstring"
+}
+
+// Name returns the name of this rule
+func (r *PlaceholderDocstringRule) Name() string {
+	return "Placeholder Docstring"
+}
+
+// Description returns a description of this rule
+func (r *PlaceholderDocstringRule) Description() string {
+	return "Detects docstrings that are templates or contain TODO placeholders"
+}
+
+// Rationale explains why this rule exists
+func (r *PlaceholderDocstringRule) Rationale() string {
+	return "Editors and code generators insert docstring templates such as \"_summary_\" or " +
+		"\"TODO: add description\". Left unfilled, they look like documentation to tools that measure " +
+		"coverage while telling the reader nothing. Summaries and the entries of Google, Sphinx and " +
+		"NumPy style sections are checked."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *PlaceholderDocstringRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `def retry(fn, attempts):
+    """_summary_
+
+    Args:
+        fn (_type_): _description_
+        attempts (_type_): _description_
+    """`,
+		Good: `def retry(fn, attempts):
+    """Call fn until it succeeds, at most attempts times.
+
+    Args:
+        fn: Callable taking no arguments.
+        attempts: Number of calls before the last error is raised.
+    """`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *PlaceholderDocstringRule) Options() []core.RuleOption {
+	return []core.RuleOption{
+		{Key: "rules.docstrings.enabled", Flag: "-enable-docstrings", Default: "true", Description: "Run the docstring rules"},
+		{Key: "rules.docstrings.checkPlaceholders", Flag: "-check-docstring-placeholders", Default: "true", Description: "Report template and TODO docstrings"},
+	}
+}
+
+// Category returns the category of this rule
+func (r *PlaceholderDocstringRule) Category() core.RuleCategory {
+	return core.CategoryComments
+}
+
+// Severity returns the severity of violations of this rule
+func (r *PlaceholderDocstringRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a function's docstring violates this rule
+func (r *PlaceholderDocstringRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	if !config.Rules.Docstrings.CheckPlaceholders {
+		return nil
+	}
+
+	n, ok := node.(*FunctionMetrics)
+	if !ok || n.DocstringLine == 0 {
+		return nil
+	}
+
+	var problem string
+	if summary, ok := docstringSummary(n.Docstring); ok && isPlaceholder(summary) {
+		problem = "summary is a template placeholder"
+	} else {
+		for _, entry := range parseDocstringEntries(n.Docstring) {
+			if entry.section != "numpy" && isPlaceholder(entry.description) {
+				problem = fmt.Sprintf("%s entry %q has a placeholder description", entry.section, entry.name)
+				break
+			}
+		}
+	}
+	if problem == "" {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.DocstringLine,
+		Message:    fmt.Sprintf("Docstring of '%s' is unfinished: %s", n.Name, problem),
+		Suggestion: fmt.Sprintf("Describe what '%s' does or remove the template docstring", n.Name),
+	}
+}
+
+// DocstringParamMismatchRule detects docstrings whose parameter list differs from the signature
+type DocstringParamMismatchRule struct {
+	config core.Config
+}
+
+// NewDocstringParamMismatchRule creates a new docstring parameter mismatch rule
+func NewDocstringParamMismatchRule(config core.Config) *DocstringParamMismatchRule {
+	return &DocstringParamMismatchRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *DocstringParamMismatchRule) ID() string {
+	return "docstring-param-mismatch"
+}
+
+// Name returns the name of this rule
+func (r *DocstringParamMismatchRule) Name() string {
+	return "Docstring Parameter Mismatch"
+}
+
+// Description returns a description of this rule
+func (r *DocstringParamMismatchRule) Description() string {
+	return "Detects docstrings that document parameters the function does not have, or omit ones it does"
+}
+
+// Rationale explains why this rule exists
+func (r *DocstringParamMismatchRule) Rationale() string {
+	return "When a parameter is renamed, added or removed, the docstring is easy to forget. A docstring " +
+		"that documents parameters the function no longer has is worse than none, because readers " +
+		"trust it. The Google, Sphinx and NumPy parameter sections are compared with the signature."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *DocstringParamMismatchRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `def fetch(url, timeout=10):
+    """Fetch a page.
+
+    Args:
+        uri: Address of the page.
+    """`,
+		Good: `def fetch(url, timeout=10):
+    """Fetch a page.
+
+    Args:
+        url: Address of the page.
+        timeout: Seconds to wait for the server.
+    """`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *DocstringParamMismatchRule) Options() []core.RuleOption {
+	return []core.RuleOption{
+		{Key: "rules.docstrings.enabled", Flag: "-enable-docstrings", Default: "true", Description: "Run the docstring rules"},
+		{Key: "rules.docstrings.checkParameters", Flag: "-check-docstring-params", Default: "true", Description: "Compare documented parameters with the signature"},
+	}
+}
+
+// Category returns the category of this rule
+func (r *DocstringParamMismatchRule) Category() core.RuleCategory {
+	return core.CategoryComments
+}
+
+// Severity returns the severity of violations of this rule
+func (r *DocstringParamMismatchRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a function's docstring violates this rule
+func (r *DocstringParamMismatchRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	if !config.Rules.Docstrings.CheckParameters {
+		return nil
+	}
+
+	n, ok := node.(*FunctionMetrics)
+	if !ok || n.DocstringLine == 0 {
+		return nil
+	}
+
+	documented := make(map[string]bool)
+	keywordOnly := make(map[string]bool)
+	for _, entry := range parseDocstringEntries(n.Docstring) {
+		if !isParamSection(entry.section) {
+			continue
+		}
+		name := strings.TrimLeft(entry.name, "*")
+		documented[name] = true
+		if strings.HasPrefix(entry.section, "keyword") {
+			keywordOnly[name] = true
+		}
+	}
+	if len(documented) == 0 {
+		return nil
+	}
+
+	actual := make(map[string]bool)
+	hasVarKeyword := false
+	var missing []string
+	for _, param := range n.Parameters {
+		name := strings.TrimLeft(param, "*")
+		actual[name] = true
+		if strings.HasPrefix(param, "**") {
+			hasVarKeyword = true
+		}
+		// *args and **kwargs are often described in prose instead of listed
+		if !documented[name] && !strings.HasPrefix(param, "*") {
+			missing = append(missing, name)
+		}
+	}
+
+	var unknown []string
+	for name := range documented {
+		if actual[name] || (hasVarKeyword && keywordOnly[name]) {
+			continue
+		}
+		unknown = append(unknown, name)
+	}
+	sort.Strings(unknown)
+
+	if len(missing) == 0 && len(unknown) == 0 {
+		return nil
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missing "+strings.Join(missing, ", "))
+	}
+	if len(unknown) > 0 {
+		problems = append(problems, "documents unknown "+strings.Join(unknown, ", "))
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.DocstringLine,
+		Message:    fmt.Sprintf("Docstring parameters of '%s' do not match its signature (%s)", n.Name, strings.Join(problems, "; ")),
+		Suggestion: fmt.Sprintf("Update the docstring of '%s' to document its actual parameters", n.Name),
+	}
+}
+
+// docstringSummary returns the first paragraph of a docstring, or false when the
+// docstring opens directly with a section and has no summary
+func docstringSummary(docstring string) (string, bool) {
+	summary, _, _ := strings.Cut(strings.TrimSpace(docstring), "\n\n")
+	firstLine, _, _ := strings.Cut(summary, "\n")
+	if googleSectionPattern.MatchString(firstLine) || strings.HasPrefix(firstLine, ":") {
+		return "", false
+	}
+	return summary, true
+}
+
+// isPlaceholder reports whether text is a template placeholder rather than a description
+func isPlaceholder(text string) bool {
+	normalized := strings.ToLower(strings.Join(strings.Fields(text), " "))
+	if placeholderTexts[normalized] {
+		return true
+	}
+	return placeholderTexts[strings.TrimSuffix(normalized, ".")] || strings.HasPrefix(normalized, "todo:")
+}
+
+// isParamSection reports whether entries in the section document parameters
+func isParamSection(section string) bool {
+	switch section {
+	case "args", "arguments", "parameters", "params", "keyword args", "keyword arguments", "param", "numpy":
+		return true
+	}
+	return false
+}
+
+// parseDocstringEntries extracts section entries from Google, Sphinx and NumPy style docstrings
+func parseDocstringEntries(docstring string) []docstringEntry {
+	lines := strings.Split(docstring, "\n")
+	var entries []docstringEntry
+
+	section := ""
+	entryIndent := -1
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if m := sphinxParamPattern.FindStringSubmatch(trimmed); m != nil {
+			entries = append(entries, docstringEntry{section: "param", name: m[1], description: m[2]})
+			section = ""
+			continue
+		}
+		if m := sphinxFieldPattern.FindStringSubmatch(trimmed); m != nil {
+			entries = append(entries, docstringEntry{section: strings.Fields(m[1])[0], name: m[1], description: m[2]})
+			section = ""
+			continue
+		}
+
+		// NumPy style: "Parameters" underlined with dashes
+		if i+1 < len(lines) && isNumpyParamHeader(trimmed, strings.TrimSpace(lines[i+1])) {
+			entries = append(entries, parseNumpyEntries(lines[i+2:], indent)...)
+			section = ""
+			continue
+		}
+
+		if m := googleSectionPattern.FindStringSubmatch(trimmed); m != nil {
+			section = strings.ToLower(m[1])
+			entryIndent = -1
+			continue
+		}
+		if section == "" || trimmed == "" {
+			continue
+		}
+
+		if entryIndent == -1 {
+			entryIndent = indent
+		}
+		if indent < entryIndent {
+			section = ""
+			continue
+		}
+		if indent > entryIndent {
+			continue // continuation of the previous entry's description
+		}
+
+		if m := googleEntryPattern.FindStringSubmatch(trimmed); m != nil {
+			description := m[2]
+			if description == "" && i+1 < len(lines) {
+				// Description may start on the following, further indented line
+				next := lines[i+1]
+				if len(next)-len(strings.TrimLeft(next, " \t")) > indent {
+					description = strings.TrimSpace(next)
+				}
+			}
+			entries = append(entries, docstringEntry{section: section, name: m[1], description: description})
+		} else if section == "returns" || section == "return" || section == "yields" {
+			entries = append(entries, docstringEntry{section: section, name: section, description: trimmed})
+		}
+	}
+	return entries
+}
+
+// isNumpyParamHeader reports whether a line and its successor form a NumPy "Parameters" header
+func isNumpyParamHeader(line, underline string) bool {
+	if line != "Parameters" && line != "Other Parameters" {
+		return false
+	}
+	return len(underline) >= 3 && strings.Trim(underline, "-") == ""
+}
+
+// parseNumpyEntries reads parameter names from the body of a NumPy "Parameters" section
+func parseNumpyEntries(lines []string, headerIndent int) []docstringEntry {
+	var entries []docstringEntry
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		// The next section header ends this one
+		if i+1 < len(lines) && strings.Trim(strings.TrimSpace(lines[i+1]), "-") == "" && strings.TrimSpace(lines[i+1]) != "" {
+			break
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent > headerIndent {
+			continue
+		}
+		if m := numpyEntryPattern.FindStringSubmatch(trimmed); m != nil {
+			entries = append(entries, docstringEntry{section: "numpy", name: m[1]})
+		}
+	}
+	return entries
+}
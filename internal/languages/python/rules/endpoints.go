@@ -0,0 +1,98 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+)
+
+// HardcodedEndpointRule detects URLs, IP addresses and ports written into string literals
+type HardcodedEndpointRule struct {
+	config core.Config
+}
+
+// NewHardcodedEndpointRule creates a new hardcoded endpoint rule
+func NewHardcodedEndpointRule(config core.Config) *HardcodedEndpointRule {
+	return &HardcodedEndpointRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *HardcodedEndpointRule) ID() string {
+	return languages.EndpointRuleID
+}
+
+// Name returns the name of this rule
+func (r *HardcodedEndpointRule) Name() string {
+	return "Hardcoded Endpoint"
+}
+
+// Description returns a description of this rule
+func (r *HardcodedEndpointRule) Description() string {
+	return "Detects http:// URLs, example hosts, IP addresses and ports hardcoded outside configuration and test files"
+}
+
+// Rationale explains why this rule exists
+func (r *HardcodedEndpointRule) Rationale() string {
+	return "Generated code fills in the endpoints it needs with whatever the example used: " +
+		"localhost:8000, api.example.com or 127.0.0.1. They work on the developer's machine and " +
+		"reach production unnoticed, where the service cannot be pointed anywhere else without a code change."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *HardcodedEndpointRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad:  `response = requests.get("http://localhost:8000/api/users")`,
+		Good: `response = requests.get(f"{settings.API_URL}/api/users")`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *HardcodedEndpointRule) Options() []core.RuleOption {
+	return core.EndpointOptions()
+}
+
+// Category returns the category of this rule
+func (r *HardcodedEndpointRule) Category() core.RuleCategory {
+	return core.CategoryBug
+}
+
+// Severity returns the severity of violations of this rule
+func (r *HardcodedEndpointRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check is unused; endpoints are found line by line
+func (r *HardcodedEndpointRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// ReadsStrings reports that the rule is given lines with their string literals unmasked
+func (r *HardcodedEndpointRule) ReadsStrings() bool {
+	return true
+}
+
+// CheckLine checks the string literals of a line for a hardcoded endpoint. The analyzer skips
+// test and configuration files.
+func (r *HardcodedEndpointRule) CheckLine(line string, lineNum int) *core.Result {
+	for _, str := range languages.QuotedStrings(line, `"'`, "#") {
+		endpoint, found := languages.FindEndpoint(str.Text, r.config.Rules.Endpoints.Allow)
+		if !found {
+			continue
+		}
+		return &core.Result{
+			RuleID:     r.ID(),
+			RuleName:   r.Name(),
+			Category:   string(r.Category()),
+			Severity:   string(r.Severity()),
+			Line:       lineNum,
+			Column:     str.Column,
+			Message:    fmt.Sprintf("Hardcoded %s %q", endpoint.Kind, endpoint.Value),
+			Suggestion: "Read the endpoint from settings or an environment variable, or add it to rules.endpoints.allow",
+		}
+	}
+	return nil
+}
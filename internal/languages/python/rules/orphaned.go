@@ -24,6 +24,24 @@ func (r *UnusedFunctionRule) Description() string { return "Detects functions th
 func (r *UnusedFunctionRule) Category() core.RuleCategory { return core.CategoryOrphaned }
 func (r *UnusedFunctionRule) Severity() core.Severity     { return core.SeverityWarning }
 
+func (r *UnusedFunctionRule) Rationale() string {
+	return "Functions nothing calls are left behind when code is rewritten rather than edited, and they " +
+		"mislead readers into thinking they matter. Python resolves calls at run time, so the rule is " +
+		"conservative: dunder methods, tests, setUp and tearDown hooks and main are never reported."
+}
+
+func (r *UnusedFunctionRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `def _format_legacy(record):  # replaced by _format, never called
+    return f"{record.name};{record.id}"`,
+		Good: `FORMATTERS = {"legacy": _format_legacy}  # referenced, so it is used`,
+	}}
+}
+
+func (r *UnusedFunctionRule) Options() []core.RuleOption {
+	return core.UnusedFunctionOptions()
+}
+
 func (r *UnusedFunctionRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	if !config.Rules.OrphanedCode.CheckUnusedFunctions {
 		return nil
@@ -70,6 +88,25 @@ func (r *UnusedVariableRule) Description() string { return "Detects variables th
 func (r *UnusedVariableRule) Category() core.RuleCategory { return core.CategoryOrphaned }
 func (r *UnusedVariableRule) Severity() core.Severity     { return core.SeverityWarning }
 
+func (r *UnusedVariableRule) Rationale() string {
+	return "A variable that is assigned but never read is either dead code or a sign that a result is " +
+		"being dropped by mistake."
+}
+
+func (r *UnusedVariableRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `def total(items):
+    count = len(items)
+    return sum(item.price for item in items)`,
+		Good: `def total(items):
+    return sum(item.price for item in items)`,
+	}}
+}
+
+func (r *UnusedVariableRule) Options() []core.RuleOption {
+	return core.UnusedVariableOptions()
+}
+
 func (r *UnusedVariableRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	if !config.Rules.OrphanedCode.CheckUnusedVariables {
 		return nil
@@ -92,6 +129,27 @@ func (r *UnreachableCodeRule) Description() string { return "Detects code that c
 func (r *UnreachableCodeRule) Category() core.RuleCategory { return core.CategoryOrphaned }
 func (r *UnreachableCodeRule) Severity() core.Severity     { return core.SeverityWarning }
 
+func (r *UnreachableCodeRule) Rationale() string {
+	return "Statements after a return or raise in the same block can never run. They usually remain " +
+		"from an edit that added an early exit, and readers waste time working out when they " +
+		"execute."
+}
+
+func (r *UnreachableCodeRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `def load(path):
+    return path.read_text()
+    logger.info("loaded %s", path)`,
+		Good: `def load(path):
+    logger.info("loading %s", path)
+    return path.read_text()`,
+	}}
+}
+
+func (r *UnreachableCodeRule) Options() []core.RuleOption {
+	return core.UnreachableCodeOptions()
+}
+
 func (r *UnreachableCodeRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	if !config.Rules.OrphanedCode.CheckUnreachableCode {
 		return nil
@@ -114,6 +172,23 @@ func (r *DeadImportRule) Description() string { return "Detects imports that are
 func (r *DeadImportRule) Category() core.RuleCategory { return core.CategoryOrphaned }
 func (r *DeadImportRule) Severity() core.Severity     { return core.SeverityWarning }
 
+func (r *DeadImportRule) Rationale() string {
+	return "An import nothing uses slows down start-up, can have side effects and hints at code that " +
+		"was removed only halfway."
+}
+
+func (r *DeadImportRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `import json
+import re  # nothing in the module uses re`,
+		Good: `import json`,
+	}}
+}
+
+func (r *DeadImportRule) Options() []core.RuleOption {
+	return core.DeadImportOptions()
+}
+
 func (r *DeadImportRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	if !config.Rules.OrphanedCode.CheckDeadImports {
 		return nil
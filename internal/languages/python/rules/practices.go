@@ -0,0 +1,464 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// LineCheckRule is implemented by rules that check individual lines
+type LineCheckRule interface {
+	core.Rule
+	CheckLine(line string, lineNum int) *core.Result
+}
+
+// BareExceptRule detects except clauses without an exception type
+type BareExceptRule struct {
+	config  core.Config
+	pattern *regexp.Regexp
+}
+
+// NewBareExceptRule creates a new bare except rule
+func NewBareExceptRule(config core.Config) *BareExceptRule {
+	return &BareExceptRule{
+		config:  config,
+		pattern: regexp.MustCompile(`^\s*except\s*:`),
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *BareExceptRule) ID() string {
+	return "bare-except"
+}
+
+// Name returns the name of this rule
+func (r *BareExceptRule) Name() string {
+	return "Bare Except"
+}
+
+// Description returns a description of this rule
+func (r *BareExceptRule) Description() string {
+	return "Detects except clauses that catch every exception"
+}
+
+// Rationale explains why this rule exists
+func (r *BareExceptRule) Rationale() string {
+	return "A bare except: catches everything, including KeyboardInterrupt, SystemExit and the typos " +
+		"and attribute errors of the code inside the try block. Errors that should crash loudly are " +
+		"silently swallowed, and Ctrl-C stops working."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *BareExceptRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `try:
+    config = json.load(f)
+except:
+    config = {}`,
+		Good: `try:
+    config = json.load(f)
+except json.JSONDecodeError:
+    config = {}`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *BareExceptRule) Options() []core.RuleOption {
+	return nil
+}
+
+// Category returns the category of this rule
+func (r *BareExceptRule) Category() core.RuleCategory {
+	return core.CategoryBug
+}
+
+// Severity returns the severity of violations of this rule
+func (r *BareExceptRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check is unused; bare except clauses are found line by line
+func (r *BareExceptRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// CheckLine checks a single line for an except clause without an exception type
+func (r *BareExceptRule) CheckLine(line string, lineNum int) *core.Result {
+	if !r.pattern.MatchString(line) {
+		return nil
+	}
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       lineNum,
+		Message:    "Bare 'except:' also catches KeyboardInterrupt and SystemExit and hides unexpected errors",
+		Suggestion: "Catch the specific exceptions expected here, or 'except Exception:' at most",
+	}
+}
+
+// WildcardImportRule detects "from module import *" statements
+type WildcardImportRule struct {
+	config  core.Config
+	pattern *regexp.Regexp
+}
+
+// NewWildcardImportRule creates a new wildcard import rule
+func NewWildcardImportRule(config core.Config) *WildcardImportRule {
+	return &WildcardImportRule{
+		config:  config,
+		pattern: regexp.MustCompile(`^\s*from\s+(\S+)\s+import\s+\*`),
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *WildcardImportRule) ID() string {
+	return "wildcard-import"
+}
+
+// Name returns the name of this rule
+func (r *WildcardImportRule) Name() string {
+	return "Wildcard Import"
+}
+
+// Description returns a description of this rule
+func (r *WildcardImportRule) Description() string {
+	return "Detects wildcard imports that hide where names come from"
+}
+
+// Rationale explains why this rule exists
+func (r *WildcardImportRule) Rationale() string {
+	return "from module import * pulls every public name of a module into the namespace, so readers " +
+		"and tools cannot tell where a name comes from, a later import can silently shadow an earlier " +
+		"one, and unused imports go unnoticed."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *WildcardImportRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad:  `from os.path import *`,
+		Good: `from os.path import exists, join`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *WildcardImportRule) Options() []core.RuleOption {
+	return nil
+}
+
+// Category returns the category of this rule
+func (r *WildcardImportRule) Category() core.RuleCategory {
+	return core.CategoryStyle
+}
+
+// Severity returns the severity of violations of this rule
+func (r *WildcardImportRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check is unused; wildcard imports are found line by line
+func (r *WildcardImportRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// CheckLine checks a single line for a wildcard import
+func (r *WildcardImportRule) CheckLine(line string, lineNum int) *core.Result {
+	matches := r.pattern.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       lineNum,
+		Message:    fmt.Sprintf("'from %s import *' pulls every public name into the module, hiding unused imports and definitions", matches[1]),
+		Suggestion: fmt.Sprintf("Import the names used explicitly: from %s import name1, name2", matches[1]),
+	}
+}
+
+// PrintDebugRule detects print() calls left over from debugging
+type PrintDebugRule struct {
+	config  core.Config
+	pattern *regexp.Regexp
+}
+
+// NewPrintDebugRule creates a new print debug rule
+func NewPrintDebugRule(config core.Config) *PrintDebugRule {
+	return &PrintDebugRule{
+		config:  config,
+		pattern: regexp.MustCompile(`^\s*print\s*\(`),
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *PrintDebugRule) ID() string {
+	return "print-debug"
+}
+
+// Name returns the name of this rule
+func (r *PrintDebugRule) Name() string {
+	return "Print Debug"
+}
+
+// Description returns a description of this rule
+func (r *PrintDebugRule) Description() string {
+	return "Detects print() statements outside command-line entry points"
+}
+
+// Rationale explains why this rule exists
+func (r *PrintDebugRule) Rationale() string {
+	return "print() calls added while debugging tend to survive into commits, where they clutter the " +
+		"output of every caller and cannot be silenced or routed like log records. Command-line entry " +
+		"points, such as modules with an if __name__ == \"__main__\": block, __main__.py, cli.py, " +
+		"manage.py and setup.py, are skipped because printing is their output."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *PrintDebugRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `def charge(order):
+    print("order", order)
+    return gateway.charge(order.total)`,
+		Good: `logger = logging.getLogger(__name__)
+
+def charge(order):
+    logger.debug("charging order %s", order.id)
+    return gateway.charge(order.total)`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *PrintDebugRule) Options() []core.RuleOption {
+	return nil
+}
+
+// Category returns the category of this rule
+func (r *PrintDebugRule) Category() core.RuleCategory {
+	return core.CategoryStyle
+}
+
+// Severity returns the severity of violations of this rule
+func (r *PrintDebugRule) Severity() core.Severity {
+	return core.SeverityInfo
+}
+
+// Check is unused; print statements are found line by line
+func (r *PrintDebugRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// CheckLine checks a single line for a print() statement. The analyzer skips command-line
+// modules, where printing is the output.
+func (r *PrintDebugRule) CheckLine(line string, lineNum int) *core.Result {
+	if !r.pattern.MatchString(line) {
+		return nil
+	}
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       lineNum,
+		Message:    "print() statement looks like leftover debugging output",
+		Suggestion: "Remove the print() or use the logging module",
+	}
+}
+
+// mutableDefaultPattern matches default values that create a mutable object
+var mutableDefaultPattern = regexp.MustCompile(`^(?:\[|\{|(?:list|dict|set|bytearray|defaultdict|OrderedDict|deque|Counter)\s*\()`)
+
+// MutableDefaultRule detects parameters whose default value is a mutable object
+type MutableDefaultRule struct {
+	config core.Config
+}
+
+// NewMutableDefaultRule creates a new mutable default argument rule
+func NewMutableDefaultRule(config core.Config) *MutableDefaultRule {
+	return &MutableDefaultRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *MutableDefaultRule) ID() string {
+	return "mutable-default-argument"
+}
+
+// Name returns the name of this rule
+func (r *MutableDefaultRule) Name() string {
+	return "Mutable Default Argument"
+}
+
+// Description returns a description of this rule
+func (r *MutableDefaultRule) Description() string {
+	return "Detects parameters with mutable default values shared between calls"
+}
+
+// Rationale explains why this rule exists
+func (r *MutableDefaultRule) Rationale() string {
+	return "Default values are evaluated once, when the def statement runs, so a list, dict or set " +
+		"default is shared by every call that does not pass the argument. Anything one call adds to it " +
+		"is still there in the next."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *MutableDefaultRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `def add_tag(tag, tags=[]):
+    tags.append(tag)
+    return tags`,
+		Good: `def add_tag(tag, tags=None):
+    if tags is None:
+        tags = []
+    tags.append(tag)
+    return tags`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *MutableDefaultRule) Options() []core.RuleOption {
+	return nil
+}
+
+// Category returns the category of this rule
+func (r *MutableDefaultRule) Category() core.RuleCategory {
+	return core.CategoryBug
+}
+
+// Severity returns the severity of violations of this rule
+func (r *MutableDefaultRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a function has parameters with mutable defaults
+func (r *MutableDefaultRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*FunctionMetrics)
+	if !ok {
+		return nil
+	}
+
+	var mutable []string
+	for _, param := range n.Signature {
+		if mutableDefaultPattern.MatchString(param.Default) {
+			mutable = append(mutable, param.Name+"="+param.Default)
+		}
+	}
+	if len(mutable) == 0 {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.StartLine,
+		Message:    fmt.Sprintf("Function '%s' has mutable default arguments shared between calls: %s", n.Name, strings.Join(mutable, ", ")),
+		Suggestion: "Default to None and create the object inside the function",
+	}
+}
+
+// SleepSynchronizationRule detects time.sleep() in async functions and in tests
+type SleepSynchronizationRule struct {
+	config core.Config
+}
+
+// NewSleepSynchronizationRule creates a new sleep synchronization rule
+func NewSleepSynchronizationRule(config core.Config) *SleepSynchronizationRule {
+	return &SleepSynchronizationRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *SleepSynchronizationRule) ID() string {
+	return "sleep-synchronization"
+}
+
+// Name returns the name of this rule
+func (r *SleepSynchronizationRule) Name() string {
+	return "Sleep Synchronization"
+}
+
+// Description returns a description of this rule
+func (r *SleepSynchronizationRule) Description() string {
+	return "Detects time.sleep() in async functions and in test functions"
+}
+
+// Rationale explains why this rule exists
+func (r *SleepSynchronizationRule) Rationale() string {
+	return "time.sleep() in an async function blocks the event loop, stopping every other task for as " +
+		"long as it sleeps. In a test it waits for a thread, a server or a retry to have probably finished, " +
+		"which makes the test slow when the sleep is long and flaky when it is not."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *SleepSynchronizationRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `async def poll(job):
+    while not job.done:
+        time.sleep(1)`,
+		Good: `async def poll(job):
+    while not job.done:
+        await asyncio.sleep(1)`,
+	}, {
+		Bad: `def test_worker_processes_job():
+    worker.start()
+    time.sleep(2)
+    assert job.done`,
+		Good: `def test_worker_processes_job():
+    worker.start()
+    assert job.finished.wait(timeout=5)`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *SleepSynchronizationRule) Options() []core.RuleOption {
+	return core.ConcurrencyOptions()
+}
+
+// Category returns the category of this rule
+func (r *SleepSynchronizationRule) Category() core.RuleCategory {
+	return core.CategoryBug
+}
+
+// Severity returns the severity of violations of this rule
+func (r *SleepSynchronizationRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if an async function or a test function, named test*, calls time.sleep()
+func (r *SleepSynchronizationRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*FunctionMetrics)
+	if !ok || n.SleepLine == 0 {
+		return nil
+	}
+
+	var message, suggestion string
+	switch {
+	case n.IsAsync:
+		message = fmt.Sprintf("Async function '%s' calls time.sleep(), which blocks the event loop", n.Name)
+		suggestion = "Use await asyncio.sleep(), or better, await the event or task being waited for"
+	case strings.HasPrefix(n.Name, "test"):
+		message = fmt.Sprintf("Test '%s' calls time.sleep() to wait, which makes it slow or flaky", n.Name)
+		suggestion = "Wait on an event, a future or a polling helper with a timeout instead of a fixed sleep"
+	default:
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.SleepLine,
+		Message:    message,
+		Suggestion: suggestion,
+	}
+}
@@ -9,14 +9,30 @@ import (
 
 // FunctionMetrics contains metrics about a Python function
 type FunctionMetrics struct {
-	Name         string
-	IsMethod     bool
-	ClassName    string
-	IsPrivate    bool
-	LineCount    int
-	StartLine    int
-	NestingDepth int
-	Decorators   []string
+	Name          string
+	IsMethod      bool
+	ClassName     string
+	IsPrivate     bool
+	LineCount     int
+	LogicalLines  int // statements, counting multi-line statements once
+	StartLine     int
+	EndLine       int // last non-blank line of the body
+	NestingDepth  int
+	Decorators    []string
+	Parameters    []string    // excluding self/cls for methods
+	Signature     []Parameter // the same parameters with their annotations and defaults
+	Docstring     string
+	DocstringLine int
+	BranchChain   BranchChain // the if-elif chain or match statement with the most branches
+	IsAsync       bool
+	SleepLine     int // first line of the body calling time.sleep, 0 if none
+}
+
+// Parameter is a function parameter as written in its signature
+type Parameter struct {
+	Name       string // keeps any * or ** prefix
+	Annotation string
+	Default    string
 }
 
 // FileMetrics contains metrics about a Python file
@@ -30,6 +46,16 @@ type FileMetrics struct {
 	FunctionCount int
 	ImportCount   int
 	ClassCount    int
+	Generated     bool // carries a code generation marker
+	Vendored      bool // lies in a vendored dependency directory
+	TypeHints     TypeHintCoverage
+}
+
+// TypeHintCoverage counts the function signatures of a file that are fully annotated
+type TypeHintCoverage struct {
+	Signatures       int
+	Annotated        int
+	FirstUnannotated int // line of the first signature missing an annotation
 }
 
 // LargeFunctionRule detects functions that are too large
@@ -56,7 +82,34 @@ func (r *LargeFunctionRule) Name() string {
 
 // Description returns a description of this rule
 func (r *LargeFunctionRule) Description() string {
-	return "Detects functions that exceed the maximum number of lines"
+	return "Detects functions that exceed the maximum number of lines or logical lines"
+}
+
+// Rationale explains why this rule exists
+func (r *LargeFunctionRule) Rationale() string {
+	return "Long functions mix several steps and levels of abstraction, which makes them hard to review, " +
+		"test and change safely. Generated code tends to grow one function instead of adding new ones. " +
+		"A function is measured from its def line to its last line, or by its logical lines when the " +
+		"metric is statements, so comments, docstrings and blank lines do not count against it."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *LargeFunctionRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `def import_orders(path):
+    # open the file, parse every row, validate the fields,
+    # price the items and write them to the database: 120 lines
+    ...`,
+		Good: `def import_orders(path):
+    rows = read_rows(path)
+    orders = [validate(row) for row in rows]
+    save_orders(price(order) for order in orders)`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *LargeFunctionRule) Options() []core.RuleOption {
+	return core.FunctionSizeOptions("python", "python")
 }
 
 // Category returns the category of this rule
@@ -75,19 +128,28 @@ func (r *LargeFunctionRule) Check(ctx context.Context, node interface{}, config
 
 	switch n := node.(type) {
 	case *FunctionMetrics:
-		if n.LineCount > maxLines {
+		size, unit := n.LineCount, "lines"
+		if config.Rules.FunctionSize.Metric == core.MetricStatements {
+			size, unit = n.LogicalLines, "logical lines"
+		}
+		if size > maxLines {
 			funcType := "Function"
 			if n.IsMethod {
 				funcType = "Method"
 			}
+			suggestion := fmt.Sprintf("Consider breaking down %s '%s' into smaller functions", funcType, n.Name)
+			if config.Output.Verbose {
+				suggestion += fmt.Sprintf(" (%d lines, %d logical lines)", n.LineCount, n.LogicalLines)
+			}
 			return &core.Result{
 				RuleID:     r.ID(),
 				RuleName:   r.Name(),
 				Category:   string(r.Category()),
 				Severity:   string(r.Severity()),
 				Line:       n.StartLine,
-				Message:    fmt.Sprintf("%s '%s' is too large (%d lines, max %d)", funcType, n.Name, n.LineCount, maxLines),
-				Suggestion: fmt.Sprintf("Consider breaking down %s '%s' into smaller functions", funcType, n.Name),
+				EndLine:    n.EndLine,
+				Message:    fmt.Sprintf("%s '%s' is too large (%d %s, max %d)", funcType, n.Name, size, unit, maxLines),
+				Suggestion: suggestion,
 			}
 		}
 	}
@@ -122,6 +184,30 @@ func (r *LargeFileRule) Description() string {
 	return "Detects files that exceed the maximum number of lines"
 }
 
+// Rationale explains why this rule exists
+func (r *LargeFileRule) Rationale() string {
+	return "A large module usually holds several unrelated concerns, so readers have to page through code " +
+		"they do not care about and changes collide in review. Generated modules and vendored packages " +
+		"are never reported."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *LargeFileRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `# utils.py, 1800 lines: date helpers, HTTP clients,
+# database access and report rendering`,
+		Good: `# dates.py    date helpers
+# clients.py  HTTP clients
+# db.py       database access
+# reports.py  report rendering`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *LargeFileRule) Options() []core.RuleOption {
+	return core.FileSizeOptions("python", "python")
+}
+
 // Category returns the category of this rule
 func (r *LargeFileRule) Category() core.RuleCategory {
 	return core.CategorySize
@@ -138,14 +224,21 @@ func (r *LargeFileRule) Check(ctx context.Context, node interface{}, config core
 
 	switch n := node.(type) {
 	case *FileMetrics:
-		if n.TotalLines > maxLines {
+		if n.Generated || n.Vendored {
+			return nil
+		}
+		size, unit := n.TotalLines, "lines"
+		if config.Rules.FileSize.CountMode == core.CountCode {
+			size, unit = n.CodeLines, "code lines"
+		}
+		if size > maxLines {
 			return &core.Result{
 				RuleID:     r.ID(),
 				RuleName:   r.Name(),
 				Category:   string(r.Category()),
 				Severity:   string(r.Severity()),
 				Line:       1,
-				Message:    fmt.Sprintf("File is too large (%d lines, max %d)", n.TotalLines, maxLines),
+				Message:    fmt.Sprintf("File is too large (%d %s, max %d)", size, unit, maxLines),
 				Suggestion: "Consider splitting this file into multiple smaller modules",
 			}
 		}
@@ -0,0 +1,266 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+)
+
+// TestFunction describes a test function, named test*, of a test file
+type TestFunction struct {
+	Name          string
+	ClassName     string
+	Line          int
+	Asserts       bool     // has an assert statement, calls an assertion method or helper, raises or skips
+	Getters       []string // accessors called by a test that calls nothing else but constructors, nil otherwise
+	DuplicateOf   string   // earlier test of the file with nearly the same code, "" if none
+	DuplicateLine int
+	Similarity    float64
+}
+
+// AssertionFreeTestRule detects tests that can only fail by raising an unexpected exception
+type AssertionFreeTestRule struct {
+	config core.Config
+}
+
+// NewAssertionFreeTestRule creates a new assertion-free test rule
+func NewAssertionFreeTestRule(config core.Config) *AssertionFreeTestRule {
+	return &AssertionFreeTestRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *AssertionFreeTestRule) ID() string {
+	return languages.AssertionFreeTestRuleID
+}
+
+// Name returns the name of this rule
+func (r *AssertionFreeTestRule) Name() string {
+	return "Assertion-Free Test"
+}
+
+// Description returns a description of this rule
+func (r *AssertionFreeTestRule) Description() string {
+	return "Detects test functions without an assert statement, an assert* call, pytest.raises or a raise"
+}
+
+// Rationale explains why this rule exists
+func (r *AssertionFreeTestRule) Rationale() string {
+	return "A test that runs the code without checking the result passes whatever the code returns. " +
+		"It adds to the coverage figure while catching nothing but exceptions, and generated test suites " +
+		"contain many of them because calling a function is easier than working out what it should return."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *AssertionFreeTestRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `def test_parse():
+    config = parse("port: 80")
+    print(config)`,
+		Good: `def test_parse():
+    config = parse("port: 80")
+    assert config.port == 80`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *AssertionFreeTestRule) Options() []core.RuleOption {
+	return core.TestQualityOptions()
+}
+
+// Category returns the category of this rule
+func (r *AssertionFreeTestRule) Category() core.RuleCategory {
+	return core.CategoryBug
+}
+
+// Severity returns the severity of violations of this rule
+func (r *AssertionFreeTestRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if a test function asserts anything. The analyzer only applies it to test files.
+func (r *AssertionFreeTestRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*TestFunction)
+	if !ok || n.Asserts {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.Line,
+		Message:    fmt.Sprintf("Test '%s' never asserts anything, so it passes whatever the code does", n.Name),
+		Suggestion: "Assert the results against the expected values, or use pytest.raises for expected errors",
+	}
+}
+
+// DuplicateTestRule detects tests that repeat an earlier test of the same file
+type DuplicateTestRule struct {
+	config core.Config
+}
+
+// NewDuplicateTestRule creates a new duplicate test rule
+func NewDuplicateTestRule(config core.Config) *DuplicateTestRule {
+	return &DuplicateTestRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *DuplicateTestRule) ID() string {
+	return languages.DuplicateTestRuleID
+}
+
+// Name returns the name of this rule
+func (r *DuplicateTestRule) Name() string {
+	return "Duplicate Test"
+}
+
+// Description returns a description of this rule
+func (r *DuplicateTestRule) Description() string {
+	return "Detects test functions that call the same functions as an earlier test with nearly the same code"
+}
+
+// Rationale explains why this rule exists
+func (r *DuplicateTestRule) Rationale() string {
+	return "Generated tests are often one test copied for every input, with only the values changed. " +
+		"Each copy has to be updated when the code changes, and a long run of them hides which cases " +
+		"are actually covered. pytest.mark.parametrize or subTest states the cases once and the checks once."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *DuplicateTestRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `def test_add_positive():
+    result = add(1, 2)
+    assert result == 3
+
+def test_add_negative():
+    result = add(-1, -2)
+    assert result == -3`,
+		Good: `@pytest.mark.parametrize("a, b, expected", [(1, 2, 3), (-1, -2, -3)])
+def test_add(a, b, expected):
+    assert add(a, b) == expected`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *DuplicateTestRule) Options() []core.RuleOption {
+	return core.DuplicateTestOptions()
+}
+
+// Category returns the category of this rule
+func (r *DuplicateTestRule) Category() core.RuleCategory {
+	return core.CategoryStyle
+}
+
+// Severity returns the severity of violations of this rule
+func (r *DuplicateTestRule) Severity() core.Severity {
+	return core.SeverityInfo
+}
+
+// Check checks if a test function nearly repeats an earlier one. The analyzer only applies it
+// to test files.
+func (r *DuplicateTestRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*TestFunction)
+	if !ok || n.DuplicateOf == "" {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.Line,
+		Message:    fmt.Sprintf("Test '%s' repeats '%s' (line %d) with %.0f%% of the same code", n.Name, n.DuplicateOf, n.DuplicateLine, n.Similarity*100),
+		Suggestion: "Merge the tests into one test parametrized with pytest.mark.parametrize or subTest",
+	}
+}
+
+// TrivialGetterTestRule detects tests that only call getters and setters
+type TrivialGetterTestRule struct {
+	config core.Config
+}
+
+// NewTrivialGetterTestRule creates a new trivial getter test rule
+func NewTrivialGetterTestRule(config core.Config) *TrivialGetterTestRule {
+	return &TrivialGetterTestRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *TrivialGetterTestRule) ID() string {
+	return languages.TrivialTestRuleID
+}
+
+// Name returns the name of this rule
+func (r *TrivialGetterTestRule) Name() string {
+	return "Trivial Getter Test"
+}
+
+// Description returns a description of this rule
+func (r *TrivialGetterTestRule) Description() string {
+	return "Detects test functions whose only calls besides constructors and assertions are get_*, is_*, has_* and set_* methods"
+}
+
+// Rationale explains why this rule exists
+func (r *TrivialGetterTestRule) Rationale() string {
+	return "Checking that a getter returns the value the constructor was given tests the language, not " +
+		"the code. Such tests are the easiest way to raise coverage and generated suites are full of " +
+		"them, but they only fail when an attribute is renamed, and the time is better spent on the logic."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *TrivialGetterTestRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `def test_user_name():
+    user = User("ada")
+    assert user.get_name() == "ada"`,
+		Good: `def test_user_rename_rejects_empty_name():
+    user = User("ada")
+    with pytest.raises(ValueError):
+        user.rename("")`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *TrivialGetterTestRule) Options() []core.RuleOption {
+	return core.TestQualityOptions()
+}
+
+// Category returns the category of this rule
+func (r *TrivialGetterTestRule) Category() core.RuleCategory {
+	return core.CategoryStyle
+}
+
+// Severity returns the severity of violations of this rule
+func (r *TrivialGetterTestRule) Severity() core.Severity {
+	return core.SeverityInfo
+}
+
+// Check checks if a test function only exercises accessors. The analyzer only applies it to
+// test files.
+func (r *TrivialGetterTestRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*TestFunction)
+	if !ok || !n.Asserts || len(n.Getters) == 0 {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.Line,
+		Message:    fmt.Sprintf("Test '%s' only exercises the accessors %s", n.Name, strings.Join(n.Getters, ", ")),
+		Suggestion: "Test the behaviour that uses these values, or drop the test if the accessors have no logic",
+	}
+}
@@ -0,0 +1,96 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+)
+
+// TypeHintCoverageRule detects modules where too few function signatures have type hints
+type TypeHintCoverageRule struct {
+	config core.Config
+}
+
+// NewTypeHintCoverageRule creates a new type hint coverage rule
+func NewTypeHintCoverageRule(config core.Config) *TypeHintCoverageRule {
+	return &TypeHintCoverageRule{
+		config: config,
+	}
+}
+
+// ID returns the unique identifier for this rule
+func (r *TypeHintCoverageRule) ID() string {
+	return "type-hint-coverage"
+}
+
+// Name returns the name of this rule
+func (r *TypeHintCoverageRule) Name() string {
+	return "Type Hint Coverage"
+}
+
+// Description returns a description of this rule
+func (r *TypeHintCoverageRule) Description() string {
+	return "Detects modules whose function signatures lack type hints"
+}
+
+// Rationale explains why this rule exists
+func (r *TypeHintCoverageRule) Rationale() string {
+	return "Type checkers and editors can only help with annotated code, and a module where some " +
+		"functions are annotated and others are not gives a false sense of safety. A signature counts " +
+		"as annotated when its parameters and return type have hints; dunder methods are left out. " +
+		"Test files, generated modules and vendored packages are skipped. The rule is off by default."
+}
+
+// Examples returns code this rule reports next to code it accepts
+func (r *TypeHintCoverageRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad:  `def load(path, strict=False):`,
+		Good: `def load(path: Path, strict: bool = False) -> Config:`,
+	}}
+}
+
+// Options returns the configuration settings this rule reads
+func (r *TypeHintCoverageRule) Options() []core.RuleOption {
+	return []core.RuleOption{
+		{Key: "rules.typeHints.enabled", Flag: "-enable-type-hints", Default: "false", Description: "Run the type hint coverage rule"},
+		{Key: "rules.typeHints.minCoverage", Flag: "-type-hint-min-coverage", Default: "0.8", Description: "Share of signatures in a file that must be fully annotated"},
+	}
+}
+
+// Category returns the category of this rule
+func (r *TypeHintCoverageRule) Category() core.RuleCategory {
+	return core.CategoryStyle
+}
+
+// Severity returns the severity of violations of this rule
+func (r *TypeHintCoverageRule) Severity() core.Severity {
+	return core.SeverityWarning
+}
+
+// Check checks if the share of fully annotated signatures in a file is below the minimum.
+// Test files are skipped, as are generated and vendored ones.
+func (r *TypeHintCoverageRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*FileMetrics)
+	if !ok || n.Generated || n.Vendored || languages.IsTestFile(n.Path) || n.TypeHints.Signatures == 0 {
+		return nil
+	}
+
+	minCoverage := config.Rules.TypeHints.MinCoverage
+	coverage := float64(n.TypeHints.Annotated) / float64(n.TypeHints.Signatures)
+	if coverage >= minCoverage {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:   r.ID(),
+		RuleName: r.Name(),
+		Category: string(r.Category()),
+		Severity: string(r.Severity()),
+		Line:     n.TypeHints.FirstUnannotated,
+		Message: fmt.Sprintf("Only %.0f%% of function signatures have complete type hints (%d of %d, min %.0f%%)",
+			coverage*100, n.TypeHints.Annotated, n.TypeHints.Signatures, minCoverage*100),
+		Suggestion: "Annotate every parameter and the return type, starting with this function",
+	}
+}
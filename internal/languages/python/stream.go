@@ -0,0 +1,100 @@
+package python
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/python/rules"
+)
+
+// analyzeStream analyzes a file above the streaming threshold in one pass, without keeping its
+// lines: the lines are counted as ParseFile counts them, the line rules run on each line as it
+// is read and comments are checked as they are found, against the code before them on their
+// line. The rules on functions, classes, docstrings and tests need the whole file and are
+// skipped. Whether the file is a script is only known at the end, so print-debug findings are
+// dropped then.
+func (a *Analyzer) analyzeStream(ctx context.Context, filePath string, config core.Config) ([]core.Result, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	parsed := a.parser.newParsedFile()
+	state := &lineParseState{}
+	masker := languages.NewMasker(languages.Python)
+	lineRules := a.enabledLineRules(filePath, config)
+	reported := make(languages.LineFindings)
+	var header []string
+	var results []core.Result
+	mainGuard := false
+
+	scanner := languages.NewLineReader(file, config.Parsing.MaxLineLength)
+	for scanner.Scan() {
+		state.lineNum++
+		line := scanner.Text()
+		parsed.TotalLines++
+		if len(header) < languages.GeneratedHeaderLines {
+			header = append(header, line)
+		}
+		mainGuard = mainGuard || mainGuardPattern.MatchString(line)
+
+		a.parser.processLine(line, state, parsed)
+		for _, comment := range parsed.Comments {
+			results = a.checkComment(ctx, results, comment, line, filePath, config)
+		}
+		parsed.Comments = parsed.Comments[:0]
+
+		code := masker.Mask(line)
+		for _, rule := range lineRules {
+			text := code
+			if languages.ReadsStrings(rule) {
+				text = line
+			}
+			if result := rule.CheckLine(text, state.lineNum); result != nil && reported.Add(result) {
+				results = append(results, lineResult(result, filePath))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse file %s: %w", filePath, err)
+	}
+	warnLongLines(filePath, scanner.LongLines(), config.Parsing.MaxLineLength)
+
+	if mainGuard || isScript(filePath, parsed) {
+		kept := results[:0]
+		for _, result := range results {
+			if result.RuleID != "print-debug" {
+				kept = append(kept, result)
+			}
+		}
+		results = kept
+	}
+
+	metrics := a.parser.CalculateFileMetrics(ctx, filePath, parsed)
+	metrics.Generated = languages.IsGenerated(header)
+	metrics.TypeHints = rules.TypeHintCoverage{} // signatures are not parsed
+	return append(a.applyFileRules(ctx, nil, metrics, filePath, config), results...), nil
+}
+
+// checkComment runs the comment rules on a comment found while streaming a file, with the code
+// before it on its line
+func (a *Analyzer) checkComment(ctx context.Context, results []core.Result, comment Comment, line, filePath string, config core.Config) []core.Result {
+	commentLine := &rules.CommentLine{Text: comment.Text, Line: comment.Line}
+	if comment.IsInline {
+		commentLine.Code = inlineCommentCode(line, comment)
+	}
+	for _, rule := range a.rules {
+		if !isRuleEnabled(rule, config) || !isCommentRule(rule) {
+			continue
+		}
+		if result := rule.Check(ctx, commentLine, config); result != nil {
+			result.FilePath = filePath
+			results = append(results, *result)
+		}
+	}
+	return results
+}
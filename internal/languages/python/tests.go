@@ -0,0 +1,239 @@
+package python
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/python/rules"
+)
+
+var (
+	assertStatementPattern = regexp.MustCompile(`(?:^|[^\w.])(?:assert|raise)\b`)
+	skipDecoratorPattern   = regexp.MustCompile(`^@[\w.]*\b(?:skip|skipIf|skipUnless|xfail)\b`)
+	callPattern            = regexp.MustCompile(`([A-Za-z_][\w.]*)\s*\(`)
+	testTokenPattern       = regexp.MustCompile(`[A-Za-z_]\w*|\d[\w.]*|\S`)
+)
+
+// testHarnessCalls are pytest and unittest calls that check, skip or fail a test
+var testHarnessCalls = map[string]bool{
+	"pytest.raises": true, "pytest.warns": true, "pytest.fail": true, "pytest.skip": true, "pytest.xfail": true,
+	"self.fail": true, "self.skipTest": true, "self.subTest": true,
+}
+
+// assertionPrefixes start the names of assertion functions and methods, such as
+// self.assertEqual, assert_called_once_with or expect_error
+var assertionPrefixes = []string{"assert", "expect", "verify", "should", "must"}
+
+// builtinCalls are the builtin functions a test uses to inspect values
+var builtinCalls = map[string]bool{
+	"len": true, "str": true, "int": true, "float": true, "bool": true, "list": true, "dict": true, "set": true,
+	"tuple": true, "frozenset": true, "bytes": true, "isinstance": true, "issubclass": true, "type": true,
+	"range": true, "sorted": true, "reversed": true, "enumerate": true, "zip": true, "any": true, "all": true,
+	"min": true, "max": true, "sum": true, "abs": true, "round": true, "repr": true, "print": true,
+	"getattr": true, "hasattr": true, "id": true, "iter": true, "next": true, "callable": true,
+}
+
+// pythonKeywords are keywords a parenthesis can follow
+var pythonKeywords = map[string]bool{
+	"if": true, "elif": true, "while": true, "for": true, "in": true, "not": true, "and": true, "or": true,
+	"return": true, "yield": true, "with": true, "assert": true, "raise": true, "lambda": true, "is": true,
+	"await": true, "del": true, "except": true, "def": true,
+}
+
+// testCalls sorts the calls of a test function
+type testCalls struct {
+	asserts  bool
+	getters  []string
+	other    bool            // calls something that is not an accessor, a constructor or part of the harness
+	subjects map[string]bool // functions and methods called outside the harness and assertions
+}
+
+// collectTestFunctions describes the test functions, named test*, of a test file. A test is a
+// near duplicate of an earlier one when both call the same functions and share threshold of
+// their code, see languages.FindDuplicateTests.
+func collectTestFunctions(parsed *ParsedFile, threshold float64) []*rules.TestFunction {
+	modules := make(map[string]bool)
+	for _, imp := range parsed.Imports {
+		if !imp.IsFrom {
+			modules[strings.Split(imp.Module, ".")[0]] = true
+		}
+	}
+
+	var tests []*rules.TestFunction
+	var bodies []languages.TestBody
+	for _, fn := range parsed.Functions {
+		if !strings.HasPrefix(fn.Name, "test") {
+			continue
+		}
+		code := testCode(parsed, fn)
+		calls := classifyTestCalls(code, modules)
+		test := &rules.TestFunction{
+			Name:      fn.Name,
+			ClassName: fn.ClassName,
+			Line:      fn.StartLine,
+			Asserts:   calls.asserts || isSkipped(parsed.Lines, fn),
+		}
+		if !calls.other {
+			test.Getters = calls.getters
+		}
+		tests = append(tests, test)
+		bodies = append(bodies, languages.TestBody{Tokens: testTokens(code), Subject: sortedKeys(calls.subjects)})
+	}
+
+	for i, duplicate := range languages.FindDuplicateTests(bodies, threshold) {
+		if duplicate.Index >= 0 {
+			tests[i].DuplicateOf = tests[duplicate.Index].Name
+			tests[i].DuplicateLine = tests[duplicate.Index].Line
+			tests[i].Similarity = duplicate.Similarity
+		}
+	}
+	return tests
+}
+
+// testCode returns the body lines of a test with strings emptied and comments and the
+// docstring removed
+func testCode(parsed *ParsedFile, fn FunctionDef) []string {
+	var code []string
+	stringDelim := ""
+	for i := fn.StartLine; i < fn.EndLine && i < len(parsed.Lines); i++ {
+		if fn.Docstring != nil && i+1 >= fn.Docstring.StartLine && i+1 <= fn.Docstring.EndLine {
+			continue
+		}
+		line := parsed.Lines[i]
+		continued := stringDelim != ""
+		_, stringDelim = scanBrackets(line, 0, stringDelim)
+		if stripped := strings.TrimSpace(stripStrings(line)); !continued && stripped != "" {
+			code = append(code, stripped)
+		}
+	}
+	return code
+}
+
+// isSkipped reports whether a test is decorated with a skip or xfail marker
+func isSkipped(lines []string, fn FunctionDef) bool {
+	for i := fn.StartLine - 2; i >= 0 && i < len(lines); i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, "@") {
+			return false
+		}
+		if skipDecoratorPattern.MatchString(trimmed) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyTestCalls sorts the calls of a test's code into assertions, accessors and other
+// calls. modules are the names of the modules the file imports, whose functions are not
+// accessors, and neither are the helper methods of a test case class.
+func classifyTestCalls(code []string, modules map[string]bool) testCalls {
+	calls := testCalls{subjects: make(map[string]bool)}
+	for _, line := range code {
+		if assertStatementPattern.MatchString(line) {
+			calls.asserts = true
+		}
+		for _, match := range callPattern.FindAllStringSubmatchIndex(line, -1) {
+			if match[0] > 0 && line[match[0]-1] == '.' {
+				continue
+			}
+			calls.add(line[match[2]:match[3]], callArgs(line, match[1]-1), modules)
+		}
+	}
+	return calls
+}
+
+// add classifies one call of a test by the dotted name it calls and its argument list
+func (c *testCalls) add(name, args string, modules map[string]bool) {
+	receiver, method := "", name
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		receiver, method = name[:dot], name[dot+1:]
+	}
+	switch {
+	case pythonKeywords[name] || builtinCalls[name]:
+		return
+	case testHarnessCalls[name] || isAssertionName(method):
+		c.asserts = true
+		return
+	case receiver == "pytest" || receiver == "mock" || strings.HasPrefix(receiver, "mock."):
+		return
+	}
+
+	c.subjects[method] = true
+	isMethod := receiver != "" && receiver != "self" && !modules[strings.Split(receiver, ".")[0]]
+	switch {
+	case unicode.IsUpper(rune(method[0])):
+	case isMethod && args == "" && languages.IsGetterName(method):
+		c.addGetter(method)
+	case isMethod && args != "" && !strings.Contains(args, ",") && languages.IsSetterName(method):
+		c.addGetter(method)
+	default:
+		c.other = true
+	}
+}
+
+// addGetter records an accessor the first time it is called
+func (c *testCalls) addGetter(name string) {
+	for _, getter := range c.getters {
+		if getter == name {
+			return
+		}
+	}
+	c.getters = append(c.getters, name)
+}
+
+// callArgs returns the trimmed argument list of the call whose parenthesis opens at open,
+// or "..." when the list continues on the next line
+func callArgs(line string, open int) string {
+	depth := 0
+	for i := open; i < len(line); i++ {
+		switch line[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return strings.TrimSpace(line[open+1 : i])
+			}
+		}
+	}
+	return "..."
+}
+
+// isAssertionName reports whether a function or method name is that of an assertion
+func isAssertionName(name string) bool {
+	lower := strings.ToLower(name)
+	for _, prefix := range assertionPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// testTokens splits the code of a test into names, numbers and symbols, with every number
+// reduced to 0
+func testTokens(code []string) []string {
+	var tokens []string
+	for _, line := range code {
+		for _, token := range testTokenPattern.FindAllString(line, -1) {
+			if token[0] >= '0' && token[0] <= '9' {
+				token = "0"
+			}
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// sortedKeys returns the keys of a set, sorted and joined with spaces
+func sortedKeys(set map[string]bool) string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, " ")
+}
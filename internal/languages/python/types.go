@@ -3,7 +3,11 @@ package python
 import (
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/python/rules"
 )
 
 // ParsedFile represents a parsed Python file
@@ -19,6 +23,14 @@ type ParsedFile struct {
 	CodeLines    int
 	CommentLines int
 	BlankLines   int
+	SyntaxError  *SyntaxError // set when brackets or strings do not balance
+	LongLines    []int        // lines truncated to the maximum line length
+}
+
+// SyntaxError describes where the structure of a file breaks down
+type SyntaxError struct {
+	Line    int
+	Message string
 }
 
 // FunctionDef represents a Python function definition
@@ -27,21 +39,26 @@ type FunctionDef struct {
 	StartLine  int
 	EndLine    int
 	Parameters []string
+	Signature  []rules.Parameter
+	Returns    string // return annotation
 	Decorators []string
 	IsMethod   bool
 	IsPrivate  bool
+	IsAsync    bool
 	ClassName  string
 	Indent     int
+	Docstring  *Docstring
 }
 
 // ClassDef represents a Python class definition
 type ClassDef struct {
 	Name       string
 	StartLine  int
-	EndLine    int
+	EndLine    int // last non-blank line of the body
 	Bases      []string
 	Decorators []string
-	Methods    []FunctionDef
+	Methods    []FunctionDef // functions defined directly in the class body
+	Indent     int
 }
 
 // ImportStmt represents a Python import statement
@@ -79,15 +96,20 @@ type VariableDef struct {
 // cachedFile represents a cached parsed file
 type cachedFile struct {
 	parsed   *ParsedFile
-	modTime  time.Time
+	modTime  time.Time // modification time of the file when it was parsed
+	cachedAt time.Time
 	filePath string
 }
 
-// Cache holds cached parsed files with time-based expiration
+// Cache holds cached parsed files with time-based expiration. It is safe for concurrent use.
 type Cache struct {
-	cache  map[string]*cachedFile
-	mu     sync.RWMutex
-	maxAge time.Duration
+	cache     map[string]*cachedFile
+	lru       *languages.LRU
+	mu        sync.RWMutex
+	maxAge    time.Duration
+	hits      int64
+	misses    int64
+	evictions int64
 }
 
 // NewCache creates a new cache with the specified max age
@@ -97,28 +119,77 @@ func NewCache(maxAge time.Duration) *Cache {
 	}
 	return &Cache{
 		cache:  make(map[string]*cachedFile),
+		lru:    languages.NewLRU(0),
 		maxAge: maxAge,
 	}
 }
 
-// Get retrieves a cached parsed file if it exists and hasn't expired
-func (c *Cache) Get(filePath string) (*ParsedFile, bool) {
+// SetMaxEntries caps the number of files cached, evicting the least recently used ones beyond
+// it; 0, the default, caches any number
+func (c *Cache) SetMaxEntries(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evict(c.lru.SetCapacity(n))
+}
+
+// Stats returns the number of files cached and counts of the lookups so far
+func (c *Cache) Stats() languages.CacheStats {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
+	return languages.CacheStats{
+		Entries:   len(c.cache),
+		Hits:      atomic.LoadInt64(&c.hits),
+		Misses:    atomic.LoadInt64(&c.misses),
+		Evictions: atomic.LoadInt64(&c.evictions),
+	}
+}
 
+// evict drops the entries the LRU has let go of; c.mu must be held
+func (c *Cache) evict(filePaths []string) {
+	for _, filePath := range filePaths {
+		delete(c.cache, filePath)
+	}
+	atomic.AddInt64(&c.evictions, int64(len(filePaths)))
+}
+
+// Get retrieves a cached parsed file unless it has expired or the file has been modified
+// since it was stored
+func (c *Cache) Get(filePath string) (*ParsedFile, bool) {
+	c.mu.RLock()
 	cached, exists := c.cache[filePath]
-	if !exists {
-		return nil, false
+	c.mu.RUnlock()
+
+	fresh := false
+	if exists && time.Since(cached.cachedAt) <= c.maxAge {
+		stat, err := os.Stat(filePath)
+		fresh = err == nil && stat.ModTime().Equal(cached.modTime)
 	}
 
-	if time.Since(cached.modTime) > c.maxAge {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !exists || c.cache[filePath] != cached {
+		atomic.AddInt64(&c.misses, 1)
+		return nil, false
+	}
+	if !fresh {
 		delete(c.cache, filePath)
+		c.lru.Remove(filePath)
+		atomic.AddInt64(&c.misses, 1)
 		return nil, false
 	}
-
+	c.lru.Touch(filePath)
+	atomic.AddInt64(&c.hits, 1)
 	return cached.parsed, true
 }
 
+// Clear removes every parsed file from the cache
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache = make(map[string]*cachedFile)
+	c.lru.Clear()
+}
+
 // Set stores a parsed file in the cache
 func (c *Cache) Set(filePath string, parsed *ParsedFile) {
 	c.mu.Lock()
@@ -132,6 +203,8 @@ func (c *Cache) Set(filePath string, parsed *ParsedFile) {
 	c.cache[filePath] = &cachedFile{
 		parsed:   parsed,
 		modTime:  stat.ModTime(),
+		cachedAt: time.Now(),
 		filePath: filePath,
 	}
+	c.evict(c.lru.Touch(filePath))
 }
@@ -6,16 +6,22 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
 	"github.com/CiaranMcAleer/AgentLint/internal/languages/reactnative/rules"
+	"github.com/CiaranMcAleer/AgentLint/internal/profiling"
 )
 
-// Analyzer implements the core.Analyzer interface for React Native (JS/TS/JSX/TSX)
+// Analyzer implements the core.Analyzer interface for React Native (JS/TS/JSX/TSX). It is
+// safe for concurrent use.
 type Analyzer struct {
-	parser     *Parser
-	rules      []core.Rule
-	lineRules  []rules.LineCheckRule
+	parser      *Parser
+	rules       []core.Rule
+	lineRules   []rules.LineCheckRule
+	sourceRules []rules.SourceCheckRule
+	typeRules   []core.Rule
 }
 
 // NewAnalyzer creates a new React Native analyzer
@@ -30,6 +36,10 @@ func NewAnalyzer(config core.Config) *Analyzer {
 		rules.NewUnusedVariableRule(config),
 		rules.NewUnreachableCodeRule(config),
 		rules.NewDeadImportRule(config),
+		rules.NewAICommentRule(config),
+		rules.NewRedundantCommentRule(config),
+		rules.NewLongBranchChainRule(config),
+		rules.NewComponentComplexityRule(config),
 	}
 
 	lineRulesList := []rules.LineCheckRule{
@@ -39,54 +49,210 @@ func NewAnalyzer(config core.Config) *Analyzer {
 		rules.NewDeprecatedLifecycleRule(config),
 		rules.NewHardcodedDimensionRule(config),
 		rules.NewDirectStateMutationRule(config),
+		rules.NewMissingKeyPropRule(config),
+		rules.NewStarExportRule(config),
+		rules.NewHardcodedEndpointRule(config),
+	}
+
+	sourceRulesList := []rules.SourceCheckRule{
+		rules.NewHookDependencyRule(config),
+		rules.NewConditionalHookRule(config),
+		rules.NewScrollViewMapRule(config),
+		rules.NewFlatListKeyExtractorRule(config),
+		rules.NewFlatListRenderHintsRule(config),
+		rules.NewFloatingPromiseRule(config),
+		rules.NewUnhandledAsyncHandlerRule(config),
+		rules.NewTestSleepRule(config),
+		rules.NewAssertionFreeTestRule(config),
+		rules.NewDuplicateTestRule(config),
+		rules.NewTrivialGetterTestRule(config),
+	}
+
+	typeRulesList := []core.Rule{
+		rules.NewAnyTypeRule(config),
+		rules.NewTSSuppressionRule(config),
 	}
 
 	return &Analyzer{
-		parser:    parser,
-		rules:     rulesList,
-		lineRules: lineRulesList,
+		parser:      parser,
+		rules:       rulesList,
+		lineRules:   lineRulesList,
+		sourceRules: sourceRulesList,
+		typeRules:   typeRulesList,
 	}
 }
 
-// Analyze analyzes a React Native file and returns results
+// Analyze analyzes a React Native file and returns results. A file with lines longer than the
+// maximum line length is minified, and only reported as skipped.
 func (a *Analyzer) Analyze(ctx context.Context, filePath string, config core.Config) ([]core.Result, error) {
+	defer profiling.TrackFile(filePath, time.Now())
+
+	config.Rules = config.Language.ReactNative.Rules.Apply(config.Rules)
+	if languages.IsTestFile(filePath) {
+		config = config.ForTestFile()
+	}
+	config = languages.ForAIFile(filePath, config)
+	if languages.ShouldStream(filePath, config) {
+		return a.analyzeStream(ctx, filePath, config)
+	}
+
 	parsed, err := a.parser.ParseFile(ctx, filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse file %s: %w", filePath, err)
 	}
+	if len(parsed.LongLines) > 0 {
+		return []core.Result{languages.MinifiedFileResult(filePath, parsed.LongLines[0], config.Parsing.MaxLineLength)}, nil
+	}
 
 	fileMetrics := a.parser.CalculateFileMetrics(ctx, filePath, parsed)
+	if config.Parsing.Tolerant && parsed.SyntaxError != nil {
+		return a.analyzePartial(ctx, parsed, fileMetrics, filePath, config), nil
+	}
 	functionMetrics := a.parser.CalculateFunctionMetrics(ctx, parsed)
 
 	results := make([]core.Result, 0, 16)
 	results = a.applyFileRules(ctx, results, fileMetrics, filePath, config)
 	results = a.applyFunctionRules(ctx, results, functionMetrics, filePath, config)
+	results = a.applyComponentRules(ctx, results, parsed, filePath, config)
+	results = a.applyCommentRules(ctx, results, parsed, filePath, config)
 	results = a.applyLineRules(ctx, results, parsed, filePath, config)
+	results = a.applySourceRules(ctx, results, parsed, filePath, config)
+	results = a.applyTypeRules(ctx, results, parsed, filePath, config)
 
 	return results, nil
 }
 
+// analyzePartial falls back to the line-based file, comment, line and type rules for a file whose
+// braces do not balance, since its function boundaries cannot be trusted
+func (a *Analyzer) analyzePartial(ctx context.Context, parsed *ParsedFile, metrics *rules.FileMetrics, filePath string, config core.Config) []core.Result {
+	config.Rules.OrphanedCode.Enabled = false
+	results := []core.Result{languages.SyntaxErrorResult(filePath, parsed.SyntaxError.Line, parsed.SyntaxError.Message)}
+	results = a.applyFileRules(ctx, results, metrics, filePath, config)
+	results = a.applyCommentRules(ctx, results, parsed, filePath, config)
+	results = a.applyLineRules(ctx, results, parsed, filePath, config)
+	results = a.applyTypeRules(ctx, results, parsed, filePath, config)
+	return results
+}
+
+// applyLineRules runs the single-line rules over every line of the file, and over its window
+// when the line's expression carries on to the following lines. Comments and the contents of
+// strings are masked first, except for rules that read strings. A rule reports a line at most
+// once. They match text without a syntax tree, so their findings have medium confidence unless
+// the rule sets one.
 func (a *Analyzer) applyLineRules(ctx context.Context, results []core.Result, parsed *ParsedFile, filePath string, config core.Config) []core.Result {
-	for lineNum, line := range parsed.Lines {
-		for _, rule := range a.lineRules {
-			if result := rule.CheckLine(line, lineNum+1); result != nil {
-				result.FilePath = filePath
-				results = append(results, *result)
+	lineRules := a.enabledLineRules(filePath, config)
+	var elapsed []time.Duration
+	if profiling.RuleProfilingEnabled() {
+		elapsed = make([]time.Duration, len(lineRules))
+	}
+
+	code := maskCode(parsed.Lines)
+	codeWindows, windows := lineWindows(code), lineWindows(parsed.Lines)
+	reported := make(languages.LineFindings)
+	for lineNum := range parsed.Lines {
+		for i, rule := range lineRules {
+			start := time.Now()
+			line, window := code[lineNum], codeWindows[lineNum]
+			if languages.ReadsStrings(rule) {
+				line, window = parsed.Lines[lineNum], windows[lineNum]
+			}
+			if result := checkWindow(rule, line, window, lineNum+1); result != nil && reported.Add(result) {
+				results = append(results, lineResult(result, filePath))
+			}
+			if elapsed != nil {
+				elapsed[i] += time.Since(start)
 			}
 		}
 	}
+
+	for i, d := range elapsed {
+		profiling.RecordRule(lineRules[i].ID(), d)
+	}
+	return results
+}
+
+// enabledLineRules returns the line rules that apply to a file. Endpoints belong in test and
+// configuration files, so the endpoint rule skips them.
+func (a *Analyzer) enabledLineRules(filePath string, config core.Config) []rules.LineCheckRule {
+	endpoints := config.Rules.Endpoints.Enabled && !languages.IsTestFile(filePath) && !languages.IsConfigFile(filePath)
+	var enabled []rules.LineCheckRule
+	for _, rule := range a.lineRules {
+		if !config.RuleDisabled(rule.ID()) && (endpoints || rule.ID() != languages.EndpointRuleID) {
+			enabled = append(enabled, rule)
+		}
+	}
+	return enabled
+}
+
+// lineResult completes a finding of a line rule
+func lineResult(result *core.Result, filePath string) core.Result {
+	result.FilePath = filePath
+	if result.Confidence == "" {
+		result.Confidence = string(core.ConfidenceMedium)
+	}
+	return *result
+}
+
+// applySourceRules runs the rules that need multi-line context over the masked file. They
+// depend on block structure, so they are skipped for files with syntax errors. Application
+// code uses timers for its own purposes, so sleep-synchronization only checks test files, as
+// do the test quality rules.
+func (a *Analyzer) applySourceRules(ctx context.Context, results []core.Result, parsed *ParsedFile, filePath string, config core.Config) []core.Result {
+	code := maskCode(parsed.Lines)
+	testFile := languages.IsTestFile(filePath)
+	for _, rule := range a.sourceRules {
+		if config.RuleDisabled(rule.ID()) {
+			continue
+		}
+		if rule.ID() == "sleep-synchronization" && (!testFile || !config.Rules.Concurrency.Enabled) {
+			continue
+		}
+		if languages.IsTestQualityRule(rule.ID()) && (!testFile || !config.Rules.TestQuality.Enabled) {
+			continue
+		}
+		start := time.Now()
+		for _, result := range rule.CheckSource(code) {
+			result.FilePath = filePath
+			results = append(results, result)
+		}
+		profiling.TrackRule(rule.ID(), start)
+	}
+	return results
+}
+
+// applyTypeRules counts the uses of 'any' and type check suppressions in TypeScript files
+func (a *Analyzer) applyTypeRules(ctx context.Context, results []core.Result, parsed *ParsedFile, filePath string, config core.Config) []core.Result {
+	ext := filepath.Ext(filePath)
+	if !config.Rules.TypeSafety.Enabled || (ext != ".ts" && ext != ".tsx") {
+		return results
+	}
+
+	source := &rules.TypeScriptSource{Lines: parsed.Lines, Code: maskCode(parsed.Lines)}
+	for _, rule := range a.typeRules {
+		if config.RuleDisabled(rule.ID()) {
+			continue
+		}
+		start := time.Now()
+		if result := rule.Check(ctx, source, config); result != nil {
+			result.FilePath = filePath
+			results = append(results, *result)
+		}
+		profiling.TrackRule(rule.ID(), start)
+	}
 	return results
 }
 
 func (a *Analyzer) applyFileRules(ctx context.Context, results []core.Result, metrics *rules.FileMetrics, filePath string, config core.Config) []core.Result {
 	for _, rule := range a.rules {
-		if !isRuleEnabled(rule, config) || isFunctionRule(rule) {
+		if !isRuleEnabled(rule, config) || isFunctionRule(rule) || isComponentRule(rule) || isCommentRule(rule) {
 			continue
 		}
+		start := time.Now()
 		if result := rule.Check(ctx, metrics, config); result != nil {
 			result.FilePath = filePath
 			results = append(results, *result)
 		}
+		profiling.TrackRule(rule.ID(), start)
 	}
 	return results
 }
@@ -96,6 +262,7 @@ func (a *Analyzer) applyFunctionRules(ctx context.Context, results []core.Result
 		if !isRuleEnabled(rule, config) || !isFunctionRule(rule) {
 			continue
 		}
+		start := time.Now()
 		for _, funcMetrics := range functionMetrics {
 			if result := rule.Check(ctx, funcMetrics, config); result != nil {
 				if result.FilePath == "" {
@@ -104,21 +271,135 @@ func (a *Analyzer) applyFunctionRules(ctx context.Context, results []core.Result
 				results = append(results, *result)
 			}
 		}
+		profiling.TrackRule(rule.ID(), start)
+	}
+	return results
+}
+
+// applyComponentRules applies component-level rules to each React component in the file
+func (a *Analyzer) applyComponentRules(ctx context.Context, results []core.Result, parsed *ParsedFile, filePath string, config core.Config) []core.Result {
+	var componentMetrics []*rules.ComponentMetrics
+	for _, rule := range a.rules {
+		if !isRuleEnabled(rule, config) || !isComponentRule(rule) {
+			continue
+		}
+		start := time.Now()
+		if componentMetrics == nil {
+			componentMetrics = a.parser.CalculateComponentMetrics(ctx, parsed)
+		}
+		for _, metrics := range componentMetrics {
+			if result := rule.Check(ctx, metrics, config); result != nil {
+				result.FilePath = filePath
+				results = append(results, *result)
+			}
+		}
+		profiling.TrackRule(rule.ID(), start)
 	}
 	return results
 }
 
+func (a *Analyzer) applyCommentRules(ctx context.Context, results []core.Result, parsed *ParsedFile, filePath string, config core.Config) []core.Result {
+	for _, rule := range a.rules {
+		if !isRuleEnabled(rule, config) || !isCommentRule(rule) {
+			continue
+		}
+		start := time.Now()
+		for _, comment := range parsed.Comments {
+			commentLine := &rules.CommentLine{Text: comment.Text, Line: comment.Line, Code: commentCode(parsed, comment)}
+			if result := rule.Check(ctx, commentLine, config); result != nil {
+				result.FilePath = filePath
+				results = append(results, *result)
+			}
+		}
+		profiling.TrackRule(rule.ID(), start)
+	}
+	return results
+}
+
+// commentCode returns the code a comment describes: the code before an inline comment, or the
+// next code line after a standalone one. Comments introducing a declaration describe intent
+// rather than code, so they return "".
+func commentCode(parsed *ParsedFile, comment Comment) string {
+	if comment.IsBlock || comment.Line < 1 || comment.Line > len(parsed.Lines) {
+		return ""
+	}
+	if comment.IsInline {
+		return inlineCommentCode(parsed.Lines[comment.Line-1], comment)
+	}
+
+	code := languages.CodeAfter(parsed.Lines, comment.Line, "//", "/*", "*")
+	if strings.HasPrefix(code, "function ") ||
+		strings.HasPrefix(code, "async function ") ||
+		strings.HasPrefix(code, "class ") ||
+		strings.HasPrefix(code, "export ") {
+		return ""
+	}
+	return code
+}
+
+// inlineCommentCode returns the code before an inline comment on its line
+func inlineCommentCode(line string, comment Comment) string {
+	if idx := strings.Index(line, comment.Text); idx > 0 {
+		return strings.TrimSpace(line[:idx])
+	}
+	return ""
+}
+
 // SupportedExtensions returns the file extensions supported by this analyzer
 func (a *Analyzer) SupportedExtensions() []string {
 	return []string{".js", ".jsx", ".ts", ".tsx"}
 }
 
+// FlushCache drops the parsed files held by the parser's cache
+func (a *Analyzer) FlushCache() {
+	a.parser.cache.Clear()
+}
+
+// CacheStats returns the hit and eviction counts of the parser's cache
+func (a *Analyzer) CacheStats() languages.CacheStats {
+	return a.parser.cache.Stats()
+}
+
 // Name returns the name of this analyzer
 func (a *Analyzer) Name() string {
 	return "reactnative"
 }
 
+// Rules returns the rules applied to each file, for agentlint explain
+func (a *Analyzer) Rules() []core.Rule {
+	all := append([]core.Rule{}, a.rules...)
+	for _, rule := range a.lineRules {
+		all = append(all, rule)
+	}
+	for _, rule := range a.sourceRules {
+		all = append(all, rule)
+	}
+	return append(all, a.typeRules...)
+}
+
+// CountFunctions returns the number of functions in a JavaScript/TypeScript file, for core.Escalate
+func (a *Analyzer) CountFunctions(ctx context.Context, filePath string) (int, error) {
+	parsed, err := a.parser.ParseFile(ctx, filePath)
+	if err != nil {
+		return 0, err
+	}
+	return len(parsed.Functions), nil
+}
+
 func isRuleEnabled(rule core.Rule, config core.Config) bool {
+	if config.RuleDisabled(rule.ID()) {
+		return false
+	}
+	if rule.ID() == "ai-comment-fingerprint" {
+		return config.Rules.AIComments.Enabled
+	}
+	if rule.ID() == "long-branch-chain" {
+		return config.Rules.Branches.Enabled
+	}
+	if isComponentRule(rule) {
+		return config.Rules.Components.Enabled
+	}
+
 	switch rule.Category() {
 	case core.CategorySize:
 		if strings.Contains(rule.ID(), "function") {
@@ -138,7 +419,16 @@ func isRuleEnabled(rule core.Rule, config core.Config) bool {
 func isFunctionRule(rule core.Rule) bool {
 	return strings.Contains(rule.ID(), "function") ||
 		strings.Contains(rule.ID(), "unused") ||
-		strings.Contains(rule.ID(), "unreachable")
+		strings.Contains(rule.ID(), "unreachable") ||
+		rule.ID() == "long-branch-chain"
+}
+
+func isComponentRule(rule core.Rule) bool {
+	return rule.ID() == "component-complexity"
+}
+
+func isCommentRule(rule core.Rule) bool {
+	return rule.ID() == "ai-comment-fingerprint" || rule.ID() == "redundant-comment"
 }
 
 // FileScanner scans directories for React Native files
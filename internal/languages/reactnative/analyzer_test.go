@@ -2,12 +2,20 @@ package reactnative
 
 import (
 	"context"
+	"encoding/binary"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode/utf16"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/reactnative/rules"
 )
 
 func getTestConfig() core.Config {
@@ -99,6 +107,9 @@ func TestAnalyzer_LargeFunctionDetection(t *testing.T) {
 	for _, result := range results {
 		if result.RuleID == "large-function" {
 			found = true
+			if result.Line != 1 || result.EndLine != 62 {
+				t.Errorf("Expected lines 1 to 62, got %d to %d", result.Line, result.EndLine)
+			}
 			break
 		}
 	}
@@ -238,3 +249,812 @@ func TestAnalyzer_ArrowFunctions(t *testing.T) {
 		t.Error("Expected to find large-function violation for arrow function")
 	}
 }
+
+func TestAnalyzer_AICommentFingerprints(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsFile := filepath.Join(tmpDir, "fingerprints.js")
+	content := `// As an AI language model, I cannot run this code
+import React from 'react';
+
+/* Note: you may need to install react-native-svg
+   before rendering icons */
+function Icon() {
+    // ✅ Step 2: render the icon
+    return null; // keeps layout stable
+}
+
+export default Icon;
+`
+	if err := os.WriteFile(jsFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := getTestConfig()
+	config.Rules.AIComments.Enabled = true
+	analyzer := NewAnalyzer(config)
+	results, err := analyzer.Analyze(context.Background(), jsFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	flagged := make(map[int]bool)
+	for _, result := range results {
+		if result.RuleID == "ai-comment-fingerprint" {
+			flagged[result.Line] = true
+		}
+	}
+
+	for _, line := range []int{1, 4, 7} {
+		if !flagged[line] {
+			t.Errorf("Expected ai-comment-fingerprint violation on line %d", line)
+		}
+	}
+	if flagged[8] {
+		t.Error("Did not expect a violation for a plain inline comment on line 8")
+	}
+}
+
+func TestAnalyzer_RedundantComments(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsFile := filepath.Join(tmpDir, "redundant.js")
+	content := `// Renders the user avatar
+function Avatar({ user }) {
+    // get user name
+    const name = user.getName();
+    let retries = 0;
+    retries++; // increment retries
+    // fall back to initials when the image fails
+    return name;
+}
+`
+	if err := os.WriteFile(jsFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := getTestConfig()
+	config.Rules.Overcommenting.MaxCommentRatio = 1
+	config.Rules.Overcommenting.CheckRedundant = true
+	analyzer := NewAnalyzer(config)
+	results, err := analyzer.Analyze(context.Background(), jsFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	flagged := make(map[int]bool)
+	for _, result := range results {
+		if result.RuleID == "redundant-comment" {
+			flagged[result.Line] = true
+		}
+	}
+
+	for _, line := range []int{3, 6} {
+		if !flagged[line] {
+			t.Errorf("Expected redundant-comment violation on line %d", line)
+		}
+	}
+	for _, line := range []int{1, 7} {
+		if flagged[line] {
+			t.Errorf("Did not expect a redundant-comment violation on line %d", line)
+		}
+	}
+}
+
+func TestAnalyzer_LogicalLineFunctionSize(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsFile := filepath.Join(tmpDir, "profile.jsx")
+	content := `function Profile({ user }) {
+    // load the avatar once
+    useEffect(() => {
+        fetchAvatar(user.id)
+            .then(setAvatar)
+            .catch(console.error);
+    }, [user.id]);
+
+    const label = user.name
+        ? user.name
+        : 'Anonymous';
+
+    return (
+        <View>
+            <Text>{label}</Text>
+        </View>
+    );
+}
+`
+	if err := os.WriteFile(jsFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := getTestConfig()
+	config.Rules.FunctionSize.MaxLines = 3
+	config.Rules.FunctionSize.Metric = core.MetricStatements
+	analyzer := NewAnalyzer(config)
+	results, err := analyzer.Analyze(context.Background(), jsFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var messages []string
+	for _, result := range results {
+		if result.RuleID == "large-function" {
+			messages = append(messages, result.Message)
+		}
+	}
+
+	expected := "Function 'Profile' is too large (4 logical lines, max 3)"
+	if len(messages) != 1 || messages[0] != expected {
+		t.Errorf("Expected %q, got %v", expected, messages)
+	}
+
+	// verbose output adds the other measurement to the suggestion
+	config.Output.Verbose = true
+	results, err = analyzer.Analyze(context.Background(), jsFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	for _, result := range results {
+		if result.RuleID == "large-function" && !strings.Contains(result.Suggestion, " lines, 4 logical lines)") {
+			t.Errorf("Expected both measurements in the verbose suggestion, got %q", result.Suggestion)
+		}
+	}
+}
+
+func TestAnalyzer_LargeMethod(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsFile := filepath.Join(tmpDir, "cart.ts")
+	lines := []string{"export class Cart {", "    checkout(items: Item[]): void {"}
+	for i := 0; i < 60; i++ {
+		lines = append(lines, "        console.log('line');")
+	}
+	lines = append(lines, "    }", "}")
+	if err := os.WriteFile(jsFile, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := getTestConfig()
+	results, err := NewAnalyzer(config).Analyze(context.Background(), jsFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var messages []string
+	for _, result := range results {
+		if result.RuleID == "large-function" {
+			messages = append(messages, result.Message)
+		}
+	}
+	expected := "Method 'Cart.checkout' is too large (61 lines, max 50)"
+	if len(messages) != 1 || messages[0] != expected {
+		t.Errorf("Expected %q, got %v", expected, messages)
+	}
+}
+
+func TestAnalyzer_ComponentComplexity(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsFile := filepath.Join(tmpDir, "Settings.jsx")
+	content := `function Settings({ user, theme }) {
+    const [a, setA] = useState(0);
+    const [b, setB] = useState(0);
+    const [c, setC] = useState(0);
+    return <View><Text>{a + b + c}</Text></View>;
+}
+
+function Badge({ label }) {
+    return <Text>{label}</Text>;
+}
+`
+	if err := os.WriteFile(jsFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := getTestConfig()
+	config.Rules.Components = core.ComponentsConfig{Enabled: true, MaxHooks: 2, MaxProps: 1, MaxJSXDepth: 1}
+	analyzer := NewAnalyzer(config)
+	results, err := analyzer.Analyze(context.Background(), jsFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var messages []string
+	for _, result := range results {
+		if result.RuleID == "component-complexity" {
+			messages = append(messages, fmt.Sprintf("%d: %s", result.Line, result.Message))
+		}
+	}
+	expected := []string{"1: Component 'Settings' is too complex (3 hooks, max 2; 2 props, max 1; JSX depth 2, max 1)"}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Expected %v, got %v", expected, messages)
+	}
+
+	config.Rules.Components.Enabled = false
+	results, err = analyzer.Analyze(context.Background(), jsFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	for _, result := range results {
+		if result.RuleID == "component-complexity" {
+			t.Errorf("Expected no findings with the rule disabled, got %q", result.Message)
+		}
+	}
+}
+
+func TestAnalyzer_MultiLineJSX(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsFile := filepath.Join(tmpDir, "Card.jsx")
+	content := `function Card({ items, onOpen }) {
+    return (
+        <View
+            style={
+                { padding: 16 }
+            }>
+            <Button
+                onPress={
+                    () => onOpen()
+                }
+            />
+            {items.map(item =>
+                <Row
+                    key={item.id}
+                    item={item} />
+            )}
+            {items.map(item =>
+                <Row item={item} />
+            )}
+        </View>
+    );
+}
+`
+	if err := os.WriteFile(jsFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := getTestConfig()
+	results, err := NewAnalyzer(config).Analyze(context.Background(), jsFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var got []string
+	for _, result := range results {
+		switch result.RuleID {
+		case "inline-style", "anonymous-function-jsx", "missing-key-prop":
+			got = append(got, fmt.Sprintf("%d %s", result.Line, result.RuleID))
+		}
+	}
+	expected := []string{"4 inline-style", "8 anonymous-function-jsx", "17 missing-key-prop"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestAnalyzer_MaskedStrings(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsFile := filepath.Join(tmpDir, "logger.js")
+	content := `const usage = "call console.log(message) to debug";
+const hint = ` + "`" + `use console.log(${name}) and style={{ color }}` + "`" + `;
+/* console.log("block") */
+const help = ` + "`" + `
+  console.log(value)
+` + "`" + `;
+const api = "https://api.example.com/v1";
+console.log(usage);
+`
+	if err := os.WriteFile(jsFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := getTestConfig()
+	config.Rules.Endpoints = core.EndpointsConfig{Enabled: true}
+	results, err := NewAnalyzer(config).Analyze(context.Background(), jsFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var got []string
+	for _, result := range results {
+		switch result.RuleID {
+		case "console-log", "inline-style", "hardcoded-endpoint":
+			got = append(got, fmt.Sprintf("%d %s", result.Line, result.RuleID))
+		}
+	}
+	expected := []string{"7 hardcoded-endpoint", "8 console-log"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestAnalyzer_OneFindingPerRuleAndLine(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsFile := filepath.Join(tmpDir, "Counter.js")
+	content := `class Counter extends React.Component {
+  reset() {
+    this.state.items.push(this.state.count = 0); console.log(1); console.log(2);
+  }
+}
+`
+	if err := os.WriteFile(jsFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := getTestConfig()
+	analyzer := NewAnalyzer(config)
+	// a rule registered twice still reports each line once
+	analyzer.lineRules = append(analyzer.lineRules, rules.NewDirectStateMutationRule(config))
+	results, err := analyzer.Analyze(context.Background(), jsFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var got []string
+	for _, result := range results {
+		switch result.RuleID {
+		case "direct-state-mutation", "console-log":
+			got = append(got, fmt.Sprintf("%d %s", result.Line, result.RuleID))
+		}
+	}
+	expected := []string{"3 console-log", "3 direct-state-mutation"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestAnalyzer_StreamLargeFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsFile := filepath.Join(tmpDir, "Card.jsx")
+	content := `function Card({ items, onOpen }) {
+    console.log(items); // log items
+    return (
+        <View
+            style={
+                { padding: 16 }
+            }>
+            <Button
+                onPress={
+                    () => onOpen()
+                }
+            />
+        </View>
+    );
+}
+`
+	if err := os.WriteFile(jsFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := getTestConfig()
+	config.Rules.FunctionSize.MaxLines = 5
+	config.Rules.FileSize.MaxLines = 10
+	config.Parsing.StreamThreshold = 1
+	results, err := NewAnalyzer(config).Analyze(context.Background(), jsFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var got []string
+	for _, result := range results {
+		got = append(got, fmt.Sprintf("%d %s", result.Line, result.RuleID))
+	}
+	// the function rules need the whole file, so large-function is not reported
+	expected := []string{"1 large-file", "2 console-log", "5 inline-style", "9 anonymous-function-jsx"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestAnalyzer_MinifiedFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsFile := filepath.Join(tmpDir, "vendor.min.js")
+	// longer than bufio.Scanner's default limit
+	content := "/*! bundle */\n" + strings.Repeat("console.log(1);", 10000) + "\n"
+	if err := os.WriteFile(jsFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	for _, threshold := range []int{0, 1} {
+		config := getTestConfig()
+		config.Parsing.MaxLineLength = 64 << 10
+		config.Parsing.StreamThreshold = threshold
+		results, err := NewAnalyzer(config).Analyze(context.Background(), jsFile, config)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		if len(results) != 1 || results[0].RuleID != languages.MinifiedFileRuleID || results[0].Line != 2 {
+			t.Errorf("Expected only a minified-file result on line 2 with stream threshold %d, got %v", threshold, results)
+		}
+	}
+}
+
+// encodings are the ways a source file may be written to disk, by name
+var encodings = map[string]func(string) []byte{
+	"utf-8":           func(s string) []byte { return []byte(s) },
+	"utf-8 with BOM":  func(s string) []byte { return append([]byte{0xEF, 0xBB, 0xBF}, s...) },
+	"utf-16le":        func(s string) []byte { return encodeUTF16(s, binary.LittleEndian, true) },
+	"utf-16be no BOM": func(s string) []byte { return encodeUTF16(s, binary.BigEndian, false) },
+}
+
+func encodeUTF16(s string, order binary.AppendByteOrder, bom bool) []byte {
+	var out []byte
+	if bom {
+		out = order.AppendUint16(out, 0xFEFF)
+	}
+	for _, unit := range utf16.Encode([]rune(s)) {
+		out = order.AppendUint16(out, unit)
+	}
+	return out
+}
+
+func TestAnalyzer_Encodings(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `// 🚀 Fetch the user profile ✨
+const api = "https://api.example.com/v1"; // 🔥 endpoint
+const label = "🎉🎉"; const url = "https://cdn.example.com/x";
+console.log(api);
+`
+	config := getTestConfig()
+	config.Rules.Endpoints = core.EndpointsConfig{Enabled: true}
+	analyzer := NewAnalyzer(config)
+
+	for name, encode := range encodings {
+		jsFile := filepath.Join(tmpDir, strings.ReplaceAll(name, " ", "-")+".js")
+		if err := os.WriteFile(jsFile, encode(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		results, err := analyzer.Analyze(context.Background(), jsFile, config)
+		if err != nil {
+			t.Fatalf("Analyze failed for %s: %v", name, err)
+		}
+
+		var got []string
+		for _, result := range results {
+			switch result.RuleID {
+			case "hardcoded-endpoint", "console-log":
+				got = append(got, fmt.Sprintf("%d:%d %s", result.Line, result.Column, result.RuleID))
+			}
+		}
+		expected := []string{"2:13 hardcoded-endpoint", "3:33 hardcoded-endpoint", "4:0 console-log"}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected %v for %s, got %v", expected, name, got)
+		}
+	}
+}
+
+func TestAnalyzer_TestFileRelaxation(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `function render() {
+    console.log('rendering');
+    return null;
+}
+`
+	for _, name := range []string{"render.js", "render.spec.js"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	config := getTestConfig()
+	config.TestFiles.DisabledRules = []string{"console-log"}
+	analyzer := NewAnalyzer(config)
+
+	countConsoleLogs := func(name string) int {
+		results, err := analyzer.Analyze(context.Background(), filepath.Join(tmpDir, name), config)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		count := 0
+		for _, result := range results {
+			if result.RuleID == "console-log" {
+				count++
+			}
+		}
+		return count
+	}
+
+	if countConsoleLogs("render.js") != 1 {
+		t.Error("Expected console-log violation in render.js")
+	}
+	if countConsoleLogs("render.spec.js") != 0 {
+		t.Error("Did not expect console-log violation in render.spec.js")
+	}
+}
+
+func TestAnalyzer_TolerantParsing(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "unfinished.js")
+	content := `function render(items) {
+    console.log('rendering');
+    if (items.length) {
+        return items.map((item) => item.name);
+`
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := getTestConfig()
+	analyzer := NewAnalyzer(config)
+
+	if _, err := analyzer.Analyze(context.Background(), filePath, config); err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	config.Parsing.Tolerant = true
+	results, err := analyzer.Analyze(context.Background(), filePath, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	found := make(map[string]int)
+	for _, result := range results {
+		found[result.RuleID] = result.Line
+	}
+	if line, ok := found["syntax-error"]; !ok || line != 3 {
+		t.Errorf("Expected syntax-error on line 3, got %v", found)
+	}
+	if _, ok := found["console-log"]; !ok {
+		t.Error("Expected line rules to still run on a file with syntax errors")
+	}
+}
+
+func TestAnalyzer_TypeSafety(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `// @ts-nocheck is not honoured here
+export function parse(input: any): Record<string, any> {
+  const label = "value: any, as any";
+  // @ts-ignore
+  const data = JSON.parse(input) as any;
+  /* @ts-expect-error legacy typings */
+  const items: any[] = data.items;
+  return { items, label } as any;
+}
+`
+	config := getTestConfig()
+	config.Rules.TypeSafety = core.TypeSafetyConfig{Enabled: true, MaxAny: 3, MaxSuppressions: 2}
+	analyzer := NewAnalyzer(config)
+
+	results := make(map[string][]core.Result)
+	for _, name := range []string{"parse.ts", "parse.js"} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		fileResults, err := analyzer.Analyze(context.Background(), path, config)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		for _, r := range fileResults {
+			if r.RuleID == "any-type" || r.RuleID == "ts-suppression" {
+				results[name] = append(results[name], r)
+			}
+		}
+	}
+
+	want := map[string]string{
+		"any-type":       "File uses 'any' 5 times (3 annotations, 2 casts, max 3)",
+		"ts-suppression": "File suppresses type checking 3 times (max 2)",
+	}
+	lines := map[string]int{"any-type": 7, "ts-suppression": 6}
+	if len(results["parse.ts"]) != len(want) {
+		t.Fatalf("Expected %d type safety results, got %v", len(want), results["parse.ts"])
+	}
+	for _, r := range results["parse.ts"] {
+		if r.Message != want[r.RuleID] || r.Line != lines[r.RuleID] {
+			t.Errorf("Unexpected result on line %d: %s", r.Line, r.Message)
+		}
+	}
+	if len(results["parse.js"]) != 0 {
+		t.Errorf("Expected no type safety results for JavaScript, got %v", results["parse.js"])
+	}
+}
+
+func TestAnalyzer_HardcodedEndpoints(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `import Config from 'react-native-config';
+
+const API_URL = 'http://localhost:3000'; // "http://10.0.2.2:3000" on Android
+const PROFILE = ` + "`https://api.example.com/users/${id}`" + `;
+const svg = <Svg xmlns="http://www.w3.org/2000/svg" />;
+/* fetch("http://localhost:4000") */
+export const client = createClient({ baseURL: Config.API_URL, socket: "192.168.1.20:9090" });
+const docs = 'https://reactnative.dev/docs/network';
+`
+	config := getTestConfig()
+	config.Rules.Endpoints = core.EndpointsConfig{Enabled: true}
+	analyzer := NewAnalyzer(config)
+
+	lines := make(map[string][]int)
+	for _, name := range []string{"api.ts", "api.test.ts", "metro.config.js"} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		results, err := analyzer.Analyze(context.Background(), path, config)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		for _, r := range results {
+			if r.RuleID == "hardcoded-endpoint" {
+				lines[name] = append(lines[name], r.Line)
+			}
+		}
+	}
+
+	if got := lines["api.ts"]; len(got) != 3 || got[0] != 3 || got[1] != 4 || got[2] != 7 {
+		t.Errorf("Expected findings on lines 3, 4 and 7, got %v", got)
+	}
+	if len(lines["api.test.ts"])+len(lines["metro.config.js"]) != 0 {
+		t.Errorf("Expected no findings in test and configuration files, got %v", lines)
+	}
+}
+
+func TestAnalyzer_TestSleep(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `it('saves the draft', async () => {
+  fireEvent.press(getByText('Save'));
+  await new Promise((resolve) => setTimeout(resolve, 500));
+  // setTimeout(done, 100) would not wait either
+  expect(getByText('Saved')).toBeTruthy();
+  await page.waitForTimeout(1000);
+  cy.wait(250);
+  cy.wait('@saveDraft');
+});
+`
+	config := getTestConfig()
+	config.Rules.Concurrency.Enabled = true
+	analyzer := NewAnalyzer(config)
+
+	lines := make(map[string][]int)
+	files := map[string]string{
+		"draft.test.tsx": content,
+		"draft.tsx":      content,
+		"timers.test.ts": "jest.useFakeTimers();\n" + content,
+	}
+	for name, body := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		results, err := analyzer.Analyze(context.Background(), path, config)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		for _, r := range results {
+			if r.RuleID == "sleep-synchronization" {
+				lines[name] = append(lines[name], r.Line)
+			}
+		}
+	}
+
+	if got := lines["draft.test.tsx"]; len(got) != 3 || got[0] != 3 || got[1] != 6 || got[2] != 7 {
+		t.Errorf("Expected findings on lines 3, 6 and 7, got %v", got)
+	}
+	if len(lines["draft.tsx"])+len(lines["timers.test.ts"]) != 0 {
+		t.Errorf("Expected no findings outside tests or with fake timers, got %v", lines)
+	}
+}
+
+func TestAnalyzer_TestQuality(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `import { User, rename } from '../user';
+
+it('creates a user', () => {
+  const user = new User('ada');
+  console.log(user);
+});
+
+it('returns the name', () => {
+  const user = new User('ada');
+  expect(user.getName()).toBe('ada');
+});
+
+test('renames to a short name', () => {
+  const user = new User('ada');
+  const result = rename(user, 'bo');
+  expect(result.ok).toBe(true);
+  expect(user.getName()).toBe('bo');
+  expect(user.history).toHaveLength(1);
+});
+
+test('renames to a long name', () => {
+  const user = new User('ada');
+  const result = rename(user, 'grace');
+  expect(result.ok).toBe(true);
+  expect(user.getName()).toBe('grace');
+  expect(user.history).toHaveLength(1);
+});
+
+it('renders the profile', () => {
+  render(<Profile user={new User('ada')} />);
+  getByText('ada');
+});
+
+it.todo('deletes a user');
+`
+	config := getTestConfig()
+	config.Rules.TestQuality = core.TestQualityConfig{Enabled: true, Threshold: 0.9}
+	analyzer := NewAnalyzer(config)
+
+	messages := make(map[string]map[int]string)
+	for _, name := range []string{"user.test.tsx", "user.tsx"} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		results, err := analyzer.Analyze(context.Background(), path, config)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		messages[name] = make(map[int]string)
+		for _, r := range results {
+			switch r.RuleID {
+			case "assertion-free-test", "duplicate-test", "trivial-getter-test":
+				messages[name][r.Line] = r.Message
+			}
+		}
+	}
+
+	want := map[int]string{
+		3:  "Test never asserts anything, so it passes whatever the code does",
+		8:  "Test only exercises the accessors getName",
+		21: "Test repeats the test on line 13 with 100% of the same code",
+	}
+	if !reflect.DeepEqual(messages["user.test.tsx"], want) {
+		t.Errorf("Expected findings %v, got %v", want, messages["user.test.tsx"])
+	}
+	if len(messages["user.tsx"]) != 0 {
+		t.Errorf("Expected no findings outside tests, got %v", messages["user.tsx"])
+	}
+}
+
+// TestAnalyzer_ConcurrentUse analyzes the same files from many goroutines through one
+// Analyzer whose cache entries expire at once, so entries are dropped while they are read;
+// run it with -race
+func TestAnalyzer_ConcurrentUse(t *testing.T) {
+	tmpDir := t.TempDir()
+	var files []string
+	for i := 0; i < 4; i++ {
+		lines := []string{"function process() {"}
+		for j := 0; j < 60; j++ {
+			lines = append(lines, fmt.Sprintf("  const value%d = %d;", j, j))
+		}
+		lines = append(lines, "  return value0;", "}", "")
+		file := filepath.Join(tmpDir, fmt.Sprintf("module%d.js", i))
+		os.WriteFile(file, []byte(strings.Join(lines, "\n")), 0644)
+		files = append(files, file)
+	}
+	config := getTestConfig()
+	analyzer := NewAnalyzer(config)
+	analyzer.parser.cache = NewCache(time.Nanosecond)
+
+	want := make([]int, len(files))
+	for i, file := range files {
+		results, err := analyzer.Analyze(context.Background(), file, config)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		if len(results) == 0 {
+			t.Fatalf("Expected findings for %s", file)
+		}
+		want[i] = len(results)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8*len(files))
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i, file := range files {
+				results, err := analyzer.Analyze(context.Background(), file, config)
+				if err != nil {
+					errs <- err
+				} else if len(results) != want[i] {
+					errs <- fmt.Errorf("%s: expected %d results, got %d", file, want[i], len(results))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
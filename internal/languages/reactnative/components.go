@@ -0,0 +1,209 @@
+package reactnative
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/reactnative/rules"
+)
+
+// hookCallPattern matches a call of a hook, a function named use followed by a capital letter
+var hookCallPattern = regexp.MustCompile(`(?:^|[^.\w$]|React\.)use[A-Z][\w$]*\s*\(`)
+
+// propsDestructurePattern matches destructuring of a component's props in its body, such as
+// `const { title, onPress } = this.props`
+var propsDestructurePattern = regexp.MustCompile(`\b(?:const|let|var)\s*\{([^{}]*)\}\s*=\s*(?:this\.)?props\b`)
+
+// jsxKeywords are the keywords after which a < starts JSX rather than a comparison
+var jsxKeywords = map[string]bool{"return": true, "yield": true, "await": true, "default": true, "case": true}
+
+// destructuredParamPattern matches a parameter list opening with an object pattern
+var destructuredParamPattern = regexp.MustCompile(`\(\s*\{`)
+
+// measureComponents fills in the end line, hook count, prop count and JSX depth of each
+// component from lines masked by maskCode. Function components end where their function does;
+// class components ended when their class body closed.
+func measureComponents(parsed *ParsedFile, masked []string) {
+	for i := range parsed.Components {
+		comp := &parsed.Components[i]
+		if !comp.IsClass {
+			for _, fn := range parsed.Functions {
+				if fn.StartLine == comp.StartLine && !fn.IsMethod {
+					comp.EndLine = fn.EndLine
+					break
+				}
+			}
+		}
+		if comp.EndLine < comp.StartLine || comp.EndLine > len(masked) {
+			continue
+		}
+
+		body := masked[comp.StartLine-1 : comp.EndLine]
+		text := strings.Join(body, "\n")
+		props := make(map[string]bool)
+		if !comp.IsClass {
+			comp.HookCount = len(hookCallPattern.FindAllString(text, -1))
+			addPropNames(props, destructuredParams(text))
+		}
+		for _, m := range propsDestructurePattern.FindAllStringSubmatch(text, -1) {
+			addPropNames(props, m[1])
+		}
+		comp.PropCount = len(props)
+		comp.JSXDepth = jsxDepth(body)
+	}
+}
+
+// CalculateComponentMetrics returns the metrics of each component of a parsed file
+func (p *Parser) CalculateComponentMetrics(ctx context.Context, parsed *ParsedFile) []*rules.ComponentMetrics {
+	metrics := make([]*rules.ComponentMetrics, 0, len(parsed.Components))
+	for _, comp := range parsed.Components {
+		metrics = append(metrics, &rules.ComponentMetrics{
+			Name:      comp.Name,
+			StartLine: comp.StartLine,
+			IsClass:   comp.IsClass,
+			HookCount: comp.HookCount,
+			PropCount: comp.PropCount,
+			JSXDepth:  comp.JSXDepth,
+		})
+	}
+	return metrics
+}
+
+// destructuredParams returns the object pattern a function component destructures its props
+// with, such as `title, onPress` for `function Button({ title, onPress }) {`, or "" when its
+// first parameter is not destructured
+func destructuredParams(text string) string {
+	m := destructuredParamPattern.FindStringIndex(text)
+	open := strings.IndexByte(text, '{')
+	if m == nil || open != m[1]-1 || strings.Contains(text[:open], "=>") {
+		return ""
+	}
+
+	depth := 0
+	for i := open; i < len(text); i++ {
+		switch text[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return text[open+1 : i]
+			}
+		}
+	}
+	return ""
+}
+
+// addPropNames adds the names bound by the top level of an object pattern to props, skipping a
+// rest element
+func addPropNames(props map[string]bool, pattern string) {
+	depth := 0
+	start := 0
+	add := func(entry string) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "...") {
+			return
+		}
+		if end := strings.IndexAny(entry, ":="); end >= 0 {
+			entry = strings.TrimSpace(entry[:end])
+		}
+		if entry != "" && isIdentStart(entry[0]) {
+			props[entry] = true
+		}
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '{', '[', '(':
+			depth++
+		case '}', ']', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				add(pattern[start:i])
+				start = i + 1
+			}
+		}
+	}
+	add(pattern[start:])
+}
+
+// wordBefore returns the identifier ending just before index i of line, skipping spaces
+func wordBefore(line string, i int) string {
+	end := i
+	for end > 0 && (line[end-1] == ' ' || line[end-1] == '\t') {
+		end--
+	}
+	start := end
+	for start > 0 && isIdentPart(line[start-1]) {
+		start--
+	}
+	return line[start:end]
+}
+
+// jsxDepth returns the deepest nesting of JSX elements in masked lines. In code, a < opens a
+// tag when it is followed by a name or > and does not follow a value, a keyword such as return
+// aside, which tells tags from comparisons and type arguments; in the children of an element,
+// outside {} expressions, every < opens a tag. Braces in attributes are skipped, so arrow functions there do not end the tag.
+func jsxDepth(lines []string) int {
+	var open []int // for each open element, the {} expressions open when it was opened
+	deepest := 0
+	expressions := 0 // {} expressions open inside elements
+	inTag, closing := false, false
+	braces := 0
+	var prev byte // last significant character outside tags
+
+	for _, line := range lines {
+		for i := 0; i < len(line); i++ {
+			c := line[i]
+			if inTag {
+				switch {
+				case c == '{':
+					braces++
+				case c == '}':
+					braces--
+				case c == '>' && braces == 0:
+					inTag = false
+					switch {
+					case closing:
+						if len(open) > 0 {
+							open = open[:len(open)-1]
+						}
+					case i > 0 && line[i-1] == '/':
+						deepest = max(deepest, len(open)+1)
+					default:
+						open = append(open, expressions)
+						deepest = max(deepest, len(open))
+					}
+					prev = '>'
+				}
+				continue
+			}
+
+			switch c {
+			case ' ', '\t':
+				continue
+			case '{':
+				if len(open) > 0 {
+					expressions++
+				}
+			case '}':
+				if expressions > 0 {
+					expressions--
+				}
+			case '<':
+				children := len(open) > 0 && open[len(open)-1] == expressions
+				value := isIdentPart(prev) && !jsxKeywords[wordBefore(line, i)] || prev == ')' || prev == ']'
+				if i+1 < len(line) && (children || !value) {
+					if next := line[i+1]; isIdentStart(next) || next == '>' || next == '/' {
+						inTag, closing, braces = true, next == '/', 0
+						continue
+					}
+				}
+			}
+			prev = c
+		}
+		prev = 0
+	}
+	return deepest
+}
@@ -0,0 +1,260 @@
+package reactnative
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// methodModifiers are the keywords that may precede the name of a class member
+const methodModifiers = `(?:(?:public|private|protected|static|async|get|set|override|abstract|readonly|declare)\s+)*`
+
+// shorthandMethodPattern matches a method header such as `async getValue(` or `static create<T>(`,
+// in a class body or an object literal
+var shorthandMethodPattern = regexp.MustCompile(`^\s*(` + methodModifiers + `)\*?\s*(#?[A-Za-z_$][\w$]*)\s*(?:<[^>]*>)?\s*\(`)
+
+// propertyMethodPattern matches a class property holding a function, such as
+// `handlePress = async (event) =>` or `onChange: Handler = function (`
+var propertyMethodPattern = regexp.MustCompile(`^\s*(?:(?:public|private|protected|static|readonly|override)\s+)*(#?[A-Za-z_$][\w$]*)\s*(?::[^=]+)?=\s*(async\s+)?(\(|[A-Za-z_$][\w$]*\s*=>|function\b)`)
+
+// objectMethodPattern matches an object literal property holding a function, such as
+// `fetchUser: async (id) =>` or `format: function (`
+var objectMethodPattern = regexp.MustCompile(`^\s*([A-Za-z_$][\w$]*)\s*:\s*(async\s+)?(\(|[A-Za-z_$][\w$]*\s*=>|function\b)`)
+
+// objectNamePattern finds the name an object literal is assigned to from the code before its brace
+var objectNamePattern = regexp.MustCompile(`([A-Za-z_$][\w$]*)\s*(?::[^=:]*)?[=:]\s*$`)
+
+// typeAliasPattern matches the start of a TypeScript type alias, whose braces hold a type rather
+// than an object
+var typeAliasPattern = regexp.MustCompile(`\btype\s+\w+`)
+
+// nonMethodNames are keywords that look like a method header when followed by parentheses
+var nonMethodNames = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true, "with": true,
+	"return": true, "function": true, "super": true, "typeof": true, "new": true, "await": true,
+	"do": true, "else": true, "try": true, "delete": true, "void": true, "yield": true,
+}
+
+// objectOpeners are the endings of code after which a brace opens an object literal rather than a block
+var objectOpeners = []string{"=", ":", "(", ",", "[", "?", "||", "&&", "??", "return", "export default"}
+
+// scope is a brace-delimited region of a file: a class body, an object literal or a block
+type scope struct {
+	kind  byte   // 'c' for a class body, 'o' for an object literal, 'b' for any other block
+	name  string // the class, or the variable or property holding the object
+	class int    // index in ParsedFile.Classes of a class body
+}
+
+// collectMethods adds the methods of class bodies and object literals to the functions of a
+// parsed file, attributed to their class or to the variable holding the object, and records
+// where each class ends. It reads lines masked by maskCode, so braces and parentheses in
+// strings and comments are ignored.
+func (p *Parser) collectMethods(parsed *ParsedFile, masked []string) {
+	var scopes []scope
+	pendingClass := -1 // class whose body brace has not been seen yet
+
+	for n, line := range masked {
+		if len(scopes) > 0 {
+			if top := scopes[len(scopes)-1]; top.kind != 'b' {
+				if fn, ok := methodAt(masked, n, top.kind); ok {
+					fn.ClassName = top.name
+					parsed.Functions = append(parsed.Functions, fn)
+				}
+			}
+		}
+
+		if p.classPattern.MatchString(line) {
+			pendingClass = classStartingAt(parsed, n+1)
+		}
+
+		for i := 0; i < len(line); i++ {
+			switch line[i] {
+			case '{':
+				s := scope{kind: 'b'}
+				before := strings.TrimSpace(line[:i])
+				if before == "" {
+					before = previousCode(masked, n)
+				}
+				switch {
+				case pendingClass >= 0:
+					s = scope{kind: 'c', name: parsed.Classes[pendingClass].Name, class: pendingClass}
+					pendingClass = -1
+				case hasAnySuffix(before, objectOpeners) && !strings.HasSuffix(before, "=>") && !typeAliasPattern.MatchString(before):
+					s.kind = 'o'
+					if m := objectNamePattern.FindStringSubmatch(before); m != nil {
+						s.name = m[1]
+					}
+				}
+				scopes = append(scopes, s)
+			case '}':
+				if len(scopes) == 0 {
+					continue
+				}
+				if top := scopes[len(scopes)-1]; top.kind == 'c' {
+					p.endClass(parsed, top.class, n+1)
+				}
+				scopes = scopes[:len(scopes)-1]
+			}
+		}
+	}
+
+	sort.SliceStable(parsed.Functions, func(i, j int) bool {
+		return parsed.Functions[i].StartLine < parsed.Functions[j].StartLine
+	})
+}
+
+// classStartingAt returns the index of the class declared on line, or -1
+func classStartingAt(parsed *ParsedFile, line int) int {
+	for i, c := range parsed.Classes {
+		if c.StartLine == line {
+			return i
+		}
+	}
+	return -1
+}
+
+// endClass records the last line of a class and of the React component it defines
+func (p *Parser) endClass(parsed *ParsedFile, class, endLine int) {
+	c := &parsed.Classes[class]
+	c.EndLine = endLine
+	for i := range parsed.Components {
+		if comp := &parsed.Components[i]; comp.IsClass && comp.StartLine == c.StartLine {
+			comp.EndLine = endLine
+		}
+	}
+}
+
+// assignMethods copies the methods of each class, with their end lines, into the class
+func assignMethods(parsed *ParsedFile) {
+	for i := range parsed.Classes {
+		c := &parsed.Classes[i]
+		for _, fn := range parsed.Functions {
+			if fn.IsMethod && fn.ClassName == c.Name && fn.StartLine > c.StartLine &&
+				(c.EndLine == 0 || fn.StartLine < c.EndLine) {
+				c.Methods = append(c.Methods, fn)
+			}
+		}
+	}
+}
+
+// methodAt returns the method whose header starts on masked line n directly inside a class body
+// ('c') or an object literal ('o')
+func methodAt(masked []string, n int, kind byte) (FunctionDef, bool) {
+	line := masked[n]
+	fn := FunctionDef{
+		StartLine: n + 1,
+		IsMethod:  true,
+		Indent:    len(line) - len(strings.TrimLeft(line, " \t")),
+	}
+
+	if m := shorthandMethodPattern.FindStringSubmatchIndex(line); m != nil {
+		fn.Name = line[m[4]:m[5]]
+		modifiers := strings.Fields(line[m[2]:m[3]])
+		if nonMethodNames[fn.Name] || kind == 'o' && !objectModifiers(modifiers) {
+			return FunctionDef{}, false
+		}
+		if !opensBody(masked, n, m[1]-1, false) {
+			return FunctionDef{}, false
+		}
+		fn.IsAsync = contains(modifiers, "async")
+		return fn, true
+	}
+
+	pattern := propertyMethodPattern
+	if kind == 'o' {
+		pattern = objectMethodPattern
+	}
+	m := pattern.FindStringSubmatchIndex(line)
+	if m == nil {
+		return FunctionDef{}, false
+	}
+	fn.Name = line[m[2]:m[3]]
+	fn.IsAsync = m[4] >= 0
+	value := line[m[6]:m[7]]
+	switch {
+	case value == "function":
+		return fn, true
+	case value == "(":
+		fn.IsArrow = true
+		return fn, opensBody(masked, n, m[6], true)
+	default:
+		fn.IsArrow = true
+		return fn, true
+	}
+}
+
+// opensBody reports whether the parameter list opening at column col of masked line n is
+// followed by a function body: a brace, possibly after a return type annotation, or an arrow
+// when arrow is set. Signatures without a body, such as overloads and abstract methods, and
+// calls are rejected.
+func opensBody(masked []string, n, col int, arrow bool) bool {
+	depth := 0
+	for ; n < len(masked); n++ {
+		line := masked[n]
+		for i := col; i < len(line); i++ {
+			switch line[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth == 0 {
+					return startsBody(strings.TrimSpace(line[i+1:]), nextCode(masked, n), arrow)
+				}
+			}
+		}
+		col = 0
+	}
+	return false
+}
+
+// startsBody reports whether the code after a parameter list, or failing that the next line of
+// code, starts a function body
+func startsBody(rest, next string, arrow bool) bool {
+	if rest == "" {
+		rest = next
+	}
+	if arrow {
+		return strings.HasPrefix(rest, "=>") || strings.HasPrefix(rest, ":") && strings.Contains(rest, "=>")
+	}
+	return strings.HasPrefix(rest, "{") || strings.HasPrefix(rest, ":") && strings.HasSuffix(rest, "{")
+}
+
+// previousCode returns the last non-blank masked line before line n, trimmed
+func previousCode(masked []string, n int) string {
+	for k := n - 1; k >= 0; k-- {
+		if trimmed := strings.TrimSpace(masked[k]); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+// nextCode returns the first non-blank masked line after line n, trimmed
+func nextCode(masked []string, n int) string {
+	for k := n + 1; k < len(masked); k++ {
+		if trimmed := strings.TrimSpace(masked[k]); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+// objectModifiers reports whether modifiers are limited to async, get and set, the only ones an
+// object literal method allows
+func objectModifiers(modifiers []string) bool {
+	for _, modifier := range modifiers {
+		if modifier != "async" && modifier != "get" && modifier != "set" {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
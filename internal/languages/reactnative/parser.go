@@ -1,13 +1,13 @@
 package reactnative
 
 import (
-	"bufio"
 	"context"
 	"os"
 	"regexp"
 	"strings"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
 	"github.com/CiaranMcAleer/AgentLint/internal/languages/reactnative/rules"
 )
 
@@ -34,9 +34,9 @@ func NewParser(config core.Config) *Parser {
 	return &Parser{
 		config:            config,
 		cache:             NewCache(0),
-		funcPattern:       regexp.MustCompile(`^(\s*)(?:async\s+)?function\s+(\w+)\s*\(`),
-		arrowFuncPattern:  regexp.MustCompile(`^(\s*)(?:const|let|var)\s+(\w+)\s*=\s*(?:async\s+)?(?:\([^)]*\)|[\w]+)\s*=>`),
-		classPattern:      regexp.MustCompile(`^(\s*)(?:export\s+)?(?:default\s+)?class\s+(\w+)(?:\s+extends\s+(\w+))?`),
+		funcPattern:       regexp.MustCompile(`^(\s*)(?:export\s+(?:default\s+)?)?(?:async\s+)?function\s+(\w+)\s*\(`),
+		arrowFuncPattern:  regexp.MustCompile(`^(\s*)(?:export\s+)?(?:const|let|var)\s+(\w+)\s*=\s*(?:async\s+)?(?:\([^)]*\)|[\w]+)\s*=>`),
+		classPattern:      regexp.MustCompile(`^(\s*)(?:export\s+)?(?:default\s+)?(?:abstract\s+)?class\s+(\w+)(?:\s+extends\s+([\w.]+))?`),
 		importPattern:     regexp.MustCompile(`^import\s+(.+)\s+from\s+['"]([^'"]+)['"]`),
 		exportPattern:     regexp.MustCompile(`^export\s+(?:(default)\s+)?(?:const|let|var|function|class)\s*(\w*)`),
 		constPattern:      regexp.MustCompile(`^(\s*)const\s+(\w+)\s*=`),
@@ -63,7 +63,7 @@ func (p *Parser) ParseFile(ctx context.Context, filePath string) (*ParsedFile, e
 	parsed := p.newParsedFile()
 	state := &parseState{}
 
-	scanner := bufio.NewScanner(file)
+	scanner := languages.NewLineReader(file, p.config.Parsing.MaxLineLength)
 	for scanner.Scan() {
 		state.lineNum++
 		line := scanner.Text()
@@ -73,7 +73,13 @@ func (p *Parser) ParseFile(ctx context.Context, filePath string) (*ParsedFile, e
 		p.processLine(line, state, parsed)
 	}
 
-	p.calculateFunctionEndLines(parsed)
+	masked := maskCode(parsed.Lines)
+	p.collectMethods(parsed, masked)
+	p.calculateFunctionEndLines(parsed, masked)
+	assignMethods(parsed)
+	measureComponents(parsed, masked)
+	parsed.SyntaxError = findSyntaxError(parsed.Lines)
+	parsed.LongLines = scanner.LongLines()
 	p.cache.Set(filePath, parsed)
 
 	return parsed, scanner.Err()
@@ -262,7 +268,7 @@ func (p *Parser) handleClass(line string, state *parseState, parsed *ParsedFile)
 	})
 
 	// Check if it's a React component
-	if extends == "Component" || extends == "PureComponent" || extends == "React.Component" {
+	if extends == "Component" || extends == "PureComponent" || extends == "React.Component" || extends == "React.PureComponent" {
 		parsed.Components = append(parsed.Components, ComponentDef{
 			Name:       className,
 			StartLine:  state.lineNum,
@@ -373,28 +379,31 @@ func (p *Parser) handleVariable(line string, state *parseState, parsed *ParsedFi
 	})
 }
 
-func (p *Parser) calculateFunctionEndLines(parsed *ParsedFile) {
+// calculateFunctionEndLines finds the brace closing each function body in lines masked by
+// maskCode. A function whose statement ends with a semicolon before any brace, such as an arrow
+// function returning an expression, ends on that line.
+func (p *Parser) calculateFunctionEndLines(parsed *ParsedFile, masked []string) {
 	for i := range parsed.Functions {
 		fn := &parsed.Functions[i]
 		braceCount := 0
 		started := false
 
-		for j := fn.StartLine - 1; j < len(parsed.Lines); j++ {
-			line := parsed.Lines[j]
+		for j := fn.StartLine - 1; j < len(masked); j++ {
+			line := masked[j]
 			braceCount += strings.Count(line, "{") - strings.Count(line, "}")
 
 			if strings.Contains(line, "{") {
 				started = true
 			}
 
-			if started && braceCount <= 0 {
+			if started && braceCount <= 0 || !started && strings.HasSuffix(strings.TrimSpace(line), ";") {
 				fn.EndLine = j + 1
 				break
 			}
 		}
 
 		if fn.EndLine == 0 {
-			fn.EndLine = len(parsed.Lines)
+			fn.EndLine = len(masked)
 		}
 	}
 }
@@ -416,11 +425,14 @@ func (p *Parser) CalculateFileMetrics(ctx context.Context, filePath string, pars
 		ImportCount:    len(parsed.Imports),
 		ClassCount:     len(parsed.Classes),
 		ComponentCount: len(parsed.Components),
+		Generated:      languages.IsGenerated(parsed.Lines),
+		Vendored:       languages.IsVendored(filePath),
 	}
 }
 
 func (p *Parser) CalculateFunctionMetrics(ctx context.Context, parsed *ParsedFile) []*rules.FunctionMetrics {
 	metrics := make([]*rules.FunctionMetrics, 0, len(parsed.Functions))
+	masked := maskCode(parsed.Lines)
 
 	for _, fn := range parsed.Functions {
 		lineCount := fn.EndLine - fn.StartLine
@@ -429,16 +441,327 @@ func (p *Parser) CalculateFunctionMetrics(ctx context.Context, parsed *ParsedFil
 		}
 
 		metrics = append(metrics, &rules.FunctionMetrics{
-			Name:       fn.Name,
-			IsMethod:   fn.IsMethod,
-			ClassName:  fn.ClassName,
-			IsAsync:    fn.IsAsync,
-			IsArrow:    fn.IsArrow,
-			IsExported: fn.IsExported,
-			LineCount:  lineCount,
-			StartLine:  fn.StartLine,
+			Name:         fn.Name,
+			IsMethod:     fn.IsMethod,
+			ClassName:    fn.ClassName,
+			IsAsync:      fn.IsAsync,
+			IsArrow:      fn.IsArrow,
+			IsExported:   fn.IsExported,
+			LineCount:    lineCount,
+			LogicalLines: countLogicalLines(parsed.Lines, fn),
+			StartLine:    fn.StartLine,
+			EndLine:      fn.EndLine,
+			BranchChain:  longestBranchChain(masked, fn),
 		})
 	}
 
 	return metrics
 }
+
+// continuationSuffixes end a line whose expression carries on to the next line
+var continuationSuffixes = []string{"=", "=>", "&&", "||", "??", "+", "?"}
+
+// continuationPrefixes start a line that carries on the expression of the previous line
+var continuationPrefixes = []string{".", "?", ":", "&&", "||", "??", "+"}
+
+// countLogicalLines counts the logical lines of a function body. Statements wrapped over
+// several physical lines inside parentheses or brackets, or continued by an operator or
+// method chain, count once; blank lines and comments are not counted. Braces open a new scope, so callback bodies are counted.
+func countLogicalLines(lines []string, fn FunctionDef) int {
+	count := 0
+	scanner := &bracketScanner{}
+	continued := false
+	inBlockComment := false
+	for i := fn.StartLine - 1; i < fn.EndLine && i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if inBlockComment {
+			inBlockComment = !strings.Contains(trimmed, "*/")
+			continue
+		}
+		if strings.HasPrefix(trimmed, "/*") {
+			inBlockComment = !strings.Contains(trimmed, "*/")
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+
+		// Lines opening with closing punctuation finish an earlier statement
+		if scanner.depth == 0 && !continued && !hasAnyPrefix(trimmed, continuationPrefixes) &&
+			!strings.ContainsAny(trimmed[:1], "})]") {
+			count++
+		}
+		scanner.scan(trimmed)
+		continued = hasAnySuffix(trimmed, continuationSuffixes)
+	}
+
+	// The function header is not part of the body
+	if count > 0 {
+		count--
+	}
+	return count
+}
+
+// openChain is a switch or if-else chain whose branches are still being counted, at the brace
+// depth of its clauses
+type openChain struct {
+	rules.BranchChain
+	depth int
+}
+
+// longestBranchChain returns the switch or if-else chain with the most branches in a function.
+// A switch counts the case and default labels of its body, a chain the if and each else if and
+// else clause after it at the same brace depth. It reads lines masked by maskCode, so keywords
+// in strings and comments are ignored.
+func longestBranchChain(masked []string, fn FunctionDef) rules.BranchChain {
+	var longest rules.BranchChain
+	var open []openChain
+	finish := func(i int) {
+		if open[i].Branches > longest.Branches {
+			longest = open[i].BranchChain
+		}
+		open = append(open[:i], open[i+1:]...)
+	}
+	find := func(kind string, depth int) int {
+		for i := len(open) - 1; i >= 0; i-- {
+			if open[i].Kind == kind && open[i].depth == depth {
+				return i
+			}
+		}
+		return -1
+	}
+
+	depth := 0
+	switchLine := 0 // line of a switch whose body has not been opened yet
+	for n := fn.StartLine - 1; n < fn.EndLine && n < len(masked); n++ {
+		line := masked[n]
+		for i := 0; i < len(line); i++ {
+			c := line[i]
+			if c == '{' {
+				depth++
+				if switchLine > 0 {
+					open = append(open, openChain{BranchChain: rules.BranchChain{Kind: "switch", Line: switchLine}, depth: depth})
+					switchLine = 0
+				}
+				continue
+			}
+			if c == '}' {
+				depth--
+				for k := len(open) - 1; k >= 0; k-- {
+					if open[k].depth > depth {
+						finish(k)
+					}
+				}
+				continue
+			}
+			if !isIdentStart(c) || i > 0 && (isIdentPart(line[i-1]) || line[i-1] == '.') {
+				continue
+			}
+
+			end := i + 1
+			for end < len(line) && isIdentPart(line[end]) {
+				end++
+			}
+			rest := strings.TrimLeft(line[end:], " \t")
+			switch line[i:end] {
+			case "switch":
+				switchLine = n + 1
+			case "case":
+				if k := find("switch", depth); k >= 0 {
+					open[k].Branches++
+				}
+			case "default":
+				if k := find("switch", depth); k >= 0 && strings.HasPrefix(rest, ":") {
+					open[k].Branches++
+				}
+			case "if":
+				if k := find("if-else chain", depth); k >= 0 {
+					finish(k)
+				}
+				open = append(open, openChain{BranchChain: rules.BranchChain{Kind: "if-else chain", Branches: 1, Line: n + 1}, depth: depth})
+			case "else":
+				if k := find("if-else chain", depth); k >= 0 {
+					open[k].Branches++
+				}
+				// the if of an else if continues the chain rather than starting one
+				if strings.HasPrefix(rest, "if") && (len(rest) == 2 || !isIdentPart(rest[2])) {
+					end = len(line) - len(rest) + 2
+				}
+			}
+			i = end - 1
+		}
+	}
+	for len(open) > 0 {
+		finish(len(open) - 1)
+	}
+	return longest
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9'
+}
+
+// findSyntaxError reports a brace left open at the end of the file or closed without being
+// opened, which leaves the brace-based function boundaries unreliable
+func findSyntaxError(lines []string) *SyntaxError {
+	checker := &braceChecker{}
+	for i, line := range lines {
+		if !checker.scan(line, i+1) {
+			return &SyntaxError{Line: i + 1, Message: "unexpected '}'"}
+		}
+	}
+	for k := len(checker.open) - 1; k >= 0; k-- {
+		if checker.open[k].kind == '{' {
+			return &SyntaxError{Line: checker.open[k].line, Message: "unclosed '{'"}
+		}
+	}
+	if len(checker.open) > 0 {
+		return &SyntaxError{Line: checker.open[0].line, Message: "unterminated template literal"}
+	}
+	return nil
+}
+
+// openToken is a brace, template literal or template substitution still open
+type openToken struct {
+	kind byte // '{', '`' or '$'
+	line int
+}
+
+// braceChecker follows braces through a file, skipping strings, comments and regular
+// expressions. Template literals and their ${} substitutions may span lines.
+type braceChecker struct {
+	open           []openToken
+	inBlockComment bool
+}
+
+// scan processes a line and reports false on a closing brace that was never opened
+func (c *braceChecker) scan(line string, lineNum int) bool {
+	var prev byte // last significant character, to tell regular expressions from division
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		if c.inBlockComment {
+			if strings.HasPrefix(line[i:], "*/") {
+				c.inBlockComment = false
+				i++
+			}
+			continue
+		}
+		if n := len(c.open); n > 0 && c.open[n-1].kind == '`' {
+			switch {
+			case ch == '\\':
+				i++
+			case ch == '`':
+				c.open = c.open[:n-1]
+			case strings.HasPrefix(line[i:], "${"):
+				c.open = append(c.open, openToken{kind: '$', line: lineNum})
+				i++
+			}
+			continue
+		}
+
+		switch ch {
+		case ' ', '\t':
+			continue
+		case '"', '\'':
+			i = languages.SkipQuoted(line, i)
+		case '`':
+			c.open = append(c.open, openToken{kind: '`', line: lineNum})
+		case '/':
+			if strings.HasPrefix(line[i:], "//") {
+				return true
+			}
+			if strings.HasPrefix(line[i:], "/*") {
+				c.inBlockComment = true
+				i++
+				continue
+			}
+			if languages.RegexCanStart(prev) {
+				i = languages.SkipRegex(line, i)
+			}
+		case '{':
+			c.open = append(c.open, openToken{kind: '{', line: lineNum})
+		case '}':
+			n := len(c.open)
+			if n == 0 {
+				return false
+			}
+			c.open = c.open[:n-1]
+		}
+		prev = ch
+	}
+	return true
+}
+
+// maskCode blanks out comments and the contents of strings, regular expressions and template
+// literals, keeping line lengths, so that multi-line rules can match the code structure alone.
+// Template substitutions stay visible as code.
+func maskCode(lines []string) []string {
+	return languages.MaskCode(lines, languages.JavaScript)
+}
+
+// bracketScanner tracks the parentheses and square brackets open in the current brace scope
+type bracketScanner struct {
+	depth  int
+	scopes []int
+}
+
+// scan updates the open bracket depth with a line, ignoring brackets in strings and comments
+func (s *bracketScanner) scan(line string) {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '"', '\'', '`':
+			quote = c
+		case '/':
+			if strings.HasPrefix(line[i:], "//") {
+				return
+			}
+		case '(', '[':
+			s.depth++
+		case ')', ']':
+			if s.depth > 0 {
+				s.depth--
+			}
+		case '{':
+			s.scopes = append(s.scopes, s.depth)
+			s.depth = 0
+		case '}':
+			if len(s.scopes) > 0 {
+				s.depth = s.scopes[len(s.scopes)-1]
+				s.scopes = s.scopes[:len(s.scopes)-1]
+			}
+		}
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
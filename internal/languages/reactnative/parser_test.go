@@ -2,6 +2,7 @@ package reactnative
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -143,6 +144,96 @@ class MyComponent extends React.Component {
 	}
 }
 
+func TestParser_ParseMethods(t *testing.T) {
+	parser := NewParser(getParserTestConfig())
+	content := `class Store extends React.Component {
+    state = { items: [] };
+
+    constructor(props) {
+        super(props);
+        const label = "render() {";
+    }
+
+    static async load(id: string): Promise<Store> {
+        if (id) {
+            fetchItem(id);
+        }
+        return new Store();
+    }
+
+    get size() {
+        return this.state.items.length;
+    }
+
+    handlePress = async (event) => {
+        this.setState({ pressed: true });
+    };
+
+    format = item => item.name;
+
+    render() {
+        return <List items={this.state.items} />;
+    }
+}
+
+abstract class Shape {
+    abstract area(): number;
+}
+
+const api = {
+    baseUrl: '/api',
+    fetchUser(id) {
+        return fetch(id);
+    },
+    save: async (user) => {
+        return post(user);
+    },
+    remove: function (id) {
+        return del(id);
+    },
+};
+`
+	parsed, err := parser.ParseFile(context.Background(), createTestFile(t, content))
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+
+	var got []string
+	for _, fn := range parsed.Functions {
+		if !fn.IsMethod {
+			t.Errorf("Expected %s on line %d to be a method", fn.Name, fn.StartLine)
+		}
+		got = append(got, fmt.Sprintf("%s.%s:%d-%d", fn.ClassName, fn.Name, fn.StartLine, fn.EndLine))
+	}
+	expected := []string{
+		"Store.constructor:4-7", "Store.load:9-14", "Store.size:16-18", "Store.handlePress:20-22",
+		"Store.format:24-24", "Store.render:26-28", "api.fetchUser:37-39", "api.save:40-42", "api.remove:43-45",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("Expected methods %v, got %v", expected, got)
+	}
+
+	if len(parsed.Classes) != 2 {
+		t.Fatalf("Expected 2 classes, got %d", len(parsed.Classes))
+	}
+	store := parsed.Classes[0]
+	if store.EndLine != 29 || len(store.Methods) != 6 {
+		t.Errorf("Expected Store to end on line 29 with 6 methods, got line %d and %d methods", store.EndLine, len(store.Methods))
+	}
+	if len(parsed.Components) != 1 || parsed.Components[0].EndLine != 29 {
+		t.Errorf("Expected the Store component to end on line 29, got %+v", parsed.Components)
+	}
+	if shape := parsed.Classes[1]; shape.EndLine != 33 || len(shape.Methods) != 0 {
+		t.Errorf("Expected Shape to end on line 33 without methods, got line %d and %d methods", shape.EndLine, len(shape.Methods))
+	}
+	if fn := parsed.Functions[1]; !fn.IsAsync || fn.IsArrow {
+		t.Errorf("Expected load to be an async method, got %+v", fn)
+	}
+	if fn := parsed.Functions[3]; !fn.IsAsync || !fn.IsArrow {
+		t.Errorf("Expected handlePress to be an async arrow function, got %+v", fn)
+	}
+}
+
 func TestParser_ParseImports(t *testing.T) {
 	config := getParserTestConfig()
 	parser := NewParser(config)
@@ -275,6 +366,54 @@ export default MyComponent;
 	}
 }
 
+func TestParser_ComponentMetrics(t *testing.T) {
+	parser := NewParser(getParserTestConfig())
+	content := `export const Profile = ({ user, onSave, style: containerStyle, ...rest }) => {
+    const [name, setName] = useState(user.name);
+    const theme = React.useContext(ThemeContext);
+    const save = useSaveProfile(onSave);
+    useEffect(() => {
+        setName(user.name);
+    }, [user.name]);
+    const short = name.length < 3;
+
+    return (
+        <View style={containerStyle}>
+            {short && <Text>Hello <Bold>{name}</Bold></Text>}
+            <Button onPress={() => save(name)} title="Save" />
+        </View>
+    );
+};
+
+class Legacy extends React.Component {
+    render() {
+        const { title, subtitle } = this.props;
+        return (
+            <>
+                <Text>{title}</Text>
+            </>
+        );
+    }
+}
+`
+	parsed, err := parser.ParseFile(context.Background(), createTestFile(t, content))
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+	if len(parsed.Components) != 2 {
+		t.Fatalf("Expected 2 components, got %+v", parsed.Components)
+	}
+
+	profile := parsed.Components[0]
+	if profile.EndLine != 16 || profile.HookCount != 4 || profile.PropCount != 3 || profile.JSXDepth != 3 {
+		t.Errorf("Expected Profile to end on line 16 with 4 hooks, 3 props and JSX depth 3, got %+v", profile)
+	}
+	legacy := parsed.Components[1]
+	if legacy.EndLine != 27 || legacy.HookCount != 0 || legacy.PropCount != 2 || legacy.JSXDepth != 2 {
+		t.Errorf("Expected Legacy to end on line 27 with 2 props and JSX depth 2, got %+v", legacy)
+	}
+}
+
 func TestParser_LineMetrics(t *testing.T) {
 	config := getParserTestConfig()
 	parser := NewParser(config)
@@ -293,3 +432,115 @@ function test() {
 		t.Errorf("Expected 6 total lines, got %d", parsed.TotalLines)
 	}
 }
+
+func TestParser_SyntaxError(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *SyntaxError
+	}{
+		{
+			name: "balanced with multi-line template and regex",
+			content: "const msg = `Hello ${\n  user.name\n} {`;\n" +
+				"const re = /[{}'\"]/g;\n" +
+				"function f() {\n  return '}';\n}\n",
+		},
+		{
+			name:    "unclosed brace",
+			content: "function f() {\n  if (x) {\n    return 1;\n  }\n",
+			want:    &SyntaxError{Line: 1, Message: "unclosed '{'"},
+		},
+		{
+			name:    "extra closing brace",
+			content: "const a = 1;\n}\n",
+			want:    &SyntaxError{Line: 2, Message: "unexpected '}'"},
+		},
+		{
+			name:    "unterminated template literal",
+			content: "const a = `open\n",
+			want:    &SyntaxError{Line: 1, Message: "unterminated template literal"},
+		},
+	}
+
+	parser := NewParser(getParserTestConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parser.ParseFile(context.Background(), createTestFile(t, tt.content))
+			if err != nil {
+				t.Fatalf("ParseFile failed: %v", err)
+			}
+			got := parsed.SyntaxError
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("Expected syntax error %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMaskCode(t *testing.T) {
+	lines := []string{
+		"const msg = `if (${user.name}) {`; // if (x) {",
+		"const re = /[{}]/g; /* useState(",
+		"still comment */ if (a) { load('}'); }",
+	}
+	want := []string{
+		"const msg = `    ${user.name}   `;            ",
+		"const re = /    /g;             ",
+		"                 if (a) { load(' '); }",
+	}
+
+	got := maskCode(lines)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Line %d: expected %q, got %q", i+1, want[i], got[i])
+		}
+	}
+}
+
+func TestParser_LongestBranchChain(t *testing.T) {
+	content := `function reducer(state, action) {
+  switch (action.type) {
+    case 'add': return add(state);
+    case 'remove': { const fallback = { default: state }; return fallback.default; }
+    case 'reset':
+      // case 'clear':
+      return "case 'empty':";
+    default:
+      return state;
+  }
+}
+
+const pick = (x) => {
+  if (x === 1) {
+    return 1;
+  } else if (x === 2) {
+    if (x > 0) { log(x); } else { warn(x); }
+    return 2;
+  }
+  else if (x === 3) return 3;
+  else return 4;
+};
+
+function twoChains(x) {
+  if (x === 1) return 1;
+  if (x === 2) return 2;
+  else return 3;
+}
+`
+	parser := NewParser(getParserTestConfig())
+	parsed, err := parser.ParseFile(context.Background(), createTestFile(t, content))
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"reducer":   "{switch 4 2}",
+		"pick":      "{if-else chain 4 14}",
+		"twoChains": "{if-else chain 2 26}",
+	}
+	for _, metrics := range parser.CalculateFunctionMetrics(context.Background(), parsed) {
+		if got := fmt.Sprint(metrics.BranchChain); got != expected[metrics.Name] {
+			t.Errorf("Expected %s for %s, got %s", expected[metrics.Name], metrics.Name, got)
+		}
+	}
+}
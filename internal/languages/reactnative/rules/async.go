@@ -0,0 +1,366 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+var (
+	asyncDeclPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\basync\s+function\s*\*?\s*([A-Za-z_$][\w$]*)`),
+		regexp.MustCompile(`([A-Za-z_$][\w$]*)\s*[:=]\s*async\b`),
+		regexp.MustCompile(`(?m)^\s*(?:static\s+)?async\s+([A-Za-z_$][\w$]*)\s*\(`),
+	}
+	statementCallPattern = regexp.MustCompile(`(?m)^[ \t]*(?:this\.)?([A-Za-z_$][\w$]*)\s*\(`)
+	// Keywords after which a call on the next line is an operand rather than a statement
+	operandKeywords = map[string]bool{
+		"return": true, "await": true, "yield": true, "void": true, "typeof": true,
+		"new": true, "in": true, "of": true, "case": true, "throw": true, "delete": true,
+	}
+)
+
+// FloatingPromiseRule detects calls to async functions whose promise is neither awaited,
+// returned, assigned nor handled with .then/.catch
+type FloatingPromiseRule struct {
+	config core.Config
+}
+
+func NewFloatingPromiseRule(config core.Config) *FloatingPromiseRule {
+	return &FloatingPromiseRule{config: config}
+}
+
+func (r *FloatingPromiseRule) ID() string                  { return "floating-promise" }
+func (r *FloatingPromiseRule) Name() string                { return "Floating Promise" }
+func (r *FloatingPromiseRule) Description() string         { return "Detects unawaited async calls" }
+func (r *FloatingPromiseRule) Category() core.RuleCategory { return core.CategoryBug }
+func (r *FloatingPromiseRule) Severity() core.Severity     { return core.SeverityWarning }
+
+func (r *FloatingPromiseRule) Rationale() string {
+	return "A promise that is neither awaited, returned, assigned nor given a .catch handler runs " +
+		"unobserved: its errors become unhandled rejections and the code after it does not wait for " +
+		"it. The rule follows calls to fetch and to functions declared async in the same file. Prefix " +
+		"a call with void when fire-and-forget is intended."
+}
+
+func (r *FloatingPromiseRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `async function saveDraft(draft) { /* ... */ }
+
+function onChange(text) {
+  saveDraft(text);
+}`,
+		Good: `function onChange(text) {
+  saveDraft(text).catch(reportError);
+}`,
+	}}
+}
+
+func (r *FloatingPromiseRule) Options() []core.RuleOption { return nil }
+
+func (r *FloatingPromiseRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// CheckSource reports statements that call fetch or a function declared async in the file
+// and discard the returned promise
+func (r *FloatingPromiseRule) CheckSource(code []string) []core.Result {
+	text := strings.Join(code, "\n")
+	known := map[string]bool{"fetch": true}
+	for _, pattern := range asyncDeclPatterns {
+		for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+			known[match[1]] = true
+		}
+	}
+
+	var results []core.Result
+	for _, loc := range statementCallPattern.FindAllStringSubmatchIndex(text, -1) {
+		name := text[loc[2]:loc[3]]
+		if !known[name] || !startsStatement(text, loc[0]) {
+			continue
+		}
+		end := closing(text, loc[1]-1)
+		if end < 0 {
+			continue
+		}
+		// A following '{' makes this a method declaration; a '.' chains .then or .catch
+		if next := strings.TrimLeft(text[end+1:], " \t\r\n"); next != "" && (next[0] == '{' || next[0] == '.') {
+			continue
+		}
+		results = append(results, core.Result{
+			RuleID:     r.ID(),
+			RuleName:   r.Name(),
+			Category:   string(r.Category()),
+			Severity:   string(r.Severity()),
+			Line:       lineOf(text, loc[2]),
+			Message:    fmt.Sprintf("Promise returned by '%s' is not awaited or handled, so its errors are silently lost", name),
+			Suggestion: "Await the call, return it, or add a .catch() handler; prefix with void if it is intentionally fire-and-forget",
+		})
+	}
+	return results
+}
+
+// startsStatement reports whether the code at pos begins a new statement rather than
+// continuing an expression from the previous line
+func startsStatement(text string, pos int) bool {
+	before := strings.TrimRight(text[:pos], " \t\r\n")
+	if before == "" {
+		return true
+	}
+	switch last := before[len(before)-1]; {
+	case strings.IndexByte(";{})]'\"`", last) >= 0:
+		return true
+	case last == '_' || last == '$' || isAlnum(last):
+		start := len(before)
+		for start > 0 && (isAlnum(before[start-1]) || before[start-1] == '_' || before[start-1] == '$') {
+			start--
+		}
+		return !operandKeywords[before[start:]]
+	}
+	return false
+}
+
+func isAlnum(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
+
+// closing returns the offset of the bracket that closes the one at open, or -1
+func closing(text string, open int) int {
+	depth := 0
+	for i := open; i < len(text); i++ {
+		switch text[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+var (
+	handlerPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\basync\s+function\s+((?:handle|on)[A-Z][\w$]*)\s*`),
+		regexp.MustCompile(`(?m)^\s*(?:static\s+)?async\s+((?:handle|on)[A-Z][\w$]*)\s*`),
+		regexp.MustCompile(`\b((?:handle|on)[A-Z][\w$]*)\s*[=:]\s*\{?\s*(?:useCallback\s*\(\s*)?async\b\s*(?:function\b[^(]*)?`),
+	}
+	// inlineHandlerPattern matches the part of a handler match between the name and async that
+	// marks a function written directly as a prop or object property value
+	inlineHandlerPattern = regexp.MustCompile(`^\s*(?:=\s*\{|:)`)
+	awaitPattern         = regexp.MustCompile(`\bawait\b`)
+	errorHandlerPattern  = regexp.MustCompile(`\btry\s*\{|\.catch\s*\(`)
+)
+
+// UnhandledAsyncHandlerRule detects async event handlers that await without catching errors.
+// Nothing awaits an event handler's promise, so a rejection inside one is never handled.
+type UnhandledAsyncHandlerRule struct {
+	config core.Config
+}
+
+func NewUnhandledAsyncHandlerRule(config core.Config) *UnhandledAsyncHandlerRule {
+	return &UnhandledAsyncHandlerRule{config: config}
+}
+
+func (r *UnhandledAsyncHandlerRule) ID() string   { return "unhandled-async-handler" }
+func (r *UnhandledAsyncHandlerRule) Name() string { return "Unhandled Async Handler" }
+func (r *UnhandledAsyncHandlerRule) Description() string {
+	return "Detects async event handlers without try/catch"
+}
+func (r *UnhandledAsyncHandlerRule) Category() core.RuleCategory { return core.CategoryBug }
+func (r *UnhandledAsyncHandlerRule) Severity() core.Severity     { return core.SeverityWarning }
+
+func (r *UnhandledAsyncHandlerRule) Rationale() string {
+	return "Nothing awaits the promise of an event handler, so when an async handler passed to a prop " +
+		"or listener awaits something that fails, the rejection goes unhandled: the user sees nothing " +
+		"and the error is lost. Handlers that await inside a try block or attach .catch() are accepted."
+}
+
+func (r *UnhandledAsyncHandlerRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `const handleSubmit = async () => {
+  await api.post('/orders', order);
+  navigation.goBack();
+};`,
+		Good: `const handleSubmit = async () => {
+  try {
+    await api.post('/orders', order);
+    navigation.goBack();
+  } catch (error) {
+    showError(error);
+  }
+};`,
+	}}
+}
+
+func (r *UnhandledAsyncHandlerRule) Options() []core.RuleOption { return nil }
+
+func (r *UnhandledAsyncHandlerRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// CheckSource reports async functions written inline as onX props, and async functions named
+// handleX or onX that are passed to a prop or listener, whose body awaits without a try block
+// or .catch()
+func (r *UnhandledAsyncHandlerRule) CheckSource(code []string) []core.Result {
+	text := strings.Join(code, "\n")
+	var results []core.Result
+	reported := make(map[int]bool)
+	for _, pattern := range handlerPatterns {
+		for _, loc := range pattern.FindAllStringSubmatchIndex(text, -1) {
+			name := text[loc[2]:loc[3]]
+			if reported[loc[2]] || (!inlineHandlerPattern.MatchString(text[loc[3]:loc[1]]) && !isEventHandler(text, name)) {
+				continue
+			}
+			body := functionBody(text, loc[1])
+			if !awaitPattern.MatchString(body) || errorHandlerPattern.MatchString(body) {
+				continue
+			}
+			reported[loc[2]] = true
+			results = append(results, core.Result{
+				RuleID:     r.ID(),
+				RuleName:   r.Name(),
+				Category:   string(r.Category()),
+				Severity:   string(r.Severity()),
+				Line:       lineOf(text, loc[2]),
+				Message:    fmt.Sprintf("Async event handler '%s' awaits without try/catch, so a rejection goes unhandled", name),
+				Suggestion: "Wrap the awaits in try/catch and report the error to the user or a logger",
+			})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Line < results[j].Line })
+	return results
+}
+
+// isEventHandler reports whether a named function is passed to a JSX prop or registered as
+// an event listener, rather than merely named like a handler
+func isEventHandler(text, name string) bool {
+	ref := `(?:this\.)?` + regexp.QuoteMeta(name)
+	return regexp.MustCompile(`=\s*\{\s*` + ref + `\s*\}|(?:addEventListener|\.on|\.once|\.addListener)\s*\([^,()]*,\s*` + ref + `\b`).MatchString(text)
+}
+
+// functionBody returns the body of the function whose parameters start at pos: a brace block,
+// or the expression of an arrow function
+func functionBody(text string, pos int) string {
+	pos += len(text[pos:]) - len(strings.TrimLeft(text[pos:], " \t\r\n"))
+	if pos < len(text) && text[pos] == '(' {
+		end := closing(text, pos)
+		if end < 0 {
+			return ""
+		}
+		pos = end + 1
+	} else if loc := identPattern.FindStringIndex(text[pos:]); loc != nil && loc[0] == 0 {
+		pos += loc[1]
+	}
+
+	rest := strings.TrimLeft(text[pos:], " \t\r\n")
+	rest = strings.TrimLeft(strings.TrimPrefix(rest, "=>"), " \t\r\n")
+	if strings.HasPrefix(rest, "{") {
+		if end := closing(rest, 0); end >= 0 {
+			return rest[:end+1]
+		}
+		return rest
+	}
+
+	depth := 0
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth == 0 {
+				return rest[:i]
+			}
+			depth--
+		case ',', ';', '\n':
+			if depth == 0 {
+				return rest[:i]
+			}
+		}
+	}
+	return rest
+}
+
+var (
+	testSleepPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\bsetTimeout\s*\(`),
+		regexp.MustCompile(`\.waitForTimeout\s*\(`),
+		regexp.MustCompile(`\bcy\.wait\s*\(\s*\d`),
+	}
+	fakeTimersPattern = regexp.MustCompile(`\buseFakeTimers\s*\(`)
+)
+
+// TestSleepRule detects tests that wait a fixed time for something to happen. The analyzer
+// applies it to test files only.
+type TestSleepRule struct {
+	config core.Config
+}
+
+func NewTestSleepRule(config core.Config) *TestSleepRule {
+	return &TestSleepRule{config: config}
+}
+
+func (r *TestSleepRule) ID() string   { return "sleep-synchronization" }
+func (r *TestSleepRule) Name() string { return "Sleep Synchronization" }
+func (r *TestSleepRule) Description() string {
+	return "Detects tests that wait with setTimeout, waitForTimeout or cy.wait(ms)"
+}
+func (r *TestSleepRule) Category() core.RuleCategory { return core.CategoryBug }
+func (r *TestSleepRule) Severity() core.Severity     { return core.SeverityWarning }
+
+func (r *TestSleepRule) Rationale() string {
+	return "A test that waits a fixed time for a render, a request or a state update to have probably " +
+		"happened is slow when the wait is long and flaky when it is not, and the wait hides what the " +
+		"test depends on. Waiting for the result itself, with waitFor, findBy queries or fake timers, " +
+		"returns as soon as it is there. Files that use fake timers are skipped."
+}
+
+func (r *TestSleepRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `fireEvent.press(getByText('Save'));
+await new Promise((resolve) => setTimeout(resolve, 500));
+expect(getByText('Saved')).toBeTruthy();`,
+		Good: `fireEvent.press(getByText('Save'));
+expect(await findByText('Saved')).toBeTruthy();`,
+	}}
+}
+
+func (r *TestSleepRule) Options() []core.RuleOption { return core.ConcurrencyOptions() }
+
+func (r *TestSleepRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// CheckSource reports each line of a test that waits with setTimeout, Playwright's
+// waitForTimeout or Cypress's cy.wait with a number, unless the file uses fake timers
+func (r *TestSleepRule) CheckSource(code []string) []core.Result {
+	if fakeTimersPattern.MatchString(strings.Join(code, "\n")) {
+		return nil
+	}
+
+	var results []core.Result
+	for i, line := range code {
+		for _, pattern := range testSleepPatterns {
+			if !pattern.MatchString(line) {
+				continue
+			}
+			results = append(results, core.Result{
+				RuleID:     r.ID(),
+				RuleName:   r.Name(),
+				Category:   string(r.Category()),
+				Severity:   string(r.Severity()),
+				Line:       i + 1,
+				Message:    "Test waits a fixed time instead of waiting for the result, which makes it slow or flaky",
+				Suggestion: "Wait for the expected state with waitFor or a findBy query, or use fake timers",
+			})
+			break
+		}
+	}
+	return results
+}
@@ -0,0 +1,86 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+const defaultMaxBranches = 10
+
+// BranchChain is the switch or if-else chain with the most branches in a function
+type BranchChain struct {
+	Kind     string // "switch" or "if-else chain"
+	Branches int    // case and default labels, or if, else if and else clauses
+	Line     int
+}
+
+// LongBranchChainRule detects switch statements and if-else chains with too many branches
+type LongBranchChainRule struct {
+	config core.Config
+}
+
+func NewLongBranchChainRule(config core.Config) *LongBranchChainRule {
+	return &LongBranchChainRule{config: config}
+}
+
+func (r *LongBranchChainRule) ID() string   { return "long-branch-chain" }
+func (r *LongBranchChainRule) Name() string { return "Long Branch Chain" }
+func (r *LongBranchChainRule) Description() string {
+	return "Detects switch statements and if-else chains with too many branches"
+}
+func (r *LongBranchChainRule) Category() core.RuleCategory { return core.CategoryComplexity }
+func (r *LongBranchChainRule) Severity() core.Severity     { return core.SeverityWarning }
+
+func (r *LongBranchChainRule) Rationale() string {
+	return "A switch or if-else chain that grows a branch for every new kind of input has to be found " +
+		"and edited each time a kind is added, and the branches tend to drift apart. An object or Map " +
+		"from key to value or handler, or a class per kind, keeps each case in one place."
+}
+
+func (r *LongBranchChainRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `switch (action.type) {
+  case 'add': return add(state, action);
+  case 'remove': return remove(state, action);
+  // ... a case for every action
+}`,
+		Good: `const handlers = { add, remove };
+
+return handlers[action.type]?.(state, action) ?? state;`,
+	}}
+}
+
+func (r *LongBranchChainRule) Options() []core.RuleOption {
+	return core.BranchesOptions()
+}
+
+func (r *LongBranchChainRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	maxBranches := config.Rules.Branches.MaxBranches
+	if maxBranches <= 0 {
+		maxBranches = defaultMaxBranches
+	}
+
+	n, ok := node.(*FunctionMetrics)
+	if !ok || n.BranchChain.Branches <= maxBranches {
+		return nil
+	}
+
+	funcType := "Function"
+	if n.IsArrow {
+		funcType = "Arrow function"
+	}
+	if n.IsMethod {
+		funcType = "Method"
+	}
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.BranchChain.Line,
+		Message:    fmt.Sprintf("%s '%s' has a long %s (%d branches, max %d)", funcType, n.QualifiedName(), n.BranchChain.Kind, n.BranchChain.Branches, maxBranches),
+		Suggestion: fmt.Sprintf("Consider replacing the %s in '%s' with an object or Map of values or handlers, or a class per case", n.BranchChain.Kind, n.QualifiedName()),
+	}
+}
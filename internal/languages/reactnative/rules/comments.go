@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
 )
 
 // OvercommentingRule detects overcommented code
@@ -22,6 +23,25 @@ func (r *OvercommentingRule) Description() string { return "Detects code with ex
 func (r *OvercommentingRule) Category() core.RuleCategory { return core.CategoryComments }
 func (r *OvercommentingRule) Severity() core.Severity     { return core.SeverityInfo }
 
+func (r *OvercommentingRule) Rationale() string {
+	return "When most lines of a file are comments, the comments narrate the code instead of explaining " +
+		"it, and they drift out of date as the code changes. Generated code often comments every line. " +
+		"The rule compares the comment lines of a file with its code lines."
+}
+
+func (r *OvercommentingRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `// Filter the users
+const active = users.filter(
+  // Keep only active users
+  user => user.active,
+);`,
+		Good: `const active = users.filter(user => user.active);`,
+	}}
+}
+
+func (r *OvercommentingRule) Options() []core.RuleOption { return core.OvercommentingOptions() }
+
 func (r *OvercommentingRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	maxRatio := config.Rules.Overcommenting.MaxCommentRatio
 
@@ -41,3 +61,131 @@ func (r *OvercommentingRule) Check(ctx context.Context, node interface{}, config
 	}
 	return nil
 }
+
+// CommentLine represents a single comment line with its position
+type CommentLine struct {
+	Text string
+	Line int
+	Code string // the code the comment describes, empty when it documents a declaration
+}
+
+// AICommentRule detects comments carrying LLM chat boilerplate fingerprints
+type AICommentRule struct {
+	config core.Config
+}
+
+func NewAICommentRule(config core.Config) *AICommentRule {
+	return &AICommentRule{config: config}
+}
+
+func (r *AICommentRule) ID() string                  { return "ai-comment-fingerprint" }
+func (r *AICommentRule) Name() string                { return "AI Comment Fingerprint" }
+func (r *AICommentRule) Description() string         { return "Detects comments containing chat-assistant boilerplate left behind by LLMs" }
+func (r *AICommentRule) Category() core.RuleCategory { return core.CategoryComments }
+func (r *AICommentRule) Severity() core.Severity     { return core.SeverityWarning }
+
+func (r *AICommentRule) Rationale() string {
+	return "Code pasted from a chat assistant often keeps the assistant's side of the conversation: " +
+		"\"Here's the updated code\", \"In a real application you would...\", or comments that open with " +
+		"\"This function\" and narrate the obvious. They describe the conversation rather than the code " +
+		"and show that the change was not reviewed."
+}
+
+func (r *AICommentRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `// Replace with your actual API endpoint
+const API_URL = 'https://example.com/api';`,
+		Good: `const API_URL = Config.API_URL;`,
+	}}
+}
+
+func (r *AICommentRule) Options() []core.RuleOption { return core.AICommentOptions() }
+
+func (r *AICommentRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*CommentLine)
+	if !ok {
+		return nil
+	}
+
+	match := languages.MatchAIFingerprint(n.Text, config.Rules.AIComments.Phrases)
+	if match == "" {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.Line,
+		Message:    fmt.Sprintf("Comment looks like LLM boilerplate (%s): %q", match, n.Text),
+		Suggestion: "Remove the comment or rewrite it to explain intent specific to this code",
+	}
+}
+
+// defaultRedundantThreshold is the comment/code word overlap at which a comment is redundant
+const defaultRedundantThreshold = 0.6
+
+// RedundantCommentRule detects comments that restate the code they describe
+type RedundantCommentRule struct {
+	config core.Config
+}
+
+func NewRedundantCommentRule(config core.Config) *RedundantCommentRule {
+	return &RedundantCommentRule{config: config}
+}
+
+func (r *RedundantCommentRule) ID() string                  { return "redundant-comment" }
+func (r *RedundantCommentRule) Name() string                { return "Redundant Comment" }
+func (r *RedundantCommentRule) Description() string         { return "Detects comments that simply restate what the code does" }
+func (r *RedundantCommentRule) Category() core.RuleCategory { return core.CategoryComments }
+func (r *RedundantCommentRule) Severity() core.Severity     { return core.SeverityInfo }
+
+func (r *RedundantCommentRule) Rationale() string {
+	return "A comment that repeats the statement it sits on tells the reader nothing the code does not, " +
+		"and it is one more thing to keep in sync. The rule compares the words of each comment with the " +
+		"identifiers of the code it describes and reports comments whose words mostly appear there."
+}
+
+func (r *RedundantCommentRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `// set loading to true
+setLoading(true);`,
+		Good: `// keep the spinner up until both requests have settled
+setLoading(true);`,
+	}}
+}
+
+func (r *RedundantCommentRule) Options() []core.RuleOption { return core.RedundantCommentOptions() }
+
+func (r *RedundantCommentRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	if !config.Rules.Overcommenting.CheckRedundant {
+		return nil
+	}
+
+	threshold := config.Rules.Overcommenting.RedundantThreshold
+	if threshold <= 0 {
+		threshold = defaultRedundantThreshold
+	}
+
+	n, ok := node.(*CommentLine)
+	if !ok || n.Code == "" {
+		return nil
+	}
+
+	overlap := languages.CommentRedundancy(n.Text, n.Code)
+	if overlap < threshold {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Confidence: string(core.ConfidenceLow),
+		Line:       n.Line,
+		Message:    fmt.Sprintf("Comment restates the code it describes (%.0f%% word overlap): %q", overlap*100, n.Text),
+		Suggestion: "Consider removing this redundant comment or explaining why the code does this instead",
+	}
+}
@@ -0,0 +1,99 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// ComponentMetrics contains metrics about a React component
+type ComponentMetrics struct {
+	Name      string
+	StartLine int
+	IsClass   bool
+	HookCount int // hook calls, including custom hooks
+	PropCount int // distinct props destructured from the parameters, props or this.props
+	JSXDepth  int // deepest nesting of the JSX elements it renders
+}
+
+// ComponentComplexityRule detects components with too many hooks or props, or deeply nested JSX
+type ComponentComplexityRule struct {
+	config core.Config
+}
+
+func NewComponentComplexityRule(config core.Config) *ComponentComplexityRule {
+	return &ComponentComplexityRule{config: config}
+}
+
+func (r *ComponentComplexityRule) ID() string   { return "component-complexity" }
+func (r *ComponentComplexityRule) Name() string { return "Component Complexity" }
+func (r *ComponentComplexityRule) Description() string {
+	return "Detects components with too many hooks or props, or deeply nested JSX"
+}
+func (r *ComponentComplexityRule) Category() core.RuleCategory { return core.CategoryComplexity }
+func (r *ComponentComplexityRule) Severity() core.Severity     { return core.SeverityWarning }
+
+func (r *ComponentComplexityRule) Rationale() string {
+	return "A component with a dozen hooks, a long list of props and deeply nested JSX holds the state, " +
+		"data loading and layout of several screens' worth of UI, so every change re-renders and " +
+		"re-tests all of it. Generated code tends to keep adding state and markup to the component it " +
+		"is editing rather than extracting custom hooks and child components."
+}
+
+func (r *ComponentComplexityRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `function Checkout({ cart, user, address, payment, coupon, onPay, onBack, theme }) {
+  const [step, setStep] = useState(0);
+  const [error, setError] = useState(null);
+  // ... ten more hooks and JSX nested ten levels deep
+}`,
+		Good: `function Checkout({ cart, user, onPay }) {
+  const payment = usePayment(cart, onPay);
+  return <CheckoutSteps cart={cart} user={user} payment={payment} />;
+}`,
+	}}
+}
+
+func (r *ComponentComplexityRule) Options() []core.RuleOption {
+	return []core.RuleOption{
+		{Key: "rules.components.enabled", Flag: "-enable-components", Default: "true", Description: "Report components that are too complex"},
+		{Key: "rules.components.maxHooks", Flag: "-component-max-hooks", Default: "10", Description: "Most hook calls in one function component"},
+		{Key: "rules.components.maxProps", Flag: "-component-max-props", Default: "10", Description: "Most props one component may destructure"},
+		{Key: "rules.components.maxJSXDepth", Flag: "-component-max-jsx-depth", Default: "8", Description: "Deepest nesting of the JSX elements one component renders"},
+	}
+}
+
+// Check checks if a component violates this rule. A limit of zero or less is not checked.
+func (r *ComponentComplexityRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*ComponentMetrics)
+	if !ok {
+		return nil
+	}
+
+	limits := config.Rules.Components
+	var exceeded []string
+	if limits.MaxHooks > 0 && n.HookCount > limits.MaxHooks {
+		exceeded = append(exceeded, fmt.Sprintf("%d hooks, max %d", n.HookCount, limits.MaxHooks))
+	}
+	if limits.MaxProps > 0 && n.PropCount > limits.MaxProps {
+		exceeded = append(exceeded, fmt.Sprintf("%d props, max %d", n.PropCount, limits.MaxProps))
+	}
+	if limits.MaxJSXDepth > 0 && n.JSXDepth > limits.MaxJSXDepth {
+		exceeded = append(exceeded, fmt.Sprintf("JSX depth %d, max %d", n.JSXDepth, limits.MaxJSXDepth))
+	}
+	if len(exceeded) == 0 {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       n.StartLine,
+		Message:    fmt.Sprintf("Component '%s' is too complex (%s)", n.Name, strings.Join(exceeded, "; ")),
+		Suggestion: fmt.Sprintf("Consider moving state and effects of '%s' into custom hooks and splitting its JSX into child components", n.Name),
+	}
+}
@@ -0,0 +1,77 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+)
+
+// HardcodedEndpointRule detects URLs, IP addresses and ports written into string literals
+type HardcodedEndpointRule struct {
+	config core.Config
+}
+
+func NewHardcodedEndpointRule(config core.Config) *HardcodedEndpointRule {
+	return &HardcodedEndpointRule{config: config}
+}
+
+func (r *HardcodedEndpointRule) ID() string                  { return languages.EndpointRuleID }
+func (r *HardcodedEndpointRule) Name() string                { return "Hardcoded Endpoint" }
+func (r *HardcodedEndpointRule) Category() core.RuleCategory { return core.CategoryBug }
+func (r *HardcodedEndpointRule) Severity() core.Severity     { return core.SeverityWarning }
+
+func (r *HardcodedEndpointRule) Description() string {
+	return "Detects http:// URLs, example hosts, IP addresses and ports hardcoded outside configuration and test files"
+}
+
+func (r *HardcodedEndpointRule) Rationale() string {
+	return "Generated code fills in the endpoints it needs with whatever the example used: " +
+		"localhost:3000, api.example.com or 10.0.2.2. They work in the simulator and ship in " +
+		"the release build, where the app cannot be pointed at another server without a new release."
+}
+
+func (r *HardcodedEndpointRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad:  "const res = await fetch('http://localhost:3000/api/users');",
+		Good: "const res = await fetch(`${Config.API_URL}/api/users`);",
+	}}
+}
+
+func (r *HardcodedEndpointRule) Options() []core.RuleOption { return core.EndpointOptions() }
+
+func (r *HardcodedEndpointRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// ReadsStrings reports that the rule is given lines with their string literals unmasked
+func (r *HardcodedEndpointRule) ReadsStrings() bool { return true }
+
+// CheckLine checks the string literals of a line for a hardcoded endpoint. The analyzer skips
+// test and configuration files.
+func (r *HardcodedEndpointRule) CheckLine(line string, lineNum int) *core.Result {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "*") || strings.HasPrefix(trimmed, "/*") {
+		return nil
+	}
+
+	for _, str := range languages.QuotedStrings(line, "\"'`", "//") {
+		endpoint, found := languages.FindEndpoint(str.Text, r.config.Rules.Endpoints.Allow)
+		if !found {
+			continue
+		}
+		return &core.Result{
+			RuleID:     r.ID(),
+			RuleName:   r.Name(),
+			Category:   string(r.Category()),
+			Severity:   string(r.Severity()),
+			Line:       lineNum,
+			Column:     str.Column,
+			Message:    fmt.Sprintf("Hardcoded %s %q", endpoint.Kind, endpoint.Value),
+			Suggestion: "Read the endpoint from app configuration such as react-native-config, or add it to rules.endpoints.allow",
+		}
+	}
+	return nil
+}
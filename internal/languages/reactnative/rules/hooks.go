@@ -0,0 +1,498 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// SourceCheckRule interface for rules that need several lines of context. The lines are
+// masked: comments and the contents of strings and regular expressions are blanked out.
+type SourceCheckRule interface {
+	core.Rule
+	CheckSource(code []string) []core.Result
+}
+
+var (
+	identPattern        = regexp.MustCompile(`[A-Za-z_$][\w$]*`)
+	declarationPattern  = regexp.MustCompile(`\b(?:const|let|var)\s+([A-Za-z_$][\w$]*)`)
+	destructurePattern  = regexp.MustCompile(`\b(?:const|let|var)\s*([\[{][^\]}=]*[\]}])\s*=\s*(?:React\.)?(\w*)`)
+	arrowParamsPattern  = regexp.MustCompile(`(?:\(([^()]*)\)|([A-Za-z_$][\w$]*))\s*=>`)
+	functionArgsPattern = regexp.MustCompile(`\bfunction\b[^(]*\(([^)]*)\)`)
+	componentPattern    = regexp.MustCompile(`^\s*(?:export\s+(?:default\s+)?)?(?:function\s+([A-Z]\w*|use[A-Z]\w*)\s*\(|(?:const|let|var)\s+([A-Z]\w*|use[A-Z]\w*)\s*=)`)
+	refPattern          = regexp.MustCompile(`\b(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*(?:React\.)?useRef\s*\(`)
+)
+
+// HookDependencyRule detects effect and memoization hooks whose dependency array is missing,
+// or is empty or incomplete while the callback uses props or state
+type HookDependencyRule struct {
+	config  core.Config
+	pattern *regexp.Regexp
+}
+
+func NewHookDependencyRule(config core.Config) *HookDependencyRule {
+	return &HookDependencyRule{
+		config:  config,
+		pattern: regexp.MustCompile(`(?:^|[^.\w$]|React\.)(useEffect|useLayoutEffect|useCallback|useMemo)\s*\(`),
+	}
+}
+
+func (r *HookDependencyRule) ID() string   { return "hook-dependencies" }
+func (r *HookDependencyRule) Name() string { return "Hook Dependencies" }
+func (r *HookDependencyRule) Description() string {
+	return "Detects hooks with missing or incomplete dependency arrays"
+}
+func (r *HookDependencyRule) Category() core.RuleCategory { return core.CategoryBug }
+func (r *HookDependencyRule) Severity() core.Severity     { return core.SeverityWarning }
+
+func (r *HookDependencyRule) Rationale() string {
+	return "useEffect, useLayoutEffect, useCallback and useMemo re-run only when a value in their " +
+		"dependency array changes. A callback that reads a prop or state value missing from the array " +
+		"keeps seeing the value from the render that created it, a classic stale closure bug. An effect " +
+		"with no array at all runs after every render. State setters and refs are stable and need not " +
+		"be listed."
+}
+
+func (r *HookDependencyRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `useEffect(() => {
+  loadMessages(roomId);
+}, []);`,
+		Good: `useEffect(() => {
+  loadMessages(roomId);
+}, [roomId]);`,
+	}}
+}
+
+func (r *HookDependencyRule) Options() []core.RuleOption { return nil }
+
+func (r *HookDependencyRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// CheckSource checks every effect and memoization hook call in a file
+func (r *HookDependencyRule) CheckSource(code []string) []core.Result {
+	text := strings.Join(code, "\n")
+	var results []core.Result
+	for _, match := range r.pattern.FindAllStringSubmatchIndex(text, -1) {
+		hook := text[match[2]:match[3]]
+		args := splitArgs(text, match[1]-1)
+		if len(args) == 0 {
+			continue
+		}
+		message := r.checkCall(hook, args, componentScope(text, match[2]))
+		if message == "" {
+			continue
+		}
+		results = append(results, core.Result{
+			RuleID:     r.ID(),
+			RuleName:   r.Name(),
+			Category:   string(r.Category()),
+			Severity:   string(r.Severity()),
+			Line:       1 + strings.Count(text[:match[2]], "\n"),
+			Message:    message,
+			Suggestion: "List every prop, state value and derived value the callback uses in the dependency array",
+		})
+	}
+	return results
+}
+
+// checkCall returns a message describing the dependency problem of one hook call, or ""
+func (r *HookDependencyRule) checkCall(hook string, args []string, scope hookScope) string {
+	callback := args[0]
+	used := scope.outerNames(callback)
+
+	if len(args) < 2 {
+		if hook == "useCallback" || hook == "useMemo" {
+			return fmt.Sprintf("%s has no dependency array, so it is recomputed on every render", hook)
+		}
+		for _, name := range identifiers(callback) {
+			if scope.setters[name] {
+				return fmt.Sprintf("%s updates state with '%s' but has no dependency array, so it runs after every render", hook, name)
+			}
+		}
+		return ""
+	}
+
+	deps := strings.TrimSpace(args[1])
+	if !strings.HasPrefix(deps, "[") || !strings.HasSuffix(deps, "]") {
+		return ""
+	}
+	listed := make(map[string]bool)
+	for _, dep := range strings.Split(deps[1:len(deps)-1], ",") {
+		if root := identPattern.FindString(dep); root != "" {
+			listed[root] = true
+		}
+	}
+
+	var missing []string
+	for _, name := range used {
+		if !listed[name] {
+			missing = append(missing, "'"+name+"'")
+		}
+	}
+	if len(missing) == 0 {
+		return ""
+	}
+	if len(listed) == 0 {
+		return fmt.Sprintf("%s has an empty dependency array but uses %s", hook, strings.Join(missing, ", "))
+	}
+	return fmt.Sprintf("%s dependency array is missing %s", hook, strings.Join(missing, ", "))
+}
+
+// hookScope holds the names declared in a component or custom hook before a hook call
+type hookScope struct {
+	declared map[string]bool // props and local values that can change between renders
+	setters  map[string]bool // state setters, dispatchers and refs, which are stable
+}
+
+// componentScope collects the names declared between the start of the component or custom
+// hook enclosing pos and pos itself
+func componentScope(text string, pos int) hookScope {
+	scope := hookScope{declared: make(map[string]bool), setters: make(map[string]bool)}
+
+	lines := strings.Split(text[:pos], "\n")
+	start := -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		if componentPattern.MatchString(lines[i]) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return scope
+	}
+	body := strings.Join(lines[start:], "\n")
+
+	for _, name := range paramNames(body) {
+		scope.declared[name] = true
+	}
+	for _, m := range declarationPattern.FindAllStringSubmatch(body, -1) {
+		scope.declared[m[1]] = true
+	}
+	for _, m := range destructurePattern.FindAllStringSubmatch(body, -1) {
+		names := identifiers(m[1])
+		for _, name := range names {
+			scope.declared[name] = true
+		}
+		if (m[2] == "useState" || m[2] == "useReducer") && strings.HasPrefix(m[1], "[") && len(names) > 1 {
+			scope.setters[names[1]] = true
+		}
+	}
+	for _, m := range refPattern.FindAllStringSubmatch(body, -1) {
+		scope.setters[m[1]] = true
+	}
+	return scope
+}
+
+// paramNames returns the parameter names of the component declared at the start of body
+func paramNames(body string) []string {
+	open := strings.Index(body, "(")
+	if open < 0 {
+		return nil
+	}
+	return paramIdentifiers(strings.Join(splitArgs(body, open), ","))
+}
+
+// paramIdentifiers returns the names in a parameter list, skipping TypeScript annotations and
+// default values
+func paramIdentifiers(params string) []string {
+	var names []string
+	for _, part := range strings.FieldsFunc(params, func(r rune) bool { return strings.ContainsRune(",{}[]()", r) }) {
+		part = strings.SplitN(strings.SplitN(part, "=", 2)[0], ":", 2)[0]
+		if name := identPattern.FindString(strings.TrimPrefix(strings.TrimSpace(part), "...")); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// outerNames returns the component-scope names a callback uses, excluding stable setters and
+// names the callback declares itself
+func (s hookScope) outerNames(callback string) []string {
+	local := make(map[string]bool)
+	for _, m := range declarationPattern.FindAllStringSubmatch(callback, -1) {
+		local[m[1]] = true
+	}
+	for _, m := range arrowParamsPattern.FindAllStringSubmatch(callback, -1) {
+		for _, name := range paramIdentifiers(m[1] + "," + m[2]) {
+			local[name] = true
+		}
+	}
+	for _, m := range functionArgsPattern.FindAllStringSubmatch(callback, -1) {
+		for _, name := range paramIdentifiers(m[1]) {
+			local[name] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range identifiers(callback) {
+		if s.declared[name] && !s.setters[name] && !local[name] && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// identifiers returns the identifiers in code that are not property names
+func identifiers(code string) []string {
+	var names []string
+	for _, loc := range identPattern.FindAllStringIndex(code, -1) {
+		if loc[0] > 0 && code[loc[0]-1] == '.' {
+			continue
+		}
+		if rest := strings.TrimLeft(code[loc[1]:], " \t"); strings.HasPrefix(rest, ":") && !strings.HasPrefix(rest, "::") {
+			// Object keys, unless part of a ternary such as a ? b : c
+			if !strings.Contains(code[:loc[0]], "?") {
+				continue
+			}
+		}
+		names = append(names, code[loc[0]:loc[1]])
+	}
+	return names
+}
+
+// splitArgs returns the top-level arguments of the call whose opening parenthesis is at open,
+// or nil when the parentheses do not close
+func splitArgs(text string, open int) []string {
+	var args []string
+	depth := 0
+	start := open + 1
+	for i := open; i < len(text); i++ {
+		switch text[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				if arg := strings.TrimSpace(text[start:i]); arg != "" {
+					args = append(args, arg)
+				}
+				return args
+			}
+		case ',':
+			if depth == 1 {
+				args = append(args, strings.TrimSpace(text[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return nil
+}
+
+// ConditionalHookRule detects hooks called inside conditions or loops, or after an early
+// return, which changes the order of hook calls between renders
+type ConditionalHookRule struct {
+	config      core.Config
+	hookPattern *regexp.Regexp
+}
+
+func NewConditionalHookRule(config core.Config) *ConditionalHookRule {
+	return &ConditionalHookRule{
+		config:      config,
+		hookPattern: regexp.MustCompile(`(?:^|[^.\w$]|React\.)(use[A-Z]\w*)\s*\(`),
+	}
+}
+
+func (r *ConditionalHookRule) ID() string   { return "conditional-hook" }
+func (r *ConditionalHookRule) Name() string { return "Conditional Hook" }
+func (r *ConditionalHookRule) Description() string {
+	return "Detects hooks called inside conditions, loops or after an early return"
+}
+func (r *ConditionalHookRule) Category() core.RuleCategory { return core.CategoryBug }
+func (r *ConditionalHookRule) Severity() core.Severity     { return core.SeverityError }
+
+func (r *ConditionalHookRule) Rationale() string {
+	return "React identifies hooks by the order they are called in. A hook inside a condition or loop, " +
+		"or after an early return, runs on some renders and not others, which shifts the state of every " +
+		"later hook onto the wrong call and crashes or corrupts the component."
+}
+
+func (r *ConditionalHookRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `function Profile({ user }) {
+  if (!user) {
+    return null;
+  }
+  const [tab, setTab] = useState('posts');`,
+		Good: `function Profile({ user }) {
+  const [tab, setTab] = useState('posts');
+  if (!user) {
+    return null;
+  }`,
+	}}
+}
+
+func (r *ConditionalHookRule) Options() []core.RuleOption { return nil }
+
+func (r *ConditionalHookRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// block kinds tracked by ConditionalHookRule
+const (
+	blockOther = iota
+	blockFunction
+	blockCondition
+	blockLoop
+)
+
+type hookBlock struct {
+	kind        int
+	earlyReturn bool   // a function block that may return before its later hooks
+	outer       string // statement the block appears in, resumed after other blocks
+}
+
+var (
+	conditionStart = regexp.MustCompile(`^(?:else\b|if\b|switch\b|case\b|default\b|\?)`)
+	loopStart      = regexp.MustCompile(`^(?:for|while|do)\b`)
+	otherStart     = regexp.MustCompile(`^(?:try|catch|finally|class|return)\b`)
+	functionEnd    = regexp.MustCompile(`(?:=>|\bfunction\b[^{]*\)|^[\w$]+\s*\([^)]*\))\s*$`)
+	returnPattern  = regexp.MustCompile(`\breturn\b`)
+)
+
+// CheckSource follows the block structure of a file and checks every hook call against the
+// blocks enclosing it
+func (r *ConditionalHookRule) CheckSource(code []string) []core.Result {
+	var results []core.Result
+	var stack []hookBlock
+	var stmt strings.Builder // code since the last brace or semicolon
+
+	for lineNum, line := range code {
+		hooks := make(map[int]string)
+		for _, m := range r.hookPattern.FindAllStringSubmatchIndex(line, -1) {
+			hooks[m[2]] = line[m[2]:m[3]]
+		}
+
+		reported := false
+		for i := 0; i < len(line); i++ {
+			switch line[i] {
+			case '{', '}', ';':
+				current := stmt.String()
+				if returnPattern.MatchString(current) {
+					markEarlyReturn(stack, statementHead(current))
+				}
+				if line[i] == ';' && strings.Count(current, "(") > strings.Count(current, ")") {
+					break // for (init; cond; post)
+				}
+				stmt.Reset()
+				switch line[i] {
+				case '{':
+					stack = append(stack, hookBlock{kind: blockKind(statementHead(current)), outer: current})
+				case '}':
+					// Object literals, destructuring and JSX expressions are part of a statement
+					if n := len(stack); n > 0 {
+						if stack[n-1].kind == blockOther {
+							stmt.WriteString(stack[n-1].outer)
+						}
+						stack = stack[:n-1]
+					}
+				}
+				continue
+			}
+
+			if hook, ok := hooks[i]; ok && !reported {
+				if reason := hookMisplacement(stack, statementHead(stmt.String())); reason != "" {
+					results = append(results, core.Result{
+						RuleID:     r.ID(),
+						RuleName:   r.Name(),
+						Category:   string(r.Category()),
+						Severity:   string(r.Severity()),
+						Line:       lineNum + 1,
+						Message:    fmt.Sprintf("Hook '%s' is called %s", hook, reason),
+						Suggestion: "Call hooks unconditionally at the top level of the component, before any early return",
+					})
+					reported = true
+				}
+			}
+			stmt.WriteByte(line[i])
+		}
+		// Statements without semicolons end with the line
+		if returnPattern.MatchString(line) && stmt.Len() > 0 {
+			markEarlyReturn(stack, statementHead(stmt.String()))
+		}
+		stmt.WriteByte('\n')
+	}
+	return results
+}
+
+// statementHead returns the statement that the last line of stmt belongs to. Without
+// semicolons, earlier lines are separate statements unless the code continues across them,
+// as in a multi-line if condition.
+func statementHead(stmt string) string {
+	lines := strings.Split(strings.TrimRight(stmt, " \t\n"), "\n")
+	start := len(lines) - 1
+	for start > 0 {
+		current := strings.TrimSpace(lines[start])
+		previous := strings.TrimSpace(lines[start-1])
+		if current != "" && !strings.ContainsAny(current[:1], ").?:&|") &&
+			(previous == "" || !strings.ContainsAny(previous[len(previous)-1:], "(,=&|?:")) {
+			break
+		}
+		start--
+	}
+	return strings.TrimSpace(strings.Join(lines[start:], "\n"))
+}
+
+// blockKind classifies a block by the statement that opens it
+func blockKind(stmt string) int {
+	switch {
+	case conditionStart.MatchString(stmt):
+		return blockCondition
+	case loopStart.MatchString(stmt):
+		return blockLoop
+	case otherStart.MatchString(stmt):
+		return blockOther
+	case functionEnd.MatchString(stmt):
+		return blockFunction
+	}
+	return blockOther
+}
+
+// markEarlyReturn flags the innermost function when it returns from inside a condition
+func markEarlyReturn(stack []hookBlock, stmt string) {
+	conditional := conditionStart.MatchString(stmt)
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch stack[i].kind {
+		case blockCondition, blockLoop:
+			conditional = true
+		case blockFunction:
+			if conditional {
+				stack[i].earlyReturn = true
+			}
+			return
+		}
+	}
+}
+
+// hookMisplacement describes why a hook call is not at the top level of its function, or
+// returns "" when it is
+func hookMisplacement(stack []hookBlock, stmt string) string {
+	if conditionStart.MatchString(stmt) || strings.Contains(stmt, "&&") || strings.Contains(stmt, "||") {
+		return "conditionally"
+	}
+	if loopStart.MatchString(stmt) {
+		return "inside a loop"
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch stack[i].kind {
+		case blockCondition:
+			return "conditionally"
+		case blockLoop:
+			return "inside a loop"
+		case blockFunction:
+			if stack[i].earlyReturn {
+				return "after an early return"
+			}
+			return ""
+		}
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// StarExportRule detects export * statements, which re-export every name of another module
+// and hide which of them are used
+type StarExportRule struct {
+	config  core.Config
+	pattern *regexp.Regexp
+}
+
+func NewStarExportRule(config core.Config) *StarExportRule {
+	return &StarExportRule{
+		config:  config,
+		pattern: regexp.MustCompile(`^\s*export\s+\*\s+from\s+['"]([^'"]+)['"]`),
+	}
+}
+
+func (r *StarExportRule) ID() string                  { return "star-export" }
+func (r *StarExportRule) Name() string                { return "Star Export" }
+func (r *StarExportRule) Description() string         { return "Detects export * re-exports" }
+func (r *StarExportRule) Category() core.RuleCategory { return core.CategoryStyle }
+func (r *StarExportRule) Severity() core.Severity     { return core.SeverityWarning }
+
+func (r *StarExportRule) Rationale() string {
+	return "export * from './module' re-exports every name of another module, so nobody can tell from " +
+		"the barrel file what it offers, bundlers have a harder time dropping unused code and unused " +
+		"exports cannot be detected. Namespaced re-exports such as export * as api are not reported."
+}
+
+func (r *StarExportRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad:  `export * from './Button';`,
+		Good: `export { Button, ButtonProps } from './Button';`,
+	}}
+}
+
+func (r *StarExportRule) Options() []core.RuleOption { return nil }
+
+func (r *StarExportRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// ReadsStrings reports that the rule is given lines with their string literals unmasked, to
+// name the re-exported module
+func (r *StarExportRule) ReadsStrings() bool { return true }
+
+// CheckLine checks a single line for an export * statement. Namespaced re-exports
+// (export * as name from) keep the names apart and are allowed.
+func (r *StarExportRule) CheckLine(line string, lineNum int) *core.Result {
+	matches := r.pattern.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       lineNum,
+		Message:    fmt.Sprintf("'export * from %q' re-exports every name, so unused exports cannot be detected", matches[1]),
+		Suggestion: fmt.Sprintf("Re-export the names consumers need explicitly: export { name1, name2 } from '%s'", matches[1]),
+	}
+}
@@ -0,0 +1,256 @@
+package rules
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// jsxElement is the opening tag of a JSX element found in masked code
+type jsxElement struct {
+	start       int    // offset of '<'
+	end         int    // offset just past the closing '>' of the opening tag
+	attrs       string // text between the tag name and '>'
+	selfClosing bool
+}
+
+// findElements returns the opening tags of every element named tag. Attribute expressions
+// may contain '>' (arrow functions, comparisons), so only a '>' outside braces ends the tag.
+func findElements(text, tag string) []jsxElement {
+	var elements []jsxElement
+	pattern := regexp.MustCompile(`<` + regexp.QuoteMeta(tag) + `\b`)
+	for _, loc := range pattern.FindAllStringIndex(text, -1) {
+		depth := 0
+		for i := loc[1]; i < len(text); i++ {
+			switch text[i] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			case '>':
+				if depth == 0 {
+					elements = append(elements, jsxElement{
+						start:       loc[0],
+						end:         i + 1,
+						attrs:       text[loc[1]:i],
+						selfClosing: text[i-1] == '/',
+					})
+					i = len(text)
+				}
+			}
+		}
+	}
+	return elements
+}
+
+// hasProp reports whether a JSX opening tag sets the named prop
+func (e jsxElement) hasProp(name string) bool {
+	return regexp.MustCompile(`(?:^|\s)` + regexp.QuoteMeta(name) + `\s*=|\{\s*\.\.\.`).MatchString(e.attrs)
+}
+
+// lineOf returns the 1-based line number of an offset in text
+func lineOf(text string, offset int) int {
+	return 1 + strings.Count(text[:offset], "\n")
+}
+
+// ScrollViewMapRule detects lists rendered with .map() inside a ScrollView, which renders
+// every item up front instead of virtualizing them
+type ScrollViewMapRule struct {
+	config     core.Config
+	mapPattern *regexp.Regexp
+}
+
+func NewScrollViewMapRule(config core.Config) *ScrollViewMapRule {
+	return &ScrollViewMapRule{
+		config:     config,
+		mapPattern: regexp.MustCompile(`\.map\s*\(`),
+	}
+}
+
+func (r *ScrollViewMapRule) ID() string                  { return "scrollview-map" }
+func (r *ScrollViewMapRule) Name() string                { return "ScrollView With Map" }
+func (r *ScrollViewMapRule) Description() string         { return "Detects .map() lists inside a ScrollView" }
+func (r *ScrollViewMapRule) Category() core.RuleCategory { return core.CategoryPerformance }
+func (r *ScrollViewMapRule) Severity() core.Severity     { return core.SeverityWarning }
+
+func (r *ScrollViewMapRule) Rationale() string {
+	return "A ScrollView renders all of its children up front, so a list built with .map() inside one " +
+		"mounts every item at once, however long the list grows. FlatList and SectionList only render " +
+		"the items near the screen and recycle memory as the user scrolls."
+}
+
+func (r *ScrollViewMapRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `<ScrollView>
+  {messages.map(m => <MessageRow key={m.id} message={m} />)}
+</ScrollView>`,
+		Good: `<FlatList
+  data={messages}
+  keyExtractor={m => m.id}
+  renderItem={({ item }) => <MessageRow message={item} />}
+/>`,
+	}}
+}
+
+func (r *ScrollViewMapRule) Options() []core.RuleOption { return nil }
+
+func (r *ScrollViewMapRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// CheckSource reports the first .map() call in the children of each ScrollView
+func (r *ScrollViewMapRule) CheckSource(code []string) []core.Result {
+	text := strings.Join(code, "\n")
+	var results []core.Result
+	for _, element := range findElements(text, "ScrollView") {
+		if element.selfClosing {
+			continue
+		}
+		children := text[element.end:]
+		if end := strings.Index(children, "</ScrollView"); end >= 0 {
+			children = children[:end]
+		}
+		loc := r.mapPattern.FindStringIndex(children)
+		if loc == nil {
+			continue
+		}
+		results = append(results, core.Result{
+			RuleID:     r.ID(),
+			RuleName:   r.Name(),
+			Category:   string(r.Category()),
+			Severity:   string(r.Severity()),
+			Line:       lineOf(text, element.end+loc[0]),
+			Message:    "ScrollView renders a list with .map(), which mounts every item at once",
+			Suggestion: "Use FlatList or SectionList so only visible items are rendered",
+		})
+	}
+	return results
+}
+
+// FlatListKeyExtractorRule detects FlatLists without a keyExtractor
+type FlatListKeyExtractorRule struct {
+	config core.Config
+}
+
+func NewFlatListKeyExtractorRule(config core.Config) *FlatListKeyExtractorRule {
+	return &FlatListKeyExtractorRule{config: config}
+}
+
+func (r *FlatListKeyExtractorRule) ID() string   { return "flatlist-key-extractor" }
+func (r *FlatListKeyExtractorRule) Name() string { return "FlatList Key Extractor" }
+func (r *FlatListKeyExtractorRule) Description() string {
+	return "Detects FlatLists without keyExtractor"
+}
+func (r *FlatListKeyExtractorRule) Category() core.RuleCategory { return core.CategoryPerformance }
+func (r *FlatListKeyExtractorRule) Severity() core.Severity     { return core.SeverityWarning }
+
+func (r *FlatListKeyExtractorRule) Rationale() string {
+	return "Without a keyExtractor, FlatList keys items by their key field or else by index. Index keys " +
+		"change when items are inserted or removed, so rows re-render needlessly and keep the state of " +
+		"the wrong item. Lists whose props are spread are not reported, since the spread may supply it."
+}
+
+func (r *FlatListKeyExtractorRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad:  `<FlatList data={orders} renderItem={renderOrder} />`,
+		Good: `<FlatList data={orders} keyExtractor={order => order.id} renderItem={renderOrder} />`,
+	}}
+}
+
+func (r *FlatListKeyExtractorRule) Options() []core.RuleOption { return nil }
+
+func (r *FlatListKeyExtractorRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// CheckSource checks the props of every FlatList. Lists whose props are spread are skipped,
+// since the spread may supply the missing prop.
+func (r *FlatListKeyExtractorRule) CheckSource(code []string) []core.Result {
+	text := strings.Join(code, "\n")
+	var results []core.Result
+	for _, element := range findElements(text, "FlatList") {
+		if element.hasProp("keyExtractor") {
+			continue
+		}
+		results = append(results, core.Result{
+			RuleID:     r.ID(),
+			RuleName:   r.Name(),
+			Category:   string(r.Category()),
+			Severity:   string(r.Severity()),
+			Line:       lineOf(text, element.start),
+			Message:    "FlatList has no keyExtractor, so items are keyed by index unless they have a key field",
+			Suggestion: "Add keyExtractor={item => item.id} with a stable, unique id",
+		})
+	}
+	return results
+}
+
+// FlatListRenderHintsRule detects FlatLists over dynamic data without getItemLayout or
+// initialNumToRender
+type FlatListRenderHintsRule struct {
+	config core.Config
+}
+
+func NewFlatListRenderHintsRule(config core.Config) *FlatListRenderHintsRule {
+	return &FlatListRenderHintsRule{config: config}
+}
+
+func (r *FlatListRenderHintsRule) ID() string   { return "flatlist-render-hints" }
+func (r *FlatListRenderHintsRule) Name() string { return "FlatList Render Hints" }
+func (r *FlatListRenderHintsRule) Description() string {
+	return "Detects FlatLists without render hints"
+}
+func (r *FlatListRenderHintsRule) Category() core.RuleCategory { return core.CategoryPerformance }
+func (r *FlatListRenderHintsRule) Severity() core.Severity     { return core.SeverityInfo }
+
+func (r *FlatListRenderHintsRule) Rationale() string {
+	return "Without getItemLayout, FlatList has to render items to learn their height before it can " +
+		"scroll to an offset, and without initialNumToRender it renders a fixed first batch whatever " +
+		"the screen holds. For long lists of fixed-height rows, either hint makes the first render and " +
+		"scrolling noticeably faster. Lists over an inline array literal are small and not reported."
+}
+
+func (r *FlatListRenderHintsRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `<FlatList data={contacts} renderItem={renderContact} keyExtractor={c => c.id} />`,
+		Good: `<FlatList
+  data={contacts}
+  renderItem={renderContact}
+  keyExtractor={c => c.id}
+  getItemLayout={(_, index) => ({ length: ROW_HEIGHT, offset: ROW_HEIGHT * index, index })}
+/>`,
+	}}
+}
+
+func (r *FlatListRenderHintsRule) Options() []core.RuleOption { return nil }
+
+func (r *FlatListRenderHintsRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// inlineDataPattern matches a data prop given as an array literal, which is small and static
+var inlineDataPattern = regexp.MustCompile(`(?:^|\s)data\s*=\s*\{\s*\[`)
+
+// CheckSource checks every FlatList whose data is not an inline array literal
+func (r *FlatListRenderHintsRule) CheckSource(code []string) []core.Result {
+	text := strings.Join(code, "\n")
+	var results []core.Result
+	for _, element := range findElements(text, "FlatList") {
+		if inlineDataPattern.MatchString(element.attrs) ||
+			element.hasProp("getItemLayout") || element.hasProp("initialNumToRender") {
+			continue
+		}
+		results = append(results, core.Result{
+			RuleID:     r.ID(),
+			RuleName:   r.Name(),
+			Category:   string(r.Category()),
+			Severity:   string(r.Severity()),
+			Line:       lineOf(text, element.start),
+			Message:    "FlatList sets neither getItemLayout nor initialNumToRender, which slows rendering of large lists",
+			Suggestion: "Add getItemLayout for fixed-height rows, or tune initialNumToRender to fill the first screen",
+		})
+	}
+	return results
+}
@@ -23,6 +23,25 @@ func (r *UnusedFunctionRule) Description() string { return "Detects functions th
 func (r *UnusedFunctionRule) Category() core.RuleCategory { return core.CategoryOrphaned }
 func (r *UnusedFunctionRule) Severity() core.Severity     { return core.SeverityWarning }
 
+func (r *UnusedFunctionRule) Rationale() string {
+	return "Functions nothing calls are left behind when code is rewritten rather than edited, and they " +
+		"mislead readers into thinking they matter. Only one file is visible to the rule, so it is " +
+		"conservative: exported functions, tests and React lifecycle methods are never reported."
+}
+
+func (r *UnusedFunctionRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `function formatLegacy(record) { // replaced by format, never called
+  return record.name + ';' + record.id;
+}`,
+		Good: `export function formatLegacy(record) {
+  return record.name + ';' + record.id;
+}`,
+	}}
+}
+
+func (r *UnusedFunctionRule) Options() []core.RuleOption { return core.UnusedFunctionOptions() }
+
 func (r *UnusedFunctionRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	if !config.Rules.OrphanedCode.CheckUnusedFunctions {
 		return nil
@@ -76,6 +95,21 @@ func (r *UnusedVariableRule) Description() string { return "Detects variables th
 func (r *UnusedVariableRule) Category() core.RuleCategory { return core.CategoryOrphaned }
 func (r *UnusedVariableRule) Severity() core.Severity     { return core.SeverityWarning }
 
+func (r *UnusedVariableRule) Rationale() string {
+	return "A variable that is assigned but never read is either dead code or a sign that a result is " +
+		"being dropped by mistake."
+}
+
+func (r *UnusedVariableRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `const count = items.length;
+return items.reduce((sum, item) => sum + item.price, 0);`,
+		Good: `return items.reduce((sum, item) => sum + item.price, 0);`,
+	}}
+}
+
+func (r *UnusedVariableRule) Options() []core.RuleOption { return core.UnusedVariableOptions() }
+
 func (r *UnusedVariableRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	if !config.Rules.OrphanedCode.CheckUnusedVariables {
 		return nil
@@ -98,6 +132,22 @@ func (r *UnreachableCodeRule) Description() string { return "Detects code that c
 func (r *UnreachableCodeRule) Category() core.RuleCategory { return core.CategoryOrphaned }
 func (r *UnreachableCodeRule) Severity() core.Severity     { return core.SeverityWarning }
 
+func (r *UnreachableCodeRule) Rationale() string {
+	return "Statements after a return or throw in the same block can never run. They usually remain " +
+		"from an edit that added an early exit, and readers waste time working out when they execute."
+}
+
+func (r *UnreachableCodeRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `return fetchUser(id);
+setLoading(false);`,
+		Good: `setLoading(false);
+return fetchUser(id);`,
+	}}
+}
+
+func (r *UnreachableCodeRule) Options() []core.RuleOption { return core.UnreachableCodeOptions() }
+
 func (r *UnreachableCodeRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	if !config.Rules.OrphanedCode.CheckUnreachableCode {
 		return nil
@@ -120,6 +170,20 @@ func (r *DeadImportRule) Description() string { return "Detects imports that are
 func (r *DeadImportRule) Category() core.RuleCategory { return core.CategoryOrphaned }
 func (r *DeadImportRule) Severity() core.Severity     { return core.SeverityWarning }
 
+func (r *DeadImportRule) Rationale() string {
+	return "An import nothing uses still ends up in the bundle when the module has side effects, slows " +
+		"down start-up and hints at code that was removed only halfway."
+}
+
+func (r *DeadImportRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad:  `import { View, Text, Image } from 'react-native'; // Image is never used`,
+		Good: `import { View, Text } from 'react-native';`,
+	}}
+}
+
+func (r *DeadImportRule) Options() []core.RuleOption { return core.DeadImportOptions() }
+
 func (r *DeadImportRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	if !config.Rules.OrphanedCode.CheckDeadImports {
 		return nil
@@ -28,6 +28,25 @@ func (r *InlineStyleRule) Description() string           { return "Detects inlin
 func (r *InlineStyleRule) Category() core.RuleCategory   { return core.CategoryPerformance }
 func (r *InlineStyleRule) Severity() core.Severity       { return core.SeverityWarning }
 
+func (r *InlineStyleRule) Rationale() string {
+	return "A style={{...}} literal creates a new object on every render, so memoized children see a " +
+		"changed prop and render again, and React Native has to diff and send the style to the native " +
+		"side each time. StyleSheet.create defines the style once and validates it."
+}
+
+func (r *InlineStyleRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `<View style={{ padding: 16, flexDirection: 'row' }}>`,
+		Good: `const styles = StyleSheet.create({
+  row: { padding: 16, flexDirection: 'row' },
+});
+
+<View style={styles.row}>`,
+	}}
+}
+
+func (r *InlineStyleRule) Options() []core.RuleOption { return nil }
+
 func (r *InlineStyleRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	return nil
 }
@@ -72,6 +91,23 @@ func (r *AnonymousFunctionInJSXRule) Description() string           { return "De
 func (r *AnonymousFunctionInJSXRule) Category() core.RuleCategory   { return core.CategoryPerformance }
 func (r *AnonymousFunctionInJSXRule) Severity() core.Severity       { return core.SeverityWarning }
 
+func (r *AnonymousFunctionInJSXRule) Rationale() string {
+	return "An arrow function written inline in an onX or renderX prop is a new function on every " +
+		"render, which defeats React.memo and PureComponent in the child and makes lists re-render " +
+		"every row. useCallback, or a method defined once, keeps the reference stable."
+}
+
+func (r *AnonymousFunctionInJSXRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `<Button onPress={() => submit(form)} />`,
+		Good: `const onSubmit = useCallback(() => submit(form), [form]);
+
+<Button onPress={onSubmit} />`,
+	}}
+}
+
+func (r *AnonymousFunctionInJSXRule) Options() []core.RuleOption { return nil }
+
 func (r *AnonymousFunctionInJSXRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	return nil
 }
@@ -113,6 +149,21 @@ func (r *ConsoleLogRule) Description() string           { return "Detects consol
 func (r *ConsoleLogRule) Category() core.RuleCategory   { return core.CategoryPerformance }
 func (r *ConsoleLogRule) Severity() core.Severity       { return core.SeverityInfo }
 
+func (r *ConsoleLogRule) Rationale() string {
+	return "console calls left in from debugging slow down release builds, since React Native sends " +
+		"every message across the bridge, and they can leak user data into device logs. Use a logger " +
+		"that is silenced in production instead."
+}
+
+func (r *ConsoleLogRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad:  `console.log('user', user);`,
+		Good: `logger.debug('loaded user', { id: user.id });`,
+	}}
+}
+
+func (r *ConsoleLogRule) Options() []core.RuleOption { return nil }
+
 func (r *ConsoleLogRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	return nil
 }
@@ -165,6 +216,27 @@ func (r *DeprecatedLifecycleRule) Description() string           { return "Detec
 func (r *DeprecatedLifecycleRule) Category() core.RuleCategory   { return core.CategoryDeprecated }
 func (r *DeprecatedLifecycleRule) Severity() core.Severity       { return core.SeverityWarning }
 
+func (r *DeprecatedLifecycleRule) Rationale() string {
+	return "componentWillMount, componentWillReceiveProps and componentWillUpdate, with or without the " +
+		"UNSAFE_ prefix, run during rendering and are unsafe with concurrent rendering. React warns " +
+		"about them and has replacements for each."
+}
+
+func (r *DeprecatedLifecycleRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `componentWillReceiveProps(nextProps) {
+  if (nextProps.id !== this.props.id) {
+    this.setState({ data: null });
+  }
+}`,
+		Good: `static getDerivedStateFromProps(props, state) {
+  return props.id !== state.id ? { id: props.id, data: null } : null;
+}`,
+	}}
+}
+
+func (r *DeprecatedLifecycleRule) Options() []core.RuleOption { return nil }
+
 func (r *DeprecatedLifecycleRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	return nil
 }
@@ -207,10 +279,59 @@ func (r *MissingKeyPropRule) Description() string           { return "Detects .m
 func (r *MissingKeyPropRule) Category() core.RuleCategory   { return core.CategoryPerformance }
 func (r *MissingKeyPropRule) Severity() core.Severity       { return core.SeverityWarning }
 
+func (r *MissingKeyPropRule) Rationale() string {
+	return "React uses the key prop to match list items between renders. Without keys it falls back to " +
+		"positions, so inserting or removing an item re-renders every row after it and can attach " +
+		"state, such as the text of an input, to the wrong item."
+}
+
+func (r *MissingKeyPropRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad:  `{todos.map(todo => <TodoRow todo={todo} />)}`,
+		Good: `{todos.map(todo => <TodoRow key={todo.id} todo={todo} />)}`,
+	}}
+}
+
+func (r *MissingKeyPropRule) Options() []core.RuleOption { return nil }
+
 func (r *MissingKeyPropRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	return nil
 }
 
+var (
+	// mapElementPattern matches a JSX element or fragment returned directly by a map callback
+	mapElementPattern = regexp.MustCompile(`^\s*\(?\s*<([A-Za-z][\w.]*|>)`)
+	keyPropPattern    = regexp.MustCompile(`\bkey\s*=`)
+)
+
+// CheckLine checks a line for a .map() callback that returns JSX without a key. Callbacks
+// whose element starts after the line, or whose opening tag does not end on it, are skipped.
+func (r *MissingKeyPropRule) CheckLine(line string, lineNum int) *core.Result {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "*") {
+		return nil
+	}
+
+	loc := r.mapPattern.FindStringIndex(line)
+	if loc == nil {
+		return nil
+	}
+	rest := line[loc[1]:]
+	element := mapElementPattern.FindStringIndex(rest)
+	if element == nil || keyPropPattern.MatchString(rest) || !strings.Contains(rest[element[1]-1:], ">") {
+		return nil
+	}
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       lineNum,
+		Message:    "Element rendered by .map() has no key prop",
+		Suggestion: "Add a stable key, e.g. key={item.id}, to the outermost element; fragments need <React.Fragment key={...}>",
+	}
+}
+
 // HardcodedDimensionRule detects hardcoded pixel values
 type HardcodedDimensionRule struct {
 	config  core.Config
@@ -230,6 +351,23 @@ func (r *HardcodedDimensionRule) Description() string           { return "Detect
 func (r *HardcodedDimensionRule) Category() core.RuleCategory   { return core.CategoryStyle }
 func (r *HardcodedDimensionRule) Severity() core.Severity       { return core.SeverityInfo }
 
+func (r *HardcodedDimensionRule) Rationale() string {
+	return "Sizes of 100 points or more written as literals are tuned for one screen. On smaller phones " +
+		"they overflow, and on tablets they look lost. Flex layout, percentages or sizes derived from " +
+		"useWindowDimensions adapt to the device."
+}
+
+func (r *HardcodedDimensionRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `card: { width: 350, height: 600 }`,
+		Good: `const { width } = useWindowDimensions();
+
+card: { width: width - 32, flex: 1 }`,
+	}}
+}
+
+func (r *HardcodedDimensionRule) Options() []core.RuleOption { return nil }
+
 func (r *HardcodedDimensionRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	return nil
 }
@@ -276,6 +414,21 @@ func (r *DirectStateMutationRule) Description() string           { return "Detec
 func (r *DirectStateMutationRule) Category() core.RuleCategory   { return core.CategoryBug }
 func (r *DirectStateMutationRule) Severity() core.Severity       { return core.SeverityError }
 
+func (r *DirectStateMutationRule) Rationale() string {
+	return "Assigning to this.state or mutating one of its arrays in place does not schedule a render, " +
+		"and the next setState may overwrite the change. Components also compare state by reference, so " +
+		"a mutated array looks unchanged."
+}
+
+func (r *DirectStateMutationRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad:  `this.state.items.push(item);`,
+		Good: `this.setState(state => ({ items: [...state.items, item] }));`,
+	}}
+}
+
+func (r *DirectStateMutationRule) Options() []core.RuleOption { return nil }
+
 func (r *DirectStateMutationRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	return nil
 }
@@ -1,6 +1,7 @@
 package rules
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
@@ -201,6 +202,38 @@ func TestDirectStateMutationRule_CheckLine(t *testing.T) {
 	}
 }
 
+func TestMissingKeyPropRule_CheckLine(t *testing.T) {
+	config := getTestConfig()
+	rule := NewMissingKeyPropRule(config)
+
+	tests := []struct {
+		name     string
+		line     string
+		hasIssue bool
+	}{
+		{"element without key", `{items.map(item => <Item title={item.title} />)}`, true},
+		{"parenthesized element", `{items.map((item, i) => (<Row item={item} />))}`, true},
+		{"fragment", `{items.map(item => <><Text>{item.name}</Text></>)}`, true},
+		{"element with key", `{items.map(item => <Item key={item.id} title={item.title} />)}`, false},
+		{"element on next line", `{items.map(item => (`, false},
+		{"props on next line", `{items.map(item => <Item`, false},
+		{"non-JSX map", `const ids = items.map(item => item.id);`, false},
+		{"commented out", `// items.map(item => <Item />)`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := rule.CheckLine(tt.line, 1)
+			if tt.hasIssue && result == nil {
+				t.Errorf("Expected issue for line: %s", tt.line)
+			}
+			if !tt.hasIssue && result != nil {
+				t.Errorf("Unexpected issue for line: %s", tt.line)
+			}
+		})
+	}
+}
+
 func TestInlineStyleRule_ID(t *testing.T) {
 	config := getTestConfig()
 	rule := NewInlineStyleRule(config)
@@ -248,3 +281,222 @@ func TestDirectStateMutationRule_ID(t *testing.T) {
 		t.Errorf("Expected ID 'direct-state-mutation', got '%s'", rule.ID())
 	}
 }
+
+func TestHookDependencyRule_CheckSource(t *testing.T) {
+	rule := NewHookDependencyRule(getTestConfig())
+
+	tests := []struct {
+		name    string
+		code    string
+		message string
+	}{
+		{"empty array using a prop", "function Profile({ userId }) {\n  useEffect(() => {\n    load(userId);\n  }, []);\n}", "useEffect has an empty dependency array but uses 'userId'"},
+		{"incomplete array", "function Form({ onSave }) {\n  const [value, setValue] = useState('');\n  const save = useCallback(() => onSave(value), [value]);\n}", "useCallback dependency array is missing 'onSave'"},
+		{"memo without array", "const Total = ({ items }) => {\n  const sum = useMemo(() => items.length);\n}", "useMemo has no dependency array, so it is recomputed on every render"},
+		{"effect setting state every render", "function Counter() {\n  const [count, setCount] = useState(0);\n  useEffect(() => { setCount(1); });\n}", "useEffect updates state with 'setCount' but has no dependency array, so it runs after every render"},
+		{"complete array", "function Profile({ userId }) {\n  const ref = useRef(null);\n  useEffect(() => {\n    ref.current = userId;\n  }, [userId]);\n}", ""},
+		{"stable setter and local values", "function Clock() {\n  const [now, setNow] = useState(0);\n  useEffect(() => {\n    const id = setInterval(() => setNow(Date.now()), 1000);\n    return () => clearInterval(id);\n  }, []);\n}", ""},
+		{"effect without array and without state", "function Title({ title }) {\n  useEffect(() => {\n    document.title = title;\n  });\n}", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := rule.CheckSource(strings.Split(tt.code, "\n"))
+			if tt.message == "" {
+				if len(results) != 0 {
+					t.Errorf("Unexpected results: %v", results)
+				}
+				return
+			}
+			if len(results) != 1 || results[0].Message != tt.message {
+				t.Errorf("Expected %q, got %v", tt.message, results)
+			}
+		})
+	}
+}
+
+func TestConditionalHookRule_CheckSource(t *testing.T) {
+	rule := NewConditionalHookRule(getTestConfig())
+
+	code := `export default function Profile({ user, items }) {
+  const [open, setOpen] = useState(false);
+  if (user) {
+    const [name, setName] = useState(user.name);
+  }
+  for (let i = 0; i < items.length; i++) {
+    useEffect(() => {}, []);
+  }
+  const theme = open && useContext(Theme);
+  useEffect(() => {
+    if (open) {
+      setOpen(false);
+    }
+  }, [open]);
+  if (!user) return null
+  const ref = useRef(null)
+  return <List data={items} />
+}
+
+function Other() {
+  const value = useMemo(() => compute(), [])
+  return value
+}`
+
+	results := rule.CheckSource(strings.Split(code, "\n"))
+	want := map[int]string{
+		4:  "Hook 'useState' is called conditionally",
+		7:  "Hook 'useEffect' is called inside a loop",
+		9:  "Hook 'useContext' is called conditionally",
+		16: "Hook 'useRef' is called after an early return",
+	}
+	if len(results) != len(want) {
+		t.Errorf("Expected %d results, got %d: %v", len(want), len(results), results)
+	}
+	for _, r := range results {
+		if want[r.Line] != r.Message {
+			t.Errorf("Unexpected result on line %d: %s", r.Line, r.Message)
+		}
+	}
+}
+
+func TestListRules_CheckSource(t *testing.T) {
+	config := getTestConfig()
+
+	code := `function Feed({ posts, tags }) {
+  return (
+    <View>
+      <ScrollView horizontal>
+        {tags.map(tag => <Tag key={tag} name={tag} />)}
+      </ScrollView>
+      <ScrollView />
+      <FlatList
+        data={posts}
+        renderItem={({ item }) => item.visible > 0 && <Post post={item} />}
+      />
+      <FlatList data={posts} keyExtractor={post => post.id} initialNumToRender={8} />
+      <FlatList data={[{ key: 'a' }, { key: 'b' }]} renderItem={renderTab} />
+    </View>
+  );
+}`
+	lines := strings.Split(code, "\n")
+
+	tests := []struct {
+		rule  SourceCheckRule
+		lines []int
+	}{
+		{NewScrollViewMapRule(config), []int{5}},
+		{NewFlatListKeyExtractorRule(config), []int{8, 13}},
+		{NewFlatListRenderHintsRule(config), []int{8}},
+	}
+
+	for _, tt := range tests {
+		results := tt.rule.CheckSource(lines)
+		if len(results) != len(tt.lines) {
+			t.Errorf("%s: expected %d results, got %d: %v", tt.rule.ID(), len(tt.lines), len(results), results)
+			continue
+		}
+		for i, r := range results {
+			if r.Line != tt.lines[i] || r.RuleID != tt.rule.ID() {
+				t.Errorf("%s: expected a result on line %d, got %+v", tt.rule.ID(), tt.lines[i], r)
+			}
+		}
+	}
+}
+
+func TestAsyncRules_CheckSource(t *testing.T) {
+	config := getTestConfig()
+
+	code := `async function loadUser(id) {
+  const res = await fetch(url + id)
+  return res.json()
+}
+
+export default function Profile({ id }) {
+  useEffect(() => {
+    loadUser(id)
+  }, [id])
+
+  const refresh = () =>
+    loadUser(id)
+
+  const handleSave = async () => {
+    await saveUser(id)
+  }
+
+  const handleDelete = async () => {
+    try {
+      loadUser(id).catch(report)
+      await deleteUser(id)
+    } catch (e) {
+      report(e)
+    }
+  }
+
+  async function onRefresh() {
+    await loadUser(id)
+  }
+
+  const handleLoad = async () => {
+    await loadUser(id)
+  }
+
+  return (
+    <View onLayout={layout}>
+      <Button onPress={async e => await track(e)} title="Save" />
+      <Button onPress={handleSave} title="Save" />
+      <Button onPress={handleDelete} onLongPress={onRefresh} title="Delete" />
+    </View>
+  )
+}`
+	lines := strings.Split(code, "\n")
+
+	tests := []struct {
+		rule  SourceCheckRule
+		lines []int
+	}{
+		{NewFloatingPromiseRule(config), []int{8}},
+		{NewUnhandledAsyncHandlerRule(config), []int{14, 27, 37}},
+	}
+
+	for _, tt := range tests {
+		results := tt.rule.CheckSource(lines)
+		if len(results) != len(tt.lines) {
+			t.Errorf("%s: expected %d results, got %d: %v", tt.rule.ID(), len(tt.lines), len(results), results)
+			continue
+		}
+		for i, r := range results {
+			if r.Line != tt.lines[i] || r.RuleID != tt.rule.ID() {
+				t.Errorf("%s: expected a result on line %d, got %+v", tt.rule.ID(), tt.lines[i], r)
+			}
+		}
+	}
+}
+
+func TestStarExportRule_CheckLine(t *testing.T) {
+	config := getTestConfig()
+	rule := NewStarExportRule(config)
+
+	tests := []struct {
+		name     string
+		line     string
+		hasIssue bool
+	}{
+		{"star export", `export * from './components';`, true},
+		{"double quotes", `export * from "../utils"`, true},
+		{"namespaced export", `export * as icons from './icons';`, false},
+		{"named export", `export { Button } from './Button';`, false},
+		{"namespace import", `import * as React from 'react';`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := rule.CheckLine(tt.line, 1)
+			if tt.hasIssue && result == nil {
+				t.Errorf("Expected issue for line: %s", tt.line)
+			}
+			if !tt.hasIssue && result != nil {
+				t.Errorf("Unexpected issue for line: %s", tt.line)
+			}
+		})
+	}
+}
@@ -9,14 +9,25 @@ import (
 
 // FunctionMetrics contains metrics about a JavaScript/TypeScript function
 type FunctionMetrics struct {
-	Name       string
-	IsMethod   bool
-	ClassName  string
-	IsAsync    bool
-	IsArrow    bool
-	IsExported bool
-	LineCount  int
-	StartLine  int
+	Name         string
+	IsMethod     bool
+	ClassName    string
+	IsAsync      bool
+	IsArrow      bool
+	IsExported   bool
+	LineCount    int
+	LogicalLines int // statements, counting multi-line statements once
+	StartLine    int
+	EndLine      int         // line of the brace closing the body
+	BranchChain  BranchChain // the switch or if-else chain with the most branches
+}
+
+// QualifiedName returns the name of the function, prefixed by its class or object for a method
+func (m *FunctionMetrics) QualifiedName() string {
+	if m.IsMethod && m.ClassName != "" {
+		return m.ClassName + "." + m.Name
+	}
+	return m.Name
 }
 
 // FileMetrics contains metrics about a JavaScript/TypeScript file
@@ -31,6 +42,8 @@ type FileMetrics struct {
 	ImportCount    int
 	ClassCount     int
 	ComponentCount int
+	Generated      bool // carries a code generation marker
+	Vendored       bool // lies in a vendored dependency directory
 }
 
 // LargeFunctionRule detects functions that are too large
@@ -44,16 +57,44 @@ func NewLargeFunctionRule(config core.Config) *LargeFunctionRule {
 
 func (r *LargeFunctionRule) ID() string          { return "large-function" }
 func (r *LargeFunctionRule) Name() string        { return "Large Function" }
-func (r *LargeFunctionRule) Description() string { return "Detects functions that exceed the maximum number of lines" }
+func (r *LargeFunctionRule) Description() string { return "Detects functions that exceed the maximum number of lines or logical lines" }
 func (r *LargeFunctionRule) Category() core.RuleCategory { return core.CategorySize }
 func (r *LargeFunctionRule) Severity() core.Severity     { return core.SeverityWarning }
 
+func (r *LargeFunctionRule) Rationale() string {
+	return "Long functions and components mix several steps and levels of abstraction, which makes them " +
+		"hard to review, test and change safely. Generated code tends to grow one component instead of " +
+		"extracting new ones. A function is measured by its lines, or by its logical lines when the " +
+		"metric is statements, so comments and blank lines do not count against it."
+}
+
+func (r *LargeFunctionRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `function ProfileScreen({ userId }) {
+  // fetching, form state, validation and 150 lines of JSX
+}`,
+		Good: `function ProfileScreen({ userId }) {
+  const user = useUser(userId);
+  const form = useProfileForm(user);
+  return <ProfileForm form={form} />;
+}`,
+	}}
+}
+
+func (r *LargeFunctionRule) Options() []core.RuleOption {
+	return core.FunctionSizeOptions("reactnative", "js")
+}
+
 func (r *LargeFunctionRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	maxLines := config.Rules.FunctionSize.MaxLines
 
 	switch n := node.(type) {
 	case *FunctionMetrics:
-		if n.LineCount > maxLines {
+		size, unit := n.LineCount, "lines"
+		if config.Rules.FunctionSize.Metric == core.MetricStatements {
+			size, unit = n.LogicalLines, "logical lines"
+		}
+		if size > maxLines {
 			funcType := "Function"
 			if n.IsArrow {
 				funcType = "Arrow function"
@@ -61,14 +102,19 @@ func (r *LargeFunctionRule) Check(ctx context.Context, node interface{}, config
 			if n.IsMethod {
 				funcType = "Method"
 			}
+			suggestion := fmt.Sprintf("Consider breaking down %s '%s' into smaller functions", funcType, n.QualifiedName())
+			if config.Output.Verbose {
+				suggestion += fmt.Sprintf(" (%d lines, %d logical lines)", n.LineCount, n.LogicalLines)
+			}
 			return &core.Result{
 				RuleID:     r.ID(),
 				RuleName:   r.Name(),
 				Category:   string(r.Category()),
 				Severity:   string(r.Severity()),
 				Line:       n.StartLine,
-				Message:    fmt.Sprintf("%s '%s' is too large (%d lines, max %d)", funcType, n.Name, n.LineCount, maxLines),
-				Suggestion: fmt.Sprintf("Consider breaking down %s '%s' into smaller functions", funcType, n.Name),
+				EndLine:    n.EndLine,
+				Message:    fmt.Sprintf("%s '%s' is too large (%d %s, max %d)", funcType, n.QualifiedName(), size, unit, maxLines),
+				Suggestion: suggestion,
 			}
 		}
 	}
@@ -90,19 +136,45 @@ func (r *LargeFileRule) Description() string { return "Detects files that exceed
 func (r *LargeFileRule) Category() core.RuleCategory { return core.CategorySize }
 func (r *LargeFileRule) Severity() core.Severity     { return core.SeverityWarning }
 
+func (r *LargeFileRule) Rationale() string {
+	return "A large module usually holds several unrelated components or concerns, so readers have to " +
+		"page through code they do not care about and changes collide in review. Generated and vendored " +
+		"files are never reported."
+}
+
+func (r *LargeFileRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `// screens.tsx, 1800 lines: every screen of the app`,
+		Good: `// screens/Home.tsx
+// screens/Profile.tsx
+// screens/Settings.tsx`,
+	}}
+}
+
+func (r *LargeFileRule) Options() []core.RuleOption {
+	return core.FileSizeOptions("reactnative", "js")
+}
+
 func (r *LargeFileRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
 	maxLines := config.Rules.FileSize.MaxLines
 
 	switch n := node.(type) {
 	case *FileMetrics:
-		if n.TotalLines > maxLines {
+		if n.Generated || n.Vendored {
+			return nil
+		}
+		size, unit := n.TotalLines, "lines"
+		if config.Rules.FileSize.CountMode == core.CountCode {
+			size, unit = n.CodeLines, "code lines"
+		}
+		if size > maxLines {
 			return &core.Result{
 				RuleID:     r.ID(),
 				RuleName:   r.Name(),
 				Category:   string(r.Category()),
 				Severity:   string(r.Severity()),
 				Line:       1,
-				Message:    fmt.Sprintf("File is too large (%d lines, max %d)", n.TotalLines, maxLines),
+				Message:    fmt.Sprintf("File is too large (%d %s, max %d)", size, unit, maxLines),
 				Suggestion: "Consider splitting this file into multiple smaller modules",
 			}
 		}
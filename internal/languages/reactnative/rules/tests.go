@@ -0,0 +1,342 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+)
+
+var (
+	testCallPattern   = regexp.MustCompile(`(?:^|[^\w$.])(?:it|test)(?:\.only|\.concurrent)?\s*\(`)
+	assertionPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\b(?:expect|assert|verify|should)[\w$]*\s*[(.]`),
+		regexp.MustCompile(`\.should\b`),
+		regexp.MustCompile(`\bcy\.[\w$]+\s*\(`),
+		regexp.MustCompile(`\b(?:getBy|getAllBy|findBy|findAllBy)[\w$]*\s*\(`),
+		regexp.MustCompile(`\bt\.(?:is|not|true|false|truthy|falsy|deepEqual|like|throws|throwsAsync|pass|fail|snapshot)\s*\(`),
+		regexp.MustCompile(`\b(?:throw|fail)\b`),
+	}
+	testCalleePattern = regexp.MustCompile(`([A-Za-z_$][\w$.]*)\s*\(`)
+	assertionName     = regexp.MustCompile(`^(?:expect|assert|verify|should)`)
+	moduleNamePattern = regexp.MustCompile(`\bimport\s+(?:\*\s+as\s+)?([A-Za-z_$][\w$]*)|\b([A-Za-z_$][\w$]*)\s*=\s*require\s*\(`)
+	testTokenPattern  = regexp.MustCompile(`[A-Za-z_$][\w$]*|\d[\w.]*|\S`)
+)
+
+// neutralCallees are keywords and builtins that a test uses to inspect values
+var neutralCallees = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "return": true, "function": true, "catch": true,
+	"typeof": true, "await": true, "async": true, "String": true, "Number": true, "Boolean": true,
+	"parseInt": true, "parseFloat": true,
+}
+
+// neutralReceivers are builtin objects whose methods a test uses to inspect values
+var neutralReceivers = map[string]bool{"JSON": true, "Object": true, "Array": true, "Math": true, "Promise": true, "console": true, "jest": true}
+
+// testBlock is an it() or test() call of a test file
+type testBlock struct {
+	line     int
+	asserts  bool
+	getters  []string // accessors called by a test that calls nothing else but constructors, nil otherwise
+	body     languages.TestBody
+	subjects map[string]bool
+}
+
+// findTestBlocks returns the it() and test() calls of masked code with a function as their
+// second argument. Skipped and todo tests are left out.
+func findTestBlocks(code []string) []testBlock {
+	text := strings.Join(code, "\n")
+	modules := make(map[string]bool)
+	for _, m := range moduleNamePattern.FindAllStringSubmatch(text, -1) {
+		modules[m[1]+m[2]] = true
+	}
+
+	var blocks []testBlock
+	for _, loc := range testCallPattern.FindAllStringIndex(text, -1) {
+		args := splitArgs(text, loc[1]-1)
+		if len(args) < 2 {
+			continue
+		}
+		callback := strings.TrimSpace(strings.TrimPrefix(args[1], "async"))
+		callback = strings.TrimSpace(strings.TrimPrefix(callback, "function"))
+		if !strings.HasPrefix(callback, "(") && !identPattern.MatchString(callback) {
+			continue
+		}
+		body := functionBody(callback, 0)
+		block := testBlock{line: lineOf(text, loc[1]-1), subjects: make(map[string]bool)}
+		block.classify(body, modules)
+		block.body = languages.TestBody{Tokens: testTokens(body), Subject: sortedSet(block.subjects)}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// classify sorts the calls of a test body into assertions, accessors and other calls. modules
+// are the names of imported modules, whose functions are not accessors.
+func (b *testBlock) classify(body string, modules map[string]bool) {
+	for _, pattern := range assertionPatterns {
+		if pattern.MatchString(body) {
+			b.asserts = true
+		}
+	}
+
+	other := false
+	for _, match := range testCalleePattern.FindAllStringSubmatchIndex(body, -1) {
+		name := body[match[2]:match[3]]
+		if match[0] > 0 && body[match[0]-1] == '.' || neutralCallees[name] {
+			continue
+		}
+		receiver, method := "", name
+		if dot := strings.LastIndex(name, "."); dot >= 0 {
+			receiver, method = name[:dot], name[dot+1:]
+		}
+		root := strings.Split(receiver, ".")[0]
+		if method == "" || neutralReceivers[root] || assertionName.MatchString(method) {
+			continue
+		}
+
+		b.subjects[method] = true
+		isMethod := receiver != "" && !modules[root]
+		args := splitArgs(body, match[1]-1)
+		switch {
+		case strings.HasSuffix(strings.TrimSpace(body[:match[0]]), "new"):
+		case isMethod && len(args) == 0 && languages.IsGetterName(method):
+			b.addGetter(method)
+		case isMethod && len(args) == 1 && languages.IsSetterName(method):
+			b.addGetter(method)
+		default:
+			other = true
+		}
+	}
+	if other {
+		b.getters = nil
+	}
+}
+
+// addGetter records an accessor the first time it is called
+func (b *testBlock) addGetter(name string) {
+	for _, getter := range b.getters {
+		if getter == name {
+			return
+		}
+	}
+	b.getters = append(b.getters, name)
+}
+
+// testTokens splits masked code into names, numbers and symbols, with every number reduced to 0
+func testTokens(code string) []string {
+	tokens := testTokenPattern.FindAllString(code, -1)
+	for i, token := range tokens {
+		if token[0] >= '0' && token[0] <= '9' {
+			tokens[i] = "0"
+		}
+	}
+	return tokens
+}
+
+// sortedSet returns the keys of a set, sorted and joined with spaces
+func sortedSet(set map[string]bool) string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, " ")
+}
+
+// AssertionFreeTestRule detects tests that can only fail by throwing unexpectedly. The analyzer
+// applies the test quality rules to test files only.
+type AssertionFreeTestRule struct {
+	config core.Config
+}
+
+func NewAssertionFreeTestRule(config core.Config) *AssertionFreeTestRule {
+	return &AssertionFreeTestRule{config: config}
+}
+
+func (r *AssertionFreeTestRule) ID() string   { return languages.AssertionFreeTestRuleID }
+func (r *AssertionFreeTestRule) Name() string { return "Assertion-Free Test" }
+func (r *AssertionFreeTestRule) Description() string {
+	return "Detects it() and test() blocks without expect, assert, a throwing query or a Cypress command"
+}
+func (r *AssertionFreeTestRule) Category() core.RuleCategory { return core.CategoryBug }
+func (r *AssertionFreeTestRule) Severity() core.Severity     { return core.SeverityWarning }
+
+func (r *AssertionFreeTestRule) Rationale() string {
+	return "A test that renders a component or calls a function without checking the result passes " +
+		"whatever the code does. It adds to the coverage figure while catching nothing but exceptions, " +
+		"and generated suites contain many of them because rendering is easier than deciding what should appear."
+}
+
+func (r *AssertionFreeTestRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `it('renders the profile', () => {
+  render(<Profile user={user} />);
+});`,
+		Good: `it('renders the profile', () => {
+  render(<Profile user={user} />);
+  expect(screen.getByText('Ada')).toBeTruthy();
+});`,
+	}}
+}
+
+func (r *AssertionFreeTestRule) Options() []core.RuleOption { return core.TestQualityOptions() }
+
+func (r *AssertionFreeTestRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// CheckSource reports each test block that never asserts anything
+func (r *AssertionFreeTestRule) CheckSource(code []string) []core.Result {
+	var results []core.Result
+	for _, block := range findTestBlocks(code) {
+		if block.asserts {
+			continue
+		}
+		results = append(results, core.Result{
+			RuleID:     r.ID(),
+			RuleName:   r.Name(),
+			Category:   string(r.Category()),
+			Severity:   string(r.Severity()),
+			Line:       block.line,
+			Message:    "Test never asserts anything, so it passes whatever the code does",
+			Suggestion: "Check the rendered output or the returned value with expect",
+		})
+	}
+	return results
+}
+
+// DuplicateTestRule detects tests that repeat an earlier test of the same file
+type DuplicateTestRule struct {
+	config core.Config
+}
+
+func NewDuplicateTestRule(config core.Config) *DuplicateTestRule {
+	return &DuplicateTestRule{config: config}
+}
+
+func (r *DuplicateTestRule) ID() string   { return languages.DuplicateTestRuleID }
+func (r *DuplicateTestRule) Name() string { return "Duplicate Test" }
+func (r *DuplicateTestRule) Description() string {
+	return "Detects tests that call the same functions as an earlier test with nearly the same code"
+}
+func (r *DuplicateTestRule) Category() core.RuleCategory { return core.CategoryStyle }
+func (r *DuplicateTestRule) Severity() core.Severity     { return core.SeverityInfo }
+
+func (r *DuplicateTestRule) Rationale() string {
+	return "Generated tests are often one test copied for every input, with only the values changed. " +
+		"Each copy has to be updated when the code changes, and a long run of them hides which cases " +
+		"are actually covered. test.each states the cases once and the checks once."
+}
+
+func (r *DuplicateTestRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `test('adds positive numbers', () => {
+  expect(add(1, 2)).toBe(3);
+});
+test('adds negative numbers', () => {
+  expect(add(-1, -2)).toBe(-3);
+});`,
+		Good: `test.each([[1, 2, 3], [-1, -2, -3]])('add(%i, %i) is %i', (a, b, sum) => {
+  expect(add(a, b)).toBe(sum);
+});`,
+	}}
+}
+
+func (r *DuplicateTestRule) Options() []core.RuleOption { return core.DuplicateTestOptions() }
+
+func (r *DuplicateTestRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// CheckSource reports each test block that nearly repeats an earlier one
+func (r *DuplicateTestRule) CheckSource(code []string) []core.Result {
+	blocks := findTestBlocks(code)
+	bodies := make([]languages.TestBody, len(blocks))
+	for i, block := range blocks {
+		bodies[i] = block.body
+	}
+
+	var results []core.Result
+	for i, duplicate := range languages.FindDuplicateTests(bodies, r.config.Rules.TestQuality.Threshold) {
+		if duplicate.Index < 0 {
+			continue
+		}
+		results = append(results, core.Result{
+			RuleID:     r.ID(),
+			RuleName:   r.Name(),
+			Category:   string(r.Category()),
+			Severity:   string(r.Severity()),
+			Line:       blocks[i].line,
+			Message:    fmt.Sprintf("Test repeats the test on line %d with %.0f%% of the same code", blocks[duplicate.Index].line, duplicate.Similarity*100),
+			Suggestion: "Merge the tests into one test.each table with a row for each input",
+		})
+	}
+	return results
+}
+
+// TrivialGetterTestRule detects tests that only call getters and setters
+type TrivialGetterTestRule struct {
+	config core.Config
+}
+
+func NewTrivialGetterTestRule(config core.Config) *TrivialGetterTestRule {
+	return &TrivialGetterTestRule{config: config}
+}
+
+func (r *TrivialGetterTestRule) ID() string   { return languages.TrivialTestRuleID }
+func (r *TrivialGetterTestRule) Name() string { return "Trivial Getter Test" }
+func (r *TrivialGetterTestRule) Description() string {
+	return "Detects tests whose only calls besides constructors and assertions are get*, is*, has* and set* methods"
+}
+func (r *TrivialGetterTestRule) Category() core.RuleCategory { return core.CategoryStyle }
+func (r *TrivialGetterTestRule) Severity() core.Severity     { return core.SeverityInfo }
+
+func (r *TrivialGetterTestRule) Rationale() string {
+	return "Checking that a getter returns the value the constructor was given tests the language, not " +
+		"the code. Such tests are the easiest way to raise coverage and generated suites are full of " +
+		"them, but they only fail when a property is renamed, and the time is better spent on the logic."
+}
+
+func (r *TrivialGetterTestRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `test('user name', () => {
+  const user = new User('ada');
+  expect(user.getName()).toBe('ada');
+});`,
+		Good: `test('rename rejects an empty name', () => {
+  const user = new User('ada');
+  expect(() => user.rename('')).toThrow();
+});`,
+	}}
+}
+
+func (r *TrivialGetterTestRule) Options() []core.RuleOption { return core.TestQualityOptions() }
+
+func (r *TrivialGetterTestRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	return nil
+}
+
+// CheckSource reports each test block that asserts on nothing but accessors
+func (r *TrivialGetterTestRule) CheckSource(code []string) []core.Result {
+	var results []core.Result
+	for _, block := range findTestBlocks(code) {
+		if !block.asserts || len(block.getters) == 0 {
+			continue
+		}
+		results = append(results, core.Result{
+			RuleID:     r.ID(),
+			RuleName:   r.Name(),
+			Category:   string(r.Category()),
+			Severity:   string(r.Severity()),
+			Line:       block.line,
+			Message:    fmt.Sprintf("Test only exercises the accessors %s", strings.Join(block.getters, ", ")),
+			Suggestion: "Test the behaviour that uses these values, or drop the test if the accessors have no logic",
+		})
+	}
+	return results
+}
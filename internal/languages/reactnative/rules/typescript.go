@@ -0,0 +1,167 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// TypeScriptSource is a TypeScript file as seen by the type safety rules: Lines is the raw
+// source, Code the same lines with comments and string contents masked out
+type TypeScriptSource struct {
+	Lines []string
+	Code  []string
+}
+
+var (
+	// anyAnnotationPattern matches 'any' used as a type: after ':', or as a type argument
+	anyAnnotationPattern = regexp.MustCompile(`(?:[:<|&]|,)\s*any\b\s*(?:\[\s*\])*\s*(?:[,;)>=|&{}\]]|$)`)
+	anyCastPattern       = regexp.MustCompile(`\bas\s+any\b`)
+	suppressionPattern   = regexp.MustCompile(`(?://|/\*|^\s*\*)\s*@ts-(?:ignore|expect-error|nocheck)\b`)
+)
+
+// AnyTypeRule detects files that use 'any' annotations and casts beyond a threshold
+type AnyTypeRule struct {
+	config core.Config
+}
+
+func NewAnyTypeRule(config core.Config) *AnyTypeRule {
+	return &AnyTypeRule{config: config}
+}
+
+func (r *AnyTypeRule) ID() string                  { return "any-type" }
+func (r *AnyTypeRule) Name() string                { return "Any Type" }
+func (r *AnyTypeRule) Description() string         { return "Detects overuse of the 'any' type" }
+func (r *AnyTypeRule) Category() core.RuleCategory { return core.CategoryStyle }
+func (r *AnyTypeRule) Severity() core.Severity     { return core.SeverityWarning }
+
+func (r *AnyTypeRule) Rationale() string {
+	return "Every any annotation or as any cast switches off type checking for the values that pass " +
+		"through it, and the hole spreads to everything they are assigned to. A few are sometimes " +
+		"unavoidable, so the rule reports files that use more than the configured number. Only .ts " +
+		"and .tsx files are checked."
+}
+
+func (r *AnyTypeRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `function parse(body: any): any {
+  return (body as any).items;
+}`,
+		Good: `function parse(body: unknown): Item[] {
+  return isItemList(body) ? body.items : [];
+}`,
+	}}
+}
+
+func (r *AnyTypeRule) Options() []core.RuleOption {
+	return []core.RuleOption{
+		{Key: "rules.typeSafety.enabled", Flag: "-enable-type-safety", Default: "true", Description: "Run the TypeScript type safety rules"},
+		{Key: "rules.typeSafety.maxAny", Flag: "-max-any", Default: "5", Description: "Most 'any' annotations and casts allowed per file"},
+	}
+}
+
+// Check counts 'any' annotations and casts, and reports the file at the first use past the limit
+func (r *AnyTypeRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*TypeScriptSource)
+	if !ok {
+		return nil
+	}
+
+	maxAny := config.Rules.TypeSafety.MaxAny
+	annotations, casts, line := 0, 0, 0
+	for i, code := range n.Code {
+		c := len(anyCastPattern.FindAllStringIndex(code, -1))
+		// A cast ends in 'any' followed by a delimiter, so it also matches as an annotation
+		a := len(anyAnnotationPattern.FindAllStringIndex(anyCastPattern.ReplaceAllString(code, ""), -1))
+		annotations += a
+		casts += c
+		if line == 0 && annotations+casts > maxAny {
+			line = i + 1
+		}
+	}
+	if line == 0 {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       line,
+		Message:    fmt.Sprintf("File uses 'any' %d times (%d annotations, %d casts, max %d)", annotations+casts, annotations, casts, maxAny),
+		Suggestion: "Replace 'any' with a specific type, a generic, or 'unknown' narrowed before use",
+	}
+}
+
+// TSSuppressionRule detects files that suppress type errors beyond a threshold
+type TSSuppressionRule struct {
+	config core.Config
+}
+
+func NewTSSuppressionRule(config core.Config) *TSSuppressionRule {
+	return &TSSuppressionRule{config: config}
+}
+
+func (r *TSSuppressionRule) ID() string                  { return "ts-suppression" }
+func (r *TSSuppressionRule) Name() string                { return "TypeScript Suppression" }
+func (r *TSSuppressionRule) Description() string         { return "Detects @ts-ignore comments" }
+func (r *TSSuppressionRule) Category() core.RuleCategory { return core.CategoryStyle }
+func (r *TSSuppressionRule) Severity() core.Severity     { return core.SeverityWarning }
+
+func (r *TSSuppressionRule) Rationale() string {
+	return "@ts-ignore, @ts-expect-error and @ts-nocheck hide type errors instead of fixing them, and " +
+		"@ts-ignore keeps hiding whatever error appears on that line later. The rule reports files " +
+		"with more suppressions than the configured number. Only .ts and .tsx files are checked."
+}
+
+func (r *TSSuppressionRule) Examples() []core.RuleExample {
+	return []core.RuleExample{{
+		Bad: `// @ts-ignore
+navigation.navigate('Details', { id });`,
+		Good: `navigation.navigate('Details', { id } satisfies RootParams['Details']);`,
+	}}
+}
+
+func (r *TSSuppressionRule) Options() []core.RuleOption {
+	return []core.RuleOption{
+		{Key: "rules.typeSafety.enabled", Flag: "-enable-type-safety", Default: "true", Description: "Run the TypeScript type safety rules"},
+		{Key: "rules.typeSafety.maxSuppressions", Flag: "-max-ts-suppressions", Default: "2", Description: "Most @ts-ignore, @ts-expect-error and @ts-nocheck comments allowed per file"},
+	}
+}
+
+// Check counts @ts-ignore, @ts-expect-error and @ts-nocheck comments, and reports the file at
+// the first one past the limit
+func (r *TSSuppressionRule) Check(ctx context.Context, node interface{}, config core.Config) *core.Result {
+	n, ok := node.(*TypeScriptSource)
+	if !ok {
+		return nil
+	}
+
+	maxSuppressions := config.Rules.TypeSafety.MaxSuppressions
+	count, line := 0, 0
+	for i, text := range n.Lines {
+		if !suppressionPattern.MatchString(text) {
+			continue
+		}
+		count++
+		if line == 0 && count > maxSuppressions {
+			line = i + 1
+		}
+	}
+	if line == 0 {
+		return nil
+	}
+
+	return &core.Result{
+		RuleID:     r.ID(),
+		RuleName:   r.Name(),
+		Category:   string(r.Category()),
+		Severity:   string(r.Severity()),
+		Line:       line,
+		Message:    fmt.Sprintf("File suppresses type checking %d times (max %d)", count, maxSuppressions),
+		Suggestion: "Fix the underlying type errors; keep any remaining @ts-expect-error with a comment explaining why",
+	}
+}
@@ -0,0 +1,126 @@
+package reactnative
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/reactnative/rules"
+)
+
+// analyzeStream analyzes a file above the streaming threshold in one pass, keeping only the
+// last maxWindowLines lines: the lines are counted as ParseFile counts them, the line rules run
+// on each line once the lines that may finish its window have been read, and comments are
+// checked as they are found, against the code before them on their line. The function,
+// component, type and multi-line rules need the whole file and are skipped. Reading stops at
+// the first line longer than the maximum line length, as the file is minified.
+func (a *Analyzer) analyzeStream(ctx context.Context, filePath string, config core.Config) ([]core.Result, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	parsed := a.parser.newParsedFile()
+	state := &parseState{}
+	s := &lineStream{
+		masker:    languages.NewMasker(languages.JavaScript),
+		lineRules: a.enabledLineRules(filePath, config),
+		reported:  make(languages.LineFindings),
+		filePath:  filePath,
+	}
+	var header []string
+
+	scanner := languages.NewLineReader(file, config.Parsing.MaxLineLength)
+	for scanner.Scan() {
+		if long := scanner.LongLines(); len(long) > 0 {
+			return []core.Result{languages.MinifiedFileResult(filePath, long[0], config.Parsing.MaxLineLength)}, nil
+		}
+		state.lineNum++
+		line := scanner.Text()
+		parsed.TotalLines++
+		if len(header) < languages.GeneratedHeaderLines {
+			header = append(header, line)
+		}
+
+		a.parser.processLine(line, state, parsed)
+		for _, comment := range parsed.Comments {
+			s.results = a.checkComment(ctx, s.results, comment, line, filePath, config)
+		}
+		parsed.Comments = parsed.Comments[:0]
+
+		s.push(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse file %s: %w", filePath, err)
+	}
+	for len(s.lines) > 0 {
+		s.checkFirst()
+	}
+
+	metrics := a.parser.CalculateFileMetrics(ctx, filePath, parsed)
+	metrics.Generated = languages.IsGenerated(header)
+	return append(a.applyFileRules(ctx, nil, metrics, filePath, config), s.results...), nil
+}
+
+// lineStream runs the line rules over the lines of a file as they are read, holding back each
+// line until the lines that may finish its window have been read
+type lineStream struct {
+	masker    *languages.Masker
+	lineRules []rules.LineCheckRule
+	reported  languages.LineFindings
+	filePath  string
+
+	lines   []string // the lines held back, raw
+	code    []string // the lines held back, masked
+	read    int      // the number of lines read
+	results []core.Result
+}
+
+// push adds the next line of the file, checking the oldest line held back once its window is
+// complete
+func (s *lineStream) push(line string) {
+	s.read++
+	s.lines = append(s.lines, line)
+	s.code = append(s.code, s.masker.Mask(line))
+	if len(s.lines) == maxWindowLines {
+		s.checkFirst()
+	}
+}
+
+// checkFirst runs the line rules on the oldest line held back and drops it
+func (s *lineStream) checkFirst() {
+	lineNum := s.read - len(s.lines) + 1
+	window, codeWindow := windowAt(s.lines, 0), windowAt(s.code, 0)
+	for _, rule := range s.lineRules {
+		line, w := s.code[0], codeWindow
+		if languages.ReadsStrings(rule) {
+			line, w = s.lines[0], window
+		}
+		if result := checkWindow(rule, line, w, lineNum); result != nil && s.reported.Add(result) {
+			s.results = append(s.results, lineResult(result, s.filePath))
+		}
+	}
+	s.lines, s.code = s.lines[1:], s.code[1:]
+}
+
+// checkComment runs the comment rules on a comment found while streaming a file, with the code
+// before it on its line
+func (a *Analyzer) checkComment(ctx context.Context, results []core.Result, comment Comment, line, filePath string, config core.Config) []core.Result {
+	commentLine := &rules.CommentLine{Text: comment.Text, Line: comment.Line}
+	if comment.IsInline {
+		commentLine.Code = inlineCommentCode(line, comment)
+	}
+	for _, rule := range a.rules {
+		if !isRuleEnabled(rule, config) || !isCommentRule(rule) {
+			continue
+		}
+		if result := rule.Check(ctx, commentLine, config); result != nil {
+			result.FilePath = filePath
+			results = append(results, *result)
+		}
+	}
+	return results
+}
@@ -3,7 +3,10 @@ package reactnative
 import (
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
 )
 
 // ParsedFile represents a parsed JavaScript/TypeScript file
@@ -20,6 +23,14 @@ type ParsedFile struct {
 	CodeLines    int
 	CommentLines int
 	BlankLines   int
+	SyntaxError  *SyntaxError // set when braces do not balance
+	LongLines    []int        // lines truncated to the maximum line length
+}
+
+// SyntaxError describes where the structure of a file breaks down
+type SyntaxError struct {
+	Line    int
+	Message string
 }
 
 // FunctionDef represents a function definition
@@ -55,6 +66,9 @@ type ComponentDef struct {
 	IsFunctional bool
 	IsExported  bool
 	HasHooks    bool
+	HookCount   int // hook calls, including custom hooks
+	PropCount   int // distinct props destructured
+	JSXDepth    int // deepest nesting of the JSX elements it renders
 }
 
 // ImportStmt represents an import statement
@@ -94,15 +108,20 @@ type VariableDef struct {
 
 type cachedFile struct {
 	parsed   *ParsedFile
-	modTime  time.Time
+	modTime  time.Time // modification time of the file when it was parsed
+	cachedAt time.Time
 	filePath string
 }
 
-// Cache holds cached parsed files
+// Cache holds cached parsed files. It is safe for concurrent use.
 type Cache struct {
-	cache  map[string]*cachedFile
-	mu     sync.RWMutex
-	maxAge time.Duration
+	cache     map[string]*cachedFile
+	lru       *languages.LRU
+	mu        sync.RWMutex
+	maxAge    time.Duration
+	hits      int64
+	misses    int64
+	evictions int64
 }
 
 func NewCache(maxAge time.Duration) *Cache {
@@ -111,27 +130,75 @@ func NewCache(maxAge time.Duration) *Cache {
 	}
 	return &Cache{
 		cache:  make(map[string]*cachedFile),
+		lru:    languages.NewLRU(0),
 		maxAge: maxAge,
 	}
 }
 
-func (c *Cache) Get(filePath string) (*ParsedFile, bool) {
+// SetMaxEntries caps the number of files cached, evicting the least recently used ones beyond
+// it; 0, the default, caches any number
+func (c *Cache) SetMaxEntries(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evict(c.lru.SetCapacity(n))
+}
+
+// Stats returns the number of files cached and counts of the lookups so far
+func (c *Cache) Stats() languages.CacheStats {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
+	return languages.CacheStats{
+		Entries:   len(c.cache),
+		Hits:      atomic.LoadInt64(&c.hits),
+		Misses:    atomic.LoadInt64(&c.misses),
+		Evictions: atomic.LoadInt64(&c.evictions),
+	}
+}
+
+// evict drops the entries the LRU has let go of; c.mu must be held
+func (c *Cache) evict(filePaths []string) {
+	for _, filePath := range filePaths {
+		delete(c.cache, filePath)
+	}
+	atomic.AddInt64(&c.evictions, int64(len(filePaths)))
+}
 
+func (c *Cache) Get(filePath string) (*ParsedFile, bool) {
+	c.mu.RLock()
 	cached, exists := c.cache[filePath]
-	if !exists {
-		return nil, false
+	c.mu.RUnlock()
+
+	fresh := false
+	if exists && time.Since(cached.cachedAt) <= c.maxAge {
+		stat, err := os.Stat(filePath)
+		fresh = err == nil && stat.ModTime().Equal(cached.modTime)
 	}
 
-	if time.Since(cached.modTime) > c.maxAge {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !exists || c.cache[filePath] != cached {
+		atomic.AddInt64(&c.misses, 1)
+		return nil, false
+	}
+	if !fresh {
 		delete(c.cache, filePath)
+		c.lru.Remove(filePath)
+		atomic.AddInt64(&c.misses, 1)
 		return nil, false
 	}
-
+	c.lru.Touch(filePath)
+	atomic.AddInt64(&c.hits, 1)
 	return cached.parsed, true
 }
 
+// Clear removes every parsed file from the cache
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache = make(map[string]*cachedFile)
+	c.lru.Clear()
+}
+
 func (c *Cache) Set(filePath string, parsed *ParsedFile) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -144,6 +211,8 @@ func (c *Cache) Set(filePath string, parsed *ParsedFile) {
 	c.cache[filePath] = &cachedFile{
 		parsed:   parsed,
 		modTime:  stat.ModTime(),
+		cachedAt: time.Now(),
 		filePath: filePath,
 	}
+	c.evict(c.lru.Touch(filePath))
 }
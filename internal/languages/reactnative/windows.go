@@ -0,0 +1,93 @@
+package reactnative
+
+import (
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/reactnative/rules"
+)
+
+// maxWindowLines is the most physical lines a line window joins, so a construct left open by a
+// syntax error does not pull in the rest of the file
+const maxWindowLines = 5
+
+// expressionOpeners are the characters before a brace that make it open an object literal or a
+// JSX expression rather than a block, as in style={ or onPress={
+const expressionOpeners = "=({[:,"
+
+// lineWindow is a physical line joined with the lines that finish its expression, so that line
+// rules see style={ followed by an object on the next line, or onPress={ followed by an arrow
+// function, as one piece of code
+type lineWindow struct {
+	text string // the line and its continuation lines, joined by spaces
+	rest string // the continuation lines alone, "" when the line stands alone
+}
+
+// lineWindows returns the window of each line. A line continues on the next one when it ends
+// with an operator or an arrow, with a bracket or an expression brace it opens, or when the next
+// line starts with an operator or a method call. Comment lines stand alone.
+func lineWindows(lines []string) []lineWindow {
+	windows := make([]lineWindow, len(lines))
+	for i := range lines {
+		windows[i] = windowAt(lines, i)
+	}
+	return windows
+}
+
+// windowAt returns the window of line i, which needs only the maxWindowLines-1 lines after it
+func windowAt(lines []string, i int) lineWindow {
+	line := lines[i]
+	window := lineWindow{text: line}
+	tail := strings.TrimSpace(line)
+	if isCommentLine(tail) {
+		return window
+	}
+
+	var rest []string
+	for k := i + 1; k < len(lines) && k-i < maxWindowLines; k++ {
+		next := strings.TrimSpace(lines[k])
+		if isCommentLine(next) || !continuesOnNextLine(tail) && !hasAnyPrefix(next, continuationPrefixes) {
+			break
+		}
+		if next == "" {
+			continue
+		}
+		rest = append(rest, next)
+		tail = next
+	}
+	if len(rest) > 0 {
+		window.rest = strings.Join(rest, " ")
+		window.text = strings.TrimRight(line, " \t") + " " + window.rest
+	}
+	return window
+}
+
+// continuesOnNextLine reports whether a trimmed line leaves an expression for the next line to
+// finish
+func continuesOnNextLine(trimmed string) bool {
+	if hasAnySuffix(trimmed, continuationSuffixes) || strings.HasSuffix(trimmed, "(") || strings.HasSuffix(trimmed, "[") {
+		return true
+	}
+	if !strings.HasSuffix(trimmed, "{") {
+		return false
+	}
+	before := strings.TrimRight(strings.TrimSuffix(trimmed, "{"), " \t")
+	return before != "" && strings.IndexByte(expressionOpeners, before[len(before)-1]) >= 0
+}
+
+func isCommentLine(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*")
+}
+
+// checkWindow runs a line rule on a line, and failing that on its window. A match found in the
+// continuation lines alone belongs to the line it starts on, which reports it from its own
+// window, so it is not reported twice.
+func checkWindow(rule rules.LineCheckRule, line string, window lineWindow, lineNum int) *core.Result {
+	if result := rule.CheckLine(line, lineNum); result != nil || window.rest == "" {
+		return result
+	}
+	if rule.CheckLine(window.rest, lineNum) != nil {
+		return nil
+	}
+	return rule.CheckLine(window.text, lineNum)
+}
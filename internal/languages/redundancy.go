@@ -0,0 +1,143 @@
+package languages
+
+import (
+	"strings"
+	"unicode"
+)
+
+// commentStopwords carry no information about what code does and are ignored when comparing
+var commentStopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "to": true, "of": true, "and": true, "or": true,
+	"is": true, "are": true, "be": true, "this": true, "that": true, "it": true, "its": true,
+	"we": true, "our": true, "then": true, "with": true, "by": true, "on": true, "in": true,
+	"at": true, "from": true, "as": true, "now": true, "here": true, "so": true,
+}
+
+// operatorWords translates operators into the words comments use to describe them
+var operatorWords = []struct {
+	op    string
+	words []string
+}{
+	{"++", []string{"increment", "add"}},
+	{"--", []string{"decrement", "subtract"}},
+	{"+=", []string{"add", "increment"}},
+	{"-=", []string{"subtract", "decrement"}},
+	{"==", []string{"equal", "check"}},
+	{"!=", []string{"not", "equal", "check"}},
+	{"<=", []string{"less", "compare", "check"}},
+	{">=", []string{"greater", "compare", "check"}},
+	{":=", []string{"set", "assign", "create"}},
+	{"=", []string{"set", "assign"}},
+}
+
+// CommentRedundancy returns the fraction of a comment's meaningful words that also appear in
+// the code it describes, from 0 (unrelated) to 1 (the comment only restates the code).
+// Comments with fewer than two meaningful words score 0, as there is too little to compare.
+func CommentRedundancy(comment, code string) float64 {
+	commentWords := uniqueWords(tokenize(stripCommentMarker(comment)))
+	if len(commentWords) < 2 {
+		return 0
+	}
+
+	codeWords := make(map[string]bool)
+	for _, word := range tokenize(code) {
+		codeWords[word] = true
+	}
+	for _, ow := range operatorWords {
+		if strings.Contains(code, ow.op) {
+			for _, word := range ow.words {
+				codeWords[word] = true
+			}
+			code = strings.ReplaceAll(code, ow.op, " ")
+		}
+	}
+
+	matched := 0
+	for word := range commentWords {
+		if codeWords[word] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(commentWords))
+}
+
+// tokenize splits text into lowercase, lightly stemmed words, breaking identifiers on
+// camelCase and snake_case boundaries and dropping stopwords
+func tokenize(text string) []string {
+	var words []string
+	var current []rune
+
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		word := stem(strings.ToLower(string(current)))
+		if !commentStopwords[word] {
+			words = append(words, word)
+		}
+		current = current[:0]
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			// Split fooBar and HTTPServer style boundaries
+			if unicode.IsUpper(r) && len(current) > 0 {
+				prev := current[len(current)-1]
+				nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if unicode.IsLower(prev) || (unicode.IsUpper(prev) && nextLower) {
+					flush()
+				}
+			}
+			current = append(current, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words
+}
+
+// stem strips common English suffixes so "increments", "incremented" and "incrementing" match
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "ed"} {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 3 {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	if strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && len(word) > 3 {
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+func uniqueWords(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
+
+// CodeAfter returns the first non-blank line after the 1-based line number that is not itself
+// a comment, trimmed of surrounding whitespace. It returns "" when no such line exists.
+func CodeAfter(lines []string, line int, commentPrefixes ...string) string {
+	for i := line; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" {
+			continue
+		}
+		isComment := false
+		for _, prefix := range commentPrefixes {
+			if strings.HasPrefix(trimmed, prefix) {
+				isComment = true
+				break
+			}
+		}
+		if !isComment {
+			return trimmed
+		}
+	}
+	return ""
+}
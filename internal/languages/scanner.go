@@ -2,6 +2,7 @@ package languages
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,6 +12,17 @@ import (
 type MultiScanner struct {
 	registry   *Registry
 	ignoreDirs []string
+	extensions map[string]string // extension remapping, see SetExtensionMap
+	filter     *PathFilter       // include and exclude globs, see SetPathFilter
+
+	recordSkipped bool          // keep the paths that are not analyzed, see RecordSkipped
+	skipped       []SkippedPath // paths skipped since recording started
+}
+
+// SkippedPath is a file or directory the scanner did not select for analysis
+type SkippedPath struct {
+	Path   string
+	Reason string
 }
 
 // NewMultiScanner creates a new multi-language file scanner
@@ -57,32 +69,13 @@ func (s *MultiScanner) Scan(ctx context.Context, rootPath string) (map[string][]
 
 		// Skip directories
 		if info.IsDir() {
-			// Skip ignored directories
-			for _, ignoreDir := range s.ignoreDirs {
-				if info.Name() == ignoreDir {
-					return filepath.SkipDir
-				}
-			}
-			return nil
+			return s.handleDirectory(rootPath, path, info)
 		}
-
-		// Get file extension
-		ext := filepath.Ext(path)
-		if ext == "" {
+		if !s.selected(rootPath, path) {
 			return nil
 		}
 
-		// Find analyzer for this extension
-		analyzer, exists := s.registry.GetAnalyzerByExtension(ext)
-		if !exists {
-			return nil
-		}
-
-		// Group file by language
-		language := analyzer.Name()
-		filesByLanguage[language] = append(filesByLanguage[language], path)
-
-		return nil
+		return s.addFileToLanguageMap(path, filesByLanguage)
 	})
 
 	return filesByLanguage, err
@@ -98,6 +91,91 @@ func (s *MultiScanner) SetIgnoreDirs(dirs []string) {
 	s.ignoreDirs = dirs
 }
 
+// IncludeVendored stops skipping vendor directories, so the dependencies copied into them are
+// analyzed too. node_modules is still skipped: it is installed rather than checked in.
+func (s *MultiScanner) IncludeVendored() {
+	dirs := make([]string, 0, len(s.ignoreDirs))
+	for _, dir := range s.ignoreDirs {
+		if dir != "vendor" {
+			dirs = append(dirs, dir)
+		}
+	}
+	s.ignoreDirs = dirs
+}
+
+// RecordSkipped makes the scanner keep the files and directories it skips, with the reason,
+// until Skipped is called
+func (s *MultiScanner) RecordSkipped(enabled bool) {
+	s.recordSkipped = enabled
+}
+
+// Skipped returns the paths skipped since the last call, in the order they were met
+func (s *MultiScanner) Skipped() []SkippedPath {
+	skipped := s.skipped
+	s.skipped = nil
+	return skipped
+}
+
+func (s *MultiScanner) skip(path, reason string) {
+	if s.recordSkipped {
+		s.skipped = append(s.skipped, SkippedPath{Path: path, Reason: reason})
+	}
+}
+
+// SetPathFilter restricts scanning to files matching the include globs and not matching the
+// exclude globs, see PathFilter. Paths are relative to the scanned directory, or to the working
+// directory for files passed to GroupFiles.
+func (s *MultiScanner) SetPathFilter(include, exclude []string) error {
+	if len(include) == 0 && len(exclude) == 0 {
+		s.filter = nil
+		return nil
+	}
+	filter, err := NewPathFilter(include, exclude)
+	if err != nil {
+		return err
+	}
+	s.filter = filter
+	return nil
+}
+
+// selected reports whether the path filter keeps the file at path under rootPath
+func (s *MultiScanner) selected(rootPath, path string) bool {
+	if s.filter == nil {
+		return true
+	}
+	rel, err := filepath.Rel(rootPath, path)
+	if err != nil {
+		rel = path
+	}
+	if s.filter.Match(rel) {
+		return true
+	}
+	if s.filter.excluded(rel) {
+		s.skip(path, "matches an exclude glob")
+	} else {
+		s.skip(path, "matches no include glob")
+	}
+	return false
+}
+
+// SetExtensionMap routes files with the given extensions to other languages, e.g. ".mjs" to
+// "javascript" or ".pyi" to "python". Language names are those accepted by LanguageExtension.
+func (s *MultiScanner) SetExtensionMap(extensions map[string]string) error {
+	mapped := make(map[string]string, len(extensions))
+	for ext, language := range extensions {
+		target, ok := LanguageExtension(language)
+		if !ok {
+			return fmt.Errorf("unknown language %q for extension %q", language, ext)
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		mapped[ext] = target
+	}
+	s.extensions = mapped
+	return nil
+}
+
 // ScanWithFilter scans a directory with a custom filter function
 func (s *MultiScanner) ScanWithFilter(ctx context.Context, rootPath string, filter func(path string) bool) (map[string][]string, error) {
 	filesByLanguage := make(map[string][]string)
@@ -106,14 +184,14 @@ func (s *MultiScanner) ScanWithFilter(ctx context.Context, rootPath string, filt
 		if err != nil {
 			return err
 		}
-		return s.processFileWithFilter(ctx, path, info, filter, filesByLanguage)
+		return s.processFileWithFilter(ctx, rootPath, path, info, filter, filesByLanguage)
 	})
 
 	return filesByLanguage, err
 }
 
 // processFileWithFilter processes a single file during filtered scanning
-func (s *MultiScanner) processFileWithFilter(ctx context.Context, path string, info os.FileInfo, filter func(path string) bool, filesByLanguage map[string][]string) error {
+func (s *MultiScanner) processFileWithFilter(ctx context.Context, rootPath, path string, info os.FileInfo, filter func(path string) bool, filesByLanguage map[string][]string) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
@@ -121,10 +199,10 @@ func (s *MultiScanner) processFileWithFilter(ctx context.Context, path string, i
 	}
 
 	if info.IsDir() {
-		return s.handleDirectory(info)
+		return s.handleDirectory(rootPath, path, info)
 	}
 
-	if filter != nil && !filter(path) {
+	if !s.selected(rootPath, path) || (filter != nil && !filter(path)) {
 		return nil
 	}
 
@@ -132,9 +210,16 @@ func (s *MultiScanner) processFileWithFilter(ctx context.Context, path string, i
 }
 
 // handleDirectory checks if directory should be skipped
-func (s *MultiScanner) handleDirectory(info os.FileInfo) error {
+func (s *MultiScanner) handleDirectory(rootPath, path string, info os.FileInfo) error {
 	for _, ignoreDir := range s.ignoreDirs {
 		if info.Name() == ignoreDir {
+			s.skip(path, "ignored directory")
+			return filepath.SkipDir
+		}
+	}
+	if s.filter != nil {
+		if rel, err := filepath.Rel(rootPath, path); err == nil && s.filter.SkipDir(rel) {
+			s.skip(path, "directory matches an exclude glob")
 			return filepath.SkipDir
 		}
 	}
@@ -143,13 +228,15 @@ func (s *MultiScanner) handleDirectory(info os.FileInfo) error {
 
 // addFileToLanguageMap adds a file to the language map if it has a supported extension
 func (s *MultiScanner) addFileToLanguageMap(path string, filesByLanguage map[string][]string) error {
-	ext := filepath.Ext(path)
+	ext := s.fileExtension(path)
 	if ext == "" {
+		s.skip(path, "no extension, shebang or mode line naming a supported language")
 		return nil
 	}
 
 	analyzer, exists := s.registry.GetAnalyzerByExtension(ext)
 	if !exists {
+		s.skip(path, "unsupported extension "+ext)
 		return nil
 	}
 
@@ -158,6 +245,25 @@ func (s *MultiScanner) addFileToLanguageMap(path string, filesByLanguage map[str
 	return nil
 }
 
+// GroupFiles groups an explicit list of files by language, dropping unsupported files and
+// those the path filter excludes
+func (s *MultiScanner) GroupFiles(paths []string) map[string][]string {
+	wd, _ := os.Getwd()
+	return s.GroupFilesUnder(wd, paths)
+}
+
+// GroupFilesUnder groups files like GroupFiles, matching the path filter against their paths
+// relative to root rather than to the working directory
+func (s *MultiScanner) GroupFilesUnder(root string, paths []string) map[string][]string {
+	filesByLanguage := make(map[string][]string)
+	for _, path := range paths {
+		if s.selected(root, path) {
+			s.addFileToLanguageMap(path, filesByLanguage)
+		}
+	}
+	return filesByLanguage
+}
+
 // ScanForLanguage scans a directory for files of a specific language
 func (s *MultiScanner) ScanForLanguage(ctx context.Context, rootPath string, language string) ([]string, error) {
 	analyzer, exists := s.registry.GetAnalyzer(language)
@@ -185,16 +291,10 @@ func (s *MultiScanner) ScanForLanguage(ctx context.Context, rootPath string, lan
 		}
 
 		if info.IsDir() {
-			for _, ignoreDir := range s.ignoreDirs {
-				if info.Name() == ignoreDir {
-					return filepath.SkipDir
-				}
-			}
-			return nil
+			return s.handleDirectory(rootPath, path, info)
 		}
 
-		ext := filepath.Ext(path)
-		if extSet[ext] {
+		if s.selected(rootPath, path) && extSet[s.fileExtension(path)] {
 			files = append(files, path)
 		}
 
@@ -204,17 +304,66 @@ func (s *MultiScanner) ScanForLanguage(ctx context.Context, rootPath string, lan
 	return files, err
 }
 
+// fileExtension returns the extension that decides which analyzer gets a file. A language
+// directive wins, then the extension remapping; a file without an extension is routed by its
+// shebang or mode line. Only files that would be analyzed anyway, or have no extension, are
+// read for a directive, so scanning does not open every asset in the tree.
+func (s *MultiScanner) fileExtension(path string) string {
+	ext := filepath.Ext(path)
+	if mapped, ok := s.extensions[ext]; ok {
+		ext = mapped
+	}
+	if _, known := s.registry.GetAnalyzerByExtension(ext); !known && ext != "" {
+		return ext
+	}
+
+	header := readHeader(path)
+	if directive := directiveFromHeader(header); directive != "" {
+		return directive
+	}
+	if ext == "" {
+		return detectFromHeader(header)
+	}
+	return ext
+}
+
+// Language returns the name of the analyzer a file is routed to, as Scan routes it, or "" when
+// no analyzer takes it
+func (s *MultiScanner) Language(path string) string {
+	analyzer, ok := s.registry.GetAnalyzerByExtension(s.fileExtension(path))
+	if !ok {
+		return ""
+	}
+	return analyzer.Name()
+}
+
 // IgnoreTestFiles returns a filter function that ignores test files
 func IgnoreTestFiles(language string) func(path string) bool {
 	return func(path string) bool {
-		base := filepath.Base(path)
 		switch language {
-		case "go":
-			return !strings.HasSuffix(base, "_test.go")
-		case "python":
-			return !strings.HasPrefix(base, "test_") && !strings.HasSuffix(base, "_test.py")
+		case "go", "python", "reactnative":
+			return !IsTestFile(path)
 		default:
 			return true
 		}
 	}
 }
+
+// IsTestFile reports whether a path names a test file: Go _test.go files, Python test_*.py and
+// *_test.py modules, and JavaScript/TypeScript *.test.* and *.spec.* files or files under __tests__
+func IsTestFile(path string) bool {
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+
+	switch ext {
+	case ".go":
+		return strings.HasSuffix(stem, "_test")
+	case ".py", ".pyw":
+		return strings.HasPrefix(stem, "test_") || strings.HasSuffix(stem, "_test")
+	case ".js", ".jsx", ".ts", ".tsx":
+		return strings.HasSuffix(stem, ".test") || strings.HasSuffix(stem, ".spec") ||
+			strings.Contains(filepath.ToSlash(path), "/__tests__/")
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+package languages
+
+import (
+	"os"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// ShouldStream reports whether a file is larger than the streaming threshold of config, so
+// the line-based analyzers read it in one pass instead of holding all its lines
+func ShouldStream(filePath string, config core.Config) bool {
+	if config.Parsing.StreamThreshold <= 0 {
+		return false
+	}
+	info, err := os.Stat(filePath)
+	return err == nil && info.Size() > int64(config.Parsing.StreamThreshold)
+}
@@ -0,0 +1,112 @@
+package languages
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Rule IDs of the test quality rules, which every language applies to test files only
+const (
+	AssertionFreeTestRuleID = "assertion-free-test"
+	DuplicateTestRuleID     = "duplicate-test"
+	TrivialTestRuleID       = "trivial-getter-test"
+)
+
+// minDuplicateTokens is the size below which tests are too short to call duplicates: small
+// tests of the same function look alike whatever they check
+const minDuplicateTokens = 30
+
+// IsTestQualityRule reports whether a rule ID is one of the test quality rules
+func IsTestQualityRule(id string) bool {
+	return id == AssertionFreeTestRuleID || id == DuplicateTestRuleID || id == TrivialTestRuleID
+}
+
+// TestBody is the code of a test reduced for comparison with the other tests of its file
+type TestBody struct {
+	Tokens  []string // identifiers, keywords and operators, with every literal replaced by its kind
+	Subject string   // the functions the test calls outside its assertions, sorted and joined
+}
+
+// DuplicateTest is the earlier test a test nearly repeats
+type DuplicateTest struct {
+	Index      int // of the earlier test, -1 if there is none
+	Similarity float64
+}
+
+// FindDuplicateTests returns, for each test of a file in order, the first earlier test that
+// calls the same functions and shares at least threshold of its code, see SequenceSimilarity.
+// Tests that differ only in their literals score 1.
+func FindDuplicateTests(bodies []TestBody, threshold float64) []DuplicateTest {
+	duplicates := make([]DuplicateTest, len(bodies))
+	for i, body := range bodies {
+		duplicates[i].Index = -1
+		if len(body.Tokens) < minDuplicateTokens {
+			continue
+		}
+		for j := 0; j < i; j++ {
+			other := bodies[j]
+			if other.Subject != body.Subject || len(other.Tokens) < minDuplicateTokens {
+				continue
+			}
+			shorter, longer := min(len(body.Tokens), len(other.Tokens)), max(len(body.Tokens), len(other.Tokens))
+			if 2*float64(shorter)/float64(shorter+longer) < threshold {
+				continue
+			}
+			if similarity := SequenceSimilarity(other.Tokens, body.Tokens); similarity >= threshold {
+				duplicates[i] = DuplicateTest{Index: j, Similarity: similarity}
+				break
+			}
+		}
+	}
+	return duplicates
+}
+
+// SequenceSimilarity returns the share of two token sequences taken up by their longest
+// common subsequence, from 0 for nothing in common to 1 for equal sequences
+func SequenceSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				curr[j+1] = prev[j] + 1
+			case prev[j+1] >= curr[j]:
+				curr[j+1] = prev[j+1]
+			default:
+				curr[j+1] = curr[j]
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return 2 * float64(prev[len(b)]) / float64(len(a)+len(b))
+}
+
+// IsGetterName reports whether a method name reads a property: GetName, IsEmpty, HasItems,
+// get_name, is_empty or has_items
+func IsGetterName(name string) bool {
+	for _, prefix := range []string{"get", "is", "has"} {
+		if hasWordPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSetterName reports whether a method name writes a property: SetName or set_name
+func IsSetterName(name string) bool {
+	return hasWordPrefix(name, "set")
+}
+
+// hasWordPrefix reports whether name starts with the word prefix, in any case, followed by a
+// capital letter or an underscore
+func hasWordPrefix(name, prefix string) bool {
+	if len(name) <= len(prefix) || !strings.EqualFold(name[:len(prefix)], prefix) {
+		return false
+	}
+	next := rune(name[len(prefix)])
+	return unicode.IsUpper(next) || next == '_'
+}
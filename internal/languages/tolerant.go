@@ -0,0 +1,46 @@
+package languages
+
+import (
+	"fmt"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// SyntaxErrorRuleID identifies the result reported for a file analyzed in tolerant mode
+// despite syntax errors
+const SyntaxErrorRuleID = "syntax-error"
+
+func init() {
+	core.RegisterRuleDoc(core.RuleDoc{
+		ID:          SyntaxErrorRuleID,
+		Name:        "Syntax Error",
+		Description: "Reports files with syntax errors that received only line-based checks",
+		Rationale: "In tolerant mode a file that does not parse still gets the file size and comment " +
+			"checks, but none of the checks that need its functions. The finding, at the first syntax " +
+			"error, keeps a clean report from being mistaken for a fully analyzed file.",
+		Category: core.CategoryBug,
+		Severity: core.SeverityWarning,
+		Examples: []core.RuleExample{{
+			Bad:  "def load(path)\n    return open(path).read()",
+			Good: "def load(path):\n    return open(path).read()",
+		}},
+		Options: []core.RuleOption{
+			{Key: "parsing.tolerant", Flag: "-tolerant", Default: "false", Description: "Analyze files with syntax errors using line-based rules only"},
+		},
+	})
+}
+
+// SyntaxErrorResult reports a file that did not parse cleanly. Only line-based file and
+// comment rules are applied to such files, so the result says so.
+func SyntaxErrorResult(filePath string, line int, detail string) core.Result {
+	return core.Result{
+		RuleID:     SyntaxErrorRuleID,
+		RuleName:   "Syntax Error",
+		Category:   string(core.CategoryBug),
+		Severity:   string(core.SeverityWarning),
+		FilePath:   filePath,
+		Line:       line,
+		Message:    fmt.Sprintf("File has syntax errors: %s", detail),
+		Suggestion: "Only file size and comment checks were run; fix the syntax to enable function-level checks",
+	}
+}
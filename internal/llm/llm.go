@@ -0,0 +1,143 @@
+// Package llm asks a language model behind an OpenAI-compatible chat completions API, hosted
+// or local, for a refactoring suggestion tailored to a finding. Source code is redacted before
+// it leaves the machine.
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// DefaultTimeout bounds each completion when the Client sets no Timeout, as local models can
+// be slow but should not hang a run
+const DefaultTimeout = 60 * time.Second
+
+// systemPrompt tells the model what is asked of it
+const systemPrompt = "You review code flagged by AgentLint, a linter for the bad smells common in code written by LLMs. " +
+	"Suggest a concrete refactoring that fixes the finding in the code shown. " +
+	"Answer in plain text, in at most 150 words, without repeating the finding."
+
+// Client asks a chat completions endpoint for suggestions
+type Client struct {
+	Endpoint string // the base URL of the API, such as https://api.openai.com/v1 or http://localhost:11434/v1
+	Model    string
+	APIKey   string // sent as a bearer token; "" sends none, as local servers need none
+	Redactor *Redactor
+	HTTP     *http.Client  // nil for one giving up after Timeout
+	Timeout  time.Duration // bounds each completion; 0 allows DefaultTimeout
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// Prompt returns the question asked about a finding, with excerpt, the source around it, if
+// any, redacted
+func (c *Client) Prompt(result core.Result, excerpt string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Finding: %s (%s), %s severity\n", result.RuleName, result.RuleID, result.Severity)
+	location := c.Redactor.Redact(result.FilePath)
+	if result.Line > 0 {
+		location += fmt.Sprintf(":%d", result.Line)
+	}
+	fmt.Fprintf(&sb, "Location: %s\n", location)
+	fmt.Fprintf(&sb, "Message: %s\n", c.Redactor.Redact(result.Message))
+	if result.Suggestion != "" {
+		fmt.Fprintf(&sb, "Generic advice: %s\n", result.Suggestion)
+	}
+	if excerpt != "" {
+		fmt.Fprintf(&sb, "\nCode:\n%s\n", c.Redactor.Redact(excerpt))
+	}
+	return sb.String()
+}
+
+// Suggest returns the model's suggestion for a finding, see Prompt
+func (c *Client) Suggest(ctx context.Context, result core.Result, excerpt string) (string, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body, err := json.Marshal(chatRequest{
+		Model: c.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: c.Prompt(result, excerpt)},
+		},
+		Temperature: 0.2,
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.Endpoint, "/")+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	client := c.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: timeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("completion request failed: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	var completion chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return "", fmt.Errorf("invalid completion response: %w", err)
+	}
+	if len(completion.Choices) == 0 || strings.TrimSpace(completion.Choices[0].Message.Content) == "" {
+		return "", fmt.Errorf("the completion response has no answer")
+	}
+	return strings.TrimSpace(completion.Choices[0].Message.Content), nil
+}
+
+// Excerpt returns the lines of a file within context lines of the 1-based line, numbered, or
+// "" when line is outside the file
+func Excerpt(lines []string, line, context int) string {
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	first, last := max(line-context, 1), min(line+context, len(lines))
+	var sb strings.Builder
+	for n := first; n <= last; n++ {
+		marker := "  "
+		if n == line {
+			marker = "> "
+		}
+		fmt.Fprintf(&sb, "%s%5d | %s\n", marker, n, lines[n-1])
+	}
+	return sb.String()
+}
@@ -0,0 +1,74 @@
+package llm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces what a Redactor masks
+const redacted = "[REDACTED]"
+
+// builtinRedactions match the secrets most often found in source code. In a pattern with a
+// group, only the first group is masked, so the name of the variable a secret is assigned to
+// stays readable.
+var builtinRedactions = []*regexp.Regexp{
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?(?:-----END [A-Z ]*PRIVATE KEY-----|$)`),
+	regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),                             // AWS access key IDs
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{30,}\b`),                            // GitHub tokens
+	regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`),                           // Slack tokens
+	regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}`),                                   // OpenAI-style API keys
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]+`), // JSON web tokens
+	regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9._~+/-]{16,}=*)`),
+	regexp.MustCompile(`(?i)[a-z0-9_]*(?:password|passwd|secret|token|api_?key|apikey|credential)[a-z0-9_]*["']?\s*(?::=|=|:)\s*["'\x60]([^"'\x60\s]{4,})["'\x60]`),
+	regexp.MustCompile(`[a-z][a-z0-9+.-]*://[^/\s:@]+:([^/\s@]+)@`), // passwords in URLs
+}
+
+// Redactor masks secrets in text sent to a model: the built-in patterns and any configured
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor returns a redactor of the built-in secret patterns and extra, regular
+// expressions whose matches are masked, or only their first group when they have one
+func NewRedactor(extra []string) (*Redactor, error) {
+	patterns := append([]*regexp.Regexp(nil), builtinRedactions...)
+	for _, expr := range extra {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", expr, err)
+		}
+		patterns = append(patterns, re)
+	}
+	return &Redactor{patterns: patterns}, nil
+}
+
+// Redact returns text with every match of the patterns masked. A nil Redactor masks the
+// built-in patterns.
+func (r *Redactor) Redact(text string) string {
+	patterns := builtinRedactions
+	if r != nil {
+		patterns = r.patterns
+	}
+	for _, re := range patterns {
+		var sb strings.Builder
+		last := 0
+		for _, match := range re.FindAllStringSubmatchIndex(text, -1) {
+			start, end := match[0], match[1]
+			if len(match) > 2 && match[2] >= 0 {
+				start, end = match[2], match[3]
+			}
+			if start == end {
+				continue
+			}
+			sb.WriteString(text[last:start])
+			sb.WriteString(redacted)
+			last = end
+		}
+		if sb.Len() > 0 {
+			sb.WriteString(text[last:])
+			text = sb.String()
+		}
+	}
+	return text
+}
@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNew_FiltersByLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "warn", FormatText)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	logger.Info("scanning")
+	logger.Warn("slow file", "file", "main.go")
+
+	out := buf.String()
+	if strings.Contains(out, "scanning") {
+		t.Errorf("Expected info record to be filtered, got %q", out)
+	}
+	if !strings.Contains(out, "level=WARN") || !strings.Contains(out, "file=main.go") {
+		t.Errorf("Expected warn record, got %q", out)
+	}
+	if strings.Contains(out, "time=") {
+		t.Errorf("Expected text records without a timestamp, got %q", out)
+	}
+}
+
+func TestNew_JSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", FormatJSON)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	logger.Error("analyzing file failed", "file", "bad.py")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expected a JSON record, got %q: %v", buf.String(), err)
+	}
+	if record["level"] != "ERROR" || record["file"] != "bad.py" {
+		t.Errorf("Unexpected record: %v", record)
+	}
+}
+
+func TestNew_RejectsInvalidOptions(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, "verbose", FormatText); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+	if _, err := New(&bytes.Buffer{}, "info", "xml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
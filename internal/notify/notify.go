@@ -0,0 +1,195 @@
+// Package notify posts a summary of an analysis run to a Slack or Microsoft Teams incoming
+// webhook
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/output"
+)
+
+// Formats of the message posted to a webhook
+const (
+	FormatSlack = "slack"
+	FormatTeams = "teams"
+)
+
+// topRules is the number of rules the summary lists
+const topRules = 3
+
+// Timeout bounds a post, so a webhook that hangs does not hold up a nightly job
+const Timeout = 10 * time.Second
+
+// Summary is what a notification tells about a run
+type Summary struct {
+	Project   string // the name of what was analyzed, such as the repository directory
+	Errors    int
+	Warnings  int
+	Info      int
+	Compared  bool // whether New and Fixed were counted against a baseline
+	New       int  // findings not in the baseline
+	Fixed     int  // findings of the baseline no longer reported
+	TopRules  []output.Offender
+	ReportURL string // link to the full report, "" for none
+}
+
+// Summarize counts the results of a run by severity and, when baseline holds the results of an
+// earlier run, the findings introduced and fixed since
+func Summarize(project string, results, baseline []core.Result, reportURL string) Summary {
+	s := Summary{Project: project, ReportURL: reportURL, Compared: baseline != nil}
+	for _, result := range results {
+		switch core.Severity(result.Severity) {
+		case core.SeverityError:
+			s.Errors++
+		case core.SeverityWarning:
+			s.Warnings++
+		case core.SeverityInfo:
+			s.Info++
+		}
+	}
+	if s.Compared {
+		comparison := core.Compare(baseline, results)
+		s.New, s.Fixed = len(comparison.Introduced), len(comparison.Fixed)
+	}
+	if top := output.TopOffendersOf(results, topRules); top != nil {
+		s.TopRules = top.Rules
+	}
+	return s
+}
+
+// Total returns the number of findings of the run
+func (s Summary) Total() int {
+	return s.Errors + s.Warnings + s.Info
+}
+
+// DetectFormat tells the format a webhook expects from its URL: Teams for Microsoft's
+// Office 365 connectors and Power Automate workflows, Slack for anything else, which
+// Slack-compatible services such as Mattermost accept too
+func DetectFormat(webhook string) string {
+	u, err := url.Parse(webhook)
+	if err != nil {
+		return FormatSlack
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, suffix := range []string{".office.com", ".office365.com", ".logic.azure.com", ".powerplatform.com"} {
+		if strings.HasSuffix(host, suffix) {
+			return FormatTeams
+		}
+	}
+	return FormatSlack
+}
+
+// Post sends the summary to webhook as a message in format, slack or teams, or in the format
+// DetectFormat tells when format is ""
+func Post(ctx context.Context, client *http.Client, webhook, format string, s Summary) error {
+	if format == "" {
+		format = DetectFormat(webhook)
+	}
+	var payload interface{}
+	switch format {
+	case FormatSlack:
+		payload = slackMessage(s)
+	case FormatTeams:
+		payload = teamsMessage(s)
+	default:
+		return fmt.Errorf("unknown notification format %q", format)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		reply, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook answered %s: %s", resp.Status, strings.TrimSpace(string(reply)))
+	}
+	return nil
+}
+
+// title is the first line of the message
+func (s Summary) title() string {
+	title := fmt.Sprintf("AgentLint: %d findings", s.Total())
+	if s.Project != "" {
+		title += " in " + s.Project
+	}
+	return title
+}
+
+// details are the lines under the title, without markup
+func (s Summary) details() []string {
+	lines := []string{fmt.Sprintf("%d errors, %d warnings, %d info", s.Errors, s.Warnings, s.Info)}
+	if s.Compared {
+		lines = append(lines, fmt.Sprintf("%d new and %d fixed since the baseline", s.New, s.Fixed))
+	}
+	if len(s.TopRules) > 0 {
+		rules := make([]string, len(s.TopRules))
+		for i, rule := range s.TopRules {
+			rules[i] = fmt.Sprintf("%s (%d)", rule.Name, rule.Count)
+		}
+		lines = append(lines, "Top rules: "+strings.Join(rules, ", "))
+	}
+	return lines
+}
+
+// slackMessage is an incoming webhook message in Slack's mrkdwn
+func slackMessage(s Summary) map[string]interface{} {
+	text := "*" + slackEscape(s.title()) + "*"
+	for _, line := range s.details() {
+		text += "\n" + slackEscape(line)
+	}
+	if s.ReportURL != "" {
+		text += "\n<" + s.ReportURL + "|View the full report>"
+	}
+	return map[string]interface{}{"text": text}
+}
+
+// slackEscape escapes the characters Slack reads as markup
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// teamsMessage is an Adaptive Card message, as Teams workflows and connectors accept
+func teamsMessage(s Summary) map[string]interface{} {
+	body := []map[string]interface{}{
+		{"type": "TextBlock", "text": s.title(), "weight": "Bolder", "size": "Medium", "wrap": true},
+	}
+	for _, line := range s.details() {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": line, "wrap": true})
+	}
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if s.ReportURL != "" {
+		card["actions"] = []map[string]interface{}{{"type": "Action.OpenUrl", "title": "View the full report", "url": s.ReportURL}}
+	}
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{"contentType": "application/vnd.microsoft.card.adaptive", "content": card},
+		},
+	}
+}
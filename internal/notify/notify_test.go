@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+func TestSummarize(t *testing.T) {
+	baseline := []core.Result{
+		{RuleID: "large-function", Severity: "warning", Fingerprint: "a"},
+		{RuleID: "console-log", Severity: "info", Fingerprint: "b"},
+	}
+	results := []core.Result{
+		{RuleID: "large-function", Severity: "warning", Fingerprint: "a"},
+		{RuleID: "large-function", Severity: "warning", Fingerprint: "c"},
+		{RuleID: "bare-except", Severity: "error", Fingerprint: "d"},
+	}
+
+	s := Summarize("shop", results, baseline, "https://ci.example.com/run/7")
+	if s.Total() != 3 || s.Errors != 1 || s.Warnings != 2 || s.Info != 0 {
+		t.Errorf("Expected 1 error and 2 warnings, got %+v", s)
+	}
+	if !s.Compared || s.New != 2 || s.Fixed != 1 {
+		t.Errorf("Expected 2 new and 1 fixed findings, got %+v", s)
+	}
+	if len(s.TopRules) != 2 || s.TopRules[0].Name != "large-function" || s.TopRules[0].Count != 2 {
+		t.Errorf("Expected large-function as the top rule, got %+v", s.TopRules)
+	}
+
+	if s := Summarize("shop", results, nil, ""); s.Compared {
+		t.Errorf("Expected no comparison without a baseline, got %+v", s)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := map[string]string{
+		"https://hooks.slack.com/services/T000/B000/XXXX":                       FormatSlack,
+		"https://contoso.webhook.office.com/webhookb2/abc":                      FormatTeams,
+		"https://prod-12.westeurope.logic.azure.com:443/workflows/abc/triggers": FormatTeams,
+		"https://mattermost.example.com/hooks/xyz":                              FormatSlack,
+		"://not a url": FormatSlack,
+	}
+	for webhook, want := range tests {
+		if got := DetectFormat(webhook); got != want {
+			t.Errorf("DetectFormat(%q) = %s, expected %s", webhook, got, want)
+		}
+	}
+}
+
+func TestPost(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected a JSON post, got %s", r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		received = nil
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("Invalid JSON %q: %v", body, err)
+		}
+	}))
+	defer server.Close()
+
+	s := Summary{Project: "shop", Warnings: 2, Compared: true, New: 1, ReportURL: "https://ci.example.com/run/7"}
+	if err := Post(context.Background(), server.Client(), server.URL, FormatSlack, s); err != nil {
+		t.Fatal(err)
+	}
+	text, _ := received["text"].(string)
+	for _, want := range []string{"*AgentLint: 2 findings in shop*", "1 new and 0 fixed since the baseline", "<https://ci.example.com/run/7|View the full report>"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the Slack message, got %q", want, text)
+		}
+	}
+
+	if err := Post(context.Background(), server.Client(), server.URL, FormatTeams, s); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(received)
+	for _, want := range []string{`"type":"message"`, `"contentType":"application/vnd.microsoft.card.adaptive"`, `"Action.OpenUrl"`, "AgentLint: 2 findings in shop"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %s in the Teams message, got %s", want, data)
+		}
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer failing.Close()
+	if err := Post(context.Background(), failing.Client(), failing.URL, "", s); err == nil || !strings.Contains(err.Error(), "invalid_token") {
+		t.Errorf("Expected the webhook's error, got %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package output
+
+import "os"
+
+// ANSI escape sequences used by the console formatter
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// ColorEnabled reports whether output written to file should be colored: file is a terminal
+// and the NO_COLOR environment variable (https://no-color.org) is unset or empty
+func ColorEnabled(file *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// severityColor returns the color of a severity label
+func severityColor(severity string) string {
+	switch severity {
+	case "error":
+		return ansiRed
+	case "warning":
+		return ansiYellow
+	case "info":
+		return ansiCyan
+	default:
+		return ""
+	}
+}
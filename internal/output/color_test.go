@@ -0,0 +1,97 @@
+package output_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/output"
+)
+
+// captureStdout returns what write prints to stdout
+func captureStdout(t *testing.T, write func()) []byte {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldStdout := os.Stdout
+	os.Stdout = w
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- data
+	}()
+	write()
+	os.Stdout = oldStdout
+	w.Close()
+	return <-done
+}
+
+func TestColorEnabled(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm")
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	defer writer.Close()
+	if output.ColorEnabled(writer) {
+		t.Error("Expected no color when writing to a pipe")
+	}
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if output.ColorEnabled(file) {
+		t.Error("Expected no color when writing to a file")
+	}
+
+	// /dev/null is a character device, so it passes for a terminal
+	device, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Skipf("opening %s failed: %v", os.DevNull, err)
+	}
+	defer device.Close()
+	if info, err := device.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		t.Skipf("%s is not a character device", os.DevNull)
+	}
+	if !output.ColorEnabled(device) {
+		t.Error("Expected color on a terminal")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if output.ColorEnabled(device) {
+		t.Error("Expected no color with NO_COLOR set")
+	}
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "dumb")
+	if output.ColorEnabled(device) {
+		t.Error("Expected no color on a dumb terminal")
+	}
+}
+
+func TestConsoleFormatter_Color(t *testing.T) {
+	results := []core.Result{
+		{RuleID: "large-function", Severity: "error", FilePath: "main.go", Line: 3, Message: "too large"},
+		{RuleID: "magic-number", Severity: "info", FilePath: "main.go", Line: 9, Message: "magic number"},
+	}
+	for _, verbose := range []bool{false, true} {
+		formatter := output.NewConsoleFormatter(verbose)
+		got := string(captureStdout(t, func() { formatter.Format(results) }))
+		if strings.Contains(got, "\x1b[") {
+			t.Errorf("Expected no escape codes by default, got %q", got)
+		}
+
+		formatter.SetColor(true)
+		got = string(captureStdout(t, func() { formatter.Format(results) }))
+		if !strings.Contains(got, "\x1b[31m") {
+			t.Errorf("Expected errors in red once colors are enabled, got %q", got)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// ComparisonOutput is the JSON output of agentlint diff
+type ComparisonOutput struct {
+	Before     string            `json:"before"` // the directory of the first snapshot
+	After      string            `json:"after"`  // the directory of the second snapshot
+	Summary    ComparisonSummary `json:"summary"`
+	Introduced []core.Result     `json:"introduced"`
+	Fixed      []core.Result     `json:"fixed"`
+	Unchanged  []core.Result     `json:"unchanged"`
+	Timestamp  string            `json:"timestamp"`
+}
+
+// ComparisonSummary counts the findings of a comparison
+type ComparisonSummary struct {
+	Introduced int `json:"introduced"`
+	Fixed      int `json:"fixed"`
+	Unchanged  int `json:"unchanged"`
+	// BlockingCount is the number of introduced findings that fail the run
+	BlockingCount int `json:"blocking_count"`
+}
+
+// FormatComparison formats the comparison of the snapshots in the directories before and
+// after as JSON
+func (f *JSONFormatter) FormatComparison(before, after string, comparison core.Comparison) error {
+	orEmpty := func(results []core.Result) []core.Result {
+		if results == nil {
+			return []core.Result{}
+		}
+		return results
+	}
+	output := ComparisonOutput{
+		Before:     before,
+		After:      after,
+		Summary:    summarizeComparison(comparison),
+		Introduced: orEmpty(comparison.Introduced),
+		Fixed:      orEmpty(comparison.Fixed),
+		Unchanged:  orEmpty(comparison.Unchanged),
+		Timestamp:  getCurrentTimestamp(),
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+// FormatComparison prints the findings introduced and fixed between the snapshots in the
+// directories before and after, and the unchanged ones in verbose mode
+func (f *ConsoleFormatter) FormatComparison(before, after string, comparison core.Comparison) error {
+	summary := summarizeComparison(comparison)
+	fmt.Printf("Comparing %s with %s: %d introduced, %d fixed, %d unchanged\n",
+		before, after, summary.Introduced, summary.Fixed, summary.Unchanged)
+
+	f.printComparisonSection("Introduced", comparison.Introduced)
+	f.printComparisonSection("Fixed", comparison.Fixed)
+	if f.verbose {
+		f.printComparisonSection("Unchanged", comparison.Unchanged)
+	}
+	if summary.BlockingCount > 0 {
+		fmt.Println()
+		fmt.Println(f.paint(ansiRed, fmt.Sprintf("%d introduced findings fail the run", summary.BlockingCount)))
+	}
+	return nil
+}
+
+// printComparisonSection prints results under title, one line each
+func (f *ConsoleFormatter) printComparisonSection(title string, results []core.Result) {
+	if len(results) == 0 {
+		return
+	}
+	fmt.Printf("\n%s (%d):\n", f.paint(ansiBold, title), len(results))
+	for _, result := range results {
+		location := f.paint(ansiDim, fmt.Sprintf("%s:%d:", result.FilePath, result.Line))
+		severity := f.paint(severityColor(result.Severity), formatSeverity(result.Severity))
+		fmt.Printf("  %s %s [%s, %s]\n", location, result.Message, severity, result.RuleID)
+		if f.verbose && result.Suggestion != "" {
+			fmt.Printf("    Suggestion: %s\n", result.Suggestion)
+		}
+	}
+}
+
+func summarizeComparison(comparison core.Comparison) ComparisonSummary {
+	summary := ComparisonSummary{
+		Introduced: len(comparison.Introduced),
+		Fixed:      len(comparison.Fixed),
+		Unchanged:  len(comparison.Unchanged),
+	}
+	for _, result := range comparison.Introduced {
+		if result.Blocking {
+			summary.BlockingCount++
+		}
+	}
+	return summary
+}
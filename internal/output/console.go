@@ -3,6 +3,7 @@ package output
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
@@ -10,9 +11,18 @@ import (
 
 // ConsoleFormatter formats results for console output
 type ConsoleFormatter struct {
-	verbose bool
+	verbose    bool
+	color      bool
+	groupBy    string
+	roots      []string
+	kinds      map[string]string
+	fileErrors []core.FileError
+	top        int
 }
 
+// topFilesPerRule is the number of files listed under each rule when grouping by rule
+const topFilesPerRule = 3
+
 // NewConsoleFormatter creates a new console formatter
 func NewConsoleFormatter(verbose bool) *ConsoleFormatter {
 	return &ConsoleFormatter{
@@ -22,19 +32,147 @@ func NewConsoleFormatter(verbose bool) *ConsoleFormatter {
 
 // Format formats the results for console output
 func (f *ConsoleFormatter) Format(results []core.Result) error {
+	defer f.printFileErrors()
+
+	if len(f.roots) > 1 {
+		f.printResultsByRoot(results)
+		return nil
+	}
+
+	results, external := splitExternal(results)
+	defer f.printExternal(external)
+	defer f.printProjectKind("")
 	if len(results) == 0 {
-		fmt.Println("No issues found!")
+		if len(external) > 0 {
+			fmt.Println("No issues found in the project's own code!")
+		} else {
+			fmt.Println("No issues found!")
+		}
 		return nil
 	}
 
-	fileResults := groupResultsByFile(results)
+	fmt.Printf("Found %d issues across %d files\n\n", len(results), len(groupResultsByFile(results)))
+	f.printGroupedResults(results)
+	f.printSummary("Summary:", results)
+	f.printOwnerSummary(results)
+	f.printTopOffenders(results)
 
-	fmt.Printf("Found %d issues across %d files\n\n", len(results), len(fileResults))
+	return nil
+}
 
-	f.printResultsByFile(fileResults)
-	f.printSummary(results)
+// splitExternal separates the results in the project's own code from those in vendored and
+// third-party code
+func splitExternal(results []core.Result) (own, external []core.Result) {
+	for _, result := range results {
+		if result.External {
+			external = append(external, result)
+		} else {
+			own = append(own, result)
+		}
+	}
+	return own, external
+}
 
-	return nil
+// printExternal prints the findings in vendored and third-party code after the project's own,
+// with a summary of their own, so smells inherited from dependencies can be told apart
+func (f *ConsoleFormatter) printExternal(results []core.Result) {
+	if len(results) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Printf("%s (%d issues across %d files)\n", f.paint(ansiBold, "External code"), len(results), len(groupResultsByFile(results)))
+	fmt.Println(strings.Repeat("-", 40))
+	f.printGroupedResults(results)
+	f.printSummary("External summary:", results)
+}
+
+// printGroupedResults prints results grouped by rule, by module when they span several, or
+// by file
+func (f *ConsoleFormatter) printGroupedResults(results []core.Result) {
+	if f.groupBy == "rule" {
+		f.printResultsByRule(results)
+	} else if moduleResults := groupResultsByModule(results); len(moduleResults) > 1 {
+		f.printResultsByModule(moduleResults)
+	} else {
+		f.printResultsByFile(groupResultsByFile(results))
+	}
+}
+
+// printResultsByRoot prints a section with its own summary for each analyzed root, in command
+// line order, followed by the totals across roots
+func (f *ConsoleFormatter) printResultsByRoot(results []core.Result) {
+	rootResults := make(map[string][]core.Result)
+	for _, result := range results {
+		rootResults[result.Root] = append(rootResults[result.Root], result)
+	}
+
+	for _, root := range f.roots {
+		issues := rootResults[root]
+		fmt.Printf("%s (%d issues)\n", f.paint(ansiBold, "Root "+root), len(issues))
+		fmt.Println(strings.Repeat("-", 40))
+		if len(issues) == 0 {
+			fmt.Println("No issues found!")
+			f.printProjectKind(root)
+			fmt.Println()
+			continue
+		}
+		f.printGroupedResults(issues)
+		f.printSummary("Summary:", issues)
+		f.printProjectKind(root)
+		fmt.Println()
+	}
+
+	fmt.Printf("Found %d issues across %d roots\n", len(results), len(f.roots))
+	f.printSummary("Total:", results)
+	f.printOwnerSummary(results)
+	f.printTopOffenders(results)
+}
+
+// SetColor switches ANSI colors on or off; they are off by default
+func (f *ConsoleFormatter) SetColor(enabled bool) {
+	f.color = enabled
+}
+
+// SetRoots lists the roots analyzed in one run; with several, results are reported per root
+// with a summary for each
+func (f *ConsoleFormatter) SetRoots(roots []string) {
+	f.roots = roots
+}
+
+// SetProjectKinds records whether each analyzed root builds a binary or is a library, keyed
+// by root, or by "" when a single project is analyzed
+func (f *ConsoleFormatter) SetProjectKinds(kinds map[string]string) {
+	f.kinds = kinds
+}
+
+// printProjectKind prints whether the project analyzed from root is a binary or a library,
+// when its Go code told
+func (f *ConsoleFormatter) printProjectKind(root string) {
+	switch f.kinds[root] {
+	case "binary":
+		fmt.Println("Project: binary (has a main package)")
+	case "library":
+		fmt.Println("Project: library (no main package)")
+	}
+}
+
+// SetTop lists the n files and the n rules with the most findings after the summary; 0 leaves
+// them out
+func (f *ConsoleFormatter) SetTop(n int) {
+	f.top = n
+}
+
+// SetGroupBy selects how results are grouped: by "file" (the default) or by "rule"
+func (f *ConsoleFormatter) SetGroupBy(mode string) {
+	f.groupBy = mode
+}
+
+// paint wraps text in an ANSI color when colors are enabled
+func (f *ConsoleFormatter) paint(color, text string) string {
+	if !f.color || color == "" {
+		return text
+	}
+	return color + text + ansiReset
 }
 
 func groupResultsByFile(results []core.Result) map[string][]core.Result {
@@ -45,17 +183,115 @@ func groupResultsByFile(results []core.Result) map[string][]core.Result {
 	return fileResults
 }
 
+// groupResultsByModule groups results by their Go module; results outside any module share
+// the "" key
+func groupResultsByModule(results []core.Result) map[string][]core.Result {
+	moduleResults := make(map[string][]core.Result)
+	for _, result := range results {
+		moduleResults[result.Module] = append(moduleResults[result.Module], result)
+	}
+	return moduleResults
+}
+
+// printResultsByModule prints a section for each module, in module path order
+func (f *ConsoleFormatter) printResultsByModule(moduleResults map[string][]core.Result) {
+	modules := make([]string, 0, len(moduleResults))
+	for module := range moduleResults {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	for _, module := range modules {
+		name := module
+		if name == "" {
+			name = "(outside any Go module)"
+		}
+		fmt.Printf("%s (%d issues)\n", f.paint(ansiBold, "Module "+name), len(moduleResults[module]))
+		fmt.Println(strings.Repeat("-", 40))
+		f.printResultsByFile(groupResultsByFile(moduleResults[module]))
+	}
+}
+
 func (f *ConsoleFormatter) printResultsByFile(fileResults map[string][]core.Result) {
 	for filePath, fileIssues := range fileResults {
-		fmt.Printf("%s (%d issues):\n", filePath, len(fileIssues))
+		fmt.Printf("%s (%d issues):\n", f.paint(ansiBold, filePath), len(fileIssues))
 
 		for _, issue := range fileIssues {
-			severity := formatSeverity(issue.Severity)
-			fmt.Printf("  %s:%d: %s [%s]\n", filePath, issue.Line, issue.Message, severity)
+			location := f.paint(ansiDim, fmt.Sprintf("%s:%d:", filePath, issue.Line))
+			severity := f.paint(severityColor(issue.Severity), formatSeverity(issue.Severity))
+			if issue.Confidence != "" && issue.Confidence != string(core.ConfidenceHigh) {
+				severity += ", " + issue.Confidence + " confidence"
+			}
+			if issue.Uncovered {
+				severity += ", " + f.paint(ansiBold, "untested")
+			}
+			if issue.AIGenerated {
+				severity += ", AI-generated"
+			}
+			if issue.External {
+				severity += ", external"
+			}
+			fmt.Printf("  %s %s [%s]\n", location, issue.Message, severity)
 
 			if f.verbose && issue.Suggestion != "" {
 				fmt.Printf("    Suggestion: %s\n", issue.Suggestion)
 			}
+			if f.verbose && issue.Related != nil {
+				fmt.Printf("    Related: %s:%d\n", issue.Related.FilePath, issue.Related.Line)
+			}
+		}
+		fmt.Println()
+	}
+}
+
+// printResultsByRule prints a section per rule, most frequent first, with the files that have
+// the most findings for it
+func (f *ConsoleFormatter) printResultsByRule(results []core.Result) {
+	ruleResults := make(map[string][]core.Result)
+	for _, result := range results {
+		ruleResults[result.RuleID] = append(ruleResults[result.RuleID], result)
+	}
+	ruleIDs := make([]string, 0, len(ruleResults))
+	for id := range ruleResults {
+		ruleIDs = append(ruleIDs, id)
+	}
+	sort.Slice(ruleIDs, func(i, j int) bool {
+		a, b := ruleResults[ruleIDs[i]], ruleResults[ruleIDs[j]]
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return ruleIDs[i] < ruleIDs[j]
+	})
+
+	for _, id := range ruleIDs {
+		issues := ruleResults[id]
+		severity := f.paint(severityColor(issues[0].Severity), formatSeverity(issues[0].Severity))
+		fmt.Printf("%s - %s (%d issues) [%s]\n", f.paint(ansiBold, id), issues[0].RuleName, len(issues), severity)
+
+		fileCounts := make(map[string]int)
+		for _, issue := range issues {
+			fileCounts[issue.FilePath]++
+		}
+		files := make([]string, 0, len(fileCounts))
+		for file := range fileCounts {
+			files = append(files, file)
+		}
+		sort.Slice(files, func(i, j int) bool {
+			if fileCounts[files[i]] != fileCounts[files[j]] {
+				return fileCounts[files[i]] > fileCounts[files[j]]
+			}
+			return files[i] < files[j]
+		})
+
+		shown := files
+		if !f.verbose && len(shown) > topFilesPerRule {
+			shown = shown[:topFilesPerRule]
+		}
+		for _, file := range shown {
+			fmt.Printf("  %s: %d\n", f.paint(ansiDim, file), fileCounts[file])
+		}
+		if len(shown) < len(files) {
+			fmt.Printf("  ... and %d more files\n", len(files)-len(shown))
 		}
 		fmt.Println()
 	}
@@ -75,9 +311,12 @@ func formatSeverity(severity string) string {
 }
 
 type severityCounts struct {
-	errors   int
-	warnings int
-	info     int
+	errors      int
+	warnings    int
+	info        int
+	uncovered   int
+	aiGenerated int
+	external    int
 }
 
 func countSeverities(results []core.Result) severityCounts {
@@ -91,27 +330,123 @@ func countSeverities(results []core.Result) severityCounts {
 		case "info":
 			counts.info++
 		}
+		if result.Uncovered {
+			counts.uncovered++
+		}
+		if result.AIGenerated {
+			counts.aiGenerated++
+		}
+		if result.External {
+			counts.external++
+		}
 	}
 	return counts
 }
 
-func (f *ConsoleFormatter) printSummary(results []core.Result) {
+// printSummary prints the count of each severity under title
+func (f *ConsoleFormatter) printSummary(title string, results []core.Result) {
 	counts := countSeverities(results)
 
 	if counts.errors > 0 || counts.warnings > 0 || counts.info > 0 {
-		fmt.Println("Summary:")
+		fmt.Println(title)
 		if counts.errors > 0 {
-			fmt.Printf("  Errors: %d\n", counts.errors)
+			fmt.Println(f.paint(ansiRed, fmt.Sprintf("  Errors: %d", counts.errors)))
 		}
 		if counts.warnings > 0 {
-			fmt.Printf("  Warnings: %d\n", counts.warnings)
+			fmt.Println(f.paint(ansiYellow, fmt.Sprintf("  Warnings: %d", counts.warnings)))
 		}
 		if counts.info > 0 {
-			fmt.Printf("  Info: %d\n", counts.info)
+			fmt.Println(f.paint(ansiCyan, fmt.Sprintf("  Info: %d", counts.info)))
+		}
+		if counts.uncovered > 0 {
+			fmt.Printf("  In untested code: %d\n", counts.uncovered)
+		}
+		if counts.aiGenerated > 0 {
+			fmt.Printf("  In AI-generated files: %d\n", counts.aiGenerated)
+		}
+		if counts.external > 0 && counts.external < len(results) { // not in the external section itself
+			fmt.Printf("  In external code: %d\n", counts.external)
 		}
 	}
 }
 
+// printOwnerSummary prints the number of findings of each CODEOWNERS owner, most first, when
+// any result has owners
+func (f *ConsoleFormatter) printOwnerSummary(results []core.Result) {
+	ownerResults := groupResultsByOwner(results)
+	if len(ownerResults) == 0 {
+		return
+	}
+	owners := make([]string, 0, len(ownerResults))
+	unowned := len(results)
+	for owner := range ownerResults {
+		owners = append(owners, owner)
+	}
+	for _, result := range results {
+		if len(result.Owners) > 0 {
+			unowned--
+		}
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		a, b := len(ownerResults[owners[i]]), len(ownerResults[owners[j]])
+		if a != b {
+			return a > b
+		}
+		return owners[i] < owners[j]
+	})
+
+	fmt.Println("By owner:")
+	for _, owner := range owners {
+		fmt.Printf("  %s: %d\n", owner, len(ownerResults[owner]))
+	}
+	if unowned > 0 {
+		fmt.Printf("  (unowned): %d\n", unowned)
+	}
+}
+
+// printTopOffenders prints the files and rules with the most findings, when SetTop asked for them
+func (f *ConsoleFormatter) printTopOffenders(results []core.Result) {
+	top := TopOffendersOf(results, f.top)
+	if top == nil || len(results) == 0 {
+		return
+	}
+	fmt.Printf("Top %d files:\n", len(top.Files))
+	for _, offender := range top.Files {
+		fmt.Printf("  %s: %d\n", f.paint(ansiDim, offender.Name), offender.Count)
+	}
+	fmt.Printf("Top %d rules:\n", len(top.Rules))
+	for _, offender := range top.Rules {
+		fmt.Printf("  %s: %d\n", offender.Name, offender.Count)
+	}
+}
+
+// groupResultsByOwner groups results by the owners of their files; a result with several
+// owners is listed under each, and unowned results are left out
+func groupResultsByOwner(results []core.Result) map[string][]core.Result {
+	ownerResults := make(map[string][]core.Result)
+	for _, result := range results {
+		for _, owner := range result.Owners {
+			ownerResults[owner] = append(ownerResults[owner], result)
+		}
+	}
+	return ownerResults
+}
+
+// SetFileErrors records files that could not be analyzed, listed after the results
+func (f *ConsoleFormatter) SetFileErrors(errors []core.FileError) {
+	f.fileErrors = errors
+}
+
+func (f *ConsoleFormatter) printFileErrors() {
+	if len(f.fileErrors) == 0 {
+		return
+	}
+	fmt.Printf("\n%d files could not be analyzed:\n", len(f.fileErrors))
+	for _, err := range f.fileErrors {
+		fmt.Printf("  %s: %s\n", err.FilePath, err.Message)
+	}
+}
+
 // FormatError formats an error for console output
 func (f *ConsoleFormatter) FormatError(err error) error {
 	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -0,0 +1,86 @@
+package output_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/output"
+)
+
+func TestConsoleFormatter_Top(t *testing.T) {
+	results := []core.Result{
+		{RuleID: "console-log", Severity: "info", FilePath: "app.js", Line: 1},
+		{RuleID: "console-log", Severity: "info", FilePath: "app.js", Line: 2},
+		{RuleID: "large-function", Severity: "warning", FilePath: "util.js", Line: 1},
+	}
+	formatter := output.NewConsoleFormatter(false)
+	formatter.SetTop(1)
+	got := string(captureStdout(t, func() { formatter.Format(results) }))
+
+	want := "Top 1 files:\n  app.js: 2\nTop 1 rules:\n  console-log: 2\n"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("Expected the output to end with %q, got %q", want, got)
+	}
+}
+
+func TestConsoleFormatter_External(t *testing.T) {
+	results := []core.Result{
+		{RuleID: "large-function", Severity: "warning", FilePath: "main.go", Line: 3},
+		{RuleID: "large-function", Severity: "warning", FilePath: "vendor/lib/lib.go", Line: 10, External: true},
+		{RuleID: "magic-number", Severity: "info", FilePath: "vendor/lib/lib.go", Line: 12, External: true},
+	}
+	got := string(captureStdout(t, func() { output.NewConsoleFormatter(false).Format(results) }))
+
+	own, external, found := strings.Cut(got, "External code (2 issues across 1 files)")
+	if !found {
+		t.Fatalf("Expected a section for external code, got %q", got)
+	}
+	if !strings.HasPrefix(own, "Found 1 issues across 1 files") || strings.Contains(own, "vendor/") {
+		t.Errorf("Expected only the project's own findings before the external section, got %q", own)
+	}
+	if !strings.Contains(external, "External summary:\n  Warnings: 1\n  Info: 1\n") {
+		t.Errorf("Expected a summary of the external findings, got %q", external)
+	}
+
+	got = string(captureStdout(t, func() { output.NewConsoleFormatter(false).Format(results[1:]) }))
+	if !strings.HasPrefix(got, "No issues found in the project's own code!\n") {
+		t.Errorf("Expected the project's own code reported clean, got %q", got)
+	}
+}
+
+func TestConsoleFormatter_GroupByRule(t *testing.T) {
+	var results []core.Result
+	add := func(rule, name, severity, file string, count int) {
+		for i := 0; i < count; i++ {
+			results = append(results, core.Result{RuleID: rule, RuleName: name, Severity: severity, FilePath: file, Line: i + 1})
+		}
+	}
+	add("magic-number", "Magic Number", "info", "a.go", 1)
+	add("magic-number", "Magic Number", "info", "b.go", 3)
+	add("magic-number", "Magic Number", "info", "c.go", 2)
+	add("magic-number", "Magic Number", "info", "d.go", 2)
+	add("magic-number", "Magic Number", "info", "e.go", 1)
+	add("large-function", "Large Function", "warning", "b.go", 1)
+	add("console-log", "Console Log", "info", "app.js", 1)
+
+	formatter := output.NewConsoleFormatter(false)
+	formatter.SetGroupBy("rule")
+	got := string(captureStdout(t, func() { formatter.Format(results) }))
+
+	want := "magic-number - Magic Number (9 issues) [INFO]\n" +
+		"  b.go: 3\n  c.go: 2\n  d.go: 2\n  ... and 2 more files\n\n" +
+		"console-log - Console Log (1 issues) [INFO]\n  app.js: 1\n\n" +
+		"large-function - Large Function (1 issues) [WARN]\n  b.go: 1\n\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("Expected rules by frequency with their top files %q, got %q", want, got)
+	}
+
+	verbose := output.NewConsoleFormatter(true)
+	verbose.SetGroupBy("rule")
+	got = string(captureStdout(t, func() { verbose.Format(results) }))
+	want = "  b.go: 3\n  c.go: 2\n  d.go: 2\n  a.go: 1\n  e.go: 1\n\n"
+	if !strings.Contains(got, want) || strings.Contains(got, "more files") {
+		t.Errorf("Expected every file listed in verbose mode %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,84 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// csvHeader names the columns written by the CSV formatter
+var csvHeader = []string{"file", "line", "column", "rule", "severity", "confidence", "message"}
+
+// CSVFormatter formats results as comma- or tab-separated values, one row per result
+type CSVFormatter struct {
+	comma      rune
+	fileErrors []core.FileError
+}
+
+// NewCSVFormatter creates a new CSV formatter
+func NewCSVFormatter() *CSVFormatter {
+	return &CSVFormatter{comma: ','}
+}
+
+// NewTSVFormatter creates a CSV formatter that separates fields with tabs
+func NewTSVFormatter() *CSVFormatter {
+	return &CSVFormatter{comma: '\t'}
+}
+
+// Format writes a header row followed by a row per result
+func (f *CSVFormatter) Format(results []core.Result) error {
+	defer f.printFileErrors()
+
+	writer := csv.NewWriter(os.Stdout)
+	writer.Comma = f.comma
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, result := range results {
+		record := []string{
+			result.FilePath,
+			strconv.Itoa(result.Line),
+			strconv.Itoa(result.Column),
+			result.RuleID,
+			result.Severity,
+			result.Confidence,
+			result.Message,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// SetFileErrors records files that could not be analyzed, listed on stderr so they do not
+// break the table
+func (f *CSVFormatter) SetFileErrors(errors []core.FileError) {
+	f.fileErrors = errors
+}
+
+func (f *CSVFormatter) printFileErrors() {
+	for _, err := range f.fileErrors {
+		fmt.Fprintf(os.Stderr, "could not analyze %s\n", err.Error())
+	}
+}
+
+// FormatError formats an error for CSV output
+func (f *CSVFormatter) FormatError(err error) error {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	return nil
+}
+
+// PrintHeader prints a header for the analysis (no-op for CSV)
+func (f *CSVFormatter) PrintHeader() {
+	// The column header row is written by Format
+}
+
+// PrintFooter prints a footer for the analysis (no-op for CSV)
+func (f *CSVFormatter) PrintFooter() {
+	// No footer for CSV output
+}
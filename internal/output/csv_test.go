@@ -0,0 +1,62 @@
+package output_test
+
+import (
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/output"
+)
+
+func TestCSVFormatter(t *testing.T) {
+	results := []core.Result{
+		{RuleID: "large-function", Severity: "warning", Confidence: "high", FilePath: "main.go", Line: 3, Column: 1, Message: "function main is 80 lines, max is 50"},
+		{RuleID: "magic-number", Severity: "info", FilePath: "util.go", Line: 7, Message: "magic number \"42\"\tin call\nconsider a constant"},
+	}
+	header := []string{"file", "line", "column", "rule", "severity", "confidence", "message"}
+	want := [][]string{
+		header,
+		{"main.go", "3", "1", "large-function", "warning", "high", "function main is 80 lines, max is 50"},
+		{"util.go", "7", "0", "magic-number", "info", "", "magic number \"42\"\tin call\nconsider a constant"},
+	}
+
+	tests := []struct {
+		name      string
+		formatter *output.CSVFormatter
+		comma     rune
+	}{
+		{"csv", output.NewCSVFormatter(), ','},
+		{"tsv", output.NewTSVFormatter(), '\t'},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := captureStdout(t, func() {
+				if err := tt.formatter.Format(results); err != nil {
+					t.Errorf("Format failed: %v", err)
+				}
+			})
+			if !strings.HasPrefix(string(data), strings.Join(header, string(tt.comma))+"\n") {
+				t.Errorf("Expected the output to start with a header row, got %q", data)
+			}
+
+			reader := csv.NewReader(strings.NewReader(string(data)))
+			reader.Comma = tt.comma
+			records, err := reader.ReadAll()
+			if err != nil {
+				t.Fatalf("Reading the output back failed: %v\n%s", err, data)
+			}
+			if !reflect.DeepEqual(records, want) {
+				t.Errorf("Expected %q, got %q", want, records)
+			}
+		})
+	}
+}
+
+func TestCSVFormatter_NoResults(t *testing.T) {
+	data := captureStdout(t, func() { output.NewCSVFormatter().Format(nil) })
+	if want := "file,line,column,rule,severity,confidence,message\n"; string(data) != want {
+		t.Errorf("Expected only the header row %q, got %q", want, data)
+	}
+}
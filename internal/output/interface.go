@@ -6,6 +6,7 @@ import "github.com/CiaranMcAleer/AgentLint/internal/core"
 type Formatter interface {
 	Format(results []core.Result) error
 	FormatError(err error) error
+	SetFileErrors(errors []core.FileError)
 	PrintHeader()
 	PrintFooter()
 }
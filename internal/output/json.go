@@ -1,16 +1,32 @@
 package output
 
 import (
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
 )
 
 // JSONFormatter formats results as JSON
 type JSONFormatter struct {
-	verbose bool
+	verbose    bool
+	roots      []string
+	kinds      map[string]string
+	fileErrors []core.FileError
+	run        *RunInfo
+	top        int
+}
+
+// RunInfo describes the analysis run the results come from
+type RunInfo struct {
+	Started     time.Time
+	Finished    time.Time
+	ToolVersion string
+	ConfigHash  string         // see core.Config.Hash
+	Languages   map[string]int // files analyzed per language
 }
 
 // NewJSONFormatter creates a new JSON formatter
@@ -20,12 +36,26 @@ func NewJSONFormatter(verbose bool) *JSONFormatter {
 	}
 }
 
+// SchemaVersion is the version of JSONSchema the JSON output follows, as major.minor. The
+// minor version is raised when fields are added, the major version when fields are removed or
+// change meaning.
+const SchemaVersion = "1.6"
+
+// JSONSchema is the JSON Schema of the JSON output, printed by agentlint schema
+//
+//go:embed schema.json
+var JSONSchema []byte
+
 // JSONOutput represents the structure of JSON output
 type JSONOutput struct {
-	Summary   Summary       `json:"summary"`
-	Results   []core.Result `json:"results"`
-	Errors    []string      `json:"errors,omitempty"`
-	Timestamp string        `json:"timestamp"`
+	SchemaVersion string             `json:"schema_version"`
+	Summary       Summary            `json:"summary"`
+	Roots         map[string]Summary `json:"roots,omitempty"`    // summary per root when several are analyzed
+	Owners        map[string]Summary `json:"owners,omitempty"`   // summary per CODEOWNERS owner, when findings have owners
+	External      *Summary           `json:"external,omitempty"` // summary of the findings in external code, see core.FilesConfig.External
+	Results       []core.Result      `json:"results"`
+	Errors        []string           `json:"errors,omitempty"`
+	Timestamp     string             `json:"timestamp"`
 }
 
 // Summary contains summary information about the analysis
@@ -35,16 +65,61 @@ type Summary struct {
 	WarnCount   int `json:"warning_count"`
 	InfoCount   int `json:"info_count"`
 	FileCount   int `json:"file_count"`
+	// BlockingCount is the number of results that fail the run
+	BlockingCount int `json:"blocking_count"`
+	// UncoveredCount is the number of results in code the tests never ran, see -coverage
+	UncoveredCount int `json:"uncovered_count,omitempty"`
+	// AIGeneratedCount is the number of results in files AI assistants wrote, see
+	// core.AIFilesConfig
+	AIGeneratedCount int `json:"ai_generated_count,omitempty"`
+	// ExternalCount is the number of results in vendored and third-party code, see
+	// core.FilesConfig.External
+	ExternalCount int `json:"external_count,omitempty"`
+	// Project is "binary" or "library" according to the project's Go code, see SetProjectKinds
+	Project string `json:"project,omitempty"`
+
+	// The run the results come from, in the overall summary only, see SetRunInfo
+	StartedAt       string         `json:"started_at,omitempty"`
+	FinishedAt      string         `json:"finished_at,omitempty"`
+	DurationSeconds float64        `json:"duration_seconds,omitempty"`
+	ToolVersion     string         `json:"tool_version,omitempty"`
+	ConfigHash      string         `json:"config_hash,omitempty"`
+	Languages       map[string]int `json:"languages,omitempty"` // files analyzed per language
+
+	// Top lists the files and rules with the most findings, in the overall summary only, see
+	// SetTop
+	Top *TopOffenders `json:"top,omitempty"`
 }
 
 // Format formats the results as JSON
 func (f *JSONFormatter) Format(results []core.Result) error {
 	summary := f.calculateSummary(results)
+	if len(f.roots) < 2 {
+		summary.Project = f.kinds[""]
+	}
+	if f.run != nil {
+		summary.StartedAt = f.run.Started.UTC().Format(time.RFC3339)
+		summary.FinishedAt = f.run.Finished.UTC().Format(time.RFC3339)
+		summary.DurationSeconds = f.run.Finished.Sub(f.run.Started).Seconds()
+		summary.ToolVersion = f.run.ToolVersion
+		summary.ConfigHash = f.run.ConfigHash
+		summary.Languages = f.run.Languages
+	}
+	summary.Top = TopOffendersOf(results, f.top)
+
+	if results == nil {
+		results = []core.Result{} // the schema requires a list
+	}
 
 	output := JSONOutput{
-		Summary:   summary,
-		Results:   results,
-		Timestamp: getCurrentTimestamp(),
+		SchemaVersion: SchemaVersion,
+		Summary:       summary,
+		Roots:         f.rootSummaries(results),
+		Owners:        f.ownerSummaries(results),
+		External:      f.externalSummary(results),
+		Results:       results,
+		Errors:        fileErrorMessages(f.fileErrors),
+		Timestamp:     getCurrentTimestamp(),
 	}
 
 	// Use encoder for better performance with large outputs
@@ -53,6 +128,93 @@ func (f *JSONFormatter) Format(results []core.Result) error {
 	return encoder.Encode(output)
 }
 
+// SetRoots lists the roots analyzed in one run; with several, a summary is added for each
+func (f *JSONFormatter) SetRoots(roots []string) {
+	f.roots = roots
+}
+
+// SetRunInfo records the run the results come from, reported in the overall summary
+func (f *JSONFormatter) SetRunInfo(info RunInfo) {
+	f.run = &info
+}
+
+// SetTop adds the n files and the n rules with the most findings to the overall summary; 0
+// leaves them out
+func (f *JSONFormatter) SetTop(n int) {
+	f.top = n
+}
+
+// SetProjectKinds records whether each analyzed root builds a binary or is a library, keyed
+// by root, or by "" when a single project is analyzed
+func (f *JSONFormatter) SetProjectKinds(kinds map[string]string) {
+	f.kinds = kinds
+}
+
+// rootSummaries computes a summary for each root when several were analyzed
+func (f *JSONFormatter) rootSummaries(results []core.Result) map[string]Summary {
+	if len(f.roots) < 2 {
+		return nil
+	}
+	rootResults := make(map[string][]core.Result, len(f.roots))
+	for _, result := range results {
+		rootResults[result.Root] = append(rootResults[result.Root], result)
+	}
+	summaries := make(map[string]Summary, len(f.roots))
+	for _, root := range f.roots {
+		summary := f.calculateSummary(rootResults[root])
+		summary.Project = f.kinds[root]
+		summaries[root] = summary
+	}
+	return summaries
+}
+
+// ownerSummaries computes a summary for each owner named by the results; a result with
+// several owners counts for each of them
+func (f *JSONFormatter) ownerSummaries(results []core.Result) map[string]Summary {
+	ownerResults := groupResultsByOwner(results)
+	if len(ownerResults) == 0 {
+		return nil
+	}
+	summaries := make(map[string]Summary, len(ownerResults))
+	for owner, issues := range ownerResults {
+		summaries[owner] = f.calculateSummary(issues)
+	}
+	return summaries
+}
+
+// externalSummary summarizes the results in external code on their own, or returns nil when
+// there are none
+func (f *JSONFormatter) externalSummary(results []core.Result) *Summary {
+	var external []core.Result
+	for _, result := range results {
+		if result.External {
+			external = append(external, result)
+		}
+	}
+	if len(external) == 0 {
+		return nil
+	}
+	summary := f.calculateSummary(external)
+	return &summary
+}
+
+// SetFileErrors records files that could not be analyzed, reported in the errors list
+func (f *JSONFormatter) SetFileErrors(errors []core.FileError) {
+	f.fileErrors = errors
+}
+
+// fileErrorMessages renders each file error as "path: message"
+func fileErrorMessages(errors []core.FileError) []string {
+	if len(errors) == 0 {
+		return nil
+	}
+	messages := make([]string, len(errors))
+	for i, err := range errors {
+		messages[i] = err.Error()
+	}
+	return messages
+}
+
 // calculateSummary computes summary statistics from results
 func (f *JSONFormatter) calculateSummary(results []core.Result) Summary {
 	summary := Summary{TotalIssues: len(results)}
@@ -68,6 +230,18 @@ func (f *JSONFormatter) calculateSummary(results []core.Result) Summary {
 		case "info":
 			summary.InfoCount++
 		}
+		if results[i].Blocking {
+			summary.BlockingCount++
+		}
+		if results[i].Uncovered {
+			summary.UncoveredCount++
+		}
+		if results[i].AIGenerated {
+			summary.AIGeneratedCount++
+		}
+		if results[i].External {
+			summary.ExternalCount++
+		}
 		fileSet[results[i].FilePath] = struct{}{}
 	}
 	summary.FileCount = len(fileSet)
@@ -77,6 +251,7 @@ func (f *JSONFormatter) calculateSummary(results []core.Result) Summary {
 // FormatError formats an error as JSON
 func (f *JSONFormatter) FormatError(err error) error {
 	errorOutput := JSONOutput{
+		SchemaVersion: SchemaVersion,
 		Summary: Summary{
 			TotalIssues: 0,
 			ErrorCount:  0,
@@ -109,7 +284,7 @@ func (f *JSONFormatter) PrintFooter() {
 	// No footer for JSON output
 }
 
-// getCurrentTimestamp returns the current timestamp in ISO 8601 format
+// getCurrentTimestamp returns the current time in RFC 3339 format, in UTC
 func getCurrentTimestamp() string {
-	return fmt.Sprintf("%d", 0) // Placeholder - would use time.Now().Format(time.RFC3339)
+	return time.Now().UTC().Format(time.RFC3339)
 }
@@ -0,0 +1,275 @@
+package output_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/output"
+)
+
+func TestJSONFormatter_MatchesSchema(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(output.JSONSchema, &schema); err != nil {
+		t.Fatalf("Invalid schema: %v", err)
+	}
+	if got := schema["properties"].(map[string]interface{})["schema_version"].(map[string]interface{})["const"]; got != output.SchemaVersion {
+		t.Errorf("Expected the schema to pin schema_version %s, got %v", output.SchemaVersion, got)
+	}
+
+	results := []core.Result{
+		{
+			RuleID: "large-function", RuleName: "Large Function", Category: "size", Severity: "warning",
+			Confidence: "high", FilePath: "services/api/main.go", Line: 15, Column: 2, EndLine: 80, EndColumn: 2, Message: "too large",
+			Suggestion: "split it", Module: "example.com/api", Root: "services/api", Owners: []string{"@api"},
+			Blocking: true, Uncovered: true, AIGenerated: true, Fingerprint: "3f6c1e0a",
+		},
+		{RuleID: "console-log", RuleName: "Console Log", Category: "style", Severity: "info", FilePath: "services/web/app.js", Line: 3, Message: "remove it", Root: "services/web"},
+		{RuleID: "code-similarity", RuleName: "Code Similarity", Category: "complexity", Severity: "info", FilePath: "services/api/a.go", Line: 3, Message: "similar", Root: "services/api", Related: &core.Location{FilePath: "services/api/b.go", Line: 9}},
+		{RuleID: "magic-number", RuleName: "Magic Number", Category: "style", Severity: "info", FilePath: "services/web/vendor/lib.js", Line: 8, Message: "name it", Root: "services/web", External: true},
+	}
+	tests := []struct {
+		name  string
+		write func(f *output.JSONFormatter)
+	}{
+		{"results", func(f *output.JSONFormatter) {
+			f.SetRoots([]string{"services/api", "services/web"})
+			f.SetProjectKinds(map[string]string{"services/api": "binary"})
+			f.SetFileErrors([]core.FileError{{FilePath: "broken.go", Message: "syntax error"}})
+			started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			f.SetTop(2)
+			f.SetRunInfo(output.RunInfo{
+				Started: started, Finished: started.Add(1500 * time.Millisecond), ToolVersion: "1.2.3",
+				ConfigHash: "b580663760da50c9", Languages: map[string]int{"go": 4, "reactnative": 1},
+			})
+			f.Format(results)
+		}},
+		{"no results", func(f *output.JSONFormatter) { f.Format(nil) }},
+		{"error", func(f *output.JSONFormatter) { f.FormatError(errors.New("no files found")) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := captureStdout(t, func() { tt.write(output.NewJSONFormatter(false)) })
+			var doc interface{}
+			if err := json.Unmarshal(data, &doc); err != nil {
+				t.Fatalf("Invalid JSON %q: %v", data, err)
+			}
+			for _, problem := range validate(schema, schema, doc, "") {
+				t.Error(problem)
+			}
+		})
+	}
+}
+
+// validate checks a decoded JSON document against the parts of JSON Schema that schema.json
+// uses, returning a description of each mismatch
+func validate(root, schema map[string]interface{}, doc interface{}, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		return validate(root, root["$defs"].(map[string]interface{})[name].(map[string]interface{}), doc, path)
+	}
+
+	var problems []string
+	fail := func(format string, args ...interface{}) {
+		problems = append(problems, "/"+path+": "+fmt.Sprintf(format, args...))
+	}
+	if want, ok := schema["const"]; ok && !reflect.DeepEqual(doc, want) {
+		fail("expected %v, got %v", want, doc)
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, value := range enum {
+			found = found || reflect.DeepEqual(doc, value)
+		}
+		if !found {
+			fail("%v is not one of %v", doc, enum)
+		}
+	}
+	if minimum, ok := schema["minimum"].(float64); ok {
+		if n, isNumber := doc.(float64); isNumber && n < minimum {
+			fail("%v is below %v", n, minimum)
+		}
+	}
+	if schema["format"] == "date-time" {
+		if _, err := time.Parse(time.RFC3339, fmt.Sprint(doc)); err != nil {
+			fail("%v is not a date-time", doc)
+		}
+	}
+
+	switch schema["type"] {
+	case "object":
+		object, ok := doc.(map[string]interface{})
+		if !ok {
+			fail("expected an object, got %v", doc)
+			return problems
+		}
+		required, _ := schema["required"].([]interface{})
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				fail("missing %s", name)
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := properties[name].(map[string]interface{})
+			if !ok {
+				property, ok = schema["additionalProperties"].(map[string]interface{})
+			}
+			if !ok {
+				if schema["additionalProperties"] == false {
+					fail("unexpected property %s", name)
+				}
+				continue
+			}
+			problems = append(problems, validate(root, property, object[name], path+name+"/")...)
+		}
+	case "array":
+		array, ok := doc.([]interface{})
+		if !ok {
+			fail("expected an array, got %v", doc)
+			return problems
+		}
+		items := schema["items"].(map[string]interface{})
+		for i, item := range array {
+			problems = append(problems, validate(root, items, item, fmt.Sprintf("%s%d/", path, i))...)
+		}
+	case "string":
+		if _, ok := doc.(string); !ok {
+			fail("expected a string, got %v", doc)
+		}
+	case "number":
+		if _, ok := doc.(float64); !ok {
+			fail("expected a number, got %v", doc)
+		}
+	case "integer":
+		if n, ok := doc.(float64); !ok || n != float64(int64(n)) {
+			fail("expected an integer, got %v", doc)
+		}
+	case "boolean":
+		if _, ok := doc.(bool); !ok {
+			fail("expected a boolean, got %v", doc)
+		}
+	}
+	return problems
+}
+
+func TestJSONFormatter_RunInfo(t *testing.T) {
+	started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+	formatter := output.NewJSONFormatter(false)
+	formatter.SetRoots([]string{"services/api", "services/web"})
+	formatter.SetRunInfo(output.RunInfo{
+		Started: started, Finished: started.Add(1500 * time.Millisecond), ToolVersion: "1.2.3",
+		ConfigHash: "b580663760da50c9", Languages: map[string]int{"go": 4},
+	})
+	results := []core.Result{{RuleID: "console-log", Severity: "info", FilePath: "app.js", Root: "services/web"}}
+	data := captureStdout(t, func() { formatter.Format(results) })
+
+	var decoded output.JSONOutput
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", data, err)
+	}
+	summary := decoded.Summary
+	if summary.StartedAt != "2026-01-02T02:04:05Z" || summary.FinishedAt != "2026-01-02T02:04:06Z" || summary.DurationSeconds != 1.5 {
+		t.Errorf("Expected the run times in UTC, got %s to %s (%vs)", summary.StartedAt, summary.FinishedAt, summary.DurationSeconds)
+	}
+	if summary.ToolVersion != "1.2.3" || summary.ConfigHash != "b580663760da50c9" || summary.Languages["go"] != 4 {
+		t.Errorf("Expected the tool version, config hash and file counts, got %+v", summary)
+	}
+	if root := decoded.Roots["services/web"]; root.ToolVersion != "" || root.StartedAt != "" {
+		t.Errorf("Expected the run only in the overall summary, got %+v", root)
+	}
+}
+
+func TestJSONFormatter_Top(t *testing.T) {
+	results := []core.Result{
+		{RuleID: "console-log", FilePath: "app.js"},
+		{RuleID: "large-function", FilePath: "app.js"},
+		{RuleID: "console-log", FilePath: "app.js"},
+		{RuleID: "console-log", FilePath: "util.js"},
+		{RuleID: "magic-number", FilePath: "util.js"},
+		{RuleID: "large-function", FilePath: "main.js"},
+	}
+	formatter := output.NewJSONFormatter(false)
+	formatter.SetTop(2)
+	data := captureStdout(t, func() { formatter.Format(results) })
+
+	var decoded output.JSONOutput
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", data, err)
+	}
+	want := &output.TopOffenders{
+		Files: []output.Offender{{Name: "app.js", Count: 3}, {Name: "util.js", Count: 2}},
+		Rules: []output.Offender{{Name: "console-log", Count: 3}, {Name: "large-function", Count: 2}},
+	}
+	if !reflect.DeepEqual(decoded.Summary.Top, want) {
+		t.Errorf("Expected the top files and rules %+v, got %+v", want, decoded.Summary.Top)
+	}
+
+	data = captureStdout(t, func() { output.NewJSONFormatter(false).Format(results) })
+	if strings.Contains(string(data), `"top"`) {
+		t.Errorf("Expected no top offenders without SetTop, got %s", data)
+	}
+}
+
+func TestJSONFormatter_Comparison(t *testing.T) {
+	comparison := core.Comparison{
+		Introduced: []core.Result{
+			{RuleID: "large-function", Severity: "warning", FilePath: "after/main.go", Blocking: true},
+			{RuleID: "console-log", Severity: "info", FilePath: "after/app.js"},
+		},
+		Unchanged: []core.Result{{RuleID: "magic-number", Severity: "info", FilePath: "after/util.go"}},
+	}
+	data := captureStdout(t, func() { output.NewJSONFormatter(false).FormatComparison("before", "after", comparison) })
+
+	var decoded output.ComparisonOutput
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", data, err)
+	}
+	want := output.ComparisonSummary{Introduced: 2, Fixed: 0, Unchanged: 1, BlockingCount: 1}
+	if decoded.Summary != want {
+		t.Errorf("Expected the summary %+v, got %+v", want, decoded.Summary)
+	}
+	if decoded.Before != "before" || decoded.After != "after" || len(decoded.Introduced) != 2 {
+		t.Errorf("Expected the snapshots and the introduced findings, got %+v", decoded)
+	}
+	if !strings.Contains(string(data), `"fixed": []`) {
+		t.Errorf("Expected an empty list of fixed findings, got %s", data)
+	}
+}
+
+func TestJSONFormatter_External(t *testing.T) {
+	results := []core.Result{
+		{RuleID: "large-function", Severity: "warning", FilePath: "main.go", Blocking: true},
+		{RuleID: "large-function", Severity: "warning", FilePath: "vendor/lib/lib.go", External: true},
+		{RuleID: "magic-number", Severity: "info", FilePath: "vendor/lib/lib.go", External: true},
+	}
+	data := captureStdout(t, func() { output.NewJSONFormatter(false).Format(results) })
+
+	var decoded output.JSONOutput
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", data, err)
+	}
+	if decoded.Summary.TotalIssues != 3 || decoded.Summary.ExternalCount != 2 {
+		t.Errorf("Expected 3 findings, 2 of them external, got %+v", decoded.Summary)
+	}
+	want := &output.Summary{TotalIssues: 2, WarnCount: 1, InfoCount: 1, FileCount: 1, ExternalCount: 2}
+	if !reflect.DeepEqual(decoded.External, want) {
+		t.Errorf("Expected the external summary %+v, got %+v", want, decoded.External)
+	}
+
+	data = captureStdout(t, func() { output.NewJSONFormatter(false).Format(results[:1]) })
+	if strings.Contains(string(data), `"external"`) {
+		t.Errorf("Expected no external summary without external findings, got %s", data)
+	}
+}
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// WritePrometheus writes gauges of a run's results in the Prometheus text exposition format,
+// for node_exporter's textfile collector: the findings by severity, by rule and by language,
+// the files analyzed per language, and the duration and time of the run. languageOf names
+// the language of a file, "" for findings about anything else, such as a go.mod file.
+func WritePrometheus(w io.Writer, results []core.Result, run RunInfo, languageOf func(path string) string) error {
+	bySeverity := map[string]int{
+		string(core.SeverityError):   0,
+		string(core.SeverityWarning): 0,
+		string(core.SeverityInfo):    0,
+	}
+	byRule := make(map[string]int)
+	byLanguage := make(map[string]int)
+	for language := range run.Languages {
+		byLanguage[language] = 0
+	}
+	fileLanguages := make(map[string]string)
+	for _, result := range results {
+		bySeverity[result.Severity]++
+		byRule[result.RuleID]++
+		language, ok := fileLanguages[result.FilePath]
+		if !ok {
+			language = languageOf(result.FilePath)
+			if language == "" {
+				language = "other"
+			}
+			fileLanguages[result.FilePath] = language
+		}
+		byLanguage[language]++
+	}
+
+	bw := bufio.NewWriter(w)
+	writeGauge(bw, "agentlint_issues", "Findings of the last run by severity", "severity", bySeverity)
+	writeGauge(bw, "agentlint_rule_issues", "Findings of the last run by rule", "rule", byRule)
+	writeGauge(bw, "agentlint_language_issues", "Findings of the last run by language of the file", "language", byLanguage)
+	writeGauge(bw, "agentlint_files", "Files analyzed by the last run by language", "language", run.Languages)
+
+	fmt.Fprintln(bw, "# HELP agentlint_analysis_duration_seconds Duration of the last run")
+	fmt.Fprintln(bw, "# TYPE agentlint_analysis_duration_seconds gauge")
+	fmt.Fprintf(bw, "agentlint_analysis_duration_seconds %g\n", run.Finished.Sub(run.Started).Seconds())
+	fmt.Fprintln(bw, "# HELP agentlint_last_run_timestamp_seconds When the last run finished, in seconds since the Unix epoch")
+	fmt.Fprintln(bw, "# TYPE agentlint_last_run_timestamp_seconds gauge")
+	fmt.Fprintf(bw, "agentlint_last_run_timestamp_seconds %d\n", run.Finished.Unix())
+	fmt.Fprintln(bw, "# HELP agentlint_info The version and configuration of the last run")
+	fmt.Fprintln(bw, "# TYPE agentlint_info gauge")
+	fmt.Fprintf(bw, "agentlint_info{version=\"%s\",config_hash=\"%s\"} 1\n", escapeLabel(run.ToolVersion), escapeLabel(run.ConfigHash))
+	return bw.Flush()
+}
+
+// writeGauge writes a gauge with a sample per label value, sorted by value
+func writeGauge(w io.Writer, name, help, label string, values map[string]int) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s{%s=\"%s\"} %d\n", name, label, escapeLabel(key), values[key])
+	}
+}
+
+// escapeLabel escapes a label value as the exposition format requires
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// WritePrometheusFile writes the metrics of WritePrometheus to path, replacing the file in
+// one step so the textfile collector never reads it half written
+func WritePrometheusFile(path string, results []core.Result, run RunInfo, languageOf func(path string) string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := WritePrometheus(tmp, results, run, languageOf); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,58 @@
+package output_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/output"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	results := []core.Result{
+		{RuleID: "large-function", Severity: "warning", FilePath: "api/main.go"},
+		{RuleID: "console-log", Severity: "info", FilePath: "web/app.js"},
+		{RuleID: "large-function", Severity: "warning", FilePath: "api/main.go"},
+		{RuleID: "go-mod-replace", Severity: "error", FilePath: "go.mod"},
+	}
+	started := time.Unix(1767322800, 0)
+	run := output.RunInfo{
+		Started: started, Finished: started.Add(2500 * time.Millisecond), ToolVersion: "1.2.3",
+		ConfigHash: "b580663760da50c9", Languages: map[string]int{"go": 4, "reactnative": 2, "python": 1},
+	}
+	languageOf := func(path string) string {
+		switch filepath.Ext(path) {
+		case ".go":
+			return "go"
+		case ".js":
+			return "reactnative"
+		}
+		return ""
+	}
+
+	var buf bytes.Buffer
+	if err := output.WritePrometheus(&buf, results, run, languageOf); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"# TYPE agentlint_issues gauge\n",
+		`agentlint_issues{severity="error"} 1` + "\n",
+		`agentlint_issues{severity="warning"} 2` + "\n",
+		`agentlint_rule_issues{rule="large-function"} 2` + "\n",
+		`agentlint_language_issues{language="go"} 2` + "\n",
+		`agentlint_language_issues{language="other"} 1` + "\n",
+		`agentlint_language_issues{language="python"} 0` + "\n",
+		`agentlint_files{language="reactnative"} 2` + "\n",
+		"agentlint_analysis_duration_seconds 2.5\n",
+		"agentlint_last_run_timestamp_seconds 1767322802\n",
+		`agentlint_info{version="1.2.3",config_hash="b580663760da50c9"} 1` + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// RDFormatter formats results as reviewdog diagnostics (rdjsonl), one JSON object per line,
+// for reviewdog -f=rdjsonl to comment them on pull requests
+type RDFormatter struct {
+	fileErrors []core.FileError
+}
+
+// NewRDFormatter creates a new reviewdog formatter
+func NewRDFormatter() *RDFormatter {
+	return &RDFormatter{}
+}
+
+// rdDiagnostic is reviewdog's Diagnostic message
+// (https://github.com/reviewdog/reviewdog/tree/master/proto/rdf)
+type rdDiagnostic struct {
+	Message  string     `json:"message"`
+	Location rdLocation `json:"location"`
+	Severity string     `json:"severity,omitempty"`
+	Source   rdSource   `json:"source"`
+	Code     rdCode     `json:"code"`
+
+	RelatedLocations []rdRelatedLocation `json:"related_locations,omitempty"`
+}
+
+type rdRelatedLocation struct {
+	Message  string     `json:"message,omitempty"`
+	Location rdLocation `json:"location"`
+}
+
+type rdLocation struct {
+	Path  string   `json:"path"`
+	Range *rdRange `json:"range,omitempty"` // nil for findings about a whole file
+}
+
+type rdRange struct {
+	Start rdPosition  `json:"start"`
+	End   *rdPosition `json:"end,omitempty"` // nil for findings at a single position
+}
+
+type rdPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column,omitempty"`
+}
+
+type rdSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type rdCode struct {
+	Value string `json:"value"`
+}
+
+// rdSeverities maps result severities to reviewdog's
+var rdSeverities = map[string]string{
+	string(core.SeverityError):   "ERROR",
+	string(core.SeverityWarning): "WARNING",
+	string(core.SeverityInfo):    "INFO",
+}
+
+// Format writes a diagnostic per result. Paths are made relative to the working directory,
+// as reviewdog matches them against the files of the diff; the suggestion follows the message.
+func (f *RDFormatter) Format(results []core.Result) error {
+	defer f.printFileErrors()
+
+	wd, _ := os.Getwd()
+	encoder := json.NewEncoder(os.Stdout)
+	for _, result := range results {
+		diagnostic := rdDiagnostic{
+			Message:  result.Message,
+			Location: rdLocation{Path: relativePath(wd, result.FilePath)},
+			Severity: rdSeverities[result.Severity],
+			Source:   rdSource{Name: "agentlint", URL: "https://github.com/CiaranMcAleer/AgentLint"},
+			Code:     rdCode{Value: result.RuleID},
+		}
+		if result.Suggestion != "" {
+			diagnostic.Message += "\n\nSuggestion: " + result.Suggestion
+		}
+		if result.Line > 0 {
+			diagnostic.Location.Range = &rdRange{Start: rdPosition{Line: result.Line, Column: result.Column}}
+			if result.EndLine > 0 {
+				diagnostic.Location.Range.End = &rdPosition{Line: result.EndLine, Column: result.EndColumn}
+			}
+		}
+		if related := result.Related; related != nil {
+			location := rdLocation{Path: relativePath(wd, related.FilePath)}
+			if related.Line > 0 {
+				location.Range = &rdRange{Start: rdPosition{Line: related.Line}}
+			}
+			diagnostic.RelatedLocations = []rdRelatedLocation{{Location: location}}
+		}
+		if err := encoder.Encode(diagnostic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relativePath returns path relative to dir when it is under it, or path unchanged
+func relativePath(dir, path string) string {
+	if dir == "" || !filepath.IsAbs(path) {
+		return path
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// SetFileErrors records files that could not be analyzed, listed on stderr so reviewdog does
+// not read them as diagnostics
+func (f *RDFormatter) SetFileErrors(errors []core.FileError) {
+	f.fileErrors = errors
+}
+
+func (f *RDFormatter) printFileErrors() {
+	for _, err := range f.fileErrors {
+		fmt.Fprintf(os.Stderr, "could not analyze %s\n", err.Error())
+	}
+}
+
+// FormatError formats an error for reviewdog output
+func (f *RDFormatter) FormatError(err error) error {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	return nil
+}
+
+// PrintHeader prints a header for the analysis (no-op for reviewdog)
+func (f *RDFormatter) PrintHeader() {
+	// Every line must be a diagnostic
+}
+
+// PrintFooter prints a footer for the analysis (no-op for reviewdog)
+func (f *RDFormatter) PrintFooter() {
+	// Every line must be a diagnostic
+}
@@ -0,0 +1,61 @@
+package output_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/output"
+)
+
+func TestRDFormatter(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := []core.Result{
+		{RuleID: "large-function", Severity: "warning", FilePath: filepath.Join(wd, "api", "main.go"), Line: 15, Column: 2, Message: "too large", Suggestion: "split it"},
+		{RuleID: "minified-file", Severity: "info", FilePath: "dist/app.js", Message: "minified"},
+		{RuleID: "large-function", Severity: "warning", FilePath: "app.py", Line: 4, EndLine: 70, Message: "too large"},
+		{RuleID: "code-similarity", Severity: "info", FilePath: filepath.Join(wd, "api", "a.go"), Line: 3, Message: "similar", Related: &core.Location{FilePath: filepath.Join(wd, "api", "b.go"), Line: 9}},
+	}
+	data := captureStdout(t, func() { output.NewRDFormatter().Format(results) })
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected a line per result, got %q", data)
+	}
+	var first map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", lines[0], err)
+	}
+	want := map[string]interface{}{
+		"message": "too large\n\nSuggestion: split it",
+		"location": map[string]interface{}{
+			"path":  "api/main.go",
+			"range": map[string]interface{}{"start": map[string]interface{}{"line": 15.0, "column": 2.0}},
+		},
+		"severity": "WARNING",
+		"source":   map[string]interface{}{"name": "agentlint", "url": "https://github.com/CiaranMcAleer/AgentLint"},
+		"code":     map[string]interface{}{"value": "large-function"},
+	}
+	if !reflect.DeepEqual(first, want) {
+		t.Errorf("Expected %v, got %v", want, first)
+	}
+	if strings.Contains(lines[1], `"range"`) || !strings.Contains(lines[1], `"severity":"INFO"`) {
+		t.Errorf("Expected an INFO diagnostic without a range for a whole-file finding, got %s", lines[1])
+	}
+	if want := `"related_locations":[{"location":{"path":"api/b.go","range":{"start":{"line":9}}}}]`; !strings.Contains(lines[3], want) {
+		t.Errorf("Expected the second location as a related location %s, got %s", want, lines[3])
+	}
+	if want := `"range":{"start":{"line":4},"end":{"line":70}}`; !strings.Contains(lines[2], want) {
+		t.Errorf("Expected a range over the whole function %s, got %s", want, lines[2])
+	}
+	if strings.Contains(lines[0], `"end"`) || strings.Contains(lines[0], "related_locations") {
+		t.Errorf("Expected no related locations for a finding about one place, got %s", lines[0])
+	}
+}
@@ -0,0 +1,82 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// DefaultTemplate is the line format used by the template formatter when none is given
+const DefaultTemplate = "{{.FilePath}}:{{.Line}}: {{.RuleID}} {{.Message}}"
+
+// TemplateFormatter formats each result with a user-supplied text/template, one result per line
+type TemplateFormatter struct {
+	tmpl       *template.Template
+	fileErrors []core.FileError
+}
+
+// NewTemplateFormatter parses text as a template over core.Result. The template is also run
+// against an empty result, so a reference to a field that does not exist is reported here
+// rather than halfway through the output.
+func NewTemplateFormatter(text string) (*TemplateFormatter, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	tmpl, err := template.New("result").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output template: %w", err)
+	}
+	if err := tmpl.Execute(io.Discard, core.Result{}); err != nil {
+		return nil, fmt.Errorf("invalid output template: %w", err)
+	}
+	return &TemplateFormatter{tmpl: tmpl}, nil
+}
+
+// Format executes the template for each result
+func (f *TemplateFormatter) Format(results []core.Result) error {
+	defer f.printFileErrors()
+
+	writer := bufio.NewWriter(os.Stdout)
+	for i := range results {
+		if err := f.tmpl.Execute(writer, results[i]); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// SetFileErrors records files that could not be analyzed, listed on stderr so they do not
+// mix with the formatted lines
+func (f *TemplateFormatter) SetFileErrors(errors []core.FileError) {
+	f.fileErrors = errors
+}
+
+func (f *TemplateFormatter) printFileErrors() {
+	for _, err := range f.fileErrors {
+		fmt.Fprintf(os.Stderr, "could not analyze %s\n", err.Error())
+	}
+}
+
+// FormatError formats an error for template output
+func (f *TemplateFormatter) FormatError(err error) error {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	return nil
+}
+
+// PrintHeader prints a header for the analysis (no-op for template output)
+func (f *TemplateFormatter) PrintHeader() {
+	// Only the formatted results are written
+}
+
+// PrintFooter prints a footer for the analysis (no-op for template output)
+func (f *TemplateFormatter) PrintFooter() {
+	// Only the formatted results are written
+}
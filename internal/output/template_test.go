@@ -0,0 +1,64 @@
+package output_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/output"
+)
+
+func TestTemplateFormatter(t *testing.T) {
+	results := []core.Result{
+		{RuleID: "large-function", Severity: "warning", FilePath: "main.go", Line: 3, Message: "too large"},
+		{RuleID: "print-debug", Severity: "info", FilePath: "app.py", Line: 9, Message: "print call", Blocking: true},
+	}
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"default", "", "main.go:3: large-function too large\napp.py:9: print-debug print call\n"},
+		{"custom", "{{.Severity}} {{.FilePath}}:{{.Line}}{{if .Blocking}} blocking{{end}}", "warning main.go:3\ninfo app.py:9 blocking\n"},
+		{"trailing newline", "{{.RuleID}}\n", "large-function\nprint-debug\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, err := output.NewTemplateFormatter(tt.text)
+			if err != nil {
+				t.Fatalf("NewTemplateFormatter failed: %v", err)
+			}
+			got := string(captureStdout(t, func() {
+				if err := formatter.Format(results); err != nil {
+					t.Errorf("Format failed: %v", err)
+				}
+			}))
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTemplateFormatter_Invalid(t *testing.T) {
+	for _, text := range []string{"{{.FilePath", "{{.NoSuchField}}", "{{.Related.FilePath}}"} {
+		if _, err := output.NewTemplateFormatter(text); err == nil || !strings.Contains(err.Error(), "invalid output template") {
+			t.Errorf("Expected template %q to be rejected, got %v", text, err)
+		}
+	}
+}
+
+func TestTemplateFormatter_ExecutionError(t *testing.T) {
+	// The empty result checked when parsing never takes the branch, so the missing field is
+	// only found once a result has a line
+	formatter, err := output.NewTemplateFormatter("{{if .Line}}{{.NoSuchField}}{{end}}")
+	if err != nil {
+		t.Fatalf("NewTemplateFormatter failed: %v", err)
+	}
+	captureStdout(t, func() {
+		err = formatter.Format([]core.Result{{RuleID: "large-function", FilePath: "main.go", Line: 3}})
+	})
+	if err == nil || !strings.Contains(err.Error(), "NoSuchField") {
+		t.Errorf("Expected an error naming the missing field, got %v", err)
+	}
+}
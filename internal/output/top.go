@@ -0,0 +1,53 @@
+package output
+
+import (
+	"sort"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// Offender is a file or rule with the number of findings it has
+type Offender struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// TopOffenders lists the files with the most findings and the rules found most often, to
+// tell where cleanup pays off first
+type TopOffenders struct {
+	Files []Offender `json:"files"`
+	Rules []Offender `json:"rules"`
+}
+
+// TopOffendersOf returns the n files and the n rules with the most results, or nil when n is 0
+// or less
+func TopOffendersOf(results []core.Result, n int) *TopOffenders {
+	if n <= 0 {
+		return nil
+	}
+	files := make(map[string]int)
+	rules := make(map[string]int)
+	for _, result := range results {
+		files[result.FilePath]++
+		rules[result.RuleID]++
+	}
+	return &TopOffenders{Files: mostFrequent(files, n), Rules: mostFrequent(rules, n)}
+}
+
+// mostFrequent returns the n names with the highest counts, most first and by name on a tie
+func mostFrequent(counts map[string]int, n int) []Offender {
+	offenders := make([]Offender, 0, len(counts))
+	for name, count := range counts {
+		offenders = append(offenders, Offender{Name: name, Count: count})
+	}
+	sort.Slice(offenders, func(i, j int) bool {
+		if offenders[i].Count != offenders[j].Count {
+			return offenders[i].Count > offenders[j].Count
+		}
+		return offenders[i].Name < offenders[j].Name
+	})
+	if len(offenders) > n {
+		offenders = offenders[:n]
+	}
+	return offenders
+}
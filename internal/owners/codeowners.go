@@ -0,0 +1,182 @@
+// Package owners maps files to the teams that own them, as declared in a CODEOWNERS file
+package owners
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Locations are the places a repository's CODEOWNERS file is looked for, relative to the
+// repository root, in the order GitHub searches them
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Codeowners holds the rules of a CODEOWNERS file. Paths are matched relative to Root, the
+// repository root.
+type Codeowners struct {
+	Root  string
+	rules []ownerRule
+}
+
+// ownerRule is one line of a CODEOWNERS file
+type ownerRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// Discover finds the CODEOWNERS file of the repository containing dir and loads it. It
+// returns nil when the repository has no CODEOWNERS file, or dir is not inside a repository.
+func Discover(dir string) (*Codeowners, error) {
+	root := RepositoryRoot(dir)
+	if root == "" {
+		return nil, nil
+	}
+	for _, location := range Locations {
+		path := filepath.Join(root, filepath.FromSlash(location))
+		if _, err := os.Stat(path); err == nil {
+			return Load(path, root)
+		}
+	}
+	return nil, nil
+}
+
+// RepositoryRoot returns the closest directory at or above dir that holds .git, or "" when
+// dir is not inside a repository
+func RepositoryRoot(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Load reads a CODEOWNERS file whose patterns are relative to root
+func Load(path, root string) (*Codeowners, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if c.Root, err = filepath.Abs(root); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Parse reads the rules of a CODEOWNERS file. Each line holds a pattern followed by its
+// owners; blank lines and text after a # are ignored, and a pattern without owners leaves
+// the files it matches unowned.
+func Parse(r io.Reader) (*Codeowners, error) {
+	c := &Codeowners{}
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		fields := strings.Fields(stripComment(scanner.Text()))
+		if len(fields) == 0 {
+			continue
+		}
+		pattern, err := regexp.Compile(patternToRegexp(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid pattern %q: %w", lineNum, fields[0], err)
+		}
+		c.rules = append(c.rules, ownerRule{pattern: pattern, owners: fields[1:]})
+	}
+	return c, scanner.Err()
+}
+
+// Owners returns the owners of the file at path, absolute or relative to Root. The last rule
+// matching the file wins, as on GitHub; nil means the file is unowned.
+func (c *Codeowners) Owners(path string) []string {
+	if c == nil {
+		return nil
+	}
+	if filepath.IsAbs(path) && c.Root != "" {
+		rel, err := filepath.Rel(c.Root, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return nil
+		}
+		path = rel
+	}
+	path = filepath.ToSlash(path)
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].pattern.MatchString(path) {
+			if len(c.rules[i].owners) == 0 {
+				return nil
+			}
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// stripComment removes a # comment from a line; an escaped \# starts a pattern instead
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] != '\\') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// patternToRegexp translates a CODEOWNERS pattern, which follows .gitignore rules, into an
+// anchored regular expression. A pattern with a leading or inner slash is relative to the
+// repository root, otherwise it matches at any depth; a pattern naming a directory matches
+// every file under it, except that "dir/*" matches only the files directly in dir.
+func patternToRegexp(pattern string) string {
+	pattern = strings.ReplaceAll(pattern, `\#`, "#")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored && !strings.HasPrefix(pattern, "**") {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case pattern[i:] == "/**":
+			b.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	switch {
+	case dirOnly:
+		b.WriteString("/.*")
+	case !strings.HasSuffix(pattern, "/*") && !strings.HasSuffix(pattern, "/**"):
+		b.WriteString("(?:/.*)?")
+	}
+	b.WriteString("$")
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package owners
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testCodeowners = `# Default owners
+*                   @org/core
+
+*.js                @org/web
+/docs/*             @org/docs
+apps/               @org/apps
+/build/logs/        @org/infra
+**/generated        @org/codegen
+services/billing    @org/billing @alice
+services/billing/vendored
+\#notes.md          @org/notes  # escaped hash
+`
+
+func TestOwners(t *testing.T) {
+	c, err := Parse(strings.NewReader(testCodeowners))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"main.go", "@org/core"},
+		{"web/app.js", "@org/web"},
+		{"docs/index.md", "@org/docs"},
+		{"docs/guides/setup.md", "@org/core"},
+		{"apps/mobile/screen.ts", "@org/apps"},
+		{"src/apps/mobile/screen.ts", "@org/apps"},
+		{"build/logs/out.txt", "@org/infra"},
+		{"src/build/logs/out.txt", "@org/core"},
+		{"api/generated/client.go", "@org/codegen"},
+		{"generated/client.js", "@org/codegen"},
+		{"services/billing/invoice.py", "@org/billing @alice"},
+		{"services/billing/vendored/lib.py", ""},
+		{"lib/services/billing/invoice.py", "@org/core"},
+		{"#notes.md", "@org/notes"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(c.Owners(tt.path), " "); got != tt.want {
+			t.Errorf("Owners(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestOwnersAbsolutePaths(t *testing.T) {
+	c, err := Parse(strings.NewReader("/src/ @org/src\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	c.Root = filepath.Join(string(filepath.Separator), "repo")
+
+	if got := c.Owners(filepath.Join(c.Root, "src", "main.go")); len(got) != 1 || got[0] != "@org/src" {
+		t.Errorf("expected @org/src for a file under the root, got %v", got)
+	}
+	if got := c.Owners(filepath.Join(string(filepath.Separator), "other", "src", "main.go")); got != nil {
+		t.Errorf("expected no owners outside the root, got %v", got)
+	}
+
+	var none *Codeowners
+	if got := none.Owners("src/main.go"); got != nil {
+		t.Errorf("expected no owners without a CODEOWNERS file, got %v", got)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "services", "api")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if c, err := Discover(sub); err != nil || c != nil {
+		t.Fatalf("expected nothing outside a repository, got %v, %v", c, err)
+	}
+
+	os.Mkdir(filepath.Join(root, ".git"), 0755)
+	if c, err := Discover(sub); err != nil || c != nil {
+		t.Fatalf("expected nothing without a CODEOWNERS file, got %v, %v", c, err)
+	}
+
+	os.WriteFile(filepath.Join(root, "CODEOWNERS"), []byte("* @org/root\n"), 0644)
+	os.Mkdir(filepath.Join(root, ".github"), 0755)
+	os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"), []byte("services/ @org/services\n"), 0644)
+
+	c, err := Discover(sub)
+	if err != nil || c == nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if c.Root != root {
+		t.Errorf("expected root %s, got %s", root, c.Root)
+	}
+	if got := c.Owners(filepath.Join(sub, "main.go")); len(got) != 1 || got[0] != "@org/services" {
+		t.Errorf("expected .github/CODEOWNERS to take precedence, got %v", got)
+	}
+}
@@ -0,0 +1,256 @@
+// Package patch reads unified diffs, as written by diff -u and git diff, and applies them to
+// the files they change
+package patch
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// File is the change a patch makes to one file
+type File struct {
+	OldPath string // the path before the change, "" for a file the patch creates
+	NewPath string // the path after the change, "" for a file the patch deletes
+	Hunks   []Hunk
+}
+
+// Hunk is one block of changed lines, with the unchanged lines around it
+type Hunk struct {
+	OldStart, OldLines int // the lines the hunk replaces in the old file
+	NewStart, NewLines int // the lines it leaves in the new file
+	// Lines holds each line of the hunk after its ' ', '-' or '+' prefix
+	Lines []string
+}
+
+// Parse reads the changes of a unified diff. Text around the file changes, such as a commit
+// message or git's extended headers, is skipped; a/ and b/ prefixes are stripped from the
+// paths of git diffs. A path that is absolute or climbs out of the tree with .. is an error,
+// since the paths are joined to the directory the patch is checked against.
+func Parse(r io.Reader) ([]File, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	var files []File
+	var oldPath string
+	var hunk *Hunk
+	var oldLeft, newLeft int // the lines of the current hunk not read yet
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if oldLeft > 0 || newLeft > 0 {
+			if err := addHunkLine(hunk, line, &oldLeft, &newLeft); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			oldPath = headerPath(line[4:])
+		case strings.HasPrefix(line, "+++ "):
+			file := newFile(oldPath, headerPath(line[4:]))
+			if err := checkPaths(file); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			files = append(files, file)
+		case strings.HasPrefix(line, "@@ "):
+			if len(files) == 0 {
+				return nil, fmt.Errorf("line %d: hunk before any file header", lineNum)
+			}
+			h, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			last := &files[len(files)-1]
+			last.Hunks = append(last.Hunks, h)
+			hunk = &last.Hunks[len(last.Hunks)-1]
+			oldLeft, newLeft = h.OldLines, h.NewLines
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if oldLeft > 0 || newLeft > 0 {
+		return nil, fmt.Errorf("line %d: the last hunk ends early", lineNum)
+	}
+	return files, nil
+}
+
+// newFile returns the change to the file at oldPath and newPath, stripping the a/ and b/
+// prefixes git adds
+func newFile(oldPath, newPath string) File {
+	gitOld := oldPath == "" || strings.HasPrefix(oldPath, "a/")
+	gitNew := newPath == "" || strings.HasPrefix(newPath, "b/")
+	if gitOld && gitNew {
+		oldPath, newPath = strings.TrimPrefix(oldPath, "a/"), strings.TrimPrefix(newPath, "b/")
+	}
+	return File{OldPath: oldPath, NewPath: newPath}
+}
+
+// checkPaths reports a path of file that is not local to the tree the patch applies to
+func checkPaths(file File) error {
+	for _, path := range []string{file.OldPath, file.NewPath} {
+		if path != "" && !filepath.IsLocal(filepath.FromSlash(path)) {
+			return fmt.Errorf("path %q is outside the tree", path)
+		}
+	}
+	return nil
+}
+
+// headerPath returns the path of a ---/+++ header, without the timestamp diff -u adds after a
+// tab, or "" for /dev/null
+func headerPath(header string) string {
+	if tab := strings.IndexByte(header, '\t'); tab >= 0 {
+		header = header[:tab]
+	}
+	header = strings.TrimSpace(header)
+	if header == "/dev/null" {
+		return ""
+	}
+	if unquoted, err := strconv.Unquote(header); err == nil {
+		return unquoted
+	}
+	return header
+}
+
+// parseHunkHeader reads a "@@ -old,count +new,count @@" line; an omitted count is 1
+func parseHunkHeader(line string) (Hunk, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || fields[3] != "@@" || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return Hunk{}, fmt.Errorf("malformed hunk header %q", line)
+	}
+	var h Hunk
+	var err error
+	if h.OldStart, h.OldLines, err = parseRange(fields[1][1:]); err != nil {
+		return Hunk{}, fmt.Errorf("malformed hunk header %q: %w", line, err)
+	}
+	if h.NewStart, h.NewLines, err = parseRange(fields[2][1:]); err != nil {
+		return Hunk{}, fmt.Errorf("malformed hunk header %q: %w", line, err)
+	}
+	return h, nil
+}
+
+func parseRange(text string) (int, int, error) {
+	startText, countText, hasCount := strings.Cut(text, ",")
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return 0, 0, err
+	}
+	count := 1
+	if hasCount {
+		if count, err = strconv.Atoi(countText); err != nil {
+			return 0, 0, err
+		}
+	}
+	return start, count, nil
+}
+
+// addHunkLine adds a line of a hunk's body, counting it off the old and new lines left. An
+// empty line is an unchanged empty line, as some editors strip the space before it. "\ No
+// newline at end of file" markers are dropped, as the analyzers do not tell a missing final
+// newline apart.
+func addHunkLine(h *Hunk, line string, oldLeft, newLeft *int) error {
+	if line == "" {
+		line = " "
+	}
+	switch line[0] {
+	case ' ':
+		*oldLeft--
+		*newLeft--
+	case '-':
+		*oldLeft--
+	case '+':
+		*newLeft--
+	case '\\':
+		return nil
+	default:
+		return fmt.Errorf("unexpected line %q in a hunk", line)
+	}
+	if *oldLeft < 0 || *newLeft < 0 {
+		return fmt.Errorf("hunk has more lines than its header %d,%d", h.OldLines, h.NewLines)
+	}
+	h.Lines = append(h.Lines, line)
+	return nil
+}
+
+// AddedLines returns the numbers of the lines of the new file the patch adds or changes
+func (f File) AddedLines() map[int]bool {
+	added := make(map[int]bool)
+	for _, h := range f.Hunks {
+		lineNum := h.NewStart
+		for _, line := range h.Lines {
+			switch line[0] {
+			case ' ':
+				lineNum++
+			case '+':
+				added[lineNum] = true
+				lineNum++
+			}
+		}
+	}
+	return added
+}
+
+// Reverse returns the patch undoing f
+func (f File) Reverse() File {
+	reversed := File{OldPath: f.NewPath, NewPath: f.OldPath}
+	for _, h := range f.Hunks {
+		r := Hunk{OldStart: h.NewStart, OldLines: h.NewLines, NewStart: h.OldStart, NewLines: h.OldLines}
+		for _, line := range h.Lines {
+			switch line[0] {
+			case '-':
+				line = "+" + line[1:]
+			case '+':
+				line = "-" + line[1:]
+			}
+			r.Lines = append(r.Lines, line)
+		}
+		reversed.Hunks = append(reversed.Hunks, r)
+	}
+	return reversed
+}
+
+// Apply returns the text of the file after the change, given its text before. The unchanged
+// and removed lines of every hunk must match the old text at the lines the hunk names;
+// trailing whitespace is ignored when comparing them.
+func (f File) Apply(old string) (string, error) {
+	var lines []string
+	if old != "" {
+		lines = strings.Split(strings.TrimSuffix(old, "\n"), "\n")
+	}
+
+	var out []string
+	next := 0 // the index in lines of the first line not copied yet
+	for i, h := range f.Hunks {
+		start := h.OldStart - 1
+		if h.OldLines == 0 {
+			start = h.OldStart // a hunk that only adds lines names the line it follows
+		}
+		if start < next || start > len(lines) {
+			return "", fmt.Errorf("hunk %d does not apply: line %d is out of order or past the end", i+1, h.OldStart)
+		}
+		out = append(out, lines[next:start]...)
+		next = start
+		for _, line := range h.Lines {
+			if line[0] == '+' {
+				out = append(out, line[1:])
+				continue
+			}
+			if next >= len(lines) || strings.TrimRight(lines[next], " \t\r") != strings.TrimRight(line[1:], " \t\r") {
+				return "", fmt.Errorf("hunk %d does not apply: line %d differs", i+1, next+1)
+			}
+			if line[0] == ' ' {
+				out = append(out, lines[next])
+			}
+			next++
+		}
+	}
+	out = append(out, lines[next:]...)
+	if len(out) == 0 {
+		return "", nil
+	}
+	return strings.Join(out, "\n") + "\n", nil
+}
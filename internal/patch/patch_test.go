@@ -0,0 +1,121 @@
+package patch
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testPatch = `From 1a2b3c Mon Sep 17 00:00:00 2001
+Subject: [PATCH] Add a helper
+
+diff --git a/cart.go b/cart.go
+index 83db48f..bf269f4 100644
+--- a/cart.go
++++ b/cart.go
+@@ -2,4 +2,5 @@ package cart
+ 
+ func Total(items []int) int {
+-	return 0
++	sum := 0
++	return sum
+ }
+@@ -8 +9,2 @@ func Empty() bool {
+ var debug = false
++var verbose = false
+diff --git a/notes.go b/notes.go
+new file mode 100644
+--- /dev/null
++++ b/notes.go
+@@ -0,0 +1,2 @@
++package cart
++// Notes
+`
+
+const testOld = `package cart
+
+func Total(items []int) int {
+	return 0
+}
+
+func Empty() bool { return true }
+var debug = false
+`
+
+const testNew = `package cart
+
+func Total(items []int) int {
+	sum := 0
+	return sum
+}
+
+func Empty() bool { return true }
+var debug = false
+var verbose = false
+`
+
+func TestParse(t *testing.T) {
+	files, err := Parse(strings.NewReader(testPatch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 files, got %+v", files)
+	}
+	if files[0].OldPath != "cart.go" || files[0].NewPath != "cart.go" || len(files[0].Hunks) != 2 {
+		t.Errorf("Expected cart.go changed by 2 hunks, got %+v", files[0])
+	}
+	if h := files[0].Hunks[1]; h.OldStart != 8 || h.OldLines != 1 || h.NewStart != 9 || h.NewLines != 2 {
+		t.Errorf("Expected a hunk replacing line 8 with lines 9-10, got %+v", h)
+	}
+	if files[1].OldPath != "" || files[1].NewPath != "notes.go" {
+		t.Errorf("Expected notes.go created, got %+v", files[1])
+	}
+
+	if got, want := files[0].AddedLines(), map[int]bool{4: true, 5: true, 10: true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected added lines %v, got %v", want, got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"hunk before header": "@@ -1 +1 @@\n-a\n+b\n",
+		"malformed header":   "--- a/x\n+++ b/x\n@@ -1 1 @@\n",
+		"short hunk":         "--- a/x\n+++ b/x\n@@ -1,2 +1,2 @@\n a\n",
+		"unexpected line":    "--- a/x\n+++ b/x\n@@ -1,2 +1,2 @@\n a\n*b\n",
+		"escaping new path":  "--- /dev/null\n+++ b/../../../tmp/pwned.go\n@@ -0,0 +1 @@\n+package x\n",
+		"escaping old path":  "--- a/../secret.go\n+++ b/x.go\n@@ -1 +1 @@\n-a\n+b\n",
+		"absolute new path":  "--- /dev/null\n+++ /tmp/pwned.go\n@@ -0,0 +1 @@\n+package x\n",
+		"absolute old path":  "--- /etc/passwd\n+++ x.go\n@@ -1 +1 @@\n-a\n+b\n",
+	}
+	for name, patch := range tests {
+		if _, err := Parse(strings.NewReader(patch)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	files, err := Parse(strings.NewReader(testPatch))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := files[0].Apply(testOld)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != testNew {
+		t.Errorf("Expected\n%s\ngot\n%s", testNew, got)
+	}
+	if got, err := files[1].Apply(""); err != nil || got != "package cart\n// Notes\n" {
+		t.Errorf("Expected the new file, got %q (%v)", got, err)
+	}
+
+	if _, err := files[0].Apply(testNew); err == nil {
+		t.Error("Expected the patch not to apply twice")
+	}
+	if got, err := files[0].Reverse().Apply(testNew); err != nil || got != testOld {
+		t.Errorf("Expected the reversed patch to restore the old text, got %q (%v)", got, err)
+	}
+}
@@ -11,19 +11,18 @@ import (
 )
 
 var (
-	mu          sync.Mutex
-	profEnabled bool
-	cpuProfile  *os.File
-	memProfile  *os.File
-	traceFile   *os.File
+	mu         sync.Mutex
+	cpuProfile *os.File
+	memProfile *os.File
+	traceFile  *os.File
 )
 
 func StartCPUProfile(filename string) error {
 	mu.Lock()
 	defer mu.Unlock()
 
-	if profEnabled {
-		return fmt.Errorf("profiling already enabled")
+	if cpuProfile != nil {
+		return fmt.Errorf("CPU profiling already enabled")
 	}
 
 	f, err := os.Create(filename)
@@ -37,7 +36,6 @@ func StartCPUProfile(filename string) error {
 	}
 
 	cpuProfile = f
-	profEnabled = true
 	return nil
 }
 
@@ -45,12 +43,11 @@ func StopCPUProfile() {
 	mu.Lock()
 	defer mu.Unlock()
 
-	if profEnabled && cpuProfile != nil {
+	if cpuProfile != nil {
 		pprof.StopCPUProfile()
 		cpuProfile.Close()
 		cpuProfile = nil
 	}
-	profEnabled = false
 }
 
 func StartMemProfile(filename string) error {
@@ -93,8 +90,8 @@ func StartTrace(filename string) error {
 	mu.Lock()
 	defer mu.Unlock()
 
-	if profEnabled {
-		return fmt.Errorf("profiling already enabled")
+	if traceFile != nil {
+		return fmt.Errorf("tracing already enabled")
 	}
 
 	f, err := os.Create(filename)
@@ -108,7 +105,6 @@ func StartTrace(filename string) error {
 	}
 
 	traceFile = f
-	profEnabled = true
 	return nil
 }
 
@@ -116,12 +112,11 @@ func StopTrace() {
 	mu.Lock()
 	defer mu.Unlock()
 
-	if profEnabled && traceFile != nil {
+	if traceFile != nil {
 		trace.Stop()
 		traceFile.Close()
 		traceFile = nil
 	}
-	profEnabled = false
 }
 
 type ProfileStats struct {
@@ -173,14 +168,22 @@ type TimingStats struct {
 	Elapsed     time.Duration
 	FileCount   int
 	ResultCount int
+	Languages   map[string]int // files analyzed per language, see AddFiles
 }
 
 func NewTimingStats() *TimingStats {
 	return &TimingStats{
 		StartTime: time.Now(),
+		Languages: make(map[string]int),
 	}
 }
 
+// AddFiles records that count files of a language are analyzed
+func (t *TimingStats) AddFiles(language string, count int) {
+	t.Languages[language] += count
+	t.FileCount += count
+}
+
 func (t *TimingStats) Finish(fileCount, resultCount int) {
 	t.EndTime = time.Now()
 	t.Elapsed = t.EndTime.Sub(t.StartTime)
@@ -0,0 +1,140 @@
+package profiling
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+var (
+	ruleMu           sync.Mutex
+	ruleProfiling    bool
+	ruleTimings      = make(map[string]*Timing)
+	fileTimings      = make(map[string]*Timing)
+	defaultTableRows = 10
+)
+
+// Timing accumulates the time spent in repeated calls to a rule, or in analyzing a file
+type Timing struct {
+	Name  string
+	Calls int
+	Total time.Duration
+}
+
+// Average returns the mean duration of a call
+func (t Timing) Average() time.Duration {
+	if t.Calls == 0 {
+		return 0
+	}
+	return t.Total / time.Duration(t.Calls)
+}
+
+// EnableRuleProfiling starts recording per-rule and per-file timings. It must be called
+// before analysis starts.
+func EnableRuleProfiling() {
+	ruleMu.Lock()
+	defer ruleMu.Unlock()
+
+	ruleProfiling = true
+	ruleTimings = make(map[string]*Timing)
+	fileTimings = make(map[string]*Timing)
+}
+
+// TrackRule records the time a rule has spent since start. It does nothing unless rule
+// profiling is enabled.
+func TrackRule(ruleID string, start time.Time) {
+	if !ruleProfiling {
+		return
+	}
+	record(ruleTimings, ruleID, time.Since(start))
+}
+
+// RecordRule records time a rule has spent, for callers that accumulate it themselves.
+// It does nothing unless rule profiling is enabled.
+func RecordRule(ruleID string, elapsed time.Duration) {
+	if !ruleProfiling {
+		return
+	}
+	record(ruleTimings, ruleID, elapsed)
+}
+
+// RuleProfilingEnabled reports whether per-rule timings are being recorded
+func RuleProfilingEnabled() bool {
+	return ruleProfiling
+}
+
+// TrackFile records the time spent analyzing a file since start. It does nothing unless
+// rule profiling is enabled.
+func TrackFile(path string, start time.Time) {
+	if !ruleProfiling {
+		return
+	}
+	record(fileTimings, path, time.Since(start))
+}
+
+func record(timings map[string]*Timing, name string, elapsed time.Duration) {
+	ruleMu.Lock()
+	defer ruleMu.Unlock()
+
+	timing, ok := timings[name]
+	if !ok {
+		timing = &Timing{Name: name}
+		timings[name] = timing
+	}
+	timing.Calls++
+	timing.Total += elapsed
+}
+
+// SlowestRules returns the rule timings ordered from most to least total time
+func SlowestRules() []Timing {
+	return sortedTimings(ruleTimings)
+}
+
+// SlowestFiles returns the file timings ordered from most to least total time
+func SlowestFiles() []Timing {
+	return sortedTimings(fileTimings)
+}
+
+func sortedTimings(timings map[string]*Timing) []Timing {
+	ruleMu.Lock()
+	defer ruleMu.Unlock()
+
+	sorted := make([]Timing, 0, len(timings))
+	for _, timing := range timings {
+		sorted = append(sorted, *timing)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Total != sorted[j].Total {
+			return sorted[i].Total > sorted[j].Total
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// PrintRuleProfile writes tables of the slowest rules and files. A limit of 0 or less
+// prints the default number of rows.
+func PrintRuleProfile(w io.Writer, limit int) {
+	if limit <= 0 {
+		limit = defaultTableRows
+	}
+	printTimingTable(w, "Slowest Rules", "RULE", SlowestRules(), limit)
+	printTimingTable(w, "Slowest Files", "FILE", SlowestFiles(), limit)
+}
+
+func printTimingTable(w io.Writer, title, column string, timings []Timing, limit int) {
+	fmt.Fprintf(w, "=== %s ===\n", title)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\tCALLS\tTOTAL\tAVG\n", column)
+	for i, timing := range timings {
+		if i == limit {
+			break
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%v\t%v\n", timing.Name, timing.Calls, timing.Total.Round(time.Microsecond), timing.Average().Round(time.Microsecond))
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "==========================\n")
+}
@@ -0,0 +1,158 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// Coordinator shards the files of an analysis across remote workers
+type Coordinator struct {
+	workers []string
+	token   string
+	client  *http.Client
+}
+
+// NewCoordinator creates a coordinator for the workers at the given addresses, each a
+// host:port or a URL, started with token. A worker that has not answered within timeout
+// fails the run.
+func NewCoordinator(workers []string, token string, timeout time.Duration) *Coordinator {
+	return &Coordinator{workers: workers, token: token, client: &http.Client{Timeout: timeout}}
+}
+
+// Run analyzes the files under root on the workers and returns the findings along with the
+// files that could not be analyzed, in the order analyzing them locally would give. The files
+// are split into one contiguous share per worker. Every worker must have the repository
+// checked out at the same revision; a worker that cannot be reached or fails fails the run.
+func (c *Coordinator) Run(ctx context.Context, root string, filesByLanguage map[string][]string, cfg core.Config) ([]core.Result, []core.FileError, error) {
+	files, err := relativeFiles(root, filesByLanguage)
+	if err != nil || len(files) == 0 {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	shards := shardFiles(files, len(c.workers))
+	responses := make([]Response, len(shards))
+	var mu sync.Mutex
+	var firstErr error
+	var wg sync.WaitGroup
+	for i, shard := range shards {
+		wg.Add(1)
+		go func(i int, shard []File) {
+			defer wg.Done()
+			resp, err := c.send(ctx, c.workers[i], Request{Version: ProtocolVersion, Config: cfg, Files: shard})
+			if err != nil {
+				// the first failure cancels the other requests, which then fail too
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("worker %s: %w", c.workers[i], err)
+				}
+				mu.Unlock()
+				cancel()
+				return
+			}
+			responses[i] = resp
+		}(i, shard)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+
+	var results []core.Result
+	var fileErrors []core.FileError
+	for _, resp := range responses {
+		for _, result := range resp.Results {
+			result.FilePath = filepath.Join(root, filepath.FromSlash(result.FilePath))
+			results = append(results, result)
+		}
+		for _, fileErr := range resp.Errors {
+			fileErr.FilePath = filepath.Join(root, filepath.FromSlash(fileErr.FilePath))
+			fileErrors = append(fileErrors, fileErr)
+		}
+	}
+	return results, fileErrors, nil
+}
+
+// send posts a request to a worker and decodes its response
+func (c *Coordinator) send(ctx context.Context, worker string, req Request) (Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, workerURL(worker), bytes.NewReader(body))
+	if err != nil {
+		return Response{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(TokenHeader, c.token)
+
+	httpResp, err := c.client.Do(httpReq)
+	if err != nil {
+		return Response{}, err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(httpResp.Body, 1024))
+		return Response{}, fmt.Errorf("%s: %s", httpResp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var resp Response
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return Response{}, fmt.Errorf("invalid response: %w", err)
+	}
+	return resp, nil
+}
+
+// workerURL returns the analysis URL of a worker given as a host:port or a URL
+func workerURL(worker string) string {
+	if !strings.Contains(worker, "://") {
+		worker = "http://" + worker
+	}
+	return strings.TrimSuffix(worker, "/") + AnalyzePath
+}
+
+// relativeFiles lists the files to analyze by language, in the order of the local engine,
+// with paths relative to root
+func relativeFiles(root string, filesByLanguage map[string][]string) ([]File, error) {
+	languageNames := make([]string, 0, len(filesByLanguage))
+	for language := range filesByLanguage {
+		languageNames = append(languageNames, language)
+	}
+	sort.Strings(languageNames)
+
+	var files []File
+	for _, language := range languageNames {
+		for _, path := range filesByLanguage[language] {
+			rel, err := filepath.Rel(root, path)
+			if err != nil || !filepath.IsLocal(rel) {
+				return nil, fmt.Errorf("%s is outside the repository root %s, so workers cannot find it", path, root)
+			}
+			files = append(files, File{Language: language, Path: filepath.ToSlash(rel)})
+		}
+	}
+	return files, nil
+}
+
+// shardFiles splits files into at most n contiguous shards of nearly equal size
+func shardFiles(files []File, n int) [][]File {
+	if n > len(files) {
+		n = len(files)
+	}
+	shards := make([][]File, n)
+	for i := range shards {
+		shards[i] = files[i*len(files)/n : (i+1)*len(files)/n]
+	}
+	return shards
+}
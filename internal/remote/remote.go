@@ -0,0 +1,45 @@
+// Package remote shards the per-file analysis of a large repository across agentlint workers
+// on other machines. A worker serves the files of its own checkout of the repository over
+// HTTP, and a coordinator sends each worker a share of the files, as paths relative to the
+// repository root, along with the configuration to analyze them with, and merges the findings.
+// Every request carries a token shared by the coordinator and its workers, as workers send
+// back the messages and snippets of the code they analyze.
+package remote
+
+import (
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// ProtocolVersion is the version of the requests and responses exchanged with workers. A
+// worker refuses requests of another version, so a fleet running mixed releases fails loudly
+// rather than analyzing with settings it does not understand.
+const ProtocolVersion = 1
+
+// AnalyzePath is the path workers serve analysis requests on
+const AnalyzePath = "/v1/analyze"
+
+// TokenHeader is the request header carrying the token a worker was started with
+const TokenHeader = "X-Agentlint-Token"
+
+// TokenEnv names the environment variable holding the token, on workers and coordinators alike
+const TokenEnv = "AGENTLINT_WORKER_TOKEN"
+
+// File is a file for a worker to analyze
+type File struct {
+	Language string `json:"language"` // name of the analyzer, as registered in languages.Registry
+	Path     string `json:"path"`     // slash-separated path relative to the repository root
+}
+
+// Request asks a worker to analyze files
+type Request struct {
+	Version int         `json:"version"`
+	Config  core.Config `json:"config"`
+	Files   []File      `json:"files"`
+}
+
+// Response holds the findings of a worker, with file paths relative to the repository root,
+// in the order of the files requested
+type Response struct {
+	Results []core.Result    `json:"results"`
+	Errors  []core.FileError `json:"errors"`
+}
@@ -0,0 +1,210 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+)
+
+// lineCounter reports the line count of each file, and fails on files it cannot read
+type lineCounter struct{}
+
+func (lineCounter) Name() string                  { return "text" }
+func (lineCounter) SupportedExtensions() []string { return []string{".txt"} }
+func (lineCounter) Analyze(ctx context.Context, filePath string, config core.Config) ([]core.Result, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Count(string(data), "\n")
+	return []core.Result{{
+		RuleID:   "line-count",
+		FilePath: filePath,
+		Line:     1,
+		Message:  fmt.Sprintf("%d lines, max %d", lines, config.Rules.FileSize.MaxLines),
+	}}, nil
+}
+
+const testToken = "s3cret"
+
+func newTestServer(t *testing.T, root string) *httptest.Server {
+	server := httptest.NewServer(NewServer(root, 2, testToken, func(core.Config) *languages.Registry {
+		registry := languages.NewRegistry()
+		registry.Register(lineCounter{})
+		return registry
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCoordinator_Run(t *testing.T) {
+	root := t.TempDir()
+	var files []string
+	for i := 1; i <= 5; i++ {
+		path := filepath.Join(root, "pkg", fmt.Sprintf("f%d.txt", i))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(strings.Repeat("x\n", i)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, path)
+	}
+	files = append(files, filepath.Join(root, "missing.txt"))
+
+	workers := []string{newTestServer(t, root).URL, strings.TrimPrefix(newTestServer(t, root).URL, "http://")}
+	cfg := core.Config{Rules: core.RulesConfig{FileSize: core.FileSizeConfig{MaxLines: 3}}}
+	results, fileErrors, err := NewCoordinator(workers, testToken, time.Minute).Run(context.Background(), root, map[string][]string{"text": files}, cfg)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	var got []string
+	for _, result := range results {
+		rel, _ := filepath.Rel(root, result.FilePath)
+		got = append(got, filepath.ToSlash(rel)+" "+result.Message)
+	}
+	expected := []string{
+		"pkg/f1.txt 1 lines, max 3",
+		"pkg/f2.txt 2 lines, max 3",
+		"pkg/f3.txt 3 lines, max 3",
+		"pkg/f4.txt 4 lines, max 3",
+		"pkg/f5.txt 5 lines, max 3",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if len(fileErrors) != 1 || fileErrors[0].FilePath != files[5] {
+		t.Errorf("Expected a file error for %s, got %v", files[5], fileErrors)
+	}
+}
+
+func TestCoordinator_WorkerDown(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.txt")
+	if err := os.WriteFile(path, []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	workers := []string{newTestServer(t, root).URL, down.URL}
+	files := map[string][]string{"text": {path, path}}
+	if _, _, err := NewCoordinator(workers, testToken, time.Minute).Run(context.Background(), root, files, core.Config{}); err == nil || !strings.Contains(err.Error(), down.URL) {
+		t.Errorf("Expected an error naming the unreachable worker, got %v", err)
+	}
+}
+
+func TestCoordinator_WorkerHangs(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.txt")
+	if err := os.WriteFile(path, []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	release := make(chan struct{})
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { <-release }))
+	t.Cleanup(hung.Close)
+	defer close(release)
+
+	files := map[string][]string{"text": {path}}
+	_, _, err := NewCoordinator([]string{hung.URL}, testToken, 50*time.Millisecond).Run(context.Background(), root, files, core.Config{})
+	if err == nil || !strings.Contains(err.Error(), hung.URL) {
+		t.Errorf("Expected a timeout naming the hung worker, got %v", err)
+	}
+}
+
+func TestCoordinator_WrongToken(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.txt")
+	if err := os.WriteFile(path, []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]string{"text": {path}}
+	workers := []string{newTestServer(t, root).URL}
+	if _, _, err := NewCoordinator(workers, "guess", time.Minute).Run(context.Background(), root, files, core.Config{}); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected the worker to refuse a wrong token, got %v", err)
+	}
+}
+
+func TestCoordinator_FileOutsideRoot(t *testing.T) {
+	root := t.TempDir()
+	files := map[string][]string{"text": {filepath.Join(filepath.Dir(root), "other.txt")}}
+	if _, _, err := NewCoordinator([]string{"localhost:1"}, testToken, time.Minute).Run(context.Background(), root, files, core.Config{}); err == nil {
+		t.Error("Expected an error for a file outside the root")
+	}
+}
+
+func TestServer_RefusesAllWithoutToken(t *testing.T) {
+	server := httptest.NewServer(NewServer(t.TempDir(), 1, "", func(core.Config) *languages.Registry { return languages.NewRegistry() }))
+	defer server.Close()
+	req, _ := http.NewRequest(http.MethodPost, server.URL+AnalyzePath, strings.NewReader("{}"))
+	req.Header.Set(TokenHeader, "")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected a worker without a token to refuse requests, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_RejectsRequests(t *testing.T) {
+	server := newTestServer(t, t.TempDir())
+
+	tests := []struct {
+		name   string
+		token  string
+		req    Request
+		status int
+		errors int
+	}{
+		{"no token", "", Request{Version: ProtocolVersion}, http.StatusUnauthorized, 0},
+		{"wrong token", "guess", Request{Version: ProtocolVersion}, http.StatusUnauthorized, 0},
+		{"other protocol version", testToken, Request{Version: ProtocolVersion + 1}, http.StatusBadRequest, 0},
+		{"path outside root", testToken, Request{Version: ProtocolVersion, Files: []File{{Language: "text", Path: "../etc/passwd"}}}, http.StatusOK, 1},
+		{"unknown language", testToken, Request{Version: ProtocolVersion, Files: []File{{Language: "cobol", Path: "a.cbl"}}}, http.StatusOK, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.req)
+			req, err := http.NewRequest(http.MethodPost, server.URL+AnalyzePath, bytes.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.token != "" {
+				req.Header.Set(TokenHeader, tt.token)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var decoded Response
+			if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+				t.Fatal(err)
+			}
+			if len(decoded.Results) != 0 || len(decoded.Errors) != tt.errors {
+				t.Errorf("Expected %d file errors and no results, got %+v", tt.errors, decoded)
+			}
+		})
+	}
+}
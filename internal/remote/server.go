@@ -0,0 +1,116 @@
+package remote
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
+)
+
+// maxRequestBytes bounds the size of an analysis request, which holds a configuration and a
+// list of paths
+const maxRequestBytes = 64 << 20
+
+// Server is an agentlint worker: it analyzes files of the repository checked out under its
+// root on behalf of a coordinator
+type Server struct {
+	root        string
+	workers     int
+	token       string
+	newRegistry func(core.Config) *languages.Registry
+}
+
+// NewServer creates a worker analyzing files under root with the given number of parallel
+// workers, 0 for one per CPU, for coordinators sending token in TokenHeader. newRegistry
+// builds the analyzers for the configuration of each request.
+func NewServer(root string, workers int, token string, newRegistry func(core.Config) *languages.Registry) *Server {
+	return &Server{root: root, workers: workers, token: token, newRegistry: newRegistry}
+}
+
+// ServeHTTP answers analysis requests posted to AnalyzePath by coordinators holding the token
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != AnalyzePath {
+		http.NotFound(w, r)
+		return
+	}
+	if !s.authorized(r) {
+		slog.Warn("refused request without a valid token", "remote", r.RemoteAddr)
+		http.Error(w, "missing or invalid "+TokenHeader, http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "analysis requests must be posted", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Version != ProtocolVersion {
+		http.Error(w, fmt.Sprintf("unsupported protocol version %d, this worker speaks %d", req.Version, ProtocolVersion), http.StatusBadRequest)
+		return
+	}
+
+	slog.Info("analyzing for coordinator", "files", len(req.Files), "remote", r.RemoteAddr)
+	resp := s.analyze(r, req)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.Warn("sending results failed", "remote", r.RemoteAddr, "error", err)
+	}
+}
+
+// authorized tells whether a request carries the worker's token. A worker without a token
+// refuses every request.
+func (s *Server) authorized(r *http.Request) bool {
+	token := r.Header.Get(TokenHeader)
+	return s.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// analyze runs the analyzers over the requested files. A file outside the root or of a
+// language the worker has no analyzer for is reported as a file error.
+func (s *Server) analyze(r *http.Request, req Request) Response {
+	registry := s.newRegistry(req.Config)
+	var resp Response
+	var jobs []core.AnalysisJob
+	for _, file := range req.Files {
+		path := filepath.FromSlash(file.Path)
+		if !filepath.IsLocal(path) {
+			resp.Errors = append(resp.Errors, core.FileError{FilePath: file.Path, Message: "path is outside the repository root"})
+			continue
+		}
+		analyzer, ok := registry.GetAnalyzer(file.Language)
+		if !ok {
+			resp.Errors = append(resp.Errors, core.FileError{FilePath: file.Path, Message: fmt.Sprintf("no analyzer for language %q", file.Language)})
+			continue
+		}
+		jobs = append(jobs, core.AnalysisJob{Analyzer: analyzer, FilePath: filepath.Join(s.root, path)})
+	}
+
+	results, fileErrors := core.NewEngine(s.workers).Run(r.Context(), jobs, req.Config)
+	for i := range results {
+		results[i].FilePath = s.relative(results[i].FilePath)
+	}
+	for i := range fileErrors {
+		fileErrors[i].FilePath = s.relative(fileErrors[i].FilePath)
+	}
+	resp.Results = results
+	resp.Errors = append(resp.Errors, fileErrors...)
+	return resp
+}
+
+// relative returns a path under the root as a slash-separated path relative to it
+func (s *Server) relative(path string) string {
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
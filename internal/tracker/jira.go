@@ -0,0 +1,168 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// jiraPageSize is the number of tickets asked for per search request
+	jiraPageSize = 100
+	// jiraTimeout bounds each request when no Client is given, so an unresponsive site
+	// cannot stall an export
+	jiraTimeout = 30 * time.Second
+)
+
+// Jira exports tickets to a Jira project through its REST API, version 2
+type Jira struct {
+	BaseURL   string // the address of the Jira site, e.g. https://example.atlassian.net
+	Project   string // the project key
+	IssueType string // the type of the tickets created, e.g. Task
+	// Authorize adds credentials to each request, e.g. basic authentication with an API token.
+	// Credentials are only sent to an https BaseURL.
+	Authorize func(req *http.Request)
+	Client    *http.Client // nil for a client with a 30 second timeout
+}
+
+type jiraIssue struct {
+	Key    string     `json:"key,omitempty"`
+	Fields jiraFields `json:"fields"`
+}
+
+type jiraFields struct {
+	Project     *jiraRef `json:"project,omitempty"`
+	IssueType   *jiraRef `json:"issuetype,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+}
+
+type jiraRef struct {
+	Key  string `json:"key,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// Find returns the unresolved tickets of the project that carry Label
+func (j *Jira) Find(ctx context.Context) (map[string]Ticket, error) {
+	jql := fmt.Sprintf("project = %q AND labels = %q AND resolution = Unresolved", j.Project, Label)
+	tickets := make(map[string]Ticket)
+	for startAt := 0; ; startAt += jiraPageSize {
+		query := url.Values{
+			"jql":        {jql},
+			"fields":     {"summary,description,labels"},
+			"startAt":    {fmt.Sprint(startAt)},
+			"maxResults": {fmt.Sprint(jiraPageSize)},
+		}
+		var page struct {
+			Issues []jiraIssue `json:"issues"`
+			Total  int         `json:"total"`
+		}
+		if err := j.do(ctx, http.MethodGet, "/rest/api/2/search?"+query.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		for _, issue := range page.Issues {
+			ticket := Ticket{ID: issue.Key, Summary: issue.Fields.Summary, Description: issue.Fields.Description}
+			for _, label := range issue.Fields.Labels {
+				switch label {
+				case GroupByLabel(GroupByFile):
+					ticket.GroupBy = GroupByFile
+				case GroupByLabel(GroupByRule):
+					ticket.GroupBy = GroupByRule
+				default:
+					if strings.HasPrefix(label, Label+"-") {
+						ticket.GroupLabel = label
+					}
+				}
+			}
+			if ticket.GroupLabel != "" {
+				tickets[ticket.GroupLabel] = ticket
+			}
+		}
+		if len(page.Issues) == 0 || startAt+len(page.Issues) >= page.Total {
+			return tickets, nil
+		}
+	}
+}
+
+// Create files a ticket labeled with Label, its group label and how its group was made
+func (j *Jira) Create(ctx context.Context, ticket Ticket) (string, error) {
+	issue := jiraIssue{Fields: jiraFields{
+		Project:     &jiraRef{Key: j.Project},
+		IssueType:   &jiraRef{Name: j.IssueType},
+		Summary:     ticket.Summary,
+		Description: ticket.Description,
+		Labels:      []string{Label, ticket.GroupLabel, GroupByLabel(ticket.GroupBy)},
+	}}
+	var created jiraIssue
+	if err := j.do(ctx, http.MethodPost, "/rest/api/2/issue", issue, &created); err != nil {
+		return "", err
+	}
+	return created.Key, nil
+}
+
+// Update replaces the summary and description of a ticket
+func (j *Jira) Update(ctx context.Context, ticket Ticket) error {
+	issue := jiraIssue{Fields: jiraFields{Summary: ticket.Summary, Description: ticket.Description}}
+	return j.do(ctx, http.MethodPut, "/rest/api/2/issue/"+url.PathEscape(ticket.ID), issue, nil)
+}
+
+// Comment adds a comment to a ticket
+func (j *Jira) Comment(ctx context.Context, ticket Ticket, text string) error {
+	comment := map[string]string{"body": text}
+	return j.do(ctx, http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(ticket.ID)+"/comment", comment, nil)
+}
+
+// do sends a request with a JSON body, if any, and decodes the JSON response into out, if
+// not nil
+func (j *Jira) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(j.BaseURL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if j.Authorize != nil {
+		if req.URL.Scheme != "https" {
+			return fmt.Errorf("refusing to send credentials to %s over %s, use an https address", req.URL.Host, req.URL.Scheme)
+		}
+		j.Authorize(req)
+	}
+
+	client := j.Client
+	if client == nil {
+		client = &http.Client{Timeout: jiraTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid response to %s %s: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+// Package tracker files findings as tickets in an issue tracker, one ticket per file or per
+// rule, updating the tickets filed by earlier exports instead of filing them again
+package tracker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+)
+
+// How findings are grouped into tickets
+const (
+	GroupByFile = "file"
+	GroupByRule = "rule"
+)
+
+// Label marks every ticket an export files, so later exports can find them
+const Label = "agentlint"
+
+// Group is the findings one ticket tracks
+type Group struct {
+	Key     string // the file path, relative to the root and with slashes, or rule ID the findings share
+	Results []core.Result
+}
+
+// Ticket is a ticket as exported, or as found in the tracker
+type Ticket struct {
+	ID          string // the tracker's key for the ticket, "" before it is created
+	GroupLabel  string // the label naming the group the ticket tracks, see GroupLabel
+	GroupBy     string // how the group was made, "" for tickets filed before it was recorded
+	Summary     string
+	Description string
+}
+
+// Tracker is an issue tracker tickets are exported to
+type Tracker interface {
+	// Find returns the open tickets filed by earlier exports, by group label
+	Find(ctx context.Context) (map[string]Ticket, error)
+	// Create files a ticket and returns its key
+	Create(ctx context.Context, ticket Ticket) (string, error)
+	// Update replaces the summary and description of an existing ticket
+	Update(ctx context.Context, ticket Ticket) error
+	// Comment adds a comment to an existing ticket
+	Comment(ctx context.Context, ticket Ticket, text string) error
+}
+
+// Outcome lists what an export did, by ticket key, or by group label for tickets a dry run
+// would create
+type Outcome struct {
+	Created   []string
+	Updated   []string
+	Unchanged []string
+	Fixed     []string // tickets whose findings have all been fixed, told so with a comment
+}
+
+// GroupResults groups results into tickets by file or by rule, sorted by key. Files are keyed
+// by their path relative to root with slashes, so that a file keeps its ticket whichever
+// directory the report was written from and on whichever system; a relative path is taken to
+// be relative to the working directory, and a path outside root is kept as it is. Results
+// without a fingerprint cannot be told apart between exports and are left out.
+func GroupResults(results []core.Result, by, root string) []Group {
+	byKey := make(map[string][]core.Result)
+	for _, result := range results {
+		if result.Fingerprint == "" {
+			continue
+		}
+		key := result.RuleID
+		if by == GroupByFile {
+			key = rootRelative(root, result.FilePath)
+		}
+		byKey[key] = append(byKey[key], result)
+	}
+	groups := make([]Group, 0, len(byKey))
+	for key, grouped := range byKey {
+		groups = append(groups, Group{Key: key, Results: grouped})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Key < groups[j].Key })
+	return groups
+}
+
+// rootRelative returns path relative to root with slashes, or path with slashes when it is
+// outside root
+func rootRelative(root, path string) string {
+	if abs, err := filepath.Abs(path); err == nil && root != "" {
+		if rel, err := filepath.Rel(root, abs); err == nil && filepath.IsLocal(rel) {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// GroupByLabel returns the label naming how the groups of the tickets it marks were made, so
+// that an export grouped one way leaves the tickets of the other alone
+func GroupByLabel(by string) string {
+	return Label + "-by-" + by
+}
+
+// GroupLabel returns the label naming the ticket of the group with key, grouped by, as labels
+// cannot hold the spaces and slashes of a path
+func GroupLabel(by, key string) string {
+	sum := sha256.Sum256([]byte(by + "\x00" + key))
+	return Label + "-" + hex.EncodeToString(sum[:6])
+}
+
+// fingerprintLine starts the last line of a description, listing the fingerprints of the
+// findings the ticket tracks
+const fingerprintLine = "AgentLint fingerprints:"
+
+// NewTicket returns the ticket of a group, grouped by, in Jira's wiki markup
+func NewTicket(by string, group Group) Ticket {
+	var summary string
+	if by == GroupByFile {
+		summary = fmt.Sprintf("AgentLint: %d findings in %s", len(group.Results), group.Key)
+	} else {
+		summary = fmt.Sprintf("AgentLint: %d %s findings", len(group.Results), group.Key)
+	}
+
+	var sb strings.Builder
+	for _, result := range group.Results {
+		fmt.Fprintf(&sb, "* %s:%d [%s] %s (%s)\n", result.FilePath, result.Line, result.Severity, result.Message, result.RuleID)
+	}
+	if suggestion := group.Results[0].Suggestion; by == GroupByRule && suggestion != "" {
+		fmt.Fprintf(&sb, "\nSuggestion: %s\n", suggestion)
+	}
+	fingerprints := make([]string, len(group.Results))
+	for i, result := range group.Results {
+		fingerprints[i] = result.Fingerprint
+	}
+	sort.Strings(fingerprints)
+	fmt.Fprintf(&sb, "\n%s %s", fingerprintLine, strings.Join(fingerprints, " "))
+
+	return Ticket{GroupLabel: GroupLabel(by, group.Key), GroupBy: by, Summary: summary, Description: sb.String()}
+}
+
+// Fingerprints returns the fingerprints of the findings a ticket description lists, sorted
+func Fingerprints(description string) []string {
+	_, line, found := strings.Cut(description, fingerprintLine)
+	if !found {
+		return nil
+	}
+	line, _, _ = strings.Cut(line, "\n")
+	fingerprints := strings.Fields(line)
+	sort.Strings(fingerprints)
+	return fingerprints
+}
+
+// fixedComment is added to a ticket once its findings have all been fixed
+const fixedComment = "AgentLint no longer reports any of the findings this ticket tracks. Resolve it once the fixes are confirmed."
+
+// Export files a ticket per group, grouped by, in tracker. A group whose ticket an earlier
+// export filed updates it when its findings changed, matched by fingerprint, and leaves it
+// alone otherwise, so exporting the same findings twice files nothing new. The open tickets
+// of groups left without findings, grouped the same way, get a comment saying so, and their
+// fingerprints are cleared so that later exports do not comment again. A dry run reads the
+// tracker but changes nothing.
+func Export(ctx context.Context, t Tracker, by string, groups []Group, dryRun bool) (Outcome, error) {
+	existing, err := t.Find(ctx)
+	if err != nil {
+		return Outcome{}, fmt.Errorf("finding exported tickets: %w", err)
+	}
+
+	var outcome Outcome
+	for _, group := range groups {
+		ticket := NewTicket(by, group)
+		old, found := existing[ticket.GroupLabel]
+		delete(existing, ticket.GroupLabel)
+		switch {
+		case !found:
+			id := ticket.GroupLabel
+			if !dryRun {
+				if id, err = t.Create(ctx, ticket); err != nil {
+					return outcome, fmt.Errorf("creating the ticket for %s: %w", group.Key, err)
+				}
+			}
+			outcome.Created = append(outcome.Created, id)
+		case strings.Join(Fingerprints(old.Description), " ") == strings.Join(Fingerprints(ticket.Description), " "):
+			outcome.Unchanged = append(outcome.Unchanged, old.ID)
+		default:
+			ticket.ID = old.ID
+			if !dryRun {
+				if err := t.Update(ctx, ticket); err != nil {
+					return outcome, fmt.Errorf("updating %s for %s: %w", old.ID, group.Key, err)
+				}
+			}
+			outcome.Updated = append(outcome.Updated, old.ID)
+		}
+	}
+
+	labels := make([]string, 0, len(existing))
+	for label, old := range existing {
+		if old.GroupBy == by && len(Fingerprints(old.Description)) > 0 {
+			labels = append(labels, label)
+		}
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		old := existing[label]
+		if !dryRun {
+			if err := t.Comment(ctx, old, fixedComment); err != nil {
+				return outcome, fmt.Errorf("commenting on %s: %w", old.ID, err)
+			}
+			old.Description = fixedComment + "\n\n" + fingerprintLine
+			if err := t.Update(ctx, old); err != nil {
+				return outcome, fmt.Errorf("updating %s: %w", old.ID, err)
+			}
+		}
+		outcome.Fixed = append(outcome.Fixed, old.ID)
+	}
+	return outcome, nil
+}
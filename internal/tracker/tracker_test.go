@@ -0,0 +1,253 @@
+package tracker_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/tracker"
+)
+
+func TestGroupResults(t *testing.T) {
+	results := []core.Result{
+		{RuleID: "magic-number", FilePath: "b.go", Fingerprint: "1"},
+		{RuleID: "console-log", FilePath: "a.js", Fingerprint: "2"},
+		{RuleID: "magic-number", FilePath: "a.js", Fingerprint: "3"},
+		{RuleID: "magic-number", FilePath: "a.js"},
+	}
+
+	root, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byRule := tracker.GroupResults(results, tracker.GroupByRule, root)
+	if len(byRule) != 2 || byRule[0].Key != "console-log" || byRule[1].Key != "magic-number" || len(byRule[1].Results) != 2 {
+		t.Errorf("Expected the fingerprinted findings grouped by rule, got %+v", byRule)
+	}
+	byFile := tracker.GroupResults(results, tracker.GroupByFile, root)
+	if len(byFile) != 2 || byFile[0].Key != "a.js" || len(byFile[0].Results) != 2 {
+		t.Errorf("Expected the fingerprinted findings grouped by file, got %+v", byFile)
+	}
+
+	// a file is the same group whether its path is absolute or relative
+	results = []core.Result{
+		{RuleID: "magic-number", FilePath: filepath.Join("src", "a.go"), Fingerprint: "1"},
+		{RuleID: "magic-number", FilePath: filepath.Join(root, "src", "a.go"), Fingerprint: "2"},
+	}
+	byFile = tracker.GroupResults(results, tracker.GroupByFile, filepath.Dir(root))
+	want := filepath.Base(root) + "/src/a.go"
+	if len(byFile) != 1 || byFile[0].Key != want || len(byFile[0].Results) != 2 {
+		t.Errorf("Expected both findings grouped under %s, got %+v", want, byFile)
+	}
+}
+
+func TestNewTicket_Fingerprints(t *testing.T) {
+	group := tracker.Group{Key: "magic-number", Results: []core.Result{
+		{RuleID: "magic-number", FilePath: "b.go", Line: 3, Severity: "info", Message: "magic 42", Fingerprint: "ffff"},
+		{RuleID: "magic-number", FilePath: "a.go", Line: 7, Severity: "info", Message: "magic 7", Fingerprint: "aaaa"},
+	}}
+	ticket := tracker.NewTicket(tracker.GroupByRule, group)
+	if ticket.Summary != "AgentLint: 2 magic-number findings" {
+		t.Errorf("Unexpected summary %q", ticket.Summary)
+	}
+	if !strings.Contains(ticket.Description, "* b.go:3 [info] magic 42 (magic-number)") {
+		t.Errorf("Expected the findings listed, got %q", ticket.Description)
+	}
+	if got := tracker.Fingerprints(ticket.Description); !reflect.DeepEqual(got, []string{"aaaa", "ffff"}) {
+		t.Errorf("Expected the sorted fingerprints, got %v", got)
+	}
+	if tracker.GroupLabel(tracker.GroupByRule, "a") == tracker.GroupLabel(tracker.GroupByFile, "a") {
+		t.Error("Expected the group label to depend on the grouping")
+	}
+	if strings.ContainsAny(tracker.GroupLabel(tracker.GroupByFile, "src/my file.go"), " /") {
+		t.Error("Expected a label without spaces or slashes")
+	}
+}
+
+// fakeJira serves the parts of the Jira REST API the exporter uses, holding issues in memory
+type fakeJira struct {
+	mu       sync.Mutex
+	issues   map[string]map[string]interface{} // fields by key
+	comments map[string][]string               // comments by key
+	writes   int
+	requests int
+}
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests++
+	if r.Header.Get("Authorization") != "Basic "+base64.StdEncoding.EncodeToString([]byte("me:token")) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/search":
+		if !strings.Contains(r.URL.Query().Get("jql"), `project = "KEY"`) {
+			http.Error(w, "unexpected JQL", http.StatusBadRequest)
+			return
+		}
+		issues := []map[string]interface{}{}
+		for key, fields := range f.issues {
+			issues = append(issues, map[string]interface{}{"key": key, "fields": fields})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"issues": issues, "total": len(issues)})
+	case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+		var body struct{ Fields map[string]interface{} }
+		json.NewDecoder(r.Body).Decode(&body)
+		key := fmt.Sprintf("KEY-%d", len(f.issues)+1)
+		f.issues[key] = body.Fields
+		f.writes++
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"key": key})
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/comment"):
+		key := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/"), "/comment")
+		if _, ok := f.issues[key]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body struct{ Body string }
+		json.NewDecoder(r.Body).Decode(&body)
+		f.comments[key] = append(f.comments[key], body.Body)
+		f.writes++
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("{}"))
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/rest/api/2/issue/"):
+		fields, ok := f.issues[strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body struct{ Fields map[string]interface{} }
+		json.NewDecoder(r.Body).Decode(&body)
+		for name, value := range body.Fields {
+			fields[name] = value
+		}
+		f.writes++
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestExport_Jira(t *testing.T) {
+	fake := &fakeJira{issues: make(map[string]map[string]interface{}), comments: make(map[string][]string)}
+	server := httptest.NewTLSServer(fake)
+	defer server.Close()
+	jira := &tracker.Jira{
+		BaseURL: server.URL, Project: "KEY", IssueType: "Task", Client: server.Client(),
+		Authorize: func(req *http.Request) { req.SetBasicAuth("me", "token") },
+	}
+	ctx := context.Background()
+
+	results := []core.Result{
+		{RuleID: "console-log", FilePath: "app.js", Line: 1, Severity: "info", Message: "remove it", Fingerprint: "01"},
+		{RuleID: "magic-number", FilePath: "app.js", Line: 2, Severity: "info", Message: "name it", Fingerprint: "02"},
+	}
+	outcome, err := tracker.Export(ctx, jira, tracker.GroupByRule, tracker.GroupResults(results, tracker.GroupByRule, ""), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcome.Created) != 2 || len(outcome.Updated) != 0 {
+		t.Fatalf("Expected two tickets created, got %+v", outcome)
+	}
+	labels := fake.issues["KEY-1"]["labels"].([]interface{})
+	if len(labels) != 3 || labels[0] != tracker.Label || labels[1] != tracker.GroupLabel(tracker.GroupByRule, "console-log") || labels[2] != tracker.GroupByLabel(tracker.GroupByRule) {
+		t.Errorf("Expected the ticket labeled with its group and grouping, got %v", labels)
+	}
+
+	// the same findings again change nothing
+	writes := fake.writes
+	outcome, err = tracker.Export(ctx, jira, tracker.GroupByRule, tracker.GroupResults(results, tracker.GroupByRule, ""), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcome.Unchanged) != 2 || fake.writes != writes {
+		t.Errorf("Expected an export of the same findings to change nothing, got %+v", outcome)
+	}
+
+	// a new finding updates its rule's ticket, and a dry run writes nothing
+	results = append(results, core.Result{RuleID: "console-log", FilePath: "util.js", Line: 9, Severity: "info", Message: "remove it", Fingerprint: "03"})
+	groups := tracker.GroupResults(results, tracker.GroupByRule, "")
+	outcome, err = tracker.Export(ctx, jira, tracker.GroupByRule, groups, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcome.Updated) != 1 || fake.writes != writes {
+		t.Errorf("Expected a dry run to report an update without writing, got %+v", outcome)
+	}
+	outcome, err = tracker.Export(ctx, jira, tracker.GroupByRule, groups, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(outcome.Updated, []string{"KEY-1"}) || !reflect.DeepEqual(outcome.Unchanged, []string{"KEY-2"}) {
+		t.Errorf("Expected KEY-1 updated and KEY-2 unchanged, got %+v", outcome)
+	}
+	if got := tracker.Fingerprints(fake.issues["KEY-1"]["description"].(string)); !reflect.DeepEqual(got, []string{"01", "03"}) {
+		t.Errorf("Expected the updated ticket to track both findings, got %v", got)
+	}
+
+	// a rule whose findings are all fixed gets a comment, once, and a file grouping leaves
+	// the rule tickets alone
+	groups = tracker.GroupResults(results[:1], tracker.GroupByRule, "")
+	outcome, err = tracker.Export(ctx, jira, tracker.GroupByRule, groups, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(outcome.Fixed, []string{"KEY-2"}) || len(fake.comments["KEY-2"]) != 1 {
+		t.Errorf("Expected KEY-2 marked as fixed with a comment, got %+v and %v", outcome, fake.comments)
+	}
+	if got := tracker.Fingerprints(fake.issues["KEY-2"]["description"].(string)); len(got) != 0 {
+		t.Errorf("Expected the fixed ticket to track no findings, got %v", got)
+	}
+	outcome, err = tracker.Export(ctx, jira, tracker.GroupByRule, groups, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcome.Fixed) != 0 || len(fake.comments["KEY-2"]) != 1 {
+		t.Errorf("Expected a fixed ticket to be commented on once, got %+v", outcome)
+	}
+	outcome, err = tracker.Export(ctx, jira, tracker.GroupByFile, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcome.Fixed) != 0 {
+		t.Errorf("Expected the rule tickets left alone by a file grouping, got %+v", outcome)
+	}
+}
+
+func TestExport_Unauthorized(t *testing.T) {
+	server := httptest.NewServer(&fakeJira{})
+	defer server.Close()
+	jira := &tracker.Jira{BaseURL: server.URL, Project: "KEY", Client: server.Client()}
+	_, err := tracker.Export(context.Background(), jira, tracker.GroupByRule, nil, false)
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected the status of a refused search, got %v", err)
+	}
+}
+
+func TestExport_CredentialsOverHTTP(t *testing.T) {
+	fake := &fakeJira{issues: make(map[string]map[string]interface{}), comments: make(map[string][]string)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	jira := &tracker.Jira{
+		BaseURL: server.URL, Project: "KEY", Client: server.Client(),
+		Authorize: func(req *http.Request) { req.SetBasicAuth("me", "token") },
+	}
+	_, err := tracker.Export(context.Background(), jira, tracker.GroupByRule, nil, false)
+	if err == nil || !strings.Contains(err.Error(), "https") {
+		t.Errorf("Expected credentials refused over http, got %v", err)
+	}
+	if fake.requests != 0 {
+		t.Errorf("Expected no request sent, got %d", fake.requests)
+	}
+}
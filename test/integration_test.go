@@ -5,12 +5,20 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/CiaranMcAleer/AgentLint/internal/benchmark"
+	"github.com/CiaranMcAleer/AgentLint/internal/config"
 	"github.com/CiaranMcAleer/AgentLint/internal/core"
+	"github.com/CiaranMcAleer/AgentLint/internal/headers"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages"
 	golang "github.com/CiaranMcAleer/AgentLint/internal/languages/golang"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/python"
+	"github.com/CiaranMcAleer/AgentLint/internal/languages/reactnative"
 	"github.com/CiaranMcAleer/AgentLint/internal/output"
 	"github.com/CiaranMcAleer/AgentLint/internal/profiling"
 )
@@ -134,6 +142,958 @@ func main() {
 	}
 }
 
+func TestIntegrationTypeSize(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Methods on service are split across two files of the same package
+	typesFile := filepath.Join(tmpDir, "types.go")
+	typesContent := `package testpkg
+
+type config struct {
+	host, port string
+	timeout    int
+	retries    int
+}
+
+type service struct {
+	cfg config
+}
+
+func (s *service) Start() {}
+func (s *service) Stop()  {}
+`
+	methodsFile := filepath.Join(tmpDir, "methods.go")
+	methodsContent := `package testpkg
+
+func (s *service) Restart() {}
+`
+	os.WriteFile(typesFile, []byte(typesContent), 0644)
+	os.WriteFile(methodsFile, []byte(methodsContent), 0644)
+
+	config := core.Config{
+		Rules: core.RulesConfig{
+			TypeSize: core.TypeSizeConfig{
+				Enabled:    true,
+				MaxFields:  3,
+				MaxMethods: 2,
+			},
+		},
+	}
+
+	analyzer := golang.NewAnalyzer(config)
+	results, err := analyzer.Analyze(context.Background(), typesFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	found := make(map[string]string)
+	for _, r := range results {
+		found[r.RuleID] = r.Message
+	}
+
+	if msg := found["god-struct"]; !strings.Contains(msg, "'config'") || !strings.Contains(msg, "(4,") {
+		t.Errorf("Expected god-struct finding for 'config' with 4 fields, got %q", msg)
+	}
+	if msg := found["too-many-methods"]; !strings.Contains(msg, "'service'") || !strings.Contains(msg, "(3,") {
+		t.Errorf("Expected too-many-methods finding for 'service' with 3 methods, got %q", msg)
+	}
+}
+
+func TestIntegrationReturnValues(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	testFile := filepath.Join(tmpDir, "test.go")
+	content := `package testpkg
+
+func bounds(xs []int) (min, max, sum int, err error) {
+	return 0, 0, 0, nil
+}
+
+func short() (n int) {
+	n = 1
+	return
+}
+
+func long(xs []int) (total int) {
+	for _, x := range xs {
+		total += x
+	}
+	if total < 0 {
+		total = 0
+	}
+	f := func() int { return 1 }
+	total += f()
+	return
+}
+`
+	os.WriteFile(testFile, []byte(content), 0644)
+
+	config := core.Config{
+		Rules: core.RulesConfig{
+			Returns: core.ReturnsConfig{
+				Enabled:             true,
+				MaxValues:           3,
+				NakedReturnMaxLines: 5,
+			},
+		},
+	}
+
+	analyzer := golang.NewAnalyzer(config)
+	results, err := analyzer.Analyze(context.Background(), testFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var tooMany, naked []core.Result
+	for _, r := range results {
+		switch r.RuleID {
+		case "too-many-return-values":
+			tooMany = append(tooMany, r)
+		case "naked-return":
+			naked = append(naked, r)
+		}
+	}
+
+	if len(tooMany) != 1 || !strings.Contains(tooMany[0].Message, "'bounds'") || !strings.Contains(tooMany[0].Message, "(4,") {
+		t.Errorf("Expected one too-many-return-values finding for 'bounds' with 4 values, got %v", tooMany)
+	}
+	if len(naked) != 1 || !strings.Contains(naked[0].Message, "'long'") || naked[0].Line != 21 {
+		t.Errorf("Expected one naked-return finding for 'long' at line 21, got %v", naked)
+	}
+}
+
+func TestIntegrationLongBranchChains(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	testFile := filepath.Join(tmpDir, "test.go")
+	content := `package testpkg
+
+func describe(v interface{}) string {
+	switch v.(type) {
+	case int:
+		return "int"
+	case string:
+		return "string"
+	}
+	if v == nil {
+		return "nil"
+	} else if v == 1 {
+		return "one"
+	} else if v == 2 {
+		return "two"
+	} else {
+		return "other"
+	}
+}
+
+func kind(code string) string {
+	handle := func() string {
+		switch code {
+		case "a", "b":
+			return "letter"
+		case "1":
+			return "digit"
+		case " ":
+			return "space"
+		default:
+			return "other"
+		}
+	}
+	return handle()
+}
+
+func small(x int) int {
+	if x > 0 {
+		return 1
+	} else if x < 0 {
+		return -1
+	}
+	return 0
+}
+`
+	os.WriteFile(testFile, []byte(content), 0644)
+
+	config := core.Config{
+		Rules: core.RulesConfig{
+			Branches: core.BranchesConfig{
+				Enabled:     true,
+				MaxBranches: 3,
+			},
+		},
+	}
+
+	analyzer := golang.NewAnalyzer(config)
+	results, err := analyzer.Analyze(context.Background(), testFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var found []string
+	for _, r := range results {
+		if r.RuleID == "long-branch-chain" {
+			found = append(found, fmt.Sprintf("%d: %s", r.Line, r.Message))
+		}
+	}
+	expected := []string{
+		"10: Function 'describe' has a long if-else chain (4 branches, max 3)",
+		"23: Function 'kind' has a long switch (4 branches, max 3)",
+	}
+	if fmt.Sprint(found) != fmt.Sprint(expected) {
+		t.Errorf("Expected long-branch-chain findings %q, got %q", expected, found)
+	}
+
+	config.Rules.Branches.Enabled = false
+	results, err = golang.NewAnalyzer(config).Analyze(context.Background(), testFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	for _, r := range results {
+		if r.RuleID == "long-branch-chain" {
+			t.Errorf("Expected no findings with the rule disabled, got %v", r)
+		}
+	}
+}
+
+func TestIntegrationAICommentFingerprints(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	testFile := filepath.Join(tmpDir, "test.go")
+	content := `package testpkg
+
+// This function adds two numbers
+func add(a, b int) int {
+	/*
+	   Here is the updated code:
+	   it now handles overflow
+	*/
+	return a + b
+}
+
+// sub subtracts b from a
+func sub(a, b int) int {
+	return a - b // ⚠️ may overflow
+}
+`
+	os.WriteFile(testFile, []byte(content), 0644)
+
+	config := core.Config{
+		Rules: core.RulesConfig{
+			AIComments: core.AICommentsConfig{Enabled: true},
+		},
+	}
+
+	analyzer := golang.NewAnalyzer(config)
+	results, err := analyzer.Analyze(context.Background(), testFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var lines []int
+	for _, r := range results {
+		if r.RuleID == "ai-comment-fingerprint" {
+			lines = append(lines, r.Line)
+		}
+	}
+
+	expected := []int{3, 6, 14}
+	if fmt.Sprint(lines) != fmt.Sprint(expected) {
+		t.Errorf("Expected ai-comment-fingerprint findings on lines %v, got %v", expected, lines)
+	}
+}
+
+func TestIntegrationFunctionSizeMetric(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	testFile := filepath.Join(tmpDir, "test.go")
+	content := `package testpkg
+
+func documented(values []int) int {
+	// Sum every value.
+	//
+	// Negative values are included on purpose: callers
+	// rely on them to cancel out earlier entries.
+
+	total := 0
+
+	for _, v := range values {
+		total += v
+	}
+
+	return total
+}
+
+func busy(a, b int) int {
+	a++
+	b++
+	a += b
+	b += a
+	if a > b {
+		return a
+	}
+	return b
+}
+`
+	os.WriteFile(testFile, []byte(content), 0644)
+
+	largeFunctions := func(metric core.FunctionSizeMetric) []string {
+		config := core.Config{
+			Rules: core.RulesConfig{
+				FunctionSize: core.FunctionSizeConfig{Enabled: true, MaxLines: 5, Metric: metric},
+			},
+		}
+		analyzer := golang.NewAnalyzer(config)
+		results, err := analyzer.Analyze(context.Background(), testFile, config)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+
+		var messages []string
+		for _, r := range results {
+			if r.RuleID == "large-function" {
+				messages = append(messages, r.Message)
+			}
+		}
+		return messages
+	}
+
+	byLines := largeFunctions(core.MetricLines)
+	if len(byLines) != 2 {
+		t.Errorf("Expected both functions to exceed 5 lines, got %v", byLines)
+	}
+
+	byStatements := largeFunctions(core.MetricStatements)
+	expected := "Function 'busy' is too large (7 statements, max 5)"
+	if len(byStatements) != 1 || byStatements[0] != expected {
+		t.Errorf("Expected only %q when counting statements, got %v", expected, byStatements)
+	}
+
+	// the other measurement is only reported in verbose output
+	for _, verbose := range []bool{false, true} {
+		config := core.Config{
+			Rules:  core.RulesConfig{FunctionSize: core.FunctionSizeConfig{Enabled: true, MaxLines: 5, Metric: core.MetricStatements}},
+			Output: core.OutputConfig{Verbose: verbose},
+		}
+		results, err := golang.NewAnalyzer(config).Analyze(context.Background(), testFile, config)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		for _, r := range results {
+			if r.RuleID == "large-function" && strings.Contains(r.Suggestion, "(10 lines, 7 statements)") != verbose {
+				t.Errorf("Expected both measurements in the suggestion only when verbose=%v, got %q", verbose, r.Suggestion)
+			}
+		}
+	}
+}
+
+func TestIntegrationFileSizeExemptions(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	commented := "package testpkg\n\n" + strings.Repeat("// explains the next value\n", 12) + "var x = 1\n"
+	generated := "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage testpkg\n\n" + strings.Repeat("var _ = 1\n", 12)
+
+	files := map[string]string{
+		"commented.go":      commented,
+		"generated.pb.go":   generated,
+		"vendor/dep/dep.go": "package dep\n\n" + strings.Repeat("var _ = 1\n", 12),
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+
+	largeFiles := func(countMode core.FileSizeCountMode) []string {
+		config := core.Config{
+			Rules: core.RulesConfig{
+				FileSize: core.FileSizeConfig{Enabled: true, MaxLines: 10, CountMode: countMode},
+			},
+		}
+		analyzer := golang.NewAnalyzer(config)
+
+		var flagged []string
+		for name := range files {
+			results, err := analyzer.Analyze(context.Background(), filepath.Join(tmpDir, name), config)
+			if err != nil {
+				t.Fatalf("Analyze %s failed: %v", name, err)
+			}
+			for _, r := range results {
+				if r.RuleID == "large-file" {
+					flagged = append(flagged, name)
+				}
+			}
+		}
+		return flagged
+	}
+
+	if got := largeFiles(core.CountTotal); fmt.Sprint(got) != "[commented.go]" {
+		t.Errorf("Expected only commented.go flagged when counting total lines, got %v", got)
+	}
+	if got := largeFiles(core.CountCode); len(got) != 0 {
+		t.Errorf("Expected no files flagged when counting code lines, got %v", got)
+	}
+}
+
+func TestIntegrationLanguageOverrides(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	testFile := filepath.Join(tmpDir, "test.go")
+	content := `package testpkg
+
+func small(a int) int {
+	a++
+	a++
+	return a
+}
+`
+	os.WriteFile(testFile, []byte(content), 0644)
+
+	hierarchy := config.NewConfigHierarchy()
+	hierarchy.SetDefaults(config.DefaultConfig())
+	hierarchy.SetProject(core.Config{
+		Language: core.LanguageConfig{
+			Go: core.GoConfig{Rules: core.LanguageRulesConfig{
+				FunctionSize: core.FunctionSizeOverride{MaxLines: 3},
+			}},
+		},
+	})
+	hierarchy.SetCLI(core.Config{
+		Language: core.LanguageConfig{
+			Python: core.PythonConfig{Rules: core.LanguageRulesConfig{
+				FunctionSize: core.FunctionSizeOverride{MaxLines: 100},
+			}},
+		},
+	})
+	merged := hierarchy.Merge()
+
+	if merged.Rules.FunctionSize.MaxLines != 50 {
+		t.Errorf("Expected global function limit to stay 50, got %d", merged.Rules.FunctionSize.MaxLines)
+	}
+	if merged.Language.Python.Rules.FunctionSize.MaxLines != 100 {
+		t.Errorf("Expected Python override of 100, got %d", merged.Language.Python.Rules.FunctionSize.MaxLines)
+	}
+
+	analyzer := golang.NewAnalyzer(merged)
+	results, err := analyzer.Analyze(context.Background(), testFile, merged)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var messages []string
+	for _, r := range results {
+		if r.RuleID == "large-function" {
+			messages = append(messages, r.Message)
+		}
+	}
+
+	expected := "Function 'small' is too large (5 lines, max 3)"
+	if len(messages) != 1 || messages[0] != expected {
+		t.Errorf("Expected %q from the Go override, got %v", expected, messages)
+	}
+}
+
+func TestIntegrationTestFileRelaxation(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	content := `package testpkg
+
+func helper() (a, b int) {
+	a = 1
+	b = 2
+	return
+}
+`
+	for _, name := range []string{"util.go", "util_test.go"} {
+		os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644)
+	}
+
+	config := core.Config{
+		Rules: core.RulesConfig{
+			FunctionSize: core.FunctionSizeConfig{Enabled: true, MaxLines: 3},
+			Returns:      core.ReturnsConfig{Enabled: true, NakedReturnMaxLines: 2},
+		},
+		TestFiles: core.TestFilesConfig{
+			DisabledRules: []string{"naked-return"},
+			Rules: core.LanguageRulesConfig{
+				FunctionSize: core.FunctionSizeOverride{MaxLines: 100},
+			},
+		},
+	}
+	analyzer := golang.NewAnalyzer(config)
+
+	ruleIDs := func(name string) string {
+		results, err := analyzer.Analyze(context.Background(), filepath.Join(tmpDir, name), config)
+		if err != nil {
+			t.Fatalf("Analyze %s failed: %v", name, err)
+		}
+		var ids []string
+		for _, r := range results {
+			ids = append(ids, r.RuleID)
+		}
+		return strings.Join(ids, ",")
+	}
+
+	if got := ruleIDs("util.go"); got != "large-function,naked-return" {
+		t.Errorf("Expected large-function and naked-return in util.go, got %q", got)
+	}
+	if got := ruleIDs("util_test.go"); got != "" {
+		t.Errorf("Expected no findings in util_test.go, got %q", got)
+	}
+}
+
+func TestIntegrationAIFileThresholds(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	body := `package testpkg
+
+func helper() int {
+	a := 1
+	b := 2
+	return a + b
+}
+`
+	files := map[string]string{
+		"human.go":     body,
+		"assistant.go": "// Code written with help from an assistant.\n// Generated by ChatGPT, reviewed by the team.\n" + body,
+		"gen_api.go":   body,
+		"marked.go":    "// Source: internal-bot v2\n" + body,
+	}
+	for name, content := range files {
+		os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644)
+	}
+
+	config := core.Config{
+		Rules: core.RulesConfig{
+			FunctionSize: core.FunctionSizeConfig{Enabled: true, MaxLines: 50},
+		},
+		AIFiles: core.AIFilesConfig{
+			Markers: []string{"Internal-Bot"},
+			Paths:   []string{"gen_*.go"},
+			Rules: core.LanguageRulesConfig{
+				FunctionSize: core.FunctionSizeOverride{MaxLines: 3},
+			},
+		},
+	}
+	analyzer := golang.NewAnalyzer(config)
+
+	for name, wantAI := range map[string]bool{"human.go": false, "assistant.go": true, "gen_api.go": true, "marked.go": true} {
+		path := filepath.Join(tmpDir, name)
+		if got := languages.IsAIGenerated(path, config.AIFiles); got != wantAI {
+			t.Errorf("%s: expected IsAIGenerated %v, got %v", name, wantAI, got)
+		}
+		results, err := analyzer.Analyze(context.Background(), path, config)
+		if err != nil {
+			t.Fatalf("Analyze %s failed: %v", name, err)
+		}
+		large := false
+		for _, r := range results {
+			large = large || r.RuleID == "large-function"
+		}
+		if large != wantAI {
+			t.Errorf("%s: expected large-function only under the AI threshold, got %v", name, results)
+		}
+	}
+}
+
+func TestIntegrationTolerantParsing(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "unfinished.go")
+	os.WriteFile(testFile, []byte(`package main
+
+// Here is the updated code for the handler
+func handle() {
+	x :=
+}
+`), 0644)
+
+	cfg := config.DefaultConfig()
+	analyzer := golang.NewAnalyzer(cfg)
+	if _, err := analyzer.Analyze(context.Background(), testFile, cfg); err == nil {
+		t.Fatal("Expected a parse error without tolerant parsing")
+	}
+
+	cfg.Parsing.Tolerant = true
+	results, err := analyzer.Analyze(context.Background(), testFile, cfg)
+	if err != nil {
+		t.Fatalf("Tolerant analysis failed: %v", err)
+	}
+
+	found := make(map[string]int)
+	for _, result := range results {
+		found[result.RuleID] = result.Line
+	}
+	if line, ok := found["syntax-error"]; !ok || line != 6 {
+		t.Errorf("Expected syntax-error on line 6, got %v", found)
+	}
+	if _, ok := found["ai-comment-fingerprint"]; !ok {
+		t.Errorf("Expected comment rules to run on the partial AST, got %v", found)
+	}
+	if _, ok := found["unused-function"]; ok {
+		t.Error("Did not expect function rules on a file with syntax errors")
+	}
+}
+
+func TestIntegrationRedundantComments(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	testFile := filepath.Join(tmpDir, "test.go")
+	content := `package testpkg
+
+// counter counts calls
+var counter int
+
+// bump increments the counter
+func bump() {
+	// increment counter
+	counter++
+	total := counter * 2 // set total
+	// guard against overflow in callers
+	_ = total
+}
+`
+	os.WriteFile(testFile, []byte(content), 0644)
+
+	config := core.Config{
+		Rules: core.RulesConfig{
+			Overcommenting: core.OvercommentingConfig{
+				Enabled:         true,
+				MaxCommentRatio: 1,
+				CheckRedundant:  true,
+			},
+		},
+	}
+
+	analyzer := golang.NewAnalyzer(config)
+	results, err := analyzer.Analyze(context.Background(), testFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var lines []int
+	for _, r := range results {
+		if r.RuleID == "redundant-comment" {
+			lines = append(lines, r.Line)
+		}
+	}
+
+	expected := []int{8, 10}
+	if fmt.Sprint(lines) != fmt.Sprint(expected) {
+		t.Errorf("Expected redundant-comment findings on lines %v, got %v", expected, lines)
+	}
+}
+
+func TestIntegrationShebangDetection(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"manage":  "#!/usr/bin/env python3\nprint('hello')\n",
+		"deploy":  "#!/usr/bin/env -S node --no-warnings\nconsole.log('hi');\n",
+		"migrate": "#!/usr/bin/python3.11 -u\nimport os\n",
+		"tool":    "# -*- mode: python; coding: utf-8 -*-\nimport sys\n",
+		"build":   "#!/bin/sh\necho build\n",
+		"LICENSE": "MIT License\n",
+	}
+	for name, content := range files {
+		os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0755)
+	}
+
+	config := core.Config{}
+	registry := languages.NewRegistry()
+	registry.Register(golang.NewAnalyzer(config))
+	registry.Register(python.NewAnalyzer(config))
+	registry.Register(reactnative.NewAnalyzer(config))
+
+	found, err := languages.NewMultiScanner(registry).Scan(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	names := make(map[string][]string)
+	for language, paths := range found {
+		for _, path := range paths {
+			names[language] = append(names[language], filepath.Base(path))
+		}
+	}
+	expected := map[string][]string{
+		"python":      {"manage", "migrate", "tool"},
+		"reactnative": {"deploy"},
+	}
+	if fmt.Sprint(names) != fmt.Sprint(expected) {
+		t.Errorf("Expected files grouped as %v, got %v", expected, names)
+	}
+}
+
+func TestIntegrationLanguageOverrideDirectives(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"server.mjs":  "export const port = 8080;\n",
+		"stubs.pyi":   "def load(path: str) -> bytes: ...\n",
+		"legacy.es6":  "// agentlint:language=javascript\nconst a = 1;\n",
+		"helpers.js":  "# agentlint:language=python\nimport os\n",
+		"job":         "#!/bin/sh\n# agentlint:language=python\nimport sys\n",
+		"notes.txt":   "agentlint:language=python is documented here\n",
+		"styles.scss": "body { margin: 0; }\n",
+	}
+	for name, content := range files {
+		os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644)
+	}
+
+	config := core.Config{}
+	registry := languages.NewRegistry()
+	registry.Register(python.NewAnalyzer(config))
+	registry.Register(reactnative.NewAnalyzer(config))
+
+	scanner := languages.NewMultiScanner(registry)
+	if err := scanner.SetExtensionMap(map[string]string{".mjs": "javascript", "pyi": "python", ".es6": "javascript"}); err != nil {
+		t.Fatalf("SetExtensionMap failed: %v", err)
+	}
+	if err := languages.NewMultiScanner(registry).SetExtensionMap(map[string]string{".kt": "kotlin"}); err == nil {
+		t.Error("Expected an error for an unknown language")
+	}
+
+	found, err := scanner.Scan(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	names := make(map[string][]string)
+	for language, paths := range found {
+		for _, path := range paths {
+			names[language] = append(names[language], filepath.Base(path))
+		}
+	}
+	expected := map[string][]string{
+		"python":      {"helpers.js", "job", "stubs.pyi"},
+		"reactnative": {"legacy.es6", "server.mjs"},
+	}
+	if fmt.Sprint(names) != fmt.Sprint(expected) {
+		t.Errorf("Expected files grouped as %v, got %v", expected, names)
+	}
+}
+
+func TestIntegrationPathFilter(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := []string{
+		"src/app.ts",
+		"src/components/Button.tsx",
+		"src/generated/client.ts",
+		"src/api/generated/types.ts",
+		"src/util.js",
+		"scripts/build.ts",
+		"tools/gen.py",
+	}
+	for _, name := range files {
+		path := filepath.Join(tmpDir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte("export const a = 1;\n"), 0644)
+	}
+
+	config := core.Config{}
+	registry := languages.NewRegistry()
+	registry.Register(python.NewAnalyzer(config))
+	registry.Register(reactnative.NewAnalyzer(config))
+
+	scanner := languages.NewMultiScanner(registry)
+	if err := scanner.SetPathFilter([]string{"src/**/*.ts", "src/**/*.tsx", "*.py"}, []string{"**/generated/**"}); err != nil {
+		t.Fatalf("SetPathFilter failed: %v", err)
+	}
+
+	found, err := scanner.Scan(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	var names []string
+	for _, paths := range found {
+		for _, path := range paths {
+			rel, _ := filepath.Rel(tmpDir, path)
+			names = append(names, filepath.ToSlash(rel))
+		}
+	}
+	sort.Strings(names)
+	expected := []string{"src/app.ts", "src/components/Button.tsx", "tools/gen.py"}
+	if fmt.Sprint(names) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+
+	if err := scanner.SetPathFilter(nil, []string{"[z-a].go"}); err == nil {
+		t.Error("Expected an error for an invalid glob")
+	}
+}
+
+func TestIntegrationScannerSkipped(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"main.go":              "package main\n",
+		"_scratch.go":          "package main\n",
+		"main_test.go":         "package main\n",
+		"README.md":            "# readme\n",
+		"bin/tool":             "#!/usr/bin/env python3\nprint(1)\n",
+		"bin/run":              "#!/bin/sh\necho run\n",
+		"gen/client.go":        "package gen\n",
+		"node_modules/a/a.js":  "module.exports = 1;\n",
+		"scripts/deploy.py":    "print(1)\n",
+		"scripts/release.py":   "print(2)\n",
+		"scripts/data/load.py": "print(3)\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+
+	config := core.Config{}
+	config.Language.Go.IgnoreTests = true
+	registry := languages.NewRegistry()
+	registry.Register(golang.NewAnalyzer(config))
+	registry.Register(python.NewAnalyzer(config))
+	registry.Register(reactnative.NewAnalyzer(config))
+
+	scanner := languages.NewMultiScanner(registry)
+	scanner.SetPathFilter(nil, []string{"gen/**", "release.py"})
+	scanner.RecordSkipped(true)
+	if _, err := scanner.Scan(context.Background(), tmpDir); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	skipped := make(map[string]string)
+	for _, path := range scanner.Skipped() {
+		rel, _ := filepath.Rel(tmpDir, path.Path)
+		skipped[filepath.ToSlash(rel)] = path.Reason
+	}
+	expected := map[string]string{
+		"README.md":          "unsupported extension .md",
+		"bin/run":            "no extension, shebang or mode line naming a supported language",
+		"gen":                "directory matches an exclude glob",
+		"node_modules":       "ignored directory",
+		"scripts/release.py": "matches an exclude glob",
+	}
+	if fmt.Sprint(skipped) != fmt.Sprint(expected) {
+		t.Errorf("Expected skipped paths %v, got %v", expected, skipped)
+	}
+	if len(scanner.Skipped()) != 0 {
+		t.Error("Expected Skipped to reset the recorded paths")
+	}
+
+	// the Go analyzer itself skips underscore-prefixed files, and test files with IgnoreTests
+	for name, want := range map[string]bool{"main.go": false, "_scratch.go": true, "main_test.go": true} {
+		if reason := golang.IgnoreReason(filepath.Join(tmpDir, name), config); (reason != "") != want {
+			t.Errorf("IgnoreReason(%s) = %q", name, reason)
+		}
+	}
+}
+
+func TestIntegrationSystemicSmells(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	var py []string
+	for i := 0; i < 4; i++ {
+		py = append(py, fmt.Sprintf("def handler%d(x):", i))
+		body := 2
+		if i < 3 {
+			body = 12
+		}
+		for j := 0; j < body; j++ {
+			py = append(py, fmt.Sprintf("    x = x + %d", j))
+		}
+		py = append(py, "    return x", "")
+	}
+	pyFile := filepath.Join(tmpDir, "handlers.py")
+	os.WriteFile(pyFile, []byte(strings.Join(py, "\n")), 0644)
+
+	js := []string{"function render(items) {"}
+	for i := 0; i < 21; i++ {
+		js = append(js, fmt.Sprintf("  console.log('item', items[%d]);", i))
+	}
+	js = append(js, "  return items;", "}")
+	jsFile := filepath.Join(tmpDir, "Screen.js")
+	os.WriteFile(jsFile, []byte(strings.Join(js, "\n")), 0644)
+
+	config := core.Config{
+		Rules: core.RulesConfig{
+			FunctionSize: core.FunctionSizeConfig{Enabled: true, MaxLines: 60},
+			Systemic:     core.SystemicConfig{Enabled: true, MaxFileFindings: 20, MaxPackageRatio: 0.3},
+		},
+	}
+	pyConfig := config
+	pyConfig.Rules.FunctionSize.MaxLines = 10
+	pyAnalyzer := python.NewAnalyzer(pyConfig)
+	jsAnalyzer := reactnative.NewAnalyzer(config)
+
+	pyResults, err := pyAnalyzer.Analyze(context.Background(), pyFile, pyConfig)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	jsResults, err := jsAnalyzer.Analyze(context.Background(), jsFile, config)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	counters := map[string]core.FunctionCounter{pyFile: pyAnalyzer, jsFile: jsAnalyzer}
+	countFunctions := func(dir string) int {
+		count := 0
+		for file, counter := range counters {
+			if n, err := counter.CountFunctions(context.Background(), file); err == nil && filepath.Dir(file) == dir {
+				count += n
+			}
+		}
+		return count
+	}
+	results := core.Escalate(append(pyResults, jsResults...), config.Rules.Systemic, countFunctions)
+
+	var systemic []string
+	for _, result := range results {
+		if result.RuleID == core.SystemicRuleID {
+			systemic = append(systemic, filepath.Base(result.FilePath)+" "+result.Message)
+		}
+	}
+	if len(systemic) != 2 {
+		t.Fatalf("Expected systemic findings for console-log and large-function, got %v", systemic)
+	}
+	if !strings.HasPrefix(systemic[0], "Screen.js 21 console-log findings") {
+		t.Errorf("Expected the console-log cluster to be escalated, got %q", systemic[0])
+	}
+	if !strings.HasPrefix(systemic[1], "handlers.py 3 of 5 functions") {
+		t.Errorf("Expected 3 of the 5 functions in the directory to be escalated, got %q", systemic[1])
+	}
+}
+
+func TestIntegrationRuleDocumentation(t *testing.T) {
+	config := core.Config{}
+	analyzers := map[string][]core.Rule{
+		"go":          golang.NewAnalyzer(config).Rules(),
+		"python":      python.NewAnalyzer(config).Rules(),
+		"reactnative": reactnative.NewAnalyzer(config).Rules(),
+	}
+
+	for language, rules := range analyzers {
+		if len(rules) == 0 {
+			t.Errorf("%s analyzer has no rules", language)
+		}
+		for _, rule := range rules {
+			if rule.Rationale() == "" {
+				t.Errorf("%s rule %s has no rationale", language, rule.ID())
+			}
+			examples := rule.Examples()
+			if len(examples) == 0 {
+				t.Errorf("%s rule %s has no examples", language, rule.ID())
+			}
+			for _, example := range examples {
+				if example.Bad == "" || example.Good == "" || example.Bad == example.Good {
+					t.Errorf("%s rule %s has an incomplete example: %+v", language, rule.ID(), example)
+				}
+			}
+			for _, option := range rule.Options() {
+				if !strings.HasPrefix(option.Flag, "-") || option.Key == "" || option.Default == "" || option.Description == "" {
+					t.Errorf("%s rule %s has an incomplete option: %+v", language, rule.ID(), option)
+				}
+			}
+		}
+	}
+}
+
 func TestIntegrationJSONOutput(t *testing.T) {
 	results := []core.Result{
 		{
@@ -148,130 +1108,680 @@ func TestIntegrationJSONOutput(t *testing.T) {
 		},
 	}
 
-	formatter := output.NewJSONFormatter(false)
-	err := formatter.Format(results)
-	if err != nil {
-		t.Fatalf("Format failed: %v", err)
+	formatter := output.NewJSONFormatter(false)
+	err := formatter.Format(results)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+}
+
+func TestIntegrationParallelAnalysis(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for i := 0; i < 20; i++ {
+		testFile := filepath.Join(tmpDir, fmt.Sprintf("test%d.go", i))
+		lines := []string{"package main", "", fmt.Sprintf("func func%d() {", i)}
+		for j := 0; j < 60; j++ {
+			lines = append(lines, fmt.Sprintf("	line%d := %d", j, j))
+		}
+		lines = append(lines, "}")
+		content := strings.Join(lines, "\n")
+		os.WriteFile(testFile, []byte(content), 0644)
+	}
+
+	config := core.Config{
+		Rules: core.RulesConfig{
+			FunctionSize: core.FunctionSizeConfig{
+				Enabled:  true,
+				MaxLines: 50,
+			},
+		},
+	}
+
+	parallelAnalyzer := golang.NewParallelAnalyzer(config, 4)
+	scanner := golang.NewFileScanner()
+	files, _ := scanner.Scan(context.Background(), tmpDir)
+
+	start := time.Now()
+	results := parallelAnalyzer.AnalyzeFiles(context.Background(), files, config)
+	elapsed := time.Since(start)
+
+	if len(results) == 0 {
+		t.Error("Expected results from parallel analysis")
+	}
+
+	t.Logf("Parallel analysis of %d files took %v", len(files), elapsed)
+}
+
+func TestIntegrationCrossFileAnalysis(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	mainFile := filepath.Join(tmpDir, "main.go")
+	os.WriteFile(mainFile, []byte(`package main
+
+func main() {
+	foo()
+	bar()
+}
+
+func foo() {
+	_ = 1
+}
+`), 0644)
+
+	utilsFile := filepath.Join(tmpDir, "utils.go")
+	os.WriteFile(utilsFile, []byte(`package main
+
+func bar() {
+	_ = 2
+}
+
+func unused() {
+	_ = 3
+}
+`), 0644)
+
+	crossFileAnalyzer := golang.NewCrossFileAnalyzer()
+	err := crossFileAnalyzer.AnalyzeDirectory(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Cross file analysis failed: %v", err)
+	}
+
+	unusedResults := crossFileAnalyzer.FindUnusedFunctions()
+	if len(unusedResults) == 0 {
+		t.Error("Expected to find unused function 'unused'")
+	}
+}
+
+func TestIntegrationSimilarityDetection(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	file1 := filepath.Join(tmpDir, "file1.go")
+	os.WriteFile(file1, []byte(`package main
+
+func processX() {
+	if a > 0 {
+		for i := 0; i < 10; i++ {
+			if i > 5 {
+				_ = i
+			}
+		}
+	}
+}
+`), 0644)
+
+	file2 := filepath.Join(tmpDir, "file2.go")
+	os.WriteFile(file2, []byte(`package main
+
+func processY() {
+	if b > 0 {
+		for j := 0; j < 10; j++ {
+			if j > 5 {
+				_ = j
+			}
+		}
+	}
+}
+`), 0644)
+
+	similarityAnalyzer := golang.NewSimilarityAnalyzer()
+	results, err := similarityAnalyzer.AnalyzeDirectory(context.Background(), tmpDir, 0.7)
+	if err != nil {
+		t.Fatalf("Similarity analysis failed: %v", err)
+	}
+
+	if len(results) == 0 {
+		t.Error("Expected to find similar code patterns")
+	}
+}
+
+func TestIntegrationDuplicateErrorStrings(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"store/load.go": `package store
+
+import "fmt"
+
+func loadUsers(path string) error { return fmt.Errorf("failed to open database %s: %w", path, nil) }
+func loadOrders(path string) error { return fmt.Errorf("Failed to open database %q: %v", path, nil) }
+`,
+		"store/save.go": `package store
+
+import "fmt"
+
+func saveUsers(path string) error { return fmt.Errorf("failed to open the database %s: %w", path, nil) }
+`,
+		"store/zz_generated.go": `// Code generated by mockgen. DO NOT EDIT.
+
+package store
+
+import "fmt"
+
+func mockLoad(path string) error { return fmt.Errorf("failed to open database %s: %w", path, nil) }
+`,
+		"api/api.go": `package api
+
+import "fmt"
+
+func get(path string) error { return fmt.Errorf("failed to open database %s: %w", path, nil) }
+func put(path string) error { return fmt.Errorf("failed to open database %s: %w", path, nil) }
+`,
+	}
+	var goFiles []string
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+		goFiles = append(goFiles, path)
+	}
+
+	cfg := config.DefaultConfig().Rules.DuplicateErrors
+	analyzer := golang.NewErrorStringAnalyzer(cfg.MinOccurrences, cfg.Threshold)
+	analyzer.SetCache(golang.NewASTCache(time.Minute))
+	results, err := analyzer.AnalyzeFiles(context.Background(), goFiles)
+	if err != nil {
+		t.Fatalf("Duplicate error string analysis failed: %v", err)
+	}
+
+	// the two messages in api stay below the default of three occurrences, and the generated
+	// file does not count towards store's
+	if len(results) != 1 {
+		t.Fatalf("Expected one finding, got %v", results)
+	}
+	if rel, _ := filepath.Rel(tmpDir, results[0].FilePath); filepath.ToSlash(rel) != "store/load.go" || results[0].Line != 5 {
+		t.Errorf("Expected the finding at store/load.go:5, got %s:%d", results[0].FilePath, results[0].Line)
+	}
+	if !strings.Contains(results[0].Message, "3 times in package store") {
+		t.Errorf("Unexpected message: %s", results[0].Message)
+	}
+}
+
+func TestIntegrationPackageLayout(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"go.mod":              "module example.com/go-billing\n",
+		"billing.go":          "package billing\n\nfunc Charge() {}\n",
+		"internal/utils/a.go": "package utils\n\nfunc A() {}\n",
+		"pkg/common/b.go":     "package common\n\nfunc B() {}\n",
+		"invoice/invoice.go":  "package invoice\n\nfunc New() {}\n\nfunc Send() {}\n",
+		"invoice/types.go":    "package invoice\n",
+		"invoice/stuff.go":    "// Code generated by stringer. DO NOT EDIT.\n\npackage invoice\n",
+		"tax/v2/tax.go":       "package tax\n\nfunc Rate() {}\n",
+		"tax/tax.go":          "package taxes\n\nfunc Rate() {}\n",
+	}
+	var goFiles []string
+	analyzed := make(map[string]bool)
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+		if strings.HasSuffix(name, ".go") {
+			goFiles = append(goFiles, path)
+			analyzed[path] = true
+		}
+	}
+
+	modules, err := golang.DiscoverModules(tmpDir)
+	if err != nil {
+		t.Fatalf("Module discovery failed: %v", err)
+	}
+	analyzer := golang.NewLayoutAnalyzer(config.DefaultConfig().Rules.Layout)
+	analyzer.SetCache(golang.NewASTCache(time.Minute))
+	results := analyzer.Analyze(tmpDir, goFiles, modules, analyzed)
+
+	// the generated stuff.go is neither a generic file name nor a stub, the module root is named
+	// after the module path and tax/v2 after its parent directory
+	var found []string
+	for _, result := range results {
+		rel, _ := filepath.Rel(tmpDir, result.FilePath)
+		found = append(found, result.RuleID+" "+filepath.ToSlash(rel))
+	}
+	sort.Strings(found)
+	want := []string{
+		"generic-package-name internal/utils/a.go",
+		"generic-package-name pkg/common/b.go",
+		"package-name-mismatch tax/tax.go",
+		"stub-files invoice/types.go",
+	}
+	if strings.Join(found, ", ") != strings.Join(want, ", ") {
+		t.Errorf("Expected findings %v, got %v", want, found)
+	}
+}
+
+func TestIntegrationHeaderDrift(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	header := "// Copyright 2024 The Billing Authors. All rights reserved.\n// Use of this source code is governed by the Apache 2.0 license.\n\n"
+	files := map[string]string{
+		"invoice.go":   header + "package billing\n",
+		"payment.go":   header + "package billing\n",
+		"refund.go":    header + "package billing\n",
+		"tax.go":       strings.Replace(header, "Billing", "Payments", 1) + "package billing\n",
+		"scripts/x.py": "# Copyright 2024 The Billing Authors. All rights reserved.\n# Use of this source code is governed by the Apache 2.0 license.\n",
+		"scripts/y.py": "# Copyright 2024 The Billing Authors.\n# Licensed under the MIT license.\n",
+	}
+	var paths []string
+	analyzed := make(map[string]bool)
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+		paths = append(paths, path)
+		analyzed[path] = true
+	}
+
+	// the Python header matches the Go ones once comment markers are removed, and y.py carries
+	// a different license rather than a drifted copy
+	results := headers.Analyze(paths, analyzed, config.DefaultConfig().Rules.Headers, nil)
+	if len(results) != 1 {
+		t.Fatalf("Expected one finding, got %v", results)
+	}
+	if filepath.Base(results[0].FilePath) != "tax.go" || results[0].Line != 1 {
+		t.Errorf("Expected the finding at tax.go:1, got %s:%d", results[0].FilePath, results[0].Line)
+	}
+	if !strings.Contains(results[0].Message, `"Payments" instead of "Billing"`) {
+		t.Errorf("Unexpected message: %s", results[0].Message)
+	}
+}
+
+func TestIntegrationHardcodedEndpoints(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	content := `package client
+
+import "net/http"
+
+const schema = "http://www.w3.org/2001/XMLSchema"
+
+type Options struct {
+	Addr string ` + "`json:\"addr\" default:\":8080\"`" + `
+}
+
+func Fetch(base string) {
+	http.Get("http://localhost:8080/api/users")
+	http.Get("https://api.stripe.com/v1/charges")
+	http.Get(base + "/api/users")
+	http.ListenAndServe(":9090", nil)
+	dial("10.0.0.12")
+	version("1.2.3.4.5")
+}
+
+func dial(string)    {}
+func version(string) {}
+`
+	files := map[string]string{
+		"client.go":        content,
+		"client_test.go":   content,
+		"config/config.go": content,
+	}
+	for name, body := range files {
+		path := filepath.Join(tmpDir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(body), 0644)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Rules.Endpoints.Allow = []string{"10.0.0.12"}
+	var found []string
+	for name := range files {
+		results, err := golang.NewAnalyzer(cfg).Analyze(context.Background(), filepath.Join(tmpDir, name), cfg)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		for _, r := range results {
+			if r.RuleID == "hardcoded-endpoint" {
+				found = append(found, fmt.Sprintf("%s:%d: %s", name, r.Line, r.Message))
+			}
+		}
+	}
+
+	// the schema URL, https URL, struct tag and allowed IP are not reported, and neither are
+	// test or configuration files
+	expected := []string{
+		`client.go:12: Hardcoded URL "http://localhost:8080/api/users"`,
+		`client.go:15: Hardcoded address ":9090"`,
+	}
+	if fmt.Sprint(found) != fmt.Sprint(expected) {
+		t.Errorf("Expected hardcoded-endpoint findings %q, got %q", expected, found)
 	}
 }
 
-func TestIntegrationParallelAnalysis(t *testing.T) {
+func TestIntegrationSleepSynchronization(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	for i := 0; i < 20; i++ {
-		testFile := filepath.Join(tmpDir, fmt.Sprintf("test%d.go", i))
-		lines := []string{"package main", "", fmt.Sprintf("func func%d() {", i)}
-		for j := 0; j < 60; j++ {
-			lines = append(lines, fmt.Sprintf("	line%d := %d", j, j))
+	content := `package worker
+
+import (
+	"sync/atomic"
+	clock "time"
+)
+
+func Start(w *Worker) {
+	go w.Run()
+	clock.Sleep(100 * clock.Millisecond)
+}
+
+func WaitReady(ready *atomic.Bool) {
+	for !ready.Load() {
+		clock.Sleep(clock.Millisecond)
+	}
+}
+
+func WaitDone(w *Worker) {
+	for {
+		if w.done {
+			break
 		}
-		lines = append(lines, "}")
-		content := strings.Join(lines, "\n")
-		os.WriteFile(testFile, []byte(content), 0644)
+		clock.Sleep(clock.Millisecond)
 	}
+}
 
-	config := core.Config{
-		Rules: core.RulesConfig{
-			FunctionSize: core.FunctionSizeConfig{
-				Enabled:  true,
-				MaxLines: 50,
-			},
-		},
+func Retry(fn func() error) error {
+	var err error
+	for i := 0; i < 3; i++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		clock.Sleep(clock.Second)
 	}
+	return err
+}
 
-	parallelAnalyzer := golang.NewParallelAnalyzer(config, 4)
-	scanner := golang.NewFileScanner()
-	files, _ := scanner.Scan(context.Background(), tmpDir)
+func Tick(w *Worker) {
+	go func() {
+		for {
+			w.Flush()
+			clock.Sleep(clock.Second)
+		}
+	}()
+}
+`
+	cfg := config.DefaultConfig()
+	var found []string
+	for _, name := range []string{"worker.go", "worker_test.go"} {
+		path := filepath.Join(tmpDir, name)
+		os.WriteFile(path, []byte(content), 0644)
+		results, err := golang.NewAnalyzer(cfg).Analyze(context.Background(), path, cfg)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		for _, r := range results {
+			if r.RuleID == "sleep-synchronization" {
+				found = append(found, fmt.Sprintf("%s:%d: %s", name, r.Line, r.Message))
+			}
+		}
+	}
 
-	start := time.Now()
-	results := parallelAnalyzer.AnalyzeFiles(context.Background(), files, config)
-	elapsed := time.Since(start)
+	// retries and the goroutine's own periodic sleep are not synchronization, and tests are skipped
+	expected := []string{
+		"worker.go:10: Function 'Start' calls time.Sleep after starting a goroutine instead of synchronizing",
+		"worker.go:15: Function 'WaitReady' calls time.Sleep in a polling loop instead of synchronizing",
+		"worker.go:24: Function 'WaitDone' calls time.Sleep in a polling loop instead of synchronizing",
+	}
+	if fmt.Sprint(found) != fmt.Sprint(expected) {
+		t.Errorf("Expected sleep-synchronization findings %q, got %q", expected, found)
+	}
 
-	if len(results) == 0 {
-		t.Error("Expected results from parallel analysis")
+	cfg.Rules.Concurrency.Enabled = false
+	results, err := golang.NewAnalyzer(cfg).Analyze(context.Background(), filepath.Join(tmpDir, "worker.go"), cfg)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
 	}
+	for _, r := range results {
+		if r.RuleID == "sleep-synchronization" {
+			t.Errorf("Expected no findings with the concurrency rules disabled, got %v", r)
+		}
+	}
+}
 
-	t.Logf("Parallel analysis of %d files took %v", len(files), elapsed)
+func TestIntegrationGoroutineLeaks(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	testFile := filepath.Join(tmpDir, "fetch.go")
+	content := `package fetch
+
+import (
+	"sync"
+	"time"
+)
+
+func FetchAll(urls []string) {
+	for _, url := range urls {
+		go get(url)
+	}
 }
 
-func TestIntegrationCrossFileAnalysis(t *testing.T) {
+func FetchWithTimeout(url string) (string, error) {
+	ch := make(chan string)
+	go func() { ch <- get(url) }()
+	select {
+	case body := <-ch:
+		return body, nil
+	case <-time.After(time.Second):
+		return "", errTimeout
+	}
+}
+
+func FetchEach(urls []string) {
+	var wg sync.WaitGroup
+	for _, url := range urls {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			get(url)
+		}(url)
+	}
+	wg.Wait()
+}
+`
+	os.WriteFile(testFile, []byte(content), 0644)
+
+	cfg := config.DefaultConfig()
+	results, err := golang.NewAnalyzer(cfg).Analyze(context.Background(), testFile, cfg)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var found []string
+	for _, r := range results {
+		if r.RuleID == "goroutine-leak" {
+			found = append(found, fmt.Sprintf("%d: %s", r.Line, r.Message))
+		}
+	}
+	expected := []string{
+		"10: Function 'FetchAll' starts goroutines in a loop with no context or done channel to stop them",
+		"16: Function 'FetchWithTimeout' starts a goroutine that blocks forever sending on unbuffered channel 'ch' once the select takes another case",
+	}
+	if fmt.Sprint(found) != fmt.Sprint(expected) {
+		t.Errorf("Expected goroutine-leak findings %q, got %q", expected, found)
+	}
+
+	cfg.Rules.Concurrency.Enabled = false
+	if results, err = golang.NewAnalyzer(cfg).Analyze(context.Background(), testFile, cfg); err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	for _, r := range results {
+		if r.RuleID == "goroutine-leak" {
+			t.Errorf("Expected no findings with the concurrency rules disabled, got %v", r)
+		}
+	}
+}
+
+func TestIntegrationMutexCopyAndDeferInLoop(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	mainFile := filepath.Join(tmpDir, "main.go")
-	os.WriteFile(mainFile, []byte(`package main
+	testFile := filepath.Join(tmpDir, "store.go")
+	content := `package store
 
-func main() {
-	foo()
-	bar()
+import (
+	"os"
+	"sync"
+)
+
+type Counter struct {
+	mu sync.Mutex
+	n  int
 }
 
-func foo() {
-	_ = 1
+type Registry struct {
+	counters Counter
+	shared   *sync.RWMutex
 }
-`), 0644)
 
-	utilsFile := filepath.Join(tmpDir, "utils.go")
-	os.WriteFile(utilsFile, []byte(`package main
+type Plain struct {
+	mu *sync.Mutex
+}
 
-func bar() {
-	_ = 2
+func (c Counter) Value() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
 }
 
-func unused() {
-	_ = 3
+func (c *Counter) Inc() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n++
 }
-`), 0644)
 
-	crossFileAnalyzer := golang.NewCrossFileAnalyzer()
-	err := crossFileAnalyzer.AnalyzeDirectory(context.Background(), tmpDir)
+func Snapshot(r *Registry) Registry {
+	return *r
+}
+
+func Use(p Plain, wg sync.WaitGroup) {}
+
+func ReadAll(paths []string) error {
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+	}
+	for _, path := range paths {
+		func() {
+			f, _ := os.Open(path)
+			defer f.Close()
+		}()
+	}
+	return nil
+}
+`
+	os.WriteFile(testFile, []byte(content), 0644)
+
+	cfg := config.DefaultConfig()
+	results, err := golang.NewAnalyzer(cfg).Analyze(context.Background(), testFile, cfg)
 	if err != nil {
-		t.Fatalf("Cross file analysis failed: %v", err)
+		t.Fatalf("Analyze failed: %v", err)
 	}
 
-	unusedResults := crossFileAnalyzer.FindUnusedFunctions()
-	if len(unusedResults) == 0 {
-		t.Error("Expected to find unused function 'unused'")
+	var found []string
+	for _, r := range results {
+		if r.RuleID == "mutex-copy" || r.RuleID == "defer-in-loop" {
+			found = append(found, fmt.Sprintf("%d: %s", r.Line, r.Message))
+		}
+	}
+	sort.Strings(found)
+	expected := []string{
+		"22: Method 'Value' has a value receiver of type 'Counter', so each call copies its sync.Mutex",
+		"34: Function 'Snapshot' returns 'Registry' by value, copying its sync.Mutex",
+		"38: Function 'Use' takes 'sync.WaitGroup' by value, copying its sync.WaitGroup",
+		"46: Function 'ReadAll' defers inside a loop, so nothing deferred runs until the function returns",
+	}
+	if fmt.Sprint(found) != fmt.Sprint(expected) {
+		t.Errorf("Expected findings %q, got %q", expected, found)
+	}
+
+	cfg.Rules.Concurrency.Enabled = false
+	if results, err = golang.NewAnalyzer(cfg).Analyze(context.Background(), testFile, cfg); err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	for _, r := range results {
+		if r.RuleID == "mutex-copy" || r.RuleID == "defer-in-loop" {
+			t.Errorf("Expected no findings with the concurrency rules disabled, got %v", r)
+		}
 	}
 }
 
-func TestIntegrationSimilarityDetection(t *testing.T) {
+func TestIntegrationTestQuality(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	file1 := filepath.Join(tmpDir, "file1.go")
-	os.WriteFile(file1, []byte(`package main
+	testFile := filepath.Join(tmpDir, "user_test.go")
+	content := `package user
 
-func processX() {
-	if a > 0 {
-		for i := 0; i < 10; i++ {
-			if i > 5 {
-				_ = i
-			}
-		}
+import "testing"
+
+func TestNewUser(t *testing.T) {
+	u := NewUser("ada")
+	t.Log(u)
+}
+
+func TestUserName(t *testing.T) {
+	u := NewUser("ada")
+	if u.GetName() != "ada" {
+		t.Errorf("GetName() = %q", u.GetName())
 	}
 }
-`), 0644)
 
-	file2 := filepath.Join(tmpDir, "file2.go")
-	os.WriteFile(file2, []byte(`package main
+func TestRenameShort(t *testing.T) {
+	u := NewUser("ada")
+	if err := u.Rename("bo"); err != nil {
+		t.Fatalf("Rename(%q) failed: %v", "bo", err)
+	}
+	if got := u.GetName(); got != "bo" {
+		t.Errorf("GetName() = %q, want %q", got, "bo")
+	}
+}
 
-func processY() {
-	if b > 0 {
-		for j := 0; j < 10; j++ {
-			if j > 5 {
-				_ = j
-			}
-		}
+func TestRenameLong(t *testing.T) {
+	u := NewUser("ada")
+	if err := u.Rename("grace"); err != nil {
+		t.Fatalf("Rename(%q) failed: %v", "grace", err)
+	}
+	if got := u.GetName(); got != "grace" {
+		t.Errorf("GetName() = %q, want %q", got, "grace")
 	}
 }
-`), 0644)
 
-	similarityAnalyzer := golang.NewSimilarityAnalyzer()
-	results, err := similarityAnalyzer.AnalyzeDirectory(context.Background(), tmpDir, 0.7)
+func TestRenameEmpty(t *testing.T) {
+	u := NewUser("ada")
+	if err := u.Rename(""); err == nil {
+		t.Error("Rename accepted an empty name")
+	}
+}
+
+func TestSkipped(t *testing.T) {
+	t.Skip("needs a database")
+}
+
+func TestHelper(t *testing.T) {
+	checkUser(t, NewUser("ada"))
+}
+`
+	os.WriteFile(testFile, []byte(content), 0644)
+
+	cfg := config.DefaultConfig()
+	results, err := golang.NewAnalyzer(cfg).Analyze(context.Background(), testFile, cfg)
 	if err != nil {
-		t.Fatalf("Similarity analysis failed: %v", err)
+		t.Fatalf("Analyze failed: %v", err)
 	}
 
-	if len(results) == 0 {
-		t.Error("Expected to find similar code patterns")
+	var found []string
+	for _, r := range results {
+		if r.RuleID == "assertion-free-test" || r.RuleID == "duplicate-test" || r.RuleID == "trivial-getter-test" {
+			found = append(found, fmt.Sprintf("%d: %s", r.Line, r.Message))
+		}
+	}
+	sort.Strings(found)
+	expected := []string{
+		"10: Test 'TestUserName' only exercises the accessors GetName",
+		"27: Test 'TestRenameLong' repeats 'TestRenameShort' (line 17) with 100% of the same code",
+		"5: Test 'TestNewUser' never reports a failure, so it passes whatever the code does",
+	}
+	if fmt.Sprint(found) != fmt.Sprint(expected) {
+		t.Errorf("Expected findings %q, got %q", expected, found)
 	}
 }
 
@@ -285,6 +1795,44 @@ func TestIntegrationProfiling(t *testing.T) {
 	}
 }
 
+func TestIntegrationRuleProfiling(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "main.go")
+	os.WriteFile(testFile, []byte(`package main
+
+func main() {
+	println("hello")
+}
+`), 0644)
+
+	profiling.EnableRuleProfiling()
+	analyzer := golang.NewAnalyzer(config.DefaultConfig())
+	if _, err := analyzer.Analyze(context.Background(), testFile, config.DefaultConfig()); err != nil {
+		t.Fatalf("Analysis failed: %v", err)
+	}
+
+	rules := profiling.SlowestRules()
+	if len(rules) == 0 {
+		t.Fatal("Expected rule timings to be recorded")
+	}
+	for _, timing := range rules {
+		if timing.Calls != 1 {
+			t.Errorf("Expected rule %s to be called once, got %d", timing.Name, timing.Calls)
+		}
+	}
+
+	files := profiling.SlowestFiles()
+	if len(files) != 1 || files[0].Name != testFile {
+		t.Errorf("Expected timing for %s, got %+v", testFile, files)
+	}
+
+	var out strings.Builder
+	profiling.PrintRuleProfile(&out, 0)
+	if !strings.Contains(out.String(), "Slowest Rules") || !strings.Contains(out.String(), "large-function") {
+		t.Errorf("Expected profile table to list rules, got:\n%s", out.String())
+	}
+}
+
 func TestIntegrationConfigHierarchy(t *testing.T) {
 	config := core.Config{
 		Rules: core.RulesConfig{
@@ -301,6 +1849,183 @@ func TestIntegrationConfigHierarchy(t *testing.T) {
 	}
 }
 
+// TestIntegrationEngineSharedPool analyzes a mixed-language tree with each file queued
+// several times on one pool, so every analyzer handles the same files from many workers at
+// once; run it with -race. The findings must match those of a single worker.
+func TestIntegrationEngineSharedPool(t *testing.T) {
+	tmpDir := t.TempDir()
+	sources := map[string]string{
+		"main.go": `package main
+
+// main runs the program.
+func main() {
+	a := 1
+	b := 2
+	c := a + b
+	d := c * 2
+	e := d - 1
+	println(a, b, c, d, e)
+}
+
+func unused() {}
+`,
+		"tool.py": `def process(items):
+    total = 0
+    for item in items:
+        if item > 0:
+            total += item
+        else:
+            total -= item
+    return total
+
+
+def unused_helper():
+    pass
+`,
+		"App.tsx": `import React from 'react';
+
+export default function App() {
+  const a = 1;
+  const b = 2;
+  const c = a + b;
+  return <Text>{c}</Text>;
+}
+`,
+	}
+	for name, content := range sources {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Rules.FunctionSize.MaxLines = 3
+	registry := languages.NewRegistry()
+	registry.Register(golang.NewAnalyzer(cfg))
+	registry.Register(python.NewAnalyzer(cfg))
+	registry.Register(reactnative.NewAnalyzer(cfg))
+	files, err := languages.NewMultiScanner(registry).Scan(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Scanning the tree failed: %v", err)
+	}
+	var jobs []core.AnalysisJob
+	for _, language := range []string{"go", "python", "reactnative"} {
+		analyzer, _ := registry.GetAnalyzer(language)
+		if len(files[language]) == 0 {
+			t.Fatalf("Expected %s files in the tree", language)
+		}
+		for _, path := range files[language] {
+			jobs = append(jobs, core.AnalysisJob{Analyzer: analyzer, FilePath: path})
+		}
+	}
+	repeated := make([]core.AnalysisJob, 0, 4*len(jobs))
+	for i := 0; i < 4; i++ {
+		repeated = append(repeated, jobs...)
+	}
+
+	want, wantErrors := core.NewEngine(1).Run(context.Background(), repeated, cfg)
+	got, gotErrors := core.NewEngine(8).Run(context.Background(), repeated, cfg)
+	if len(want) == 0 {
+		t.Fatal("Expected findings in the mixed-language tree")
+	}
+	if len(gotErrors) != len(wantErrors) {
+		t.Fatalf("Expected %d file errors, got %v", len(wantErrors), gotErrors)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the shared pool to find the same %d results as one worker, got %d", len(want), len(got))
+	}
+}
+
+func TestIntegrationConfigFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("AGENTLINT_CONFIG", "")
+
+	testFile := filepath.Join(tmpDir, "main.go")
+	os.WriteFile(testFile, []byte("package main\n\nfunc main() {\n\tprintln(1)\n\tprintln(2)\n}\n"), 0644)
+	configFile := filepath.Join(tmpDir, "agentlint.yaml")
+	os.WriteFile(configFile, []byte(`rules:
+  functionSize:
+    maxLines: 3
+    metric: statements
+  fileSize:
+    maxLine: 2
+`), 0644)
+
+	loaded, err := config.NewConfigLoader().LoadHierarchy(configFile, nil)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if len(loaded.Diagnostics) != 1 || loaded.Diagnostics[0].Line != 6 || !strings.Contains(loaded.Diagnostics[0].Message, "did you mean rules.fileSize.maxLines?") {
+		t.Errorf("Expected the misspelled key to be reported on line 6, got %v", loaded.Diagnostics)
+	}
+
+	// statements are counted, so the two calls fit within the limit that lines would exceed
+	analyzer := golang.NewAnalyzer(loaded.Config)
+	results, err := analyzer.Analyze(context.Background(), testFile, loaded.Config)
+	if err != nil {
+		t.Fatalf("Analysis failed: %v", err)
+	}
+	for _, result := range results {
+		if result.RuleID == "large-function" || result.RuleID == "large-file" {
+			t.Errorf("Expected no size findings with the configured metric, got %+v", result)
+		}
+	}
+
+	loaded.Config.Rules.FunctionSize.Metric = core.MetricLines
+	results, _ = analyzer.Analyze(context.Background(), testFile, loaded.Config)
+	found := false
+	for _, result := range results {
+		found = found || result.RuleID == "large-function"
+	}
+	if !found {
+		t.Error("Expected large-function with the configured limit measured in lines")
+	}
+}
+
+func TestIntegrationBenchmarkCorpus(t *testing.T) {
+	tmpDir := t.TempDir()
+	corpus := benchmark.Bundled()
+	if err := benchmark.Extract(corpus, tmpDir); err != nil {
+		t.Fatalf("Extracting the corpus failed: %v", err)
+	}
+	expected, err := benchmark.ReadExpectations(corpus)
+	if err != nil {
+		t.Fatalf("Reading the expectations failed: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	registry := languages.NewRegistry()
+	registry.Register(golang.NewAnalyzer(cfg))
+	registry.Register(python.NewAnalyzer(cfg))
+	registry.Register(reactnative.NewAnalyzer(cfg))
+	files, err := languages.NewMultiScanner(registry).Scan(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Scanning the corpus failed: %v", err)
+	}
+	var jobs []core.AnalysisJob
+	for language, paths := range files {
+		analyzer, _ := registry.GetAnalyzer(language)
+		for _, path := range paths {
+			jobs = append(jobs, core.AnalysisJob{Analyzer: analyzer, FilePath: path})
+		}
+	}
+	results, fileErrors := core.NewEngine(1).Run(context.Background(), jobs, cfg)
+	if len(fileErrors) > 0 {
+		t.Fatalf("Analyzing the corpus failed: %v", fileErrors)
+	}
+
+	// the bundled corpus is the regression baseline: every rule must score perfectly on it
+	for _, score := range benchmark.Score(expected, results, tmpDir) {
+		for _, missed := range score.Missed {
+			t.Errorf("Missed %s", missed)
+		}
+		for _, result := range score.Unexpected {
+			t.Errorf("Unexpected %s:%d %s: %s", result.FilePath, result.Line, result.RuleID, result.Message)
+		}
+	}
+}
+
 func TestIntegrationLargeScale(t *testing.T) {
 	tmpDir := t.TempDir()
 